@@ -14,10 +14,12 @@ var (
 	flagContinueOnError bool
 
 	// Alloy configuration flags
-	flagProfile            string
-	flagMonitorBlockNode   bool
-	flagClusterName        string
-	flagClusterSecretStore string
+	flagProfile              string
+	flagMonitorBlockNode     bool
+	flagMonitorShaper        bool
+	flagClusterName          string
+	flagClusterSecretStore   string
+	flagSkipValuesValidation bool
 
 	// Legacy single-remote flags (deprecated, use --add-prometheus-remote and --add-loki-remote instead)
 	flagPrometheusURL      string
@@ -53,6 +55,7 @@ func init() {
 	common.FlagProfile().SetVarP(clusterCmd, &flagProfile, false)
 	common.FlagClusterName().SetVarP(clusterCmd, &flagClusterName, false)
 	common.FlagMonitorBlockNode().SetVarP(clusterCmd, &flagMonitorBlockNode, false)
+	common.FlagMonitorTrafficShaper().SetVarP(clusterCmd, &flagMonitorShaper, false)
 
 	// Deprecated: kept for backward compatibility but hidden
 	common.FlagAlloyClusterSecretStore().SetVarPHidden(clusterCmd, &flagClusterSecretStore, false)
@@ -67,6 +70,9 @@ func init() {
 	common.FlagLokiURL().SetVarP(clusterCmd, &flagLokiURL, false)
 	common.FlagLokiUsername().SetVarP(clusterCmd, &flagLokiUsername, false)
 
+	// Install-only: values validation happens when the charts are installed
+	common.FlagSkipValuesValidation().SetVarP(installCmd, &flagSkipValuesValidation, false)
+
 	clusterCmd.AddCommand(installCmd)
 	clusterCmd.AddCommand(uninstallCmd)
 }
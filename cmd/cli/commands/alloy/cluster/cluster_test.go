@@ -30,6 +30,14 @@ func TestProfileFlagRegistered(t *testing.T) {
 		"install must inherit --profile from the cluster command")
 }
 
+// TestSkipValuesValidationFlagRegistered pins --skip-values-validation on
+// `alloy cluster install`, as on the block node and teleport installs.
+func TestSkipValuesValidationFlagRegistered(t *testing.T) {
+	install := findSubcommand(t, GetCmd(), "install")
+	require.NotNil(t, install.Flags().Lookup(common.FlagSkipValuesValidation().Name),
+		"--skip-values-validation must be registered on alloy cluster install")
+}
+
 func findSubcommand(t *testing.T, parent *cobra.Command, name string) *cobra.Command {
 	t.Helper()
 	for _, c := range parent.Commands() {
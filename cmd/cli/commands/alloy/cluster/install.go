@@ -86,6 +86,7 @@ Examples:
 		// Apply Alloy configuration overrides
 		alloyOverrides := models.AlloyConfig{
 			MonitorBlockNode:       flagMonitorBlockNode,
+			MonitorTrafficShaper:   flagMonitorShaper,
 			ClusterName:            flagClusterName,
 			ClusterSecretStoreName: flagClusterSecretStore,
 			PrometheusRemotes:      prometheusRemotes,
@@ -108,6 +109,7 @@ Examples:
 		l.Debug().
 			Strs("args", args).
 			Bool("monitorBlockNode", flagMonitorBlockNode).
+			Bool("monitorTrafficShaper", flagMonitorShaper).
 			Str("clusterName", flagClusterName).
 			Int("prometheusRemotes", len(prometheusRemotes)).
 			Int("lokiRemotes", len(lokiRemotes)).
@@ -125,7 +127,7 @@ Examples:
 		opts := workflows.DefaultWorkflowExecutionOptions()
 		opts.ExecutionMode = execMode
 
-		wb := workflows.WithWorkflowExecutionMode(workflows.NewAlloyInstallWorkflow(), opts)
+		wb := workflows.WithWorkflowExecutionMode(workflows.NewAlloyInstallWorkflow(flagSkipValuesValidation), opts)
 
 		if err := common.RunWorkflowBuilder(cmd.Context(), wb); err != nil {
 			return err
@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/bll/blocknode"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/daemon"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows"
+	"github.com/hashgraph/solo-weaver/pkg/config"
+	"github.com/hashgraph/solo-weaver/pkg/deps"
+	"github.com/hashgraph/solo-weaver/pkg/helm"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDeploymentFile string
+
+	applyCmd = &cobra.Command{
+		Use:   "apply",
+		Short: "Converge a Hedera Block Node to a declarative deployment file",
+		Long: `Read a BlockNodeDeployment file and converge the block node it names to it.
+
+The file carries what the install, upgrade and reconfigure flags carry: profile,
+chart, storage, retention, plugins, load balancer, traffic shaping, host firewall
+and the traffic-shaper daemon. apply chooses the verb itself:
+  install      the release is not deployed
+  upgrade      spec.chart.version is newer than the deployed chart
+  reconfigure  the Helm values, storage migrations, service endpoint, traffic
+               shaping or host firewall differ from the deployed block node
+and does nothing when the block node already matches the file, so it can run on
+every commit of a GitOps pipeline. An upgrade is followed by a reconfigure when
+the file also changes what an upgrade only re-asserts (traffic shaping, the host
+firewall).
+
+A section left out of the file keeps what is deployed; on install it takes the
+flag's default. apply never prompts. A storage path change or an older chart
+version is refused, as reconfigure and upgrade refuse them: run
+'block node reconfigure --purge-storage' or 'block node rollback' for those.`,
+		Example: `  # Show what applying the file would do, without changing anything
+  sudo solo-provisioner block node apply -f block-node.yaml --plan
+
+  # Converge the host to the file
+  sudo solo-provisioner block node apply -f block-node.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, err := bnpkg.LoadDeployment(flagDeploymentFile)
+			if err != nil {
+				return err
+			}
+			if err := selectDeploymentRelease(d); err != nil {
+				return err
+			}
+			if _, err := bnpkg.LoadPluginPresets(); err != nil {
+				return err
+			}
+
+			inputs, err := deploymentInputs(cmd, args, d)
+			if err != nil {
+				return err
+			}
+
+			if err := initializeDependencies(); err != nil {
+				return err
+			}
+
+			plan, err := planDeployment(cmd, d, inputs)
+			if err != nil || plan.UpToDate() || flagPlan {
+				return err
+			}
+			if err := applyDeployment(cmd, d, plan.Action, inputs); err != nil {
+				return err
+			}
+			if plan.Action != models.ActionUpgrade {
+				return nil
+			}
+
+			// An upgrade re-asserts the recorded traffic shaping and host firewall
+			// rather than applying new ones; plan again for what it left behind.
+			plan, err = planDeployment(cmd, d, inputs)
+			if err != nil || plan.UpToDate() {
+				return err
+			}
+			return applyDeployment(cmd, d, plan.Action, inputs)
+		},
+	}
+)
+
+// selectDeploymentRelease makes metadata.name the selected block node
+// instance, so initializeDependencies and the state reads pick it. --release
+// may only repeat it; an empty name takes --release.
+func selectDeploymentRelease(d *bnpkg.Deployment) error {
+	selected := selectedRelease()
+	switch {
+	case d.Metadata.Name == "":
+		d.Metadata.Name = selected
+	case selected != "" && selected != d.Metadata.Name:
+		return errorx.IllegalArgument.New(
+			"--release %q and metadata.name %q of %s name different block nodes", selected, d.Metadata.Name, d.Path).
+			WithProperty(models.ErrPropertyResolution, "drop --release: the deployment file names the block node")
+	}
+	flagRelease = d.Metadata.Name
+	if d.Metadata.Namespace == "" {
+		d.Metadata.Namespace = flagNamespace
+	}
+	return nil
+}
+
+// deploymentInputs maps the deployment onto the inputs prepareBlocknodeInputs
+// builds from flags. Traffic shaping left out of the file keeps the recorded
+// decision, as a reconfigure without --traffic-shaping-enabled does.
+func deploymentInputs(cmd *cobra.Command, args []string, d *bnpkg.Deployment) (*models.UserInputs[models.BlockNodeInputs], error) {
+	var rootFlags common.RootFlags
+	if err := common.ExtractRootFlags(cmd, args, &rootFlags); err != nil {
+		return nil, err
+	}
+	execMode, err := common.GetExecutionMode(flagContinueOnError, flagStopOnError, flagRollbackOnError)
+	if err != nil {
+		return nil, errorx.Decorate(err, "failed to determine execution mode")
+	}
+	execOpts := workflows.DefaultWorkflowExecutionOptions()
+	execOpts.ExecutionMode = execMode
+
+	spec := d.Spec
+	pluginPreset, pluginList := spec.Plugins.Preset, d.PluginList()
+	if pluginList == "" && bnpkg.IsKnownPreset(pluginPreset) {
+		pluginList = bnpkg.PluginListForPreset(pluginPreset, spec.Chart.Version)
+	}
+	if pluginList != "" && pluginPreset == "" {
+		pluginPreset = bnpkg.PresetCustom
+	}
+
+	timeout := spec.Chart.Timeout
+	if timeout == 0 {
+		timeout = helm.DefaultTimeout
+	}
+
+	trafficShaping := true
+	if recorded, err := state.ReadBlockNodePromptDefaultsFromDisk(d.Metadata.Name); err != nil {
+		logx.As().Debug().Err(err).Msg("could not read state file for the recorded traffic-shaping decision; keeping it enabled")
+	} else {
+		trafficShaping = !recorded.BlockNode.TrafficShapingDisabled
+	}
+	var egressInterface, linkRate string
+	var shapeOverrides map[string]models.ShapeOverride
+	if s := spec.Shaping; s != nil {
+		trafficShaping = s.Enabled
+		egressInterface, linkRate, shapeOverrides = s.EgressInterface, s.LinkRate, s.Overrides
+	}
+
+	if spec.Daemon.StatuszBaseURL != "" || spec.Daemon.StatuszPollInterval != "" {
+		sc := daemon.StatuszConfig{BaseURL: spec.Daemon.StatuszBaseURL, PollInterval: spec.Daemon.StatuszPollInterval}
+		if err := sc.Validate(); err != nil {
+			return nil, errorx.IllegalArgument.Wrap(err, "invalid spec.daemon.statuszBaseURL / spec.daemon.statuszPollInterval in %s", d.Path)
+		}
+	}
+
+	inputs := &models.UserInputs[models.BlockNodeInputs]{
+		Common: models.CommonInputs{
+			Force:            rootFlags.Force,
+			NodeType:         models.NodeTypeBlock,
+			ExecutionOptions: *execOpts,
+		},
+		Custom: models.BlockNodeInputs{
+			Profile:               spec.Profile,
+			Namespace:             d.Metadata.Namespace,
+			Release:               d.Metadata.Name,
+			Chart:                 spec.Chart.Repo,
+			ChartVersion:          spec.Chart.Version,
+			Storage:               spec.Storage,
+			ValuesFile:            spec.Chart.ValuesFile,
+			ReuseValues:           true,
+			SkipHardwareChecks:    rootFlags.SkipHardwareChecks,
+			LoadBalancerEnabled:   d.LoadBalancerEnabled(),
+			LoadBalancerPool:      spec.LoadBalancer.Pool,
+			ServicePort:           spec.LoadBalancer.ServicePort,
+			HistoricRetention:     spec.Retention.Historic,
+			RecentRetention:       spec.Retention.Recent,
+			PluginPreset:          pluginPreset,
+			PluginList:            pluginList,
+			TrafficShapingEnabled: trafficShaping,
+			EgressInterface:       egressInterface,
+			LinkRate:              linkRate,
+			ShapeOverrides:        shapeOverrides,
+			Timeout:               timeout,
+			StatuszBaseURL:        spec.Daemon.StatuszBaseURL,
+			StatuszPollInterval:   spec.Daemon.StatuszPollInterval,
+		},
+	}
+	if err := inputs.Validate(); err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "invalid block node deployment %s", d.Path)
+	}
+	return inputs, nil
+}
+
+// planDeployment works out the action that converges the block node to d and
+// prints it. A fresh install takes traffic shaping left out of the file as
+// off, the opt-in default of --traffic-shaping-enabled.
+func planDeployment(cmd *cobra.Command, d *bnpkg.Deployment, inputs *models.UserInputs[models.BlockNodeInputs]) (*blocknode.ApplyPlan, error) {
+	fw, err := deploymentHostConfig(d)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := blockNodeHandler.PlanApply(cmd.Context(), *inputs, fw)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Action == models.ActionInstall && d.Spec.Shaping == nil {
+		inputs.Custom.TrafficShapingEnabled = false
+	}
+	writeApplyPlan(cmd.OutOrStdout(), d, plan)
+	return plan, nil
+}
+
+// writeApplyPlan prints the chosen action and the changes behind it.
+func writeApplyPlan(w io.Writer, d *bnpkg.Deployment, plan *blocknode.ApplyPlan) {
+	name := d.Metadata.Name
+	if name == "" {
+		name = deps.BLOCK_NODE_RELEASE
+	}
+	if plan.UpToDate() {
+		_, _ = fmt.Fprintf(w, "Block node %s is up to date with %s\n", name, d.Path)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Block node %s: %s\n", name, plan.Action)
+	for _, c := range plan.Changes {
+		_, _ = fmt.Fprintf(w, "  - %s\n", c)
+	}
+}
+
+// applyDeployment runs action through its handler, as the install, upgrade
+// and reconfigure commands do, and then activates the traffic-shaper daemon
+// when traffic shaping is on.
+func applyDeployment(cmd *cobra.Command, d *bnpkg.Deployment, action models.ActionType, inputs *models.UserInputs[models.BlockNodeInputs]) error {
+	if err := applyDeploymentFirewall(d, action); err != nil {
+		return err
+	}
+
+	intent := models.Intent{Action: action, Target: models.TargetBlockNode}
+	logx.As().Info().
+		Any("intent", intent).
+		Str("file", d.Path).
+		Msg("Applying Hedera Block Node deployment")
+
+	handler, err := blockNodeHandler.ForAction(intent.Action)
+	if err != nil {
+		return err
+	}
+	if err := common.RunWorkflow(cmd.Context(), func() (*automa.Report, error) {
+		return handler.HandleIntent(cmd.Context(), intent, *inputs)
+	}); err != nil {
+		return err
+	}
+	logx.As().Info().Msgf("Successfully applied %s to Hedera Block Node (%s)", d.Path, action)
+
+	if !inputs.Custom.TrafficShapingEnabled {
+		return nil
+	}
+	// Resolved after the workflow, as reconfigure does, so a missing daemon
+	// binary cannot mask the workflow's own precondition errors.
+	if d.Spec.Daemon.Bin != "" {
+		flagDaemonBin = d.Spec.Daemon.Bin
+	}
+	if d.Spec.Daemon.Version != "" {
+		flagDaemonVersion = d.Spec.Daemon.Version
+	}
+	daemonSource, err := resolveDaemonBinarySource(cmd)
+	if err != nil {
+		return err
+	}
+	namespace := inputs.Custom.Namespace
+	if namespace == "" {
+		if recorded, err := state.ReadBlockNodePromptDefaultsFromDisk(d.Metadata.Name); err == nil {
+			namespace = recorded.BlockNode.Namespace
+		}
+	}
+	return ensureBlockNodeDaemon(cmd, namespace, daemonSource)
+}
+
+// deploymentHostConfig returns the deployment's host firewall with its pod
+// CIDR defaulted to, and checked against, the cluster pod subnet.
+func deploymentHostConfig(d *bnpkg.Deployment) (*models.HostConfig, error) {
+	fw := d.HostConfig()
+	if err := common.ResolveHostPodCIDR(fw); err != nil {
+		return nil, errorx.Decorate(err, "invalid spec.firewall in %s", d.Path)
+	}
+	return fw, nil
+}
+
+// applyDeploymentFirewall sets the host firewall the workflow renders: the
+// file's when it has one, else off for an install (the opt-in default of
+// --firewall-enabled) and the recorded one otherwise.
+func applyDeploymentFirewall(d *bnpkg.Deployment, action models.ActionType) error {
+	fw, err := deploymentHostConfig(d)
+	if err != nil {
+		return err
+	}
+	switch {
+	case fw != nil && !fw.Disabled:
+		config.OverrideHostConfig(*fw)
+	case fw != nil || action == models.ActionInstall:
+		hostCfg := config.Get().Host
+		hostCfg.Disabled = true
+		config.OverrideHostConfig(hostCfg)
+	default:
+		return common.SeedHostFirewallFromState()
+	}
+	return nil
+}
+
+func init() {
+	common.FlagDeploymentFile().SetVarP(applyCmd, &flagDeploymentFile, true)
+	applyCmd.Flags().BoolVar(&flagPlan, "plan", false,
+		"Show the action apply would run and the changes behind it, then exit without changing anything")
+}
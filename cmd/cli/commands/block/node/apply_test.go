@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package node
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/bll/blocknode"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectDeploymentRelease(t *testing.T) {
+	t.Cleanup(func() { flagRelease, flagReleaseName, flagNamespace = "", "", "" })
+
+	flagRelease, flagNamespace = "bn-2", "bn-ns"
+	d := &bnpkg.Deployment{}
+	require.NoError(t, selectDeploymentRelease(d))
+	assert.Equal(t, "bn-2", d.Metadata.Name, "an unnamed deployment takes --release")
+	assert.Equal(t, "bn-ns", d.Metadata.Namespace)
+
+	flagRelease = ""
+	d = &bnpkg.Deployment{Metadata: bnpkg.DeploymentMetadata{Name: "bn-3", Namespace: "bn-3"}}
+	require.NoError(t, selectDeploymentRelease(d))
+	assert.Equal(t, "bn-3", flagRelease, "metadata.name selects the instance")
+	assert.Equal(t, "bn-3", d.Metadata.Namespace)
+
+	flagRelease = "bn-2"
+	require.ErrorContains(t, selectDeploymentRelease(d), "name different block nodes")
+}
+
+func TestWriteApplyPlan(t *testing.T) {
+	d := &bnpkg.Deployment{Path: "block-node.yaml"}
+
+	var out bytes.Buffer
+	writeApplyPlan(&out, d, &blocknode.ApplyPlan{Action: models.ActionReconfigure})
+	assert.Equal(t, "Block node block-node is up to date with block-node.yaml\n", out.String())
+
+	out.Reset()
+	d.Metadata.Name = "bn-2"
+	writeApplyPlan(&out, d, &blocknode.ApplyPlan{
+		Action:  models.ActionUpgrade,
+		Changes: []string{"chart version 0.39.1 -> 0.40.0"},
+	})
+	assert.Equal(t, "Block node bn-2: upgrade\n  - chart version 0.39.1 -> 0.40.0\n", out.String())
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBackupTarget      string
+	flagBackupIncremental bool
+
+	backupCmd = &cobra.Command{
+		Use:   "backup",
+		Short: "Back up a Hedera Block Node's storage",
+		Long: `Back up every storage volume of a Hedera Block Node (archive, live, logs and the
+optional volumes of the deployed chart version) to a directory.
+
+This command will:
+1. Scale down the block node StatefulSet to stop the pod
+2. Wait for the block node pod to terminate
+3. Write a manifest and one checksummed tar/zstd archive per volume to
+   <target>/<release>-<timestamp>
+4. Scale the StatefulSet back up and wait for the block node to become ready
+
+With --incremental, the archive tier stores only the files added since the newest
+backup under --target; restore replays the chain. If the backup fails, the block
+node is scaled back up; its data is never modified.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputs, _, err := prepareBlocknodeInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = initializeDependencies()
+			if err != nil {
+				return err
+			}
+
+			intent := models.Intent{
+				Action: models.ActionBackup,
+				Target: models.TargetBlockNode,
+			}
+
+			logx.As().Debug().
+				Any("intent", intent).
+				Any("inputs", inputs).
+				Msg("Backing up Hedera Block Node storage")
+
+			handler, err := blockNodeHandler.ForAction(intent.Action)
+			if err != nil {
+				return err
+			}
+
+			if err := common.RunWorkflow(cmd.Context(), func() (*automa.Report, error) {
+				return handler.HandleIntent(cmd.Context(), intent, *inputs)
+			}); err != nil {
+				return err
+			}
+
+			logx.As().Info().Str("target", inputs.Custom.BackupTarget).Msg("Successfully backed up Hedera Block Node storage")
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagBackupTarget().SetVarP(backupCmd, &flagBackupTarget, true)
+	common.FlagBackupIncremental().SetVarP(backupCmd, &flagBackupIncremental, false)
+}
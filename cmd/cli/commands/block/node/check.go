@@ -40,6 +40,11 @@ var checkCmd = &cobra.Command{
 		// Set the profile in the global config so other components can access it
 		config.SetProfile(flagProfile)
 
+		// Operator preset files carry hardware floors of their own.
+		if _, err := blocknode.LoadPluginPresets(); err != nil {
+			return err
+		}
+
 		// Resolve plugin options for hardware sizing.
 		// --plugins overrides --plugin-preset when both are set (mirrors init.go precedence).
 		opts := map[string]any{}
@@ -180,8 +180,8 @@ var zeroVersion = func() semver.Semver {
 // provisionBlockNodeDaemon runs the daemon install + provisioning workflow for
 // the block-node component. daemon.yaml has already been written by the install
 // workflow's Traffic-shaper Monitor phase, so it is loaded here as the source of
-// truth; the block-node component's orbit is defaulted to this install's
-// namespace if not already set (never re-prompted).
+// truth; the instance selected by --release is enabled and its orbit is
+// defaulted to this install's namespace if not already set (never re-prompted).
 func provisionBlockNodeDaemon(cmd *cobra.Command, namespace string, source workflowsteps.DaemonBinarySource) error {
 	paths := models.Paths()
 
@@ -204,14 +204,10 @@ func provisionBlockNodeDaemon(cmd *cobra.Command, namespace string, source workf
 	if cfg.Components.BlockNode == nil {
 		cfg.Components.BlockNode = &daemon.BlockNodeComponentConfig{}
 	}
-	cfg.Components.BlockNode.Enabled = true
-	cfg.Components.BlockNode.Monitors.TrafficShaper = true
 	if cfg.Components.BlockNode.Kubeconfig == "" {
 		cfg.Components.BlockNode.Kubeconfig = paths.DaemonBNKubeconfigPath
 	}
-	if cfg.Components.BlockNode.Orbit == "" {
-		cfg.Components.BlockNode.Orbit = namespace
-	}
+	enableBlockNodeInstance(cfg.Components.BlockNode, models.BlockNodeInstance(selectedRelease()), namespace)
 
 	wf, err := workflows.NewDaemonServiceInstallWorkflow(cfg, source)
 	if err != nil {
@@ -223,3 +219,34 @@ func provisionBlockNodeDaemon(cmd *cobra.Command, namespace string, source workf
 	logx.As().Info().Msg("solo-provisioner-daemon service installed, enabled, and started")
 	return nil
 }
+
+// enableBlockNodeInstance turns on the block node instance being provisioned
+// (the default one when instance is empty) and its traffic-shaper monitor in
+// bn, defaulting its orbit to namespace. Other instances are left as they are.
+func enableBlockNodeInstance(bn *daemon.BlockNodeComponentConfig, instance, namespace string) {
+	if instance == "" {
+		bn.Enabled = true
+		bn.Monitors.TrafficShaper = true
+		if bn.Orbit == "" {
+			bn.Orbit = namespace
+		}
+		return
+	}
+	for i := range bn.Instances {
+		if bn.Instances[i].Name != instance {
+			continue
+		}
+		bn.Instances[i].Enabled = true
+		bn.Instances[i].Monitors.TrafficShaper = true
+		if bn.Instances[i].Orbit == "" {
+			bn.Instances[i].Orbit = namespace
+		}
+		return
+	}
+	bn.Instances = append(bn.Instances, daemon.BlockNodeInstanceConfig{
+		Name:     instance,
+		Enabled:  true,
+		Orbit:    namespace,
+		Monitors: daemon.BlockNodeMonitors{TrafficShaper: true},
+	})
+}
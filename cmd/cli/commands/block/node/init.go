@@ -24,6 +24,10 @@ import (
 var blockNodeHandler *blocknode.Handlers
 
 func initializeDependencies() error {
+	// Point the configuration at the selected block node instance before the
+	// runtime and reality checkers are built from it.
+	config.SelectBlockNodeInstance(selectedRelease())
+
 	sr, err := common.Setup()
 	if err != nil {
 		return err
@@ -32,9 +36,9 @@ func initializeDependencies() error {
 	// Set values from other sources (other than config and state) as required
 	// The order of initialization doesn't make any difference since each value can have its own value resolver to
 	// set the correct precedence (e.g. env vars can override defaults, but not user inputs).
-	defaults := config.DefaultsConfig()
+	defaults := config.InstanceDefaultsConfig(selectedRelease())
 	envVals := config.EnvConfig()
-	logx.As().Debug().Any("defaults_config", config.DefaultsConfig()).Msg("Setting defaults")
+	logx.As().Debug().Any("defaults_config", defaults).Msg("Setting defaults")
 	sr.Runtime.BlockNodeRuntime.WithDefaults(defaults)
 	sr.Runtime.MachineRuntime.WithDefaults(defaults)
 	logx.As().Debug().Any("env_config", config.EnvConfig()).Msg("Setting env config")
@@ -79,16 +83,26 @@ func extractBlockNodeParentFlagsWithOpts(cmd *cobra.Command, args []string, flag
 	return nil
 }
 
+// infersFromState reports whether cmd operates on an existing deployment and
+// takes every value (profile included) from the current state/config.
+func infersFromState(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case "uninstall", "backup", "restore", "gc", "forecast", "rollback":
+		return true
+	}
+	return false
+}
+
 // promptForMissingFlags presents interactive prompts for any block node flags
 // not supplied on the command line. Returns the ChosenValues collector so
 // callers can fold in additional prompt sections (e.g. host firewall) before
 // printing the unified summary. Returns nil when the session is
-// non-interactive or for commands (uninstall) that infer all values from
-// existing state.
+// non-interactive or for commands (uninstall, backup, restore, storage gc/forecast, rollback) that infer all
+// values from existing state.
 func promptForMissingFlags(cmd *cobra.Command, args []string) (*prompt.ChosenValues, error) {
-	// Destructive commands that operate on an existing deployment should not
-	// prompt — they infer everything from the current state/config.
-	if cmd.Name() == "uninstall" {
+	// Commands that operate on an existing deployment should not prompt —
+	// they infer everything from the current state/config.
+	if infersFromState(cmd) {
 		return nil, nil
 	}
 
@@ -102,7 +116,7 @@ func promptForMissingFlags(cmd *cobra.Command, args []string) (*prompt.ChosenVal
 	cv := prompt.NewChosenValues()
 
 	// Read all prompt-relevant fields from the on-disk state file in one pass.
-	defaults, err := state.ReadPromptDefaultsFromDisk()
+	defaults, err := state.ReadBlockNodePromptDefaultsFromDisk(selectedRelease())
 	if err != nil {
 		logx.As().Debug().Err(err).Msg("Could not read prompt defaults from state file; using config/defaults only")
 	}
@@ -189,6 +203,11 @@ func validateBlockNodeFlags(cmd *cobra.Command) error {
 			return errorx.IllegalArgument.Wrap(err, "invalid --plugins value")
 		}
 	}
+	if flagRelease != "" && flagReleaseName != "" && flagRelease != flagReleaseName {
+		return errorx.IllegalArgument.New(
+			"--release %q and --release-name %q name different block nodes", flagRelease, flagReleaseName).
+			WithProperty(models.ErrPropertyResolution, "pass only --release to select the block node instance")
+	}
 	// Validate operator-supplied statusz overrides (install/reconfigure only) by
 	// reusing the daemon's own StatuszConfig.Validate, so malformed input fails
 	// immediately with an actionable hint rather than only at daemon.yaml write
@@ -214,6 +233,14 @@ func validateBlockNodeFlags(cmd *cobra.Command) error {
 func prepareBlocknodeInputs(cmd *cobra.Command, args []string) (*models.UserInputs[models.BlockNodeInputs], *prompt.ChosenValues, error) {
 	var err error
 
+	// Operator plugin preset files must be loaded (and valid) before the preset
+	// is prompted for or resolved to a plugin list.
+	if !infersFromState(cmd) {
+		if _, err = bnpkg.LoadPluginPresets(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// ── Interactive prompts ──────────────────────────────────────────────
 	// Run prompts for missing flags before extracting/validating them.
 	cv, err := promptForMissingFlags(cmd, args)
@@ -224,8 +251,9 @@ func prepareBlocknodeInputs(cmd *cobra.Command, args []string) (*models.UserInpu
 	// ── Extract & validate flags ─────────────────────────────────────────
 	// extract shared flags set in the parent commands
 	var parentFlags BlockNodeFlags
-	// Uninstall infers profile from existing state/config, so it is not required on the CLI.
-	requireProfile := cmd.Name() != "uninstall"
+	// Uninstall, backup, restore, storage gc/forecast and rollback infer profile from existing state/config, so
+	// it is not required on the CLI.
+	requireProfile := !infersFromState(cmd)
 	err = extractBlockNodeParentFlagsWithOpts(cmd, args, &parentFlags, requireProfile)
 	if err != nil {
 		return nil, nil, err
@@ -241,6 +269,25 @@ func prepareBlocknodeInputs(cmd *cobra.Command, args []string) (*models.UserInpu
 		}
 	}
 
+	// Resolve the backup/restore locations to absolute paths up front; the
+	// workflow compares them against the (absolute) storage paths.
+	var backupTarget, restoreFrom, storageGCArchiveTo string
+	if flagBackupTarget != "" {
+		if backupTarget, err = sanity.SanitizePath(flagBackupTarget); err != nil {
+			return nil, nil, errorx.IllegalArgument.Wrap(err, "invalid --target")
+		}
+	}
+	if flagRestoreFrom != "" {
+		if restoreFrom, err = sanity.SanitizePath(flagRestoreFrom); err != nil {
+			return nil, nil, errorx.IllegalArgument.Wrap(err, "invalid --from")
+		}
+	}
+	if flagStorageGCArchiveTo != "" {
+		if storageGCArchiveTo, err = sanity.SanitizePath(flagStorageGCArchiveTo); err != nil {
+			return nil, nil, errorx.IllegalArgument.Wrap(err, "invalid --archive-to")
+		}
+	}
+
 	// Determine execution mode based on flags
 	execMode, err := common.GetExecutionMode(flagContinueOnError, flagStopOnError, flagRollbackOnError)
 	if err != nil {
@@ -270,7 +317,7 @@ func prepareBlocknodeInputs(cmd *cobra.Command, args []string) (*models.UserInpu
 	// plugins.names): re-resolving from the saved preset would clobber the values file.
 	if cmd.Name() == "upgrade" && pluginPreset == "" && pluginList == "" &&
 		!bnpkg.ValuesFileDefinesPlugins(validatedValuesFile) {
-		if stateDefaults, defErr := state.ReadPromptDefaultsFromDisk(); defErr == nil {
+		if stateDefaults, defErr := state.ReadBlockNodePromptDefaultsFromDisk(selectedRelease()); defErr == nil {
 			if bnpkg.IsKnownPreset(stateDefaults.BlockNode.PluginPreset) {
 				pluginPreset = stateDefaults.BlockNode.PluginPreset
 				pluginList = bnpkg.PluginListForPreset(pluginPreset, flagChartVersion)
@@ -292,7 +339,7 @@ func prepareBlocknodeInputs(cmd *cobra.Command, args []string) (*models.UserInpu
 		},
 		Custom: models.BlockNodeInputs{
 			Namespace:    flagNamespace,
-			Release:      flagReleaseName,
+			Release:      selectedRelease(),
 			Chart:        flagChartRepo,
 			ChartVersion: flagChartVersion,
 			Storage: models.BlockNodeStorage{
@@ -318,6 +365,8 @@ func prepareBlocknodeInputs(cmd *cobra.Command, args []string) (*models.UserInpu
 			NoRestart:           flagNoRestart,
 			SkipHardwareChecks:  parentFlags.SkipHardwareChecks,
 			LoadBalancerEnabled: flagLoadBalancerEnabled,
+			LoadBalancerPool:    flagLoadBalancerPool,
+			ServicePort:         flagServicePort,
 			HistoricRetention:   flagHistoricRetention,
 			RecentRetention:     flagRecentRetention,
 			PluginPreset:        pluginPreset,
@@ -327,6 +376,13 @@ func prepareBlocknodeInputs(cmd *cobra.Command, args []string) (*models.UserInpu
 			Timeout:             flagHelmTimeout,
 			StatuszBaseURL:      flagStatuszBaseURL,
 			StatuszPollInterval: flagStatuszPollInterval,
+			BackupTarget:        backupTarget,
+			BackupIncremental:   flagBackupIncremental,
+			RestoreFrom:         restoreFrom,
+			StorageGCArchiveTo:  storageGCArchiveTo,
+			RollbackRevision:    flagToRevision,
+
+			SkipValuesValidation: flagSkipValuesValidation,
 		},
 	}
 
@@ -132,6 +132,7 @@ var installCmd = &cobra.Command{
 func init() {
 	installCmd.Flags().StringVar(&flagChartVersion, "chart-version", "", "Helm chart version to use")
 	common.FlagValuesFile().SetVarP(installCmd, &flagValuesFile, false)
+	common.FlagSkipValuesValidation().SetVarP(installCmd, &flagSkipValuesValidation, false)
 	common.FlagHelmTimeout().SetVarP(installCmd, &flagHelmTimeout, false)
 	common.RegisterHostFirewallFlags(installCmd)
 	common.RegisterTrafficShapingFlags(installCmd)
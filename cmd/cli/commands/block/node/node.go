@@ -18,10 +18,12 @@ var (
 	flagContinueOnError bool
 
 	flagValuesFile           string
+	flagSkipValuesValidation bool
 	flagChartVersion         string
 	flagChartRepo            string
 	flagNamespace            string
 	flagReleaseName          string
+	flagRelease              string
 	flagBasePath             string
 	flagArchivePath          string
 	flagLivePath             string
@@ -40,6 +42,8 @@ var (
 	flagPluginPreset         string
 	flagPlugins              string
 	flagLoadBalancerEnabled  bool
+	flagLoadBalancerPool     string
+	flagServicePort          int
 	flagEgressInterface      string
 	flagLinkRate             string
 	flagShape                []string
@@ -58,7 +62,7 @@ var (
 )
 
 // BlockNodeFlags contains the root-level flags plus the profile flag
-// required by all block node subcommands (install, upgrade, reset, uninstall, check).
+// required by all block node subcommands (install, upgrade, rollback, reset, uninstall, backup, restore, storage gc, check).
 type BlockNodeFlags struct {
 	common.RootFlags
 	Profile string
@@ -79,6 +83,9 @@ func init() {
 	common.FlagNamespace().SetVarP(nodeCmd, &flagNamespace, false)
 	common.FlagReleaseName().SetVarP(nodeCmd, &flagReleaseName, false)
 
+	// Block node instance selector, for hosts running several block nodes
+	common.FlagRelease().SetVarP(nodeCmd, &flagRelease, false)
+
 	// Storage path configuration flags
 	common.FlagBasePath().SetVarP(nodeCmd, &flagBasePath, false)
 	common.FlagArchivePath().SetVarP(nodeCmd, &flagArchivePath, false)
@@ -104,10 +111,21 @@ func init() {
 	common.FlagPluginPreset().SetVarP(nodeCmd, &flagPluginPreset, false)
 	common.FlagPlugins().SetVarP(nodeCmd, &flagPlugins, false)
 
-	// LoadBalancer / MetalLB annotation flag
+	// LoadBalancer / MetalLB annotation and service port flags
 	common.FlagLoadBalancerEnabled().SetVarP(nodeCmd, &flagLoadBalancerEnabled, false)
+	common.FlagLoadBalancerPool().SetVarP(nodeCmd, &flagLoadBalancerPool, false)
+	common.FlagServicePort().SetVarP(nodeCmd, &flagServicePort, false)
+
+	nodeCmd.AddCommand(checkCmd, installCmd, applyCmd, upgradeCmd, rollbackCmd, reconfigureCmd, resetCmd, uninstallCmd, backupCmd, restoreCmd, storageCmd, reconcileShaperCmd, tcAttachCmd, presetsCmd, probeCmd)
+}
 
-	nodeCmd.AddCommand(checkCmd, installCmd, upgradeCmd, reconfigureCmd, resetCmd, uninstallCmd, reconcileShaperCmd, tcAttachCmd)
+// selectedRelease returns the block node instance the command operates on:
+// --release, else --release-name, else "" (the default instance).
+func selectedRelease() string {
+	if flagRelease != "" {
+		return flagRelease
+	}
+	return flagReleaseName
 }
 
 func GetCmd() *cobra.Command {
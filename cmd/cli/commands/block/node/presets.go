@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	presetsCmd = &cobra.Command{
+		Use:   "presets",
+		Short: "Inspect the Block Node plugin presets",
+		Long: `Inspect the plugin presets --plugin-preset selects from: the presets built into
+solo-provisioner plus the preset files in ` + models.Paths().PluginPresetsDir + `.
+A file there with the id of a built-in preset replaces it.`,
+		RunE: common.DefaultRunE,
+	}
+
+	presetsListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the Block Node plugin presets",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog, err := bnpkg.LoadPluginPresets()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "%-16s %-16s %-10s %s\n", "ID", "CHART VERSIONS", "HARDWARE", "SOURCE")
+			for _, p := range catalog.Presets() {
+				versions := "all"
+				if first := p.Plugins[0].MinVersion; first != "" {
+					versions = ">= " + first
+				}
+				floors := "-"
+				if n := len(p.Hardware.Floors); n > 0 {
+					floors = fmt.Sprintf("%d floor(s)", n)
+				}
+				_, _ = fmt.Fprintf(out, "%-16s %-16s %-10s %s\n", p.ID, versions, floors, p.Source)
+			}
+			return nil
+		},
+	}
+
+	presetsShowCmd = &cobra.Command{
+		Use:   "show <preset>",
+		Short: "Show a Block Node plugin preset",
+		Long: `Show a plugin preset as loaded: its plugin list for each chart version range and
+the hardware floors preflight checks enforce for it. Pass --chart-version to
+print only the plugin list that version resolves to.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog, err := bnpkg.LoadPluginPresets()
+			if err != nil {
+				return err
+			}
+			preset, ok := catalog.Get(args[0])
+			if !ok {
+				ids := make([]string, 0)
+				for _, p := range catalog.Presets() {
+					ids = append(ids, p.ID)
+				}
+				return errorx.IllegalArgument.New("unknown plugin preset %q", args[0]).
+					WithProperty(models.ErrPropertyResolution, "use one of: "+strings.Join(ids, ", "))
+			}
+
+			out := cmd.OutOrStdout()
+			if flagPresetChartVersion != "" {
+				list := preset.PluginListFor(flagPresetChartVersion)
+				if list == "" {
+					return errorx.IllegalArgument.New("plugin preset %q has no plugin list for chart version %s",
+						preset.ID, flagPresetChartVersion)
+				}
+				_, _ = fmt.Fprintln(out, list)
+				return nil
+			}
+
+			data, err := yaml.Marshal(preset)
+			if err != nil {
+				return errorx.InternalError.Wrap(err, "failed to render plugin preset %q", preset.ID)
+			}
+			_, _ = fmt.Fprintf(out, "# source: %s\n%s", preset.Source, data)
+			return nil
+		},
+	}
+
+	flagPresetChartVersion string
+)
+
+func init() {
+	presetsShowCmd.Flags().StringVar(&flagPresetChartVersion, "chart-version", "",
+		"Print only the plugin list the preset resolves to at this chart version")
+	presetsCmd.AddCommand(presetsListCmd, presetsShowCmd)
+}
@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/blocknode/probe"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/deps"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagProbeSamples       int
+	flagProbeTimeout       time.Duration
+	flagProbeAddress       string
+	flagProbePort          int
+	flagProbeTLS           bool
+	flagProbeTLSServerName string
+	flagProbeTLSCAFile     string
+	flagProbeHistory       int
+
+	probeCmd = &cobra.Command{
+		Use:   "probe",
+		Short: "Probe a Block Node's external reachability and latency",
+		Long: `Check that the block node answers from outside its pod, over the same
+LoadBalancer path an external client takes.
+
+This command will:
+1. Resolve the block node's LoadBalancer Service: its external IP, the gRPC port
+   it advertises, every port it publishes and the block node's health port
+2. Call the block node's gRPC serverStatus --samples times, each on a new
+   connection, measuring the TCP connect and the time to the first response byte
+3. Dial every published port once (a closed health port is reported, not a fault:
+   the bn-health policy keeps it off the network)
+4. With --tls, check the certificate the gRPC port presents
+5. Append the result to the probe history and print it
+
+The verdict tells the network from the block node:
+  healthy    every sample answered and every published port open
+  degraded   answered, but some samples failed, a port is closed or the
+             certificate is invalid or expiring
+  unhealthy  connections accepted, but serverStatus failed
+  stalled    connections accepted, but serverStatus never answered: the block
+             node hangs
+  refused    every connection refused: nothing listens behind the port
+  blackhole  no connection answered at all: MetalLB, Cilium or a firewall drops
+             the traffic
+
+The command fails on unhealthy, stalled, refused and blackhole. While the
+daemon's block node component is enabled, its probe monitor probes the last
+resolved target every 5 minutes; --history shows those results too.`,
+		Example: `  # Probe the default block node
+  sudo solo-provisioner block node probe
+
+  # Probe a TLS endpoint through a private CA
+  sudo solo-provisioner block node probe --tls --tls-ca-file=/etc/bn/ca.pem --tls-server-name=bn.example.com
+
+  # Probe an address directly, e.g. from outside the cluster
+  solo-provisioner block node probe --address=203.0.113.10
+
+  # Show the last 20 probes (yours and the daemon's)
+  solo-provisioner block node probe --history=20`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instance := models.BlockNodeInstance(selectedRelease())
+			_, historyPath := models.Paths().BlockNodeProbePaths(instance)
+			store := probe.Store{Path: historyPath}
+
+			if flagProbeHistory > 0 {
+				return showProbeHistory(cmd, store, flagProbeHistory)
+			}
+			if flagProbeSamples <= 0 {
+				return errorx.IllegalArgument.New("--samples must be positive, got %d", flagProbeSamples)
+			}
+
+			target, err := resolveProbeTarget(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			res, err := probe.Run(cmd.Context(), target, probe.Options{Samples: flagProbeSamples, Timeout: flagProbeTimeout})
+			if err != nil {
+				return err
+			}
+			res.Instance = instance
+			if err := store.Append(res, res.Time); err != nil {
+				logx.As().Warn().Err(err).Msg("Could not record the probe result")
+			}
+
+			if common.OutputIsJSON() {
+				data, err := json.Marshal(res)
+				if err != nil {
+					return errorx.InternalError.Wrap(err, "failed to render the probe result")
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			} else {
+				probe.WriteResult(cmd.OutOrStdout(), res)
+			}
+
+			if res.Verdict.Failed() {
+				return errorx.IllegalState.New("block node at %s is %s", res.Target, res.Verdict).
+					WithProperty(models.ErrPropertyResolution, probeResolution(res.Verdict))
+			}
+			return nil
+		},
+	}
+)
+
+// resolveProbeTarget returns the target given by --address, else the
+// LoadBalancer endpoint of the selected block node, which is also recorded
+// for the daemon's probe monitor.
+func resolveProbeTarget(ctx context.Context) (probe.Target, error) {
+	var tlsConfig *probe.TLSConfig
+	if flagProbeTLS || flagProbeTLSServerName != "" || flagProbeTLSCAFile != "" {
+		tlsConfig = &probe.TLSConfig{ServerName: flagProbeTLSServerName, CAFile: flagProbeTLSCAFile}
+	}
+
+	if flagProbeAddress != "" {
+		return probe.Target{
+			Address:     flagProbeAddress,
+			ServicePort: flagProbePort,
+			Ports:       []probe.Port{{Name: "service", Port: flagProbePort}},
+			TLS:         tlsConfig,
+		}, nil
+	}
+
+	inputs, err := probeBlockNodeInputs()
+	if err != nil {
+		return probe.Target{}, err
+	}
+	m, err := bnpkg.NewManager(inputs)
+	if err != nil {
+		return probe.Target{}, err
+	}
+	target, err := m.ProbeTarget(ctx)
+	if err != nil {
+		return probe.Target{}, err
+	}
+	target.TLS = tlsConfig
+
+	targetPath, _ := models.Paths().BlockNodeProbePaths(models.BlockNodeInstance(inputs.Release))
+	if err := probe.WriteTarget(targetPath, target); err != nil {
+		logx.As().Warn().Err(err).Msg("Could not record the probe target for the daemon")
+	}
+	return target, nil
+}
+
+// probeBlockNodeInputs returns the release and namespace of the selected block
+// node: --namespace when given, else the namespace the state file records for
+// the release, else the default namespace.
+func probeBlockNodeInputs() (models.BlockNodeInputs, error) {
+	inputs := models.BlockNodeInputs{Release: selectedRelease(), Namespace: flagNamespace}
+	if inputs.Release == "" {
+		inputs.Release = deps.BLOCK_NODE_RELEASE
+	}
+	if inputs.Namespace != "" {
+		return inputs, nil
+	}
+
+	releases, err := state.ReadBlockNodeReleasesFromDisk()
+	if err != nil {
+		return inputs, err
+	}
+	for _, r := range releases {
+		if r.Release == inputs.Release && r.Namespace != "" {
+			inputs.Namespace = r.Namespace
+			return inputs, nil
+		}
+	}
+	inputs.Namespace = deps.BLOCK_NODE_NAMESPACE
+	return inputs, nil
+}
+
+// showProbeHistory prints the last n stored results.
+func showProbeHistory(cmd *cobra.Command, store probe.Store, n int) error {
+	results, err := store.Read()
+	if err != nil {
+		return err
+	}
+	if len(results) > n {
+		results = results[len(results)-n:]
+	}
+
+	out := cmd.OutOrStdout()
+	if common.OutputIsJSON() {
+		data, err := json.Marshal(results)
+		if err != nil {
+			return errorx.InternalError.Wrap(err, "failed to render the probe history")
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+		return nil
+	}
+	if len(results) == 0 {
+		_, _ = fmt.Fprintf(out, "No probe results in %s yet.\n", store.Path)
+		return nil
+	}
+	probe.WriteHistory(out, results)
+	return nil
+}
+
+// probeResolution returns what to check for a failed verdict.
+func probeResolution(v probe.Verdict) []string {
+	switch v {
+	case probe.VerdictBlackhole:
+		return []string{
+			"Packets to the LoadBalancer IP are dropped before they reach the block node.",
+			"Check that MetalLB announces the IP: kubectl -n metallb-system logs -l component=speaker",
+			"Check Cilium's service translation: cilium service list (look for the LoadBalancer IP)",
+			"Check the host firewall: nft list ruleset",
+		}
+	case probe.VerdictRefused:
+		return []string{
+			"The address answers but nothing listens behind the port.",
+			"Check the Service has ready endpoints: kubectl -n <namespace> get endpointslices",
+			"Check the block node pod is running and ready: kubectl -n <namespace> get pods",
+		}
+	case probe.VerdictStalled:
+		return []string{
+			"The network path works but the block node does not answer.",
+			"Check the block node logs: kubectl -n <namespace> logs <pod>",
+			"Check the pod's CPU and memory limits and the storage it writes to",
+		}
+	default:
+		return []string{
+			"The block node answers serverStatus with an error.",
+			"Check the block node logs: kubectl -n <namespace> logs <pod>",
+		}
+	}
+}
+
+func init() {
+	probeCmd.Flags().IntVar(&flagProbeSamples, "samples", probe.DefaultSamples,
+		"Number of serverStatus calls, each on a new connection")
+	probeCmd.Flags().DurationVar(&flagProbeTimeout, "timeout", probe.DefaultTimeout,
+		"Timeout of each call and each port dial")
+	probeCmd.Flags().StringVar(&flagProbeAddress, "address", "",
+		"Probe this address instead of the block node's LoadBalancer IP (not recorded for the daemon)")
+	probeCmd.Flags().IntVar(&flagProbePort, "port", int(bnpkg.BlockNodePublicPort),
+		"gRPC port to probe with --address")
+	probeCmd.Flags().BoolVar(&flagProbeTLS, "tls", false,
+		"Call the gRPC service over TLS and check its certificate")
+	probeCmd.Flags().StringVar(&flagProbeTLSServerName, "tls-server-name", "",
+		"Name the certificate must be valid for (default: the address); implies --tls")
+	probeCmd.Flags().StringVar(&flagProbeTLSCAFile, "tls-ca-file", "",
+		"PEM CA bundle to verify the certificate against instead of the system roots; implies --tls")
+	probeCmd.Flags().IntVar(&flagProbeHistory, "history", 0,
+		"Show the last N recorded probe results instead of probing")
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
 	"github.com/hashgraph/solo-weaver/internal/blocknode/shaper"
+	"github.com/hashgraph/solo-weaver/pkg/models"
 )
 
 var (
@@ -25,13 +26,14 @@ var (
 			"traffic-shaper's nft policy sets, and reconcile live set membership.\n\n" +
 			"With --check (unprivileged) it only fetches and prints a digest of the desired membership " +
 			"and touches no nft state. Without --check (root; the daemon invokes it via sudo) it reads " +
-			"the live nft sets, diffs, and rewrites only the policies whose membership changed.",
+			"the live nft sets, diffs, and rewrites only the policies whose membership changed.\n\n" +
+			"--release selects the policy sets of an additional block node instance on the host.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if flagStatuszURL == "" {
 				return errorx.IllegalArgument.New("--statusz-url is required")
 			}
 
-			r := shaper.NewReconciler(flagStatuszURL)
+			r := shaper.NewInstanceReconciler(flagStatuszURL, models.BlockNodeInstance(flagRelease))
 
 			if flagReconcileDry {
 				return runReconcileCheck(cmd, r)
@@ -52,7 +52,7 @@ var (
 			// one without the block node ever recording a decision (#1003). On an
 			// unreadable state file, bias both to enabled so a default-accept never tears
 			// an established plane down.
-			stateDefaults, err := state.ReadPromptDefaultsFromDisk()
+			stateDefaults, err := state.ReadBlockNodePromptDefaultsFromDisk(selectedRelease())
 			if err != nil {
 				logx.As().Debug().Err(err).Msg("could not read state file for reconfigure seeds; using conservative defaults")
 			}
@@ -115,6 +115,10 @@ var (
 				return err
 			}
 
+			if flagPlan {
+				return printUpgradePlan(cmd, models.ActionReconfigure, inputs)
+			}
+
 			intent := models.Intent{
 				Action: models.ActionReconfigure,
 				Target: models.TargetBlockNode,
@@ -178,8 +182,10 @@ func init() {
 	common.FlagWithStorageReset().SetVarP(reconfigureCmd, &flagWithReset, false)
 	common.FlagPurgeStorage().SetVarP(reconfigureCmd, &flagPurgeStorage, false)
 	common.FlagValuesFile().SetVarP(reconfigureCmd, &flagValuesFile, false)
+	common.FlagSkipValuesValidation().SetVarP(reconfigureCmd, &flagSkipValuesValidation, false)
 	common.FlagNoReuseValues().SetVarP(reconfigureCmd, &flagNoReuseValues, false)
 	common.FlagNoRestart().SetVar(reconfigureCmd, &flagNoRestart, false)
+	common.FlagPlan().SetVarP(reconfigureCmd, &flagPlan, false)
 	common.RegisterHostFirewallFlags(reconfigureCmd)
 	common.RegisterTrafficShapingFlags(reconfigureCmd)
 	common.RegisterEgressFlags(reconfigureCmd, &flagEgressInterface, &flagLinkRate)
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagRestoreFrom string
+
+	restoreCmd = &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a Hedera Block Node's storage from a backup",
+		Long: `Restore a Hedera Block Node's storage from a directory written by 'block node backup'.
+
+This command will:
+1. Verify the backup: every archive checksum in its chain, and that the deployed
+   chart version mounts every volume it holds (the optional-storage registry)
+2. Scale down the block node StatefulSet and wait for the pod to terminate
+3. Clear every storage directory and repopulate it from the backup
+4. Scale the StatefulSet back up and wait for the block node to become ready
+
+Volumes the deployed chart needs but the backup lacks start empty. --force
+restores a backup taken with a newer chart, or one holding a volume the deployed
+chart no longer mounts (that volume is skipped).
+
+WARNING: All data currently on the block node's storage is replaced. If the restore
+fails after step 2, the block node is left scaled down.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputs, _, err := prepareBlocknodeInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = initializeDependencies()
+			if err != nil {
+				return err
+			}
+
+			intent := models.Intent{
+				Action: models.ActionRestore,
+				Target: models.TargetBlockNode,
+			}
+
+			logx.As().Debug().
+				Any("intent", intent).
+				Any("inputs", inputs).
+				Msg("Restoring Hedera Block Node storage")
+
+			handler, err := blockNodeHandler.ForAction(intent.Action)
+			if err != nil {
+				return err
+			}
+
+			if err := common.RunWorkflow(cmd.Context(), func() (*automa.Report, error) {
+				return handler.HandleIntent(cmd.Context(), intent, *inputs)
+			}); err != nil {
+				return err
+			}
+
+			logx.As().Info().Str("from", inputs.Custom.RestoreFrom).Msg("Successfully restored Hedera Block Node storage")
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagRestoreFrom().SetVarP(restoreCmd, &flagRestoreFrom, true)
+}
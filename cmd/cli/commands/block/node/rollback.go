@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagToRevision int
+
+	rollbackCmd = &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll a Hedera Block Node back to an earlier chart revision",
+		Long: `Roll a Hedera Block Node release back to an earlier Helm revision, e.g. after an
+upgrade to a chart version that crash-loops.
+
+This command will:
+1. Read the release history and pick the revision to return to: --to-revision, or
+   the previous successfully deployed revision
+2. Compare the optional storage (verification, plugins, application-state) the
+   two chart versions mount, and refuse a rollback below a storage boundary the
+   block node cannot cross back over
+3. Re-create the PVs/PVCs of storage the target version mounts again, and delete
+   the StatefulSet (orphan cascade) when the mounted volumes change
+4. Roll the release back and wait for the block node to become ready
+5. Record the rolled-back release in the state and the action history
+
+Storage only the newer version mounts is left in place, data included.
+--force rolls back across a boundary the storage does not support; restore a
+backup taken before the upgrade afterwards ('block node restore').`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateBlockNodeFlags(cmd); err != nil {
+				return err
+			}
+
+			inputs, _, err := prepareBlocknodeInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = initializeDependencies()
+			if err != nil {
+				return err
+			}
+
+			intent := models.Intent{
+				Action: models.ActionRollback,
+				Target: models.TargetBlockNode,
+			}
+
+			logx.As().Debug().
+				Any("intent", intent).
+				Any("inputs", inputs).
+				Msg("Rolling back Hedera Block Node")
+
+			handler, err := blockNodeHandler.ForAction(intent.Action)
+			if err != nil {
+				return err
+			}
+
+			if err := common.RunWorkflow(cmd.Context(), func() (*automa.Report, error) {
+				return handler.HandleIntent(cmd.Context(), intent, *inputs)
+			}); err != nil {
+				return err
+			}
+
+			logx.As().Info().Msg("Successfully rolled back Hedera Block Node")
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagToRevision().SetVarP(rollbackCmd, &flagToRevision, false)
+	common.FlagHelmTimeout().SetVarP(rollbackCmd, &flagHelmTimeout, false)
+}
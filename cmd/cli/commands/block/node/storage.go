@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/spf13/cobra"
+)
+
+var storageCmd = &cobra.Command{
+	Use:   "storage",
+	Short: "Manage a Hedera Block Node's storage volumes",
+	Long:  "Manage a Hedera Block Node's storage volumes",
+	RunE:  common.DefaultRunE,
+}
+
+func init() {
+	storageCmd.AddCommand(storageGCCmd)
+	storageCmd.AddCommand(storageForecastCmd)
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/blocknode/forecast"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var storageForecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Project when each Block Node storage volume runs out of disk",
+	Long: `Measure the on-disk size of each block node storage volume, fit a growth rate
+to the recorded samples and project days-to-full for the filesystem behind it.
+
+This command will:
+1. Resolve the storage paths and retention thresholds of the deployed block node
+   (or of the configuration, before install)
+2. Record a sample of each volume's size; the daemon's storage sampler adds one
+   every hour while the block node component is enabled
+3. Fit each volume's growth per day over the last 30 days of samples
+4. Cap the live tier at --recent-retention blocks and the archive tier at
+   --historic-retention blocks (0 = unbounded), sized by the block size measured
+   in the live tier, and project when each volume reaches its cap and when its
+   filesystem fills
+5. Warn when the volume sizes and retention cannot fit the filesystem behind the
+   base path, as install and reconfigure do
+
+Growth rates need samples spanning at least an hour. Pass --recent-retention or
+--historic-retention to project a retention change before applying it with
+'block node reconfigure'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputs, _, err := prepareBlocknodeInputs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		err = initializeDependencies()
+		if err != nil {
+			return err
+		}
+
+		intent := models.Intent{
+			Action: models.ActionStorageForecast,
+			Target: models.TargetBlockNode,
+		}
+
+		handler, err := blockNodeHandler.ForAction(intent.Action)
+		if err != nil {
+			return err
+		}
+
+		// Project from the same effective storage layout the handler samples.
+		effective, err := handler.PrepareEffectiveInputs(intent, *inputs)
+		if err != nil {
+			return err
+		}
+
+		logx.As().Debug().
+			Any("intent", intent).
+			Any("inputs", inputs).
+			Msg("Sampling Hedera Block Node storage")
+
+		if err := common.RunWorkflow(cmd.Context(), func() (*automa.Report, error) {
+			return handler.HandleIntent(cmd.Context(), intent, *inputs)
+		}); err != nil {
+			return err
+		}
+
+		_, samplesPath := models.Paths().BlockNodeStoragePaths(models.BlockNodeInstance(effective.Custom.Release))
+		samples, err := forecast.Store{Path: samplesPath}.Read()
+		if err != nil {
+			return err
+		}
+		forecasts, err := bnpkg.ForecastStorage(effective.Custom, samples)
+		if err != nil {
+			return err
+		}
+		warnings, err := bnpkg.CheckStorageCapacity(effective.Custom, samples)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		bnpkg.WriteStorageForecast(out, forecasts)
+		for _, w := range warnings {
+			_, _ = fmt.Fprintln(out, "WARNING: "+w)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/ui/prompt"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagStorageGCArchiveTo string
+
+	// flagStorageGCYes confirms the deletion without a prompt. As with
+	// self-uninstall there is deliberately no -y short form.
+	flagStorageGCYes bool
+
+	storageGCCmd = &cobra.Command{
+		Use:   "gc",
+		Short: "Archive or delete storage volumes the installed Block Node no longer mounts",
+		Long: `Find the storage volumes the installed block node chart version has retired
+(e.g. the verification volume, dropped at chart 0.37.0), report their PV/PVC and
+the size of their host directory, and, once confirmed, delete them.
+
+This command will:
+1. Resolve the installed chart version and the retired volumes for it
+2. Report each retired volume's PVC, PV (with capacity) and host directory size
+3. Ask for confirmation (or take --yes)
+4. With --archive-to, write the host directories to a checksummed tar/zstd archive
+   <dir>/<release>-retired-storage-<timestamp>; nothing is deleted if this fails
+5. Delete each retired PVC, PV and host directory
+
+The block node keeps running: retired volumes are not mounted. The upgrade that
+retires a volume reports it and points here; nothing is removed automatically.
+Archives of retired volumes cannot be fed to 'block node restore'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputs, _, err := prepareBlocknodeInputs(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			err = initializeDependencies()
+			if err != nil {
+				return err
+			}
+
+			intent := models.Intent{
+				Action: models.ActionStorageGC,
+				Target: models.TargetBlockNode,
+			}
+
+			handler, err := blockNodeHandler.ForAction(intent.Action)
+			if err != nil {
+				return err
+			}
+
+			// Report before asking: resolve the deployed storage layout the
+			// same way the handler will, and inspect it read-only.
+			effective, err := handler.PrepareEffectiveInputs(intent, *inputs)
+			if err != nil {
+				return err
+			}
+			manager, err := bnpkg.NewManager(effective.Custom)
+			if err != nil {
+				return err
+			}
+			found, err := manager.FindRetiredStorage(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if len(found) == 0 {
+				logx.As().Info().Msg("No retired Block Node storage to collect")
+				return nil
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintln(out, "Retired Block Node storage:")
+			for _, r := range found {
+				_, _ = fmt.Fprintln(out, "  "+r.String())
+			}
+
+			if err := confirmStorageGC(found, effective.Custom.StorageGCArchiveTo); err != nil {
+				return err
+			}
+
+			logx.As().Debug().
+				Any("intent", intent).
+				Any("inputs", inputs).
+				Msg("Collecting retired Hedera Block Node storage")
+
+			if err := common.RunWorkflow(cmd.Context(), func() (*automa.Report, error) {
+				return handler.HandleIntent(cmd.Context(), intent, *inputs)
+			}); err != nil {
+				return err
+			}
+
+			logx.As().Info().Int("volumes", len(found)).Msg("Successfully collected retired Hedera Block Node storage")
+			return nil
+		},
+	}
+)
+
+// confirmStorageGC passes with --yes, asks in an interactive session, and
+// refuses otherwise: an unattended run must opt in explicitly.
+func confirmStorageGC(found []bnpkg.RetiredStorage, archiveTo string) error {
+	if flagStorageGCYes {
+		return nil
+	}
+	if !prompt.ShouldPrompt(false) {
+		return errorx.IllegalArgument.New("refusing to delete retired storage without --yes").
+			WithProperty(models.ErrPropertyResolution,
+				"re-run with --yes (add --archive-to <dir> to keep a copy of the data)")
+	}
+
+	what := "deleted"
+	if archiveTo != "" {
+		what = "archived under " + archiveTo + ", then deleted"
+	}
+	ok, err := prompt.RunConfirm(
+		fmt.Sprintf("Collect %d retired storage volume(s)?", len(found)),
+		"Their PVCs, PVs and host directories will be "+what+".",
+		false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errorx.IllegalState.New("aborted: retired storage not collected")
+	}
+	return nil
+}
+
+func init() {
+	common.FlagStorageGCArchiveTo().SetVarP(storageGCCmd, &flagStorageGCArchiveTo, false)
+	storageGCCmd.Flags().BoolVar(&flagStorageGCYes, "yes", false,
+		"Confirm deletion of the retired PVCs, PVs and host directories without prompting")
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/automa-saga/automa"
 	"github.com/automa-saga/logx"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
 	"github.com/hashgraph/solo-weaver/internal/state"
 	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/spf13/cobra"
@@ -17,6 +18,7 @@ var (
 	flagNoReuseValues bool
 	flagWithReset     bool
 	flagPurgeStorage  bool
+	flagPlan          bool
 
 	upgradeCmd = &cobra.Command{
 		Use:   "upgrade",
@@ -41,6 +43,10 @@ var (
 				return err
 			}
 
+			if flagPlan {
+				return printUpgradePlan(cmd, models.ActionUpgrade, inputs)
+			}
+
 			// Upgrade exposes no firewall flags and never prompts; seed the effective
 			// host-firewall config from the last-persisted state so the network-plane's
 			// NetworkFirewallCreate step re-asserts the operator's last-known allowlist
@@ -88,7 +94,7 @@ var (
 			// (with an empty namespace) for a node that may have traffic shaping disabled.
 			// The workflow already re-asserted daemon.yaml from the authoritative
 			// state-manager view, and a running daemon is unaffected.
-			stateDefaults, sErr := state.ReadPromptDefaultsFromDisk()
+			stateDefaults, sErr := state.ReadBlockNodePromptDefaultsFromDisk(selectedRelease())
 			switch {
 			case sErr != nil:
 				logx.As().Warn().Err(sErr).Msg(
@@ -113,6 +119,24 @@ func init() {
 	upgradeCmd.Flags().StringVar(&flagChartVersion, "chart-version", "", "Helm chart version to use")
 	common.FlagWithStorageReset().SetVarP(upgradeCmd, &flagWithReset, false)
 	common.FlagValuesFile().SetVarP(upgradeCmd, &flagValuesFile, false)
+	common.FlagSkipValuesValidation().SetVarP(upgradeCmd, &flagSkipValuesValidation, false)
 	common.FlagNoReuseValues().SetVarP(upgradeCmd, &flagNoReuseValues, false)
 	common.FlagHelmTimeout().SetVarP(upgradeCmd, &flagHelmTimeout, false)
+	common.FlagPlan().SetVarP(upgradeCmd, &flagPlan, false)
+}
+
+// printUpgradePlan previews an upgrade or reconfigure and writes the plan to
+// stdout. It stops short of the workflow and of the post-workflow daemon
+// activation, so nothing on the host or in the cluster changes.
+func printUpgradePlan(cmd *cobra.Command, action models.ActionType, inputs *models.UserInputs[models.BlockNodeInputs]) error {
+	intent := models.Intent{
+		Action: action,
+		Target: models.TargetBlockNode,
+	}
+	plan, err := blockNodeHandler.Plan(cmd.Context(), intent, *inputs)
+	if err != nil {
+		return err
+	}
+	bnpkg.WriteUpgradePlan(cmd.OutOrStdout(), plan)
+	return nil
 }
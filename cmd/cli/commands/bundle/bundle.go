@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/spf13/cobra"
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create and install air-gap bundles",
+	Long: `Create and install air-gap bundles for hosts without network egress.
+
+A bundle is one checksummed archive of every host artifact, Helm chart and
+container image a node type needs, gathered on a connected machine with
+'bundle create'. On the target host, 'bundle install' (or the global --bundle
+flag) makes downloads and chart pulls resolve from it, and pushes its images
+to a local registry CRI-O pulls them from.`,
+	RunE: common.DefaultRunE,
+}
+
+func init() {
+	bundleCmd.AddCommand(createCmd)
+	bundleCmd.AddCommand(installCmd)
+}
+
+func GetCmd() *cobra.Command {
+	return bundleCmd
+}
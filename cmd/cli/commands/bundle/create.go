@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/airgap"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagNodeType string
+	flagProfile  string
+	flagArch     string
+	flagImages   []string
+	flagArchive  string
+
+	createCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create an air-gap bundle for a node type and profile",
+		Long: `Create an air-gap bundle on a machine with network access.
+
+Every host artifact in the infrastructure catalog, every cluster chart and the
+block node chart are downloaded for the target architecture and verified
+against the catalog's checksums and signature policy. The container images the
+charts reference with their default values, and the Kubernetes control-plane
+images, are pulled into the bundle as well. Images that cannot be found this
+way, such as the Cilium images the cilium CLI installs, are added with --image.
+
+The archive is written with a <archive>.sha256 checksum file beside it.`,
+		Example: `  # Bundle a mainnet block node for an amd64 host
+  sudo solo-provisioner bundle create --node-type=block --profile=mainnet
+
+  # Bundle for an arm64 host, with the Cilium images
+  sudo solo-provisioner bundle create --node-type=block --profile=mainnet --arch=arm64 \
+    --image=quay.io/cilium/cilium:v<version>,quay.io/cilium/operator-generic:v<version>`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archive := flagArchive
+			if archive == "" {
+				archive = fmt.Sprintf("solo-provisioner-bundle-%s-%s-%s.tar.gz", flagNodeType, flagProfile, flagArch)
+			}
+
+			result, err := airgap.Create(cmd.Context(), airgap.CreateOptions{
+				NodeType: flagNodeType,
+				Profile:  flagProfile,
+				OS:       software.OSLinux,
+				Arch:     flagArch,
+				Output:   archive,
+				Images:   flagImages,
+			})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if common.OutputIsJSON() {
+				data, err := json.Marshal(result)
+				if err != nil {
+					return errorx.InternalError.Wrap(err, "failed to render the bundle result")
+				}
+				_, _ = fmt.Fprintln(out, string(data))
+				return nil
+			}
+
+			m := result.Manifest
+			_, _ = fmt.Fprintf(out, "  %-10s %d\n", "artifacts", len(m.Artifacts))
+			_, _ = fmt.Fprintf(out, "  %-10s %d\n", "charts", len(m.Charts))
+			_, _ = fmt.Fprintf(out, "  %-10s %d\n", "images", len(m.Images))
+			for _, w := range result.Warnings {
+				_, _ = fmt.Fprintf(out, "Warning: %s\n", w)
+			}
+			_, _ = fmt.Fprintf(out, "Air-gap bundle written to %s (sha256 %s)\n", result.Archive, result.SHA256)
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagNodeType().SetVarP(createCmd, &flagNodeType, false)
+	common.FlagProfile().SetVarP(createCmd, &flagProfile, true)
+	common.FlagBundleArch().SetVarP(createCmd, &flagArch, false)
+	common.FlagBundleImages().SetVarP(createCmd, &flagImages, false)
+	common.FlagBundleArchive().SetVarP(createCmd, &flagArchive, false)
+
+	// Creating a bundle runs on a connected machine that is not provisioned
+	// and need not be the target host.
+	common.SkipGlobalChecks(createCmd)
+}
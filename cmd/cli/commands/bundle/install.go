@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/airgap"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagInstallArchive string
+	flagRegistry       string
+	flagPlainHTTP      bool
+
+	installCmd = &cobra.Command{
+		Use:   "install",
+		Short: "Install an air-gap bundle on this host",
+		Long: `Install an air-gap bundle on the target host.
+
+The archive is verified against its checksum file and every file in it against
+the bundle manifest, then unpacked into the solo-provisioner home. Later
+commands resolve downloads and chart pulls from it without --bundle.
+
+With --registry, the bundle's images are pushed to that registry and CRI-O is
+configured to pull them from it in place of their source registries.`,
+		Example: `  # Install a bundle and push its images to a local registry
+  sudo solo-provisioner bundle install --archive=solo-provisioner-bundle-block-mainnet-amd64.tar.gz \
+    --registry=registry.local:5000
+
+  # Then install the block node as usual
+  sudo solo-provisioner block node install --profile=mainnet`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := airgap.Install(cmd.Context(), airgap.InstallOptions{
+				Archive:   flagInstallArchive,
+				Registry:  flagRegistry,
+				PlainHTTP: flagPlainHTTP,
+			})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if common.OutputIsJSON() {
+				data, err := json.Marshal(result)
+				if err != nil {
+					return errorx.InternalError.Wrap(err, "failed to render the bundle result")
+				}
+				_, _ = fmt.Fprintln(out, string(data))
+				return nil
+			}
+
+			for _, ref := range result.Images {
+				_, _ = fmt.Fprintf(out, "  %s\n", ref)
+			}
+			if result.MirrorsConf != "" {
+				_, _ = fmt.Fprintf(out, "CRI-O registry mirrors written to %s\n", result.MirrorsConf)
+			}
+			for _, w := range result.Warnings {
+				_, _ = fmt.Fprintf(out, "Warning: %s\n", w)
+			}
+			_, _ = fmt.Fprintf(out, "Air-gap bundle installed in %s\n", result.Dir)
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagBundleArchive().SetVarP(installCmd, &flagInstallArchive, true)
+	common.FlagBundleRegistry().SetVarP(installCmd, &flagRegistry, false)
+	common.FlagBundleRegistryPlainHTTP().SetVarP(installCmd, &flagPlainHTTP, false)
+
+	// The bundle provides what the host is provisioned from, so it is
+	// installed before anything the global checks look for.
+	common.SkipGlobalChecks(installCmd)
+}
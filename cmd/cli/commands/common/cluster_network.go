@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/config"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+)
+
+// specKeyPattern matches the key of the next key=value pair in a --lb-pool or
+// --bgp-peer spec, so values such as an address list may contain commas.
+var specKeyPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*=`)
+
+// readRecordedClusterConfig is the seam over the cluster layout recorded in
+// the state file, so tests can resolve without one.
+var readRecordedClusterConfig = state.ReadClusterConfigFromDisk
+
+// ParseLoadBalancerPools parses repeated --lb-pool specs of the form
+// "name=<name>,addresses=<cidr|first-last|machine-ip>[,<...>][,advertisement=l2|bgp]".
+func ParseLoadBalancerPools(specs []string) ([]models.AddressPool, error) {
+	var pools []models.AddressPool
+	for _, spec := range specs {
+		pool := models.AddressPool{Advertisement: models.AdvertisementL2}
+		for _, pair := range splitSpec(spec) {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, errorx.IllegalArgument.New("invalid key=value pair %q in --%s %q", pair, FlagLoadBalancerPools().Name, spec)
+			}
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "name":
+				pool.Name = strings.TrimSpace(value)
+			case "addresses":
+				pool.Addresses = normalizeCIDRs(strings.Split(value, ","))
+			case "advertisement":
+				pool.Advertisement = strings.ToLower(strings.TrimSpace(value))
+			default:
+				return nil, errorx.IllegalArgument.New("unknown key %q in --%s %q, valid keys are: name, addresses, advertisement",
+					key, FlagLoadBalancerPools().Name, spec)
+			}
+		}
+		if pool.Name == "" || len(pool.Addresses) == 0 {
+			return nil, errorx.IllegalArgument.New("--%s %q needs a name and addresses", FlagLoadBalancerPools().Name, spec).
+				WithProperty(models.ErrPropertyResolution, "e.g. --lb-pool name=public,addresses=203.0.113.0/28,advertisement=bgp")
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// ParseBGPPeers parses repeated --bgp-peer specs of the form
+// "name=<name>,address=<ip>,peerAsn=<asn>,myAsn=<asn>".
+func ParseBGPPeers(specs []string) ([]models.BGPPeer, error) {
+	var peers []models.BGPPeer
+	for _, spec := range specs {
+		var peer models.BGPPeer
+		for _, pair := range splitSpec(spec) {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, errorx.IllegalArgument.New("invalid key=value pair %q in --%s %q", pair, FlagBGPPeers().Name, spec)
+			}
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "name":
+				peer.Name = value
+			case "address":
+				peer.PeerAddress = value
+			case "peerasn", "myasn":
+				asn, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, errorx.IllegalArgument.Wrap(err, "invalid %s %q in --%s %q", key, value, FlagBGPPeers().Name, spec)
+				}
+				if strings.EqualFold(key, "peerAsn") {
+					peer.PeerASN = uint32(asn)
+				} else {
+					peer.MyASN = uint32(asn)
+				}
+			default:
+				return nil, errorx.IllegalArgument.New("unknown key %q in --%s %q, valid keys are: name, address, peerAsn, myAsn",
+					key, FlagBGPPeers().Name, spec)
+			}
+		}
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
+
+// splitSpec splits a spec into its key=value pairs on the commas that start
+// a new key.
+func splitSpec(s string) []string {
+	var pairs []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == ',' && specKeyPattern.MatchString(s[i+1:]) {
+			pairs = append(pairs, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		pairs = append(pairs, s[start:])
+	}
+	return pairs
+}
+
+// ResolveClusterConfig determines the cluster network layout `kube cluster
+// install` initialises the cluster with and applies it to the global config,
+// where the kubeadm and MetalLB steps read it. Precedence per field: CLI flag
+// (flags) > config file (`cluster` block) > the layout recorded when the
+// cluster was initialised > built-in default. The load balancer is taken as a
+// whole from the first tier that configures pools.
+//
+// Once a cluster is initialised its name, DNS domain, subnets and node CIDR
+// mask size are fixed: a flag or config value that differs from the recorded
+// one is rejected rather than silently ignored. MetalLB pools may change.
+func ResolveClusterConfig(flags models.ClusterConfig) (models.ClusterConfig, error) {
+	if len(flags.LoadBalancer.BGPPeers) > 0 && len(flags.LoadBalancer.Pools) == 0 {
+		return models.ClusterConfig{}, errorx.IllegalArgument.New("--%s needs the pools it announces", FlagBGPPeers().Name).
+			WithProperty(models.ErrPropertyResolution, "pass the load balancer pools with --lb-pool as well")
+	}
+
+	cfg := flags.Merge(config.Get().Cluster)
+
+	recorded, err := readRecordedClusterConfig()
+	if err != nil {
+		return models.ClusterConfig{}, err
+	}
+	if recorded != nil {
+		if err := cfg.CheckUnchanged(*recorded); err != nil {
+			return models.ClusterConfig{}, err
+		}
+		cfg = cfg.Merge(*recorded)
+	}
+	cfg = cfg.Merge(models.DefaultClusterConfig())
+
+	if err := cfg.Validate(); err != nil {
+		return models.ClusterConfig{}, err
+	}
+	if err := cfg.ValidateHostPodCIDR(config.Get().Host.PodCIDR); err != nil {
+		return models.ClusterConfig{}, err
+	}
+
+	config.OverrideClusterConfig(cfg)
+	return cfg, nil
+}
+
+// clusterPodSubnet returns the pod subnet the host firewall defaults its pod
+// CIDR to: the configured or recorded cluster pod subnet, else the built-in
+// default. configured reports whether it came from the config or the record.
+func clusterPodSubnet() (subnet string, configured bool) {
+	if s := config.Get().Cluster.PodSubnet; s != "" {
+		return s, true
+	}
+	recorded, err := readRecordedClusterConfig()
+	if err != nil {
+		logx.As().Debug().Err(err).Msg("could not read the recorded cluster layout; using the default pod subnet")
+	}
+	if recorded != nil && recorded.PodSubnet != "" {
+		return recorded.PodSubnet, true
+	}
+	return models.DefaultClusterPodCIDR, false
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/pkg/config"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubClusterLayout sets the config file's cluster and host blocks and the
+// layout recorded in the state for the duration of the test.
+func stubClusterLayout(t *testing.T, cluster models.ClusterConfig, hostPodCIDR string, recorded *models.ClusterConfig) {
+	t.Helper()
+	saved := config.Get()
+	cfg := saved
+	cfg.Cluster = cluster
+	cfg.Host.PodCIDR = hostPodCIDR
+	require.NoError(t, config.Set(&cfg))
+
+	orig := readRecordedClusterConfig
+	t.Cleanup(func() {
+		_ = config.Set(&saved)
+		readRecordedClusterConfig = orig
+	})
+	readRecordedClusterConfig = func() (*models.ClusterConfig, error) { return recorded, nil }
+}
+
+func TestParseLoadBalancerPools(t *testing.T) {
+	pools, err := ParseLoadBalancerPools([]string{
+		"name=public,addresses=203.0.113.16/28, 203.0.113.40-203.0.113.45,advertisement=BGP",
+		"name=host,addresses=machine-ip",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.AddressPool{
+		{Name: "public", Addresses: []string{"203.0.113.16/28", "203.0.113.40-203.0.113.45"}, Advertisement: models.AdvertisementBGP},
+		{Name: "host", Addresses: []string{models.MachineIPAddress}, Advertisement: models.AdvertisementL2},
+	}, pools)
+
+	_, err = ParseLoadBalancerPools([]string{"name=public"})
+	require.ErrorContains(t, err, "needs a name and addresses")
+	_, err = ParseLoadBalancerPools([]string{"name=public,addresses=10.1.0.0/24,vlan=7"})
+	require.ErrorContains(t, err, `unknown key "vlan"`)
+}
+
+func TestParseBGPPeers(t *testing.T) {
+	peers, err := ParseBGPPeers([]string{"name=tor1,address=10.10.0.1,peerAsn=64512,myAsn=64513"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.BGPPeer{{Name: "tor1", PeerAddress: "10.10.0.1", PeerASN: 64512, MyASN: 64513}}, peers)
+
+	_, err = ParseBGPPeers([]string{"name=tor1,address=10.10.0.1,peerAsn=x"})
+	require.ErrorContains(t, err, "invalid peerAsn")
+}
+
+func TestResolveClusterConfig(t *testing.T) {
+	t.Run("flags over config over defaults", func(t *testing.T) {
+		stubClusterLayout(t, models.ClusterConfig{Name: "site-a", PodSubnet: "172.24.0.0/14"}, "", nil)
+
+		cfg, err := ResolveClusterConfig(models.ClusterConfig{PodSubnet: "172.28.0.0/14"})
+		require.NoError(t, err)
+		assert.Equal(t, "site-a", cfg.Name)
+		assert.Equal(t, "172.28.0.0/14", cfg.PodSubnet)
+		assert.Equal(t, models.DefaultClusterServiceSubnet, cfg.ServiceSubnet)
+		assert.Equal(t, cfg, config.Get().Cluster, "the layout is applied to the global config")
+	})
+
+	t.Run("recorded layout is kept", func(t *testing.T) {
+		recorded := models.DefaultClusterConfig()
+		recorded.ServiceSubnet = "172.20.0.0/16"
+		stubClusterLayout(t, models.ClusterConfig{}, "", &recorded)
+
+		cfg, err := ResolveClusterConfig(models.ClusterConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, "172.20.0.0/16", cfg.ServiceSubnet)
+
+		_, err = ResolveClusterConfig(models.ClusterConfig{ServiceSubnet: "172.21.0.0/16"})
+		require.ErrorContains(t, err, "cannot change the cluster serviceSubnet")
+	})
+
+	t.Run("host firewall pod CIDR must match", func(t *testing.T) {
+		stubClusterLayout(t, models.ClusterConfig{}, "10.244.0.0/16", nil)
+		_, err := ResolveClusterConfig(models.ClusterConfig{})
+		require.ErrorContains(t, err, "is not the cluster podSubnet")
+	})
+
+	t.Run("peers need pools", func(t *testing.T) {
+		stubClusterLayout(t, models.ClusterConfig{}, "", nil)
+		_, err := ResolveClusterConfig(models.ClusterConfig{LoadBalancer: models.LoadBalancerConfig{
+			BGPPeers: []models.BGPPeer{{Name: "tor1", PeerAddress: "10.10.0.1", PeerASN: 1, MyASN: 2}},
+		}})
+		require.ErrorContains(t, err, "needs the pools it announces")
+	})
+}
+
+func TestResolveHostPodCIDR(t *testing.T) {
+	t.Run("defaults to the recorded pod subnet", func(t *testing.T) {
+		stubClusterLayout(t, models.ClusterConfig{}, "", &models.ClusterConfig{PodSubnet: "172.24.0.0/14"})
+		fw := &models.HostConfig{SSHPort: 22}
+		require.NoError(t, ResolveHostPodCIDR(fw))
+		assert.Equal(t, "172.24.0.0/14", fw.PodCIDR)
+	})
+
+	t.Run("defaults to the built-in subnet without a cluster layout", func(t *testing.T) {
+		stubClusterLayout(t, models.ClusterConfig{}, "", nil)
+		fw := &models.HostConfig{}
+		require.NoError(t, ResolveHostPodCIDR(fw))
+		assert.Equal(t, models.DefaultClusterPodCIDR, fw.PodCIDR)
+	})
+
+	t.Run("rejects a pod CIDR other than the configured subnet", func(t *testing.T) {
+		stubClusterLayout(t, models.ClusterConfig{PodSubnet: "172.24.0.0/14"}, "", nil)
+		require.Error(t, ResolveHostPodCIDR(&models.HostConfig{PodCIDR: models.DefaultClusterPodCIDR}))
+	})
+
+	t.Run("skips a disabled or left-out firewall", func(t *testing.T) {
+		stubClusterLayout(t, models.ClusterConfig{PodSubnet: "172.24.0.0/14"}, "", nil)
+		require.NoError(t, ResolveHostPodCIDR(nil))
+		fw := &models.HostConfig{Disabled: true}
+		require.NoError(t, ResolveHostPodCIDR(fw))
+		assert.Empty(t, fw.PodCIDR)
+	})
+}
@@ -27,6 +27,15 @@ func FlagMonitorBlockNode() FlagDefinition[bool] {
 	}
 }
 
+func FlagMonitorTrafficShaper() FlagDefinition[bool] {
+	return FlagDefinition[bool]{
+		Name:        "monitor-traffic-shaper",
+		ShortName:   "",
+		Description: "Enable scraping of the traffic shaper exporter (`network exporter`) on every node in Alloy",
+		Default:     false,
+	}
+}
+
 func FlagAlloyClusterSecretStore() FlagDefinition[string] {
 	return FlagDefinition[string]{
 		Name:        "cluster-secret-store",
@@ -42,6 +42,15 @@ func FlagReleaseName() FlagDefinition[string] {
 	}
 }
 
+func FlagRelease() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "release",
+		ShortName:   "",
+		Description: "Helm release of the block node instance to operate on, for hosts running several block nodes; defaults to the default instance (block-node). Additional instances get their own namespace, storage base path, service port and policy set names",
+		Default:     "",
+	}
+}
+
 func FlagBasePath() FlagDefinition[string] {
 	return FlagDefinition[string]{
 		Name:        "base-path",
@@ -181,7 +190,7 @@ func FlagPluginPreset() FlagDefinition[string] {
 	return FlagDefinition[string]{
 		Name:        "plugin-preset",
 		ShortName:   "",
-		Description: "Plugin preset to deploy (tier1-lfh, tier1-rfh, custom, or none for no override — use --values/chart default); prompts interactively when omitted",
+		Description: "Plugin preset to deploy (tier1-lfh, tier1-rfh or another preset listed by 'block node presets list', custom, or none for no override — use --values/chart default); prompts interactively when omitted",
 		Default:     "",
 	}
 }
@@ -204,6 +213,24 @@ func FlagLoadBalancerEnabled() FlagDefinition[bool] {
 	}
 }
 
+func FlagServicePort() FlagDefinition[int] {
+	return FlagDefinition[int]{
+		Name:        "service-port",
+		ShortName:   "",
+		Description: "Port of the block node service; defaults to the port recorded for the instance, else 40840, moved up past the ports of the other block nodes on the host",
+		Default:     0,
+	}
+}
+
+func FlagLoadBalancerPool() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "load-balancer-pool",
+		ShortName:   "",
+		Description: "MetalLB IPAddressPool the block node service is announced from; each block node on the host needs its own (default public-address-pool)",
+		Default:     "",
+	}
+}
+
 func FlagHelmTimeout() FlagDefinition[time.Duration] {
 	return FlagDefinition[time.Duration]{
 		Name:        "timeout",
@@ -230,3 +257,66 @@ func FlagStatuszPollInterval() FlagDefinition[string] {
 		Default:     "",
 	}
 }
+
+func FlagBackupTarget() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "target",
+		ShortName:   "",
+		Description: "Directory to write the backup under (a local disk or a mounted NFS/object-store target); each run creates a <release>-<timestamp> subdirectory",
+		Default:     "",
+	}
+}
+
+func FlagBackupIncremental() FlagDefinition[bool] {
+	return FlagDefinition[bool]{
+		Name:        "incremental",
+		ShortName:   "",
+		Description: "Store only the archive-tier files added since the newest backup under --target; every other volume is still backed up in full",
+		Default:     false,
+	}
+}
+
+func FlagStorageGCArchiveTo() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "archive-to",
+		ShortName:   "",
+		Description: "Directory to archive retired storage volumes under before deleting them; each run creates a <release>-retired-storage-<timestamp> subdirectory",
+		Default:     "",
+	}
+}
+
+func FlagToRevision() FlagDefinition[int] {
+	return FlagDefinition[int]{
+		Name:        "to-revision",
+		ShortName:   "",
+		Description: "Helm revision of the block node release to roll back to; defaults to the previous successfully deployed revision",
+		Default:     0,
+	}
+}
+
+func FlagPlan() FlagDefinition[bool] {
+	return FlagDefinition[bool]{
+		Name:        "plan",
+		ShortName:   "",
+		Description: "Show the values diff, chart version change, storage migrations and destructive steps, then exit without changing anything",
+		Default:     false,
+	}
+}
+
+func FlagRestoreFrom() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "from",
+		ShortName:   "",
+		Description: "Backup directory to restore from (a <release>-<timestamp> directory written by 'block node backup')",
+		Default:     "",
+	}
+}
+
+func FlagDeploymentFile() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "filename",
+		ShortName:   "f",
+		Description: "BlockNodeDeployment file describing the block node to converge the host to",
+		Default:     "",
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"runtime"
+
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// Flag descriptor factories for the `bundle` command tree and the global
+// --bundle flag.
+
+func FlagBundle() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "bundle",
+		ShortName:   "",
+		Description: "Air-gap bundle archive or directory to resolve downloads, charts and images from (default: the bundle `bundle install` installed, if any)",
+		Default:     "",
+	}
+}
+
+func FlagBundleArchive() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "archive",
+		ShortName:   "",
+		Description: "Path of the bundle archive; its checksum file is <archive>.sha256",
+		Default:     "",
+	}
+}
+
+func FlagBundleArch() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "arch",
+		ShortName:   "",
+		Description: "CPU architecture of the target host [" + software.ArchAMD64 + " " + software.ArchARM64 + "]",
+		Default:     runtime.GOARCH,
+	}
+}
+
+func FlagBundleImages() CommaSplitStringsFlagDefinition {
+	return CommaSplitStringsFlagDefinition{
+		Name:        "image",
+		ShortName:   "",
+		Description: "Additional container image to bundle, e.g. the Cilium images (repeatable or comma-separated)",
+		Default:     []string{},
+	}
+}
+
+func FlagBundleRegistry() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "registry",
+		ShortName:   "",
+		Description: "Registry (host:port) reachable from the cluster to push the bundle's images to; CRI-O is configured to pull them from it",
+		Default:     "",
+	}
+}
+
+func FlagBundleRegistryPlainHTTP() FlagDefinition[bool] {
+	return FlagDefinition[bool]{
+		Name:        "registry-plain-http",
+		ShortName:   "",
+		Description: "Use HTTP instead of HTTPS for --registry",
+		Default:     false,
+	}
+}
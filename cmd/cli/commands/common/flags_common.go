@@ -103,6 +103,18 @@ func FlagValuesFile() FlagDefinition[string] {
 	}
 }
 
+// FlagSkipValuesValidation turns off the check of Helm values against the
+// chart's values.schema.json (or, without one, its default keys) that runs
+// before every install and upgrade.
+func FlagSkipValuesValidation() FlagDefinition[bool] {
+	return FlagDefinition[bool]{
+		Name:        "skip-values-validation",
+		ShortName:   "",
+		Description: "Install the chart values without validating them against the chart's schema or default keys",
+		Default:     false,
+	}
+}
+
 func FlagESONamespace() FlagDefinition[string] {
 	return FlagDefinition[string]{
 		Name:        "namespace",
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"time"
+
+	"github.com/hashgraph/solo-weaver/internal/kube/certs"
+	"github.com/hashgraph/solo-weaver/internal/kube/etcd"
+	"github.com/hashgraph/solo-weaver/internal/kube/join"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// Flag descriptor factories for the `kube cluster` command tree.
+
+func FlagCertsWarnWithin() FlagDefinition[time.Duration] {
+	return FlagDefinition[time.Duration]{
+		Name:        "warn-within",
+		ShortName:   "",
+		Description: "Fail when a certificate expires within this window (e.g. 720h)",
+		Default:     certs.DefaultWarnWithin,
+	}
+}
+
+func FlagClusterBackupTarget() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "target",
+		ShortName:   "",
+		Description: "Directory to write the backup under (a local disk or a mounted NFS target); each run creates a cluster-etcd-<timestamp> subdirectory",
+		Default:     etcd.DefaultBackupTarget(models.Paths()),
+	}
+}
+
+func FlagClusterBackupKeep() FlagDefinition[int] {
+	return FlagDefinition[int]{
+		Name:        "keep",
+		ShortName:   "",
+		Description: "Number of cluster backups to retain under --target; older ones are removed (0 keeps all)",
+		Default:     etcd.DefaultKeep,
+	}
+}
+
+func FlagClusterBackupIfOlderThan() FlagDefinition[time.Duration] {
+	return FlagDefinition[time.Duration]{
+		Name:        "if-older-than",
+		ShortName:   "",
+		Description: "Skip the backup while the newest one under --target is younger than this (e.g. 24h)",
+		Default:     0,
+	}
+}
+
+func FlagClusterRestoreFrom() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "from",
+		ShortName:   "",
+		Description: "Backup directory to restore from (a cluster-etcd-<timestamp> directory written by 'kube cluster backup')",
+		Default:     "",
+	}
+}
+
+func FlagClusterUpgradeTo() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "to",
+		ShortName:   "",
+		Description: "Kubernetes version to upgrade the cluster to (e.g. 1.34.1); it must be declared in the infrastructure catalog",
+		Default:     "",
+	}
+}
+
+func FlagJoinTokenTTL() FlagDefinition[time.Duration] {
+	return FlagDefinition[time.Duration]{
+		Name:        "ttl",
+		ShortName:   "",
+		Description: "How long the bootstrap token in the join bundle stays valid (e.g. 2h)",
+		Default:     join.DefaultTokenTTL,
+	}
+}
+
+func FlagJoinTokenFile() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "file",
+		ShortName:   "",
+		Description: "Write the join bundle to this file (mode 0600) instead of printing it",
+		Default:     "",
+	}
+}
+
+func FlagJoinBundle() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "bundle",
+		ShortName:   "",
+		Description: "Join bundle printed by 'kube cluster join-token', or the path of a file holding it",
+		Default:     "",
+	}
+}
+
+func FlagJoinControlPlane() FlagDefinition[bool] {
+	return FlagDefinition[bool]{
+		Name:        "control-plane",
+		ShortName:   "",
+		Description: "Join as an additional control-plane node instead of a worker",
+		Default:     false,
+	}
+}
+
+func FlagKubeClusterName() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "cluster-name",
+		ShortName:   "",
+		Description: "Name kubeadm initialises the cluster with (default " + models.DefaultClusterName + ")",
+		Default:     "",
+	}
+}
+
+func FlagDNSDomain() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "dns-domain",
+		ShortName:   "",
+		Description: "Cluster DNS domain (default " + models.DefaultClusterDNSDomain + ")",
+		Default:     "",
+	}
+}
+
+func FlagServiceSubnet() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "service-subnet",
+		ShortName:   "",
+		Description: "IPv4 CIDR ClusterIP services are assigned from, at most a /12 (default " + models.DefaultClusterServiceSubnet + ")",
+		Default:     "",
+	}
+}
+
+func FlagPodSubnet() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "pod-subnet",
+		ShortName:   "",
+		Description: "IPv4 CIDR pods are assigned from; the host firewall's pod CIDR defaults to it (default " + models.DefaultClusterPodCIDR + ")",
+		Default:     "",
+	}
+}
+
+func FlagNodeCIDRMaskSize() FlagDefinition[int] {
+	return FlagDefinition[int]{
+		Name:        "node-cidr-mask-size",
+		ShortName:   "",
+		Description: "Prefix length of the slice of the pod subnet each node gets (default 24)",
+		Default:     0,
+	}
+}
+
+func FlagLoadBalancerPools() RepeatableStringFlagDefinition {
+	return RepeatableStringFlagDefinition{
+		Name:      "lb-pool",
+		ShortName: "",
+		Description: "MetalLB address pool (format: name=<name>,addresses=<cidr|first-last|machine-ip>[,...][,advertisement=l2|bgp]). " +
+			"Can be specified multiple times; replaces the default private and public pools",
+		Default: nil,
+	}
+}
+
+func FlagBGPPeers() RepeatableStringFlagDefinition {
+	return RepeatableStringFlagDefinition{
+		Name:        "bgp-peer",
+		ShortName:   "",
+		Description: "BGP router the bgp pools are announced to (format: name=<name>,address=<ip>,peerAsn=<asn>,myAsn=<asn>). Can be specified multiple times",
+		Default:     nil,
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import "time"
+
+// Flag descriptor factories for the `software` command tree.
+
+func FlagCacheOlderThan() FlagDefinition[time.Duration] {
+	return FlagDefinition[time.Duration]{
+		Name:        "older-than",
+		ShortName:   "",
+		Description: "Also prune blobs the catalog still references when unused for this long (e.g. 720h)",
+		Default:     0,
+	}
+}
+
+func FlagCacheAll() FlagDefinition[bool] {
+	return FlagDefinition[bool]{
+		Name:        "all",
+		ShortName:   "",
+		Description: "Prune every blob in the download cache",
+		Default:     false,
+	}
+}
+
+func FlagVerifyAll() FlagDefinition[bool] {
+	return FlagDefinition[bool]{
+		Name:        "all",
+		ShortName:   "",
+		Description: "Verify every installed artifact and look for unexpected files in the sandbox bin directory",
+		Default:     false,
+	}
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"time"
+
+	"github.com/hashgraph/solo-weaver/internal/support"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// Flag descriptor factories for the `support` command tree.
+
+func FlagSupportDir() FlagDefinition[string] {
+	return FlagDefinition[string]{
+		Name:        "dir",
+		ShortName:   "",
+		Description: "Directory the support bundle is written to",
+		Default:     models.Paths().DiagnosticsDir,
+	}
+}
+
+func FlagSupportSince() FlagDefinition[time.Duration] {
+	return FlagDefinition[time.Duration]{
+		Name:        "since",
+		ShortName:   "",
+		Description: "Time window: leave out log lines, log files and action history older than this (0 keeps everything)",
+		Default:     support.DefaultSince,
+	}
+}
+
+func FlagSupportMaxSourceMB() FlagDefinition[int] {
+	return FlagDefinition[int]{
+		Name:        "max-source-mb",
+		ShortName:   "",
+		Description: "Size limit of each file in the bundle, in MiB; a larger source keeps its newest part",
+		Default:     support.DefaultMaxSourceBytes >> 20,
+	}
+}
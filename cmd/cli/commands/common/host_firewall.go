@@ -191,7 +191,8 @@ func ResolveHostFirewallConfig(cmd *cobra.Command, args []string, cv *prompt.Cho
 	blockedStr := effectiveCSV(cmd, FlagNameBlockedCIDRs, cfg.BlockedCIDRs)
 	sshStr := effectiveInt(cmd, FlagNameSSHPort, cfg.SSHPort, firewall.DefaultSSHPort)
 	portsStr := effectiveIntCSV(cmd, FlagNameInClusterPorts, cfg.InClusterPorts, firewall.DefaultInClusterPorts)
-	podStr := effectiveStr(cmd, FlagNamePodCIDR, cfg.PodCIDR, models.DefaultClusterPodCIDR)
+	clusterPods, clusterConfigured := clusterPodSubnet()
+	podStr := effectiveStr(cmd, FlagNamePodCIDR, cfg.PodCIDR, clusterPods)
 
 	if prompt.ShouldPrompt(force) {
 		localCV := cv
@@ -232,10 +233,38 @@ func ResolveHostFirewallConfig(cmd *cobra.Command, args []string, cv *prompt.Cho
 	if err := hostCfg.Validate(); err != nil {
 		return errorx.IllegalArgument.Wrap(err, "invalid host firewall configuration")
 	}
+	if err := validateHostPodCIDR(hostCfg.PodCIDR, clusterPods, clusterConfigured); err != nil {
+		return err
+	}
 	config.OverrideHostConfig(hostCfg)
 	return nil
 }
 
+// ResolveHostPodCIDR defaults an enabled host firewall's empty pod CIDR to the
+// cluster pod subnet and checks it against the cluster layout, as
+// ResolveHostFirewallConfig does for the flags. It is for host firewalls that
+// come from elsewhere, e.g. the spec.firewall of a `block node apply` file.
+func ResolveHostPodCIDR(cfg *models.HostConfig) error {
+	if cfg == nil || cfg.Disabled {
+		return nil
+	}
+	clusterPods, clusterConfigured := clusterPodSubnet()
+	if cfg.PodCIDR == "" {
+		cfg.PodCIDR = clusterPods
+	}
+	return validateHostPodCIDR(cfg.PodCIDR, clusterPods, clusterConfigured)
+}
+
+// validateHostPodCIDR rejects a host firewall pod CIDR other than the cluster
+// pod subnet. Only a configured or recorded cluster layout is binding: hosts
+// whose cluster predates it keep whatever pod CIDR they were given.
+func validateHostPodCIDR(podCIDR, clusterPods string, clusterConfigured bool) error {
+	if !clusterConfigured {
+		return nil
+	}
+	return (models.ClusterConfig{PodSubnet: clusterPods}).ValidateHostPodCIDR(podCIDR)
+}
+
 // effectiveCSV returns the effective comma-joined value for a StringSlice flag:
 // the flag when explicitly set, else the config value.
 func effectiveCSV(cmd *cobra.Command, name string, cfgVal []string) string {
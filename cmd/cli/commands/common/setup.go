@@ -42,7 +42,7 @@ func Setup() (*SetupResult, error) {
 	logx.As().Info().Str("state_file", currentState.StateFile).Msg("Current state")
 	logx.As().Debug().Any("currentState", currentState).Msg("Current state details")
 
-	realityChecker, err := reality.NewCheckers(sm)
+	realityChecker, err := reality.NewCheckers(sm, reality.WithBlockNodeRelease(conf.BlockNode.Release))
 	if err != nil {
 		return nil, errorx.IllegalState.Wrap(err, "failed to create reality checker")
 	}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/kube/etcd"
+	"github.com/hashgraph/solo-weaver/internal/workflows"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagClusterBackupTarget      string
+	flagClusterBackupKeep        int
+	flagClusterBackupIfOlderThan time.Duration
+
+	backupCmd = &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the Kubernetes Cluster's etcd",
+		Long: `Back up the control plane of the Kubernetes Cluster to a directory.
+
+This command will:
+1. Take an etcd snapshot with the sandboxed etcd client certificates
+2. Check the snapshot's integrity with etcdutl
+3. Write a manifest and one checksummed tar/zstd archive each for the snapshot,
+   the cluster PKI, the kubeadm config and etcdutl to
+   <target>/cluster-etcd-<timestamp>
+4. Remove the oldest backups beyond --keep
+
+The cluster keeps serving while the backup runs. With --if-older-than the
+backup is skipped while the newest one under --target is younger than the
+given age; the daemon drives scheduled backups this way (cluster_backup in
+daemon.yaml).`,
+		Example: `  # Back up the cluster to /opt/solo/weaver/backup/cluster
+  sudo solo-provisioner kube cluster backup
+
+  # Back up to an NFS mount, keeping the newest 14 backups
+  sudo solo-provisioner kube cluster backup --target=/mnt/nfs/cluster --keep=14`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flagClusterBackupIfOlderThan > 0 {
+				dir, man, err := etcd.Latest(flagClusterBackupTarget)
+				if err != nil {
+					return err
+				}
+				if man != nil && time.Since(man.CreatedAt) < flagClusterBackupIfOlderThan {
+					logx.As().Info().Str("backup", dir).Time("created_at", man.CreatedAt).
+						Msg("Newest cluster backup is recent enough; skipping")
+					return nil
+				}
+			}
+
+			opts := workflows.DefaultWorkflowExecutionOptions()
+			wb := workflows.WithWorkflowExecutionMode(workflows.BackupClusterWorkflow(etcd.BackupOptions{
+				Target: flagClusterBackupTarget,
+				Keep:   flagClusterBackupKeep,
+			}), opts)
+			if err := common.RunWorkflowBuilder(cmd.Context(), wb); err != nil {
+				return err
+			}
+
+			logx.As().Info().Str("target", flagClusterBackupTarget).Msg("Successfully backed up the Kubernetes Cluster")
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagClusterBackupTarget().SetVarP(backupCmd, &flagClusterBackupTarget, false)
+	common.FlagClusterBackupKeep().SetVarP(backupCmd, &flagClusterBackupKeep, false)
+	common.FlagClusterBackupIfOlderThan().SetVarP(backupCmd, &flagClusterBackupIfOlderThan, false)
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/kube/certs"
+	"github.com/hashgraph/solo-weaver/internal/workflows"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCertsWarnWithin time.Duration
+
+	certsCmd = &cobra.Command{
+		Use:   "certs",
+		Short: "Check and renew the Kubernetes Cluster certificates",
+		Long: `Check and renew the certificates kubeadm issued for the cluster.
+
+kubeadm issues the control-plane leaf certificates for one year and the CAs for
+ten. Once a leaf certificate expires the API server stops serving, so renew
+them before they do: 'certs check' reports what is left, 'certs renew' renews
+them. While the daemon runs, GET /status alerts once a certificate is within
+its warning window (cluster_certs.warn_within in daemon.yaml, 30 days by
+default).`,
+		RunE: common.DefaultRunE,
+	}
+
+	certsCheckCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Report the days left on every cluster certificate",
+		Long: `Report the expiry of every certificate in the cluster's PKI (including etcd's)
+and of the client certificates embedded in admin.conf, super-admin.conf,
+controller-manager.conf and scheduler.conf.
+
+The command fails when a certificate expires within --warn-within, so it can
+drive a cron job or a monitoring check.`,
+		Example: `  # Report the cluster certificates
+  sudo solo-provisioner kube cluster certs check
+
+  # Fail when a certificate expires within 60 days
+  sudo solo-provisioner kube cluster certs check --warn-within=1440h`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubernetesDir := filepath.Join(models.Paths().SandboxDir, "etc", "kubernetes")
+			found, err := certs.Inspect(filepath.Join(kubernetesDir, "pki"), certs.Kubeconfigs(kubernetesDir)...)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			if common.OutputIsJSON() {
+				data, err := json.Marshal(found)
+				if err != nil {
+					return errorx.InternalError.Wrap(err, "failed to render the certificate report")
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			} else {
+				certs.WriteReport(cmd.OutOrStdout(), found, now, flagCertsWarnWithin)
+			}
+
+			if expiring := certs.Expiring(found, now, flagCertsWarnWithin); len(expiring) > 0 {
+				return errorx.IllegalState.New("%d cluster certificate(s) expire within %s, the first (%s) in %d day(s)",
+					len(expiring), flagCertsWarnWithin, expiring[0].Name, expiring[0].DaysRemaining(now)).
+					WithProperty(models.ErrPropertyResolution, certsResolution(expiring))
+			}
+			return nil
+		},
+	}
+
+	certsRenewCmd = &cobra.Command{
+		Use:   "renew",
+		Short: "Renew the cluster certificates and restart the control plane",
+		Long: `Renew every control-plane leaf certificate and the kubeconfigs embedding one
+through the sandboxed kubeadm, then:
+1. Restart the control-plane static pods so they load the renewed certificates
+2. Wait for the API server to answer
+3. Refresh the kubeconfig copies for root, the sudo user and the weaver account
+4. Check that the cluster is healthy
+
+The API server is unavailable for the seconds its pod restarts; workloads keep
+running. The CAs are not renewed.`,
+		Example: `  # Renew the cluster certificates
+  sudo solo-provisioner kube cluster certs renew`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := workflows.DefaultWorkflowExecutionOptions()
+			wb := workflows.WithWorkflowExecutionMode(workflows.RenewClusterCertificatesWorkflow(), opts)
+			if err := common.RunWorkflowBuilder(cmd.Context(), wb); err != nil {
+				return err
+			}
+
+			logx.As().Info().Msg("Successfully renewed the Kubernetes Cluster certificates")
+			return nil
+		},
+	}
+)
+
+// certsResolution returns the operator hints for expiring certificates: CAs
+// are outside what `certs renew` renews.
+func certsResolution(expiring []certs.Certificate) []string {
+	hints := []string{"renew the certificates: sudo solo-provisioner kube cluster certs renew"}
+	for _, c := range expiring {
+		if c.CA {
+			hints = append(hints, fmt.Sprintf("CA %s cannot be renewed by 'certs renew'; rotate it manually "+
+				"(https://kubernetes.io/docs/tasks/tls/manual-rotation-of-ca-certificates/)", c.Name))
+		}
+	}
+	return hints
+}
+
+func init() {
+	common.FlagCertsWarnWithin().SetVarP(certsCheckCmd, &flagCertsWarnWithin, false)
+
+	certsCmd.AddCommand(certsCheckCmd, certsRenewCmd)
+}
@@ -28,6 +28,13 @@ func init() {
 
 	clusterCmd.AddCommand(installCmd)
 	clusterCmd.AddCommand(uninstallCmd)
+	clusterCmd.AddCommand(certsCmd)
+	clusterCmd.AddCommand(driftCmd)
+	clusterCmd.AddCommand(backupCmd)
+	clusterCmd.AddCommand(restoreCmd)
+	clusterCmd.AddCommand(upgradeCmd)
+	clusterCmd.AddCommand(joinTokenCmd)
+	clusterCmd.AddCommand(joinCmd)
 }
 
 func GetCmd() *cobra.Command {
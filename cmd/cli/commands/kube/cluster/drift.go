@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Report changes made outside weaver to the objects it manages",
+	Long: `Compare the objects of every templated manifest weaver applied (the MetalLB
+pools, the block node storage and the Alloy ConfigMap) with the live cluster,
+without changing anything.
+
+Each manifest is recorded under /opt/solo/weaver/state/manifests when it is
+applied. An object is reported as:
+  missing  it no longer exists
+  drifted  a field the manifest sets has another value, or another field
+           manager (e.g. kubectl edit) owns it
+  in sync  otherwise
+
+The next apply overwrites drifted fields, or fails naming them when
+kube.applyConflicts is set to fail in the config file. The command fails when
+an object drifted, so it can drive a cron job or a monitoring check.`,
+	Example: `  # Report drift
+  sudo solo-provisioner kube cluster drift
+
+  # Report drift as JSON
+  sudo solo-provisioner kube cluster drift --output=json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kc, err := kube.NewClient()
+		if err != nil {
+			return err
+		}
+		drifts, err := kc.DiffRecordedManifests(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if common.OutputIsJSON() {
+			data, err := json.Marshal(drifts)
+			if err != nil {
+				return errorx.InternalError.Wrap(err, "failed to render the drift report")
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		} else {
+			kube.WriteDriftReport(cmd.OutOrStdout(), drifts)
+		}
+
+		var drifted []string
+		for _, d := range drifts {
+			if d.HasDrift() {
+				drifted = append(drifted, d.Manifest)
+			}
+		}
+		if len(drifted) > 0 {
+			return errorx.IllegalState.New("%d manifest(s) drifted from the cluster: %v", len(drifted), drifted).
+				WithProperty(models.ErrPropertyResolution, []string{
+					"move intended changes into weaver's configuration and re-run the command that applies them",
+					"or re-run it as is to put the recorded values back",
+				})
+		}
+		return nil
+	},
+}
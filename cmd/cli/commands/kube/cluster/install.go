@@ -7,14 +7,40 @@ import (
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
 	"github.com/hashgraph/solo-weaver/internal/rsl"
 	"github.com/hashgraph/solo-weaver/internal/workflows"
+	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/joomcode/errorx"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagClusterName      string
+	flagDNSDomain        string
+	flagServiceSubnet    string
+	flagPodSubnet        string
+	flagNodeCIDRMaskSize int
+	flagLBPools          []string
+	flagBGPPeers         []string
+)
+
 var installCmd = &cobra.Command{
 	Use:   "install",
 	Short: "Install a Kubernetes Cluster",
-	Long:  "Run safety checks, setup a K8s cluster",
+	Long: `Run safety checks, setup a K8s cluster.
+
+The cluster name, DNS domain, service and pod subnets and node CIDR mask size
+are taken from the flags, then the 'cluster' block of the config file, then the
+defaults, and are fixed once the cluster is initialised: later runs reuse the
+values recorded in the state. The MetalLB address pools (--lb-pool, advertised
+over L2 or BGP to the --bgp-peer routers) replace the default private
+192.168.99.0/24 pool and public host-IP pool and can be changed on later runs.`,
+	Example: `  # Install with the default layout
+  sudo solo-provisioner kube cluster install
+
+  # Install with site-specific subnets and a BGP-announced pool
+  sudo solo-provisioner kube cluster install \
+    --service-subnet=172.20.0.0/16 --pod-subnet=172.24.0.0/14 \
+    --lb-pool=name=public,addresses=203.0.113.16/28,advertisement=bgp \
+    --bgp-peer=name=tor1,address=10.10.0.1,peerAsn=64512,myAsn=64513`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Cluster install is workload-agnostic: it validates only the Kubernetes
 		// substrate hardware floor, so no --profile or --node-type is required.
@@ -37,16 +63,26 @@ var installCmd = &cobra.Command{
 		opts := workflows.DefaultWorkflowExecutionOptions()
 		opts.ExecutionMode = execMode
 
-		logx.As().Debug().
-			Strs("args", args).
-			Any("opts", opts).
-			Msg("Installing Kubernetes Cluster")
-
 		skipHardwareChecks, err := common.FlagSkipHardwareChecks().Value(cmd, args)
 		if err != nil {
 			return errorx.IllegalArgument.Wrap(err, "failed to get %s flag", common.FlagSkipHardwareChecks().Name)
 		}
 
+		inputs, err := clusterInputsFromFlags(skipHardwareChecks)
+		if err != nil {
+			return err
+		}
+		network, err := common.ResolveClusterConfig(inputs.Network)
+		if err != nil {
+			return err
+		}
+
+		logx.As().Debug().
+			Strs("args", args).
+			Any("opts", opts).
+			Any("network", network).
+			Msg("Installing Kubernetes Cluster")
+
 		sr, err := common.Setup()
 		if err != nil {
 			return err
@@ -67,7 +103,45 @@ var installCmd = &cobra.Command{
 	},
 }
 
+// clusterInputsFromFlags collects the cluster network flags into
+// ClusterInputs; unset flags leave their field zero so the config file, the
+// recorded layout or the default fills it in.
+func clusterInputsFromFlags(skipHardwareChecks bool) (*models.ClusterInputs, error) {
+	pools, err := common.ParseLoadBalancerPools(flagLBPools)
+	if err != nil {
+		return nil, err
+	}
+	peers, err := common.ParseBGPPeers(flagBGPPeers)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := &models.ClusterInputs{
+		SkipHardwareChecks: skipHardwareChecks,
+		Network: models.ClusterConfig{
+			Name:             flagClusterName,
+			DNSDomain:        flagDNSDomain,
+			ServiceSubnet:    flagServiceSubnet,
+			PodSubnet:        flagPodSubnet,
+			NodeCIDRMaskSize: flagNodeCIDRMaskSize,
+			LoadBalancer:     models.LoadBalancerConfig{Pools: pools, BGPPeers: peers},
+		},
+	}
+	if err := inputs.Validate(); err != nil {
+		return nil, err
+	}
+	return inputs, nil
+}
+
 func init() {
+	common.FlagKubeClusterName().SetVarP(installCmd, &flagClusterName, false)
+	common.FlagDNSDomain().SetVarP(installCmd, &flagDNSDomain, false)
+	common.FlagServiceSubnet().SetVarP(installCmd, &flagServiceSubnet, false)
+	common.FlagPodSubnet().SetVarP(installCmd, &flagPodSubnet, false)
+	common.FlagNodeCIDRMaskSize().SetVarP(installCmd, &flagNodeCIDRMaskSize, false)
+	common.FlagLoadBalancerPools().SetVarP(installCmd, &flagLBPools, false)
+	common.FlagBGPPeers().SetVarP(installCmd, &flagBGPPeers, false)
+
 	// Deprecated: --node-type no longer affects cluster install (substrate-only floor).
 	// Kept hidden for backward compatibility; the value is ignored (see RunE notice).
 	common.FlagNodeType().SetVarPHidden(installCmd, &flagNodeType, false)
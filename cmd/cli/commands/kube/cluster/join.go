@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/kube/join"
+	"github.com/hashgraph/solo-weaver/internal/rsl"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagJoinTokenTTL     time.Duration
+	flagJoinTokenFile    string
+	flagJoinBundle       string
+	flagJoinControlPlane bool
+
+	joinTokenCmd = &cobra.Command{
+		Use:   "join-token",
+		Short: "Mint a bundle another host joins the Kubernetes Cluster with",
+		Long: `Mint a join bundle on a control-plane node of the cluster. The bundle holds:
+- a bootstrap token, valid for --ttl (24h by default)
+- the sha256 hash of the cluster CA's public key, which the new node pins the
+  API server to
+- a certificate key the control-plane certificates are uploaded to the cluster
+  under, so the bundle can also join a control-plane node; kubeadm deletes the
+  uploaded certificates after two hours
+- the control-plane endpoint and the cluster's Kubernetes version
+
+Pass the bundle to 'kube cluster join --bundle' on the new host. It grants
+access to the cluster: treat it as a secret and let it expire rather than
+storing it.`,
+		Example: `  # Print a join bundle
+  sudo solo-provisioner kube cluster join-token
+
+  # Write a bundle valid for two hours to a file
+  sudo solo-provisioner kube cluster join-token --ttl=2h --file=/root/join.bundle`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			recorded, err := state.ReadClusterMembershipFromDisk()
+			if err != nil {
+				return err
+			}
+			if recorded != nil && recorded.Role != state.ClusterRoleControlPlane {
+				return errorx.IllegalState.New("this host is a %s node of %s", recorded.Role, recorded.Endpoint).
+					WithProperty(models.ErrPropertyResolution, "run 'kube cluster join-token' on a control-plane node")
+			}
+
+			b, err := join.NewManager(models.Paths()).Mint(cmd.Context(), flagJoinTokenTTL)
+			if err != nil {
+				return err
+			}
+			encoded, err := b.Encode()
+			if err != nil {
+				return err
+			}
+
+			if flagJoinTokenFile != "" {
+				if err := os.WriteFile(flagJoinTokenFile, []byte(encoded+"\n"), 0o600); err != nil {
+					return errorx.IllegalState.Wrap(err, "failed to write the join bundle to %s", flagJoinTokenFile)
+				}
+				encoded = ""
+			}
+
+			if common.OutputIsJSON() {
+				data, err := json.Marshal(struct {
+					Bundle                  string    `json:"bundle,omitempty"`
+					File                    string    `json:"file,omitempty"`
+					Endpoint                string    `json:"endpoint"`
+					KubernetesVersion       string    `json:"kubernetesVersion"`
+					ExpiresAt               time.Time `json:"expiresAt"`
+					CertificateKeyExpiresAt time.Time `json:"certificateKeyExpiresAt"`
+				}{encoded, flagJoinTokenFile, b.Endpoint, b.KubernetesVersion, b.ExpiresAt, b.CertificateKeyExpiresAt})
+				if err != nil {
+					return errorx.InternalError.Wrap(err, "failed to render the join bundle")
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+
+			out := cmd.OutOrStdout()
+			if encoded != "" {
+				_, _ = fmt.Fprintln(out, encoded)
+			} else {
+				_, _ = fmt.Fprintf(out, "Join bundle written to %s\n", flagJoinTokenFile)
+			}
+			_, _ = fmt.Fprintf(out, "\nCluster:        %s (Kubernetes %s)\n", b.Endpoint, b.KubernetesVersion)
+			_, _ = fmt.Fprintf(out, "Worker join:    valid until %s\n", b.ExpiresAt.Format(time.RFC3339))
+			_, _ = fmt.Fprintf(out, "Control plane:  valid until %s\n", b.CertificateKeyExpiresAt.Format(time.RFC3339))
+			_, _ = fmt.Fprintln(out, "\nOn the new host run: sudo solo-provisioner kube cluster join --bundle=<bundle> [--control-plane]")
+			return nil
+		},
+	}
+
+	joinCmd = &cobra.Command{
+		Use:   "join",
+		Short: "Join this host to an existing Kubernetes Cluster",
+		Long: `Join this host to the cluster a bundle from 'kube cluster join-token' was
+minted on, as a worker or, with --control-plane, as an additional control-plane
+node.
+
+This command will:
+1. Check the bundle has not expired and that the Kubernetes version this
+   release installs can join the cluster
+2. Run the substrate preflight and system setup 'kube cluster install' runs
+3. Install the kubelet, kubectl, CRI-O and kubeadm
+4. Run 'kubeadm join' against the bundle's control-plane endpoint, pinning the
+   cluster CA to the bundle's hash
+5. Wait for the node to turn Ready and record its role and cluster in the state
+
+Cilium, MetalLB and the metrics server already run in the cluster and schedule
+onto the new node themselves. If the join fails with --rollback-on-error,
+'kubeadm reset' undoes it.`,
+		Example: `  # Join as a worker
+  sudo solo-provisioner kube cluster join --bundle=/root/join.bundle
+
+  # Join as an additional control-plane node
+  sudo solo-provisioner kube cluster join --bundle=/root/join.bundle --control-plane`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := join.LoadBundle(flagJoinBundle)
+			if err != nil {
+				return err
+			}
+			// Fail before the host setup rather than at 'kubeadm join'.
+			if err := b.Check(flagJoinControlPlane, time.Now()); err != nil {
+				return err
+			}
+
+			recorded, err := state.ReadClusterMembershipFromDisk()
+			if err != nil {
+				return err
+			}
+			if recorded != nil {
+				return errorx.IllegalState.New("this host is already a %s node of %s", recorded.Role, recorded.Endpoint).
+					WithProperty(models.ErrPropertyResolution,
+						"remove it from its cluster first: sudo solo-provisioner kube cluster uninstall")
+			}
+
+			catalog, err := software.LoadInfrastructureCatalog()
+			if err != nil {
+				return err
+			}
+			kubeadm, err := catalog.GetHostArtifact(software.KubeadmBinaryName)
+			if err != nil {
+				return err
+			}
+			kubelet, err := catalog.GetHostArtifact(software.KubeletBinaryName)
+			if err != nil {
+				return err
+			}
+			if err := join.CheckVersions(b, string(kubeadm.Default), string(kubelet.Default), flagJoinControlPlane); err != nil {
+				return err
+			}
+
+			execMode, err := common.GetExecutionMode(flagContinueOnError, flagStopOnError, flagRollbackOnError)
+			if err != nil {
+				return errorx.Decorate(err, "failed to determine execution mode")
+			}
+			opts := workflows.DefaultWorkflowExecutionOptions()
+			opts.ExecutionMode = execMode
+
+			skipHardwareChecks, err := common.FlagSkipHardwareChecks().Value(cmd, args)
+			if err != nil {
+				return errorx.IllegalArgument.Wrap(err, "failed to get %s flag", common.FlagSkipHardwareChecks().Name)
+			}
+
+			sr, err := common.Setup()
+			if err != nil {
+				return err
+			}
+			mr, ok := sr.Runtime.MachineRuntime.(*rsl.MachineRuntimeResolver)
+			if !ok {
+				return errorx.IllegalArgument.New("expected MachineRuntime to be *rsl.MachineRuntimeResolver but got %T", sr.Runtime.MachineRuntime)
+			}
+
+			logx.As().Debug().
+				Str("endpoint", b.Endpoint).
+				Bool("controlPlane", flagJoinControlPlane).
+				Any("opts", opts).
+				Msg("Joining Kubernetes Cluster")
+
+			wb := workflows.WithWorkflowExecutionMode(
+				workflows.JoinClusterWorkflow(skipHardwareChecks, mr, b, flagJoinControlPlane), opts)
+			if err := common.RunWorkflowBuilder(cmd.Context(), wb); err != nil {
+				return err
+			}
+
+			logx.As().Info().Str("endpoint", b.Endpoint).Msg("Successfully joined the Kubernetes Cluster")
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagJoinTokenTTL().SetVarP(joinTokenCmd, &flagJoinTokenTTL, false)
+	common.FlagJoinTokenFile().SetVarP(joinTokenCmd, &flagJoinTokenFile, false)
+
+	common.FlagJoinBundle().SetVarP(joinCmd, &flagJoinBundle, true)
+	common.FlagJoinControlPlane().SetVarP(joinCmd, &flagJoinControlPlane, false)
+	common.FlagStopOnError().SetVarP(joinCmd, &flagStopOnError, false)
+	common.FlagRollbackOnError().SetVarP(joinCmd, &flagRollbackOnError, false)
+	common.FlagContinueOnError().SetVarP(joinCmd, &flagContinueOnError, false)
+	joinCmd.MarkFlagsMutuallyExclusive(
+		common.FlagStopOnError().Name,
+		common.FlagContinueOnError().Name,
+		common.FlagRollbackOnError().Name,
+	)
+}
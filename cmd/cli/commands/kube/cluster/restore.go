@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/workflows"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagClusterRestoreFrom string
+
+	restoreCmd = &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the Kubernetes Cluster's etcd from a backup",
+		Long: `Restore the control plane of the Kubernetes Cluster from a directory written by
+'kube cluster backup'.
+
+This command will:
+1. Verify every archive of the backup against its manifest checksum
+2. Restore the etcd snapshot into a new data directory with the backup's
+   etcdutl, as the member the etcd static pod manifest declares
+3. Stop the kubelet and the control-plane pods
+4. Swap the restored data directory in, keeping the replaced one as
+   <data-dir>.pre-restore-<timestamp>
+5. Start the kubelet, wait for the API server and check the cluster's health
+
+The PKI and kubeadm config are restored only when they are missing from the
+host. Cluster state written since the backup was taken is lost; workloads are
+reconciled to the restored state.
+
+WARNING: The API server is unavailable while the restore runs.`,
+		Example: `  # Restore the cluster from a backup
+  sudo solo-provisioner kube cluster restore --from=/opt/solo/weaver/backup/cluster/cluster-etcd-20261001T120000Z`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := workflows.DefaultWorkflowExecutionOptions()
+			wb := workflows.WithWorkflowExecutionMode(workflows.RestoreClusterWorkflow(flagClusterRestoreFrom), opts)
+			if err := common.RunWorkflowBuilder(cmd.Context(), wb); err != nil {
+				return err
+			}
+
+			logx.As().Info().Str("from", flagClusterRestoreFrom).Msg("Successfully restored the Kubernetes Cluster")
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagClusterRestoreFrom().SetVarP(restoreCmd, &flagClusterRestoreFrom, true)
+}
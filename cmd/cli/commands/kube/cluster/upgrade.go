@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/internal/kube/etcd"
+	"github.com/hashgraph/solo-weaver/internal/kube/upgrade"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagClusterUpgradeTo string
+
+	upgradeCmd = &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the Kubernetes Cluster to a newer Kubernetes version",
+		Long: `Upgrade the Kubernetes Cluster to a newer Kubernetes version declared in the
+infrastructure catalog.
+
+This command will:
+1. Check the upgrade against the Kubernetes version skew policy: one minor
+   version at a time, no downgrades, and a kubelet within the supported skew
+2. Check that kubeadm, the kubelet and kubectl declare the version in the
+   catalog, and pick the newest CRI-O release of the same minor
+3. Check that the running Cilium supports the version
+4. Back up the cluster's etcd to /opt/solo/weaver/backup/cluster
+5. Stage the new kubeadm through its installer with checksum verification and
+   run 'kubeadm upgrade plan' and 'kubeadm upgrade apply' with it
+6. Upgrade the kubelet, then CRI-O, restarting each service on the new
+   binaries, then kubectl
+7. Re-check Cilium against the upgraded cluster and check the cluster's health
+
+Each component's new version is recorded in the state as soon as it is
+installed. If a step fails, every component already replaced is reinstalled at
+its previous version. The control plane itself is not rolled back once
+'kubeadm upgrade apply' has succeeded; restore the pre-upgrade backup with
+'kube cluster restore' if it has to be.
+
+WARNING: Workloads on this node lose their container runtime briefly while
+CRI-O restarts.`,
+		Example: `  # Upgrade the cluster to Kubernetes 1.34.1
+  sudo solo-provisioner kube cluster upgrade --to=1.34.1`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog, err := software.LoadInfrastructureCatalog()
+			if err != nil {
+				return err
+			}
+
+			info, err := kube.RetrieveClusterInfo()
+			if err != nil {
+				return err
+			}
+			kc, err := kube.NewClient()
+			if err != nil {
+				return err
+			}
+			cilium, err := upgrade.LiveCiliumVersion(cmd.Context(), kc)
+			if err != nil {
+				return err
+			}
+
+			cur := upgrade.Current{
+				ControlPlane: info.ServerVersion.GitVersion,
+				Software:     map[string]string{},
+				Cilium:       cilium,
+			}
+			for _, key := range []string{software.KubeadmBinaryName, software.KubeletBinaryName, software.CrioBinaryName, software.KubectlBinaryName} {
+				if cur.Software[key], err = state.ReadSoftwareVersionFromDisk(key); err != nil {
+					return err
+				}
+			}
+
+			plan, err := upgrade.NewPlan(catalog, cur, flagClusterUpgradeTo)
+			if err != nil {
+				return err
+			}
+			logx.As().Info().Str("from", plan.From).Str("to", plan.To).Any("components", plan.Components).
+				Msg("Upgrading the Kubernetes Cluster")
+
+			// Roll back on failure so a failed upgrade leaves the previous binaries in place.
+			opts := workflows.DefaultWorkflowExecutionOptions()
+			opts.ExecutionMode = automa.RollbackOnError
+			wb := workflows.WithWorkflowExecutionMode(workflows.UpgradeClusterWorkflow(plan, etcd.BackupOptions{
+				Target: etcd.DefaultBackupTarget(models.Paths()),
+				Keep:   etcd.DefaultKeep,
+			}), opts)
+			if err := common.RunWorkflowBuilder(cmd.Context(), wb); err != nil {
+				return err
+			}
+
+			logx.As().Info().Str("version", plan.To).Msg("Successfully upgraded the Kubernetes Cluster")
+			return nil
+		},
+	}
+)
+
+func init() {
+	common.FlagClusterUpgradeTo().SetVarP(upgradeCmd, &flagClusterUpgradeTo, true)
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package exporter wires `solo-provisioner network exporter` to the
+// internal/network/exporter package: a long-running Prometheus endpoint for the
+// traffic shaper's tc class and nft policy counters.
+package exporter
+
+import (
+	"os/signal"
+	"syscall"
+
+	exp "github.com/hashgraph/solo-weaver/internal/network/exporter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagListen      string
+	flagEgressIface string
+)
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Serve tc class and nft policy counters as Prometheus metrics",
+	Long: "Serve the traffic shaper's kernel counters on " + exp.MetricsPath + " in the Prometheus text format " +
+		"until interrupted. Every scrape re-reads the kernel: per-class bytes, packets, drops, overlimits " +
+		"and backlog from `tc -s class show` (egress classes on the egress NIC, ingress classes on each pod " +
+		"veth), and per-policy packet/byte counters from the named nft counters every stamp and deny rule in " +
+		"`inet weaver-workload-policy` feeds.\n\n" +
+		"Read-only: it never mutates tc, nft, or the shape and policy registries. Reading nft counters " +
+		"requires root. By default it listens on the node IP, port " + exp.DefaultListenPort + ", not on every " +
+		"interface. Block node install runs it as " + exp.ExporterService + " wherever traffic shaping is " +
+		"enabled; enable the Alloy traffic-shaper module (`alloy cluster install --monitor-traffic-shaper`) " +
+		"to scrape it on every node.",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		// Ctrl-C (and SIGTERM from systemd) shuts the server down gracefully;
+		// Serve returns nil on ctx cancellation.
+		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		addr := flagListen
+		if addr == "" {
+			addr = exp.DefaultListenAddr()
+		}
+		return exp.NewWithConfig(exp.Config{EgressIface: flagEgressIface}).Serve(ctx, addr)
+	},
+}
+
+func init() {
+	exporterCmd.Flags().StringVar(&flagListen, "listen", "",
+		"Address to serve metrics on (host:port; default: the node IP, port "+exp.DefaultListenPort+")")
+	exporterCmd.Flags().StringVar(&flagEgressIface, "egress-iface", "", "Egress NIC to sample (default: detected from the default route)")
+}
+
+// GetCmd returns the `network exporter` command.
+func GetCmd() *cobra.Command {
+	return exporterCmd
+}
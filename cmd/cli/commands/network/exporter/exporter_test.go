@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"testing"
+
+	exp "github.com/hashgraph/solo-weaver/internal/network/exporter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCmd_ReturnsExporterCmd(t *testing.T) {
+	require.Equal(t, "exporter", GetCmd().Use)
+}
+
+func TestExporterCmd_FlagsRegistered(t *testing.T) {
+	for _, flag := range []string{"listen", "egress-iface"} {
+		require.NotNil(t, exporterCmd.Flags().Lookup(flag), "exporter: missing --%s", flag)
+	}
+	require.Empty(t, exporterCmd.Flags().Lookup("listen").DefValue, "the node IP is resolved when the command runs")
+	require.Contains(t, exporterCmd.Flags().Lookup("listen").Usage, exp.DefaultListenPort)
+}
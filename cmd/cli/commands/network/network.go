@@ -4,6 +4,7 @@ package network
 
 import (
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/network/exporter"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/network/firewall"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/network/policy"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/network/shape"
@@ -24,6 +25,7 @@ func init() {
 	networkCmd.AddCommand(firewall.GetCmd())
 	networkCmd.AddCommand(policy.GetCmd())
 	networkCmd.AddCommand(shape.GetCmd())
+	networkCmd.AddCommand(exporter.GetCmd())
 }
 
 // GetCmd returns the root of the `network` command group.
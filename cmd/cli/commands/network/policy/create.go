@@ -160,7 +160,7 @@ func readCIDRsFile(path string) ([]string, error) {
 
 func init() {
 	createCmd.Flags().StringVar(&flagName, "name", "", "Policy name; also the nft set name `@<name>` (required)")
-	createCmd.Flags().StringVar(&flagStamp, "stamp", "", "HTB class to classify matching packets into; also fixes the policy's direction (mutually exclusive with --deny)")
+	createCmd.Flags().StringVar(&flagStamp, "stamp", "", "HTB class (built-in or custom) to classify matching packets into; also fixes the policy's direction (mutually exclusive with --deny)")
 	createCmd.Flags().BoolVar(&flagDeny, "deny", false, "Drop the --cidrs (both directions), the --ports (request leg), or their intersection (mutually exclusive with --stamp)")
 	createCmd.Flags().StringVar(&flagReplyStamp, "reply-stamp", "", "Reply class for an asymmetric conntrack reply (requires --stamp to resolve to an egress class; --reply-stamp must resolve to the mirror ingress class)")
 	createCmd.Flags().StringVar(&flagFromEntity, "from-entity", "", "Match any source/dest with no IP-set clause (only value: world; mutually exclusive with --cidrs)")
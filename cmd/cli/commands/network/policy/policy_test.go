@@ -83,6 +83,9 @@ func (f *fakeRunner) SetElements(_ context.Context, set string, elems []string)
 func (f *fakeRunner) ListElements(_ context.Context, set string) ([]string, error) {
 	return append([]string(nil), f.elements[set]...), nil
 }
+func (f *fakeRunner) ListCounters(context.Context) (map[string]pol.CounterValue, error) {
+	return nil, nil
+}
 func (f *fakeRunner) List(context.Context) (string, error) { return f.applied, nil }
 func (f *fakeRunner) Delete(context.Context) error {
 	f.applied = ""
@@ -191,7 +194,7 @@ func resetFlags() {
 func TestCreateCmd_StampIngress(t *testing.T) {
 	doc, err := runCreate(t, "--name", "bn-publisher", "--stamp", "publisher", "--ports", "40840", "--cidrs", "10.1.0.1/32")
 	require.NoError(t, err)
-	require.Contains(t, doc, "ip daddr 10.4.0.0/24 ip saddr @bn-publisher tcp dport @bn-publisher_ports meta priority set 0x10010 accept")
+	require.Contains(t, doc, "ip daddr 10.4.0.0/24 ip saddr @bn-publisher tcp dport @bn-publisher_ports counter name \"bn-publisher_cnt\" meta priority set 0x10010 accept")
 	// Membership is persisted as set elements so it survives a reboot, in the
 	// collapsed form nft prints for a /32.
 	require.Contains(t, doc, "set bn-publisher { type ipv4_addr; flags interval; elements = { 10.1.0.1 }; }")
@@ -200,8 +203,8 @@ func TestCreateCmd_StampIngress(t *testing.T) {
 func TestCreateCmd_Deny(t *testing.T) {
 	doc, err := runCreate(t, "--name", "bn-restricted", "--deny", "--cidrs", "10.99.0.0/16")
 	require.NoError(t, err)
-	require.Contains(t, doc, "ip saddr @bn-restricted drop")
-	require.Contains(t, doc, "ip daddr @bn-restricted drop")
+	require.Contains(t, doc, "ip saddr @bn-restricted counter name \"bn-restricted_cnt\" drop")
+	require.Contains(t, doc, "ip daddr @bn-restricted counter name \"bn-restricted_cnt\" drop")
 }
 
 func TestCreateCmd_DenySkipsPodCIDRDetection(t *testing.T) {
@@ -215,7 +218,7 @@ func TestCreateCmd_DenySkipsPodCIDRDetection(t *testing.T) {
 	doc, err := env.runCreate(t, "--name", "bn-restricted", "--deny", "--cidrs", "10.99.0.0/16")
 	require.NoError(t, err, "--deny must not need pod-CIDR detection at all")
 	require.False(t, called, "detectPodCIDR must not be invoked for --deny")
-	require.Contains(t, doc, "ip saddr @bn-restricted drop")
+	require.Contains(t, doc, "ip saddr @bn-restricted counter name \"bn-restricted_cnt\" drop")
 }
 
 func TestCreateCmd_StampAndDenyMutuallyExclusive(t *testing.T) {
@@ -18,9 +18,16 @@ var createCmd = &cobra.Command{
 	Long: "Create a tc HTB shape configuration. Exactly one of --device or --class must be supplied:\n\n" +
 		"  --device <dir>  Configure the root qdisc and trunk class for \"ingress\" ($VETH) or\n" +
 		"                  \"egress\" ($NIC). Required: --rate, --default. Must run before any\n" +
-		"                  --class form for the same device (tc parent-before-child requirement).\n\n" +
-		"  --class <name>  Add an HTB leaf class + fq_codel qdisc. The device is implied by the\n" +
+		"                  --class form for the same device (tc parent-before-child requirement).\n" +
+		"                  Optional: --qdisc htb|cake|fq selects the root qdisc (htb default);\n" +
+		"                  --leaf fq_codel|cake|fq selects the per-class leaf under htb.\n\n" +
+		"  --class <name>  Add an HTB leaf class + leaf qdisc. The device is implied by the\n" +
 		"                  class name. Required: --rate. Optional: --ceil, --prio.\n\n" +
+		"  --class <name> --direction <dir>\n" +
+		"                  Register <name> as a custom class for \"ingress\" or \"egress\", with a\n" +
+		"                  classid minor auto-allocated from the reserved custom range. --rate,\n" +
+		"                  --ceil and --prio set its default bandwidth. Custom classes can be\n" +
+		"                  targeted by `network policy create --stamp` like the built-in ones.\n\n" +
 		"create-if-missing: an existing entry is left untouched unless --force is passed.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if flagClass != "" && flagDevice != "" {
@@ -56,6 +63,8 @@ func runCreateDevice(cmd *cobra.Command, m *shp.Manager, force bool) error {
 		Dir:          flagDevice,
 		Rate:         flagRate,
 		DefaultClass: flagDefault,
+		Qdisc:        flagQdisc,
+		Leaf:         flagLeaf,
 	}
 	changed, err := m.CreateDevice(cmd.Context(), dev, force)
 	if err != nil {
@@ -63,7 +72,8 @@ func runCreateDevice(cmd *cobra.Command, m *shp.Manager, force bool) error {
 	}
 	if changed {
 		logx.As().Info().Str("device", dev.Dir).Str("rate", dev.Rate).
-			Str("default", dev.DefaultClass).Msg("network shape device configured")
+			Str("default", dev.DefaultClass).Str("qdisc", dev.Qdisc).Str("leaf", dev.Leaf).
+			Msg("network shape device configured")
 	}
 	return nil
 }
@@ -76,6 +86,10 @@ func runCreateClass(cmd *cobra.Command, m *shp.Manager, force bool) error {
 		return errorx.IllegalArgument.New("--rate auto is only valid for --device; class rates must be explicit bandwidth values (e.g. 400mbit)")
 	}
 
+	if cmd.Flags().Changed("direction") {
+		return runCreateCustomClass(cmd, m, force)
+	}
+
 	cls := &shp.ClassConfig{
 		Name: flagClass,
 		Rate: flagRate,
@@ -101,11 +115,37 @@ func runCreateClass(cmd *cobra.Command, m *shp.Manager, force bool) error {
 	return nil
 }
 
+func runCreateCustomClass(cmd *cobra.Command, m *shp.Manager, force bool) error {
+	def := &shp.CustomClass{
+		Name: flagClass,
+		Dir:  flagDirection,
+		Rate: flagRate,
+		Prio: flagPrio,
+	}
+	if cmd.Flags().Changed("ceil") {
+		def.Ceil = flagCeil
+	}
+
+	changed, err := m.RegisterCustomClass(cmd.Context(), def, force)
+	if err != nil {
+		return err
+	}
+	if changed {
+		logx.As().Info().Str("class", def.Name).Str("direction", def.Dir).
+			Str("classid", "1:"+def.Minor).Str("rate", def.Rate).Int("prio", def.Prio).
+			Msg("network shape custom class registered")
+	}
+	return nil
+}
+
 func init() {
-	createCmd.Flags().StringVar(&flagClass, "class", "", "HTB class name to configure (publisher, backfill-response, reserve-ingress, partner, public, reserve-egress)")
+	createCmd.Flags().StringVar(&flagClass, "class", "", "HTB class name to configure (publisher, backfill-response, reserve-ingress, partner, public, reserve-egress, or a custom class)")
 	createCmd.Flags().StringVar(&flagDevice, "device", "", "Traffic direction to configure root for: ingress ($VETH) or egress ($NIC)")
 	createCmd.Flags().StringVar(&flagRate, "rate", "", `Bandwidth rate (e.g. 100mbit, 1gbit). For --device only: "auto" detects the link speed from sysfs at create time and stores it as an explicit rate (falls back to 1gbit when unreadable).`)
 	createCmd.Flags().StringVar(&flagCeil, "ceil", "", "Burst ceiling rate (≥ --rate; defaults to --rate if omitted)")
 	createCmd.Flags().IntVar(&flagPrio, "prio", 0, "HTB scheduling priority [0,7] (0 = highest; default 0)")
 	createCmd.Flags().StringVar(&flagDefault, "default", "", "Default class name for unmatched traffic (--device form only)")
+	createCmd.Flags().StringVar(&flagQdisc, "qdisc", "", `Root qdisc backend (--device form only): "htb" (default), "cake" (classes mapped onto diffserv4 tins) or "fq" (flat, per-flow maxrate pacing)`)
+	createCmd.Flags().StringVar(&flagLeaf, "leaf", "", `Per-class leaf qdisc under the htb root (--device form only): "fq_codel" (default), "cake" or "fq"`)
+	createCmd.Flags().StringVar(&flagDirection, "direction", "", "Register --class as a custom class for this direction: ingress ($VETH) or egress ($NIC)")
 }
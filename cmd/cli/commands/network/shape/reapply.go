@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package shape
+
+import (
+	"fmt"
+
+	"github.com/automa-saga/logx"
+	"github.com/spf13/cobra"
+)
+
+// reapply-only flag binding targets.
+var (
+	flagReapplyIface string
+	flagReapplyForce bool
+)
+
+var reapplyCmd = &cobra.Command{
+	Use:   "reapply",
+	Short: "Re-attach the egress HTB hierarchy after interface churn",
+	Long: "Re-attach the $EGRESS HTB hierarchy to the egress interface recorded in " +
+		"solo-provisioner-bandwidth-shaper.sh. An egress device created with rate \"auto\" first " +
+		"has its link speed re-read, and the trunk and every egress class are rescaled proportionally " +
+		"when it changed. The boot script is then re-run only when the rate changed or the live root " +
+		"hierarchy is missing, so repeated invocations are cheap and never churn an intact qdisc.\n\n" +
+		"The traffic-shaper daemon runs this whenever the egress interface appears, is recreated " +
+		"(netplan bond, VLAN or bridge, `netplan apply`, driver reload) or renegotiates its link " +
+		"speed. Run it by hand after fixing an interface the boot-replay unit could not shape. " +
+		"--force re-runs the script even when the hierarchy looks intact.",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		res, err := newManager().ReapplyEgress(cmd.Context(), flagReapplyIface, flagReapplyForce)
+		if err != nil {
+			return err
+		}
+		logx.As().Info().
+			Str("nic", res.NIC).
+			Str("rate", res.Rate).
+			Bool("applied", res.Applied).
+			Str("reason", res.Reason).
+			Msg("network shape egress reapply")
+		if res.Applied {
+			fmt.Fprintf(cmd.OutOrStdout(), "egress hierarchy re-applied on %s (%s)\n", res.NIC, res.Reason)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "egress hierarchy unchanged: %s\n", res.Reason)
+		}
+		return nil
+	},
+}
+
+func init() {
+	reapplyCmd.Flags().StringVar(&flagReapplyIface, "iface", "", "Egress interface to re-apply to (default: the one recorded in the boot script)")
+	reapplyCmd.Flags().BoolVar(&flagReapplyForce, "force", false, "Re-run the boot script even when the live hierarchy is intact")
+}
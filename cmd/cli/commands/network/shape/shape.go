@@ -9,8 +9,12 @@
 // Two forms of `create` are supported, mutually exclusive via --class/--device:
 //   - create --device <dir> --rate <speed> --default <class>: configure the
 //     root HTB qdisc and trunk class for "ingress" ($VETH) or "egress" ($NIC).
+//     --qdisc cake|fq selects a classless root instead; --leaf selects the
+//     per-class leaf qdisc under HTB.
 //   - create --class <name> --rate <speed> [--ceil <speed>] [--prio <n>]:
 //     add an HTB leaf class; device direction is derived from the class name.
+//     Adding --direction <dir> registers <name> as a custom class instead, with
+//     a classid minor auto-allocated from the reserved custom range.
 package shape
 
 import (
@@ -21,12 +25,17 @@ import (
 
 // Shared flag binding targets. Only one verb runs per invocation.
 var (
-	flagClass   string
-	flagDevice  string
-	flagRate    string
-	flagCeil    string
-	flagPrio    int
-	flagDefault string
+	flagClass     string
+	flagDevice    string
+	flagRate      string
+	flagCeil      string
+	flagPrio      int
+	flagDefault   string
+	flagDirection string
+	flagCustom    bool
+	flagQdisc     string
+	flagLeaf      string
+	flagShowIface string
 )
 
 var shapeCmd = &cobra.Command{
@@ -44,6 +53,7 @@ func init() {
 	shapeCmd.AddCommand(setCmd)
 	shapeCmd.AddCommand(showCmd)
 	shapeCmd.AddCommand(watchCmd)
+	shapeCmd.AddCommand(reapplyCmd)
 	shapeCmd.AddCommand(deleteCmd)
 }
 
@@ -22,10 +22,17 @@ func resetFlags() {
 	flagCeil = ""
 	flagPrio = 0
 	flagDefault = ""
+	flagDirection = ""
+	flagCustom = false
+	flagQdisc = ""
+	flagLeaf = ""
+	flagShowIface = ""
 	flagWatchIface = ""
 	flagWatchInterval = 2 * time.Second // watch's registered default
 	flagWatchCount = 0
-	for _, cmd := range []*cobra.Command{createCmd, setCmd, showCmd, watchCmd, deleteCmd} {
+	flagReapplyIface = ""
+	flagReapplyForce = false
+	for _, cmd := range []*cobra.Command{createCmd, setCmd, showCmd, watchCmd, reapplyCmd, deleteCmd} {
 		cmd.Flags().VisitAll(func(f *pflag.Flag) { f.Changed = false })
 	}
 }
@@ -37,7 +44,7 @@ func TestShapeCmd_HasExpectedSubcommands(t *testing.T) {
 	for _, c := range shapeCmd.Commands() {
 		subs[c.Name()] = true
 	}
-	for _, want := range []string{"create", "set", "show", "watch", "delete"} {
+	for _, want := range []string{"create", "set", "show", "watch", "reapply", "delete"} {
 		require.True(t, subs[want], "missing subcommand %q", want)
 	}
 }
@@ -47,7 +54,7 @@ func TestGetCmd_ReturnsShapeCmd(t *testing.T) {
 }
 
 func TestCreateCmd_FlagsRegistered(t *testing.T) {
-	for _, flag := range []string{"class", "device", "rate", "ceil", "prio", "default"} {
+	for _, flag := range []string{"class", "device", "rate", "ceil", "prio", "default", "direction", "qdisc", "leaf"} {
 		require.NotNil(t, createCmd.Flags().Lookup(flag), "create: missing --%s", flag)
 	}
 }
@@ -59,11 +66,17 @@ func TestSetCmd_FlagsRegistered(t *testing.T) {
 }
 
 func TestShowCmd_FlagsRegistered(t *testing.T) {
-	for _, flag := range []string{"class", "device"} {
+	for _, flag := range []string{"class", "device", "custom", "iface"} {
 		require.NotNil(t, showCmd.Flags().Lookup(flag), "show: missing --%s", flag)
 	}
 }
 
+func TestReapplyCmd_FlagsRegistered(t *testing.T) {
+	for _, flag := range []string{"iface", "force"} {
+		require.NotNil(t, reapplyCmd.Flags().Lookup(flag), "reapply: missing --%s", flag)
+	}
+}
+
 func TestDeleteCmd_FlagsRegistered(t *testing.T) {
 	for _, flag := range []string{"class", "device"} {
 		require.NotNil(t, deleteCmd.Flags().Lookup(flag), "delete: missing --%s", flag)
@@ -13,21 +13,34 @@ var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show shape configuration (device or class)",
 	Long: "Show the stored shape configuration. Without flags, shows all configured devices and " +
-		"classes. With --device or --class, shows only the named entry.",
+		"classes. With --device or --class, shows only the named entry. --custom lists the " +
+		"registered custom class definitions. --device with --iface appends the live counters of the " +
+		"device's qdisc backend on that interface: per class under htb, per tin under cake, and the " +
+		"root qdisc under fq.",
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		if flagClass != "" && flagDevice != "" {
 			return errorx.IllegalArgument.New("--class and --device are mutually exclusive")
 		}
+		if flagShowIface != "" && flagDevice == "" {
+			return errorx.IllegalArgument.New("--iface requires --device")
+		}
 
 		m := newManager()
 		var out string
 		var err error
 
 		switch {
+		case flagCustom:
+			out, err = m.ListCustomClasses()
 		case flagClass != "":
 			out, err = m.ShowClass(flagClass)
 		case flagDevice != "":
 			out, err = m.ShowDevice(flagDevice)
+			if err == nil && flagShowIface != "" {
+				var live string
+				live, err = m.ShowDeviceStats(cmd.Context(), flagDevice, flagShowIface)
+				out += live
+			}
 		default:
 			out, err = m.ShowAll()
 		}
@@ -42,4 +55,6 @@ var showCmd = &cobra.Command{
 func init() {
 	showCmd.Flags().StringVar(&flagClass, "class", "", "Show configuration for the named class")
 	showCmd.Flags().StringVar(&flagDevice, "device", "", "Show configuration for the named device (ingress or egress)")
+	showCmd.Flags().StringVar(&flagShowIface, "iface", "", "With --device: also show the live qdisc counters on this interface")
+	showCmd.Flags().BoolVar(&flagCustom, "custom", false, "List the registered custom class definitions")
 }
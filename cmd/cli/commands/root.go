@@ -13,18 +13,23 @@ import (
 	"github.com/automa-saga/logx"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/alloy"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/block"
+	bundlecmd "github.com/hashgraph/solo-weaver/cmd/cli/commands/bundle"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/consensus"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/daemon"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/eso"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/kube"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/network"
+	softwarecmd "github.com/hashgraph/solo-weaver/cmd/cli/commands/software"
+	supportcmd "github.com/hashgraph/solo-weaver/cmd/cli/commands/support"
 	"github.com/hashgraph/solo-weaver/cmd/cli/commands/teleport"
+	"github.com/hashgraph/solo-weaver/internal/airgap"
 	"github.com/hashgraph/solo-weaver/internal/blocknode"
 	"github.com/hashgraph/solo-weaver/internal/doctor"
 	"github.com/hashgraph/solo-weaver/internal/migration"
 	"github.com/hashgraph/solo-weaver/internal/proxy"
 	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/support"
 	"github.com/hashgraph/solo-weaver/internal/ui"
 	"github.com/hashgraph/solo-weaver/internal/workflows"
 	"github.com/hashgraph/solo-weaver/pkg/config"
@@ -47,6 +52,7 @@ var (
 	flagSkipHardwareChecks bool
 	flagForce              bool
 	flagLogLevel           string
+	flagBundle             string
 
 	rootCmd = &cobra.Command{
 		Use:   "solo-provisioner",
@@ -78,6 +84,7 @@ func init() {
 	// support '--version', '-v' to show version information
 	common.FlagVersion().SetVarP(rootCmd, &flagVersion, false)
 	common.FlagOutputFormat().SetVarP(rootCmd, &common.OutputFormat, false)
+	common.FlagBundle().SetVarP(rootCmd, &flagBundle, false)
 
 	// Verbose output flag — -V enables expanded step-by-step output
 	common.FlagVerbose().SetVarP(rootCmd, &ui.VerboseLevel)
@@ -112,6 +119,9 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(tuiDemoCmd)
 	rootCmd.AddCommand(daemon.GetCmd())
+	rootCmd.AddCommand(supportcmd.GetCmd())
+	rootCmd.AddCommand(bundlecmd.GetCmd())
+	rootCmd.AddCommand(softwarecmd.GetCmd())
 
 	if common.DetectShortNameCollisions(rootCmd) {
 		logx.As().Warn().Msg("flag short name collisions detected among commands; consider using unique short names " +
@@ -119,7 +129,7 @@ func init() {
 	}
 
 	RegisterMigrations()
-
+	RegisterSupportCollectors()
 }
 
 // RegisterMigrations is the single authoritative place where every migration is registered.
@@ -143,6 +153,20 @@ func RegisterMigrations() {
 	migration.Register(migration.ScopeBlockNode, blocknode.NewVerificationStorageMigration())
 	migration.Register(migration.ScopeBlockNode, blocknode.NewPluginsStorageMigration())
 	migration.Register(migration.ScopeBlockNode, blocknode.NewApplicationStateMigration())
+	migration.Register(migration.ScopeBlockNode, blocknode.NewVerificationRetirementMigration())
+}
+
+// RegisterSupportCollectors is the single place where every support bundle
+// collector is registered, in the order their directories appear in the
+// bundle's index.
+func RegisterSupportCollectors() {
+	support.Register(blocknode.NewSupportCollector())
+	support.Register(support.NewStateCollector())
+	support.Register(support.NewLogsCollector())
+	support.Register(support.NewConfigCollector())
+	support.Register(support.NewNetworkCollector())
+	support.Register(support.NewDaemonCollector())
+	support.Register(support.NewHostCollector())
 }
 
 // Execute executes the root command.
@@ -282,6 +306,7 @@ func initConfig(ctx context.Context) {
 	// Activate proxy after logging is initialized so the activation log
 	// respects TUI suppression and goes to the log file instead of stdout.
 	activateProxy(ctx)
+	activateBundle(ctx)
 }
 
 // isPrivilegeExemptInvocation reports whether args represents an invocation
@@ -335,6 +360,14 @@ func isCheckTrueFlag(arg string) bool {
 	return err == nil && b
 }
 
+// activateBundle makes downloads and chart pulls resolve from the air-gap
+// bundle named by --bundle, or else from the one `bundle install` installed.
+func activateBundle(ctx context.Context) {
+	if _, err := airgap.Activate(flagBundle); err != nil {
+		doctor.CheckErr(ctx, err)
+	}
+}
+
 func activateProxy(ctx context.Context) {
 	proxyCfg := config.Get().Proxy
 	if !proxyCfg.Enabled {
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagOlderThan time.Duration
+	flagAll       bool
+
+	cacheListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the blobs in the download cache",
+		Long:  "List the blobs in the download cache, most recently used first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache := software.DefaultDownloadCache()
+			entries, err := cache.List()
+			if err != nil {
+				return err
+			}
+			return renderEntries(cmd.OutOrStdout(), entries,
+				fmt.Sprintf("%d blob(s) in %s", len(entries), cache.Dir()))
+		},
+	}
+
+	cachePruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "Remove blobs from the download cache",
+		Long: `Remove blobs from the download cache.
+
+By default, only blobs whose checksum no longer appears in the software catalog
+are removed. --older-than also removes blobs the catalog still references that
+have not been used for that long; --all empties the cache.`,
+		Example: `  # Remove blobs no catalog version references any more
+  sudo solo-provisioner software cache prune
+
+  # Also remove blobs unused for 30 days
+  sudo solo-provisioner software cache prune --older-than=720h`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			catalog, err := software.LoadInfrastructureCatalog()
+			if err != nil {
+				return err
+			}
+
+			removed, err := software.DefaultDownloadCache().Prune(software.PruneOptions{
+				Referenced: catalog.ReferencedChecksums(),
+				OlderThan:  flagOlderThan,
+				All:        flagAll,
+			})
+			if err != nil {
+				return err
+			}
+
+			var freed int64
+			for _, e := range removed {
+				freed += e.Size
+			}
+			return renderEntries(cmd.OutOrStdout(), removed,
+				fmt.Sprintf("Pruned %d blob(s), %s freed", len(removed), humanBytes(freed)))
+		},
+	}
+)
+
+func init() {
+	common.FlagCacheOlderThan().SetVarP(cachePruneCmd, &flagOlderThan, false)
+	common.FlagCacheAll().SetVarP(cachePruneCmd, &flagAll, false)
+}
+
+// renderEntries writes entries as JSON or, followed by summary, as text.
+func renderEntries(out io.Writer, entries []software.CacheEntry, summary string) error {
+	if common.OutputIsJSON() {
+		if entries == nil {
+			entries = []software.CacheEntry{}
+		}
+		data, err := json.Marshal(entries)
+		if err != nil {
+			return errorx.InternalError.Wrap(err, "failed to render the download cache")
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(out, "  %s:%s  %10s  last used %s\n",
+			e.Algorithm, e.Checksum, humanBytes(e.Size), e.LastUsed.Format(time.RFC3339))
+	}
+	_, _ = fmt.Fprintln(out, summary)
+	return nil
+}
+
+// humanBytes renders a byte count with a binary magnitude suffix.
+func humanBytes(b int64) string {
+	const k = 1024.0
+	f := float64(b)
+	switch {
+	case f >= k*k*k:
+		return fmt.Sprintf("%.2f GB", f/(k*k*k))
+	case f >= k*k:
+		return fmt.Sprintf("%.2f MB", f/(k*k))
+	case f >= k:
+		return fmt.Sprintf("%.2f KB", f/k)
+	default:
+		return fmt.Sprintf("%d B", b)
+	}
+}
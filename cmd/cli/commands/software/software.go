@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/spf13/cobra"
+)
+
+var softwareCmd = &cobra.Command{
+	Use:   "software",
+	Short: "Manage the host software solo-provisioner downloads",
+	Long:  "Manage the host software solo-provisioner downloads and installs",
+	RunE:  common.DefaultRunE,
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and prune the download cache",
+	Long: `Inspect and prune the download cache.
+
+Every verified download is kept in a content-addressed cache under the
+downloads directory, keyed by its catalog checksum, so reinstalls and version
+flips reuse it instead of downloading it again.`,
+	RunE: common.DefaultRunE,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	softwareCmd.AddCommand(cacheCmd)
+	softwareCmd.AddCommand(verifyCmd)
+}
+
+func GetCmd() *cobra.Command {
+	return softwareCmd
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/hashgraph/solo-weaver/internal/alloy"
+	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/internal/support"
+	"github.com/hashgraph/solo-weaver/pkg/config"
+	"github.com/joomcode/errorx"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDir         string
+	flagSince       time.Duration
+	flagMaxSourceMB int
+
+	bundleCmd = &cobra.Command{
+		Use:   "bundle",
+		Short: "Collect a redacted support bundle",
+		Long: `Collect a support bundle: one timestamped tarball of the block node pod logs and
+diagnostics, the Helm release values, the state file and action history, the
+solo-provisioner and daemon logs, the nftables ruleset, the tc counters of the
+egress NIC and the pod veths, the daemon status, the sysctl and mount state and
+the hardware profile, with an index.yaml listing every file and every source
+that could not be read.
+
+Secrets are masked in every file: the Teleport node agent token, the Alloy
+remote-write passwords and any value of a key naming a password, token or key.
+Log files, pod logs and action history are limited to --since, and each file to
+--max-source-mb (keeping its newest part).`,
+		Example: `  # Collect the last day
+  sudo solo-provisioner support bundle
+
+  # Collect the last 2 hours, at most 50 MiB per file
+  sudo solo-provisioner support bundle --since=2h --max-source-mb=50`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flagMaxSourceMB <= 0 {
+				return errorx.IllegalArgument.New("--max-source-mb must be positive, got %d", flagMaxSourceMB)
+			}
+
+			opts := support.Options{
+				Dir:            flagDir,
+				Since:          flagSince,
+				MaxSourceBytes: int64(flagMaxSourceMB) << 20,
+				Secrets:        bundleSecrets(cmd.Context()),
+			}
+			bundlePath, index, err := support.Create(cmd.Context(), opts, support.Collectors())
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if common.OutputIsJSON() {
+				data, err := json.Marshal(map[string]any{"path": bundlePath, "index": index})
+				if err != nil {
+					return errorx.InternalError.Wrap(err, "failed to render the support bundle index")
+				}
+				_, _ = fmt.Fprintln(out, string(data))
+				return nil
+			}
+
+			for _, c := range index.Collectors {
+				files, skipped := 0, 0
+				for _, f := range c.Files {
+					if f.Error != "" {
+						skipped++
+					} else {
+						files++
+					}
+				}
+				line := fmt.Sprintf("  %-10s %d file(s)", c.Name, files)
+				if skipped > 0 {
+					line += fmt.Sprintf(", %d source(s) skipped", skipped)
+				}
+				if c.Error != "" {
+					line += " — " + c.Error
+				}
+				_, _ = fmt.Fprintln(out, line)
+			}
+			_, _ = fmt.Fprintf(out, "Support bundle written to %s\n", bundlePath)
+			return nil
+		},
+	}
+)
+
+// bundleSecrets returns the literal secrets to mask on top of the redactor's
+// patterns: the Teleport node agent token and, when the cluster is reachable,
+// the Alloy remote-write passwords.
+func bundleSecrets(ctx context.Context) []string {
+	secrets := []string{config.Get().Teleport.NodeAgentToken}
+
+	kc, err := kube.NewClient()
+	if err != nil {
+		logx.As().Debug().Err(err).Msg("Support bundle: cluster unreachable, Alloy passwords masked by pattern only")
+		return secrets
+	}
+	passwords, err := alloy.SecretValues(ctx, kc)
+	if err != nil {
+		logx.As().Debug().Err(err).Msg("Support bundle: failed to read the Alloy secret, Alloy passwords masked by pattern only")
+		return secrets
+	}
+	return append(secrets, passwords...)
+}
+
+func init() {
+	common.FlagSupportDir().SetVarP(bundleCmd, &flagDir, false)
+	common.FlagSupportSince().SetVarP(bundleCmd, &flagSince, false)
+	common.FlagSupportMaxSourceMB().SetVarP(bundleCmd, &flagMaxSourceMB, false)
+
+	// A bundle is most needed when the installation is broken, and must not
+	// run the startup migrations that change the state it collects.
+	common.SkipGlobalChecks(bundleCmd)
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"github.com/hashgraph/solo-weaver/cmd/cli/commands/common"
+	"github.com/spf13/cobra"
+)
+
+var supportCmd = &cobra.Command{
+	Use:   "support",
+	Short: "Collect troubleshooting data",
+	Long:  "Collect what it takes to troubleshoot this host into a support bundle.",
+	RunE:  common.DefaultRunE,
+}
+
+func init() {
+	supportCmd.AddCommand(bundleCmd)
+}
+
+func GetCmd() *cobra.Command {
+	return supportCmd
+}
@@ -16,6 +16,8 @@ var (
 	flagVersion    string
 	flagValuesFile string
 
+	flagSkipValuesValidation bool
+
 	clusterCmd = &cobra.Command{
 		Use:   "cluster",
 		Short: "Manage Teleport Kubernetes cluster agent",
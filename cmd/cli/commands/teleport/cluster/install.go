@@ -64,8 +64,9 @@ var installCmd = &cobra.Command{
 				},
 			},
 			Custom: models.TeleportClusterInputs{
-				Version:    flagVersion,
-				ValuesFile: validatedValuesFile,
+				Version:              flagVersion,
+				ValuesFile:           validatedValuesFile,
+				SkipValuesValidation: flagSkipValuesValidation,
 			},
 		}
 
@@ -91,6 +92,7 @@ var installCmd = &cobra.Command{
 }
 
 func init() {
+	common.FlagSkipValuesValidation().SetVarP(installCmd, &flagSkipValuesValidation, false)
 	common.FlagStopOnError().SetVarP(installCmd, &flagStopOnError, false)
 	common.FlagRollbackOnError().SetVarP(installCmd, &flagRollbackOnError, false)
 	common.FlagContinueOnError().SetVarP(installCmd, &flagContinueOnError, false)
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package airgap creates and installs air-gap bundles (see pkg/bundle) for
+// sites without network egress: `bundle create` gathers every host artifact,
+// Helm chart and container image a node type needs into one checksummed
+// archive on a connected machine, and `bundle install` (or the global
+// --bundle flag) makes the target host resolve them from it.
+package airgap
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// Activate makes the bundle at path the one downloads and chart pulls
+// resolve from. path is an unpacked bundle directory or a bundle archive,
+// which is verified and unpacked into WeaverPaths.BundleDir first. An empty
+// path activates the bundle `bundle install` left in BundleDir, if any, and
+// returns nil otherwise.
+func Activate(path string) (*bundle.Bundle, error) {
+	bundleDir := models.Paths().BundleDir
+	if path == "" {
+		if _, err := os.Stat(filepath.Join(bundleDir, bundle.ManifestFile)); err != nil {
+			return nil, nil
+		}
+		path = bundleDir
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "bundle %s is not readable", path)
+	}
+
+	var b *bundle.Bundle
+	if info.IsDir() {
+		b, err = bundle.Open(path)
+		if err == nil {
+			err = checkPlatform(b)
+		}
+	} else {
+		b, err = unpackForHost(path, bundleDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bundle.Activate(b)
+	m := b.Manifest()
+	logx.As().Info().
+		Str("bundle", b.Dir()).
+		Str("nodeType", m.NodeType).
+		Str("profile", m.Profile).
+		Int("artifacts", len(m.Artifacts)).
+		Int("charts", len(m.Charts)).
+		Int("images", len(m.Images)).
+		Msg("Air-gap bundle active")
+	return b, nil
+}
+
+// checkPlatform fails unless b was created for this host's OS and
+// architecture.
+func checkPlatform(b *bundle.Bundle) error {
+	m := b.Manifest()
+	if m.OS == runtime.GOOS && m.Arch == runtime.GOARCH {
+		return nil
+	}
+	return errorx.IllegalArgument.New("bundle %s is for %s/%s, not this %s/%s host", b.Dir(), m.OS, m.Arch, runtime.GOOS, runtime.GOARCH).
+		WithProperty(models.ErrPropertyResolution, "create the bundle with --arch="+runtime.GOARCH)
+}
@@ -0,0 +1,408 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package airgap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/automa-saga/version"
+	"github.com/joomcode/errorx"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gopkg.in/yaml.v3"
+	"oras.land/oras-go/v2/content/oci"
+
+	"github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
+	"github.com/hashgraph/solo-weaver/pkg/config"
+	"github.com/hashgraph/solo-weaver/pkg/helm"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/semver"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// kubeadmArtifact is the host artifact whose `config images list` names the
+// control-plane images.
+const kubeadmArtifact = "kubeadm"
+
+// CreateOptions configures Create.
+type CreateOptions struct {
+	NodeType string
+	Profile  string
+	OS       string
+	Arch     string
+	// Output is the path of the archive to write; its checksum file is
+	// written beside it.
+	Output string
+	// Images are container images to add on top of those found in the
+	// charts, e.g. the Cilium images the cilium CLI installs.
+	Images []string
+}
+
+// CreateResult describes a created bundle.
+type CreateResult struct {
+	Archive  string          `json:"archive"`
+	SHA256   string          `json:"sha256"`
+	Manifest bundle.Manifest `json:"manifest"`
+	// Warnings lists what could not be gathered and has to be supplied
+	// another way, such as images of a chart that does not render with its
+	// default values.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Create gathers into one archive every host artifact in the infrastructure
+// catalog, every catalog chart and the block node chart, and every container
+// image those charts and kubeadm reference, for opts.OS/opts.Arch. Host
+// artifacts and charts are verified against the catalog's checksums, and
+// charts against its signature policy, as they are downloaded.
+func Create(ctx context.Context, opts CreateOptions) (*CreateResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	// A bundle is made from the network, never from another bundle.
+	bundle.Activate(nil)
+
+	output, err := filepath.Abs(opts.Output)
+	if err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "invalid output path %q", opts.Output)
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(output), ".bundle-create-")
+	if err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "failed to create a staging directory beside %s", output)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	catalog, err := software.LoadInfrastructureCatalog()
+	if err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "failed to load infrastructure catalog")
+	}
+
+	c := &creator{opts: opts, dir: staging, catalog: catalog}
+	c.manifest = bundle.Manifest{
+		CreatedAt:          time.Now().UTC(),
+		ProvisionerVersion: version.Get().Version,
+		NodeType:           opts.NodeType,
+		Profile:            opts.Profile,
+		OS:                 opts.OS,
+		Arch:               opts.Arch,
+	}
+
+	if err := c.addArtifacts(); err != nil {
+		return nil, err
+	}
+	if err := c.addCharts(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.addImages(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := bundle.WriteManifest(staging, c.manifest); err != nil {
+		return nil, err
+	}
+	sum, err := bundle.Pack(staging, output)
+	if err != nil {
+		return nil, err
+	}
+	logx.As().Info().
+		Str("archive", output).
+		Str("sha256", sum).
+		Int("artifacts", len(c.manifest.Artifacts)).
+		Int("charts", len(c.manifest.Charts)).
+		Int("images", len(c.manifest.Images)).
+		Msg("Air-gap bundle created")
+
+	return &CreateResult{Archive: output, SHA256: sum, Manifest: c.manifest, Warnings: c.warnings}, nil
+}
+
+func (o CreateOptions) validate() error {
+	if o.NodeType != models.NodeTypeBlock {
+		return errorx.IllegalArgument.New("bundles can only be created for node type %q, not %q", models.NodeTypeBlock, o.NodeType).
+			WithProperty(models.ErrPropertyResolution, fmt.Sprintf("pass --node-type=%s", models.NodeTypeBlock))
+	}
+	if !slices.Contains(models.SupportedProfiles(), o.Profile) {
+		return errorx.IllegalArgument.New("unsupported profile %q", o.Profile).
+			WithProperty(models.ErrPropertyResolution, fmt.Sprintf("pass --profile with one of %v", models.SupportedProfiles()))
+	}
+	if o.OS != software.OSLinux {
+		return errorx.IllegalArgument.New("bundles can only be created for %s hosts, not %s", software.OSLinux, o.OS)
+	}
+	if o.Arch != software.ArchAMD64 && o.Arch != software.ArchARM64 {
+		return errorx.IllegalArgument.New("unsupported architecture %q", o.Arch).
+			WithProperty(models.ErrPropertyResolution, fmt.Sprintf("pass --arch=%s or --arch=%s", software.ArchAMD64, software.ArchARM64))
+	}
+	if o.Output == "" {
+		return errorx.IllegalArgument.New("output path is empty")
+	}
+	return nil
+}
+
+// creator fills a staging directory with a bundle's files and its manifest.
+type creator struct {
+	opts     CreateOptions
+	dir      string
+	catalog  *software.InfrastructureCatalog
+	manifest bundle.Manifest
+	warnings []string
+	// charts are the pulled chart archives, with the values and topology
+	// their images are rendered with.
+	charts []renderableChart
+	// kubeadm is the downloaded kubeadm binary and the Kubernetes version it
+	// installs.
+	kubeadm, kubeVersion string
+}
+
+type renderableChart struct {
+	tgz, release, namespace string
+	values                  map[string]interface{}
+}
+
+func (c *creator) warn(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	logx.As().Warn().Msg(msg)
+	c.warnings = append(c.warnings, msg)
+}
+
+// addArtifacts downloads every host artifact file, each verified against
+// the catalog checksum, into artifacts/<software>/.
+func (c *creator) addArtifacts() error {
+	downloads, err := c.catalog.HostDownloads(c.opts.OS, c.opts.Arch)
+	if err != nil {
+		return err
+	}
+	downloader := software.NewDownloader(software.WithBasePath(c.dir))
+
+	seen := map[string]bool{}
+	for _, d := range downloads {
+		if seen[d.URL] {
+			continue
+		}
+		seen[d.URL] = true
+
+		if d.SelfRelease && !isReleasedVersion(d.Version) {
+			c.warn("%s not bundled, this build's version %q has no release to download; "+
+				"install it on the target host with --daemon-bin", d.Software, d.Version)
+			continue
+		}
+
+		rel := path.Join(bundle.ArtifactsDir, d.Software, path.Base(d.Name))
+		dest := filepath.Join(c.dir, rel)
+		if err := os.MkdirAll(filepath.Dir(dest), models.DefaultDirOrExecPerm); err != nil {
+			return errorx.InternalError.Wrap(err, "failed to create %s", filepath.Dir(dest))
+		}
+
+		// A self-released artifact without a pinned digest is verified against
+		// its published checksum asset, which the bundle carries as well so
+		// the installer can verify it the same way offline.
+		if d.ChecksumURL != "" {
+			assetRel := path.Join(bundle.ArtifactsDir, d.Software, path.Base(d.ChecksumURL))
+			d.Checksum, err = downloader.FetchChecksumAsset(d.ChecksumURL, filepath.Join(c.dir, assetRel))
+			if err != nil {
+				return err
+			}
+			if err := c.addArtifact(assetRel, software.HostDownload{Software: d.Software, URL: d.ChecksumURL}); err != nil {
+				return err
+			}
+		}
+
+		if err := downloader.DownloadAndVerify(d.URL, dest, d.Checksum, d.Algorithm); err != nil {
+			return err
+		}
+		if err := c.addArtifact(rel, d); err != nil {
+			return err
+		}
+		if d.Software == kubeadmArtifact && path.Base(d.Name) == kubeadmArtifact {
+			c.kubeadm, c.kubeVersion = dest, "v"+d.Version
+		}
+	}
+	return nil
+}
+
+func (c *creator) addArtifact(rel string, d software.HostDownload) error {
+	sum, err := bundle.SHA256File(filepath.Join(c.dir, rel))
+	if err != nil {
+		return errorx.InternalError.Wrap(err, "failed to hash %s", rel)
+	}
+	c.manifest.Artifacts = append(c.manifest.Artifacts, bundle.Artifact{
+		File:      bundle.File{Path: rel, SHA256: sum},
+		Software:  d.Software,
+		URL:       d.URL,
+		Algorithm: d.Algorithm,
+		Checksum:  d.Checksum,
+	})
+	return nil
+}
+
+// addCharts pulls every `cluster:` chart at its default version, verified
+// against its catalog checksum, and the configured block node chart, which
+// the catalog does not pin. Both are verified against their signature policy.
+func (c *creator) addCharts(ctx context.Context) error {
+	hm, err := helm.NewManager(helm.WithLogger(*logx.As()))
+	if err != nil {
+		return err
+	}
+	chartsDir := filepath.Join(c.dir, bundle.ChartsDir)
+	keysDir := models.Paths().KeysDir
+
+	for i := range c.catalog.Cluster {
+		meta := &c.catalog.Cluster[i]
+		version, err := meta.GetDefaultVersion()
+		if err != nil {
+			return err
+		}
+		sum := meta.Versions[software.Version(version)]
+		prov := c.catalog.ProvenanceFor(meta.Chart).HelmProvenance(keysDir)
+
+		if meta.Type == software.ChartTypeClassic {
+			alias, _, _ := strings.Cut(meta.Chart, "/")
+			if _, err := hm.AddRepo(alias, meta.Repo, helm.RepoAddOptions{}); err != nil {
+				return err
+			}
+		}
+		tgz, err := hm.PullAndVerify(ctx, chartsDir, meta.Chart, version, sum.Algorithm, sum.Value, helm.WithProvenance(prov))
+		if err != nil {
+			return err
+		}
+		if err := c.addChart(tgz, meta.Chart, version, meta.Repo, sum.Value, prov); err != nil {
+			return err
+		}
+		c.charts = append(c.charts, renderableChart{tgz: tgz, release: meta.Release, namespace: meta.Namespace})
+	}
+
+	bn := config.Get().BlockNode
+	prov := c.catalog.ProvenanceFor(bn.Chart).HelmProvenance(keysDir)
+	tgz, sum, err := hm.PullChart(ctx, chartsDir, bn.Chart, bn.ChartVersion, helm.WithProvenance(prov))
+	if err != nil {
+		return err
+	}
+	if err := c.addChart(tgz, bn.Chart, bn.ChartVersion, "", sum, prov); err != nil {
+		return err
+	}
+
+	values, err := blocknode.DefaultValues(c.opts.Profile)
+	if err != nil {
+		return err
+	}
+	var vals map[string]interface{}
+	if err := yaml.Unmarshal(values, &vals); err != nil {
+		return errorx.InternalError.Wrap(err, "failed to parse block node values of profile %s", c.opts.Profile)
+	}
+	c.charts = append(c.charts, renderableChart{tgz: tgz, release: bn.Release, namespace: bn.Namespace, values: vals})
+	return nil
+}
+
+// addChart records a pulled chart. Its signature counts as verified only
+// under a required policy: an optional one lets a chart without a key or a
+// signature through.
+func (c *creator) addChart(tgz, chartRef, version, repo, checksum string, prov helm.Provenance) error {
+	sum, err := bundle.SHA256File(tgz)
+	if err != nil {
+		return errorx.InternalError.Wrap(err, "failed to hash %s", tgz)
+	}
+	c.manifest.Charts = append(c.manifest.Charts, bundle.Chart{
+		File:              bundle.File{Path: path.Join(bundle.ChartsDir, filepath.Base(tgz)), SHA256: sum},
+		Chart:             chartRef,
+		Version:           version,
+		Repo:              repo,
+		Checksum:          checksum,
+		SignatureVerified: prov.Policy == helm.ProvenanceRequired,
+	})
+	return nil
+}
+
+// addImages copies into the bundle's OCI layout every image the charts
+// reference when rendered, the control-plane images kubeadm pulls, and
+// opts.Images.
+func (c *creator) addImages(ctx context.Context) error {
+	refs := map[string]bool{}
+	for _, chart := range c.charts {
+		manifests, err := helm.RenderManifests(chart.tgz, chart.release, chart.namespace, c.kubeVersion, chart.values)
+		if err != nil {
+			c.warn("Images of chart %s not bundled, it does not render with its default values: %v; pass them with --image",
+				filepath.Base(chart.tgz), err)
+			continue
+		}
+		found, err := imageReferences(manifests)
+		if err != nil {
+			return err
+		}
+		for _, ref := range found {
+			refs[ref] = true
+		}
+	}
+
+	found, err := c.kubeadmImages(ctx)
+	if err != nil {
+		return err
+	}
+	for _, ref := range found {
+		refs[ref] = true
+	}
+
+	for _, ref := range c.opts.Images {
+		full, err := normalizeImage(ref)
+		if err != nil {
+			return err
+		}
+		refs[full] = true
+	}
+
+	store, err := oci.New(filepath.Join(c.dir, bundle.ImagesDir))
+	if err != nil {
+		return errorx.InternalError.Wrap(err, "failed to create the bundle image layout")
+	}
+	platform := &ocispec.Platform{OS: c.opts.OS, Architecture: c.opts.Arch}
+	open := remoteRepository(false)
+	for _, ref := range sortedKeys(refs) {
+		img, err := pullImage(ctx, ref, store, platform, open)
+		if err != nil {
+			return err
+		}
+		logx.As().Info().Str("image", img.Ref).Str("digest", img.Digest).Msg("Image added to the air-gap bundle")
+		c.manifest.Images = append(c.manifest.Images, img)
+	}
+	return nil
+}
+
+// kubeadmImages lists the control-plane images with the downloaded kubeadm
+// binary, which only runs when the bundle targets this machine's platform.
+func (c *creator) kubeadmImages(ctx context.Context) ([]string, error) {
+	if c.kubeadm == "" {
+		return nil, nil
+	}
+	if runtime.GOOS != c.opts.OS || runtime.GOARCH != c.opts.Arch {
+		c.warn("Kubernetes control-plane images not bundled, kubeadm for %s/%s cannot run on %s/%s; "+
+			"pass the output of `kubeadm config images list --kubernetes-version %s` with --image",
+			c.opts.OS, c.opts.Arch, runtime.GOOS, runtime.GOARCH, c.kubeVersion)
+		return nil, nil
+	}
+	if err := os.Chmod(c.kubeadm, models.DefaultDirOrExecPerm); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to make %s executable", c.kubeadm)
+	}
+	out, err := exec.CommandContext(ctx, c.kubeadm, "config", "images", "list", "--kubernetes-version", c.kubeVersion).Output()
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to list the kubeadm images of Kubernetes %s", c.kubeVersion)
+	}
+	return kubeadmImages(out)
+}
+
+// isReleasedVersion reports whether v can name a published release: builds
+// not stamped at link time report "dev", local task builds 0.0.0.
+func isReleasedVersion(v string) bool {
+	parsed, err := semver.NewSemver(v)
+	if err != nil {
+		return false
+	}
+	zero, _ := semver.NewSemver("0.0.0")
+	return !parsed.EqualTo(zero)
+}
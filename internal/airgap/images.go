@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package airgap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/joomcode/errorx"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gopkg.in/yaml.v3"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"oras.land/oras-go/v2/registry/remote/retry"
+
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
+)
+
+const (
+	dockerHub         = "docker.io"
+	dockerHubEndpoint = "registry-1.docker.io"
+)
+
+// repositoryFunc opens an image repository such as
+// "docker.io/library/busybox". Tests replace the remote one with in-memory
+// stores.
+type repositoryFunc func(repository string) (oras.Target, error)
+
+// normalizeImage returns the fully qualified form of an image reference, as
+// CRI-O resolves it: "busybox" is "docker.io/library/busybox:latest".
+func normalizeImage(ref string) (string, error) {
+	host, rest, found := strings.Cut(ref, "/")
+	if !found || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		host, rest = dockerHub, ref
+	}
+	if host == dockerHub && !strings.Contains(rest, "/") {
+		rest = "library/" + rest
+	}
+	name := host + "/" + rest
+
+	// Without a tag or digest the reference means the latest tag. A colon in
+	// the last path segment is a tag; one before it is a registry port.
+	lastSegment := name[strings.LastIndex(name, "/")+1:]
+	if !strings.Contains(lastSegment, ":") && !strings.Contains(lastSegment, "@") {
+		name += ":latest"
+	}
+	if _, err := registry.ParseReference(name); err != nil {
+		return "", errorx.IllegalArgument.Wrap(err, "invalid image reference %q", ref)
+	}
+	return name, nil
+}
+
+// imageReferences returns the fully qualified, sorted and de-duplicated image
+// references of every `image:` field in the rendered manifests.
+func imageReferences(manifests string) ([]string, error) {
+	seen := map[string]bool{}
+	dec := yaml.NewDecoder(strings.NewReader(manifests))
+	for {
+		var doc any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errorx.IllegalFormat.Wrap(err, "failed to parse rendered manifests")
+		}
+		var walkErr error
+		walkImages(doc, func(ref string) {
+			full, err := normalizeImage(ref)
+			if err != nil {
+				walkErr = err
+				return
+			}
+			seen[full] = true
+		})
+		if walkErr != nil {
+			return nil, walkErr
+		}
+	}
+	return sortedKeys(seen), nil
+}
+
+func walkImages(node any, found func(string)) {
+	switch n := node.(type) {
+	case map[string]any:
+		for k, v := range n {
+			if s, ok := v.(string); ok && k == "image" && strings.TrimSpace(s) != "" {
+				found(strings.TrimSpace(s))
+				continue
+			}
+			walkImages(v, found)
+		}
+	case []any:
+		for _, v := range n {
+			walkImages(v, found)
+		}
+	}
+}
+
+// pullImage copies the image ref (fully qualified) from its registry into
+// store, tagged with ref. A tagged image is narrowed to platform; an image
+// pinned by digest is copied whole, so that the digest still resolves.
+func pullImage(ctx context.Context, ref string, store oras.Target, platform *ocispec.Platform, open repositoryFunc) (bundle.Image, error) {
+	parsed, err := registry.ParseReference(ref)
+	if err != nil {
+		return bundle.Image{}, errorx.IllegalArgument.Wrap(err, "invalid image reference %q", ref)
+	}
+	src, err := open(parsed.Registry + "/" + parsed.Repository)
+	if err != nil {
+		return bundle.Image{}, errorx.ExternalError.Wrap(err, "failed to open repository of image %s", ref)
+	}
+
+	opts := oras.DefaultCopyOptions
+	if _, err := parsed.Digest(); err != nil {
+		opts.WithTargetPlatform(platform)
+	}
+	desc, err := oras.Copy(ctx, src, parsed.Reference, store, ref, opts)
+	if err != nil {
+		return bundle.Image{}, errorx.ExternalError.Wrap(err, "failed to pull image %s", ref)
+	}
+	return bundle.Image{Ref: ref, Digest: desc.Digest.String()}, nil
+}
+
+// pushImage copies img from the bundle's store to mirror, the local registry
+// (host:port), under the repository "<mirror>/<source registry>/<path>" the
+// CRI-O mirror configuration points at. It returns the mirrored reference.
+func pushImage(ctx context.Context, store oras.ReadOnlyTarget, img bundle.Image, mirror string, open repositoryFunc) (string, error) {
+	parsed, err := registry.ParseReference(img.Ref)
+	if err != nil {
+		return "", errorx.IllegalFormat.Wrap(err, "invalid image reference %q in the bundle", img.Ref)
+	}
+	repository := mirror + "/" + parsed.Registry + "/" + parsed.Repository
+	dst, err := open(repository)
+	if err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to open repository %s", repository)
+	}
+	if _, err := oras.Copy(ctx, store, img.Ref, dst, parsed.Reference, oras.DefaultCopyOptions); err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to push image %s to %s", img.Ref, repository)
+	}
+	if _, err := parsed.Digest(); err == nil {
+		return repository + "@" + parsed.Reference, nil
+	}
+	return repository + ":" + parsed.Reference, nil
+}
+
+// remoteRepository opens an image repository on its registry, with the
+// credentials of `docker login` when there are any. Docker Hub is served
+// from registry-1.docker.io.
+func remoteRepository(plainHTTP bool) repositoryFunc {
+	return func(repository string) (oras.Target, error) {
+		repo, err := remote.NewRepository(repository)
+		if err != nil {
+			return nil, err
+		}
+		if repo.Reference.Registry == dockerHub {
+			repo.Reference.Registry = dockerHubEndpoint
+		}
+		client := &auth.Client{Client: retry.DefaultClient, Cache: auth.NewCache()}
+		if store, err := credentials.NewStoreFromDocker(credentials.StoreOptions{}); err == nil {
+			client.Credential = credentials.Credential(store)
+		}
+		repo.Client = client
+		repo.PlainHTTP = plainHTTP
+		return repo, nil
+	}
+}
+
+// registriesOf returns the sorted source registries of images.
+func registriesOf(images []bundle.Image) []string {
+	seen := map[string]bool{}
+	for _, img := range images {
+		if parsed, err := registry.ParseReference(img.Ref); err == nil {
+			seen[parsed.Registry] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// kubeadmImages parses the output of `kubeadm config images list`.
+func kubeadmImages(output []byte) ([]string, error) {
+	var refs []string
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		ref := strings.TrimSpace(string(line))
+		if ref == "" {
+			continue
+		}
+		full, err := normalizeImage(ref)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, full)
+	}
+	return refs, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
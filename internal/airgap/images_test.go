@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package airgap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
+)
+
+func TestNormalizeImage(t *testing.T) {
+	tests := []struct {
+		ref, want string
+	}{
+		{"busybox", "docker.io/library/busybox:latest"},
+		{"busybox:1.36", "docker.io/library/busybox:1.36"},
+		{"bitnami/redis:7", "docker.io/bitnami/redis:7"},
+		{"docker.io/library/nginx:1.27", "docker.io/library/nginx:1.27"},
+		{"registry.k8s.io/pause:3.10", "registry.k8s.io/pause:3.10"},
+		{"localhost:5000/app", "localhost:5000/app:latest"},
+		{"registry.local:5000/team/app:v1", "registry.local:5000/team/app:v1"},
+		{
+			"ghcr.io/hiero-ledger/app@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			"ghcr.io/hiero-ledger/app@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := normalizeImage(tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := normalizeImage("Invalid/UPPER:tag")
+	require.Error(t, err)
+}
+
+func TestImageReferences(t *testing.T) {
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: busybox:1.36
+      containers:
+        - name: app
+          image: ghcr.io/hiero-ledger/app:0.1.0
+        - name: sidecar
+          image: "  busybox:1.36 "
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+---
+apiVersion: batch/v1
+kind: Job
+spec:
+  template:
+    spec:
+      containers:
+        - name: hook
+          image: registry.k8s.io/kubectl:v1.33.4
+`
+	refs, err := imageReferences(manifests)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"docker.io/library/busybox:1.36",
+		"ghcr.io/hiero-ledger/app:0.1.0",
+		"registry.k8s.io/kubectl:v1.33.4",
+	}, refs)
+
+	_, err = imageReferences("key: [unclosed")
+	require.Error(t, err)
+}
+
+func TestKubeadmImages(t *testing.T) {
+	out := []byte("registry.k8s.io/kube-apiserver:v1.33.4\nregistry.k8s.io/pause:3.10\n\nregistry.k8s.io/coredns/coredns:v1.12.0\n")
+	refs, err := kubeadmImages(out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"registry.k8s.io/kube-apiserver:v1.33.4",
+		"registry.k8s.io/pause:3.10",
+		"registry.k8s.io/coredns/coredns:v1.12.0",
+	}, refs)
+}
+
+func TestRegistriesOf(t *testing.T) {
+	got := registriesOf([]bundle.Image{
+		{Ref: "registry.k8s.io/pause:3.10"},
+		{Ref: "docker.io/library/busybox:1.36"},
+		{Ref: "registry.k8s.io/kube-proxy:v1.33.4"},
+	})
+	assert.Equal(t, []string{"docker.io", "registry.k8s.io"}, got)
+}
+
+// pushTestImage tags a single-layer linux/amd64 image into store and returns
+// its manifest descriptor.
+func pushTestImage(t *testing.T, store *memory.Store, tag string) ocispec.Descriptor {
+	t.Helper()
+	ctx := context.Background()
+	push := func(mediaType string, data []byte) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes(mediaType, data)
+		require.NoError(t, store.Push(ctx, desc, bytes.NewReader(data)))
+		return desc
+	}
+	config := push(ocispec.MediaTypeImageConfig, []byte(`{"architecture":"amd64","os":"linux"}`))
+	layer := push(ocispec.MediaTypeImageLayer, []byte("layer of "+tag))
+	desc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "",
+		oras.PackManifestOptions{ConfigDescriptor: &config, Layers: []ocispec.Descriptor{layer}})
+	require.NoError(t, err)
+	require.NoError(t, store.Tag(ctx, desc, tag))
+	return desc
+}
+
+// memoryRegistry opens an in-memory store per repository.
+type memoryRegistry map[string]*memory.Store
+
+func (r memoryRegistry) open(repository string) (oras.Target, error) {
+	if r[repository] == nil {
+		r[repository] = memory.New()
+	}
+	return r[repository], nil
+}
+
+func TestPullAndPushImage(t *testing.T) {
+	ctx := context.Background()
+	source := memoryRegistry{}
+	upstream, _ := source.open("ghcr.io/hiero-ledger/app")
+	desc := pushTestImage(t, upstream.(*memory.Store), "0.1.0")
+
+	store := memory.New()
+	img, err := pullImage(ctx, "ghcr.io/hiero-ledger/app:0.1.0", store, &ocispec.Platform{OS: "linux", Architecture: "amd64"}, source.open)
+	require.NoError(t, err)
+	assert.Equal(t, bundle.Image{Ref: "ghcr.io/hiero-ledger/app:0.1.0", Digest: desc.Digest.String()}, img)
+
+	mirror := memoryRegistry{}
+	ref, err := pushImage(ctx, store, img, "registry.local:5000", mirror.open)
+	require.NoError(t, err)
+	assert.Equal(t, "registry.local:5000/ghcr.io/hiero-ledger/app:0.1.0", ref)
+
+	pushed, err := mirror["registry.local:5000/ghcr.io/hiero-ledger/app"].Resolve(ctx, "0.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, desc.Digest, pushed.Digest)
+}
+
+func TestPullImage_NotFound(t *testing.T) {
+	_, err := pullImage(context.Background(), "ghcr.io/hiero-ledger/missing:0.1.0", memory.New(),
+		&ocispec.Platform{OS: "linux", Architecture: "amd64"}, memoryRegistry{}.open)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to pull image")
+}
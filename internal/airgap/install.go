@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package airgap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+	"oras.land/oras-go/v2/content/oci"
+
+	"github.com/hashgraph/solo-weaver/internal/templates"
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+const mirrorsConfTemplate = "files/crio/bundle-mirrors.conf"
+
+// InstallOptions configures Install.
+type InstallOptions struct {
+	// Archive is the bundle archive to install.
+	Archive string
+	// Registry is the host:port of a registry reachable from the cluster the
+	// bundle's images are pushed to. Without one, the images stay in the
+	// bundle and have to be loaded into the registry another way.
+	Registry string
+	// PlainHTTP talks to Registry over HTTP instead of HTTPS, and makes CRI-O
+	// treat it as insecure.
+	PlainHTTP bool
+}
+
+// InstallResult describes an installed bundle.
+type InstallResult struct {
+	Dir      string          `json:"dir"`
+	Manifest bundle.Manifest `json:"manifest"`
+	// Images are the mirrored references of the images pushed to the
+	// registry.
+	Images []string `json:"images,omitempty"`
+	// MirrorsConf is the CRI-O registry configuration pointing the images'
+	// source registries at the registry.
+	MirrorsConf string   `json:"mirrorsConf,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// Install verifies and unpacks a bundle archive into WeaverPaths.BundleDir,
+// where later commands find it without --bundle, and activates it. With a
+// registry, it pushes the bundle's images there and configures CRI-O to pull
+// them from it.
+func Install(ctx context.Context, opts InstallOptions) (*InstallResult, error) {
+	if opts.Archive == "" {
+		return nil, errorx.IllegalArgument.New("bundle archive path is empty")
+	}
+	return install(ctx, opts, remoteRepository(opts.PlainHTTP))
+}
+
+func install(ctx context.Context, opts InstallOptions, open repositoryFunc) (*InstallResult, error) {
+	b, err := unpackForHost(opts.Archive, models.Paths().BundleDir)
+	if err != nil {
+		return nil, err
+	}
+	m := b.Manifest()
+
+	result := &InstallResult{Dir: b.Dir(), Manifest: m}
+	switch {
+	case len(m.Images) == 0:
+	case opts.Registry == "":
+		msg := "Bundle images not pushed, no --registry given; load them into the cluster's registry from " + b.ImagesDir()
+		logx.As().Warn().Msg(msg)
+		result.Warnings = append(result.Warnings, msg)
+	default:
+		if result.Images, err = pushImages(ctx, b, opts.Registry, open); err != nil {
+			return nil, err
+		}
+		if result.MirrorsConf, err = installMirrorsConf(registriesOf(m.Images), opts.Registry, opts.PlainHTTP); err != nil {
+			return nil, err
+		}
+	}
+
+	bundle.Activate(b)
+	return result, nil
+}
+
+// unpackForHost unpacks archive into bundleDir, replacing the bundle there
+// only when the new one is for this host's platform.
+func unpackForHost(archive, bundleDir string) (*bundle.Bundle, error) {
+	next := bundleDir + ".next"
+	defer func() { _ = os.RemoveAll(next) }()
+	b, err := bundle.Unpack(archive, next)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPlatform(b); err != nil {
+		return nil, err
+	}
+
+	if err := os.RemoveAll(bundleDir); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to remove the previous bundle %s", bundleDir)
+	}
+	if err := os.Rename(next, bundleDir); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to move bundle into %s", bundleDir)
+	}
+	return bundle.Open(bundleDir)
+}
+
+// pushImages pushes every image of b to registry and returns their mirrored
+// references.
+func pushImages(ctx context.Context, b *bundle.Bundle, registry string, open repositoryFunc) ([]string, error) {
+	store, err := oci.NewFromFS(ctx, os.DirFS(b.ImagesDir()))
+	if err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "failed to open the bundle image layout %s", b.ImagesDir())
+	}
+	var mirrored []string
+	for _, img := range b.Manifest().Images {
+		ref, err := pushImage(ctx, store, img, registry, open)
+		if err != nil {
+			return nil, err
+		}
+		logx.As().Info().Str("image", img.Ref).Str("mirror", ref).Msg("Bundle image pushed")
+		mirrored = append(mirrored, ref)
+	}
+	return mirrored, nil
+}
+
+// installMirrorsConf writes the CRI-O registry configuration that mirrors each
+// of registries by mirror, beside the proxy's registries.conf, and returns its
+// path.
+func installMirrorsConf(registries []string, mirror string, insecure bool) (string, error) {
+	data := struct {
+		Registries []string
+		Mirror     string
+		Insecure   bool
+	}{
+		Registries: registries,
+		Mirror:     mirror,
+		Insecure:   insecure,
+	}
+
+	content, err := templates.Render(mirrorsConfTemplate, data)
+	if err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to render bundle-mirrors.conf template")
+	}
+
+	confPath := filepath.Join(models.Paths().SandboxDir, "etc", "containers", "registries.conf.d", "bundle-mirrors.conf")
+	if err := os.MkdirAll(filepath.Dir(confPath), models.DefaultDirOrExecPerm); err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to create registries.conf.d directory")
+	}
+	if err := os.WriteFile(confPath, []byte(content), models.DefaultFilePerm); err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to write bundle-mirrors.conf")
+	}
+	return confPath, nil
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package airgap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/content/oci"
+
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// packTestBundle packs a bundle for goos/goarch holding the image ref, pulled
+// from an in-memory registry, and returns the archive path.
+func packTestBundle(t *testing.T, goos, goarch, ref string) string {
+	t.Helper()
+	ctx := context.Background()
+	source := memoryRegistry{}
+	upstream, _ := source.open("ghcr.io/hiero-ledger/app")
+	pushTestImage(t, upstream.(*memory.Store), "0.1.0")
+
+	dir := t.TempDir()
+	store, err := oci.New(filepath.Join(dir, bundle.ImagesDir))
+	require.NoError(t, err)
+	img, err := pullImage(ctx, ref, store, &ocispec.Platform{OS: "linux", Architecture: "amd64"}, source.open)
+	require.NoError(t, err)
+
+	require.NoError(t, bundle.WriteManifest(dir, bundle.Manifest{
+		NodeType: models.NodeTypeBlock,
+		Profile:  models.ProfileLocal,
+		OS:       goos,
+		Arch:     goarch,
+		Images:   []bundle.Image{img},
+	}))
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	_, err = bundle.Pack(dir, archive)
+	require.NoError(t, err)
+	return archive
+}
+
+func TestInstall_PushesImagesAndWritesMirrors(t *testing.T) {
+	t.Cleanup(models.SetPaths(t.TempDir()))
+	t.Cleanup(func() { bundle.Activate(nil) })
+	archive := packTestBundle(t, runtime.GOOS, runtime.GOARCH, "ghcr.io/hiero-ledger/app:0.1.0")
+
+	mirror := memoryRegistry{}
+	result, err := install(context.Background(), InstallOptions{Archive: archive, Registry: "registry.local:5000", PlainHTTP: true}, mirror.open)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.Paths().BundleDir, result.Dir)
+	assert.Equal(t, []string{"registry.local:5000/ghcr.io/hiero-ledger/app:0.1.0"}, result.Images)
+	_, err = mirror["registry.local:5000/ghcr.io/hiero-ledger/app"].Resolve(context.Background(), "0.1.0")
+	require.NoError(t, err)
+
+	conf, err := os.ReadFile(result.MirrorsConf)
+	require.NoError(t, err)
+	assert.Contains(t, string(conf), `prefix = "ghcr.io"`)
+	assert.Contains(t, string(conf), `location = "registry.local:5000/ghcr.io"`)
+	assert.Contains(t, string(conf), "insecure = true")
+
+	require.NotNil(t, bundle.Active())
+	assert.Equal(t, result.Dir, bundle.Active().Dir())
+}
+
+func TestInstall_WithoutRegistryWarns(t *testing.T) {
+	t.Cleanup(models.SetPaths(t.TempDir()))
+	t.Cleanup(func() { bundle.Activate(nil) })
+	archive := packTestBundle(t, runtime.GOOS, runtime.GOARCH, "ghcr.io/hiero-ledger/app:0.1.0")
+
+	result, err := install(context.Background(), InstallOptions{Archive: archive}, memoryRegistry{}.open)
+	require.NoError(t, err)
+	assert.Empty(t, result.Images)
+	assert.Empty(t, result.MirrorsConf)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "no --registry")
+	assert.NotNil(t, bundle.Active())
+}
+
+func TestInstall_RejectsOtherPlatform(t *testing.T) {
+	t.Cleanup(models.SetPaths(t.TempDir()))
+	t.Cleanup(func() { bundle.Activate(nil) })
+	other := "arm64"
+	if runtime.GOARCH == other {
+		other = "amd64"
+	}
+	archive := packTestBundle(t, runtime.GOOS, other, "ghcr.io/hiero-ledger/app:0.1.0")
+
+	_, err := install(context.Background(), InstallOptions{Archive: archive}, memoryRegistry{}.open)
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, errorx.IllegalArgument))
+	assert.Nil(t, bundle.Active())
+	assert.NoDirExists(t, models.Paths().BundleDir, "a bundle for another platform is not left installed")
+}
+
+func TestActivate_InstalledBundle(t *testing.T) {
+	t.Cleanup(models.SetPaths(t.TempDir()))
+	t.Cleanup(func() { bundle.Activate(nil) })
+
+	b, err := Activate("")
+	require.NoError(t, err)
+	assert.Nil(t, b, "nothing is activated before a bundle is installed")
+
+	archive := packTestBundle(t, runtime.GOOS, runtime.GOARCH, "ghcr.io/hiero-ledger/app:0.1.0")
+	_, err = install(context.Background(), InstallOptions{Archive: archive}, memoryRegistry{}.open)
+	require.NoError(t, err)
+	bundle.Activate(nil)
+
+	b, err = Activate("")
+	require.NoError(t, err)
+	require.NotNil(t, b)
+	assert.Equal(t, models.Paths().BundleDir, b.Dir())
+	assert.Same(t, b, bundle.Active())
+}
+
+func TestActivate_Archive(t *testing.T) {
+	t.Cleanup(models.SetPaths(t.TempDir()))
+	t.Cleanup(func() { bundle.Activate(nil) })
+	archive := packTestBundle(t, runtime.GOOS, runtime.GOARCH, "ghcr.io/hiero-ledger/app:0.1.0")
+
+	b, err := Activate(archive)
+	require.NoError(t, err)
+	assert.Equal(t, models.Paths().BundleDir, b.Dir())
+	assert.Len(t, b.Manifest().Images, 1)
+
+	_, err = Activate(filepath.Join(t.TempDir(), "missing.tar.gz"))
+	require.Error(t, err)
+}
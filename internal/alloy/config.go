@@ -4,12 +4,15 @@
 package alloy
 
 import (
+	"context"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/joomcode/errorx"
 
 	"github.com/hashgraph/solo-weaver/internal/alloy/labels"
+	"github.com/hashgraph/solo-weaver/internal/kube"
 	"github.com/hashgraph/solo-weaver/internal/network"
 	"github.com/hashgraph/solo-weaver/internal/templates"
 	"github.com/hashgraph/solo-weaver/pkg/models"
@@ -28,6 +31,7 @@ const (
 	KubeletTemplatePath         = "files/alloy/kubelet.alloy"
 	SyslogTemplatePath          = "files/alloy/syslog.alloy"
 	BlockNodeTemplatePath       = "files/alloy/block-node.alloy"
+	TrafficShaperTemplatePath   = "files/alloy/traffic-shaper.alloy"
 	BlockNodeServiceMonitorPath = "files/alloy/block-node-servicemonitor.yaml"
 )
 
@@ -46,6 +50,7 @@ type ConfigBuilder struct {
 	prometheusRemotes []Remote
 	lokiRemotes       []Remote
 	monitorBlockNode  bool
+	monitorShaper     bool
 	deployProfile     string // stored for per-remote label resolution
 	machineIP         string // host IP for the "ip" label (best-effort)
 }
@@ -55,6 +60,7 @@ type ConfigBuilder struct {
 func NewConfigBuilder(cfg models.AlloyConfig, deployProfile string) (*ConfigBuilder, error) {
 	cb := &ConfigBuilder{
 		monitorBlockNode: cfg.MonitorBlockNode,
+		monitorShaper:    cfg.MonitorTrafficShaper,
 	}
 
 	// Determine cluster name
@@ -119,6 +125,11 @@ func (cb *ConfigBuilder) MonitorBlockNode() bool {
 	return cb.monitorBlockNode
 }
 
+// MonitorTrafficShaper returns whether traffic shaper exporter scraping is enabled.
+func (cb *ConfigBuilder) MonitorTrafficShaper() bool {
+	return cb.monitorShaper
+}
+
 // PrometheusForwardTo returns the forward_to string for Prometheus remotes.
 func (cb *ConfigBuilder) PrometheusForwardTo() string {
 	var receivers []string
@@ -271,3 +282,18 @@ func (cb *ConfigBuilder) RequiredSecrets() map[string][]string {
 	}
 	return map[string][]string{SecretsName: keys}
 }
+
+// SecretValues returns the passwords stored in the Alloy Secret, so they can be
+// masked wherever they appear. A missing Secret yields none.
+func SecretValues(ctx context.Context, kc *kube.Client) ([]string, error) {
+	data, err := kc.GetSecretData(ctx, alloyNamespace(), SecretsName)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(data))
+	for _, v := range data {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values, nil
+}
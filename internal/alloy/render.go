@@ -132,6 +132,19 @@ func RenderModularConfigs(cb *ConfigBuilder) ([]ModuleConfig, error) {
 		})
 	}
 
+	// 8. Traffic shaper config (only if enabled AND Prometheus remotes are configured)
+	if cb.MonitorTrafficShaper() && hasPrometheusRemotes {
+		trafficShaperConfig, err := templates.Render(TrafficShaperTemplatePath, moduleData)
+		if err != nil {
+			return nil, errorx.InternalError.Wrap(err, "failed to render traffic-shaper config")
+		}
+		modules = append(modules, ModuleConfig{
+			Name:     "traffic-shaper",
+			Filename: "traffic-shaper.alloy",
+			Content:  trafficShaperConfig,
+		})
+	}
+
 	return modules, nil
 }
 
@@ -101,8 +101,35 @@ func TestRenderModularConfigs(t *testing.T) {
 				},
 			},
 			expectedModules:     []string{"core", "remotes", "agent-metrics", "node-exporter", "kubelet", "syslog", "block-node"},
+			unexpectedModules:   []string{"traffic-shaper"},
 			expectedModuleCount: 7,
 		},
+		{
+			name: "traffic shaper monitoring with prometheus remote",
+			cfg: models.AlloyConfig{
+				ClusterName:          "test-cluster",
+				MonitorTrafficShaper: true,
+				PrometheusRemotes: []models.AlloyRemoteConfig{
+					{Name: "primary", URL: "http://prom:9090/api/v1/write", Username: "user"},
+				},
+			},
+			expectedModules:     []string{"core", "remotes", "agent-metrics", "node-exporter", "kubelet", "traffic-shaper"},
+			unexpectedModules:   []string{"block-node", "syslog"},
+			expectedModuleCount: 6,
+		},
+		{
+			name: "traffic shaper monitoring without prometheus remote - skipped",
+			cfg: models.AlloyConfig{
+				ClusterName:          "test-cluster",
+				MonitorTrafficShaper: true,
+				LokiRemotes: []models.AlloyRemoteConfig{
+					{Name: "primary", URL: "http://loki:3100/loki/api/v1/push", Username: "user"},
+				},
+			},
+			expectedModules:     []string{"core", "remotes", "syslog"},
+			unexpectedModules:   []string{"traffic-shaper"},
+			expectedModuleCount: 3,
+		},
 	}
 
 	for _, tt := range tests {
@@ -190,6 +217,41 @@ func TestRenderModularConfigs_BlockNodeLogProcessing(t *testing.T) {
 	assert.Contains(t, blockNodeContent, `forward_to = [loki.relabel.block_node_server.receiver]`)
 }
 
+func TestRenderModularConfigs_TrafficShaperScrape(t *testing.T) {
+	cfg := models.AlloyConfig{
+		ClusterName:          "test-cluster",
+		MonitorTrafficShaper: true,
+		PrometheusRemotes: []models.AlloyRemoteConfig{
+			{Name: "primary", URL: "http://prom:9090/api/v1/write", Username: "user"},
+			{Name: "backup", URL: "http://prom2:9090/api/v1/write", Username: "user"},
+		},
+	}
+
+	cb, err := NewConfigBuilder(cfg, "")
+	require.NoError(t, err)
+	modules, err := RenderModularConfigs(cb)
+	require.NoError(t, err)
+
+	var content string
+	for _, m := range modules {
+		if m.Name == "traffic-shaper" {
+			content = m.Content
+			break
+		}
+	}
+	require.NotEmpty(t, content, "traffic-shaper module should exist")
+
+	// Scrapes the host-network exporter on each node's InternalIP.
+	assert.Contains(t, content, "targets = discovery.kubernetes.nodes.targets")
+	assert.Contains(t, content, `replacement   = "$1:9469"`)
+	assert.Contains(t, content, `prometheus.scrape "traffic_shaper"`)
+
+	// Fans out to every Prometheus remote.
+	assert.Contains(t, content, "forward_to = [prometheus.relabel.traffic_shaper_primary.receiver, prometheus.relabel.traffic_shaper_backup.receiver]")
+	assert.Contains(t, content, "forward_to = [prometheus.remote_write.primary.receiver]")
+	assert.Contains(t, content, "forward_to = [prometheus.remote_write.backup.receiver]")
+}
+
 func TestRenderModularConfigs_WithOpsLabelProfile_AllModules(t *testing.T) {
 	cfg := models.AlloyConfig{
 		ClusterName:      "lfh02-previewnet-blocknode",
@@ -0,0 +1,376 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joomcode/errorx"
+	"github.com/klauspost/compress/zstd"
+)
+
+// fileIndex maps a volume-relative, slash-separated file path to its size.
+type fileIndex map[string]int64
+
+// archiveStats is what writeArchive reports about one volume.
+type archiveStats struct {
+	SHA256       string
+	ArchiveBytes int64
+	Files        int
+	Bytes        int64
+	Index        fileIndex // every regular file present, stored or not
+}
+
+// hashingWriter counts and hashes what passes through it.
+type hashingWriter struct {
+	w io.Writer
+	h hash.Hash
+	n int64
+}
+
+func (hw *hashingWriter) Write(p []byte) (int, error) {
+	n, err := hw.w.Write(p)
+	hw.h.Write(p[:n])
+	hw.n += int64(n)
+	return n, err
+}
+
+// writeArchive tars the contents of src into a zstd-compressed archive at dst.
+// Directories and symlinks are always stored; a regular file is skipped when
+// base records it at the same size (the incremental case). Other file types
+// (sockets, devices, FIFOs) have no place on block node storage and are
+// ignored.
+func writeArchive(ctx context.Context, src, dst string, base fileIndex) (*archiveStats, error) {
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to create archive %s", dst)
+	}
+	defer func() { _ = out.Close() }()
+
+	bw := bufio.NewWriterSize(out, 1<<20)
+	hw := &hashingWriter{w: bw, h: sha256.New()}
+	zw, err := zstd.NewWriter(hw)
+	if err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to start zstd encoder")
+	}
+	tw := tar.NewWriter(zw)
+
+	stats := &archiveStats{Index: fileIndex{}}
+	walkErr := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+		switch {
+		case info.Mode().IsRegular():
+			stats.Index[name] = info.Size()
+			if size, ok := base[name]; ok && size == info.Size() {
+				return nil
+			}
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		case info.IsDir():
+		default:
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(tw, f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+		stats.Files++
+		stats.Bytes += n
+		return nil
+	})
+	if walkErr != nil {
+		_ = zw.Close()
+		return nil, errorx.ExternalError.Wrap(walkErr, "failed to archive %s", src)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to finish archive %s", dst)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to finish archive %s", dst)
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to write archive %s", dst)
+	}
+	if err := out.Sync(); err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to sync archive %s", dst)
+	}
+	stats.SHA256 = hex.EncodeToString(hw.h.Sum(nil))
+	stats.ArchiveBytes = hw.n
+	return stats, nil
+}
+
+// extractArchive unpacks the archive at src into dst, which must exist, while
+// hashing the compressed stream; a checksum that does not match want fails
+// the extraction (after the fact — callers verify up front with Verify, this
+// guards against the archive changing underneath). Existing files are
+// overwritten. Ownership is restored when running as root.
+func extractArchive(ctx context.Context, src, dst, want string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to open archive %s", src)
+	}
+	defer func() { _ = in.Close() }()
+
+	h := sha256.New()
+	tee := io.TeeReader(bufio.NewReaderSize(in, 1<<20), h)
+	zr, err := zstd.NewReader(tee, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return errorx.IllegalFormat.Wrap(err, "archive %s is not zstd-compressed", src)
+	}
+	defer zr.Close()
+
+	chown := os.Geteuid() == 0
+	tr := tar.NewReader(zr)
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errorx.IllegalFormat.Wrap(err, "corrupt archive %s", src)
+		}
+		target, err := entryPath(dst, hdr.Name)
+		if err != nil {
+			return errorx.IllegalFormat.Wrap(err, "unsafe entry in archive %s", src)
+		}
+		mode := fs.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return errorx.ExternalError.Wrap(err, "failed to create %s", target)
+			}
+			if err := os.Chmod(target, mode); err != nil {
+				return errorx.ExternalError.Wrap(err, "failed to set mode on %s", target)
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, mode); err != nil {
+				return err
+			}
+			_ = os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+		case tar.TypeSymlink:
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return errorx.ExternalError.Wrap(err, "failed to replace %s", target)
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return errorx.ExternalError.Wrap(err, "failed to create symlink %s", target)
+			}
+		default:
+			continue
+		}
+		if chown {
+			if err := os.Lchown(target, hdr.Uid, hdr.Gid); err != nil {
+				return errorx.ExternalError.Wrap(err, "failed to set ownership on %s", target)
+			}
+		}
+	}
+
+	// Drain the rest of the stream (tar padding, zstd trailer) into the hash.
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return errorx.IllegalFormat.Wrap(err, "corrupt archive %s", src)
+	}
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to read archive %s", src)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); want != "" && got != want {
+		return errorx.IllegalState.New("archive %s changed during restore: sha256 %s, manifest records %s", src, got, want)
+	}
+	return nil
+}
+
+func writeFile(target string, r io.Reader, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to create %s", filepath.Dir(target))
+	}
+	// Never write through a symlink left by an earlier archive in the chain.
+	if fi, err := os.Lstat(target); err == nil && fi.Mode()&fs.ModeSymlink != 0 {
+		if err := os.Remove(target); err != nil {
+			return errorx.ExternalError.Wrap(err, "failed to replace %s", target)
+		}
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to create %s", target)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return errorx.ExternalError.Wrap(err, "failed to write %s", target)
+	}
+	if err := f.Close(); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to write %s", target)
+	}
+	return os.Chmod(target, mode)
+}
+
+// entryPath resolves an archive entry name under dst, rejecting absolute
+// names, ".." escapes and any parent directory that is a symlink — the three
+// ways a crafted archive could write outside the volume.
+func entryPath(dst, name string) (string, error) {
+	rel := filepath.FromSlash(strings.TrimSuffix(name, "/"))
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("entry %q escapes the volume", name)
+	}
+	cur := dst
+	parts := strings.Split(rel, string(filepath.Separator))
+	for _, part := range parts[:len(parts)-1] {
+		cur = filepath.Join(cur, part)
+		fi, err := os.Lstat(cur)
+		if err != nil {
+			if os.IsNotExist(err) {
+				break
+			}
+			return "", err
+		}
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			return "", fmt.Errorf("entry %q traverses symlink %s", name, cur)
+		}
+	}
+	return filepath.Join(dst, rel), nil
+}
+
+// pruneToIndex removes every regular file and symlink under dir that index
+// does not list: files a base backup carried that the node had since pruned.
+func pruneToIndex(dir string, index fileIndex) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if _, ok := index[filepath.ToSlash(rel)]; ok {
+			return nil
+		}
+		return os.Remove(p)
+	})
+}
+
+// writeIndex writes index as sorted "<size>\t<quoted path>" lines and returns
+// the file's SHA-256.
+func writeIndex(path string, index fileIndex) (string, error) {
+	names := make([]string, 0, len(index))
+	for name := range index {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to create index %s", path)
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	bw := bufio.NewWriter(io.MultiWriter(f, h))
+	for _, name := range names {
+		if _, err := fmt.Fprintf(bw, "%d\t%s\n", index[name], strconv.Quote(name)); err != nil {
+			return "", errorx.ExternalError.Wrap(err, "failed to write index %s", path)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to write index %s", path)
+	}
+	if err := f.Sync(); err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to sync index %s", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readIndex parses an index written by writeIndex, checking its SHA-256.
+func readIndex(path, want string) (fileIndex, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read index %s", path)
+	}
+	if got := sha256Hex(raw); got != want {
+		return nil, errorx.IllegalState.New("index %s is corrupt: sha256 %s, manifest records %s", path, got, want)
+	}
+	index := fileIndex{}
+	for i, line := range strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		sizeStr, quoted, ok := strings.Cut(line, "\t")
+		size, sizeErr := strconv.ParseInt(sizeStr, 10, 64)
+		name, nameErr := strconv.Unquote(quoted)
+		if !ok || sizeErr != nil || nameErr != nil {
+			return nil, errorx.IllegalFormat.New("index %s: malformed line %d", path, i+1)
+		}
+		index[name] = size
+	}
+	return index, nil
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// fileSHA256 streams the file at path through SHA-256.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to open %s", path)
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to read %s", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
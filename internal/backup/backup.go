@@ -0,0 +1,353 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+)
+
+// Source is one volume to back up.
+type Source struct {
+	Name string
+	Path string
+	// AppendOnly enables incremental backups of the volume (see Volume).
+	AppendOnly bool
+}
+
+// Options configures Create.
+type Options struct {
+	// Target is the directory backups are written under, e.g. a local disk or
+	// a mounted NFS/object-store target. It is created if missing.
+	Target       string
+	Namespace    string
+	Release      string
+	ChartVersion string
+	// Kind is recorded in the manifest and, when set, in the directory name
+	// (<release>-<kind>-<timestamp>). Latest ignores backups of any Kind.
+	Kind    string
+	Volumes []Source
+	// Incremental bases the backup on the newest complete backup of Release
+	// under Target. Append-only volumes then store only new or grown files;
+	// every other volume is still backed up in full. Without a prior backup
+	// the result is a full backup.
+	Incremental bool
+	// Now stamps the backup; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Create writes a backup of opts.Volumes under opts.Target and returns its
+// directory and manifest. The backup is assembled in a ".partial" directory
+// and renamed into place only once every archive and the manifest are synced,
+// so an interrupted run never leaves a directory Latest or Restore would
+// pick up. A volume whose directory does not exist is left out of the backup.
+func Create(ctx context.Context, opts Options) (string, *Manifest, error) {
+	if opts.Target == "" {
+		return "", nil, errorx.IllegalArgument.New("backup target directory is required")
+	}
+	if opts.Release == "" {
+		return "", nil, errorx.IllegalArgument.New("backup release name is required")
+	}
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	created := now().UTC().Truncate(time.Second)
+
+	if err := os.MkdirAll(opts.Target, 0o750); err != nil {
+		return "", nil, errorx.ExternalError.Wrap(err, "failed to create backup target %s", opts.Target)
+	}
+
+	man := &Manifest{
+		FormatVersion: FormatVersion,
+		CreatedAt:     created,
+		Namespace:     opts.Namespace,
+		Release:       opts.Release,
+		Kind:          opts.Kind,
+		ChartVersion:  opts.ChartVersion,
+	}
+
+	var base *Manifest
+	var baseDir string
+	if opts.Incremental {
+		var err error
+		baseDir, base, err = Latest(opts.Target, opts.Release)
+		if err != nil {
+			return "", nil, err
+		}
+		if base == nil {
+			logx.As().Warn().Str("target", opts.Target).Str("release", opts.Release).
+				Msg("No earlier backup to increment from; taking a full backup")
+		} else {
+			man.Base = filepath.Base(baseDir)
+		}
+	}
+
+	name := opts.Release + "-" + created.Format(timestampLayout)
+	if opts.Kind != "" {
+		name = opts.Release + "-" + opts.Kind + "-" + created.Format(timestampLayout)
+	}
+	final := filepath.Join(opts.Target, name)
+	if _, err := os.Stat(final); err == nil {
+		return "", nil, errorx.IllegalState.New("backup %s already exists", final)
+	}
+	work := final + partialSuffix
+	if err := os.RemoveAll(work); err != nil {
+		return "", nil, errorx.ExternalError.Wrap(err, "failed to clear stale %s", work)
+	}
+	if err := os.Mkdir(work, 0o750); err != nil {
+		return "", nil, errorx.ExternalError.Wrap(err, "failed to create %s", work)
+	}
+	ok := false
+	defer func() {
+		if !ok {
+			_ = os.RemoveAll(work)
+		}
+	}()
+
+	for _, src := range opts.Volumes {
+		fi, err := os.Stat(src.Path)
+		if os.IsNotExist(err) {
+			logx.As().Warn().Str("volume", src.Name).Str("path", src.Path).
+				Msg("Storage directory does not exist; leaving the volume out of the backup")
+			continue
+		}
+		if err != nil {
+			return "", nil, errorx.ExternalError.Wrap(err, "failed to stat %s", src.Path)
+		}
+		if !fi.IsDir() {
+			return "", nil, errorx.IllegalState.New("%s storage path %s is not a directory", src.Name, src.Path)
+		}
+
+		vol := Volume{Name: src.Name, SourcePath: src.Path, Archive: src.Name + archiveSuffix, AppendOnly: src.AppendOnly}
+		var baseIndex fileIndex
+		if base != nil && src.AppendOnly {
+			if bv := base.Volume(src.Name); bv != nil && bv.Index != "" {
+				if baseIndex, err = readIndex(filepath.Join(baseDir, bv.Index), bv.IndexSHA256); err != nil {
+					return "", nil, errorx.Decorate(err, "cannot increment from %s", baseDir)
+				}
+				vol.Incremental = true
+			}
+		}
+
+		logx.As().Info().Str("volume", src.Name).Str("path", src.Path).Bool("incremental", vol.Incremental).
+			Msg("Archiving backup volume")
+		stats, err := writeArchive(ctx, src.Path, filepath.Join(work, vol.Archive), baseIndex)
+		if err != nil {
+			return "", nil, err
+		}
+		vol.SHA256, vol.ArchiveBytes, vol.Files, vol.Bytes = stats.SHA256, stats.ArchiveBytes, stats.Files, stats.Bytes
+		if src.AppendOnly {
+			vol.Index = src.Name + indexSuffix
+			if vol.IndexSHA256, err = writeIndex(filepath.Join(work, vol.Index), stats.Index); err != nil {
+				return "", nil, err
+			}
+		}
+		man.Volumes = append(man.Volumes, vol)
+	}
+
+	if err := writeManifest(work, man); err != nil {
+		return "", nil, err
+	}
+	if err := os.Rename(work, final); err != nil {
+		return "", nil, errorx.ExternalError.Wrap(err, "failed to finalize backup %s", final)
+	}
+	ok = true
+	return final, man, nil
+}
+
+// Latest returns the newest complete storage backup of release under target,
+// or a nil manifest when there is none. Backups of another Kind are ignored.
+func Latest(target, release string) (string, *Manifest, error) {
+	dirs, mans, err := List(target, release, "")
+	if err != nil || len(mans) == 0 {
+		return "", nil, err
+	}
+	return dirs[len(dirs)-1], mans[len(mans)-1], nil
+}
+
+// List returns the complete backups of release and kind under target, oldest
+// first. Directories without a readable manifest are skipped.
+func List(target, release, kind string) ([]string, []*Manifest, error) {
+	entries, err := os.ReadDir(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, errorx.ExternalError.Wrap(err, "failed to list backups in %s", target)
+	}
+	type found struct {
+		dir string
+		man *Manifest
+	}
+	var all []found
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), release+"-") || strings.HasSuffix(e.Name(), partialSuffix) {
+			continue
+		}
+		dir := filepath.Join(target, e.Name())
+		m, err := ReadManifest(dir)
+		if err != nil || m.Release != release || m.Kind != kind {
+			continue
+		}
+		all = append(all, found{dir, m})
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].man.CreatedAt.Before(all[j].man.CreatedAt) })
+
+	dirs := make([]string, len(all))
+	mans := make([]*Manifest, len(all))
+	for i, f := range all {
+		dirs[i], mans[i] = f.dir, f.man
+	}
+	return dirs, mans, nil
+}
+
+// Prune deletes the oldest backups of release and kind under target so that
+// at most keep remain, and returns the deleted directories. A backup another
+// kept backup increments from is kept too, so no chain is broken. A keep
+// below 1 deletes nothing.
+func Prune(target, release, kind string, keep int) ([]string, error) {
+	if keep < 1 {
+		return nil, nil
+	}
+	dirs, mans, err := List(target, release, kind)
+	if err != nil || len(dirs) <= keep {
+		return nil, err
+	}
+	needed := map[string]bool{}
+	for i := len(dirs) - keep; i < len(dirs); i++ {
+		chain, _, err := Chain(dirs[i])
+		if err != nil {
+			continue
+		}
+		for _, d := range chain {
+			needed[d] = true
+		}
+	}
+	var removed []string
+	for i, dir := range dirs[:len(dirs)-keep] {
+		if needed[dir] {
+			continue
+		}
+		logx.As().Info().Str("backup", dir).Time("created", mans[i].CreatedAt).Msg("Removing backup beyond retention")
+		if err := os.RemoveAll(dir); err != nil {
+			return removed, errorx.ExternalError.Wrap(err, "failed to remove backup %s", dir)
+		}
+		removed = append(removed, dir)
+	}
+	return removed, nil
+}
+
+// Chain returns the backup in dir preceded by every base it depends on,
+// oldest first. The chain ends at the first full backup.
+func Chain(dir string) ([]string, []*Manifest, error) {
+	var dirs []string
+	var mans []*Manifest
+	seen := map[string]bool{}
+	for cur := filepath.Clean(dir); ; {
+		if seen[cur] {
+			return nil, nil, errorx.IllegalFormat.New("backup %s has a cyclic base chain", dir)
+		}
+		seen[cur] = true
+		m, err := ReadManifest(cur)
+		if err != nil {
+			return nil, nil, errorx.Decorate(err, "backup chain of %s is broken", dir)
+		}
+		dirs = append([]string{cur}, dirs...)
+		mans = append([]*Manifest{m}, mans...)
+		if m.Base == "" {
+			return dirs, mans, nil
+		}
+		cur = filepath.Join(filepath.Dir(cur), m.Base)
+	}
+}
+
+// Verify checks the SHA-256 of every archive and index of the backup in dir
+// and of every base it depends on. It reads each archive once in full.
+func Verify(ctx context.Context, dir string) error {
+	dirs, mans, err := Chain(dir)
+	if err != nil {
+		return err
+	}
+	for i, d := range dirs {
+		for _, v := range mans[i].Volumes {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := verifyFile(filepath.Join(d, v.Archive), v.SHA256); err != nil {
+				return err
+			}
+			if v.Index != "" {
+				if err := verifyFile(filepath.Join(d, v.Index), v.IndexSHA256); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func verifyFile(path, want string) error {
+	got, err := fileSHA256(path)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return errorx.IllegalState.New("%s is corrupt: sha256 %s, manifest records %s", path, got, want)
+	}
+	return nil
+}
+
+// RestoreVolume repopulates dest from the named volume of the backup in dir.
+// dest must exist and should be empty. An incremental volume is rebuilt by
+// extracting its base chain oldest first, then pruning every file the final
+// index does not list.
+func RestoreVolume(ctx context.Context, dir, name, dest string) error {
+	dirs, mans, err := Chain(dir)
+	if err != nil {
+		return err
+	}
+	last := mans[len(mans)-1].Volume(name)
+	if last == nil {
+		return errorx.IllegalArgument.New("backup %s holds no %q volume", dir, name)
+	}
+
+	// Walk back to the full archive this volume's increments start from.
+	start := len(mans) - 1
+	for start > 0 {
+		v := mans[start].Volume(name)
+		if v == nil || !v.Incremental {
+			break
+		}
+		start--
+	}
+	for i := start; i < len(mans); i++ {
+		v := mans[i].Volume(name)
+		if v == nil {
+			return errorx.IllegalState.New("backup %s depends on %s, which holds no %q volume", dir, dirs[i], name)
+		}
+		logx.As().Info().Str("volume", name).Str("backup", dirs[i]).Str("dest", dest).
+			Msg("Extracting backup volume")
+		if err := extractArchive(ctx, filepath.Join(dirs[i], v.Archive), dest, v.SHA256); err != nil {
+			return err
+		}
+	}
+
+	if last.Incremental && last.Index != "" {
+		index, err := readIndex(filepath.Join(dirs[len(dirs)-1], last.Index), last.IndexSHA256)
+		if err != nil {
+			return err
+		}
+		if err := pruneToIndex(dest, index); err != nil {
+			return errorx.ExternalError.Wrap(err, "failed to prune %s to the backup index", dest)
+		}
+	}
+	return nil
+}
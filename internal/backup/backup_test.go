@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o640))
+	}
+}
+
+func readTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+	out := map[string]string{}
+	require.NoError(t, filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		require.NoError(t, err)
+		if d.Type()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(p)
+			require.NoError(t, err)
+			out[filepath.ToSlash(rel)] = "-> " + link
+			return nil
+		}
+		raw, err := os.ReadFile(p)
+		require.NoError(t, err)
+		out[filepath.ToSlash(rel)] = string(raw)
+		return nil
+	}))
+	return out
+}
+
+func clock(ts ...string) func() time.Time {
+	i := 0
+	return func() time.Time {
+		v, _ := time.Parse(time.RFC3339, ts[i])
+		i++
+		return v
+	}
+}
+
+func TestCreateAndRestore_FullRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	archive, live := filepath.Join(src, "archive"), filepath.Join(src, "live")
+	writeTree(t, archive, map[string]string{"0000/0001.zip": "blocks 1", "0000/0002.zip": "blocks 2"})
+	writeTree(t, live, map[string]string{"state/latest": "42"})
+	require.NoError(t, os.Symlink("state/latest", filepath.Join(live, "current")))
+
+	target := t.TempDir()
+	dir, man, err := Create(context.Background(), Options{
+		Target:       target,
+		Namespace:    "block-node",
+		Release:      "bn",
+		ChartVersion: "0.36.0",
+		Volumes: []Source{
+			{Name: "archive", Path: archive, AppendOnly: true},
+			{Name: "live", Path: live},
+			{Name: "plugins", Path: filepath.Join(src, "missing")},
+		},
+		Now: clock("2026-10-18T10:00:00Z"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(target, "bn-20261018T100000Z"), dir)
+	require.Len(t, man.Volumes, 2, "a missing directory is left out")
+	require.Equal(t, 2, man.Volume("archive").Files)
+	require.Equal(t, "archive.index", man.Volume("archive").Index)
+	require.Empty(t, man.Volume("live").Index, "only append-only volumes are indexed")
+	require.False(t, man.Incremental())
+
+	reread, err := ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, man, reread)
+	require.NoError(t, Verify(context.Background(), dir))
+
+	dest := t.TempDir()
+	require.NoError(t, RestoreVolume(context.Background(), dir, "live", dest))
+	require.Equal(t, map[string]string{"state/latest": "42", "current": "-> state/latest"}, readTree(t, dest))
+}
+
+func TestCreate_IncrementalArchiveTierAndChainRestore(t *testing.T) {
+	src := t.TempDir()
+	archive, live := filepath.Join(src, "archive"), filepath.Join(src, "live")
+	writeTree(t, archive, map[string]string{"a.zip": "A", "b.zip": "B"})
+	writeTree(t, live, map[string]string{"state": "v1"})
+	target := t.TempDir()
+	now := clock("2026-10-18T10:00:00Z", "2026-10-18T11:00:00Z", "2026-10-18T12:00:00Z")
+	opts := Options{
+		Target:  target,
+		Release: "bn",
+		Volumes: []Source{{Name: "archive", Path: archive, AppendOnly: true}, {Name: "live", Path: live}},
+		Now:     now,
+	}
+
+	full, _, err := Create(context.Background(), opts)
+	require.NoError(t, err)
+
+	// The node appends c.zip, grows b.zip and prunes a.zip.
+	writeTree(t, archive, map[string]string{"b.zip": "BB", "c.zip": "C"})
+	require.NoError(t, os.Remove(filepath.Join(archive, "a.zip")))
+	writeTree(t, live, map[string]string{"state": "v2"})
+
+	opts.Incremental = true
+	inc1, man1, err := Create(context.Background(), opts)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Base(full), man1.Base)
+	require.True(t, man1.Volume("archive").Incremental)
+	require.Equal(t, 2, man1.Volume("archive").Files, "only the grown and the new file are stored")
+	require.False(t, man1.Volume("live").Incremental, "the live tier is always full")
+
+	writeTree(t, archive, map[string]string{"d.zip": "D"})
+	inc2, man2, err := Create(context.Background(), opts)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Base(inc1), man2.Base)
+	require.Equal(t, 1, man2.Volume("archive").Files)
+
+	dirs, _, err := Chain(inc2)
+	require.NoError(t, err)
+	require.Equal(t, []string{full, inc1, inc2}, dirs)
+	require.NoError(t, Verify(context.Background(), inc2))
+
+	dest := t.TempDir()
+	require.NoError(t, RestoreVolume(context.Background(), inc2, "archive", dest))
+	require.Equal(t, map[string]string{"b.zip": "BB", "c.zip": "C", "d.zip": "D"}, readTree(t, dest),
+		"the chain is replayed and the file pruned in between is dropped")
+
+	dest = t.TempDir()
+	require.NoError(t, RestoreVolume(context.Background(), inc2, "live", dest))
+	require.Equal(t, map[string]string{"state": "v2"}, readTree(t, dest))
+}
+
+func TestCreate_IncrementalWithoutPriorBackupIsFull(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"a.zip": "A"})
+	_, man, err := Create(context.Background(), Options{
+		Target:      t.TempDir(),
+		Release:     "bn",
+		Volumes:     []Source{{Name: "archive", Path: src, AppendOnly: true}},
+		Incremental: true,
+	})
+	require.NoError(t, err)
+	require.Empty(t, man.Base)
+	require.False(t, man.Incremental())
+}
+
+func TestLatest_IgnoresPartialAndOtherReleases(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"a": "A"})
+	target := t.TempDir()
+	now := clock("2026-10-18T10:00:00Z", "2026-10-18T11:00:00Z", "2026-10-18T12:30:00Z")
+
+	want, _, err := Create(context.Background(), Options{Target: target, Release: "bn", Volumes: []Source{{Name: "live", Path: src}}, Now: now})
+	require.NoError(t, err)
+	_, _, err = Create(context.Background(), Options{Target: target, Release: "bn-2", Volumes: []Source{{Name: "live", Path: src}}, Now: now})
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(filepath.Join(target, "bn-20261018T120000Z.partial"), 0o750))
+	retired, _, err := Create(context.Background(), Options{Target: target, Release: "bn", Kind: "retired-storage", Volumes: []Source{{Name: "verification", Path: src}}, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, "bn-retired-storage-20261018T123000Z", filepath.Base(retired))
+
+	dir, man, err := Latest(target, "bn")
+	require.NoError(t, err)
+	require.Equal(t, want, dir)
+	require.Equal(t, "bn", man.Release)
+
+	_, man, err = Latest(filepath.Join(target, "nope"), "bn")
+	require.NoError(t, err)
+	require.Nil(t, man)
+}
+
+func TestVerify_DetectsCorruptArchive(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"a": "A"})
+	dir, man, err := Create(context.Background(), Options{Target: t.TempDir(), Release: "bn", Volumes: []Source{{Name: "live", Path: src}}})
+	require.NoError(t, err)
+
+	f, err := os.OpenFile(filepath.Join(dir, man.Volume("live").Archive), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("x")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	require.ErrorContains(t, Verify(context.Background(), dir), "is corrupt")
+	require.Error(t, RestoreVolume(context.Background(), dir, "live", t.TempDir()))
+}
+
+func TestReadManifest_RejectsUnsafeReferences(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ManifestFile),
+		[]byte(`{"formatVersion":1,"release":"bn","volumes":[{"name":"live","archive":"../../etc/passwd"}]}`), 0o600))
+	_, err := ReadManifest(dir)
+	require.ErrorContains(t, err, "outside the backup")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ManifestFile), []byte(`{"formatVersion":9}`), 0o600))
+	_, err = ReadManifest(dir)
+	require.ErrorContains(t, err, "manifest format 9")
+
+	_, err = ReadManifest(t.TempDir())
+	require.ErrorContains(t, err, "is not a backup")
+}
+
+func TestEntryPath_RejectsEscapes(t *testing.T) {
+	dst := t.TempDir()
+	_, err := entryPath(dst, "../outside")
+	require.Error(t, err)
+	_, err = entryPath(dst, "/etc/passwd")
+	require.Error(t, err)
+
+	require.NoError(t, os.Symlink("/etc", filepath.Join(dst, "link")))
+	_, err = entryPath(dst, "link/passwd")
+	require.ErrorContains(t, err, "traverses symlink")
+
+	p, err := entryPath(dst, "a/b/c")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dst, "a", "b", "c"), p)
+}
+
+func TestListAndPrune(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"snapshot.db": "etcd"})
+	target := t.TempDir()
+	now := clock("2026-10-18T12:00:00Z", "2026-10-18T10:00:00Z", "2026-10-18T11:00:00Z", "2026-10-18T13:00:00Z")
+
+	var created []string
+	for i := 0; i < 3; i++ {
+		dir, _, err := Create(context.Background(), Options{
+			Target:  target,
+			Release: "cluster",
+			Kind:    "etcd",
+			Volumes: []Source{{Name: "snapshot", Path: src}},
+			Now:     now,
+		})
+		require.NoError(t, err)
+		created = append(created, dir)
+	}
+	// A storage backup sharing the target is never listed or pruned with them.
+	other, _, err := Create(context.Background(), Options{
+		Target:  target,
+		Release: "cluster",
+		Volumes: []Source{{Name: "live", Path: src}},
+		Now:     now,
+	})
+	require.NoError(t, err)
+
+	dirs, mans, err := List(target, "cluster", "etcd")
+	require.NoError(t, err)
+	require.Equal(t, []string{created[1], created[2], created[0]}, dirs, "oldest first")
+	require.Len(t, mans, 3)
+
+	removed, err := Prune(target, "cluster", "etcd", 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{created[1]}, removed)
+	require.NoDirExists(t, created[1])
+	require.DirExists(t, other)
+
+	removed, err = Prune(target, "cluster", "etcd", 0)
+	require.NoError(t, err)
+	require.Empty(t, removed, "keep below 1 deletes nothing")
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package backup writes and reads directory backups.
+//
+// A backup is one directory holding a manifest.json and, per volume, a
+// zstd-compressed tar archive of the volume's host directory. Every archive
+// (and every file index) is recorded in the manifest with its SHA-256, so a
+// backup can be verified end to end before anything on disk is replaced.
+//
+// Volumes flagged append-only (e.g. the block node's archive tier) may be backed up
+// incrementally: the archive then holds only the files that are new or have
+// grown since the base backup, and the volume's file index records everything
+// present at backup time so a restore can replay the chain and drop files the
+// node had pruned in between.
+//
+// The package is layout agnostic: callers decide which volumes exist, where
+// they live and what Kind a backup is. Block node storage backups (see
+// internal/blocknode) and the cluster's etcd backups (see internal/kube/etcd)
+// are written with it.
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+const (
+	// ManifestFile is the name of the manifest inside a backup directory.
+	ManifestFile = "manifest.json"
+
+	// FormatVersion is the manifest format this package writes and reads.
+	FormatVersion = 1
+
+	archiveSuffix = ".tar.zst"
+	indexSuffix   = ".index"
+	partialSuffix = ".partial"
+
+	// timestampLayout names backup directories <release>-<timestamp>; it sorts
+	// lexically in creation order.
+	timestampLayout = "20060102T150405Z"
+)
+
+// Manifest indexes one backup.
+type Manifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Namespace     string    `json:"namespace"`
+	Release       string    `json:"release"`
+	// Kind is set by the caller to tell backups of the same release apart;
+	// Latest only considers backups without one.
+	Kind string `json:"kind,omitempty"`
+	// ChartVersion is the version of the chart the data was written by, if
+	// the caller records one.
+	ChartVersion string `json:"chartVersion"`
+	// Base is the directory name of the backup this one increments, a sibling
+	// in the same target directory. Empty for a full backup.
+	Base    string   `json:"base,omitempty"`
+	Volumes []Volume `json:"volumes"`
+}
+
+// Volume is one storage volume's entry in the manifest.
+type Volume struct {
+	// Name is the volume name, e.g. a block node storage volume ("archive",
+	// "live", ...) or a part of a cluster backup ("snapshot", "pki", ...).
+	Name string `json:"name"`
+	// SourcePath is the host directory the volume was read from.
+	SourcePath string `json:"sourcePath"`
+	// Archive is the archive's file name inside the backup directory.
+	Archive      string `json:"archive"`
+	SHA256       string `json:"sha256"`
+	ArchiveBytes int64  `json:"archiveBytes"`
+	// Files and Bytes count the regular files (and their uncompressed size)
+	// stored in this archive — for an incremental volume, only the new ones.
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+	// AppendOnly marks a volume whose files are only ever added (or grown) and
+	// pruned, never rewritten in place; only such volumes carry an index and
+	// may be incremental.
+	AppendOnly bool `json:"appendOnly,omitempty"`
+	// Incremental is set when the archive holds only files missing from, or
+	// larger than in, the base backup's index.
+	Incremental bool `json:"incremental,omitempty"`
+	// Index is the file name of the volume's full file listing at backup time.
+	Index       string `json:"index,omitempty"`
+	IndexSHA256 string `json:"indexSha256,omitempty"`
+}
+
+// Volume returns the manifest entry for the named volume, or nil.
+func (m *Manifest) Volume(name string) *Volume {
+	for i := range m.Volumes {
+		if m.Volumes[i].Name == name {
+			return &m.Volumes[i]
+		}
+	}
+	return nil
+}
+
+// Incremental reports whether any volume of the backup depends on its base.
+func (m *Manifest) Incremental() bool {
+	for _, v := range m.Volumes {
+		if v.Incremental {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadManifest reads and structurally validates the manifest of the backup in
+// dir. It does not verify archive checksums; see Verify.
+func ReadManifest(dir string) (*Manifest, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errorx.IllegalArgument.New("%s is not a backup (no %s)", dir, ManifestFile)
+		}
+		return nil, errorx.ExternalError.Wrap(err, "failed to read backup manifest in %s", dir)
+	}
+	var m Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "malformed backup manifest in %s", dir)
+	}
+	if m.FormatVersion != FormatVersion {
+		return nil, errorx.IllegalFormat.New(
+			"backup in %s has manifest format %d; this provisioner reads format %d", dir, m.FormatVersion, FormatVersion)
+	}
+	seen := map[string]bool{}
+	for _, v := range m.Volumes {
+		if v.Name == "" || seen[v.Name] {
+			return nil, errorx.IllegalFormat.New("backup manifest in %s lists volume %q twice or unnamed", dir, v.Name)
+		}
+		seen[v.Name] = true
+		for _, f := range []string{v.Archive, v.Index} {
+			if f != "" && (filepath.Base(f) != f || !filepath.IsLocal(f)) {
+				return nil, errorx.IllegalFormat.New("backup manifest in %s references file %q outside the backup", dir, f)
+			}
+		}
+		if v.Incremental && m.Base == "" {
+			return nil, errorx.IllegalFormat.New("backup manifest in %s has incremental volume %q but no base", dir, v.Name)
+		}
+	}
+	if m.Base != "" && (filepath.Base(m.Base) != m.Base || !filepath.IsLocal(m.Base)) {
+		return nil, errorx.IllegalFormat.New("backup manifest in %s names an invalid base %q", dir, m.Base)
+	}
+	return &m, nil
+}
+
+func writeManifest(dir string, m *Manifest) error {
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errorx.InternalError.Wrap(err, "failed to encode backup manifest")
+	}
+	f, err := os.OpenFile(filepath.Join(dir, ManifestFile), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to write backup manifest in %s", dir)
+	}
+	if _, err := f.Write(append(raw, '\n')); err != nil {
+		_ = f.Close()
+		return errorx.ExternalError.Wrap(err, "failed to write backup manifest in %s", dir)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return errorx.ExternalError.Wrap(err, "failed to sync backup manifest in %s", dir)
+	}
+	return f.Close()
+}
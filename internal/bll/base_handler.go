@@ -102,26 +102,7 @@ func (h *BaseHandler[T]) HandleIntent(
 	ac IntentHandler[T],
 	callback func(full *state.State, effInputs models.UserInputs[T]) error, // optional callback for applying additional mutations to the full state
 ) (*automa.Report, error) {
-	// ── 1. Validate intent and inputs ───────────────────────────────────────────────
-	err := h.ValidateIntent(intent, inputs, h.Target)
-	if err != nil {
-		return nil, err
-	}
-
-	// ── 2. Refresh runtime state ───────────────────────────────────────────────
-	// We need to refresh runtime before preparing effective inputs
-	currentState, err := h.Runtime.Refresh(ctx, true)
-	if err != nil {
-		return nil, err
-	}
-
-	// ── 3. Prepare effective inputs ───────────────────────────────────────────────
-	effectiveInputs, err := ac.PrepareEffectiveInputs(intent, inputs)
-	if err != nil {
-		return nil, err
-	}
-
-	wb, err := ac.BuildWorkflow(currentState, *effectiveInputs)
+	effectiveInputs, wb, err := h.PrepareWorkflow(ctx, intent, inputs, ac)
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +131,43 @@ func (h *BaseHandler[T]) HandleIntent(
 	)
 }
 
+// PrepareWorkflow performs the steps of HandleIntent that precede executing the
+// workflow: it validates the intent and inputs, refreshes the
+// runtime state, prepares the effective inputs and lets the per-action handler
+// check its preconditions in BuildWorkflow. Nothing is changed and no state is
+// flushed, so callers that only preview an intent (e.g. `--plan`) use it on its own.
+func (h *BaseHandler[T]) PrepareWorkflow(
+	ctx context.Context,
+	intent models.Intent,
+	inputs models.UserInputs[T],
+	ac IntentHandler[T],
+) (*models.UserInputs[T], *automa.WorkflowBuilder, error) {
+	// ── 1. Validate intent and inputs ───────────────────────────────────────────────
+	err := h.ValidateIntent(intent, inputs, h.Target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// ── 2. Refresh runtime state ───────────────────────────────────────────────
+	// We need to refresh runtime before preparing effective inputs
+	currentState, err := h.Runtime.Refresh(ctx, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// ── 3. Prepare effective inputs ───────────────────────────────────────────────
+	effectiveInputs, err := ac.PrepareEffectiveInputs(intent, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wb, err := ac.BuildWorkflow(currentState, *effectiveInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return effectiveInputs, wb, nil
+}
+
 // FlushState is the exported generic flush used by all node handlers.
 func (h *BaseHandler[T]) FlushState(
 	ctx context.Context,
@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// ApplyPlan is what `block node apply` runs to converge a block node to a
+// deployment file.
+type ApplyPlan struct {
+	// Action is install, upgrade or reconfigure.
+	Action models.ActionType
+	// Changes lists what differs from the deployed block node, empty when it
+	// already matches the deployment.
+	Changes []string
+}
+
+// UpToDate reports whether the block node already matches the deployment.
+func (p *ApplyPlan) UpToDate() bool {
+	return len(p.Changes) == 0
+}
+
+// PlanApply chooses the action that converges the block node to inputs, the
+// block node inputs of a deployment file, and hostFirewall, its host firewall
+// (nil keeps the recorded one):
+//   - install when the release is not deployed;
+//   - upgrade when the deployment asks for a newer chart version;
+//   - reconfigure when the effective inputs of a reconfigure differ from the
+//     deployed block node: the Helm values or storage migrations `reconfigure
+//     --plan` would show, or traffic shaping, the service endpoint or the host
+//     firewall as recorded in the state file.
+//
+// A reconfigure with nothing to change comes back with no Changes, which
+// apply takes as "up to date". An older chart version is refused: rolling
+// back is `block node rollback`, not a change of configuration.
+func (h *Handlers) PlanApply(
+	ctx context.Context,
+	inputs models.UserInputs[models.BlockNodeInputs],
+	hostFirewall *models.HostConfig,
+) (*ApplyPlan, error) {
+	currentState, err := h.reconfigure.Runtime.Refresh(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	current := currentState.BlockNode(inputs.Custom.Release)
+	if current.ReleaseInfo.Status != release.StatusDeployed {
+		return &ApplyPlan{Action: models.ActionInstall, Changes: []string{"block node is not installed"}}, nil
+	}
+
+	change, err := chartVersionChange(current.ReleaseInfo.ChartVersion, inputs.Custom.ChartVersion)
+	if err != nil {
+		return nil, err
+	}
+	if change != "" {
+		return &ApplyPlan{Action: models.ActionUpgrade, Changes: []string{change}}, nil
+	}
+
+	intent := models.Intent{Action: models.ActionReconfigure, Target: models.TargetBlockNode}
+	effective, _, err := h.reconfigure.PrepareWorkflow(ctx, intent, inputs, h.reconfigure)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := applyChanges(currentState, current, effective.Custom, hostFirewall)
+
+	manager, err := bnpkg.NewManager(effective.Custom)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := manager.PlanUpgrade(effective.Custom.Profile, effective.Custom.ValuesFile, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range plan.Values {
+		changes = append(changes, fmt.Sprintf("value %s %s", v.Kind, v.Path))
+	}
+	for _, m := range plan.Migrations {
+		changes = append(changes, "storage migration "+m.ID)
+	}
+
+	return &ApplyPlan{Action: models.ActionReconfigure, Changes: changes}, nil
+}
+
+// chartVersionChange returns the upgrade that moves the deployed chart version
+// to the desired one, "" when no version is desired or it is the deployed one,
+// and an error when it is older.
+func chartVersionChange(deployed, desired string) (string, error) {
+	if desired == "" || desired == deployed {
+		return "", nil
+	}
+	desiredVer, err := semver.NewVersion(desired)
+	if err != nil {
+		return "", errorx.IllegalArgument.New("invalid chart version %q: %v", desired, err)
+	}
+	deployedVer, err := semver.NewVersion(deployed)
+	if err != nil {
+		return "", errorx.IllegalState.New("failed to parse current chart version %q: %v", deployed, err)
+	}
+	switch {
+	case desiredVer.LessThan(deployedVer):
+		return "", errorx.IllegalArgument.New(
+			"block node chart version cannot be downgraded from %q to %q", deployed, desired).
+			WithProperty(models.ErrPropertyResolution, []string{
+				"set spec.chart.version to " + deployed + " or newer",
+				"use 'solo-provisioner block node rollback' to return to an earlier release",
+			})
+	case desiredVer.Equal(deployedVer):
+		return "", nil
+	default:
+		return fmt.Sprintf("chart version %s -> %s", deployed, desired), nil
+	}
+}
+
+// applyChanges lists what a reconfigure to ins changes that the Helm values do
+// not show: the service endpoint, traffic shaping and the host firewall, as
+// recorded in the state file. Empty content in ins keeps the recorded value,
+// as it does for reconfigure.
+func applyChanges(full state.State, current state.BlockNodeState, ins models.BlockNodeInputs, hostFirewall *models.HostConfig) []string {
+	var changes []string
+
+	if ins.ServicePort != 0 && ins.ServicePort != orDefault(current.ServicePort, models.DefaultBlockNodeServicePort) {
+		changes = append(changes, "service port")
+	}
+	if ins.LoadBalancerPool != "" && ins.LoadBalancerPool != orDefault(current.LoadBalancerPool, models.DefaultBlockNodeLoadBalancerPool) {
+		changes = append(changes, "load balancer pool")
+	}
+
+	shaped := !current.TrafficShapingDisabled
+	switch {
+	case ins.TrafficShapingEnabled != shaped:
+		changes = append(changes, fmt.Sprintf("traffic shaping %s", enabledWord(ins.TrafficShapingEnabled)))
+	case ins.TrafficShapingEnabled && current.Shaping != nil:
+		if ins.EgressInterface != "" && ins.EgressInterface != current.Shaping.EgressInterface {
+			changes = append(changes, "traffic shaping egress interface")
+		}
+		if ins.LinkRate != "" && ins.LinkRate != current.Shaping.LinkRate {
+			changes = append(changes, "traffic shaping link rate")
+		}
+		if len(ins.ShapeOverrides) > 0 && !reflect.DeepEqual(ins.ShapeOverrides, current.Shaping.ShapeOverrides) {
+			changes = append(changes, "traffic shaping overrides")
+		}
+	}
+
+	return append(changes, firewallChanges(full.MachineState.Firewall, hostFirewall)...)
+}
+
+// firewallChanges compares the desired host firewall with the recorded one. A
+// firewall never recorded counts as disabled.
+func firewallChanges(recorded *state.HostFirewallState, desired *models.HostConfig) []string {
+	if desired == nil {
+		return nil
+	}
+	enabled := recorded != nil && !recorded.Disabled
+	if desired.Disabled || !enabled {
+		if desired.Disabled == !enabled {
+			return nil
+		}
+		return []string{"host firewall " + enabledWord(!desired.Disabled)}
+	}
+
+	var changes []string
+	if !sameSet(desired.ManagementCIDRs, recorded.ManagementCIDRs) {
+		changes = append(changes, "host firewall management CIDRs")
+	}
+	if !sameSet(desired.BlockedCIDRs, recorded.BlockedCIDRs) {
+		changes = append(changes, "host firewall blocked CIDRs")
+	}
+	if desired.SSHPort != recorded.SSHPort {
+		changes = append(changes, "host firewall SSH port")
+	}
+	if desired.PodCIDR != recorded.PodCIDR {
+		changes = append(changes, "host firewall pod CIDR")
+	}
+	if !sameSet(desired.InClusterPorts, recorded.InClusterPorts) {
+		changes = append(changes, "host firewall in-cluster ports")
+	}
+	return changes
+}
+
+func sameSet[T ~string | ~int](a, b []T) bool {
+	as := make([]string, len(a))
+	for i, v := range a {
+		as[i] = strings.TrimSpace(fmt.Sprint(v))
+	}
+	bs := make([]string, len(b))
+	for i, v := range b {
+		bs[i] = strings.TrimSpace(fmt.Sprint(v))
+	}
+	slices.Sort(as)
+	slices.Sort(bs)
+	return slices.Equal(slices.Compact(as), slices.Compact(bs))
+}
+
+func orDefault[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+	return v
+}
+
+func enabledWord(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
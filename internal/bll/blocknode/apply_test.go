@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package blocknode
+
+import (
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartVersionChange(t *testing.T) {
+	change, err := chartVersionChange("0.39.1", "")
+	require.NoError(t, err)
+	assert.Empty(t, change, "no desired version keeps the deployed one")
+
+	change, err = chartVersionChange("0.39.1", "v0.39.1")
+	require.NoError(t, err)
+	assert.Empty(t, change)
+
+	change, err = chartVersionChange("0.39.1", "0.40.0")
+	require.NoError(t, err)
+	assert.Equal(t, "chart version 0.39.1 -> 0.40.0", change)
+
+	_, err = chartVersionChange("0.39.1", "0.38.0")
+	require.ErrorContains(t, err, "cannot be downgraded")
+}
+
+func TestApplyChanges_UpToDate(t *testing.T) {
+	full := state.State{}
+	full.MachineState.Firewall = &state.HostFirewallState{
+		ManagementCIDRs: []string{"10.0.0.0/8", "192.168.0.0/16"},
+		SSHPort:         22,
+		PodCIDR:         "10.244.0.0/16",
+		InClusterPorts:  []int{6443, 10250},
+	}
+	current := state.BlockNodeState{
+		Shaping: &state.ShapingState{EgressInterface: "eth0", LinkRate: "10gbit"},
+	}
+	ins := models.BlockNodeInputs{
+		ServicePort:           models.DefaultBlockNodeServicePort,
+		TrafficShapingEnabled: true,
+		LinkRate:              "10gbit",
+	}
+	desired := &models.HostConfig{
+		ManagementCIDRs: []string{"192.168.0.0/16", "10.0.0.0/8"},
+		SSHPort:         22,
+		PodCIDR:         "10.244.0.0/16",
+		InClusterPorts:  []int{10250, 6443},
+	}
+
+	assert.Empty(t, applyChanges(full, current, ins, desired))
+	assert.Empty(t, applyChanges(full, current, ins, nil), "a left-out firewall keeps the recorded one")
+}
+
+func TestApplyChanges(t *testing.T) {
+	current := state.BlockNodeState{
+		TrafficShapingDisabled: true,
+		ServicePort:            40841,
+	}
+	ins := models.BlockNodeInputs{
+		ServicePort:           models.DefaultBlockNodeServicePort,
+		LoadBalancerPool:      "bn-pool-2",
+		TrafficShapingEnabled: true,
+	}
+
+	assert.Equal(t, []string{
+		"service port",
+		"load balancer pool",
+		"traffic shaping enabled",
+		"host firewall enabled",
+	}, applyChanges(state.State{}, current, ins, &models.HostConfig{ManagementCIDRs: []string{"10.0.0.0/8"}}))
+}
+
+func TestFirewallChanges(t *testing.T) {
+	recorded := &state.HostFirewallState{ManagementCIDRs: []string{"10.0.0.0/8"}, SSHPort: 22}
+
+	assert.Empty(t, firewallChanges(nil, &models.HostConfig{Disabled: true}),
+		"a firewall never recorded is already disabled")
+	assert.Equal(t, []string{"host firewall disabled"},
+		firewallChanges(recorded, &models.HostConfig{Disabled: true}))
+	assert.Equal(t, []string{"host firewall management CIDRs", "host firewall SSH port"},
+		firewallChanges(recorded, &models.HostConfig{ManagementCIDRs: []string{"10.1.0.0/16"}, SSHPort: 2222}))
+}
+
+func TestApplyPlan_UpToDate(t *testing.T) {
+	assert.True(t, (&ApplyPlan{Action: models.ActionReconfigure}).UpToDate())
+	assert.False(t, (&ApplyPlan{Action: models.ActionInstall, Changes: []string{"block node is not installed"}}).UpToDate())
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+
+	"github.com/automa-saga/automa"
+	"github.com/hashgraph/solo-weaver/internal/bll"
+	"github.com/hashgraph/solo-weaver/internal/rsl"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// BackupHandler handles the ActionBackup intent for a block node. Backup
+// archives the node's storage volumes without changing them; it requires the
+// node to be deployed so its storage layout can be resolved from state.
+type BackupHandler struct {
+	bll.BaseHandler[models.BlockNodeInputs]
+	runtime *rsl.BlockNodeRuntimeResolver
+}
+
+// PrepareEffectiveInputs for backup resolves the deployed release, chart
+// version and storage paths from the current state.
+func (h *BackupHandler) PrepareEffectiveInputs(
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*models.UserInputs[models.BlockNodeInputs], error) {
+	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, nil)
+}
+
+// BuildWorkflow validates that the block node is deployed and a target is
+// set, and returns the backup workflow.
+func (h *BackupHandler) BuildWorkflow(
+	currentState state.State,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.WorkflowBuilder, error) {
+	if currentState.BlockNode(inputs.Custom.Release).ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
+		return nil, errorx.IllegalState.New(
+			"block node is not installed; cannot back up").
+			WithProperty(models.ErrPropertyResolution, "use 'solo-provisioner block node install' to install the "+
+				"block node first, or pass --force to continue")
+	}
+	if inputs.Custom.BackupTarget == "" {
+		return nil, errorx.IllegalArgument.New("a backup target directory is required").
+			WithProperty(models.ErrPropertyResolution, "pass --target <dir>")
+	}
+
+	wb := automa.NewWorkflowBuilder().WithId("block-node-backup").
+		Steps(steps.BackupBlockNode(inputs.Custom))
+	return wb, nil
+}
+
+// HandleIntent delegates to the shared BaseHandler which orchestrates all block-node intents.
+func (h *BackupHandler) HandleIntent(
+	ctx context.Context,
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.Report, error) {
+	return h.BaseHandler.HandleIntent(ctx, intent, inputs, h, patchBlockNodeState())
+}
+
+func NewBackupHandler(base bll.BaseHandler[models.BlockNodeInputs], runtimeState *rsl.BlockNodeRuntimeResolver) (*BackupHandler, error) {
+	return &BackupHandler{BaseHandler: base, runtime: runtimeState}, nil
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package blocknode
+
+import (
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func deployedBlockNode() state.State {
+	return state.State{
+		StateRecord: state.StateRecord{
+			BlockNodeState: state.BlockNodeState{
+				ReleaseInfo: state.HelmReleaseInfo{Status: release.StatusDeployed},
+			},
+		},
+	}
+}
+
+func backupRestoreInputs(target, from string) models.UserInputs[models.BlockNodeInputs] {
+	return models.UserInputs[models.BlockNodeInputs]{
+		Custom: models.BlockNodeInputs{
+			Namespace:    "block-node-ns",
+			Release:      "block-node",
+			ChartVersion: "0.36.0",
+			BackupTarget: target,
+			RestoreFrom:  from,
+		},
+	}
+}
+
+// TestBackup_QuiescesArchivesAndScalesBackUp verifies the backup workflow
+// scales the node down, archives storage, then brings the node back.
+func TestBackup_QuiescesArchivesAndScalesBackUp(t *testing.T) {
+	h := &BackupHandler{}
+
+	wb, err := h.BuildWorkflow(deployedBlockNode(), backupRestoreInputs("/mnt/backups", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "block-node-backup", wb.Id())
+	assert.Equal(t, []string{steps.BackupBlockNodeStepId}, workflowStepIDs(t, wb))
+	assert.Equal(t, []string{
+		steps.ScaleDownBlockNodeStepId,
+		steps.WaitForBlockNodeTerminatedStepId,
+		steps.BackupBlockNodeStorageStepId,
+		steps.ScaleUpBlockNodeStepId,
+		steps.WaitForBlockNodeStepId,
+	}, workflowStepIDs(t, steps.BackupBlockNode(backupRestoreInputs("/mnt/backups", "").Custom)))
+}
+
+// TestRestore_VerifiesBeforeQuiescing verifies the backup is checked while the
+// node is still up, before anything is scaled down or cleared.
+func TestRestore_VerifiesBeforeQuiescing(t *testing.T) {
+	h := &RestoreHandler{}
+
+	wb, err := h.BuildWorkflow(deployedBlockNode(), backupRestoreInputs("", "/mnt/backups/block-node-20261018T100000Z"))
+	require.NoError(t, err)
+	assert.Equal(t, "block-node-restore", wb.Id())
+	assert.Equal(t, []string{
+		steps.VerifyBlockNodeBackupStepId,
+		steps.ScaleDownBlockNodeStepId,
+		steps.WaitForBlockNodeTerminatedStepId,
+		steps.RestoreBlockNodeStorageStepId,
+		steps.ScaleUpBlockNodeStepId,
+		steps.WaitForBlockNodeStepId,
+	}, workflowStepIDs(t, steps.RestoreBlockNode(backupRestoreInputs("", "/x").Custom, false)))
+}
+
+func TestBackupRestore_Preconditions(t *testing.T) {
+	_, err := (&BackupHandler{}).BuildWorkflow(state.State{}, backupRestoreInputs("/mnt/backups", ""))
+	require.ErrorContains(t, err, "not installed")
+	_, err = (&BackupHandler{}).BuildWorkflow(deployedBlockNode(), backupRestoreInputs("", ""))
+	require.ErrorContains(t, err, "target directory is required")
+
+	_, err = (&RestoreHandler{}).BuildWorkflow(state.State{}, backupRestoreInputs("", "/x"))
+	require.ErrorContains(t, err, "not installed")
+	_, err = (&RestoreHandler{}).BuildWorkflow(deployedBlockNode(), backupRestoreInputs("", ""))
+	require.ErrorContains(t, err, "restore from is required")
+
+	forced := backupRestoreInputs("", "/x")
+	forced.Common.Force = true
+	_, err = (&RestoreHandler{}).BuildWorkflow(state.State{}, forced)
+	require.NoError(t, err)
+}
@@ -95,6 +95,34 @@ func TestResolveEffectiveInputs_CarriesStatusz(t *testing.T) {
 		"--statusz-poll-interval must be carried through effective-input resolution")
 }
 
+// TestResolveEffectiveInputs_CarriesStorageOperationInputs is a regression guard
+// (same class as the #912 timeout drop) for the backup, restore and storage gc
+// locations, which reach their workflows only through the effective inputs.
+func TestResolveEffectiveInputs_CarriesStorageOperationInputs(t *testing.T) {
+	checker := &fakeBlockNodeChecker{st: state.NewBlockNodeState()}
+	r, err := rsl.NewBlockNodeRuntimeResolver(models.Config{}, state.NewBlockNodeState(), checker, 10*time.Minute)
+	require.NoError(t, err)
+	runtime := r.(*rsl.BlockNodeRuntimeResolver)
+
+	inputs := baseTcInputs()
+	inputs.Custom.BackupTarget = "/mnt/backups"
+	inputs.Custom.BackupIncremental = true
+	inputs.Custom.RestoreFrom = "/mnt/backups/block-node-20260101T000000Z"
+	inputs.Custom.StorageGCArchiveTo = "/mnt/archive"
+
+	eff, err := resolveBlocknodeEffectiveInputs(
+		runtime,
+		models.Intent{Action: models.ActionInstall, Target: models.TargetBlockNode},
+		inputs,
+		nil,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt/backups", eff.Custom.BackupTarget)
+	assert.True(t, eff.Custom.BackupIncremental)
+	assert.Equal(t, "/mnt/backups/block-node-20260101T000000Z", eff.Custom.RestoreFrom)
+	assert.Equal(t, "/mnt/archive", eff.Custom.StorageGCArchiveTo)
+}
+
 // baseTcInputs returns valid inputs with the resolvable fields set but the
 // traffic-shaping content deliberately left empty, so the state fallback governs.
 func baseTcInputs() models.UserInputs[models.BlockNodeInputs] {
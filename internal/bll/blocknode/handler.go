@@ -19,11 +19,16 @@ import (
 
 // Handlers is the private struct that holds all per-action handlers for block-node intents.
 type Handlers struct {
-	install     *InstallHandler
-	upgrade     *UpgradeHandler
-	reconfigure *ReconfigureHandler
-	reset       *ResetHandler
-	uninstall   *UninstallHandler
+	install         *InstallHandler
+	upgrade         *UpgradeHandler
+	reconfigure     *ReconfigureHandler
+	reset           *ResetHandler
+	uninstall       *UninstallHandler
+	backup          *BackupHandler
+	restore         *RestoreHandler
+	storageGC       *StorageGCHandler
+	storageForecast *StorageForecastHandler
+	rollback        *RollbackHandler
 }
 
 // NewHandlerFactory validates dependencies and returns a Handlers with all handlers initialized.
@@ -81,12 +86,42 @@ func NewHandlerFactory(
 		return nil, errorx.IllegalArgument.New("failed to create UninstallHandler: %v", err)
 	}
 
+	backupHandler, err := NewBackupHandler(base, bnr)
+	if err != nil {
+		return nil, errorx.IllegalArgument.New("failed to create BackupHandler: %v", err)
+	}
+
+	restoreHandler, err := NewRestoreHandler(base, bnr)
+	if err != nil {
+		return nil, errorx.IllegalArgument.New("failed to create RestoreHandler: %v", err)
+	}
+
+	storageGCHandler, err := NewStorageGCHandler(base, bnr)
+	if err != nil {
+		return nil, errorx.IllegalArgument.New("failed to create StorageGCHandler: %v", err)
+	}
+
+	storageForecastHandler, err := NewStorageForecastHandler(base, bnr)
+	if err != nil {
+		return nil, errorx.IllegalArgument.New("failed to create StorageForecastHandler: %v", err)
+	}
+
+	rollbackHandler, err := NewRollbackHandler(base, bnr)
+	if err != nil {
+		return nil, errorx.IllegalArgument.New("failed to create RollbackHandler: %v", err)
+	}
+
 	h := &Handlers{
-		install:     installHandler,
-		upgrade:     upgradeHandler,
-		reconfigure: reconfigureHandler,
-		reset:       resetHandler,
-		uninstall:   uninstallHandler,
+		install:         installHandler,
+		upgrade:         upgradeHandler,
+		reconfigure:     reconfigureHandler,
+		reset:           resetHandler,
+		uninstall:       uninstallHandler,
+		backup:          backupHandler,
+		restore:         restoreHandler,
+		storageGC:       storageGCHandler,
+		storageForecast: storageForecastHandler,
+		rollback:        rollbackHandler,
 	}
 
 	return h, nil
@@ -107,6 +142,16 @@ func (h *Handlers) ForAction(
 		return h.reset, nil
 	case models.ActionUninstall:
 		return h.uninstall, nil
+	case models.ActionBackup:
+		return h.backup, nil
+	case models.ActionRestore:
+		return h.restore, nil
+	case models.ActionStorageGC:
+		return h.storageGC, nil
+	case models.ActionStorageForecast:
+		return h.storageForecast, nil
+	case models.ActionRollback:
+		return h.rollback, nil
 	default:
 		return nil, errorx.IllegalArgument.New("unsupported action %q for block node", action)
 	}
@@ -17,38 +17,54 @@ import (
 // release or PersistentVolumes into the runtime state before flushing to disk.
 //
 //   - ChartRef: the OCI / repo reference is not stored in Helm release metadata.
+//
 //   - Storage.BasePath: a weaver concept; Kubernetes only knows the individual PV
 //     hostPaths that the reality checker reads back. Without this patch, BasePath
 //     is lost after every FlushState → Refresh cycle, and the next interactive
 //     prompt would re-fill the PV-derived individual paths instead of the
 //     operator-chosen base path.
 //
+//   - ServicePort / LoadBalancerPool: the per-instance allocations resolved by
+//     resolveInstanceEndpoint, kept so the next command on this release (and
+//     the next instance installed beside it) sees them.
+//
+// Every field is written to the instance selected by the inputs' release (see
+// state.StateRecord.BlockNode).
+//
 // Profile persistence is handled centrally by BaseHandler.FlushState via ProfileExtractor.
 func patchBlockNodeState() func(st *state.State, effectiveInputs models.UserInputs[models.BlockNodeInputs]) error {
 	return func(st *state.State, effectiveInputs models.UserInputs[models.BlockNodeInputs]) error {
-		if st.BlockNodeState.ReleaseInfo.Status != release.StatusDeployed {
+		bn := st.BlockNode(effectiveInputs.Custom.Release)
+		if bn.ReleaseInfo.Status != release.StatusDeployed {
 			return nil
 		}
 		if effectiveInputs.Custom.Chart != "" {
 			logx.As().Debug().Str("chartRef", effectiveInputs.Custom.Chart).
 				Msg("Persisted block node chart ref into runtime state")
-			st.BlockNodeState.ReleaseInfo.ChartRef = effectiveInputs.Custom.Chart
+			bn.ReleaseInfo.ChartRef = effectiveInputs.Custom.Chart
 		}
 		if effectiveInputs.Custom.Storage.BasePath != "" {
 			logx.As().Debug().Str("basePath", effectiveInputs.Custom.Storage.BasePath).
 				Msg("Persisted block node storage base path into runtime state")
-			st.BlockNodeState.Storage.BasePath = effectiveInputs.Custom.Storage.BasePath
+			bn.Storage.BasePath = effectiveInputs.Custom.Storage.BasePath
 		}
 		if effectiveInputs.Custom.PluginPreset != "" {
 			logx.As().Debug().Str("pluginPreset", effectiveInputs.Custom.PluginPreset).
 				Msg("Persisted block node plugin preset into runtime state")
-			st.BlockNodeState.PluginPreset = effectiveInputs.Custom.PluginPreset
+			bn.PluginPreset = effectiveInputs.Custom.PluginPreset
 		}
 		if effectiveInputs.Custom.PluginList != "" {
 			logx.As().Debug().Str("pluginList", effectiveInputs.Custom.PluginList).
 				Msg("Persisted block node plugin list into runtime state")
-			st.BlockNodeState.PluginList = effectiveInputs.Custom.PluginList
+			bn.PluginList = effectiveInputs.Custom.PluginList
+		}
+		if effectiveInputs.Custom.ServicePort != 0 {
+			bn.ServicePort = effectiveInputs.Custom.ServicePort
+		}
+		if effectiveInputs.Custom.LoadBalancerPool != "" {
+			bn.LoadBalancerPool = effectiveInputs.Custom.LoadBalancerPool
 		}
+		st.SetBlockNode(effectiveInputs.Custom.Release, bn)
 		return nil
 	}
 }
@@ -67,8 +83,9 @@ func patchBlockNodeState() func(st *state.State, effectiveInputs models.UserInpu
 func patchBlockNodeStateWithTrafficShaping() func(st *state.State, effectiveInputs models.UserInputs[models.BlockNodeInputs]) error {
 	base := patchBlockNodeState()
 	return func(st *state.State, effectiveInputs models.UserInputs[models.BlockNodeInputs]) error {
-		st.BlockNodeState.TrafficShapingDisabled = !effectiveInputs.Custom.TrafficShapingEnabled
-		logx.As().Debug().Bool("trafficShapingDisabled", st.BlockNodeState.TrafficShapingDisabled).
+		bn := st.BlockNode(effectiveInputs.Custom.Release)
+		bn.TrafficShapingDisabled = !effectiveInputs.Custom.TrafficShapingEnabled
+		logx.As().Debug().Bool("trafficShapingDisabled", bn.TrafficShapingDisabled).
 			Msg("Persisted block node traffic-shaping decision into runtime state")
 
 		// Persist the host-firewall decision + content (host-scoped) so a later
@@ -83,8 +100,9 @@ func patchBlockNodeStateWithTrafficShaping() func(st *state.State, effectiveInpu
 		// overrides instead of auto-detecting. When disabled we leave any prior
 		// Shaping record intact (the decision on TrafficShapingDisabled governs re-assert).
 		if effectiveInputs.Custom.TrafficShapingEnabled {
-			patchBlockNodeShaping(st, effectiveInputs.Custom)
+			patchBlockNodeShaping(&bn, effectiveInputs.Custom)
 		}
+		st.SetBlockNode(effectiveInputs.Custom.Release, bn)
 		return base(st, effectiveInputs)
 	}
 }
@@ -121,7 +139,7 @@ func patchMachineFirewallFromConfig(st *state.State) {
 }
 
 // patchBlockNodeShaping records the resolved traffic-shaping content bundle into
-// BlockNodeState.Shaping so upgrade/reconfigure can re-assert the operator's
+// bn.Shaping so upgrade/reconfigure can re-assert the operator's
 // original egress NIC and link rate, and so the last --shape request stays on
 // record.
 //
@@ -132,12 +150,12 @@ func patchMachineFirewallFromConfig(st *state.State) {
 // reality.blocknode_checker, "cannot be recovered from the Helm release or the
 // live cluster"), and a routine reconfigure should not be the thing that drops
 // it. Current per-class values live in the shape registry either way.
-func patchBlockNodeShaping(st *state.State, ins models.BlockNodeInputs) {
+func patchBlockNodeShaping(bn *state.BlockNodeState, ins models.BlockNodeInputs) {
 	overrides := ins.ShapeOverrides
-	if len(overrides) == 0 && st.BlockNodeState.Shaping != nil {
-		overrides = st.BlockNodeState.Shaping.ShapeOverrides
+	if len(overrides) == 0 && bn.Shaping != nil {
+		overrides = bn.Shaping.ShapeOverrides
 	}
-	st.BlockNodeState.Shaping = &state.ShapingState{
+	bn.Shaping = &state.ShapingState{
 		EgressInterface: ins.EgressInterface,
 		LinkRate:        ins.LinkRate,
 		ShapeOverrides:  overrides,
@@ -270,11 +288,14 @@ func resolveBlocknodeEffectiveInputs(
 			// Passed through from user input (no resolution)
 			ValuesFile:            inputs.Custom.ValuesFile,
 			ReuseValues:           inputs.Custom.ReuseValues,
+			SkipValuesValidation:  inputs.Custom.SkipValuesValidation,
 			SkipHardwareChecks:    inputs.Custom.SkipHardwareChecks,
 			ResetStorage:          inputs.Custom.ResetStorage,
 			PurgeStorage:          inputs.Custom.PurgeStorage,
 			NoRestart:             inputs.Custom.NoRestart,
 			LoadBalancerEnabled:   inputs.Custom.LoadBalancerEnabled,
+			ServicePort:           inputs.Custom.ServicePort,
+			LoadBalancerPool:      inputs.Custom.LoadBalancerPool,
 			PluginPreset:          inputs.Custom.PluginPreset,
 			PluginList:            inputs.Custom.PluginList,
 			EgressInterface:       egressInterface,
@@ -284,6 +305,11 @@ func resolveBlocknodeEffectiveInputs(
 			Timeout:               inputs.Custom.Timeout,
 			StatuszBaseURL:        inputs.Custom.StatuszBaseURL,
 			StatuszPollInterval:   inputs.Custom.StatuszPollInterval,
+			BackupTarget:          inputs.Custom.BackupTarget,
+			BackupIncremental:     inputs.Custom.BackupIncremental,
+			RestoreFrom:           inputs.Custom.RestoreFrom,
+			StorageGCArchiveTo:    inputs.Custom.StorageGCArchiveTo,
+			RollbackRevision:      inputs.Custom.RollbackRevision,
 		},
 	}
 
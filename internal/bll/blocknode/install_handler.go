@@ -39,7 +39,7 @@ func (h *InstallHandler) PrepareEffectiveInputs(
 	intent models.Intent,
 	inputs models.UserInputs[models.BlockNodeInputs],
 ) (*models.UserInputs[models.BlockNodeInputs], error) {
-	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, nil)
+	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, instanceEndpointValidator(h.Runtime))
 }
 
 // BuildWorkflow validates install preconditions and returns the workflow.
@@ -49,7 +49,7 @@ func (h *InstallHandler) BuildWorkflow(
 	currentState state.State,
 	inputs models.UserInputs[models.BlockNodeInputs],
 ) (*automa.WorkflowBuilder, error) {
-	if currentState.BlockNodeState.ReleaseInfo.Status == release.StatusDeployed && !inputs.Common.Force {
+	if currentState.BlockNode(inputs.Custom.Release).ReleaseInfo.Status == release.StatusDeployed && !inputs.Common.Force {
 		return nil, errorx.IllegalState.New(
 			"block node is already installed; cannot install again").
 			WithProperty(models.ErrPropertyResolution,
@@ -78,7 +78,7 @@ func (h *InstallHandler) BuildWorkflow(
 	// disabled there is no inet weaver-workload-policy classification for the daemon to watch.
 	var daemonConfigStep []automa.Builder
 	if ins.TrafficShapingEnabled {
-		daemonConfigStep = []automa.Builder{workflows.BlockNodeDaemonConfigWorkflow(ins.Namespace, daemon.StatuszConfig{BaseURL: ins.StatuszBaseURL, PollInterval: ins.StatuszPollInterval})}
+		daemonConfigStep = []automa.Builder{workflows.BlockNodeDaemonConfigWorkflow(models.BlockNodeInstance(ins.Release), ins.Namespace, daemon.StatuszConfig{BaseURL: ins.StatuszBaseURL, PollInterval: ins.StatuszPollInterval})}
 	}
 
 	var wb *automa.WorkflowBuilder
@@ -90,12 +90,15 @@ func (h *InstallHandler) BuildWorkflow(
 			// verifies the binary exists, not the user account. This step is
 			// idempotent and safe to repeat on up-to-date installations.
 			steps.EnsureWeaverOwnerStep(),
+			// Advisory: warn when the volume sizes and retention cannot fit the
+			// disk behind the storage base path.
+			steps.CheckBlockNodeStorageCapacity(ins),
 			// Static network plane (host firewall + weaver policy persistence +
 			// $EGRESS/$VETH tc shape config), grouped as the "Network Setup" phase.
 			// The host firewall is owned by the block-node workflow (not the generic
 			// kube cluster install); nftables is already installed/enabled by prior
 			// cluster provisioning, so it's safe to apply here.
-			workflows.NetworkSetupWorkflow(ins.EgressInterface, ins.LinkRate, toClassOverrides(ins.ShapeOverrides), inputs.Common.Force, ins.TrafficShapingEnabled, healthPort),
+			workflows.NetworkSetupWorkflow(ins.EgressInterface, ins.LinkRate, toClassOverrides(ins.ShapeOverrides), inputs.Common.Force, ins.TrafficShapingEnabled, healthPort, models.BlockNodeInstance(ins.Release)),
 			steps.SetupBlockNode(ins),
 		}
 		stepList = append(stepList, daemonConfigStep...)
@@ -106,6 +109,7 @@ func (h *InstallHandler) BuildWorkflow(
 			// before the preflight check validates it. Older binaries did not
 			// create this account during self-install.
 			steps.EnsureWeaverOwnerStep(),
+			steps.CheckBlockNodeStorageCapacity(ins),
 			// Block node install owns its workload-sized preflight (block provider,
 			// profile, plugin preset) plus system setup, then stands up Kubernetes.
 			// InstallClusterWorkflow is intentionally not reused here: it validates
@@ -117,7 +121,7 @@ func (h *InstallHandler) BuildWorkflow(
 			// nftables was just installed/enabled by NodeSetupWorkflow's
 			// systemSetupWorkflow, so applying the host firewall here (rather than
 			// in that generic, node-type-agnostic workflow) is safe.
-			workflows.NetworkSetupWorkflow(ins.EgressInterface, ins.LinkRate, toClassOverrides(ins.ShapeOverrides), inputs.Common.Force, ins.TrafficShapingEnabled, healthPort),
+			workflows.NetworkSetupWorkflow(ins.EgressInterface, ins.LinkRate, toClassOverrides(ins.ShapeOverrides), inputs.Common.Force, ins.TrafficShapingEnabled, healthPort, models.BlockNodeInstance(ins.Release)),
 			steps.SetupBlockNode(ins),
 		}
 		stepList = append(stepList, daemonConfigStep...)
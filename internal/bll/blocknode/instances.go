@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"github.com/automa-saga/logx"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/rsl"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+)
+
+// instanceEndpointValidator is the resolveBlocknodeEffectiveInputs validator of
+// the commands that render the chart values (install, upgrade, reconfigure): it
+// applies resolveInstanceEndpoint against the state of every block node on the
+// host. A handler built without a runtime (unit tests) skips it.
+func instanceEndpointValidator(runtime *rsl.RuntimeResolver) func(*models.UserInputs[models.BlockNodeInputs]) error {
+	return func(in *models.UserInputs[models.BlockNodeInputs]) error {
+		if runtime == nil {
+			return nil
+		}
+		full, err := runtime.CurrentState()
+		if err != nil {
+			return err
+		}
+		return resolveInstanceEndpoint(full, &in.Custom)
+	}
+}
+
+// resolveInstanceEndpoint fills in the service port and LoadBalancer pool of
+// the block node deployed as ins.Release and makes sure it shares neither of
+// them, nor its namespace or storage paths, with any other block node recorded
+// on the host.
+//
+// An unset port or pool takes the value persisted for this release, then the
+// default; an unset port that another instance already uses moves up to the
+// next free one. Explicitly requested values that collide are rejected rather
+// than moved, as are a shared namespace and overlapping storage paths — two
+// releases there would fight over the same PersistentVolumes.
+func resolveInstanceEndpoint(full state.State, ins *models.BlockNodeInputs) error {
+	current := full.BlockNode(ins.Release)
+	portRequested := ins.ServicePort != 0
+	if ins.ServicePort == 0 {
+		ins.ServicePort = current.ServicePort
+	}
+	if ins.LoadBalancerPool == "" {
+		ins.LoadBalancerPool = current.LoadBalancerPool
+	}
+
+	ports := map[int]string{}
+	pools := map[string]string{}
+	for _, release := range full.BlockNodeReleases() {
+		if release == ins.Release {
+			continue
+		}
+		other := full.BlockNode(release)
+
+		if other.ReleaseInfo.Namespace != "" && other.ReleaseInfo.Namespace == ins.Namespace {
+			return errorx.IllegalArgument.New(
+				"namespace %q is already used by block node release %q", ins.Namespace, release).
+				WithProperty(models.ErrPropertyResolution,
+					"pass --namespace naming a namespace of its own for release "+ins.Release)
+		}
+		if path, ok := sharedStoragePath(other, *ins); ok {
+			return errorx.IllegalArgument.New(
+				"storage path %q is already used by block node release %q", path, release).
+				WithProperty(models.ErrPropertyResolution,
+					"pass --base-path (or the individual storage paths) naming a directory of its own for release "+ins.Release)
+		}
+
+		port := other.ServicePort
+		if port == 0 {
+			port = models.DefaultBlockNodeServicePort
+		}
+		ports[port] = release
+
+		pool := other.LoadBalancerPool
+		if pool == "" {
+			pool = models.DefaultBlockNodeLoadBalancerPool
+		}
+		pools[pool] = release
+	}
+
+	if ins.ServicePort == 0 {
+		ins.ServicePort = models.DefaultBlockNodeServicePort
+		portRequested = false
+	}
+	if owner, ok := ports[ins.ServicePort]; ok {
+		if portRequested {
+			return errorx.IllegalArgument.New(
+				"service port %d is already used by block node release %q", ins.ServicePort, owner).
+				WithProperty(models.ErrPropertyResolution, "pass a --service-port no other block node on this host uses")
+		}
+		for ports[ins.ServicePort] != "" {
+			ins.ServicePort++
+		}
+		logx.As().Info().Str("release", ins.Release).Int("servicePort", ins.ServicePort).
+			Msg("Allocated a block node service port not used by the other instances on this host")
+	}
+
+	if !ins.LoadBalancerEnabled {
+		return nil
+	}
+	if ins.LoadBalancerPool == "" {
+		ins.LoadBalancerPool = models.DefaultBlockNodeLoadBalancerPool
+	}
+	if owner, ok := pools[ins.LoadBalancerPool]; ok {
+		return errorx.IllegalArgument.New(
+			"LoadBalancer pool %q is already used by block node release %q", ins.LoadBalancerPool, owner).
+			WithProperty(models.ErrPropertyResolution,
+				"pass --load-balancer-pool naming a MetalLB IPAddressPool of its own, or --load-balancer-enabled=false")
+	}
+	return nil
+}
+
+// sharedStoragePath returns the first storage path of ins that overlaps one
+// other already uses: the same directory, or one nested in the other. Paths
+// that cannot be resolved on either side are not compared; the storage steps
+// report those.
+func sharedStoragePath(other state.BlockNodeState, ins models.BlockNodeInputs) (string, bool) {
+	oArchive, oLive, oLog, oOpt, err := bnpkg.ResolveStoragePaths(other.Storage, other.ReleaseInfo.ChartVersion)
+	if err != nil {
+		return "", false
+	}
+	archive, live, log, opt, err := bnpkg.ResolveStoragePaths(ins.Storage, ins.ChartVersion)
+	if err != nil {
+		return "", false
+	}
+
+	used := append([]string{oArchive, oLive, oLog}, oOpt...)
+	for _, p := range append([]string{archive, live, log}, opt...) {
+		if p == "" {
+			continue
+		}
+		for _, u := range used {
+			if u != "" && bnpkg.PathsOverlap(p, u) {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// otherInstanceShapes reports whether a block node other than release is
+// recorded on the host with traffic shaping on. The tc hierarchies and the nft
+// service are shared by every block node on the host, so tearing down one
+// instance's plane leaves them alone while another still uses them.
+func otherInstanceShapes(full state.State, release string) bool {
+	for _, other := range full.BlockNodeReleases() {
+		if other == release || models.BlockNodeInstance(other) == models.BlockNodeInstance(release) {
+			continue
+		}
+		if !full.BlockNode(other).TrafficShapingDisabled {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package blocknode
+
+import (
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// twoInstanceState records the default block node in namespace block-node and
+// a second release "testnet" on the given service port.
+func twoInstanceState(testnetPort int, shapingDisabled bool) state.State {
+	var st state.State
+	st.SetBlockNode("block-node", state.BlockNodeState{
+		ReleaseInfo: state.HelmReleaseInfo{Name: "block-node", Namespace: "block-node"},
+	})
+	st.SetBlockNode("testnet", state.BlockNodeState{
+		ReleaseInfo:            state.HelmReleaseInfo{Name: "testnet", Namespace: "block-node-testnet"},
+		ServicePort:            testnetPort,
+		LoadBalancerPool:       "testnet-pool",
+		TrafficShapingDisabled: shapingDisabled,
+	})
+	return st
+}
+
+func TestResolveInstanceEndpoint_AllocatesFreePort(t *testing.T) {
+	st := twoInstanceState(models.DefaultBlockNodeServicePort+1, false)
+	ins := models.BlockNodeInputs{Release: "mainnet", Namespace: "block-node-mainnet"}
+
+	require.NoError(t, resolveInstanceEndpoint(st, &ins))
+	assert.Equal(t, models.DefaultBlockNodeServicePort+2, ins.ServicePort)
+}
+
+func TestResolveInstanceEndpoint_DefaultInstanceKeepsDefaults(t *testing.T) {
+	st := twoInstanceState(models.DefaultBlockNodeServicePort+1, false)
+	ins := models.BlockNodeInputs{Release: "block-node", Namespace: "block-node", LoadBalancerEnabled: true}
+
+	require.NoError(t, resolveInstanceEndpoint(st, &ins))
+	assert.Equal(t, models.DefaultBlockNodeServicePort, ins.ServicePort)
+	assert.Equal(t, models.DefaultBlockNodeLoadBalancerPool, ins.LoadBalancerPool)
+}
+
+func TestResolveInstanceEndpoint_RejectsConflicts(t *testing.T) {
+	st := twoInstanceState(models.DefaultBlockNodeServicePort+1, false)
+
+	tests := []struct {
+		name    string
+		ins     models.BlockNodeInputs
+		wantErr string
+	}{
+		{
+			name:    "shared namespace",
+			ins:     models.BlockNodeInputs{Release: "mainnet", Namespace: "block-node-testnet"},
+			wantErr: "namespace",
+		},
+		{
+			name:    "requested port in use",
+			ins:     models.BlockNodeInputs{Release: "mainnet", Namespace: "ns", ServicePort: models.DefaultBlockNodeServicePort + 1},
+			wantErr: "service port",
+		},
+		{
+			name:    "pool in use",
+			ins:     models.BlockNodeInputs{Release: "mainnet", Namespace: "ns", LoadBalancerEnabled: true, LoadBalancerPool: "testnet-pool"},
+			wantErr: "LoadBalancer pool",
+		},
+		{
+			name:    "default pool in use",
+			ins:     models.BlockNodeInputs{Release: "mainnet", Namespace: "ns", LoadBalancerEnabled: true},
+			wantErr: "LoadBalancer pool",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ins := tt.ins
+			err := resolveInstanceEndpoint(st, &ins)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSharedStoragePath_CatchesNestedPaths(t *testing.T) {
+	other := state.BlockNodeState{Storage: models.BlockNodeStorage{BasePath: "/mnt/bn"}}
+
+	tests := []struct {
+		name    string
+		storage models.BlockNodeStorage
+		want    string
+		shared  bool
+	}{
+		{name: "own base path", storage: models.BlockNodeStorage{BasePath: "/mnt/mainnet"}},
+		{name: "identical path", storage: models.BlockNodeStorage{BasePath: "/mnt/mainnet", LivePath: "/mnt/bn/live"},
+			want: "/mnt/bn/live", shared: true},
+		{name: "nested in the other's path", storage: models.BlockNodeStorage{BasePath: "/mnt/mainnet", ArchivePath: "/mnt/bn/archive/mainnet"},
+			want: "/mnt/bn/archive/mainnet", shared: true},
+		{name: "containing the other's path", storage: models.BlockNodeStorage{BasePath: "/mnt/mainnet", LogPath: "/mnt/bn"},
+			want: "/mnt/bn", shared: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, shared := sharedStoragePath(other, models.BlockNodeInputs{Storage: tt.storage})
+			assert.Equal(t, tt.shared, shared)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOtherInstanceShapes(t *testing.T) {
+	st := twoInstanceState(0, false)
+	assert.True(t, otherInstanceShapes(st, "block-node"), "testnet still shapes")
+	assert.True(t, otherInstanceShapes(st, "testnet"), "the default instance still shapes")
+
+	st = twoInstanceState(0, true)
+	assert.False(t, otherInstanceShapes(st, "block-node"), "testnet has shaping off")
+
+	var single state.State
+	single.SetBlockNode("block-node", state.BlockNodeState{
+		ReleaseInfo: state.HelmReleaseInfo{Name: "block-node"},
+	})
+	assert.False(t, otherInstanceShapes(single, "block-node"))
+}
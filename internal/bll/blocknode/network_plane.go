@@ -29,9 +29,13 @@ import (
 //
 // trafficShapingEnabled is the resolved traffic-shaping target. force is the
 // operator's --force. healthPort is the resolved block-node health/statusz port.
-func networkPlaneSteps(ins models.BlockNodeInputs, force, trafficShapingEnabled, allowTeardown bool, healthPort string) []automa.Builder {
+// sharedShaping reports whether another block node on the host still shapes
+// its traffic (see otherInstanceShapes).
+func networkPlaneSteps(ins models.BlockNodeInputs, force, trafficShapingEnabled, allowTeardown, sharedShaping bool, healthPort string) []automa.Builder {
 	return workflows.NetworkPlaneSteps(workflows.NetworkPlaneOptions{
 		Force:                 force,
+		Instance:              models.BlockNodeInstance(ins.Release),
+		SharedShaping:         sharedShaping,
 		TrafficShapingEnabled: trafficShapingEnabled,
 		HealthPort:            healthPort,
 		Namespace:             ins.Namespace,
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+
+	"github.com/hashgraph/solo-weaver/internal/bll"
+	bnpkg "github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+)
+
+// Plan previews an upgrade or reconfigure intent: it resolves the effective
+// inputs and checks the action's preconditions exactly as HandleIntent would,
+// then reports what the workflow would change instead of running it. Neither
+// the cluster nor the state file is modified.
+func (h *Handlers) Plan(
+	ctx context.Context,
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*bnpkg.UpgradePlan, error) {
+	var (
+		base *bll.BaseHandler[models.BlockNodeInputs]
+		ac   bll.IntentHandler[models.BlockNodeInputs]
+	)
+	switch intent.Action {
+	case models.ActionUpgrade:
+		base, ac = &h.upgrade.BaseHandler, h.upgrade
+	case models.ActionReconfigure:
+		base, ac = &h.reconfigure.BaseHandler, h.reconfigure
+	default:
+		return nil, errorx.IllegalArgument.New("action %q of block node cannot be planned", intent.Action)
+	}
+
+	effective, _, err := base.PrepareWorkflow(ctx, intent, inputs, ac)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := bnpkg.NewManager(effective.Custom)
+	if err != nil {
+		return nil, err
+	}
+	return manager.PlanUpgrade(effective.Custom.Profile, effective.Custom.ValuesFile,
+		rollsOutRestart(intent.Action, effective.Custom))
+}
+
+// rollsOutRestart mirrors the reconfigure workflow's branches: only the plain
+// reconfigure, without --no-restart, restarts the pod after the upgrade.
+func rollsOutRestart(action models.ActionType, ins models.BlockNodeInputs) bool {
+	return action == models.ActionReconfigure && !ins.PurgeStorage && !ins.ResetStorage && !ins.NoRestart
+}
@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package blocknode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan_RejectsActionsWithoutPreview(t *testing.T) {
+	h := &Handlers{}
+	_, err := h.Plan(context.Background(),
+		models.Intent{Action: models.ActionInstall, Target: models.TargetBlockNode},
+		models.UserInputs[models.BlockNodeInputs]{})
+	require.ErrorContains(t, err, "cannot be planned")
+}
+
+func TestRollsOutRestart(t *testing.T) {
+	assert.True(t, rollsOutRestart(models.ActionReconfigure, models.BlockNodeInputs{}))
+	assert.False(t, rollsOutRestart(models.ActionReconfigure, models.BlockNodeInputs{NoRestart: true}))
+	assert.False(t, rollsOutRestart(models.ActionReconfigure, models.BlockNodeInputs{ResetStorage: true}),
+		"a storage reset restarts by scaling down and up, not by a rollout restart")
+	assert.False(t, rollsOutRestart(models.ActionReconfigure, models.BlockNodeInputs{PurgeStorage: true}))
+	assert.False(t, rollsOutRestart(models.ActionUpgrade, models.BlockNodeInputs{}))
+}
@@ -31,7 +31,7 @@ func (h *ReconfigureHandler) PrepareEffectiveInputs(
 	intent models.Intent,
 	inputs models.UserInputs[models.BlockNodeInputs],
 ) (*models.UserInputs[models.BlockNodeInputs], error) {
-	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, nil)
+	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, instanceEndpointValidator(h.Runtime))
 }
 
 // BuildWorkflow validates reconfigure preconditions and returns the workflow.
@@ -41,7 +41,8 @@ func (h *ReconfigureHandler) BuildWorkflow(
 	currentState state.State,
 	inputs models.UserInputs[models.BlockNodeInputs],
 ) (*automa.WorkflowBuilder, error) {
-	if currentState.BlockNodeState.ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
+	current := currentState.BlockNode(inputs.Custom.Release)
+	if current.ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
 		return nil, errorx.IllegalState.New(
 			"block node is not installed; cannot reconfigure").
 			WithProperty(models.ErrPropertyResolution,
@@ -70,7 +71,11 @@ func (h *ReconfigureHandler) BuildWorkflow(
 	// fresh (in the CLI layer, seeded from the current on-host state), so the
 	// convergence assembler is driven by the operator's decision and is allowed to
 	// tear a feature down when it is turned off. See networkPlaneSteps.
-	networkSteps := networkPlaneSteps(ins, inputs.Common.Force, ins.TrafficShapingEnabled, true, healthPort)
+	// It is preceded by the advisory storage capacity check, which warns when the
+	// (possibly new) volume sizes and retention cannot fit the disk.
+	networkSteps := append([]automa.Builder{steps.CheckBlockNodeStorageCapacity(ins)},
+		networkPlaneSteps(ins, inputs.Common.Force, ins.TrafficShapingEnabled, true,
+			otherInstanceShapes(currentState, ins.Release), healthPort)...)
 
 	var wb *automa.WorkflowBuilder
 	switch {
@@ -81,7 +86,7 @@ func (h *ReconfigureHandler) BuildWorkflow(
 		// same in both old and new inputs, so ScaleStatefulSet / WaitForPodsTerminated
 		// are unaffected.
 		oldIns := ins
-		oldIns.Storage = currentState.BlockNodeState.Storage
+		oldIns.Storage = current.Storage
 
 		// After purging old dirs, recreate PVs/PVCs and create new directories at
 		// the new paths, then upgrade the chart.
@@ -94,7 +99,7 @@ func (h *ReconfigureHandler) BuildWorkflow(
 	case ins.ResetStorage:
 		// --with-reset wipes data only; PVs/PVCs are preserved. Storage paths must
 		// not have changed because local-PV hostPath is immutable.
-		changed, err := storagePathsChanged(currentState.BlockNodeState.Storage, ins)
+		changed, err := storagePathsChanged(current.Storage, ins)
 		if err != nil {
 			return nil, errorx.IllegalState.Wrap(err, "failed to compare storage paths")
 		}
@@ -106,7 +111,7 @@ func (h *ReconfigureHandler) BuildWorkflow(
 		}
 
 		oldIns := ins
-		oldIns.Storage = currentState.BlockNodeState.Storage
+		oldIns.Storage = current.Storage
 		stepList := append(networkSteps,
 			steps.PurgeBlockNodeStorage(oldIns),
 			steps.UpgradeBlockNode(ins),
@@ -115,7 +120,7 @@ func (h *ReconfigureHandler) BuildWorkflow(
 	default:
 		// For non-reset reconfigures, storage path changes require --purge-storage because
 		// existing PVs/PVCs cannot be mutated in-place; block with a clear error.
-		changed, err := storagePathsChanged(currentState.BlockNodeState.Storage, ins)
+		changed, err := storagePathsChanged(current.Storage, ins)
 		if err != nil {
 			return nil, errorx.IllegalState.Wrap(err, "failed to compare storage paths")
 		}
@@ -88,15 +88,17 @@ func reconfigureInputsTS(basePath string, trafficShapingEnabled bool) models.Use
 	}
 }
 
-// enableNetworkPrefix is the network-plane step prefix the convergence assembler
-// emits when traffic shaping is enabled and the host firewall is not being torn
+// enableNetworkPrefix is the storage capacity check followed by the
+// network-plane step prefix the convergence assembler emits when traffic shaping is enabled and the host firewall is not being torn
 // down (the default in these tests, where config.Host.Disabled is false).
 var enableNetworkPrefix = []string{
+	steps.CheckBlockNodeStorageCapacityStepId,
 	steps.NetworkFirewallCreateStepId,
 	steps.NetworkPolicyCreateStepId,
 	steps.NftWeaverPersistStepId,
 	steps.TcEgressPersistStepId,
 	steps.TcIngressRecordStepId,
+	steps.NetworkExporterSetupStepId,
 	steps.BlockNodeDaemonConfigStepId,
 	steps.RestartDaemonServiceStepId,
 }
@@ -224,9 +226,11 @@ func TestBuildWorkflow_TrafficShapingDisabled_TearsDown(t *testing.T) {
 
 	ids := workflowStepIDs(t, wb)
 	assert.Equal(t, []string{
+		steps.CheckBlockNodeStorageCapacityStepId,
 		steps.NetworkFirewallCreateStepId,
 		steps.NetworkPolicyDeleteAllStepId,
 		steps.TcEgressTeardownStepId,
+		steps.NetworkExporterTeardownStepId,
 		steps.BlockNodeDaemonConfigStepId,
 		steps.RestartDaemonServiceStepId,
 		steps.UpgradeBlockNodeStepId,
@@ -249,8 +253,8 @@ func TestBuildWorkflow_FirewallDisabled_DeletesTable(t *testing.T) {
 	require.NoError(t, err)
 
 	ids := workflowStepIDs(t, wb)
-	assert.Equal(t, steps.NetworkFirewallDeleteStepId, ids[0],
-		"host firewall disabled should emit the delete step first")
+	assert.Equal(t, steps.NetworkFirewallDeleteStepId, ids[1],
+		"host firewall disabled should emit the delete step first, after the storage capacity check")
 }
 
 // TestBuildWorkflow_NoReset_ChangedPathsReturnsError verifies that changing
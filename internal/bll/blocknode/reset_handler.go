@@ -40,7 +40,7 @@ func (h *ResetHandler) BuildWorkflow(
 	currentState state.State,
 	inputs models.UserInputs[models.BlockNodeInputs],
 ) (*automa.WorkflowBuilder, error) {
-	if currentState.BlockNodeState.ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
+	if currentState.BlockNode(inputs.Custom.Release).ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
 		return nil, errorx.IllegalState.New(
 			"block node is not installed; cannot reset").
 			WithProperty(models.ErrPropertyResolution, "use 'solo-provisioner block node install' to install the "+
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+
+	"github.com/automa-saga/automa"
+	"github.com/hashgraph/solo-weaver/internal/bll"
+	"github.com/hashgraph/solo-weaver/internal/rsl"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// RestoreHandler handles the ActionRestore intent for a block node. Restore
+// replaces the deployed node's storage with a backup; the chart version the
+// backup is checked against is the deployed one from the current state.
+type RestoreHandler struct {
+	bll.BaseHandler[models.BlockNodeInputs]
+	runtime *rsl.BlockNodeRuntimeResolver
+}
+
+// PrepareEffectiveInputs for restore resolves the deployed release, chart
+// version and storage paths from the current state.
+func (h *RestoreHandler) PrepareEffectiveInputs(
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*models.UserInputs[models.BlockNodeInputs], error) {
+	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, nil)
+}
+
+// BuildWorkflow validates that the block node is deployed and a backup is
+// named, and returns the restore workflow. --force is forwarded to the
+// backup compatibility check as well.
+func (h *RestoreHandler) BuildWorkflow(
+	currentState state.State,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.WorkflowBuilder, error) {
+	if currentState.BlockNode(inputs.Custom.Release).ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
+		return nil, errorx.IllegalState.New(
+			"block node is not installed; cannot restore").
+			WithProperty(models.ErrPropertyResolution, "use 'solo-provisioner block node install' to install the "+
+				"block node first, or pass --force to continue")
+	}
+	if inputs.Custom.RestoreFrom == "" {
+		return nil, errorx.IllegalArgument.New("a backup directory to restore from is required").
+			WithProperty(models.ErrPropertyResolution, "pass --from <backup-dir>")
+	}
+
+	wb := automa.NewWorkflowBuilder().WithId("block-node-restore").
+		Steps(steps.RestoreBlockNode(inputs.Custom, inputs.Common.Force))
+	return wb, nil
+}
+
+// HandleIntent delegates to the shared BaseHandler which orchestrates all block-node intents.
+func (h *RestoreHandler) HandleIntent(
+	ctx context.Context,
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.Report, error) {
+	return h.BaseHandler.HandleIntent(ctx, intent, inputs, h, patchBlockNodeState())
+}
+
+func NewRestoreHandler(base bll.BaseHandler[models.BlockNodeInputs], runtimeState *rsl.BlockNodeRuntimeResolver) (*RestoreHandler, error) {
+	return &RestoreHandler{BaseHandler: base, runtime: runtimeState}, nil
+}
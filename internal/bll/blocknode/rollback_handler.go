@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+
+	"github.com/automa-saga/automa"
+	"github.com/hashgraph/solo-weaver/internal/bll"
+	"github.com/hashgraph/solo-weaver/internal/rsl"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// RollbackHandler handles the ActionRollback intent for a block node. The
+// revision to return to, and whether the storage allows it, are only known
+// from the live Helm release history, so both are settled by the rollback
+// step; the handler checks what the state can tell. The chart version the
+// release ends up at is picked up by the post-workflow state refresh.
+type RollbackHandler struct {
+	bll.BaseHandler[models.BlockNodeInputs]
+	runtime *rsl.BlockNodeRuntimeResolver
+}
+
+// PrepareEffectiveInputs for rollback resolves the deployed release, namespace
+// and storage paths from the current state.
+func (h *RollbackHandler) PrepareEffectiveInputs(
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*models.UserInputs[models.BlockNodeInputs], error) {
+	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, nil)
+}
+
+// BuildWorkflow validates that the block node is deployed and returns the
+// rollback workflow. --force is forwarded to the rollback plan, where it lets
+// the rollback cross a storage boundary that does not support one.
+func (h *RollbackHandler) BuildWorkflow(
+	currentState state.State,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.WorkflowBuilder, error) {
+	if currentState.BlockNode(inputs.Custom.Release).ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
+		return nil, errorx.IllegalState.New(
+			"block node is not installed; nothing to roll back").
+			WithProperty(models.ErrPropertyResolution, "use 'solo-provisioner block node install' to install the "+
+				"block node first, or pass --force to continue")
+	}
+
+	wb := automa.NewWorkflowBuilder().WithId("block-node-rollback").
+		Steps(steps.RollbackBlockNode(inputs.Custom, inputs.Common.Force))
+	return wb, nil
+}
+
+// HandleIntent delegates to the shared BaseHandler which orchestrates all block-node intents.
+func (h *RollbackHandler) HandleIntent(
+	ctx context.Context,
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.Report, error) {
+	return h.BaseHandler.HandleIntent(ctx, intent, inputs, h, patchBlockNodeState())
+}
+
+func NewRollbackHandler(base bll.BaseHandler[models.BlockNodeInputs], runtimeState *rsl.BlockNodeRuntimeResolver) (*RollbackHandler, error) {
+	return &RollbackHandler{BaseHandler: base, runtime: runtimeState}, nil
+}
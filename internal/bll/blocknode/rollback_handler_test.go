@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package blocknode
+
+import (
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollback_BuildWorkflow(t *testing.T) {
+	h := &RollbackHandler{}
+
+	wb, err := h.BuildWorkflow(deployedBlockNode(), backupRestoreInputs("", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "block-node-rollback", wb.Id())
+	assert.Equal(t, []string{steps.RollbackBlockNodeStepId}, workflowStepIDs(t, wb))
+	assert.Equal(t, []string{
+		"ensure-hedera-owner",
+		steps.RollbackBlockNodeChartStepId,
+		steps.WaitForBlockNodeStepId,
+		steps.VerifyBlockNodeReachableStepId,
+	}, workflowStepIDs(t, steps.RollbackBlockNode(backupRestoreInputs("", "").Custom, false)))
+
+	_, err = h.BuildWorkflow(state.State{}, backupRestoreInputs("", ""))
+	require.ErrorContains(t, err, "not installed")
+
+	forced := backupRestoreInputs("", "")
+	forced.Common.Force = true
+	_, err = h.BuildWorkflow(state.State{}, forced)
+	require.NoError(t, err)
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+
+	"github.com/automa-saga/automa"
+	"github.com/hashgraph/solo-weaver/internal/bll"
+	"github.com/hashgraph/solo-weaver/internal/rsl"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// StorageForecastHandler handles the ActionStorageForecast intent for a block
+// node. It records a sample of the storage volumes' on-disk size; projecting
+// the samples is the caller's job, as the forecast changes nothing.
+type StorageForecastHandler struct {
+	bll.BaseHandler[models.BlockNodeInputs]
+	runtime *rsl.BlockNodeRuntimeResolver
+}
+
+// PrepareEffectiveInputs for storage forecast resolves the deployed storage
+// paths and retention thresholds from the current state.
+func (h *StorageForecastHandler) PrepareEffectiveInputs(
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*models.UserInputs[models.BlockNodeInputs], error) {
+	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, nil)
+}
+
+// BuildWorkflow returns the storage sampling workflow. The block node need not
+// be installed: before install the forecast checks the configured layout
+// against the empty disk.
+func (h *StorageForecastHandler) BuildWorkflow(
+	currentState state.State,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.WorkflowBuilder, error) {
+	wb := automa.NewWorkflowBuilder().WithId("block-node-storage-forecast").
+		Steps(steps.RecordBlockNodeStorageSample(inputs.Custom))
+	return wb, nil
+}
+
+// HandleIntent delegates to the shared BaseHandler which orchestrates all block-node intents.
+func (h *StorageForecastHandler) HandleIntent(
+	ctx context.Context,
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.Report, error) {
+	return h.BaseHandler.HandleIntent(ctx, intent, inputs, h, patchBlockNodeState())
+}
+
+func NewStorageForecastHandler(base bll.BaseHandler[models.BlockNodeInputs], runtimeState *rsl.BlockNodeRuntimeResolver) (*StorageForecastHandler, error) {
+	return &StorageForecastHandler{BaseHandler: base, runtime: runtimeState}, nil
+}
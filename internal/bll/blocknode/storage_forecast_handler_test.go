@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package blocknode
+
+import (
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageForecast_BuildWorkflow(t *testing.T) {
+	h := &StorageForecastHandler{}
+
+	// Forecasting does not need an installed block node.
+	for _, st := range []state.State{deployedBlockNode(), {}} {
+		wb, err := h.BuildWorkflow(st, backupRestoreInputs("", ""))
+		require.NoError(t, err)
+		assert.Equal(t, "block-node-storage-forecast", wb.Id())
+		assert.Equal(t, []string{steps.StorageForecastBlockNodeStepId}, workflowStepIDs(t, wb))
+	}
+	assert.Equal(t, []string{steps.RecordBlockNodeStorageSampleStepId},
+		workflowStepIDs(t, steps.RecordBlockNodeStorageSample(backupRestoreInputs("", "").Custom)))
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+
+	"github.com/automa-saga/automa"
+	"github.com/hashgraph/solo-weaver/internal/bll"
+	"github.com/hashgraph/solo-weaver/internal/rsl"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// StorageGCHandler handles the ActionStorageGC intent for a block node. It
+// collects the PV/PVCs and host directories of optional storage the installed
+// chart version has retired. Confirmation is the caller's job: by the time the
+// intent is handled the operator has agreed to the deletion.
+type StorageGCHandler struct {
+	bll.BaseHandler[models.BlockNodeInputs]
+	runtime *rsl.BlockNodeRuntimeResolver
+}
+
+// PrepareEffectiveInputs for storage gc resolves the deployed release, chart
+// version and storage paths from the current state.
+func (h *StorageGCHandler) PrepareEffectiveInputs(
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*models.UserInputs[models.BlockNodeInputs], error) {
+	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, nil)
+}
+
+// BuildWorkflow validates that the block node is deployed and returns the
+// storage gc workflow.
+func (h *StorageGCHandler) BuildWorkflow(
+	currentState state.State,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.WorkflowBuilder, error) {
+	if currentState.BlockNode(inputs.Custom.Release).ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
+		return nil, errorx.IllegalState.New(
+			"block node is not installed; cannot tell which storage is retired").
+			WithProperty(models.ErrPropertyResolution, "use 'solo-provisioner block node install' to install the "+
+				"block node first, or pass --force to judge by the configured chart version")
+	}
+
+	wb := automa.NewWorkflowBuilder().WithId("block-node-storage-gc").
+		Steps(steps.CollectRetiredBlockNodeStorage(inputs.Custom))
+	return wb, nil
+}
+
+// HandleIntent delegates to the shared BaseHandler which orchestrates all block-node intents.
+func (h *StorageGCHandler) HandleIntent(
+	ctx context.Context,
+	intent models.Intent,
+	inputs models.UserInputs[models.BlockNodeInputs],
+) (*automa.Report, error) {
+	return h.BaseHandler.HandleIntent(ctx, intent, inputs, h, patchBlockNodeState())
+}
+
+func NewStorageGCHandler(base bll.BaseHandler[models.BlockNodeInputs], runtimeState *rsl.BlockNodeRuntimeResolver) (*StorageGCHandler, error) {
+	return &StorageGCHandler{BaseHandler: base, runtime: runtimeState}, nil
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package blocknode
+
+import (
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageGC_BuildWorkflow(t *testing.T) {
+	h := &StorageGCHandler{}
+
+	wb, err := h.BuildWorkflow(deployedBlockNode(), backupRestoreInputs("", ""))
+	require.NoError(t, err)
+	assert.Equal(t, "block-node-storage-gc", wb.Id())
+	assert.Equal(t, []string{steps.StorageGCBlockNodeStepId}, workflowStepIDs(t, wb))
+	assert.Equal(t, []string{steps.CollectRetiredBlockNodeStorageStepId},
+		workflowStepIDs(t, steps.CollectRetiredBlockNodeStorage(backupRestoreInputs("", "").Custom)))
+
+	_, err = h.BuildWorkflow(state.State{}, backupRestoreInputs("", ""))
+	require.ErrorContains(t, err, "not installed")
+
+	forced := backupRestoreInputs("", "")
+	forced.Common.Force = true
+	_, err = h.BuildWorkflow(state.State{}, forced)
+	require.NoError(t, err)
+}
@@ -38,7 +38,7 @@ func (h *UninstallHandler) BuildWorkflow(
 	currentState state.State,
 	inputs models.UserInputs[models.BlockNodeInputs],
 ) (*automa.WorkflowBuilder, error) {
-	if currentState.BlockNodeState.ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
+	if currentState.BlockNode(inputs.Custom.Release).ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
 		return nil, errorx.IllegalState.New(
 			"block node is not installed; cannot uninstall").
 			WithProperty(models.ErrPropertyResolution,
@@ -46,6 +46,7 @@ func (h *UninstallHandler) BuildWorkflow(
 	}
 
 	ins := inputs.Custom
+	instance := models.BlockNodeInstance(ins.Release)
 
 	// withPlaneTeardown brackets the chart-removal steps with the static-plane
 	// teardown, in the reverse of install order: the daemon's traffic-shaper
@@ -72,16 +73,22 @@ func (h *UninstallHandler) BuildWorkflow(
 	// installed or was already removed, so this runs unconditionally.
 	withPlaneTeardown := func(chartSteps ...automa.Builder) []automa.Builder {
 		out := []automa.Builder{
-			steps.WriteBlockNodeDaemonConfigStep(models.Paths(), ins.Namespace, daemon.StatuszConfig{}, false),
+			steps.WriteBlockNodeInstanceDaemonConfigStep(models.Paths(), instance, ins.Namespace, daemon.StatuszConfig{}, false),
 			steps.RestartDaemonServiceStep(),
 		}
 		out = append(out, chartSteps...)
+		out = append(out, steps.NetworkPolicyDeleteAll(instance))
+		if otherInstanceShapes(currentState, ins.Release) {
+			// The nft service and the tc hierarchies are host-wide; another
+			// block node still runs on them.
+			return out
+		}
 		return append(out,
-			steps.NetworkPolicyDeleteAll(),
 			steps.NftServiceTeardown(),
 			steps.TcEgressTeardown(),
 			steps.TcEgressServiceTeardown(),
 			steps.TcIngressTeardown(),
+			steps.NetworkExporterTeardown(),
 		)
 	}
 
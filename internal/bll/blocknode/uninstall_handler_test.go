@@ -63,6 +63,7 @@ func TestUninstall_NoFlags_HelmOnly(t *testing.T) {
 		steps.TcEgressTeardownStepId,
 		steps.TcEgressServiceTeardownStepId,
 		steps.TcIngressTeardownStepId,
+		steps.NetworkExporterTeardownStepId,
 	}, workflowStepIDs(t, wb))
 }
 
@@ -94,6 +95,7 @@ func TestUninstall_WithReset_PurgeThenUninstall(t *testing.T) {
 		steps.TcEgressTeardownStepId,
 		steps.TcEgressServiceTeardownStepId,
 		steps.TcIngressTeardownStepId,
+		steps.NetworkExporterTeardownStepId,
 	}, workflowStepIDs(t, wb))
 }
 
@@ -126,6 +128,7 @@ func TestUninstall_PurgeStorage_FullCleanup(t *testing.T) {
 		steps.TcEgressTeardownStepId,
 		steps.TcEgressServiceTeardownStepId,
 		steps.TcIngressTeardownStepId,
+		steps.NetworkExporterTeardownStepId,
 	}, workflowStepIDs(t, wb))
 }
 
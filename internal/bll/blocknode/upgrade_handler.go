@@ -35,7 +35,7 @@ func (h *UpgradeHandler) PrepareEffectiveInputs(
 	intent models.Intent,
 	inputs models.UserInputs[models.BlockNodeInputs],
 ) (*models.UserInputs[models.BlockNodeInputs], error) {
-	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, nil)
+	return resolveBlocknodeEffectiveInputs(h.runtime, intent, inputs, instanceEndpointValidator(h.Runtime))
 }
 
 // BuildWorkflow validates upgrade preconditions and returns the workflow.
@@ -47,24 +47,25 @@ func (h *UpgradeHandler) BuildWorkflow(
 	currentState state.State,
 	inputs models.UserInputs[models.BlockNodeInputs],
 ) (*automa.WorkflowBuilder, error) {
-	if currentState.BlockNodeState.ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
+	current := currentState.BlockNode(inputs.Custom.Release)
+	if current.ReleaseInfo.Status != release.StatusDeployed && !inputs.Common.Force {
 		return nil, errorx.IllegalState.New(
 			"block node is not installed; cannot upgrade").
 			WithProperty(models.ErrPropertyResolution, "use 'solo-provisioner block node install' to install the block node first, or pass --force to continue")
 	}
 
-	if currentState.BlockNodeState.ReleaseInfo.ChartRef != "" &&
-		currentState.BlockNodeState.ReleaseInfo.ChartRef != inputs.Custom.Chart {
+	if current.ReleaseInfo.ChartRef != "" &&
+		current.ReleaseInfo.ChartRef != inputs.Custom.Chart {
 		logx.As().Warn().
-			Str("current_chart", currentState.BlockNodeState.ReleaseInfo.ChartRef).
+			Str("current_chart", current.ReleaseInfo.ChartRef).
 			Str("desired_chart", inputs.Custom.Chart).
 			Msg("Block node chart reference is changing during upgrade; this is not recommended and may cause issues")
 	}
 
-	currentVer, err := semver.NewVersion(currentState.BlockNodeState.ReleaseInfo.ChartVersion)
+	currentVer, err := semver.NewVersion(current.ReleaseInfo.ChartVersion)
 	if err != nil {
 		return nil, errorx.IllegalState.New(
-			"failed to parse current chart version %q: %v", currentState.BlockNodeState.ReleaseInfo.ChartVersion, err)
+			"failed to parse current chart version %q: %v", current.ReleaseInfo.ChartVersion, err)
 	}
 	desiredVer, err := semver.NewVersion(inputs.Custom.ChartVersion)
 	if err != nil {
@@ -107,7 +108,7 @@ func (h *UpgradeHandler) BuildWorkflow(
 	// decision recorded on BlockNodeState — upgrade never prompts and never resolves
 	// its own gate (only reconfigure does). Daemon activation, when traffic shaping is
 	// enabled, is handled post-workflow in the CLI layer.
-	networkSteps := networkPlaneSteps(ins, inputs.Common.Force, !currentState.BlockNodeState.TrafficShapingDisabled, false, healthPort)
+	networkSteps := networkPlaneSteps(ins, inputs.Common.Force, !current.TrafficShapingDisabled, false, false, healthPort)
 
 	var wb *automa.WorkflowBuilder
 	if ins.ResetStorage {
@@ -66,6 +66,7 @@ func TestUpgrade_TrafficShapingEnabled_ReAssertsPlane(t *testing.T) {
 		steps.NftWeaverPersistStepId,
 		steps.TcEgressPersistStepId,
 		steps.TcIngressRecordStepId,
+		steps.NetworkExporterSetupStepId,
 		steps.BlockNodeDaemonConfigStepId,
 		steps.RestartDaemonServiceStepId,
 		steps.UpgradeBlockNodeStepId,
@@ -28,9 +28,9 @@ func (h *ClusterInstallHandler) PrepareEffectiveInputs(
 
 func (h *ClusterInstallHandler) BuildWorkflow(
 	_ state.State,
-	_ models.UserInputs[models.TeleportClusterInputs],
+	inputs models.UserInputs[models.TeleportClusterInputs],
 ) (*automa.WorkflowBuilder, error) {
-	return steps.SetupTeleportClusterAgent(), nil
+	return steps.SetupTeleportClusterAgent(inputs.Custom.SkipValuesValidation), nil
 }
 
 func (h *ClusterInstallHandler) HandleIntent(
@@ -93,6 +93,8 @@ func resolveTeleportClusterEffectiveInputs(
 		Custom: models.TeleportClusterInputs{
 			Version:    effVersion.Get().Val(),
 			ValuesFile: effValuesFile.Get().Val(),
+			// Passed through from user input (no resolution)
+			SkipValuesValidation: inputs.Custom.SkipValuesValidation,
 		},
 	}
 
@@ -6,19 +6,20 @@ import "github.com/hashgraph/solo-weaver/pkg/semver"
 
 // Plugin preset IDs used by the --plugin-preset flag and TUI prompt.
 //
-// Each preset maps to a fixed, ordered plugin list maintained here in lockstep
-// with block-node chart releases. When the block-node team changes a preset's
-// contents or adds/removes presets, this file must be updated in the same
-// release cycle.
+// The presets themselves — each one's plugin list by chart version and its
+// hardware floors — are YAML files: the ones embedded from presets/ plus any an
+// operator drops into the plugin-presets config directory (see
+// plugin_presets.go). This file keeps the IDs weaver itself refers to and the
+// list of plugins each chart version offers.
 //
 // Adding a new BN version that changes plugin lists:
-//  1. Add a new blockNodePluginConfig entry to blockNodePluginHistory (in
-//     ascending MinVersion order).
-//  2. Populate Presets with ALL preset IDs (copy unchanged ones from the
-//     previous entry; update only the ones that changed).
-//  3. Populate AllPlugins with the full list for that version.
-//  4. Add tests in blocknode_plugins_test.go for the new version boundary.
-//  5. No changes to PluginListForPreset, PluginsForVersion, or any caller.
+//  1. Add a range for the version to each preset file under presets/ (in
+//     ascending minVersion order).
+//  2. When the set of available plugins changes, add a new blockNodePluginConfig
+//     entry to blockNodePluginHistory (in ascending MinVersion order) with
+//     AllPlugins populated with the full list for that version.
+//  3. Add tests in blocknode_plugins_test.go for the new version boundary.
+//  4. No changes to PluginListForPreset, PluginsForVersion, or any caller.
 const (
 	// PresetTier1LFH selects Local Full History storage (blocks stored on disk).
 	PresetTier1LFH = "tier1-lfh"
@@ -32,39 +33,32 @@ const (
 	// PresetNone means "do not override": the plugin list is left to the operator's
 	// --values file (if it sets plugins.names) or the chart's built-in default.
 	// It resolves to an empty plugin list, so injectPluginsConfig is a no-op.
-	// It is deliberately absent from every Presets map, so IsKnownPreset reports
-	// false and PluginListForPreset returns "".
+	// Preset files cannot use the ID, so IsKnownPreset reports false and
+	// PluginListForPreset returns "".
 	PresetNone = "none"
 )
 
-// blockNodePluginConfig holds the canonical plugin configuration for a range of
-// chart versions starting at MinVersion. Entries in blockNodePluginHistory must
+// blockNodePluginConfig holds the plugins available for a range of chart
+// versions starting at MinVersion. Entries in blockNodePluginHistory must
 // be in ascending MinVersion order; the first entry uses an empty MinVersion
 // (baseline, applies to all versions below the next entry's MinVersion).
 type blockNodePluginConfig struct {
 	// MinVersion is the minimum chart version (inclusive) for this config.
 	// Empty string means "from the very beginning" (baseline).
 	MinVersion string
-	// Presets maps each preset ID to its canonical comma-separated plugin list.
-	// Source of truth: hiero-block-node chart values-overrides/plugin-profile-*.yaml.
-	Presets map[string]string
 	// AllPlugins is the ordered list of all available plugins for this version,
 	// shown in the TUI custom multi-select.
 	// Source of truth: hiero-block-node block-node/app/build.gradle.kts.
 	AllPlugins []string
 }
 
-// blockNodePluginHistory is the version-ordered registry of plugin configurations.
+// blockNodePluginHistory is the version-ordered registry of available plugins.
 // Add a new entry here (in ascending MinVersion order) whenever a BN release
-// changes the plugin list. See the package comment above for the procedure.
+// changes the set of plugins it offers. See the package comment above for the procedure.
 var blockNodePluginHistory = []blockNodePluginConfig{
 	{
 		// Baseline: BN < 0.35.0. s3-archive was the cloud-storage plugin.
 		MinVersion: "",
-		Presets: map[string]string{
-			PresetTier1LFH: "facility-messaging,block-access-service,health,server-status,stream-publisher,stream-subscriber,verification,blocks-file-historic,blocks-file-recent,backfill",
-			PresetTier1RFH: "facility-messaging,block-access-service,health,server-status,stream-publisher,stream-subscriber,verification,blocks-file-recent,backfill,s3-archive",
-		},
 		AllPlugins: []string{
 			"facility-messaging",
 			"block-access-service",
@@ -82,10 +76,6 @@ var blockNodePluginHistory = []blockNodePluginConfig{
 	{
 		// BN 0.35.0: s3-archive replaced by cloud-storage-archive + cloud-storage-expanded.
 		MinVersion: "0.35.0",
-		Presets: map[string]string{
-			PresetTier1LFH: "facility-messaging,block-access-service,health,server-status,stream-publisher,stream-subscriber,verification,blocks-file-historic,blocks-file-recent,backfill",
-			PresetTier1RFH: "facility-messaging,block-access-service,health,server-status,stream-publisher,stream-subscriber,verification,blocks-file-recent,backfill,cloud-storage-archive,cloud-storage-expanded",
-		},
 		AllPlugins: []string{
 			"facility-messaging",
 			"block-access-service",
@@ -102,16 +92,9 @@ var blockNodePluginHistory = []blockNodePluginConfig{
 		},
 	},
 	{
-		// BN 0.37.1: roster-bootstrap-rsa + roster-bootstrap-tss added to both presets.
-		// The RFH preset was also trimmed upstream: block-access-service, stream-publisher,
-		// stream-subscriber, and blocks-file-recent were dropped (recent blocks now flow to
-		// cloud storage rather than the local live volume). Strings copied verbatim from the
-		// chart's values-overrides/plugin-profile-{lfh,rfh,all}.yaml at tag v0.37.1.
+		// BN 0.37.1: roster-bootstrap-rsa + roster-bootstrap-tss added. Copied verbatim
+		// from the chart's values-overrides/plugin-profile-all.yaml at tag v0.37.1.
 		MinVersion: "0.37.1",
-		Presets: map[string]string{
-			PresetTier1LFH: "backfill,block-access-service,blocks-file-historic,blocks-file-recent,facility-messaging,health,roster-bootstrap-rsa,roster-bootstrap-tss,server-status,stream-publisher,stream-subscriber,verification",
-			PresetTier1RFH: "backfill,cloud-storage-archive,cloud-storage-expanded,facility-messaging,health,roster-bootstrap-rsa,roster-bootstrap-tss,server-status,verification",
-		},
 		AllPlugins: []string{
 			"backfill",
 			"block-access-service",
@@ -136,13 +119,9 @@ var blockNodePluginHistory = []blockNodePluginConfig{
 		// no longer registers it. Boundary is 0.39.1 (the version the block-node team
 		// states the replacement is available from) rather than 0.41.0 (removal): the
 		// 0.39.x and 0.40.x images already register only block-verification.
-		// Otherwise identical to the 0.37.1 entry. Strings copied verbatim from the
-		// chart's values-overrides/plugin-profile-{lfh,rfh,all}.yaml.
+		// Otherwise identical to the 0.37.1 entry. Copied verbatim from the chart's
+		// values-overrides/plugin-profile-all.yaml.
 		MinVersion: "0.39.1",
-		Presets: map[string]string{
-			PresetTier1LFH: "backfill,block-access-service,block-verification,blocks-file-historic,blocks-file-recent,facility-messaging,health,roster-bootstrap-rsa,roster-bootstrap-tss,server-status,stream-publisher,stream-subscriber",
-			PresetTier1RFH: "backfill,block-verification,cloud-storage-archive,cloud-storage-expanded,facility-messaging,health,roster-bootstrap-rsa,roster-bootstrap-tss,server-status",
-		},
 		AllPlugins: []string{
 			"backfill",
 			"block-access-service",
@@ -167,18 +146,14 @@ var blockNodePluginHistory = []blockNodePluginConfig{
 // may be older.
 var AllBlockNodePlugins = blockNodePluginHistory[len(blockNodePluginHistory)-1].AllPlugins
 
-// presetLabels maps preset IDs to human-readable labels for TUI display.
+// presetLabels maps the IDs that are not preset files to human-readable labels
+// for TUI display; preset files carry their own.
 var presetLabels = map[string]string{
-	PresetTier1LFH: "Tier 1 — Local Full History  (blocks stored on local disk)",
-	PresetTier1RFH: "Tier 1 — Remote Full History  (blocks stored in cloud storage)",
-	PresetCustom:   "Custom  (select individual plugins)",
-	PresetNone:     "Use Values File / Chart Default  (no override)",
+	PresetCustom: "Custom  (select individual plugins)",
+	PresetNone:   "Use Values File / Chart Default  (no override)",
 }
 
-// orderedPresets is the display order for the TUI select prompt.
-var orderedPresets = []string{PresetTier1LFH, PresetTier1RFH, PresetCustom, PresetNone}
-
-// configForVersion returns the plugin configuration applicable to the given
+// configForVersion returns the available plugins applicable to the given
 // chart version. Empty or unparseable versions return the latest config.
 func configForVersion(chartVersion string) blockNodePluginConfig {
 	latest := blockNodePluginHistory[len(blockNodePluginHistory)-1]
@@ -205,11 +180,15 @@ func configForVersion(chartVersion string) blockNodePluginConfig {
 	return blockNodePluginHistory[0]
 }
 
-// AvailablePresets returns the ordered preset IDs for TUI display.
+// AvailablePresets returns the ordered preset IDs for TUI display: the preset
+// files, then custom and none.
 func AvailablePresets() []string {
-	result := make([]string, len(orderedPresets))
-	copy(result, orderedPresets)
-	return result
+	presets := presetCatalog().Presets()
+	result := make([]string, 0, len(presets)+2)
+	for _, p := range presets {
+		result = append(result, p.ID)
+	}
+	return append(result, PresetCustom, PresetNone)
 }
 
 // PluginListForPreset returns the canonical comma-separated plugin list for a
@@ -217,7 +196,11 @@ func AvailablePresets() []string {
 // ID is not recognised. When chartVersion is empty, the current-default
 // (latest) list is returned.
 func PluginListForPreset(presetID, chartVersion string) string {
-	return configForVersion(chartVersion).Presets[presetID]
+	p, ok := presetCatalog().Get(presetID)
+	if !ok {
+		return ""
+	}
+	return p.PluginListFor(chartVersion)
 }
 
 // PluginsForVersion returns the ordered list of available plugins for the given
@@ -234,6 +217,9 @@ func PluginsForVersion(chartVersion string) []string {
 // PresetLabel returns the human-readable TUI label for a preset ID,
 // or the preset ID itself when no label is registered.
 func PresetLabel(presetID string) string {
+	if p, ok := presetCatalog().Get(presetID); ok {
+		return p.Label
+	}
 	if label, ok := presetLabels[presetID]; ok {
 		return label
 	}
@@ -242,7 +228,6 @@ func PresetLabel(presetID string) string {
 
 // IsKnownPreset returns true when presetID is a recognised non-custom preset.
 func IsKnownPreset(presetID string) bool {
-	// A preset is known if it appears in at least the latest config's Presets map.
-	_, ok := blockNodePluginHistory[len(blockNodePluginHistory)-1].Presets[presetID]
+	_, ok := presetCatalog().Get(presetID)
 	return ok
 }
@@ -38,8 +38,12 @@ func TestPluginHistory_AscendingVersionOrder(t *testing.T) {
 func TestPluginHistory_AllKnownPresetsInEveryEntry(t *testing.T) {
 	knownPresets := []string{PresetTier1LFH, PresetTier1RFH}
 	for i, cfg := range blockNodePluginHistory {
+		version := cfg.MinVersion
+		if version == "" {
+			version = "0.1.0" // the baseline covers every version below the next entry
+		}
 		for _, preset := range knownPresets {
-			assert.NotEmpty(t, cfg.Presets[preset], "entry %d (MinVersion=%q) missing preset %q", i, cfg.MinVersion, preset)
+			assert.NotEmpty(t, PluginListForPreset(preset, version), "entry %d (MinVersion=%q) missing preset %q", i, cfg.MinVersion, preset)
 		}
 	}
 }
@@ -68,11 +72,9 @@ func TestPresetNone_ResolvesToEmptyList(t *testing.T) {
 	assert.Empty(t, PluginListForPreset(PresetNone, ""), "PresetNone must resolve to an empty plugin list")
 	assert.Empty(t, PluginListForPreset(PresetNone, "0.35.0"), "PresetNone must resolve empty at any version")
 	assert.NotEmpty(t, PresetLabel(PresetNone), "PresetNone must have a display label")
-	// It must be absent from every version entry's Presets map.
-	for i, cfg := range blockNodePluginHistory {
-		_, ok := cfg.Presets[PresetNone]
-		assert.False(t, ok, "entry %d (MinVersion=%q) must not define PresetNone", i, cfg.MinVersion)
-	}
+	// No preset file may define it.
+	_, ok := presetCatalog().Get(PresetNone)
+	assert.False(t, ok, "the preset catalog must not define PresetNone")
 }
 
 func TestAvailablePresets_ReturnsACopy(t *testing.T) {
@@ -207,12 +209,22 @@ func TestPluginHistory_NoRetiredPluginsAtOrAboveBound(t *testing.T) {
 			if entryVer.LessThan(boundVer) {
 				continue
 			}
-			for preset, list := range cfg.Presets {
-				assertNoPluginNamed(t, list, name, preset, cfg.MinVersion)
-			}
 			assert.NotContains(t, cfg.AllPlugins, name,
 				"entry %d (MinVersion=%q) must not offer retired plugin %q (retired at %s)", i, cfg.MinVersion, name, bound)
 		}
+		for _, preset := range presetCatalog().Presets() {
+			for _, r := range preset.Plugins {
+				if r.MinVersion == "" {
+					continue
+				}
+				rangeVer, err := semver.NewSemver(r.MinVersion)
+				require.NoError(t, err)
+				if rangeVer.LessThan(boundVer) {
+					continue
+				}
+				assertNoPluginNamed(t, strings.Join(r.Names, ","), name, preset.ID, r.MinVersion)
+			}
+		}
 	}
 }
 
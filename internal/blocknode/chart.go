@@ -6,11 +6,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
 	"github.com/hashgraph/solo-weaver/pkg/helm"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/software"
 	"github.com/joomcode/errorx"
 	"helm.sh/helm/v3/pkg/cli/values"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -68,20 +72,26 @@ func (m *Manager) InstallChart(ctx context.Context, valuesFile string) (bool, er
 		m.logger.Info().Msg("Block Node is already installed, skipping installation")
 		return false, nil
 	}
+	chartRef, chartVersion, err := m.verifiedChart(ctx)
+	if err != nil {
+		return false, err
+	}
 	_, err = m.helmManager.InstallChart(
 		ctx,
 		m.blockNodeInputs.Release,
-		m.blockNodeInputs.Chart,
-		m.blockNodeInputs.ChartVersion,
+		chartRef,
+		chartVersion,
 		m.blockNodeInputs.Namespace,
 		helm.InstallChartOptions{
 			ValueOpts: &values.Options{
 				ValueFiles: []string{valuesFile},
 			},
-			CreateNamespace: false,
-			Atomic:          true,
-			Wait:            true,
-			Timeout:         m.ResolveHelmTimeout(),
+			CreateNamespace:      false,
+			Atomic:               true,
+			Wait:                 true,
+			Timeout:              m.ResolveHelmTimeout(),
+			SkipValuesValidation: m.blockNodeInputs.SkipValuesValidation,
+			RejectUnknownValues:  true,
 		},
 	)
 	if err != nil {
@@ -90,6 +100,43 @@ func (m *Manager) InstallChart(ctx context.Context, valuesFile string) (bool, er
 	return true, nil
 }
 
+// verifiedChart returns the chart reference and version to hand to Helm. When
+// the infrastructure catalog's `provenance:` section sets a policy for the
+// block node chart, the chart is pulled into the downloads directory and its
+// signature verified first, and the local archive is returned with an empty
+// version. Otherwise the chart is copied out of the active air-gap bundle
+// when it holds it, and the configured reference and version are returned for
+// Helm to locate when it does not.
+func (m *Manager) verifiedChart(ctx context.Context) (string, string, error) {
+	catalog, err := software.LoadInfrastructureCatalog()
+	if err != nil {
+		return "", "", errorx.IllegalFormat.Wrap(err, "failed to load infrastructure catalog")
+	}
+	prov := catalog.ProvenanceFor(m.blockNodeInputs.Chart).HelmProvenance(models.Paths().KeysDir)
+	if !prov.Enabled() {
+		// Helm cannot locate the chart offline; an air-gap bundle holding it
+		// serves it through the puller instead.
+		if b := bundle.Active(); b != nil {
+			if _, ok := b.Chart(m.blockNodeInputs.Chart, m.blockNodeInputs.ChartVersion); ok {
+				localChart, _, err := m.helmManager.PullChart(ctx, path.Join(models.Paths().DownloadsDir, "charts"),
+					m.blockNodeInputs.Chart, m.blockNodeInputs.ChartVersion)
+				if err != nil {
+					return "", "", err
+				}
+				return localChart, "", nil
+			}
+		}
+		return m.blockNodeInputs.Chart, m.blockNodeInputs.ChartVersion, nil
+	}
+
+	localChart, err := m.helmManager.PullAndVerifyProvenance(ctx, path.Join(models.Paths().DownloadsDir, "charts"),
+		m.blockNodeInputs.Chart, m.blockNodeInputs.ChartVersion, prov)
+	if err != nil {
+		return "", "", err
+	}
+	return localChart, "", nil
+}
+
 // UninstallChart uninstalls the block node helm chart
 func (m *Manager) UninstallChart(ctx context.Context) error {
 	return m.helmManager.UninstallChart(m.blockNodeInputs.Release, m.blockNodeInputs.Namespace)
@@ -112,20 +159,26 @@ func (m *Manager) UpgradeChart(ctx context.Context, valuesFile string, reuseValu
 	} else if !reuseValues {
 		return errorx.IllegalArgument.New("no values file provided and --no-reuse-values is set")
 	}
+	chartRef, chartVersion, err := m.verifiedChart(ctx)
+	if err != nil {
+		return err
+	}
 	_, err = m.helmManager.UpgradeChart(
 		ctx,
 		m.blockNodeInputs.Release,
-		m.blockNodeInputs.Chart,
-		m.blockNodeInputs.ChartVersion,
+		chartRef,
+		chartVersion,
 		m.blockNodeInputs.Namespace,
 		helm.UpgradeChartOptions{
 			ValueOpts: &values.Options{
 				ValueFiles: valueFiles,
 			},
-			ReuseValues: reuseValues,
-			Atomic:      true,
-			Wait:        true,
-			Timeout:     m.ResolveHelmTimeout(),
+			ReuseValues:          reuseValues,
+			Atomic:               true,
+			Wait:                 true,
+			Timeout:              m.ResolveHelmTimeout(),
+			SkipValuesValidation: m.blockNodeInputs.SkipValuesValidation,
+			RejectUnknownValues:  true,
 		},
 	)
 	if err != nil {
@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	oslib "os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/solo-weaver/internal/network/firewall"
+	"github.com/hashgraph/solo-weaver/internal/network/shape"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/sanity"
+	"github.com/joomcode/errorx"
+	"gopkg.in/yaml.v3"
+)
+
+// DeploymentAPIVersion and DeploymentKind identify a BlockNodeDeployment file.
+const (
+	DeploymentAPIVersion = "solo-provisioner.hiero.org/v1alpha1"
+	DeploymentKind       = "BlockNodeDeployment"
+)
+
+// Deployment is the declarative description of a block node that
+// `block node apply -f` converges the host to. It carries what the install,
+// upgrade and reconfigure flags carry; a section left out keeps what is
+// deployed (or, on install, the flag's default). See "Apply a Block Node
+// Deployment File" in docs/quickstart.md for an annotated example.
+type Deployment struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   DeploymentMetadata `yaml:"metadata"`
+	Spec       DeploymentSpec     `yaml:"spec"`
+
+	// Path is the file the deployment was loaded from.
+	Path string `yaml:"-"`
+}
+
+// DeploymentMetadata names the block node: Name is the Helm release (the
+// --release instance; empty is the default block node).
+type DeploymentMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+// DeploymentSpec is the desired configuration of the block node.
+type DeploymentSpec struct {
+	Profile      string                  `yaml:"profile"`
+	Chart        DeploymentChart         `yaml:"chart"`
+	Storage      models.BlockNodeStorage `yaml:"storage"`
+	Retention    DeploymentRetention     `yaml:"retention"`
+	Plugins      DeploymentPlugins       `yaml:"plugins"`
+	LoadBalancer DeploymentLoadBalancer  `yaml:"loadBalancer"`
+	Shaping      *DeploymentShaping      `yaml:"shaping"`
+	Firewall     *DeploymentFirewall     `yaml:"firewall"`
+	Daemon       DeploymentDaemon        `yaml:"daemon"`
+}
+
+// DeploymentChart selects the chart. An empty Version keeps the deployed one
+// (or, on install, the default); ValuesFile is relative to the deployment file.
+type DeploymentChart struct {
+	Repo       string        `yaml:"repo"`
+	Version    string        `yaml:"version"`
+	ValuesFile string        `yaml:"valuesFile"`
+	Timeout    time.Duration `yaml:"timeout"`
+}
+
+// DeploymentRetention holds the historic and recent block retention thresholds.
+type DeploymentRetention struct {
+	Historic string `yaml:"historic"`
+	Recent   string `yaml:"recent"`
+}
+
+// DeploymentPlugins selects the plugins by preset or by name; Names wins, as
+// --plugins does over --plugin-preset.
+type DeploymentPlugins struct {
+	Preset string   `yaml:"preset"`
+	Names  []string `yaml:"names"`
+}
+
+// DeploymentLoadBalancer publishes the block node service. Enabled defaults to
+// true, as --load-balancer-enabled does.
+type DeploymentLoadBalancer struct {
+	Enabled     *bool  `yaml:"enabled"`
+	Pool        string `yaml:"pool"`
+	ServicePort int    `yaml:"servicePort"`
+}
+
+// DeploymentShaping is the traffic-shaping bundle (--traffic-shaping-enabled
+// and its content flags). Empty fields keep the recorded values.
+type DeploymentShaping struct {
+	Enabled         bool                            `yaml:"enabled"`
+	EgressInterface string                          `yaml:"egressInterface"`
+	LinkRate        string                          `yaml:"linkRate"`
+	Overrides       map[string]models.ShapeOverride `yaml:"overrides"`
+}
+
+// DeploymentFirewall is the host firewall (--firewall-enabled and its content
+// flags). Zero fields take the flag defaults.
+type DeploymentFirewall struct {
+	Enabled        bool     `yaml:"enabled"`
+	MgmtCIDRs      []string `yaml:"mgmtCIDRs"`
+	BlockedCIDRs   []string `yaml:"blockedCIDRs"`
+	SSHPort        int      `yaml:"sshPort"`
+	PodCIDR        string   `yaml:"podCIDR"`
+	InClusterPorts []int    `yaml:"inClusterPorts"`
+}
+
+// DeploymentDaemon is where the traffic-shaper daemon binary comes from
+// (--daemon-bin, --daemon-version) and the statusz endpoint it polls.
+type DeploymentDaemon struct {
+	Bin                 string `yaml:"bin"`
+	Version             string `yaml:"version"`
+	StatuszBaseURL      string `yaml:"statuszBaseURL"`
+	StatuszPollInterval string `yaml:"statuszPollInterval"`
+}
+
+// LoadDeployment reads and validates the deployment file at path. Unknown
+// fields are rejected so a misspelt key fails instead of being ignored, and a
+// relative values file is resolved against the deployment file's directory.
+func LoadDeployment(path string) (*Deployment, error) {
+	data, err := oslib.ReadFile(path)
+	if err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "failed to read block node deployment file %s", path)
+	}
+
+	var d Deployment
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&d); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errorx.IllegalArgument.New("block node deployment file %s is empty", path)
+		}
+		return nil, errorx.IllegalArgument.Wrap(err, "failed to parse block node deployment file %s", path)
+	}
+	d.Path = path
+
+	if vf := d.Spec.Chart.ValuesFile; vf != "" && !filepath.IsAbs(vf) {
+		d.Spec.Chart.ValuesFile = filepath.Join(filepath.Dir(path), vf)
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, errorx.Decorate(err, "invalid block node deployment file %s", path)
+	}
+	return &d, nil
+}
+
+// Validate checks the deployment for what the block node inputs do not
+// validate themselves: the header, the plugin list, the shaping overrides and
+// the host firewall.
+func (d *Deployment) Validate() error {
+	if d.APIVersion != DeploymentAPIVersion || d.Kind != DeploymentKind {
+		return errorx.IllegalArgument.New("unsupported apiVersion %q and kind %q", d.APIVersion, d.Kind).
+			WithProperty(models.ErrPropertyResolution,
+				"start the file with apiVersion: "+DeploymentAPIVersion+" and kind: "+DeploymentKind)
+	}
+	if d.Spec.Profile == "" {
+		return errorx.IllegalArgument.New("spec.profile is required")
+	}
+	if len(d.Spec.Plugins.Names) > 0 {
+		if err := models.ValidatePluginList(d.PluginList()); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid spec.plugins.names")
+		}
+	}
+	if d.Spec.Plugins.Preset == PresetCustom && len(d.Spec.Plugins.Names) == 0 {
+		return errorx.IllegalArgument.New("spec.plugins.names is required when spec.plugins.preset is %s", PresetCustom)
+	}
+	if d.Spec.Chart.ValuesFile != "" {
+		if _, err := sanity.ValidateInputFile(d.Spec.Chart.ValuesFile); err != nil {
+			return errorx.Decorate(err, "invalid spec.chart.valuesFile")
+		}
+	}
+
+	if s := d.Spec.Shaping; s != nil {
+		if !s.Enabled && (s.EgressInterface != "" || s.LinkRate != "" || len(s.Overrides) > 0) {
+			return errorx.IllegalArgument.New("spec.shaping configures traffic shaping but spec.shaping.enabled is false")
+		}
+		for _, class := range sortedKeys(s.Overrides) {
+			o := s.Overrides[class]
+			if err := shape.ValidateClassOverride(class, shape.ClassOverride{Rate: o.Rate, Ceil: o.Ceil, Prio: o.Prio}); err != nil {
+				return errorx.Decorate(err, "invalid spec.shaping.overrides.%s", class)
+			}
+		}
+	}
+	if d.Spec.Daemon.Bin != "" || d.Spec.Daemon.Version != "" {
+		if d.Spec.Shaping == nil || !d.Spec.Shaping.Enabled {
+			return errorx.IllegalArgument.New("spec.daemon.bin and spec.daemon.version need spec.shaping.enabled: the daemon is only installed with traffic shaping")
+		}
+	}
+
+	if fw := d.HostConfig(); fw != nil && !fw.Disabled {
+		if err := fw.Validate(); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid spec.firewall")
+		}
+	}
+	return nil
+}
+
+// PluginList returns spec.plugins.names as the comma-separated --plugins value.
+func (d *Deployment) PluginList() string {
+	return strings.Join(d.Spec.Plugins.Names, ",")
+}
+
+// LoadBalancerEnabled returns spec.loadBalancer.enabled, true when unset.
+func (d *Deployment) LoadBalancerEnabled() bool {
+	return d.Spec.LoadBalancer.Enabled == nil || *d.Spec.LoadBalancer.Enabled
+}
+
+// HostConfig returns the host firewall the deployment asks for, filling zero
+// fields with the flag defaults, or nil when spec.firewall is left out. An
+// unset pod CIDR stays empty: its default is the cluster pod subnet, which
+// the caller resolves (see common.ResolveHostPodCIDR).
+func (d *Deployment) HostConfig() *models.HostConfig {
+	fw := d.Spec.Firewall
+	if fw == nil {
+		return nil
+	}
+	if !fw.Enabled {
+		return &models.HostConfig{Disabled: true}
+	}
+	cfg := &models.HostConfig{
+		ManagementCIDRs: fw.MgmtCIDRs,
+		BlockedCIDRs:    fw.BlockedCIDRs,
+		SSHPort:         fw.SSHPort,
+		PodCIDR:         fw.PodCIDR,
+		InClusterPorts:  fw.InClusterPorts,
+	}
+	if cfg.SSHPort == 0 {
+		cfg.SSHPort = firewall.DefaultSSHPort
+	}
+	if len(cfg.InClusterPorts) == 0 {
+		cfg.InClusterPorts = append([]int(nil), firewall.DefaultInClusterPorts...)
+	}
+	return cfg
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDeployment(t *testing.T, dir, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, "block-node.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+func TestLoadDeployment(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("{}\n"), 0o600))
+	path := writeDeployment(t, dir, `apiVersion: solo-provisioner.hiero.org/v1alpha1
+kind: BlockNodeDeployment
+metadata:
+  name: bn-testnet
+  namespace: bn-testnet
+spec:
+  profile: testnet
+  chart:
+    version: 0.39.1
+    valuesFile: values.yaml
+    timeout: 10m
+  storage:
+    basePath: /mnt/fast-storage
+  retention:
+    recent: "96000"
+  plugins:
+    names: [health, server-status]
+  loadBalancer:
+    enabled: false
+  shaping:
+    enabled: true
+    linkRate: 10gbit
+    overrides:
+      publisher: {rate: 800mbit, ceil: 1gbit, prio: 0}
+  firewall:
+    enabled: true
+    mgmtCIDRs: [10.0.0.0/8]
+`)
+
+	d, err := LoadDeployment(path)
+	require.NoError(t, err)
+	assert.Equal(t, "bn-testnet", d.Metadata.Name)
+	assert.Equal(t, filepath.Join(dir, "values.yaml"), d.Spec.Chart.ValuesFile,
+		"a relative values file is resolved against the deployment file")
+	assert.Equal(t, "health,server-status", d.PluginList())
+	assert.False(t, d.LoadBalancerEnabled())
+	require.Contains(t, d.Spec.Shaping.Overrides, "publisher")
+	assert.Equal(t, 0, *d.Spec.Shaping.Overrides["publisher"].Prio)
+
+	fw := d.HostConfig()
+	require.NotNil(t, fw)
+	assert.Equal(t, 22, fw.SSHPort, "zero fields take the flag defaults")
+	assert.NotEmpty(t, fw.InClusterPorts)
+}
+
+func TestLoadDeployment_Defaults(t *testing.T) {
+	path := writeDeployment(t, t.TempDir(), `apiVersion: solo-provisioner.hiero.org/v1alpha1
+kind: BlockNodeDeployment
+spec:
+  profile: local
+`)
+	d, err := LoadDeployment(path)
+	require.NoError(t, err)
+	assert.True(t, d.LoadBalancerEnabled())
+	assert.Nil(t, d.HostConfig(), "a left-out firewall is nil so the recorded one is kept")
+	assert.Nil(t, d.Spec.Shaping)
+}
+
+func TestLoadDeployment_Invalid(t *testing.T) {
+	header := "apiVersion: solo-provisioner.hiero.org/v1alpha1\nkind: BlockNodeDeployment\n"
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"empty", "", "is empty"},
+		{"wrong kind", "apiVersion: v1\nkind: ConfigMap\nspec:\n  profile: local\n", "unsupported apiVersion"},
+		{"unknown field", header + "spec:\n  profile: local\n  replicas: 2\n", "field replicas not found"},
+		{"no profile", header + "spec: {}\n", "spec.profile is required"},
+		{"custom without names", header + "spec:\n  profile: local\n  plugins:\n    preset: custom\n", "spec.plugins.names is required"},
+		{"shaping content while disabled", header + "spec:\n  profile: local\n  shaping:\n    enabled: false\n    linkRate: 1gbit\n", "spec.shaping.enabled is false"},
+		{"unknown shaping class", header + "spec:\n  profile: local\n  shaping:\n    enabled: true\n    overrides:\n      nope: {rate: 1mbit}\n", "spec.shaping.overrides.nope"},
+		{"daemon without shaping", header + "spec:\n  profile: local\n  daemon:\n    bin: /tmp/daemon\n", "need spec.shaping.enabled"},
+		{"bad firewall", header + "spec:\n  profile: local\n  firewall:\n    enabled: true\n    mgmtCIDRs: [not-a-cidr]\n", "invalid spec.firewall"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadDeployment(writeDeployment(t, t.TempDir(), tt.body))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
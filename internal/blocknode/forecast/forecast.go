@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package forecast projects when block node storage fills up. It samples the
+// on-disk size of each storage volume over time, fits a growth rate per
+// volume, caps each volume at the footprint its retention threshold allows
+// and projects days-to-full for the filesystem behind it.
+//
+// The package depends on the standard library only (plus errorx) so the
+// daemon, which must stay free of provisioning packages, can persist samples
+// with it.
+package forecast
+
+import (
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+// Volume is one block node storage volume to sample.
+type Volume struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Sample is the on-disk size of one volume at one point in time.
+type Sample struct {
+	Time   time.Time `json:"time"`
+	Volume string    `json:"volume"`
+	Bytes  int64     `json:"bytes"`
+	Files  int       `json:"files"`
+}
+
+// MinGrowthSpan is the shortest sample history a growth rate is fitted over.
+// Shorter histories are dominated by block-file churn rather than growth.
+const MinGrowthSpan = time.Hour
+
+// Measure samples every volume at now. A volume whose directory does not
+// exist yet is sampled as empty.
+func Measure(vols []Volume, now time.Time) ([]Sample, error) {
+	samples := make([]Sample, 0, len(vols))
+	for _, v := range vols {
+		files, bytes, err := usage(v.Path)
+		if err != nil {
+			return nil, errorx.ExternalError.Wrap(err, "failed to measure %s storage at %s", v.Name, v.Path)
+		}
+		samples = append(samples, Sample{Time: now.UTC(), Volume: v.Name, Bytes: bytes, Files: files})
+	}
+	return samples, nil
+}
+
+// usage counts the regular files under dir and their total size.
+func usage(dir string) (files int, bytes int64, err error) {
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// Block files are rotated out while the walk runs.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		files++
+		bytes += info.Size()
+		return nil
+	})
+	return files, bytes, err
+}
+
+// GrowthRate fits a least-squares line through volume's samples and returns
+// its slope in bytes per day. ok is false when the samples span less than
+// MinGrowthSpan.
+func GrowthRate(samples []Sample, volume string) (bytesPerDay float64, ok bool) {
+	var pts []Sample
+	for _, s := range samples {
+		if s.Volume == volume {
+			pts = append(pts, s)
+		}
+	}
+	if len(pts) < 2 {
+		return 0, false
+	}
+	sort.Slice(pts, func(i, j int) bool { return pts[i].Time.Before(pts[j].Time) })
+	if pts[len(pts)-1].Time.Sub(pts[0].Time) < MinGrowthSpan {
+		return 0, false
+	}
+
+	t0 := pts[0].Time
+	n := float64(len(pts))
+	var sx, sy, sxx, sxy float64
+	for _, p := range pts {
+		x := p.Time.Sub(t0).Hours() / 24
+		y := float64(p.Bytes)
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+	den := n*sxx - sx*sx
+	if den == 0 {
+		return 0, false
+	}
+	return (n*sxy - sx*sy) / den, true
+}
+
+// Latest returns the newest sample of each volume.
+func Latest(samples []Sample) map[string]Sample {
+	out := map[string]Sample{}
+	for _, s := range samples {
+		if cur, ok := out[s.Volume]; !ok || s.Time.After(cur.Time) {
+			out[s.Volume] = s
+		}
+	}
+	return out
+}
+
+// Growth is one volume's position on its growth line.
+type Growth struct {
+	// Used is the volume's current size.
+	Used int64
+	// PerDay is the fitted growth rate; zero or negative never fills anything.
+	PerDay float64
+	// Cap is the size at which retention stops the volume from growing;
+	// zero means the volume is unbounded.
+	Cap int64
+}
+
+// DaysToCap returns the days until g reaches its cap: 0 once it has, +Inf
+// when it is unbounded or not growing.
+func (g Growth) DaysToCap() float64 {
+	if g.Cap <= 0 || g.PerDay <= 0 {
+		return math.Inf(1)
+	}
+	if g.Used >= g.Cap {
+		return 0
+	}
+	return float64(g.Cap-g.Used) / g.PerDay
+}
+
+// DaysToFull returns the days until growth consumes free bytes, with each
+// volume contributing its rate only until it reaches its cap. It returns +Inf
+// when the filesystem never fills.
+func DaysToFull(free int64, growth []Growth) float64 {
+	remaining := float64(free)
+	if remaining <= 0 {
+		return 0
+	}
+
+	type active struct {
+		rate, until float64
+	}
+	var vols []active
+	for _, g := range growth {
+		if g.PerDay > 0 {
+			vols = append(vols, active{rate: g.PerDay, until: g.DaysToCap()})
+		}
+	}
+	sort.Slice(vols, func(i, j int) bool { return vols[i].until < vols[j].until })
+
+	var t, rate float64
+	for _, v := range vols {
+		rate += v.rate
+	}
+	for _, v := range vols {
+		if rate <= 0 {
+			break
+		}
+		if math.IsInf(v.until, 1) || remaining <= rate*(v.until-t) {
+			return t + remaining/rate
+		}
+		remaining -= rate * (v.until - t)
+		t = v.until
+		rate -= v.rate
+	}
+	return math.Inf(1)
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package forecast
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var t0 = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func daily(volume string, sizes ...int64) []Sample {
+	out := make([]Sample, len(sizes))
+	for i, b := range sizes {
+		out[i] = Sample{Time: t0.Add(time.Duration(i) * 24 * time.Hour), Volume: volume, Bytes: b, Files: 1}
+	}
+	return out
+}
+
+func TestGrowthRate(t *testing.T) {
+	samples := append(daily("live", 100, 200, 300, 400), daily("archive", 0, 0)...)
+
+	rate, ok := GrowthRate(samples, "live")
+	require.True(t, ok)
+	require.InDelta(t, 100, rate, 1e-9)
+
+	rate, ok = GrowthRate(samples, "archive")
+	require.True(t, ok)
+	require.Zero(t, rate)
+
+	_, ok = GrowthRate(samples, "logs")
+	require.False(t, ok)
+
+	short := []Sample{{Time: t0, Volume: "live", Bytes: 1}, {Time: t0.Add(time.Minute), Volume: "live", Bytes: 9}}
+	_, ok = GrowthRate(short, "live")
+	require.False(t, ok)
+}
+
+func TestDaysToFull(t *testing.T) {
+	require.InDelta(t, 10, DaysToFull(1000, []Growth{{PerDay: 100}}), 1e-9)
+	require.True(t, math.IsInf(DaysToFull(1000, []Growth{{PerDay: 0}, {PerDay: -5}}), 1))
+	require.Zero(t, DaysToFull(0, []Growth{{PerDay: 1}}))
+
+	// The capped volume stops after 2 days (200 of 1000 used, then 50/day).
+	growth := []Growth{{Used: 0, PerDay: 100, Cap: 200}, {PerDay: 50}}
+	require.InDelta(t, 2+(1000-300)/50.0, DaysToFull(1000, growth), 1e-9)
+
+	// Both capped below the free space: never full.
+	require.True(t, math.IsInf(DaysToFull(1000, []Growth{{PerDay: 100, Cap: 200}, {PerDay: 50, Cap: 100}}), 1))
+}
+
+func TestGrowthDaysToCap(t *testing.T) {
+	require.InDelta(t, 3, Growth{Used: 100, PerDay: 100, Cap: 400}.DaysToCap(), 1e-9)
+	require.Zero(t, Growth{Used: 500, PerDay: 100, Cap: 400}.DaysToCap())
+	require.True(t, math.IsInf(Growth{Used: 100, PerDay: 100}.DaysToCap(), 1))
+}
+
+func TestMeasure(t *testing.T) {
+	dir := t.TempDir()
+	live := filepath.Join(dir, "live")
+	require.NoError(t, os.MkdirAll(filepath.Join(live, "0000"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(live, "0000", "1.blk.zstd"), make([]byte, 300), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(live, "0000", "2.blk.zstd"), make([]byte, 100), 0o644))
+
+	samples, err := Measure([]Volume{{Name: "live", Path: live}, {Name: "archive", Path: filepath.Join(dir, "missing")}}, t0)
+	require.NoError(t, err)
+	require.Equal(t, []Sample{
+		{Time: t0, Volume: "live", Bytes: 400, Files: 2},
+		{Time: t0, Volume: "archive"},
+	}, samples)
+
+	bpb, ok := BytesPerBlock(samples[0])
+	require.True(t, ok)
+	require.InDelta(t, 200, bpb, 1e-9)
+	_, ok = BytesPerBlock(samples[1])
+	require.False(t, ok)
+}
+
+func TestProject_CapsByRetention(t *testing.T) {
+	dir := t.TempDir()
+	vols := []Volume{{Name: "live", Path: filepath.Join(dir, "live")}, {Name: "archive", Path: filepath.Join(dir, "archive")}}
+
+	// 10 blocks of 100 bytes in the live tier, growing 1000 B/day.
+	live := daily("live", 0, 1000)
+	live[1].Files = 10
+	samples := append(live, daily("archive", 0, 500)...)
+
+	got, err := Project(Input{
+		Volumes:       vols,
+		Samples:       samples,
+		LiveVolume:    "live",
+		ArchiveVolume: "archive",
+		Retention:     Retention{Recent: 30, Historic: 0},
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	require.Equal(t, "live", got[0].Volume.Name)
+	require.True(t, got[0].HasRate)
+	require.EqualValues(t, 3000, got[0].Growth.Cap)
+	require.InDelta(t, 2, got[0].DaysToCap, 1e-9)
+
+	require.Zero(t, got[1].Growth.Cap, "historic retention 0 keeps every block")
+	require.True(t, math.IsInf(got[1].DaysToCap, 1))
+	require.Equal(t, got[0].Filesystem.Device, got[1].Filesystem.Device)
+	require.Equal(t, got[0].DaysToFull, got[1].DaysToFull)
+	require.False(t, math.IsInf(got[1].DaysToFull, 1), "an unbounded archive eventually fills the disk")
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package forecast
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// Filesystem is the capacity of the filesystem a volume lives on. Device
+// identifies it, so volumes sharing a disk are projected together.
+type Filesystem struct {
+	Device uint64
+	Total  int64
+	Free   int64
+}
+
+// StatFilesystem reports the filesystem path lives on, resolving a path that
+// does not exist yet (before install) to its nearest existing ancestor.
+func StatFilesystem(path string) (Filesystem, error) {
+	p := filepath.Clean(path)
+	for {
+		if _, err := os.Stat(p); err == nil {
+			return statFilesystem(p)
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			return statFilesystem(p)
+		}
+		p = parent
+	}
+}
+
+// Retention is a block node's block retention thresholds, in blocks. Zero
+// Historic keeps every block; zero Recent leaves the live tier unbounded.
+type Retention struct {
+	Recent   int64
+	Historic int64
+}
+
+// Input describes one block node's storage for Project.
+type Input struct {
+	Volumes []Volume
+	// Samples is the sample history, newest measurement included.
+	Samples []Sample
+	// LiveVolume and ArchiveVolume name the volumes Retention.Recent and
+	// Retention.Historic bound.
+	LiveVolume    string
+	ArchiveVolume string
+	Retention     Retention
+}
+
+// VolumeForecast is the projection for one volume.
+type VolumeForecast struct {
+	Volume     Volume
+	Filesystem Filesystem
+	Files      int
+	Growth     Growth
+	// HasRate is false until the samples span MinGrowthSpan; Growth.PerDay is
+	// zero until then.
+	HasRate bool
+	// DaysToCap is when the volume stops growing at its retention cap;
+	// DaysToFull is when its filesystem fills, counting every volume on it.
+	// Both are +Inf when it never happens.
+	DaysToCap  float64
+	DaysToFull float64
+}
+
+// BytesPerBlock estimates the on-disk size of one block from the live tier,
+// which stores one file per block. ok is false while it holds no blocks.
+func BytesPerBlock(live Sample) (float64, bool) {
+	if live.Files == 0 {
+		return 0, false
+	}
+	return float64(live.Bytes) / float64(live.Files), true
+}
+
+// RetentionCap is the footprint of blocks blocks at bytesPerBlock; zero
+// blocks or an unknown block size is no cap.
+func RetentionCap(bytesPerBlock float64, blocks int64) int64 {
+	if blocks <= 0 || bytesPerBlock <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(bytesPerBlock * float64(blocks)))
+}
+
+// Project forecasts every volume in in. Volumes without a sample are
+// projected as empty.
+func Project(in Input) ([]VolumeForecast, error) {
+	latest := Latest(in.Samples)
+	bpb, _ := BytesPerBlock(latest[in.LiveVolume])
+
+	out := make([]VolumeForecast, 0, len(in.Volumes))
+	byDevice := map[uint64][]int{}
+	for _, v := range in.Volumes {
+		fsys, err := StatFilesystem(v.Path)
+		if err != nil {
+			return nil, err
+		}
+		cur := latest[v.Name]
+		rate, ok := GrowthRate(in.Samples, v.Name)
+		vf := VolumeForecast{
+			Volume:     v,
+			Filesystem: fsys,
+			Files:      cur.Files,
+			Growth:     Growth{Used: cur.Bytes, PerDay: rate},
+			HasRate:    ok,
+		}
+		switch v.Name {
+		case in.LiveVolume:
+			vf.Growth.Cap = RetentionCap(bpb, in.Retention.Recent)
+		case in.ArchiveVolume:
+			vf.Growth.Cap = RetentionCap(bpb, in.Retention.Historic)
+		}
+		vf.DaysToCap = vf.Growth.DaysToCap()
+		byDevice[fsys.Device] = append(byDevice[fsys.Device], len(out))
+		out = append(out, vf)
+	}
+
+	for _, idx := range byDevice {
+		growth := make([]Growth, 0, len(idx))
+		for _, i := range idx {
+			growth = append(growth, out[i].Growth)
+		}
+		days := DaysToFull(out[idx[0]].Filesystem.Free, growth)
+		for _, i := range idx {
+			out[i].DaysToFull = days
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package forecast
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// statFilesystem reports the filesystem holding path, which must exist.
+func statFilesystem(path string) (Filesystem, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return Filesystem{}, err
+	}
+	var fi unix.Stat_t
+	if err := unix.Stat(path, &fi); err != nil {
+		return Filesystem{}, err
+	}
+	return Filesystem{
+		Device: uint64(fi.Dev),
+		Total:  int64(st.Blocks) * int64(st.Bsize),
+		Free:   int64(st.Bavail) * int64(st.Bsize),
+	}, nil
+}
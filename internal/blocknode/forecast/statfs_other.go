@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package forecast
+
+import "github.com/joomcode/errorx"
+
+// statFilesystem is only implemented on Linux, the one platform block nodes
+// are provisioned on.
+func statFilesystem(path string) (Filesystem, error) {
+	return Filesystem{}, errorx.NotImplemented.New("filesystem capacity of %s is only available on Linux", path)
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package forecast
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+// DefaultSampleRetention is how long samples are kept: long enough to smooth
+// over a week of uneven traffic, short enough that an old growth regime
+// (e.g. before a retention change) stops skewing the fit.
+const DefaultSampleRetention = 30 * 24 * time.Hour
+
+// fileMode keeps the stores group-writable: the CLI writes them as root and
+// the daemon appends as weaver through the setgid weaver group of their
+// directory.
+const fileMode os.FileMode = 0o664
+
+// WriteVolumes records the volumes the daemon samples. The CLI rewrites it
+// whenever it resolves the block node storage layout.
+func WriteVolumes(path string, vols []Volume) error {
+	data, err := json.MarshalIndent(vols, "", "  ")
+	if err != nil {
+		return errorx.IllegalState.Wrap(err, "failed to encode storage volumes")
+	}
+	return writeFile(path, append(data, '\n'))
+}
+
+// ReadVolumes reads the volumes written by WriteVolumes. A missing file is no
+// volumes, not an error.
+func ReadVolumes(path string) ([]Volume, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read storage volumes %s", path)
+	}
+	var vols []Volume
+	if err := json.Unmarshal(data, &vols); err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "invalid storage volumes file %s", path)
+	}
+	return vols, nil
+}
+
+// Store is the JSON-lines file samples are persisted in.
+type Store struct {
+	Path string
+	// Retain drops samples older than this on every Append; zero keeps
+	// DefaultSampleRetention.
+	Retain time.Duration
+}
+
+// Read returns every stored sample. Lines that do not parse, e.g. one torn by
+// a crash mid-write, are skipped. A missing file has no samples.
+func (s Store) Read() ([]Sample, error) {
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read storage samples %s", s.Path)
+	}
+	var out []Sample
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var smp Sample
+		if json.Unmarshal(sc.Bytes(), &smp) == nil && smp.Volume != "" {
+			out = append(out, smp)
+		}
+	}
+	return out, nil
+}
+
+// Append adds samples and drops those older than the retention window, as of
+// now. The file is rewritten in place rather than replaced so it keeps its
+// ownership across writers.
+func (s Store) Append(samples []Sample, now time.Time) error {
+	existing, err := s.Read()
+	if err != nil {
+		return err
+	}
+	retain := s.Retain
+	if retain <= 0 {
+		retain = DefaultSampleRetention
+	}
+	cutoff := now.Add(-retain)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, smp := range append(existing, samples...) {
+		if smp.Time.Before(cutoff) {
+			continue
+		}
+		if err := enc.Encode(smp); err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to encode storage sample")
+		}
+	}
+	return writeFile(s.Path, buf.Bytes())
+}
+
+// writeFile writes data to path, creating it with fileMode regardless of the
+// umask. An existing file keeps its mode: only its owner could change it.
+func writeFile(path string, data []byte) error {
+	_, statErr := os.Stat(path)
+	if err := os.WriteFile(path, data, fileMode); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to write %s", path)
+	}
+	if !os.IsNotExist(statErr) {
+		return nil
+	}
+	if err := os.Chmod(path, fileMode); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to set permissions on %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package forecast
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_AppendPrunesAndSkipsTornLines(t *testing.T) {
+	s := Store{Path: filepath.Join(t.TempDir(), "samples.jsonl"), Retain: 48 * time.Hour}
+
+	got, err := s.Read()
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	require.NoError(t, s.Append(daily("live", 1, 2), t0.Add(24*time.Hour)))
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"time":"2026-10-0`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// Three days on, the first sample has aged out of the window.
+	require.NoError(t, s.Append(daily("live", 1, 2, 3, 4)[3:], t0.Add(3*24*time.Hour)))
+	got, err = s.Read()
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.EqualValues(t, 2, got[0].Bytes)
+	require.EqualValues(t, 4, got[1].Bytes)
+
+	fi, err := os.Stat(s.Path)
+	require.NoError(t, err)
+	require.Equal(t, fileMode, fi.Mode().Perm())
+}
+
+func TestVolumes_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "volumes.json")
+
+	vols, err := ReadVolumes(path)
+	require.NoError(t, err)
+	require.Empty(t, vols)
+
+	want := []Volume{{Name: "live", Path: "/mnt/bn/live"}, {Name: "archive", Path: "/mnt/bn/archive"}}
+	require.NoError(t, WriteVolumes(path, want))
+	vols, err = ReadVolumes(path)
+	require.NoError(t, err)
+	require.Equal(t, want, vols)
+}
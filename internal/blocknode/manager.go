@@ -47,10 +47,12 @@ var (
 
 // Manager handles block node setup and management operations.
 // Methods are grouped by concern across sibling files:
-//   - storage.go      — directory setup, PV/PVC lifecycle, path resolution
-//   - chart.go        — Helm install/upgrade/uninstall, StatefulSet and pod lifecycle, helm-owned Service teardown
-//   - values.go       — Helm values file computation and YAML injection helpers
-//   - reachability.go — post-upgrade external-reachability probe
+//   - storage.go        — directory setup, PV/PVC lifecycle, path resolution
+//   - storage_backup.go — storage backup/restore and backup compatibility checks
+//   - storage_gc.go     — detection and collection of retired optional storage
+//   - chart.go          — Helm install/upgrade/uninstall, StatefulSet and pod lifecycle, helm-owned Service teardown
+//   - values.go         — Helm values file computation and YAML injection helpers
+//   - reachability.go   — post-upgrade external-reachability probe
 type Manager struct {
 	fsManager       fsx.Manager
 	helmManager     helm.Manager
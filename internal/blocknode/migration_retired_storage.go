@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// migration_retired_storage.go implements the mirror of StorageMigration for a
+// PersistentVolume the Block Node chart stops mounting across a version
+// boundary (an OptionalStorage entry with MaxVersion set).
+//
+// The migration runs in phase 1 of BuildMigrationWorkflow, before the single
+// chart upgrade, while the StatefulSet still mounts the volume — so it cannot
+// remove anything. Nor can an unattended upgrade ask whether the data should
+// be kept. It therefore only reports what will be left behind (PV/PVC and the
+// host directory with its size) and points the operator at
+// `block node storage gc`, which archives or deletes it on confirmation.
+
+package blocknode
+
+import (
+	"context"
+
+	"github.com/hashgraph/solo-weaver/internal/migration"
+	"github.com/hashgraph/solo-weaver/pkg/semver"
+)
+
+// RetiredStorageMigration reports the leftovers of a retired optional storage
+// when an upgrade crosses its MaxVersion.
+type RetiredStorageMigration struct {
+	migration.VersionMigration
+	storage OptionalStorage
+}
+
+// NewRetiredStorageMigration creates the retirement migration for the given
+// optional storage entry, which must have a MaxVersion.
+func NewRetiredStorageMigration(optStorage OptionalStorage) *RetiredStorageMigration {
+	if optStorage.MaxVersion == "" {
+		panic(optStorage.Name + " storage is not retired; it has no MaxVersion")
+	}
+	return &RetiredStorageMigration{
+		VersionMigration: migration.NewVersionMigration(
+			optStorage.Name+"-storage-retired-v"+optStorage.MaxVersion,
+			"Report "+optStorage.Name+" storage PV/PVC and data no longer mounted by Block Node v"+optStorage.MaxVersion+"+",
+			optStorage.MaxVersion,
+		),
+		storage: optStorage,
+	}
+}
+
+// Applies extends VersionMigration.Applies (installed < MaxVersion <= target)
+// with a MinVersion guard: a release installed before the storage existed
+// never had it provisioned, so there is nothing to report.
+func (m *RetiredStorageMigration) Applies(mctx *migration.Context) (bool, error) {
+	applies, err := m.VersionMigration.Applies(mctx)
+	if err != nil || !applies {
+		return applies, err
+	}
+
+	installedVersion, _ := mctx.Data.String(migration.CtxKeyInstalledVersion)
+	installed, err := semver.NewSemver(installedVersion)
+	if err != nil {
+		return false, nil
+	}
+	minVer, err := semver.NewSemver(m.storage.MinVersion)
+	if err != nil {
+		return false, nil
+	}
+	return !installed.LessThan(minVer), nil
+}
+
+// Execute inspects the retiring volume and logs what the upgrade will orphan.
+// It never fails the upgrade: the report is advisory.
+func (m *RetiredStorageMigration) Execute(ctx context.Context, mctx *migration.Context) error {
+	manager, err := getManager(mctx)
+	if err != nil {
+		return err
+	}
+
+	logger := mctx.Logger
+	r, err := manager.inspectRetiredStorage(ctx, m.storage)
+	if err != nil {
+		logger.Warn().Err(err).Str("storage", m.storage.Name).Msg("Could not inspect retired storage")
+		return nil
+	}
+	if !r.Present() {
+		logger.Info().Str("storage", m.storage.Name).Msg("Retired storage has nothing left to collect")
+		return nil
+	}
+
+	logger.Warn().
+		Str("storage", m.storage.Name).
+		Str("pvc", r.PVC).
+		Str("pv", r.PV).
+		Str("capacity", r.Capacity).
+		Str("path", r.Path).
+		Int64("bytes", r.Bytes).
+		Str("retired_in", m.storage.MaxVersion).
+		Msg("The upgraded chart no longer mounts this storage; after the upgrade, run " +
+			"'solo-provisioner block node storage gc' to archive or delete it")
+	return nil
+}
+
+// Rollback is a no-op: Execute changes nothing.
+func (m *RetiredStorageMigration) Rollback(context.Context, *migration.Context) error {
+	return nil
+}
+
+// NewVerificationRetirementMigration creates the retirement migration for the
+// verification volume, dropped at BlockNodeVerificationRetirementVersion.
+func NewVerificationRetirementMigration() *RetiredStorageMigration {
+	for _, optStor := range optionalStorages {
+		if optStor.Name == "verification" {
+			return NewRetiredStorageMigration(optStor)
+		}
+	}
+	panic("verification storage not found in optional storage registry")
+}
@@ -23,8 +23,6 @@ import (
 	"context"
 
 	"github.com/hashgraph/solo-weaver/internal/migration"
-	"github.com/hashgraph/solo-weaver/pkg/config"
-	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/joomcode/errorx"
 )
 
@@ -111,36 +109,11 @@ func (m *StorageMigration) Execute(ctx context.Context, mctx *migration.Context)
 		return err
 	}
 
-	logger := mctx.Logger
-	logger.Info().Str("storage", m.storage.Name).Msg("Creating storage directory and PV/PVC")
-
-	// Create the storage directory on host
-	storagePath, _, err := manager.resolveOptionalStoragePathAndSize(m.storage)
-	if err != nil {
-		return errorx.IllegalState.Wrap(err, "failed to resolve %s storage path", m.storage.Name)
-	}
-
-	if storagePath != "" {
-		logger.Info().Str("path", storagePath).Str("storage", m.storage.Name).Msg("Creating storage directory")
-		if err := manager.fsManager.CreateDirectory(storagePath, true); err != nil {
-			return errorx.IllegalState.Wrap(err, "failed to create %s storage directory", m.storage.Name)
-		}
-		if err := manager.fsManager.WritePermissions(storagePath, models.DefaultStorageDirPerm, true); err != nil {
-			return errorx.IllegalState.Wrap(err, "failed to set permissions on %s storage", m.storage.Name)
-		}
-
-		if err := manager.fsManager.WriteOwnerByName(storagePath, config.HederaUserName(), config.HederaGroupName(), true); err != nil {
-			return errorx.IllegalState.Wrap(err, "failed to set ownership on %s storage", m.storage.Name)
-		}
-	}
-
-	// Create the PV/PVC
-	logger.Info().Str("storage", m.storage.Name).Msg("Creating storage PV/PVC")
-	if err := manager.CreateOptionalStorage(ctx, models.Paths().TempDir, m.storage); err != nil {
-		return errorx.IllegalState.Wrap(err, "failed to create %s storage PV/PVC", m.storage.Name)
+	if err := manager.provisionOptionalStorage(ctx, m.storage); err != nil {
+		return err
 	}
 
-	logger.Info().Str("storage", m.storage.Name).Msg("Storage PV/PVC created successfully")
+	mctx.Logger.Info().Str("storage", m.storage.Name).Msg("Storage PV/PVC created successfully")
 	return nil
 }
 
@@ -158,8 +131,8 @@ func (m *StorageMigration) Rollback(ctx context.Context, mctx *migration.Context
 	if err := manager.kubeClient.DeletePVC(ctx, manager.blockNodeInputs.Namespace, m.storage.PVCName); err != nil {
 		logger.Warn().Err(err).Str("pvc", m.storage.PVCName).Msg("Could not delete PVC during rollback")
 	}
-	if err := manager.kubeClient.DeletePV(ctx, m.storage.PVName); err != nil {
-		logger.Warn().Err(err).Str("pv", m.storage.PVName).Msg("Could not delete PV during rollback")
+	if err := manager.kubeClient.DeletePV(ctx, manager.pvPrefix()+m.storage.PVName); err != nil {
+		logger.Warn().Err(err).Str("pv", manager.pvPrefix()+m.storage.PVName).Msg("Could not delete PV during rollback")
 	}
 
 	logger.Info().Str("storage", m.storage.Name).Msg("Storage rollback completed")
@@ -205,8 +178,8 @@ func NewPluginsStorageMigration() *StorageMigration {
 // mounting the applicationStateFacility volume (hiero-ledger/hiero-block-node#3025)
 // and stops mounting the verification volume in lockstep. The migration creates
 // the new PV/PVC; existing verification PV/PVC objects are left in place — the
-// chart no longer references them so they become orphan state, and cleanup is
-// a manual operator step.
+// chart no longer references them. NewVerificationRetirementMigration reports
+// them and `block node storage gc` collects them.
 func NewApplicationStateMigration() *StorageMigration {
 	for _, optStor := range optionalStorages {
 		if optStor.Name == "application-state" {
@@ -4,9 +4,11 @@
 //
 // This file contains:
 //   - BuildMigrationWorkflow(): Builds an automa workflow for applicable migrations during upgrades
+//   - applicableMigrations(): Resolves the migrations an upgrade applies (shared with PlanUpgrade)
 //
 // The workflow uses a two-phase approach:
-//   - Phase 1: Each applicable StorageMigration creates its storage dir + PV/PVC.
+//   - Phase 1: Each applicable StorageMigration creates its storage dir + PV/PVC;
+//     each applicable RetiredStorageMigration reports what the upgrade orphans.
 //   - Phase 2: A single "upgrade" step deletes the StatefulSet (orphan cascade)
 //     and performs one Helm upgrade to the final target version.
 //
@@ -53,20 +55,7 @@ func BuildMigrationWorkflow(manager *Manager, profile, valuesFile string) (*auto
 		return nil, nil
 	}
 
-	// Build context
-	mctx := &migration.Context{
-		Component: migration.ScopeBlockNode,
-		Logger:    manager.logger,
-		Data:      &automa.SyncStateBag{},
-	}
-	mctx.Data.Set(migration.CtxKeyInstalledVersion, installedVersion)
-	mctx.Data.Set(migration.CtxKeyTargetVersion, manager.blockNodeInputs.ChartVersion)
-	// #913: expose the plugins-baked signal to StorageMigration.Applies. It must be
-	// set before GetApplicableMigrations (which evaluates Applies); the Manager
-	// itself is only added to the context afterwards, for Execute/Rollback.
-	mctx.Data.Set(ctxKeyManagePlugins, manager.managesPluginsStorage())
-
-	migrations, err := migration.GetApplicableMigrations(migration.ScopeBlockNode, mctx)
+	mctx, migrations, err := manager.applicableMigrations(installedVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +81,30 @@ func BuildMigrationWorkflow(manager *Manager, profile, valuesFile string) (*auto
 	return wf, nil
 }
 
+// applicableMigrations returns the block node migrations that apply to an
+// upgrade from installedVersion to the manager's target chart version, with the
+// context they were evaluated against. Both the migration workflow and the
+// upgrade plan preview resolve them here so the two cannot disagree.
+func (m *Manager) applicableMigrations(installedVersion string) (*migration.Context, []migration.Migration, error) {
+	mctx := &migration.Context{
+		Component: migration.ScopeBlockNode,
+		Logger:    m.logger,
+		Data:      &automa.SyncStateBag{},
+	}
+	mctx.Data.Set(migration.CtxKeyInstalledVersion, installedVersion)
+	mctx.Data.Set(migration.CtxKeyTargetVersion, m.blockNodeInputs.ChartVersion)
+	// #913: expose the plugins-baked signal to StorageMigration.Applies. It must be
+	// set before GetApplicableMigrations (which evaluates Applies); the Manager
+	// itself is only added to the context afterwards, for Execute/Rollback.
+	mctx.Data.Set(ctxKeyManagePlugins, m.managesPluginsStorage())
+
+	migrations, err := migration.GetApplicableMigrations(migration.ScopeBlockNode, mctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mctx, migrations, nil
+}
+
 // buildMigrationUpgradeStep creates the final workflow step that performs a single
 // StatefulSet delete + Helm upgrade after all storage migrations have created their PV/PVCs.
 //
@@ -171,8 +184,8 @@ func buildMigrationUpgradeStep(
 				if delErr := manager.kubeClient.DeletePVC(ctx, manager.blockNodeInputs.Namespace, sm.storage.PVCName); delErr != nil {
 					l.Warn().Err(delErr).Str("pvc", sm.storage.PVCName).Msg("Could not delete PVC")
 				}
-				if delErr := manager.kubeClient.DeletePV(ctx, sm.storage.PVName); delErr != nil {
-					l.Warn().Err(delErr).Str("pv", sm.storage.PVName).Msg("Could not delete PV")
+				if delErr := manager.kubeClient.DeletePV(ctx, manager.pvPrefix()+sm.storage.PVName); delErr != nil {
+					l.Warn().Err(delErr).Str("pv", manager.pvPrefix()+sm.storage.PVName).Msg("Could not delete PV")
 				}
 			}
 
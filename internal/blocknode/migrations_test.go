@@ -335,6 +335,37 @@ func TestApplicationStateMigration_Applies(t *testing.T) {
 	}
 }
 
+func TestVerificationRetirementMigration_Applies(t *testing.T) {
+	m := NewVerificationRetirementMigration()
+	assert.Equal(t, "verification-storage-retired-v0.37.0-0", m.ID())
+
+	tests := []struct {
+		name             string
+		installedVersion string
+		targetVersion    string
+		expectApplies    bool
+	}{
+		{name: "0.36.0 -> 0.37.0 crosses the retirement", installedVersion: "0.36.0", targetVersion: "0.37.0", expectApplies: true},
+		{name: "0.26.2 -> 0.37.0-rc1 crosses the retirement", installedVersion: "0.26.2", targetVersion: "0.37.0-rc1", expectApplies: true},
+		{name: "0.25.0 -> 0.37.0 never provisioned verification", installedVersion: "0.25.0", targetVersion: "0.37.0", expectApplies: false},
+		{name: "0.35.1 -> 0.36.0 is still pre-retirement", installedVersion: "0.35.1", targetVersion: "0.36.0", expectApplies: false},
+		{name: "0.37.0 -> 0.37.5 is already past it", installedVersion: "0.37.0", targetVersion: "0.37.5", expectApplies: false},
+		{name: "fresh install", installedVersion: "", targetVersion: "0.37.0", expectApplies: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &migration.Context{Data: &automa.SyncStateBag{}}
+			ctx.Data.Set(migration.CtxKeyInstalledVersion, tt.installedVersion)
+			ctx.Data.Set(migration.CtxKeyTargetVersion, tt.targetVersion)
+			applies, err := m.Applies(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectApplies, applies)
+		})
+	}
+
+	require.Panics(t, func() { NewRetiredStorageMigration(OptionalStorage{Name: "plugins", MinVersion: "0.28.1"}) })
+}
+
 // TestGetApplicable_BlockNode tests finding applicable migrations using global registry
 func TestGetApplicable_BlockNode(t *testing.T) {
 	// Clear and register to ensure clean state
@@ -342,6 +373,7 @@ func TestGetApplicable_BlockNode(t *testing.T) {
 	migration.Register(migration.ScopeBlockNode, NewVerificationStorageMigration())
 	migration.Register(migration.ScopeBlockNode, NewPluginsStorageMigration())
 	migration.Register(migration.ScopeBlockNode, NewApplicationStateMigration())
+	migration.Register(migration.ScopeBlockNode, NewVerificationRetirementMigration())
 	defer migration.ClearRegistry()
 
 	tests := []struct {
@@ -388,10 +420,10 @@ func TestGetApplicable_BlockNode(t *testing.T) {
 			expectCount:      0,
 		},
 		{
-			name:             "upgrade from 0.35.1 to 0.37.0 requires application-state migration only",
+			name:             "upgrade from 0.35.1 to 0.37.0 requires application-state migration and verification retirement",
 			installedVersion: "0.35.1",
 			targetVersion:    "0.37.0",
-			expectCount:      1,
+			expectCount:      2,
 		},
 		{
 			name:             "upgrade from 0.27.0 to 0.36.0 requires plugins migration only",
@@ -410,7 +442,8 @@ func TestGetApplicable_BlockNode(t *testing.T) {
 		{
 			// Regression: skipping past the 0.37.0 cutover must NOT pull in the
 			// verification migration (it has retired) but MUST pull in plugins
-			// and application-state.
+			// and application-state. Verification was never provisioned, so its
+			// retirement has nothing to report either.
 			name:             "skip across cutover (0.25.0 -> 0.37.0) requires plugins + application-state",
 			installedVersion: "0.25.0",
 			targetVersion:    "0.37.0",
@@ -15,8 +15,10 @@
 // Retiring an existing storage (a chart version removes a volume) is the mirror
 // operation: set MaxVersion to the first chart version that no longer ships the
 // volume. The registry filter (GetApplicableOptionalStorages) will drop the
-// entry from that version onward; the old PV/PVC on already-installed clusters
-// remains untouched (chart no longer references it; cleanup is a manual step).
+// entry from that version onward. The old PV/PVC and host directory on
+// already-installed clusters are left in place by the upgrade; register a
+// RetiredStorageMigration (migration_retired_storage.go) so the upgrade reports
+// them, and `block node storage gc` (storage_gc.go) archives or deletes them.
 
 package blocknode
 
@@ -85,6 +87,12 @@ type OptionalStorage struct {
 	// above.
 	MaxVersion string
 
+	// NoRollback marks a boundary the block node cannot be rolled back across:
+	// once a version at or above MinVersion has run, earlier versions cannot
+	// read the state it keeps on this volume. `block node rollback` refuses to
+	// go below MinVersion unless forced (see PlanRollback).
+	NoRollback bool
+
 	// PVName is the PersistentVolume resource name (e.g., "verification-storage-pv").
 	PVName string
 
@@ -132,6 +140,7 @@ var optionalStorages = []OptionalStorage{
 		Name:           "application-state",
 		PersistenceKey: "applicationState",
 		MinVersion:     BlockNodeApplicationStateRequiredVersion,
+		NoRollback:     true,
 		PVName:         "application-state-storage-pv",
 		PVCName:        "application-state-storage-pvc",
 		DirName:        "application-state",
@@ -0,0 +1,335 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	oslib "os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/pkg/hardware"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/semver"
+	"github.com/joomcode/errorx"
+	"gopkg.in/yaml.v3"
+)
+
+// presetSchemaVersion is the only schemaVersion a preset file may declare.
+const presetSchemaVersion = 1
+
+//go:embed presets/*.yaml
+var embeddedPresetsFS embed.FS
+
+var presetIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// PluginPreset is one plugin preset file: the plugin list by chart version and
+// the hardware floors for the deployment tier it stands for. See
+// presets/tier1-lfh.yaml for the schema.
+type PluginPreset struct {
+	SchemaVersion int                 `yaml:"schemaVersion"`
+	ID            string              `yaml:"id"`
+	Label         string              `yaml:"label"`
+	Plugins       []PresetPluginRange `yaml:"plugins"`
+	Hardware      PresetHardware      `yaml:"hardware"`
+
+	// Source is the file the preset was loaded from ("embedded:<name>" for
+	// the presets shipped in the binary).
+	Source string `yaml:"-"`
+}
+
+// PresetPluginRange is the plugin list of a preset from MinVersion (inclusive)
+// up to the MinVersion of the next range. An empty MinVersion, only allowed on
+// the first range, covers every older chart version.
+type PresetPluginRange struct {
+	MinVersion string   `yaml:"minVersion"`
+	Names      []string `yaml:"names"`
+}
+
+// PresetHardware are the hardware floors of a preset.
+type PresetHardware struct {
+	// Default also applies the floors when no other preset with floors is
+	// selected (no preset, custom, none).
+	Default bool          `yaml:"default"`
+	Floors  []PresetFloor `yaml:"floors"`
+}
+
+// PresetFloor is a hardware floor for some profiles (all when empty) and, when
+// Plugins is set, only for deployments whose plugin list has all of them.
+type PresetFloor struct {
+	Profiles  []string `yaml:"profiles"`
+	Plugins   []string `yaml:"plugins"`
+	CpuCores  int      `yaml:"cpuCores"`
+	MemoryGB  int      `yaml:"memoryGB"`
+	StorageGB int      `yaml:"storageGB"`
+	Why       string   `yaml:"why"`
+}
+
+// PluginListFor returns the comma-separated plugin list of the preset at the
+// given chart version, or "" when the preset has none for it. Empty or
+// unparseable versions get the newest list.
+func (p *PluginPreset) PluginListFor(chartVersion string) string {
+	if len(p.Plugins) == 0 {
+		return ""
+	}
+	latest := p.Plugins[len(p.Plugins)-1]
+	if chartVersion == "" {
+		return strings.Join(latest.Names, ",")
+	}
+	target, err := semver.NewSemver(chartVersion)
+	if err != nil {
+		return strings.Join(latest.Names, ",")
+	}
+	for i := len(p.Plugins) - 1; i >= 0; i-- {
+		r := p.Plugins[i]
+		if r.MinVersion == "" {
+			return strings.Join(r.Names, ",")
+		}
+		minVer, _ := semver.NewSemver(r.MinVersion) // validated at load
+		if !target.LessThan(minVer) {
+			return strings.Join(r.Names, ",")
+		}
+	}
+	return ""
+}
+
+// validate enforces the schema on a parsed preset file.
+func (p *PluginPreset) validate() error {
+	if p.SchemaVersion != presetSchemaVersion {
+		return errorx.IllegalFormat.New("unsupported schemaVersion %d (want %d)", p.SchemaVersion, presetSchemaVersion)
+	}
+	if !presetIDPattern.MatchString(p.ID) {
+		return errorx.IllegalFormat.New("invalid id %q: use lowercase letters, digits and '-'", p.ID)
+	}
+	if p.ID == PresetCustom || p.ID == PresetNone {
+		return errorx.IllegalFormat.New("id %q is reserved", p.ID)
+	}
+	if strings.TrimSpace(p.Label) == "" {
+		return errorx.IllegalFormat.New("label is required")
+	}
+	if len(p.Plugins) == 0 {
+		return errorx.IllegalFormat.New("plugins must declare at least one version range")
+	}
+
+	var prev *semver.Semver
+	for i, r := range p.Plugins {
+		if err := models.ValidatePluginList(strings.Join(r.Names, ",")); err != nil {
+			return errorx.IllegalFormat.Wrap(err, "plugins[%d]", i)
+		}
+		if r.MinVersion == "" {
+			if i > 0 {
+				return errorx.IllegalFormat.New("plugins[%d]: only the first range may omit minVersion", i)
+			}
+			continue
+		}
+		v, err := semver.NewSemver(r.MinVersion)
+		if err != nil {
+			return errorx.IllegalFormat.Wrap(err, "plugins[%d]: invalid minVersion %q", i, r.MinVersion)
+		}
+		if prev != nil && !prev.LessThan(v) {
+			return errorx.IllegalFormat.New("plugins[%d]: minVersion %q must be greater than the previous range's", i, r.MinVersion)
+		}
+		prev = &v
+	}
+
+	for i, f := range p.Hardware.Floors {
+		for _, profile := range f.Profiles {
+			if !hardware.IsValidProfile(profile) {
+				return errorx.IllegalFormat.New("hardware.floors[%d]: unsupported profile %q (supported: %v)",
+					i, profile, models.SupportedProfiles())
+			}
+		}
+		if f.CpuCores < 0 || f.MemoryGB < 0 || f.StorageGB < 0 {
+			return errorx.IllegalFormat.New("hardware.floors[%d]: cpuCores, memoryGB and storageGB must not be negative", i)
+		}
+		if strings.TrimSpace(f.Why) == "" {
+			return errorx.IllegalFormat.New("hardware.floors[%d]: why is required", i)
+		}
+	}
+	return nil
+}
+
+// sizingHints returns the preset's floors for pkg/hardware.
+func (p *PluginPreset) sizingHints() []hardware.SizingHint {
+	hints := make([]hardware.SizingHint, 0, len(p.Hardware.Floors))
+	for _, f := range p.Hardware.Floors {
+		hints = append(hints, hardware.SizingHint{
+			Preset:   p.ID,
+			Default:  p.Hardware.Default,
+			Profiles: f.Profiles,
+			Plugins:  f.Plugins,
+			Contribution: hardware.Contribution{
+				CpuCores:  f.CpuCores,
+				MemoryGB:  f.MemoryGB,
+				StorageGB: f.StorageGB,
+				Why:       f.Why,
+			},
+		})
+	}
+	return hints
+}
+
+// PresetCatalog is the set of plugin presets: the embedded ones, then those of
+// the operator directory, in file name order.
+type PresetCatalog struct {
+	presets []*PluginPreset
+}
+
+// Presets returns the presets in display order.
+func (c *PresetCatalog) Presets() []*PluginPreset {
+	out := make([]*PluginPreset, len(c.presets))
+	copy(out, c.presets)
+	return out
+}
+
+// Get returns the preset with the given ID.
+func (c *PresetCatalog) Get(id string) (*PluginPreset, bool) {
+	for _, p := range c.presets {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// SizingHints returns the hardware floors of every preset.
+func (c *PresetCatalog) SizingHints() []hardware.SizingHint {
+	var hints []hardware.SizingHint
+	for _, p := range c.presets {
+		hints = append(hints, p.sizingHints()...)
+	}
+	return hints
+}
+
+// add appends p, or replaces the preset with the same ID in place.
+func (c *PresetCatalog) add(p *PluginPreset) {
+	for i, existing := range c.presets {
+		if existing.ID == p.ID {
+			c.presets[i] = p
+			return
+		}
+	}
+	c.presets = append(c.presets, p)
+}
+
+// loadPresetFiles parses and validates every *.yaml/*.yml file of fsys's dir
+// into c, in file name order.
+func (c *PresetCatalog) loadPresetFiles(fsys fs.FS, dir, sourcePrefix string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		source := sourcePrefix + name
+		data, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(dir, name)))
+		if err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to read plugin preset %s", source)
+		}
+		// Unknown keys are errors: a misspelt floor field would otherwise
+		// silently drop the floor to zero.
+		p := &PluginPreset{}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(p); err != nil {
+			return errorx.IllegalFormat.Wrap(err, "failed to parse plugin preset %s", source)
+		}
+		if err := p.validate(); err != nil {
+			return errorx.Decorate(err, "invalid plugin preset %s", source)
+		}
+		p.Source = source
+		c.add(p)
+	}
+	return nil
+}
+
+// LoadPresetCatalog loads the embedded presets, then the *.yaml/*.yml files of
+// dir on top of them; a file with the ID of a loaded preset replaces it. A
+// missing dir is not an error. Every file is validated and the first invalid
+// one fails the load.
+func LoadPresetCatalog(dir string) (*PresetCatalog, error) {
+	c := &PresetCatalog{}
+	if err := c.loadPresetFiles(embeddedPresetsFS, "presets", "embedded:"); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "embedded plugin presets are invalid")
+	}
+	if dir == "" {
+		return c, nil
+	}
+	if _, err := oslib.Stat(dir); err != nil {
+		if oslib.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, errorx.IllegalState.Wrap(err, "failed to read plugin preset directory %s", dir)
+	}
+	if err := c.loadPresetFiles(oslib.DirFS(dir), ".", dir+"/"); err != nil {
+		return nil, errorx.Decorate(err, "failed to load plugin presets from %s", dir).
+			WithProperty(models.ErrPropertyResolution,
+				"fix or remove the file; see 'solo-provisioner block node presets show tier1-lfh' for a valid preset")
+	}
+	return c, nil
+}
+
+var (
+	activePresets     = mustLoadEmbeddedPresets()
+	activePresetsMu   sync.RWMutex
+	loadPresetsOnce   sync.Once
+	loadPresetsResult error
+)
+
+// mustLoadEmbeddedPresets loads the presets shipped in the binary; they are
+// validated by the tests, so a failure is a build defect.
+func mustLoadEmbeddedPresets() *PresetCatalog {
+	c, err := LoadPresetCatalog("")
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// LoadPluginPresets loads the operator's plugin presets
+// (models.Paths().PluginPresetsDir) on top of the embedded ones, once per
+// process, and makes them the presets PluginListForPreset and friends resolve
+// against and the hardware floors preflight checks enforce. Commands that take
+// --plugin-preset call it first so an invalid file fails them up front; until
+// then, and after a failed load, only the embedded presets are in use.
+func LoadPluginPresets() (*PresetCatalog, error) {
+	loadPresetsOnce.Do(func() {
+		c, err := LoadPresetCatalog(models.Paths().PluginPresetsDir)
+		if err != nil {
+			loadPresetsResult = err
+			return
+		}
+		setActivePresets(c)
+		logx.As().Debug().Int("presets", len(c.presets)).Msg("Loaded block node plugin presets")
+	})
+	return presetCatalog(), loadPresetsResult
+}
+
+func setActivePresets(c *PresetCatalog) {
+	activePresetsMu.Lock()
+	activePresets = c
+	activePresetsMu.Unlock()
+	hardware.SetBlockNodeSizing(c.SizingHints())
+}
+
+// presetCatalog returns the presets in use.
+func presetCatalog() *PresetCatalog {
+	activePresetsMu.RLock()
+	defer activePresetsMu.RUnlock()
+	return activePresets
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/pkg/hardware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPresetYAML = `schemaVersion: 1
+id: tier2-archive
+label: "Tier 2 — Archive"
+plugins:
+  - minVersion: "0.37.1"
+    names: [backfill, blocks-file-historic, health]
+  - minVersion: "0.39.1"
+    names: [backfill, block-verification, blocks-file-historic, health]
+hardware:
+  floors:
+    - profiles: [testnet]
+      cpuCores: 4
+      memoryGB: 16
+      storageGB: 8000
+      why: "tier 2 archive"
+`
+
+func writePreset(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+func TestLoadPresetCatalog_Embedded(t *testing.T) {
+	c, err := LoadPresetCatalog("")
+	require.NoError(t, err)
+
+	var ids []string
+	for _, p := range c.Presets() {
+		ids = append(ids, p.ID)
+		assert.Equal(t, "embedded:"+p.ID+".yaml", p.Source)
+	}
+	assert.Equal(t, []string{PresetTier1LFH, PresetTier1RFH}, ids)
+}
+
+// The built-in hardware floors are the fallback until a catalog is loaded; they
+// must not drift from the embedded preset files.
+func TestLoadPresetCatalog_EmbeddedSizingMatchesHardwareDefaults(t *testing.T) {
+	c, err := LoadPresetCatalog("")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, hardware.DefaultBlockNodeSizing(), c.SizingHints())
+}
+
+func TestLoadPresetCatalog_MissingDirIsEmbeddedOnly(t *testing.T) {
+	c, err := LoadPresetCatalog(filepath.Join(t.TempDir(), "absent"))
+	require.NoError(t, err)
+	assert.Len(t, c.Presets(), 2)
+}
+
+func TestLoadPresetCatalog_OperatorPresets(t *testing.T) {
+	dir := t.TempDir()
+	writePreset(t, dir, "tier2-archive.yaml", testPresetYAML)
+	writePreset(t, dir, "rfh.yml", `schemaVersion: 1
+id: tier1-rfh
+label: "Tier 1 — RFH (site override)"
+plugins:
+  - names: [health, cloud-storage-archive]
+`)
+	writePreset(t, dir, "README.md", "not a preset")
+
+	c, err := LoadPresetCatalog(dir)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, p := range c.Presets() {
+		ids = append(ids, p.ID)
+	}
+	assert.Equal(t, []string{PresetTier1LFH, PresetTier1RFH, "tier2-archive"}, ids,
+		"an override keeps the embedded position, new presets follow")
+
+	rfh, ok := c.Get(PresetTier1RFH)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, "rfh.yml"), rfh.Source)
+	assert.Equal(t, "health,cloud-storage-archive", rfh.PluginListFor("0.40.0"))
+	assert.Empty(t, rfh.Hardware.Floors, "the override replaces the floors too")
+
+	archive, _ := c.Get("tier2-archive")
+	assert.Equal(t, "backfill,blocks-file-historic,health", archive.PluginListFor("0.38.0"))
+	assert.Equal(t, "backfill,block-verification,blocks-file-historic,health", archive.PluginListFor(""))
+	assert.Empty(t, archive.PluginListFor("0.36.0"), "no list below the first range")
+}
+
+func TestLoadPresetCatalog_RejectsInvalidFiles(t *testing.T) {
+	cases := map[string]string{
+		"schema version": `schemaVersion: 2
+id: x
+label: X
+plugins: [{names: [health]}]
+`,
+		"reserved id": `schemaVersion: 1
+id: none
+label: X
+plugins: [{names: [health]}]
+`,
+		"no plugins": `schemaVersion: 1
+id: x
+label: X
+`,
+		"descending versions": `schemaVersion: 1
+id: x
+label: X
+plugins:
+  - {minVersion: "0.39.1", names: [health]}
+  - {minVersion: "0.37.1", names: [health]}
+`,
+		"late baseline": `schemaVersion: 1
+id: x
+label: X
+plugins:
+  - {minVersion: "0.37.1", names: [health]}
+  - {names: [health]}
+`,
+		"empty plugin name": `schemaVersion: 1
+id: x
+label: X
+plugins: [{names: [health, ""]}]
+`,
+		"unknown profile": `schemaVersion: 1
+id: x
+label: X
+plugins: [{names: [health]}]
+hardware:
+  floors: [{profiles: [moonnet], cpuCores: 1, why: w}]
+`,
+		"floor without why": `schemaVersion: 1
+id: x
+label: X
+plugins: [{names: [health]}]
+hardware:
+  floors: [{cpuCores: 1}]
+`,
+		"unknown field": `schemaVersion: 1
+id: x
+label: X
+plugins: [{names: [health]}]
+hardware:
+  floors: [{cpucores: 1, why: w}]
+`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writePreset(t, dir, "bad.yaml", content)
+			_, err := LoadPresetCatalog(dir)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), filepath.Join(dir, "bad.yaml"))
+		})
+	}
+}
+
+func TestPresetCatalog_SizingHints(t *testing.T) {
+	dir := t.TempDir()
+	writePreset(t, dir, "tier2-archive.yaml", testPresetYAML)
+	c, err := LoadPresetCatalog(dir)
+	require.NoError(t, err)
+
+	var archive []hardware.SizingHint
+	for _, h := range c.SizingHints() {
+		if h.Preset == "tier2-archive" {
+			archive = append(archive, h)
+		}
+	}
+	require.Len(t, archive, 1)
+	assert.Equal(t, []string{"testnet"}, archive[0].Profiles)
+	assert.Equal(t, 8000, archive[0].StorageGB)
+	assert.False(t, archive[0].Default)
+}
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+// ServerStatusMethod is the block node API call a sample makes: it is cheap,
+// public to every client and answered by the block node application itself,
+// so an answer proves more than an open port.
+const ServerStatusMethod = "/org.hiero.block.api.BlockNodeService/serverStatus"
+
+// maxResponseBytes bounds the serverStatus response read.
+const maxResponseBytes = 1 << 20
+
+// caller makes serverStatus calls as a gRPC client would: a unary call is an
+// HTTP/2 POST of one length-prefixed protobuf message, answered by one
+// message and a grpc-status trailer. The request message is empty, so no
+// protobuf runtime is needed.
+type caller struct {
+	url       string
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+func newCaller(target Target, timeout time.Duration) *caller {
+	c := &caller{url: "http://" + target.ServiceAddr() + ServerStatusMethod, timeout: timeout}
+	if target.TLS != nil {
+		// The certificate is judged by checkTLS; the call only needs the
+		// session, so it goes ahead with a certificate checkTLS rejects.
+		c.url = "https://" + target.ServiceAddr() + ServerStatusMethod
+		c.tlsConfig = &tls.Config{
+			ServerName:         target.TLS.ServerName,
+			InsecureSkipVerify: true, // #nosec G402 -- verified separately by checkTLS
+			NextProtos:         []string{"h2"},
+		}
+	}
+	return c
+}
+
+// serverStatus makes one call on a new connection.
+func (c *caller) serverStatus(ctx context.Context) (Sample, *ServerStatus) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// The transport dials on its own goroutine, which may still be running
+	// when a timed-out Do returns.
+	var (
+		mu        sync.Mutex
+		connect   time.Duration
+		connected time.Time
+	)
+	dialed := func() (time.Duration, time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		return connect, connected
+	}
+
+	var smp Sample
+	dialer := net.Dialer{}
+	protocols := new(http.Protocols)
+	transport := &http.Transport{
+		TLSClientConfig:   c.tlsConfig,
+		DisableKeepAlives: true,
+		Protocols:         protocols,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			start := time.Now()
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err == nil {
+				mu.Lock()
+				connected = time.Now()
+				connect = connected.Sub(start)
+				mu.Unlock()
+			}
+			return conn, err
+		},
+	}
+	if c.tlsConfig != nil {
+		protocols.SetHTTP2(true)
+	} else {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	defer transport.CloseIdleConnections()
+
+	fail := func(err error) (Sample, *ServerStatus) {
+		var at time.Time
+		smp.Connect, at = dialed()
+		smp.Error = err.Error()
+		switch {
+		case at.IsZero():
+			smp.Failure = dialFailure(err)
+		case errors.Is(err, context.DeadlineExceeded) || isTimeout(err):
+			smp.Failure = FailureResponseTimeout
+		default:
+			smp.Failure = FailureCall
+		}
+		return smp, nil
+	}
+
+	// The empty ServerStatusRequest: uncompressed, zero length.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(make([]byte, 5)))
+	if err != nil {
+		return fail(errorx.InternalError.Wrap(err, "build serverStatus request"))
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+	var at time.Time
+	smp.Connect, at = dialed()
+	smp.FirstByte = time.Since(at)
+
+	if resp.StatusCode != http.StatusOK {
+		return fail(errorx.ExternalError.New("serverStatus returned HTTP status %d", resp.StatusCode))
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/grpc") {
+		return fail(errorx.ExternalError.New("serverStatus answered with content type %q, not gRPC", ct))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fail(err)
+	}
+	if err := grpcStatus(resp); err != nil {
+		return fail(err)
+	}
+	msg, err := unframe(body)
+	if err != nil {
+		return fail(err)
+	}
+	status, err := decodeServerStatus(msg)
+	if err != nil {
+		return fail(err)
+	}
+	return smp, status
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// grpcStatus returns the call's error status. A server that fails before
+// sending a message puts grpc-status in the headers ("trailers-only").
+func grpcStatus(resp *http.Response) error {
+	code, msg := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if code == "" {
+		code, msg = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	switch code {
+	case "0":
+		return nil
+	case "":
+		return errorx.ExternalError.New("serverStatus response has no grpc-status")
+	}
+	if msg != "" {
+		return errorx.ExternalError.New("serverStatus failed with gRPC status %s: %s", code, msg)
+	}
+	return errorx.ExternalError.New("serverStatus failed with gRPC status %s", code)
+}
+
+// unframe returns the message of a single length-prefixed gRPC frame.
+func unframe(body []byte) ([]byte, error) {
+	if len(body) < 5 {
+		return nil, errorx.IllegalFormat.New("serverStatus response has no message")
+	}
+	if body[0] != 0 {
+		return nil, errorx.IllegalFormat.New("serverStatus response is compressed")
+	}
+	n := binary.BigEndian.Uint32(body[1:5])
+	if uint64(len(body)-5) < uint64(n) {
+		return nil, errorx.IllegalFormat.New("serverStatus response is truncated")
+	}
+	return body[5 : 5+n], nil
+}
+
+// decodeServerStatus reads the block range of a ServerStatusResponse
+// (first_available_block = 1, last_available_block = 2) and skips every
+// other field.
+func decodeServerStatus(msg []byte) (*ServerStatus, error) {
+	status := &ServerStatus{}
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return nil, errorx.IllegalFormat.New("malformed serverStatus response")
+		}
+		msg = msg[n:]
+		field, wire := key>>3, key&7
+
+		var value uint64
+		switch wire {
+		case 0: // varint
+			value, n = binary.Uvarint(msg)
+		case 1: // fixed64
+			n = 8
+		case 2: // length-delimited
+			var l uint64
+			l, n = binary.Uvarint(msg)
+			if n > 0 {
+				if uint64(len(msg)-n) < l {
+					n = -1
+				} else {
+					n += int(l)
+				}
+			}
+		case 5: // fixed32
+			n = 4
+		default:
+			n = -1
+		}
+		if n <= 0 || n > len(msg) {
+			return nil, errorx.IllegalFormat.New("malformed serverStatus response")
+		}
+		msg = msg[n:]
+
+		if wire == 0 {
+			switch field {
+			case 1:
+				status.FirstAvailableBlock = value
+			case 2:
+				status.LastAvailableBlock = value
+			}
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,374 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package probe actively checks that a block node answers from outside its
+// pod: it calls the block node's gRPC serverStatus over the same LoadBalancer
+// path an external client takes, measures connect and first-byte latency over
+// several samples, dials every published port and checks the TLS certificate
+// when one is configured. The verdict tells a network blackhole (MetalLB,
+// Cilium, a firewall) from a block node that accepts connections but does not
+// answer.
+//
+// `block node probe` runs it on demand and the daemon's probe monitor on a
+// schedule; both append the result to the same history.
+package probe
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+// Defaults of Options.
+const (
+	DefaultSamples  = 5
+	DefaultTimeout  = 5 * time.Second
+	DefaultInterval = 200 * time.Millisecond
+)
+
+// Verdict summarises a probe.
+type Verdict string
+
+const (
+	// VerdictHealthy: every sample was answered and every required port
+	// accepted a connection.
+	VerdictHealthy Verdict = "healthy"
+	// VerdictDegraded: the block node answered, but some samples failed, a
+	// required port did not accept a connection or the certificate is not
+	// valid.
+	VerdictDegraded Verdict = "degraded"
+	// VerdictUnhealthy: connections were accepted but every call failed with
+	// an error, e.g. a gRPC error status.
+	VerdictUnhealthy Verdict = "unhealthy"
+	// VerdictStalled: connections were accepted but no call was answered in
+	// time. The network path works; the block node does not respond.
+	VerdictStalled Verdict = "stalled"
+	// VerdictRefused: every connection was actively refused: the address
+	// answers, but nothing listens behind the port.
+	VerdictRefused Verdict = "refused"
+	// VerdictBlackhole: no connection was answered at all: packets to the
+	// address are dropped, the signature of a MetalLB, Cilium or firewall
+	// fault.
+	VerdictBlackhole Verdict = "blackhole"
+)
+
+// Failed reports whether the block node could not be used at all.
+func (v Verdict) Failed() bool {
+	return v != VerdictHealthy && v != VerdictDegraded
+}
+
+// Failure classifies why a sample or a port dial failed.
+type Failure string
+
+const (
+	FailureConnectTimeout  Failure = "connect-timeout"
+	FailureConnectRefused  Failure = "connect-refused"
+	FailureConnect         Failure = "connect-error"
+	FailureResponseTimeout Failure = "response-timeout"
+	FailureCall            Failure = "call-error"
+)
+
+// Port is a port the block node publishes. An Optional port is reported but
+// does not degrade the verdict when it is closed, e.g. the health port that
+// the bn-health policy keeps off the network.
+type Port struct {
+	Name     string `json:"name"`
+	Port     int    `json:"port"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// TLSConfig enables TLS on the gRPC call and the certificate check. CAFile
+// verifies the certificate against a private CA instead of the system roots;
+// ServerName overrides the name checked, which defaults to the address.
+type TLSConfig struct {
+	ServerName string `json:"serverName,omitempty"`
+	CAFile     string `json:"caFile,omitempty"`
+}
+
+// Target is what a probe checks.
+type Target struct {
+	// Address is the host the block node is reached on, normally the
+	// LoadBalancer's external IP.
+	Address string `json:"address"`
+	// ServicePort is the port of the block node's gRPC service.
+	ServicePort int `json:"servicePort"`
+	// Ports are the published ports each dialled once, ServicePort included.
+	Ports []Port     `json:"ports,omitempty"`
+	TLS   *TLSConfig `json:"tls,omitempty"`
+}
+
+// Validate checks the target can be probed.
+func (t Target) Validate() error {
+	if t.Address == "" {
+		return errorx.IllegalArgument.New("probe target has no address")
+	}
+	if t.ServicePort <= 0 || t.ServicePort > 65535 {
+		return errorx.IllegalArgument.New("invalid probe service port %d", t.ServicePort)
+	}
+	for _, p := range t.Ports {
+		if p.Port <= 0 || p.Port > 65535 {
+			return errorx.IllegalArgument.New("invalid probe port %s=%d", p.Name, p.Port)
+		}
+	}
+	return nil
+}
+
+// ServiceAddr returns the host:port of the gRPC service.
+func (t Target) ServiceAddr() string {
+	return net.JoinHostPort(t.Address, strconv.Itoa(t.ServicePort))
+}
+
+// Options tune a probe. Zero values take the defaults.
+type Options struct {
+	// Samples is the number of serverStatus calls, each on a new connection.
+	Samples int
+	// Timeout bounds each sample and each port dial.
+	Timeout time.Duration
+	// Interval separates samples.
+	Interval time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Samples <= 0 {
+		o.Samples = DefaultSamples
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Interval < 0 {
+		o.Interval = 0
+	} else if o.Interval == 0 {
+		o.Interval = DefaultInterval
+	}
+	return o
+}
+
+// Sample is one serverStatus call. Connect is the TCP handshake and
+// FirstByte the time from the established connection to the response
+// headers, TLS handshake included.
+type Sample struct {
+	Connect   time.Duration `json:"connect,omitempty"`
+	FirstByte time.Duration `json:"firstByte,omitempty"`
+	Failure   Failure       `json:"failure,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Latency summarises the successful measurements of the samples.
+type Latency struct {
+	Min    time.Duration `json:"min"`
+	Median time.Duration `json:"median"`
+	Max    time.Duration `json:"max"`
+}
+
+// PortResult is the dial of a published port.
+type PortResult struct {
+	Name      string        `json:"name"`
+	Port      int           `json:"port"`
+	Optional  bool          `json:"optional,omitempty"`
+	Reachable bool          `json:"reachable"`
+	Connect   time.Duration `json:"connect,omitempty"`
+	Failure   Failure       `json:"failure,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// ServerStatus is the block range the block node reported.
+type ServerStatus struct {
+	FirstAvailableBlock uint64 `json:"firstAvailableBlock"`
+	LastAvailableBlock  uint64 `json:"lastAvailableBlock"`
+}
+
+// TLSResult is the certificate the block node presented.
+type TLSResult struct {
+	Subject  string    `json:"subject,omitempty"`
+	Issuer   string    `json:"issuer,omitempty"`
+	NotAfter time.Time `json:"notAfter,omitempty"`
+	Verified bool      `json:"verified"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Result is a completed probe, as persisted in the history.
+type Result struct {
+	Time     time.Time `json:"time"`
+	Instance string    `json:"instance,omitempty"`
+	Target   string    `json:"target"`
+	Verdict  Verdict   `json:"verdict"`
+	// Reasons explain every way the probe fell short of healthy.
+	Reasons      []string      `json:"reasons,omitempty"`
+	Samples      []Sample      `json:"samples"`
+	Connect      *Latency      `json:"connect,omitempty"`
+	FirstByte    *Latency      `json:"firstByte,omitempty"`
+	ServerStatus *ServerStatus `json:"serverStatus,omitempty"`
+	Ports        []PortResult  `json:"ports,omitempty"`
+	TLS          *TLSResult    `json:"tls,omitempty"`
+}
+
+// certExpiryWarning is how close to expiry a certificate degrades the
+// verdict.
+const certExpiryWarning = 14 * 24 * time.Hour
+
+// Run probes target. Failures of the block node are part of the result; an
+// error is only returned for an invalid target or TLS configuration.
+func Run(ctx context.Context, target Target, opts Options) (Result, error) {
+	if err := target.Validate(); err != nil {
+		return Result{}, err
+	}
+	opts = opts.withDefaults()
+
+	var roots *x509.CertPool
+	if target.TLS != nil {
+		var err error
+		if roots, err = loadRoots(target.TLS.CAFile); err != nil {
+			return Result{}, err
+		}
+	}
+
+	caller := newCaller(target, opts.Timeout)
+
+	res := Result{Time: time.Now().UTC(), Target: target.ServiceAddr()}
+	for i := 0; i < opts.Samples; i++ {
+		if i > 0 && opts.Interval > 0 {
+			select {
+			case <-ctx.Done():
+				return Result{}, errorx.Decorate(ctx.Err(), "block node probe interrupted")
+			case <-time.After(opts.Interval):
+			}
+		}
+		smp, status := caller.serverStatus(ctx)
+		if status != nil {
+			res.ServerStatus = status
+		}
+		res.Samples = append(res.Samples, smp)
+	}
+
+	for _, p := range target.Ports {
+		res.Ports = append(res.Ports, dialPort(ctx, target.Address, p, opts.Timeout))
+	}
+	if target.TLS != nil {
+		tlsResult := checkTLS(ctx, target, roots, opts.Timeout)
+		res.TLS = &tlsResult
+	}
+
+	res.Connect, res.FirstByte = summarize(res.Samples)
+	res.Verdict, res.Reasons = classify(res, time.Now())
+	return res, nil
+}
+
+// dialPort opens a TCP connection to the port and closes it.
+func dialPort(ctx context.Context, address string, p Port, timeout time.Duration) PortResult {
+	out := PortResult{Name: p.Name, Port: p.Port, Optional: p.Optional}
+	d := net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(address, strconv.Itoa(p.Port)))
+	if err != nil {
+		out.Failure, out.Error = dialFailure(err), err.Error()
+		return out
+	}
+	out.Connect = time.Since(start)
+	_ = conn.Close()
+	out.Reachable = true
+	return out
+}
+
+// dialFailure classifies a failed TCP dial.
+func dialFailure(err error) Failure {
+	var ne net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return FailureConnectRefused
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &ne) && ne.Timeout():
+		return FailureConnectTimeout
+	default:
+		return FailureConnect
+	}
+}
+
+// summarize returns the latency of the successful samples, nil when none
+// succeeded. Connect counts every sample that connected.
+func summarize(samples []Sample) (connect, firstByte *Latency) {
+	var connects, firstBytes []time.Duration
+	for _, s := range samples {
+		if s.Connect > 0 {
+			connects = append(connects, s.Connect)
+		}
+		if s.Failure == "" {
+			firstBytes = append(firstBytes, s.FirstByte)
+		}
+	}
+	return latency(connects), latency(firstBytes)
+}
+
+func latency(d []time.Duration) *Latency {
+	if len(d) == 0 {
+		return nil
+	}
+	slices.Sort(d)
+	return &Latency{Min: d[0], Median: d[len(d)/2], Max: d[len(d)-1]}
+}
+
+// classify derives the verdict of a result from its samples, ports and
+// certificate. The samples decide between the failed verdicts: where the
+// first failure happened tells the network from the block node.
+func classify(res Result, now time.Time) (Verdict, []string) {
+	var ok, connected, timeouts, refusals, callTimeouts int
+	var lastErr string
+	for _, s := range res.Samples {
+		switch s.Failure {
+		case "":
+			ok++
+			connected++
+			continue
+		case FailureConnectTimeout:
+			timeouts++
+		case FailureConnectRefused:
+			refusals++
+		case FailureResponseTimeout:
+			connected++
+			callTimeouts++
+		case FailureCall:
+			connected++
+		}
+		lastErr = s.Error
+	}
+
+	total := len(res.Samples)
+	switch {
+	case connected == 0 && timeouts > 0:
+		return VerdictBlackhole, []string{"no connection to " + res.Target + " was answered: " + lastErr}
+	case connected == 0 && refusals > 0:
+		return VerdictRefused, []string{"every connection to " + res.Target + " was refused: " + lastErr}
+	case connected == 0:
+		return VerdictBlackhole, []string{"could not connect to " + res.Target + ": " + lastErr}
+	case ok == 0 && callTimeouts > 0:
+		return VerdictStalled, []string{"connections were accepted but serverStatus was not answered: " + lastErr}
+	case ok == 0:
+		return VerdictUnhealthy, []string{"serverStatus failed: " + lastErr}
+	}
+
+	var reasons []string
+	if ok < total {
+		reasons = append(reasons, strconv.Itoa(total-ok)+" of "+strconv.Itoa(total)+" samples failed: "+lastErr)
+	}
+	for _, p := range res.Ports {
+		if !p.Reachable && !p.Optional {
+			reasons = append(reasons, "port "+p.Name+" ("+strconv.Itoa(p.Port)+") is not reachable: "+p.Error)
+		}
+	}
+	if res.TLS != nil {
+		switch {
+		case res.TLS.Error != "":
+			reasons = append(reasons, "certificate: "+res.TLS.Error)
+		case res.TLS.NotAfter.Sub(now) < certExpiryWarning:
+			reasons = append(reasons, "certificate expires "+res.TLS.NotAfter.Format(time.RFC3339))
+		}
+	}
+	if len(reasons) > 0 {
+		return VerdictDegraded, reasons
+	}
+	return VerdictHealthy, nil
+}
@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serverStatusResponse encodes a ServerStatusResponse with an unknown
+// length-delimited field between the block range, as a newer block node
+// would send.
+func serverStatusResponse(first, last uint64) []byte {
+	var msg []byte
+	msg = binary.AppendUvarint(msg, 1<<3|0)
+	msg = binary.AppendUvarint(msg, first)
+	msg = binary.AppendUvarint(msg, 4<<3|2)
+	msg = binary.AppendUvarint(msg, 3)
+	msg = append(msg, "0.1"...)
+	msg = binary.AppendUvarint(msg, 2<<3|0)
+	msg = binary.AppendUvarint(msg, last)
+	return msg
+}
+
+// blockNodeStandIn answers serverStatus like the block node's gRPC server.
+func blockNodeStandIn(t *testing.T, first, last uint64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, 2, r.ProtoMajor, "gRPC runs over HTTP/2")
+		assert.Equal(t, ServerStatusMethod, r.URL.Path)
+		assert.Equal(t, "application/grpc", r.Header.Get("Content-Type"))
+		req, _ := io.ReadAll(r.Body)
+		assert.Equal(t, make([]byte, 5), req, "an empty, uncompressed request message")
+
+		msg := serverStatusResponse(first, last)
+		frame := append([]byte{0}, binary.BigEndian.AppendUint32(nil, uint32(len(msg)))...)
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(append(frame, msg...))
+		w.Header().Set("Grpc-Status", "0")
+	})
+}
+
+// startStandIn serves handler over cleartext HTTP/2 and returns the target
+// of the server.
+func startStandIn(t *testing.T, handler http.Handler) Target {
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return targetOf(t, srv.Listener.Addr())
+}
+
+func targetOf(t *testing.T, addr net.Addr) Target {
+	host, port, err := net.SplitHostPort(addr.String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return Target{Address: host, ServicePort: p, Ports: []Port{{Name: "service", Port: p}}}
+}
+
+// closedPort returns a port nothing listens on.
+func closedPort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+	return port
+}
+
+var fast = Options{Samples: 3, Timeout: time.Second, Interval: -1}
+
+func TestRun_Healthy(t *testing.T) {
+	target := startStandIn(t, blockNodeStandIn(t, 7, 1234))
+	target.Ports = append(target.Ports, Port{Name: "health", Port: closedPort(t), Optional: true})
+
+	res, err := Run(context.Background(), target, fast)
+	require.NoError(t, err)
+	assert.Equal(t, VerdictHealthy, res.Verdict, res.Reasons)
+	assert.Equal(t, target.ServiceAddr(), res.Target)
+	require.Len(t, res.Samples, 3)
+	for _, s := range res.Samples {
+		assert.Empty(t, s.Failure, s.Error)
+		assert.Positive(t, s.Connect)
+		assert.Positive(t, s.FirstByte)
+	}
+	require.NotNil(t, res.ServerStatus)
+	assert.Equal(t, ServerStatus{FirstAvailableBlock: 7, LastAvailableBlock: 1234}, *res.ServerStatus)
+	require.NotNil(t, res.Connect)
+	require.NotNil(t, res.FirstByte)
+	assert.LessOrEqual(t, res.FirstByte.Min, res.FirstByte.Median)
+	assert.LessOrEqual(t, res.FirstByte.Median, res.FirstByte.Max)
+
+	require.Len(t, res.Ports, 2)
+	assert.True(t, res.Ports[0].Reachable)
+	assert.False(t, res.Ports[1].Reachable)
+	assert.Equal(t, FailureConnectRefused, res.Ports[1].Failure)
+}
+
+func TestRun_Stalled(t *testing.T) {
+	release := make(chan struct{})
+	target := startStandIn(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer close(release)
+
+	res, err := Run(context.Background(), target, Options{Samples: 2, Timeout: 200 * time.Millisecond, Interval: -1})
+	require.NoError(t, err)
+	assert.Equal(t, VerdictStalled, res.Verdict)
+	assert.True(t, res.Verdict.Failed())
+	for _, s := range res.Samples {
+		assert.Equal(t, FailureResponseTimeout, s.Failure)
+		assert.Positive(t, s.Connect, "the connection was accepted")
+	}
+	assert.NotNil(t, res.Connect)
+	assert.Nil(t, res.FirstByte)
+}
+
+func TestRun_GRPCError(t *testing.T) {
+	target := startStandIn(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "14")
+		w.Header().Set("Grpc-Message", "block node is starting")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	res, err := Run(context.Background(), target, fast)
+	require.NoError(t, err)
+	assert.Equal(t, VerdictUnhealthy, res.Verdict)
+	require.NotEmpty(t, res.Reasons)
+	assert.Contains(t, res.Reasons[0], "block node is starting")
+	assert.Equal(t, FailureCall, res.Samples[0].Failure)
+}
+
+func TestRun_Refused(t *testing.T) {
+	port := closedPort(t)
+	res, err := Run(context.Background(), Target{Address: "127.0.0.1", ServicePort: port}, fast)
+	require.NoError(t, err)
+	assert.Equal(t, VerdictRefused, res.Verdict)
+	assert.Equal(t, FailureConnectRefused, res.Samples[0].Failure)
+	assert.Zero(t, res.Samples[0].Connect)
+}
+
+func TestRun_TLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(blockNodeStandIn(t, 1, 2))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+	target := targetOf(t, srv.Listener.Addr())
+
+	// Against the system roots the test certificate does not verify, but the
+	// call still goes through.
+	target.TLS = &TLSConfig{ServerName: "example.com"}
+	res, err := Run(context.Background(), target, fast)
+	require.NoError(t, err)
+	assert.Equal(t, VerdictDegraded, res.Verdict)
+	require.NotNil(t, res.TLS)
+	assert.False(t, res.TLS.Verified)
+	assert.NotEmpty(t, res.TLS.Subject)
+	assert.NotNil(t, res.ServerStatus)
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+	target.TLS = &TLSConfig{ServerName: "example.com", CAFile: ca}
+	res, err = Run(context.Background(), target, fast)
+	require.NoError(t, err)
+	assert.True(t, res.TLS.Verified, res.TLS.Error)
+	// httptest's certificate expires in 2084, so only verification counts.
+	assert.Equal(t, VerdictHealthy, res.Verdict, res.Reasons)
+
+	target.TLS = &TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}
+	_, err = Run(context.Background(), target, fast)
+	assert.Error(t, err)
+}
+
+func TestRun_InvalidTarget(t *testing.T) {
+	_, err := Run(context.Background(), Target{ServicePort: 40840}, fast)
+	assert.Error(t, err)
+	_, err = Run(context.Background(), Target{Address: "10.0.0.1"}, fast)
+	assert.Error(t, err)
+	_, err = Run(context.Background(), Target{Address: "10.0.0.1", ServicePort: 40840, Ports: []Port{{Name: "x", Port: 70000}}}, fast)
+	assert.Error(t, err)
+}
+
+func TestClassify(t *testing.T) {
+	now := time.Now()
+	ok := Sample{Connect: time.Millisecond, FirstByte: 2 * time.Millisecond}
+	timeout := Sample{Failure: FailureConnectTimeout, Error: "i/o timeout"}
+
+	tests := []struct {
+		name string
+		res  Result
+		want Verdict
+	}{
+		{"every connect timed out", Result{Samples: []Sample{timeout, timeout}}, VerdictBlackhole},
+		{"timeouts outweigh refusals", Result{Samples: []Sample{timeout, {Failure: FailureConnectRefused}}}, VerdictBlackhole},
+		{"one sample lost", Result{Samples: []Sample{ok, timeout}}, VerdictDegraded},
+		{"required port closed", Result{Samples: []Sample{ok}, Ports: []PortResult{{Name: "publisher", Port: 40841}}}, VerdictDegraded},
+		{"optional port closed", Result{Samples: []Sample{ok}, Ports: []PortResult{{Name: "health", Port: 40983, Optional: true}}}, VerdictHealthy},
+		{"certificate expiring", Result{Samples: []Sample{ok}, TLS: &TLSResult{Verified: true, NotAfter: now.Add(24 * time.Hour)}}, VerdictDegraded},
+		{"certificate valid", Result{Samples: []Sample{ok}, TLS: &TLSResult{Verified: true, NotAfter: now.Add(90 * 24 * time.Hour)}}, VerdictHealthy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reasons := classify(tt.res, now)
+			assert.Equal(t, tt.want, got)
+			if got != VerdictHealthy {
+				assert.NotEmpty(t, reasons)
+			}
+		})
+	}
+}
+
+func TestDecodeServerStatus_Malformed(t *testing.T) {
+	_, err := decodeServerStatus([]byte{1<<3 | 0})
+	assert.Error(t, err)
+	_, err = decodeServerStatus([]byte{4<<3 | 2, 10, 'x'})
+	assert.Error(t, err)
+	_, err = unframe([]byte{0, 0, 0, 0, 9, 1})
+	assert.Error(t, err)
+	_, err = unframe([]byte{1, 0, 0, 0, 0})
+	assert.Error(t, err)
+}
+
+func TestStore_AppendKeepsRetention(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := Store{Path: filepath.Join(t.TempDir(), "probe-history.jsonl"), Retain: time.Hour}
+
+	results, err := store.Read()
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	require.NoError(t, store.Append(Result{Time: now.Add(-2 * time.Hour), Verdict: VerdictBlackhole}, now.Add(-2*time.Hour)))
+	require.NoError(t, store.Append(Result{Time: now, Verdict: VerdictHealthy, Target: "10.0.0.1:40840"}, now))
+
+	results, err = store.Read()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, VerdictHealthy, results[0].Verdict)
+
+	info, err := os.Stat(store.Path)
+	require.NoError(t, err)
+	assert.Equal(t, fileMode, info.Mode().Perm())
+}
+
+func TestTarget_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "probe-target.json")
+	got, err := ReadTarget(path)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	want := Target{Address: "10.0.0.1", ServicePort: 40840, Ports: []Port{{Name: "health", Port: 40983, Optional: true}},
+		TLS: &TLSConfig{ServerName: "bn.example.com"}}
+	require.NoError(t, WriteTarget(path, want))
+	got, err = ReadTarget(path)
+	require.NoError(t, err)
+	assert.Equal(t, &want, got)
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteResult renders a result for the terminal.
+func WriteResult(w io.Writer, r Result) {
+	_, _ = fmt.Fprintf(w, "Target:   %s\n", r.Target)
+	_, _ = fmt.Fprintf(w, "Verdict:  %s\n", r.Verdict)
+	for _, reason := range r.Reasons {
+		_, _ = fmt.Fprintf(w, "          - %s\n", reason)
+	}
+
+	ok := 0
+	for _, s := range r.Samples {
+		if s.Failure == "" {
+			ok++
+		}
+	}
+	_, _ = fmt.Fprintf(w, "Samples:  %d/%d answered\n", ok, len(r.Samples))
+	if r.Connect != nil {
+		_, _ = fmt.Fprintf(w, "Connect:  %s\n", formatLatency(*r.Connect))
+	}
+	if r.FirstByte != nil {
+		_, _ = fmt.Fprintf(w, "1st byte: %s\n", formatLatency(*r.FirstByte))
+	}
+	if r.ServerStatus != nil {
+		_, _ = fmt.Fprintf(w, "Blocks:   %d - %d\n", r.ServerStatus.FirstAvailableBlock, r.ServerStatus.LastAvailableBlock)
+	}
+	if r.TLS != nil {
+		switch {
+		case r.TLS.Subject == "":
+			_, _ = fmt.Fprintf(w, "TLS:      %s\n", r.TLS.Error)
+		case r.TLS.Verified:
+			_, _ = fmt.Fprintf(w, "TLS:      %s, valid until %s\n", r.TLS.Subject, r.TLS.NotAfter.Format(time.RFC3339))
+		default:
+			_, _ = fmt.Fprintf(w, "TLS:      %s, valid until %s, NOT VERIFIED: %s\n",
+				r.TLS.Subject, r.TLS.NotAfter.Format(time.RFC3339), r.TLS.Error)
+		}
+	}
+
+	if len(r.Ports) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%-16s %6s %-12s %10s  %s\n", "PORT", "NUMBER", "STATUS", "CONNECT", "ERROR")
+		for _, p := range r.Ports {
+			status, connect := "open", formatDuration(p.Connect)
+			if !p.Reachable {
+				status, connect = string(p.Failure), "-"
+				if p.Optional {
+					status += "*"
+				}
+			}
+			_, _ = fmt.Fprintf(w, "%-16s %6d %-12s %10s  %s\n", p.Name, p.Port, status, connect, p.Error)
+		}
+	}
+}
+
+// WriteHistory renders results one line each, oldest first.
+func WriteHistory(w io.Writer, results []Result) {
+	_, _ = fmt.Fprintf(w, "%-20s %-24s %-10s %7s %12s %12s  %s\n",
+		"TIME", "TARGET", "VERDICT", "SAMPLES", "CONNECT-P50", "1ST-BYTE-P50", "REASON")
+	for _, r := range results {
+		ok := 0
+		for _, s := range r.Samples {
+			if s.Failure == "" {
+				ok++
+			}
+		}
+		connect, firstByte := "-", "-"
+		if r.Connect != nil {
+			connect = formatDuration(r.Connect.Median)
+		}
+		if r.FirstByte != nil {
+			firstByte = formatDuration(r.FirstByte.Median)
+		}
+		reason := ""
+		if len(r.Reasons) > 0 {
+			reason = r.Reasons[0]
+			if len(r.Reasons) > 1 {
+				reason += " (+" + strconv.Itoa(len(r.Reasons)-1) + " more)"
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%-20s %-24s %-10s %7s %12s %12s  %s\n",
+			r.Time.Local().Format("2006-01-02 15:04:05"), r.Target, r.Verdict,
+			fmt.Sprintf("%d/%d", ok, len(r.Samples)), connect, firstByte, reason)
+	}
+}
+
+func formatLatency(l Latency) string {
+	return fmt.Sprintf("min %s / median %s / max %s",
+		formatDuration(l.Min), formatDuration(l.Median), formatDuration(l.Max))
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(10 * time.Microsecond).String()
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+// DefaultRetention is how long results are kept: a week covers the last
+// incident and what preceded it.
+const DefaultRetention = 7 * 24 * time.Hour
+
+// fileMode keeps the files group-writable: the CLI writes them as root and
+// the daemon appends as weaver through the setgid weaver group of their
+// directory.
+const fileMode os.FileMode = 0o664
+
+// WriteTarget records the target the daemon's probe monitor checks. The CLI
+// rewrites it whenever it resolves the block node's LoadBalancer endpoint.
+func WriteTarget(path string, target Target) error {
+	data, err := json.MarshalIndent(target, "", "  ")
+	if err != nil {
+		return errorx.IllegalState.Wrap(err, "failed to encode probe target")
+	}
+	return writeFile(path, append(data, '\n'))
+}
+
+// ReadTarget reads the target written by WriteTarget. A missing file is no
+// target, not an error.
+func ReadTarget(path string) (*Target, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read probe target %s", path)
+	}
+	var target Target
+	if err := json.Unmarshal(data, &target); err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "invalid probe target file %s", path)
+	}
+	return &target, nil
+}
+
+// Store is the JSON-lines file results are persisted in.
+type Store struct {
+	Path string
+	// Retain drops results older than this on every Append; zero keeps
+	// DefaultRetention.
+	Retain time.Duration
+}
+
+// Read returns every stored result, oldest first. Lines that do not parse,
+// e.g. one torn by a crash mid-write, are skipped. A missing file has no
+// results.
+func (s Store) Read() ([]Result, error) {
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read probe history %s", s.Path)
+	}
+	var out []Result
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var r Result
+		if json.Unmarshal(sc.Bytes(), &r) == nil && r.Verdict != "" {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// Append adds a result and drops those older than the retention window, as
+// of now. The file is rewritten in place rather than replaced so it keeps its
+// ownership across writers.
+func (s Store) Append(r Result, now time.Time) error {
+	existing, err := s.Read()
+	if err != nil {
+		return err
+	}
+	retain := s.Retain
+	if retain <= 0 {
+		retain = DefaultRetention
+	}
+	cutoff := now.Add(-retain)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, old := range append(existing, r) {
+		if old.Time.Before(cutoff) {
+			continue
+		}
+		if err := enc.Encode(old); err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to encode probe result")
+		}
+	}
+	return writeFile(s.Path, buf.Bytes())
+}
+
+// writeFile writes data to path, creating it with fileMode regardless of the
+// umask. An existing file keeps its mode: only its owner could change it.
+func writeFile(path string, data []byte) error {
+	_, statErr := os.Stat(path)
+	if err := os.WriteFile(path, data, fileMode); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to write %s", path)
+	}
+	if !os.IsNotExist(statErr) {
+		return nil
+	}
+	if err := os.Chmod(path, fileMode); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to set permissions on %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+// loadRoots returns the CA pool in caFile, or nil (the system roots) when
+// caFile is empty.
+func loadRoots(caFile string) (*x509.CertPool, error) {
+	if caFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read CA file %s", caFile)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errorx.IllegalFormat.New("CA file %s contains no PEM certificate", caFile)
+	}
+	return pool, nil
+}
+
+// checkTLS completes a TLS handshake with the gRPC service and verifies the
+// certificate it presents against roots, for ServerName or else the address.
+// The certificate is reported even when it does not verify.
+func checkTLS(ctx context.Context, target Target, roots *x509.CertPool, timeout time.Duration) TLSResult {
+	serverName := target.TLS.ServerName
+	if serverName == "" {
+		serverName = target.Address
+	}
+	dialer := tls.Dialer{Config: &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true, // #nosec G402 -- verified below, to report the certificate either way
+		NextProtos:         []string{"h2"},
+	}}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", target.ServiceAddr())
+	if err != nil {
+		return TLSResult{Error: "TLS handshake failed: " + err.Error()}
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return TLSResult{Error: "no certificate presented"}
+	}
+	leaf := certs[0]
+	out := TLSResult{
+		Subject:  leaf.Subject.String(),
+		Issuer:   leaf.Issuer.String(),
+		NotAfter: leaf.NotAfter.UTC(),
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+	}); err != nil {
+		out.Error = err.Error()
+		return out
+	}
+	out.Verified = true
+	return out
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashgraph/solo-weaver/internal/blocknode/probe"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ProbeTarget returns what `block node probe` and the daemon's probe monitor
+// check: the LoadBalancer Service's external IP, the gRPC port it advertises
+// (see loadBalancerPort), every port it publishes and the block node's health
+// port. The health port is optional: bn-health keeps it off the network, so a
+// closed health port is reported but is not a fault.
+func (m *Manager) ProbeTarget(ctx context.Context) (probe.Target, error) {
+	lb, ip, err := m.findLoadBalancerService(ctx)
+	if err != nil {
+		return probe.Target{}, err
+	}
+	healthPort, err := ResolveHealthPort(m.blockNodeInputs.ValuesFile)
+	if err != nil {
+		m.logger.Warn().Err(err).Msgf("Probing the default health port %s", DefaultBlockNodeHealthPort)
+		healthPort = DefaultBlockNodeHealthPort
+	}
+	return probeTarget(lb, ip, healthPort), nil
+}
+
+// RecordProbeTarget resolves the probe target and records it for the
+// daemon's probe monitor, so the daemon follows the LoadBalancer IP and ports
+// the block node was last deployed with. Like VerifyExternalReachable it
+// no-ops when LoadBalancerEnabled is false.
+func (m *Manager) RecordProbeTarget(ctx context.Context) error {
+	if !m.blockNodeInputs.LoadBalancerEnabled {
+		return nil
+	}
+	target, err := m.ProbeTarget(ctx)
+	if err != nil {
+		return err
+	}
+	targetPath, _ := models.Paths().BlockNodeProbePaths(models.BlockNodeInstance(m.blockNodeInputs.Release))
+	return probe.WriteTarget(targetPath, target)
+}
+
+// probeTarget builds the probe target of the LoadBalancer Service lb reached
+// on ip. Ports without a name are named after their number.
+func probeTarget(lb *unstructured.Unstructured, ip, healthPort string) probe.Target {
+	target := probe.Target{Address: ip, ServicePort: int(loadBalancerPort(lb))}
+
+	published := map[int]bool{}
+	ports, _, _ := unstructured.NestedSlice(lb.Object, "spec", "ports")
+	for _, p := range ports {
+		entry, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		port, ok, _ := unstructured.NestedInt64(entry, "port")
+		if !ok || port <= 0 || published[int(port)] {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(entry, "name")
+		if name == "" {
+			name = "port-" + strconv.FormatInt(port, 10)
+		}
+		published[int(port)] = true
+		target.Ports = append(target.Ports, probe.Port{Name: name, Port: int(port)})
+	}
+	if !published[target.ServicePort] {
+		target.Ports = append([]probe.Port{{Name: "service", Port: target.ServicePort}}, target.Ports...)
+		published[target.ServicePort] = true
+	}
+	if health, err := strconv.Atoi(healthPort); err == nil && health > 0 && !published[health] {
+		target.Ports = append(target.Ports, probe.Port{Name: "health", Port: health, Optional: true})
+	}
+	return target
+}
@@ -76,9 +76,19 @@ func (m *Manager) VerifyExternalReachable(ctx context.Context) error {
 // LoadBalancer alongside a ClusterIP main Service. Either is fine: the probe
 // targets whichever Service is actually announcing the external IP.
 func (m *Manager) findLoadBalancerEndpoint(ctx context.Context) (string, int64, error) {
+	lb, ip, err := m.findLoadBalancerService(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	return ip, loadBalancerPort(lb), nil
+}
+
+// findLoadBalancerService returns the block-node LoadBalancer Service (see
+// findLoadBalancerEndpoint) and its assigned external IP.
+func (m *Manager) findLoadBalancerService(ctx context.Context) (*unstructured.Unstructured, string, error) {
 	services, err := m.kubeClient.List(ctx, kube.KindService, m.blockNodeInputs.Namespace, kube.WaitOptions{})
 	if err != nil {
-		return "", 0, errorx.IllegalState.Wrap(err, "failed to list services in namespace %s", m.blockNodeInputs.Namespace)
+		return nil, "", errorx.IllegalState.Wrap(err, "failed to list services in namespace %s", m.blockNodeInputs.Namespace)
 	}
 
 	var lb *unstructured.Unstructured
@@ -91,7 +101,7 @@ func (m *Manager) findLoadBalancerEndpoint(ctx context.Context) (string, int64,
 		}
 	}
 	if lb == nil {
-		return "", 0, errorx.IllegalState.New(
+		return nil, "", errorx.IllegalState.New(
 			"no LoadBalancer Service found in namespace %s; cannot probe reachability",
 			m.blockNodeInputs.Namespace).
 			WithProperty(models.ErrPropertyResolution, []string{
@@ -105,20 +115,20 @@ func (m *Manager) findLoadBalancerEndpoint(ctx context.Context) (string, int64,
 
 	ingress, found, _ := unstructured.NestedSlice(lb.Object, "status", "loadBalancer", "ingress")
 	if !found || len(ingress) == 0 {
-		return "", 0, errorx.IllegalState.New(
+		return nil, "", errorx.IllegalState.New(
 			"LoadBalancer Service %s has no ingress IP assigned yet; MetalLB may have failed to allocate",
 			lb.GetName())
 	}
 	ingressEntry, ok := ingress[0].(map[string]interface{})
 	if !ok {
-		return "", 0, errorx.IllegalState.New("loadBalancer.ingress[0] has unexpected shape on Service %s", lb.GetName())
+		return nil, "", errorx.IllegalState.New("loadBalancer.ingress[0] has unexpected shape on Service %s", lb.GetName())
 	}
 	ip, _, _ := unstructured.NestedString(ingressEntry, "ip")
 	if ip == "" {
-		return "", 0, errorx.IllegalState.New("loadBalancer.ingress[0].ip is empty on Service %s", lb.GetName())
+		return nil, "", errorx.IllegalState.New("loadBalancer.ingress[0].ip is empty on Service %s", lb.GetName())
 	}
 
-	return ip, loadBalancerPort(lb), nil
+	return lb, ip, nil
 }
 
 // loadBalancerPort returns the port the LoadBalancer Service advertises (its
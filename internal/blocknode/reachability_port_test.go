@@ -5,6 +5,7 @@ package blocknode
 import (
 	"testing"
 
+	"github.com/hashgraph/solo-weaver/internal/blocknode/probe"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -28,3 +29,47 @@ func TestLoadBalancerPort_FallsBackWhenNoPorts(t *testing.T) {
 	require.Equal(t, BlockNodePublicPort, loadBalancerPort(lb),
 		"a Service with no ports falls back to the well-known public port")
 }
+
+func TestProbeTarget_ProbesEveryPublishedPort(t *testing.T) {
+	lb := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ports": []interface{}{
+				map[string]interface{}{"name": "grpc", "port": int64(40840)},
+				map[string]interface{}{"port": int64(40841)},
+				map[string]interface{}{"name": "grpc-dup", "port": int64(40840)},
+			},
+		},
+	}}
+
+	target := probeTarget(lb, "10.0.0.10", DefaultBlockNodeHealthPort)
+	require.Equal(t, probe.Target{
+		Address:     "10.0.0.10",
+		ServicePort: 40840,
+		Ports: []probe.Port{
+			{Name: "grpc", Port: 40840},
+			{Name: "port-40841", Port: 40841},
+			{Name: "health", Port: 40983, Optional: true},
+		},
+	}, target)
+}
+
+func TestProbeTarget_PublishedHealthPortIsRequired(t *testing.T) {
+	lb := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ports": []interface{}{
+				map[string]interface{}{"name": "health", "port": int64(40983)},
+			},
+		},
+	}}
+
+	target := probeTarget(lb, "10.0.0.10", DefaultBlockNodeHealthPort)
+	require.Equal(t, 40983, target.ServicePort)
+	require.Equal(t, []probe.Port{{Name: "health", Port: 40983}}, target.Ports)
+}
+
+func TestProbeTarget_NoPortsFallsBackToPublicPort(t *testing.T) {
+	lb := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+
+	target := probeTarget(lb, "10.0.0.10", "not-a-port")
+	require.Equal(t, []probe.Port{{Name: "service", Port: int(BlockNodePublicPort)}}, target.Ports)
+}
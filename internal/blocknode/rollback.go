@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashgraph/solo-weaver/pkg/helm"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// RollbackPlan is a chart-revision rollback of the block node release and the
+// storage changes it implies, as worked out by PlanRollback.
+type RollbackPlan struct {
+	FromRevision int
+	FromVersion  string
+	ToRevision   int
+	ToVersion    string
+	// Recreate are the optional storages the target revision mounts and the
+	// deployed one does not (a volume retired since): their host directory and
+	// PV/PVC are re-created before the rollback.
+	Recreate []OptionalStorage
+	// Unmounted are the optional storages the deployed revision mounts and the
+	// target one does not. Their PV/PVC and data are left in place.
+	Unmounted []OptionalStorage
+	// Irreversible are the Unmounted storages whose boundary does not support
+	// a rollback (OptionalStorage.NoRollback); only a forced plan has any.
+	Irreversible []OptionalStorage
+}
+
+// VolumesChange reports whether the target revision mounts a different set of
+// volumes, so the StatefulSet's volumeClaimTemplates change with the rollback.
+func (p *RollbackPlan) VolumesChange() bool {
+	return len(p.Recreate) > 0 || len(p.Unmounted) > 0
+}
+
+// String summarises the plan for logs and the rollback report, e.g.
+// "revision 4 (0.37.1) -> revision 3 (0.36.2), re-create verification".
+func (p *RollbackPlan) String() string {
+	s := "revision " + strconv.Itoa(p.FromRevision) + " (" + p.FromVersion + ") -> revision " +
+		strconv.Itoa(p.ToRevision) + " (" + p.ToVersion + ")"
+	if len(p.Recreate) > 0 {
+		s += ", re-create " + storageNames(p.Recreate)
+	}
+	if len(p.Unmounted) > 0 {
+		s += ", unmount " + storageNames(p.Unmounted)
+	}
+	return s
+}
+
+// PlanRollback picks the revision to roll the release with the given history
+// back to and diffs the optional storage of the two chart versions.
+//
+// toRevision 0 selects the newest revision before the deployed one that was
+// itself deployed successfully (status superseded). An explicit toRevision must
+// be in the history and differ from the deployed revision.
+//
+// A target version below the MinVersion of a NoRollback storage the deployed
+// version mounts is refused unless force is set: the block node there cannot
+// read the state the newer version wrote. managePlugins mirrors the #913
+// plugins-baked signal — a plugins-baked image never gets a plugins volume
+// re-created.
+func PlanRollback(history []*release.Release, toRevision int, managePlugins, force bool) (*RollbackPlan, error) {
+	var deployed *release.Release
+	for _, rel := range history {
+		if rel.Info != nil && rel.Info.Status == release.StatusDeployed {
+			deployed = rel
+		}
+	}
+	if deployed == nil {
+		return nil, errorx.IllegalState.New("the block node release has no deployed revision to roll back from")
+	}
+
+	var target *release.Release
+	for _, rel := range history {
+		switch {
+		case toRevision != 0:
+			if rel.Version == toRevision {
+				target = rel
+			}
+		case rel.Version < deployed.Version && rel.Info != nil && rel.Info.Status == release.StatusSuperseded:
+			target = rel
+		}
+	}
+	switch {
+	case target == nil && toRevision != 0:
+		return nil, errorx.IllegalArgument.New("revision %d is not in the block node release history", toRevision).
+			WithProperty(models.ErrPropertyResolution, "pass a --to-revision listed by 'helm history "+deployed.Name+"'")
+	case target == nil:
+		return nil, errorx.IllegalState.New("the block node release has no earlier successfully deployed revision to roll back to")
+	case target.Version == deployed.Version:
+		return nil, errorx.IllegalArgument.New("revision %d is the deployed revision", toRevision).
+			WithProperty(models.ErrPropertyResolution, "pass a --to-revision other than the deployed one")
+	}
+
+	plan := &RollbackPlan{
+		FromRevision: deployed.Version,
+		FromVersion:  chartVersionOf(deployed),
+		ToRevision:   target.Version,
+		ToVersion:    chartVersionOf(target),
+	}
+	for _, optStor := range optionalStorages {
+		from, to := optStor.RequiredByVersion(plan.FromVersion), optStor.RequiredByVersion(plan.ToVersion)
+		switch {
+		case to && !from:
+			if optStor.Name == "plugins" && !managePlugins {
+				continue
+			}
+			plan.Recreate = append(plan.Recreate, optStor)
+		case from && !to:
+			plan.Unmounted = append(plan.Unmounted, optStor)
+			if optStor.NoRollback {
+				plan.Irreversible = append(plan.Irreversible, optStor)
+			}
+		}
+	}
+
+	if len(plan.Irreversible) > 0 && !force {
+		return nil, errorx.IllegalArgument.New(
+			"cannot roll the block node back from chart version %s to %s: versions below %s cannot read the %s storage",
+			plan.FromVersion, plan.ToVersion, plan.Irreversible[0].MinVersion, storageNames(plan.Irreversible)).
+			WithProperty(models.ErrPropertyResolution,
+				"pick a --to-revision at chart version "+strings.TrimSuffix(plan.Irreversible[0].MinVersion, "-0")+
+					" or later, or pass --force and restore a backup taken before the upgrade")
+	}
+	return plan, nil
+}
+
+// PlanRollback reads the history of the block node release and plans a
+// rollback to toRevision (see the package-level PlanRollback).
+func (m *Manager) PlanRollback(toRevision int, force bool) (*RollbackPlan, error) {
+	history, err := m.helmManager.History(m.blockNodeInputs.Release, m.blockNodeInputs.Namespace)
+	if err != nil {
+		if errorx.IsOfType(err, helm.ErrNotFound) {
+			return nil, errorx.IllegalState.New("block node release %q is not installed; nothing to roll back",
+				m.blockNodeInputs.Release)
+		}
+		return nil, errorx.IllegalState.Wrap(err, "failed to read the block node release history")
+	}
+	return PlanRollback(history, toRevision, m.managesPluginsStorage(), force)
+}
+
+// RollbackChart carries out plan: it re-creates the storage the target
+// revision mounts, deletes the StatefulSet (orphan cascade) when its volumes
+// change, and rolls the release back, waiting for it to become ready.
+// Unmounted storage is left in place; `block node storage gc` reports a
+// retired volume once the release moves past it again.
+func (m *Manager) RollbackChart(ctx context.Context, plan *RollbackPlan) error {
+	// Resolve the re-created storage at the target chart version.
+	originalVersion := m.blockNodeInputs.ChartVersion
+	m.blockNodeInputs.ChartVersion = plan.ToVersion
+	defer func() { m.blockNodeInputs.ChartVersion = originalVersion }()
+
+	for _, optStor := range plan.Recreate {
+		if err := m.provisionOptionalStorage(ctx, optStor); err != nil {
+			return err
+		}
+	}
+
+	if plan.VolumesChange() {
+		if err := m.DeleteStatefulSetForUpgrade(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := m.helmManager.RollbackChart(ctx, m.blockNodeInputs.Release, m.blockNodeInputs.Namespace,
+		helm.RollbackChartOptions{
+			Revision:      plan.ToRevision,
+			Wait:          true,
+			Timeout:       m.ResolveHelmTimeout(),
+			CleanupOnFail: true,
+		})
+	if err != nil {
+		m.logger.Error().
+			Err(err).
+			Int("revision", plan.ToRevision).
+			Str("version", plan.ToVersion).
+			Str("namespace", m.blockNodeInputs.Namespace).
+			Msg("Helm rollback failed")
+		return errorx.IllegalState.Wrap(err, "failed to roll back block node chart to revision %d", plan.ToRevision)
+	}
+	return nil
+}
+
+// chartVersionOf returns the chart version a release revision was deployed
+// with, empty when the revision carries no chart metadata.
+func chartVersionOf(rel *release.Release) string {
+	if rel.Chart == nil || rel.Chart.Metadata == nil {
+		return ""
+	}
+	return rel.Chart.Metadata.Version
+}
+
+// storageNames joins the names of storages for messages.
+func storageNames(storages []OptionalStorage) string {
+	names := make([]string, 0, len(storages))
+	for _, optStor := range storages {
+		names = append(names, optStor.Name)
+	}
+	return strings.Join(names, ", ")
+}
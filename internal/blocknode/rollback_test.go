@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func revision(version int, chartVersion string, status release.Status) *release.Release {
+	return &release.Release{
+		Name:    "block-node",
+		Version: version,
+		Info:    &release.Info{Status: status},
+		Chart:   &chart.Chart{Metadata: &chart.Metadata{Version: chartVersion}},
+	}
+}
+
+func TestPlanRollback_DefaultsToPreviousSupersededRevision(t *testing.T) {
+	history := []*release.Release{
+		revision(1, "0.28.1", release.StatusSuperseded),
+		revision(2, "0.29.0", release.StatusSuperseded),
+		revision(3, "0.30.0", release.StatusFailed),
+		revision(4, "0.30.1", release.StatusDeployed),
+	}
+
+	plan, err := PlanRollback(history, 0, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, plan.FromRevision)
+	assert.Equal(t, "0.30.1", plan.FromVersion)
+	assert.Equal(t, 2, plan.ToRevision, "the failed revision 3 is skipped")
+	assert.Equal(t, "0.29.0", plan.ToVersion)
+	assert.False(t, plan.VolumesChange())
+}
+
+func TestPlanRollback_ExplicitRevision(t *testing.T) {
+	history := []*release.Release{
+		revision(1, "0.26.0", release.StatusSuperseded),
+		revision(2, "0.28.1", release.StatusDeployed),
+	}
+
+	plan, err := PlanRollback(history, 1, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, plan.ToRevision)
+	// 0.26.0 predates both verification (0.26.2) and plugins (0.28.1).
+	assert.Equal(t, []string{"verification", "plugins"}, optionalStorageNames(plan.Unmounted))
+	assert.Empty(t, plan.Recreate)
+	assert.Empty(t, plan.Irreversible)
+	assert.True(t, plan.VolumesChange())
+
+	_, err = PlanRollback(history, 7, true, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not in the block node release history")
+
+	_, err = PlanRollback(history, 2, true, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "deployed revision")
+}
+
+func TestPlanRollback_RefusesIrreversibleBoundaryUnlessForced(t *testing.T) {
+	history := []*release.Release{
+		revision(1, "0.36.2", release.StatusSuperseded),
+		revision(2, "0.37.0", release.StatusDeployed),
+	}
+
+	_, err := PlanRollback(history, 0, true, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "application-state")
+
+	plan, err := PlanRollback(history, 0, true, true)
+	require.NoError(t, err)
+	// Crossing back below 0.37.0 brings the retired verification volume back
+	// and unmounts application-state.
+	assert.Equal(t, []string{"verification"}, optionalStorageNames(plan.Recreate))
+	assert.Equal(t, []string{"application-state"}, optionalStorageNames(plan.Unmounted))
+	assert.Equal(t, []string{"application-state"}, optionalStorageNames(plan.Irreversible))
+}
+
+func TestPlanRollback_NoEarlierRevision(t *testing.T) {
+	_, err := PlanRollback([]*release.Release{revision(1, "0.30.0", release.StatusDeployed)}, 0, true, false)
+	require.Error(t, err)
+
+	_, err = PlanRollback([]*release.Release{revision(1, "0.30.0", release.StatusFailed)}, 0, true, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no deployed revision")
+}
+
+func optionalStorageNames(storages []OptionalStorage) []string {
+	var names []string
+	for _, optStor := range storages {
+		names = append(names, optStor.Name)
+	}
+	return names
+}
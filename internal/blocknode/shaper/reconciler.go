@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/hashgraph/solo-weaver/internal/network/policy"
+	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/joomcode/errorx"
 )
 
@@ -41,20 +42,34 @@ type setApplier interface {
 //
 // The three collaborators are seams so the orchestration is testable off-host:
 // fetcher reads statusz, lister reads live nft membership, applier writes it.
+//
+// instance is the block node instance whose policy sets are reconciled (see
+// models.BlockNodeInstance). The bindings are written in the default instance's
+// policy names; the reconciler maps them to the instance's own names (see
+// models.BlockNodePolicyName) wherever it touches nft or reports a set.
 type Reconciler struct {
-	fetcher endpointFetcher
-	lister  elementLister
-	applier setApplier
+	fetcher  endpointFetcher
+	lister   elementLister
+	applier  setApplier
+	instance string
 }
 
-// NewReconciler wires the production Reconciler: statusz is read over HTTP from
-// statuszURL, live nft sets are read via the exec Runner, and membership is
-// written via the network policy Manager.
+// NewReconciler wires the production Reconciler of the default block node
+// instance: statusz is read over HTTP from statuszURL, live nft sets are read
+// via the exec Runner, and membership is written via the network policy
+// Manager.
 func NewReconciler(statuszURL string) *Reconciler {
+	return NewInstanceReconciler(statuszURL, "")
+}
+
+// NewInstanceReconciler is NewReconciler for the policy sets of the given block
+// node instance.
+func NewInstanceReconciler(statuszURL, instance string) *Reconciler {
 	return &Reconciler{
-		fetcher: NewStatuszClient(statuszURL),
-		lister:  policy.NewExecRunner(),
-		applier: policy.NewManager(),
+		fetcher:  NewStatuszClient(statuszURL),
+		lister:   policy.NewExecRunner(),
+		applier:  policy.NewManager(),
+		instance: instance,
 	}
 }
 
@@ -96,7 +111,7 @@ func (r *Reconciler) Check(ctx context.Context) (CheckResult, error) {
 	if err != nil {
 		return CheckResult{}, err
 	}
-	portsDesired := desiredPorts(inbound)
+	canon, portsDesired := r.instanceKeys(canon), r.instanceKeys(desiredPorts(inbound))
 	return CheckResult{
 		Digest:       membershipDigest(combinedCanonical(canon, portsDesired)),
 		Desired:      canon,
@@ -125,13 +140,14 @@ func (r *Reconciler) Apply(ctx context.Context) (Result, error) {
 		return Result{}, err
 	}
 	portsDesired := desiredPorts(inbound)
-	digest := membershipDigest(combinedCanonical(canon, portsDesired))
+	digest := membershipDigest(combinedCanonical(r.instanceKeys(canon), r.instanceKeys(portsDesired)))
 
-	memDeltas, err := computePolicyDeltas(ctx, r.lister, ce)
+	lister := instanceLister{lister: r.lister, instance: r.instance}
+	memDeltas, err := computePolicyDeltas(ctx, lister, ce)
 	if err != nil {
 		return Result{}, err
 	}
-	portDeltas, err := computePortDeltas(ctx, r.lister, portsDesired)
+	portDeltas, err := computePortDeltas(ctx, lister, portsDesired)
 	if err != nil {
 		return Result{}, err
 	}
@@ -152,7 +168,8 @@ func (r *Reconciler) Apply(ctx context.Context) (Result, error) {
 	}
 	sort.Strings(changed)
 
-	res := Result{Digest: digest, Unchanged: unchangedSetNames(changed)}
+	res := Result{Digest: digest, Unchanged: r.instanceNames(unchangedSetNames(changed))}
+	changed = r.instanceNames(changed)
 
 	// ApplySets is called even with no deltas. Besides writing the kernel it also
 	// re-persists the on-disk artifact, and a converged node produces no deltas
@@ -160,7 +177,7 @@ func (r *Reconciler) Apply(ctx context.Context) (Result, error) {
 	// permanently stale while every tick reported success.
 	//
 	// One lock acquisition for both dimensions: the tick is atomic.
-	applied, err := r.applier.ApplySets(ctx, changedMem, changedPorts)
+	applied, err := r.applier.ApplySets(ctx, r.instanceKeys(changedMem), r.instanceKeys(changedPorts))
 	if err != nil {
 		return Result{}, errorx.ExternalError.Wrap(err, "apply reconciled traffic-shaper sets")
 	}
@@ -175,6 +192,42 @@ func (r *Reconciler) Apply(ctx context.Context) (Result, error) {
 	return res, nil
 }
 
+// instanceLister reads the live nft sets of a block node instance: the set
+// names the bindings ask for are mapped to the instance's own.
+type instanceLister struct {
+	lister   elementLister
+	instance string
+}
+
+func (l instanceLister) ListElements(ctx context.Context, set string) ([]string, error) {
+	return l.lister.ListElements(ctx, models.BlockNodePolicyName(l.instance, set))
+}
+
+// instanceNames maps policy or set names to those of the reconciled instance.
+func (r *Reconciler) instanceNames(names []string) []string {
+	if r.instance == "" {
+		return names
+	}
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = models.BlockNodePolicyName(r.instance, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// instanceKeys re-keys a per-policy map to the reconciled instance's names.
+func (r *Reconciler) instanceKeys(m map[string][]string) map[string][]string {
+	if r.instance == "" {
+		return m
+	}
+	out := make(map[string][]string, len(m))
+	for name, v := range m {
+		out[models.BlockNodePolicyName(r.instance, name)] = v
+	}
+	return out
+}
+
 // fetchEndpoints reads both statusz endpoints, buckets them into the desired
 // per-category membership view, and returns the raw inbound NetworkData too:
 // listener-port derivation reads local.port straight off the inbound endpoints
@@ -218,6 +218,32 @@ func TestReconciler_Apply_NormalizesBareHostMembership(t *testing.T) {
 	assert.Equal(t, []string{"bn-publisher"}, res.Applied)
 }
 
+func TestReconciler_Apply_InstanceUsesInstanceSetNames(t *testing.T) {
+	f := &fakeFetcher{
+		inbound: NetworkData{ActiveEndpoints: []NetworkConnection{
+			conn("publisher", "10.1.0.1/32", "*"),
+		}},
+		outbound: NetworkData{},
+	}
+	lister := newFakeLister()
+	// The default instance's set is live with different content; it must not be
+	// read or written by the testnet reconciler.
+	lister.elements["bn-publisher"] = []string{"10.9.9.9"}
+	lister.elements["bn-testnet-publisher"] = []string{"10.9.9.9"}
+
+	applier := &fakeApplier{applied: true}
+	r := &Reconciler{fetcher: f, lister: lister, applier: applier, instance: "testnet"}
+
+	res, err := r.Apply(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, map[string][]string{"bn-testnet-publisher": {"10.1.0.1/32"}}, applier.got)
+	assert.Equal(t, []string{"bn-testnet-publisher"}, res.Applied)
+	for _, name := range res.Unchanged {
+		assert.Contains(t, name, "bn-testnet-", "unchanged set %q must carry the instance prefix", name)
+	}
+}
+
 func TestBucketizeEndpoints_EmptySnapshotSeedsAllOwnedEmpty(t *testing.T) {
 	ce := bucketizeEndpoints(NetworkData{}, NetworkData{})
 	require.Len(t, ce, 4)
@@ -119,6 +119,7 @@ func (m *Manager) CreatePersistentVolumes(ctx context.Context, tempDir string) e
 	data := struct {
 		Namespace               string
 		Release                 string
+		PVPrefix                string
 		LivePath                string
 		ArchivePath             string
 		LogPath                 string
@@ -137,6 +138,7 @@ func (m *Manager) CreatePersistentVolumes(ctx context.Context, tempDir string) e
 	}{
 		Namespace:               m.blockNodeInputs.Namespace,
 		Release:                 m.blockNodeInputs.Release,
+		PVPrefix:                m.pvPrefix(),
 		LivePath:                livePath,
 		ArchivePath:             archivePath,
 		LogPath:                 logPath,
@@ -167,7 +169,7 @@ func (m *Manager) CreatePersistentVolumes(ctx context.Context, tempDir string) e
 	}
 
 	m.logger.Debug().Str("configFile", configFilePath).Msg("Applying storage manifest")
-	if err := m.kubeClient.ApplyManifest(ctx, configFilePath); err != nil {
+	if err := m.kubeClient.ApplyManifest(ctx, configFilePath, m.storageApplyOptions(m.storageManifest(kube.ManifestBlockNodeStorage))...); err != nil {
 		m.logger.Error().Err(err).Str("configFile", configFilePath).Msg("Failed to apply storage manifest")
 		return errorx.IllegalState.Wrap(err, "failed to apply storage configuration")
 	}
@@ -195,6 +197,17 @@ func (m *Manager) CreatePersistentVolumes(ctx context.Context, tempDir string) e
 	return nil
 }
 
+// pvPrefix is prepended to the names of the block node's PersistentVolumes.
+// PVs are cluster-scoped, so an additional block node on the host (see
+// models.BlockNodeInstance) gets names of its own; the default instance keeps
+// the bare names it has always used.
+func (m *Manager) pvPrefix() string {
+	if instance := models.BlockNodeInstance(m.blockNodeInputs.Release); instance != "" {
+		return instance + "-"
+	}
+	return ""
+}
+
 // SoloProvisionerStorageLabelSelector matches PVs/PVCs created by
 // solo-provisioner's own block-node storage templates.
 const SoloProvisionerStorageLabelSelector = "app.kubernetes.io/managed-by=solo-provisioner,app.kubernetes.io/component=block-node-storage"
@@ -276,10 +289,10 @@ func (m *Manager) DeleteAllPersistentVolumes(ctx context.Context) error {
 	// idempotent retry by name. Remove once all field deployments have rolled
 	// past the version that adds the labels (tracked in the operator guide).
 	legacyPVCNames := []string{"live-storage-pvc", "archive-storage-pvc", "logging-storage-pvc"}
-	legacyPVNames := []string{"live-storage-pv", "archive-storage-pv", "logging-storage-pv"}
+	legacyPVNames := []string{m.pvPrefix() + "live-storage-pv", m.pvPrefix() + "archive-storage-pv", m.pvPrefix() + "logging-storage-pv"}
 	for _, optStor := range GetOptionalStorages() {
 		legacyPVCNames = append(legacyPVCNames, optStor.PVCName)
-		legacyPVNames = append(legacyPVNames, optStor.PVName)
+		legacyPVNames = append(legacyPVNames, m.pvPrefix()+optStor.PVName)
 	}
 	for _, name := range legacyPVCNames {
 		if _, dup := seenPVC[name]; dup {
@@ -298,9 +311,44 @@ func (m *Manager) DeleteAllPersistentVolumes(ctx context.Context) error {
 		}
 	}
 
+	manifests := []string{m.storageManifest(kube.ManifestBlockNodeStorage)}
+	for _, optStor := range GetOptionalStorages() {
+		manifests = append(manifests, m.optionalStorageManifest(optStor))
+	}
+	for _, name := range manifests {
+		if err := kube.ForgetManifest(name); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// storageApplyOptions applies a storage manifest under the configured
+// conflict policy and records it under name for `kube cluster drift`.
+func (m *Manager) storageApplyOptions(name string) []kube.ApplyOption {
+	return []kube.ApplyOption{
+		kube.WithForceConflicts(config.Get().Kube.ForceConflicts()),
+		kube.WithRecord(name),
+	}
+}
+
+// storageManifest is the name a storage manifest of this block node is
+// recorded under: base for the default instance, base-<instance> for an
+// additional one (see pvPrefix), so instances keep separate drift records.
+func (m *Manager) storageManifest(base string) string {
+	if instance := models.BlockNodeInstance(m.blockNodeInputs.Release); instance != "" {
+		return base + "-" + instance
+	}
+	return base
+}
+
+// optionalStorageManifest is the name an optional storage manifest of this
+// block node is recorded under.
+func (m *Manager) optionalStorageManifest(optStor OptionalStorage) string {
+	return m.storageManifest("block-node-" + optStor.Name + "-storage")
+}
+
 // CreateOptionalStorage creates a single optional PV/PVC using the unified template.
 // Used during migration when other PVs already exist.
 func (m *Manager) CreateOptionalStorage(ctx context.Context, tempDir string, optStor OptionalStorage) error {
@@ -319,7 +367,7 @@ func (m *Manager) CreateOptionalStorage(ctx context.Context, tempDir string, opt
 	}{
 		Namespace: m.blockNodeInputs.Namespace,
 		Release:   m.blockNodeInputs.Release,
-		PVName:    optStor.PVName,
+		PVName:    m.pvPrefix() + optStor.PVName,
 		PVCName:   optStor.PVCName,
 		Path:      storagePath,
 		Size:      storageSize,
@@ -341,7 +389,7 @@ func (m *Manager) CreateOptionalStorage(ctx context.Context, tempDir string, opt
 		return errorx.IllegalState.Wrap(err, "failed to write %s storage config", optStor.Name)
 	}
 
-	if err := m.kubeClient.ApplyManifest(ctx, configFilePath); err != nil {
+	if err := m.kubeClient.ApplyManifest(ctx, configFilePath, m.storageApplyOptions(m.optionalStorageManifest(optStor))...); err != nil {
 		return errorx.IllegalState.Wrap(err, "failed to apply %s storage", optStor.Name)
 	}
 
@@ -355,6 +403,37 @@ func (m *Manager) CreateOptionalStorage(ctx context.Context, tempDir string, opt
 	return nil
 }
 
+// provisionOptionalStorage creates optStor's host directory, owned by the
+// hedera user, and its PV/PVC. Both are idempotent, so it is safe to run for a
+// volume that (partly) still exists.
+func (m *Manager) provisionOptionalStorage(ctx context.Context, optStor OptionalStorage) error {
+	m.logger.Info().Str("storage", optStor.Name).Msg("Creating storage directory and PV/PVC")
+
+	storagePath, _, err := m.resolveOptionalStoragePathAndSize(optStor)
+	if err != nil {
+		return errorx.IllegalState.Wrap(err, "failed to resolve %s storage path", optStor.Name)
+	}
+
+	if storagePath != "" {
+		m.logger.Info().Str("path", storagePath).Str("storage", optStor.Name).Msg("Creating storage directory")
+		if err := m.fsManager.CreateDirectory(storagePath, true); err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to create %s storage directory", optStor.Name)
+		}
+		if err := m.fsManager.WritePermissions(storagePath, models.DefaultStorageDirPerm, true); err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to set permissions on %s storage", optStor.Name)
+		}
+		if err := m.fsManager.WriteOwnerByName(storagePath, config.HederaUserName(), config.HederaGroupName(), true); err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to set ownership on %s storage", optStor.Name)
+		}
+	}
+
+	m.logger.Info().Str("storage", optStor.Name).Msg("Creating storage PV/PVC")
+	if err := m.CreateOptionalStorage(ctx, models.Paths().TempDir, optStor); err != nil {
+		return errorx.IllegalState.Wrap(err, "failed to create %s storage PV/PVC", optStor.Name)
+	}
+	return nil
+}
+
 // resolveOptionalStoragePathAndSize derives the effective path and size for an optional storage,
 // using basePath derivation when the individual path is not explicitly configured.
 func (m *Manager) resolveOptionalStoragePathAndSize(optStor OptionalStorage) (string, string, error) {
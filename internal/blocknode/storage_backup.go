@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashgraph/solo-weaver/internal/backup"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/semver"
+	"github.com/joomcode/errorx"
+)
+
+// Names of the core storage volumes in a backup manifest. Optional volumes
+// are named after their OptionalStorage entry.
+const (
+	BackupVolumeArchive = "archive"
+	BackupVolumeLive    = "live"
+	BackupVolumeLog     = "log"
+)
+
+// storageVolumes returns the block node's storage volumes for the configured
+// chart version: the core tiers, then the applicable optional storages in
+// registry order. The archive tier is append-only and so may be backed up
+// incrementally.
+func (m *Manager) storageVolumes() ([]backup.Source, error) {
+	return resolveStorageVolumes(m.blockNodeInputs)
+}
+
+// resolveStorageVolumes is the Manager-free counterpart of storageVolumes.
+func resolveStorageVolumes(inputs models.BlockNodeInputs) ([]backup.Source, error) {
+	archivePath, livePath, logPath, optionalPaths, err := ResolveStoragePaths(inputs.Storage, inputs.ChartVersion)
+	if err != nil {
+		return nil, err
+	}
+	vols := []backup.Source{
+		{Name: BackupVolumeArchive, Path: archivePath, AppendOnly: true},
+		{Name: BackupVolumeLive, Path: livePath},
+		{Name: BackupVolumeLog, Path: logPath},
+	}
+	for i, optStor := range GetApplicableOptionalStorages(inputs.ChartVersion) {
+		if optionalPaths[i] != "" {
+			vols = append(vols, backup.Source{Name: optStor.Name, Path: optionalPaths[i]})
+		}
+	}
+	return vols, nil
+}
+
+// BackupStorage archives every storage volume into a new backup directory
+// under target and returns its path. The block node must already be scaled
+// down: the live tier is not crash-consistent while the pod is writing. When
+// incremental is set, the archive tier stores only what was added since the
+// newest backup of this release under target.
+func (m *Manager) BackupStorage(ctx context.Context, target string, incremental bool) (string, error) {
+	vols, err := m.storageVolumes()
+	if err != nil {
+		return "", err
+	}
+	for _, v := range vols {
+		if within(target, v.Path) {
+			return "", errorx.IllegalArgument.New(
+				"backup target %s is inside the %s storage directory %s; choose a target outside block node storage",
+				target, v.Name, v.Path)
+		}
+	}
+
+	dir, man, err := backup.Create(ctx, backup.Options{
+		Target:       target,
+		Namespace:    m.blockNodeInputs.Namespace,
+		Release:      m.blockNodeInputs.Release,
+		ChartVersion: m.blockNodeInputs.ChartVersion,
+		Volumes:      vols,
+		Incremental:  incremental,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var files int
+	var bytes int64
+	for _, v := range man.Volumes {
+		files += v.Files
+		bytes += v.ArchiveBytes
+	}
+	m.logger.Info().
+		Str("backup", dir).
+		Str("base", man.Base).
+		Int("volumes", len(man.Volumes)).
+		Int("files", files).
+		Int64("archive_bytes", bytes).
+		Msg("Block node storage backup complete")
+	return dir, nil
+}
+
+// VerifyBackup checks that the backup in dir can be restored onto the
+// configured chart version (see ValidateBackupCompatibility) and that every
+// archive in its chain matches its recorded checksum. It reads every archive
+// in full and touches nothing, so it runs before the node is scaled down.
+func (m *Manager) VerifyBackup(ctx context.Context, dir string, force bool) error {
+	man, err := backup.ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+	if err := ValidateBackupCompatibility(man, m.blockNodeInputs.ChartVersion, force); err != nil {
+		return err
+	}
+	if man.Release != m.blockNodeInputs.Release || man.Namespace != m.blockNodeInputs.Namespace {
+		m.logger.Warn().
+			Str("backup_release", man.Release).
+			Str("backup_namespace", man.Namespace).
+			Str("release", m.blockNodeInputs.Release).
+			Str("namespace", m.blockNodeInputs.Namespace).
+			Msg("Restoring a backup taken from a different release")
+	}
+	return backup.Verify(ctx, dir)
+}
+
+// RestoreStorage replaces the contents of every storage volume with the backup
+// in dir. The block node must already be scaled down. Every volume is cleared
+// first, including those the backup does not hold — which then start empty, as
+// after a reset — so restored and stale data are never mixed. A backup volume
+// the configured chart version does not mount (accepted only with force by
+// ValidateBackupCompatibility) is skipped.
+func (m *Manager) RestoreStorage(ctx context.Context, dir string, force bool) error {
+	man, err := backup.ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+	if err := ValidateBackupCompatibility(man, m.blockNodeInputs.ChartVersion, force); err != nil {
+		return err
+	}
+	vols, err := m.storageVolumes()
+	if err != nil {
+		return err
+	}
+	if err := m.SetupStorage(ctx); err != nil {
+		return err
+	}
+
+	restored := map[string]bool{}
+	for _, v := range vols {
+		if err := m.ClearStorageDirectory(v.Path); err != nil {
+			return err
+		}
+		if man.Volume(v.Name) == nil {
+			m.logger.Info().Str("volume", v.Name).Str("path", v.Path).
+				Msg("Volume is not in the backup; leaving it empty")
+			continue
+		}
+		if err := backup.RestoreVolume(ctx, dir, v.Name, v.Path); err != nil {
+			return errorx.Decorate(err, "failed to restore the %s volume into %s", v.Name, v.Path)
+		}
+		restored[v.Name] = true
+	}
+	for _, v := range man.Volumes {
+		if !restored[v.Name] {
+			m.logger.Warn().Str("volume", v.Name).Str("chart_version", m.blockNodeInputs.ChartVersion).
+				Msg("Backup volume is not mounted by this chart version; skipped")
+		}
+	}
+
+	m.logger.Info().Str("backup", dir).Int("volumes", len(restored)).Msg("Block node storage restored")
+	return nil
+}
+
+// ValidateBackupCompatibility checks that a backup taken at man.ChartVersion
+// can be restored under chartVersion. Every optional volume in the backup must
+// be one the OptionalStorage registry provisions for chartVersion — otherwise
+// its data would have nowhere to go (e.g. a verification volume restored onto
+// a chart that has retired it). Restoring onto an older chart than the backup
+// was taken with is refused too, since the node may not read state a newer
+// release wrote. force accepts both, skipping unmounted volumes. Optional
+// volumes the target needs but the backup lacks are fine: they start empty,
+// exactly as after the upgrade-time storage migration. Archives of retired
+// volumes (BackupKindRetiredStorage) are never restorable.
+func ValidateBackupCompatibility(man *backup.Manifest, chartVersion string, force bool) error {
+	if man.Kind != "" {
+		return errorx.IllegalArgument.New("backup is a %s archive, not a block node storage backup", man.Kind).
+			WithProperty(models.ErrPropertyResolution,
+				"extract the volume you need by hand: tar --zstd -xf <backup>/<volume>.tar.zst -C <dir>")
+	}
+	target, err := semver.NewSemver(chartVersion)
+	if err != nil {
+		return errorx.IllegalArgument.Wrap(err, "invalid block node chart version %q", chartVersion)
+	}
+	if man.ChartVersion != "" {
+		taken, err := semver.NewSemver(man.ChartVersion)
+		if err == nil && taken.GreaterThan(target) && !force {
+			return errorx.IllegalState.New(
+				"backup was taken with chart %s, newer than the installed chart %s", man.ChartVersion, chartVersion).
+				WithProperty(models.ErrPropertyResolution,
+					"upgrade the block node to "+man.ChartVersion+" before restoring, or pass --force to restore anyway")
+		}
+	}
+
+	registry := map[string]OptionalStorage{}
+	for _, optStor := range GetOptionalStorages() {
+		registry[optStor.Name] = optStor
+	}
+	for _, v := range man.Volumes {
+		switch v.Name {
+		case BackupVolumeArchive, BackupVolumeLive, BackupVolumeLog:
+			continue
+		}
+		optStor, ok := registry[v.Name]
+		if !ok {
+			return errorx.IllegalState.New("backup holds volume %q, which this provisioner does not know", v.Name)
+		}
+		if optStor.RequiredByVersion(chartVersion) || force {
+			continue
+		}
+		return errorx.IllegalState.New(
+			"backup holds the %s volume, which chart %s does not mount (it exists for chart versions %s)",
+			v.Name, chartVersion, versionRange(optStor)).
+			WithProperty(models.ErrPropertyResolution,
+				"restore onto a chart version that mounts it, or pass --force to restore without it")
+	}
+	return nil
+}
+
+func versionRange(o OptionalStorage) string {
+	if o.MaxVersion == "" {
+		return ">= " + o.MinVersion
+	}
+	return ">= " + o.MinVersion + ", < " + o.MaxVersion
+}
+
+// PathsOverlap reports whether a and b are the same directory or one lies
+// beneath the other.
+func PathsOverlap(a, b string) bool {
+	return within(a, b) || within(b, a)
+}
+
+// within reports whether path is dir or lies beneath it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
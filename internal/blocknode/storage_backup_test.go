@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/backup"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageVolumes_FollowsChartVersion(t *testing.T) {
+	m := &Manager{blockNodeInputs: models.BlockNodeInputs{
+		ChartVersion: "0.36.0",
+		Storage:      models.BlockNodeStorage{BasePath: "/mnt/bn"},
+	}}
+	vols, err := m.storageVolumes()
+	require.NoError(t, err)
+	require.Equal(t, []backup.Source{
+		{Name: "archive", Path: "/mnt/bn/archive", AppendOnly: true},
+		{Name: "live", Path: "/mnt/bn/live"},
+		{Name: "log", Path: "/mnt/bn/logs"},
+		{Name: "verification", Path: "/mnt/bn/verification"},
+		{Name: "plugins", Path: "/mnt/bn/plugins"},
+	}, vols)
+
+	m.blockNodeInputs.ChartVersion = "0.37.0"
+	vols, err = m.storageVolumes()
+	require.NoError(t, err)
+	var names []string
+	for _, v := range vols {
+		names = append(names, v.Name)
+	}
+	require.Equal(t, []string{"archive", "live", "log", "plugins", "application-state"}, names)
+}
+
+func TestBackupStorage_WritesEveryVolume(t *testing.T) {
+	base := t.TempDir()
+	for _, dir := range []string{"archive", "live", "logs", "plugins"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(base, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(base, dir, "f"), []byte(dir), 0o644))
+	}
+	m := &Manager{logger: testLogger(), blockNodeInputs: models.BlockNodeInputs{
+		Namespace:    "block-node",
+		Release:      "bn",
+		ChartVersion: "0.37.0",
+		Storage:      models.BlockNodeStorage{BasePath: base},
+	}}
+
+	_, err := m.BackupStorage(context.Background(), filepath.Join(base, "live", "backups"), false)
+	require.ErrorContains(t, err, "inside the live storage directory")
+
+	dir, err := m.BackupStorage(context.Background(), t.TempDir(), false)
+	require.NoError(t, err)
+	man, err := backup.ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, "0.37.0", man.ChartVersion)
+	var names []string
+	for _, v := range man.Volumes {
+		names = append(names, v.Name)
+	}
+	require.Equal(t, []string{"archive", "live", "log", "plugins"}, names,
+		"application-state has no directory yet and is left out")
+}
+
+func TestValidateBackupCompatibility(t *testing.T) {
+	man := func(chart string, volumes ...string) *backup.Manifest {
+		m := &backup.Manifest{ChartVersion: chart}
+		for _, v := range volumes {
+			m.Volumes = append(m.Volumes, backup.Volume{Name: v})
+		}
+		return m
+	}
+
+	require.NoError(t, ValidateBackupCompatibility(man("0.36.0", "archive", "live", "log", "verification", "plugins"), "0.36.2", false))
+	require.NoError(t, ValidateBackupCompatibility(man("0.36.0", "archive", "live", "plugins"), "0.37.0", false),
+		"application-state missing from the backup simply starts empty")
+
+	err := ValidateBackupCompatibility(man("0.36.0", "archive", "verification"), "0.37.0", false)
+	require.ErrorContains(t, err, "does not mount")
+	require.ErrorContains(t, err, ">= 0.26.2, < 0.37.0-0")
+	require.NoError(t, ValidateBackupCompatibility(man("0.36.0", "archive", "verification"), "0.37.0", true))
+
+	require.ErrorContains(t, ValidateBackupCompatibility(man("0.37.0", "archive"), "0.36.0", false), "newer than the installed chart")
+	require.NoError(t, ValidateBackupCompatibility(man("0.37.0", "archive"), "0.36.0", true))
+
+	require.ErrorContains(t, ValidateBackupCompatibility(man("0.36.0", "mystery"), "0.36.0", true), "does not know")
+	retired := man("", "verification")
+	retired.Kind = BackupKindRetiredStorage
+	require.ErrorContains(t, ValidateBackupCompatibility(retired, "0.36.0", true), "not a block node storage backup")
+	require.ErrorContains(t, ValidateBackupCompatibility(man("0.36.0"), "not-a-version", false), "invalid block node chart version")
+}
+
+func TestWithin(t *testing.T) {
+	require.True(t, within("/mnt/bn/live", "/mnt/bn/live"))
+	require.True(t, within("/mnt/bn/live/x", "/mnt/bn/live/"))
+	require.False(t, within("/mnt/bn/live2", "/mnt/bn/live"))
+	require.False(t, within("/mnt/backups", "/mnt/bn"))
+	require.True(t, PathsOverlap("/mnt/bn", "/mnt/bn/live"))
+	require.True(t, PathsOverlap("/mnt/bn/live", "/mnt/bn"))
+	require.False(t, PathsOverlap("/mnt/bn/live", "/mnt/bn/live2"))
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashgraph/solo-weaver/internal/kube"
 	"github.com/hashgraph/solo-weaver/internal/templates"
 	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/stretchr/testify/assert"
@@ -109,6 +110,7 @@ func TestCreatePersistentVolumes_ValidYAMLOutput(t *testing.T) {
 			data := struct {
 				Namespace               string
 				Release                 string
+				PVPrefix                string
 				LivePath                string
 				ArchivePath             string
 				LogPath                 string
@@ -251,6 +253,7 @@ func TestStorageConfigNoCorruption(t *testing.T) {
 	data := struct {
 		Namespace               string
 		Release                 string
+		PVPrefix                string
 		LivePath                string
 		ArchivePath             string
 		LogPath                 string
@@ -329,6 +332,7 @@ func TestStorageConfigRendersLabels(t *testing.T) {
 	data := struct {
 		Namespace               string
 		Release                 string
+		PVPrefix                string
 		LivePath                string
 		ArchivePath             string
 		LogPath                 string
@@ -386,6 +390,7 @@ func TestStorageConfigRendersApplicationState(t *testing.T) {
 	data := struct {
 		Namespace               string
 		Release                 string
+		PVPrefix                string
 		LivePath                string
 		ArchivePath             string
 		LogPath                 string
@@ -462,3 +467,66 @@ func TestOptionalStorageRendersLabels(t *testing.T) {
 	assert.Equal(t, 2, strings.Count(rendered, "app.kubernetes.io/component: block-node-storage"))
 	assert.Equal(t, 2, strings.Count(rendered, "app.kubernetes.io/instance: my-release"))
 }
+
+// TestStorageConfigPrefixesInstancePVNames verifies an additional block node
+// instance renders PersistentVolume names of its own (PVs are cluster-scoped)
+// while its namespaced PVC names stay the chart's fixed claim names.
+func TestStorageConfigPrefixesInstancePVNames(t *testing.T) {
+	m := &Manager{blockNodeInputs: models.BlockNodeInputs{Release: "testnet", Namespace: "testnet"}}
+	require.Equal(t, "testnet-", m.pvPrefix())
+	require.Empty(t, (&Manager{blockNodeInputs: models.BlockNodeInputs{Release: "block-node"}}).pvPrefix())
+
+	data := struct {
+		Namespace               string
+		Release                 string
+		PVPrefix                string
+		LivePath                string
+		ArchivePath             string
+		LogPath                 string
+		VerificationPath        string
+		PluginsPath             string
+		ApplicationStatePath    string
+		LiveSize                string
+		ArchiveSize             string
+		LogSize                 string
+		VerificationSize        string
+		PluginsSize             string
+		ApplicationStateSize    string
+		IncludeVerification     bool
+		IncludePlugins          bool
+		IncludeApplicationState bool
+	}{
+		Namespace:               "testnet",
+		Release:                 "testnet",
+		PVPrefix:                m.pvPrefix(),
+		LivePath:                "/mnt/testnet/live",
+		ArchivePath:             "/mnt/testnet/archive",
+		LogPath:                 "/mnt/testnet/log",
+		ApplicationStatePath:    "/mnt/testnet/application-state",
+		IncludeApplicationState: true,
+	}
+
+	rendered, err := templates.Render("files/block-node/storage-config.yaml", data)
+	require.NoError(t, err)
+
+	for _, pv := range []string{"live-storage-pv", "archive-storage-pv", "logging-storage-pv", "application-state-storage-pv"} {
+		assert.Contains(t, rendered, "  name: testnet-"+pv+"\n")
+		assert.Contains(t, rendered, "volumeName: testnet-"+pv+"\n")
+		assert.NotContains(t, rendered, "  name: "+pv+"\n")
+	}
+	assert.Contains(t, rendered, "  name: live-storage-pvc\n")
+}
+
+// TestStorageManifestNamesAreInstanceScoped verifies each block node instance
+// records its storage manifests under names of its own, so one instance's
+// apply or uninstall leaves the other instances' drift records alone.
+func TestStorageManifestNamesAreInstanceScoped(t *testing.T) {
+	def := &Manager{blockNodeInputs: models.BlockNodeInputs{Release: "block-node"}}
+	other := &Manager{blockNodeInputs: models.BlockNodeInputs{Release: "testnet"}}
+	optStor := OptionalStorage{Name: "plugins"}
+
+	assert.Equal(t, "block-node-storage", def.storageManifest(kube.ManifestBlockNodeStorage))
+	assert.Equal(t, "block-node-storage-testnet", other.storageManifest(kube.ManifestBlockNodeStorage))
+	assert.Equal(t, "block-node-plugins-storage", def.optionalStorageManifest(optStor))
+	assert.Equal(t, "block-node-plugins-storage-testnet", other.optionalStorageManifest(optStor))
+}
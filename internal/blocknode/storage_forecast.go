@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/solo-weaver/internal/blocknode/forecast"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+)
+
+// The storage forecast works from the configured storage layout alone, without
+// a Manager: the install preflight runs before the cluster, and so before a
+// kube client, exists.
+
+// ForecastVolumes returns the storage volumes the forecast samples: the same
+// volumes a backup covers.
+func ForecastVolumes(inputs models.BlockNodeInputs) ([]forecast.Volume, error) {
+	srcs, err := resolveStorageVolumes(inputs)
+	if err != nil {
+		return nil, err
+	}
+	vols := make([]forecast.Volume, 0, len(srcs))
+	for _, s := range srcs {
+		vols = append(vols, forecast.Volume{Name: s.Name, Path: s.Path})
+	}
+	return vols, nil
+}
+
+// RecordStorageSample records the configured volumes in volumesPath, for the
+// daemon's storage sampler, and appends a sample of each to store.
+func RecordStorageSample(inputs models.BlockNodeInputs, volumesPath string, store forecast.Store, now time.Time) error {
+	vols, err := ForecastVolumes(inputs)
+	if err != nil {
+		return err
+	}
+	if err := forecast.WriteVolumes(volumesPath, vols); err != nil {
+		return err
+	}
+	samples, err := forecast.Measure(vols, now)
+	if err != nil {
+		return err
+	}
+	return store.Append(samples, now)
+}
+
+// ForecastStorage projects each volume's growth from samples, capped by the
+// configured retention thresholds.
+func ForecastStorage(inputs models.BlockNodeInputs, samples []forecast.Sample) ([]forecast.VolumeForecast, error) {
+	vols, err := ForecastVolumes(inputs)
+	if err != nil {
+		return nil, err
+	}
+	retention, err := parseRetention(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return forecast.Project(forecast.Input{
+		Volumes:       vols,
+		Samples:       samples,
+		LiveVolume:    BackupVolumeLive,
+		ArchiveVolume: BackupVolumeArchive,
+		Retention:     retention,
+	})
+}
+
+// WriteStorageForecast renders forecasts as a table, one row per volume.
+// Growth and the day counts read "n/a" until the samples span
+// forecast.MinGrowthSpan.
+func WriteStorageForecast(w io.Writer, forecasts []forecast.VolumeForecast) {
+	_, _ = fmt.Fprintf(w, "%-18s %-40s %10s %9s %12s %10s %11s %12s\n",
+		"VOLUME", "PATH", "USED", "FILES", "GROWTH/DAY", "CAP", "DAYS-TO-CAP", "DAYS-TO-FULL")
+	rated := false
+	for _, f := range forecasts {
+		growth, toCap, toFull := "n/a", "n/a", "n/a"
+		if f.HasRate {
+			rated = true
+			growth = humanBytes(int64(f.Growth.PerDay))
+			toCap, toFull = formatDays(f.DaysToCap), formatDays(f.DaysToFull)
+		}
+		capacity := "none"
+		if f.Growth.Cap > 0 {
+			capacity = humanBytes(f.Growth.Cap)
+		}
+		_, _ = fmt.Fprintf(w, "%-18s %-40s %10s %9d %12s %10s %11s %12s\n",
+			f.Volume.Name, f.Volume.Path, humanBytes(f.Growth.Used), f.Files, growth, capacity, toCap, toFull)
+	}
+	if !rated {
+		_, _ = fmt.Fprintf(w, "\nGrowth needs samples spanning at least %s: re-run later, or enable the "+
+			"daemon's storage sampler (block node install/reconfigure with traffic shaping enabled).\n",
+			forecast.MinGrowthSpan)
+	}
+}
+
+// formatDays renders a day count, +Inf as "never".
+func formatDays(days float64) string {
+	if math.IsInf(days, 1) {
+		return "never"
+	}
+	return strconv.FormatFloat(days, 'f', 1, 64)
+}
+
+// CheckStorageCapacity returns a warning for each way the configured storage
+// cannot fit the filesystem behind BasePath (the live path when BasePath is
+// unset):
+//   - the volume sizes of the volumes on that filesystem exceed what it has
+//     available, counting what those volumes already use as available, and
+//   - with a block size measured from samples, the recent retention needs a
+//     bigger live tier than LiveSize, or the historic retention a bigger
+//     archive than ArchiveSize; the larger figure is then counted above.
+//
+// An unlimited historic retention is not a warning: an archive that grows
+// until the disk fills is a deliberate choice, which the forecast projects.
+func CheckStorageCapacity(inputs models.BlockNodeInputs, samples []forecast.Sample) ([]string, error) {
+	vols, err := ForecastVolumes(inputs)
+	if err != nil {
+		return nil, err
+	}
+	retention, err := parseRetention(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	root := inputs.Storage.BasePath
+	if root == "" {
+		for _, v := range vols {
+			if v.Name == BackupVolumeLive {
+				root = v.Path
+			}
+		}
+	}
+	fsys, err := forecast.StatFilesystem(root)
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read the capacity of the filesystem behind %s", root)
+	}
+
+	latest := forecast.Latest(samples)
+	bpb, measured := forecast.BytesPerBlock(latest[BackupVolumeLive])
+
+	var warnings []string
+	var required, used int64
+	for _, v := range vols {
+		vfs, err := forecast.StatFilesystem(v.Path)
+		if err != nil {
+			return nil, errorx.ExternalError.Wrap(err, "failed to read the capacity of the filesystem behind %s", v.Path)
+		}
+		if vfs.Device != fsys.Device {
+			continue
+		}
+
+		size, err := parseStorageSize(volumeSize(&inputs.Storage, v.Name))
+		if err != nil {
+			return nil, err
+		}
+		var threshold int64
+		var flag string
+		switch v.Name {
+		case BackupVolumeLive:
+			threshold, flag = retention.Recent, "--recent-retention"
+		case BackupVolumeArchive:
+			threshold, flag = retention.Historic, "--historic-retention"
+		}
+		if need := forecast.RetentionCap(bpb, threshold); measured && need > size {
+			if size > 0 {
+				warnings = append(warnings, fmt.Sprintf(
+					"%s %d blocks needs about %s of %s storage at the measured %s per block, more than its configured %s",
+					flag, threshold, humanBytes(need), v.Name, humanBytes(int64(bpb)), humanBytes(size)))
+			}
+			size = need
+		}
+
+		required += size
+		used += latest[v.Name].Bytes
+	}
+
+	if available := fsys.Free + used; required > available {
+		warnings = append(warnings, fmt.Sprintf(
+			"block node storage on the filesystem behind %s needs %s, but only %s is available (%s total)",
+			root, humanBytes(required), humanBytes(available), humanBytes(fsys.Total)))
+	}
+	return warnings, nil
+}
+
+// volumeSize returns the configured size of the named storage volume.
+func volumeSize(s *models.BlockNodeStorage, name string) string {
+	switch name {
+	case BackupVolumeArchive:
+		return s.ArchiveSize
+	case BackupVolumeLive:
+		return s.LiveSize
+	case BackupVolumeLog:
+		return s.LogSize
+	}
+	for _, optStor := range optionalStorages {
+		if optStor.Name == name {
+			return optStor.GetSize(s)
+		}
+	}
+	return ""
+}
+
+// parseRetention parses the configured retention thresholds, applying the
+// chart defaults to unset ones.
+func parseRetention(inputs models.BlockNodeInputs) (forecast.Retention, error) {
+	recent, historic := inputs.RecentRetention, inputs.HistoricRetention
+	if recent == "" {
+		recent = models.DefaultRecentRetention
+	}
+	if historic == "" {
+		historic = models.DefaultHistoricRetention
+	}
+	var r forecast.Retention
+	var err error
+	if r.Recent, err = strconv.ParseInt(recent, 10, 64); err != nil {
+		return r, errorx.IllegalArgument.Wrap(err, "invalid recent retention threshold: %s", recent)
+	}
+	if r.Historic, err = strconv.ParseInt(historic, 10, 64); err != nil {
+		return r, errorx.IllegalArgument.Wrap(err, "invalid historic retention threshold: %s", historic)
+	}
+	return r, nil
+}
+
+// parseStorageSize converts a storage size in the format sanity.ValidateStorageSize
+// accepts (<number>(Mi|Gi|Ti)) to bytes. An empty size is zero.
+func parseStorageSize(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+	units := []struct {
+		suffix string
+		shift  uint
+	}{{"Ti", 40}, {"Gi", 30}, {"Mi", 20}}
+	for _, u := range units {
+		if num, ok := strings.CutSuffix(size, u.suffix); ok {
+			n, err := strconv.ParseInt(num, 10, 64)
+			if err != nil {
+				break
+			}
+			return n << u.shift, nil
+		}
+	}
+	return 0, errorx.IllegalArgument.New("invalid storage size: %s (must be <number>(Mi|Gi|Ti))", size)
+}
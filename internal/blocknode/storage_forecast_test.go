@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/solo-weaver/internal/blocknode/forecast"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStorageSize(t *testing.T) {
+	for in, want := range map[string]int64{"": 0, "5Mi": 5 << 20, "10Gi": 10 << 30, "2Ti": 2 << 40} {
+		got, err := parseStorageSize(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"5G", "Gi", "1.5Gi"} {
+		_, err := parseStorageSize(in)
+		require.Error(t, err, in)
+	}
+}
+
+func forecastInputs(t *testing.T, storage models.BlockNodeStorage, recent, historic string) models.BlockNodeInputs {
+	t.Helper()
+	if storage.BasePath == "" {
+		storage.BasePath = t.TempDir()
+	}
+	return models.BlockNodeInputs{
+		ChartVersion:      "0.36.0",
+		Storage:           storage,
+		RecentRetention:   recent,
+		HistoricRetention: historic,
+	}
+}
+
+func TestRecordStorageSampleAndForecastStorage(t *testing.T) {
+	in := forecastInputs(t, models.BlockNodeStorage{}, "", "")
+	dir := t.TempDir()
+	volumesPath := filepath.Join(dir, "volumes.json")
+	store := forecast.Store{Path: filepath.Join(dir, "samples.jsonl")}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, RecordStorageSample(in, volumesPath, store, now))
+	vols, err := forecast.ReadVolumes(volumesPath)
+	require.NoError(t, err)
+	require.Equal(t, BackupVolumeArchive, vols[0].Name)
+	require.Equal(t, filepath.Join(in.Storage.BasePath, "live"), vols[1].Path)
+
+	samples, err := store.Read()
+	require.NoError(t, err)
+	require.Len(t, samples, len(vols))
+
+	got, err := ForecastStorage(in, samples)
+	require.NoError(t, err)
+	require.Len(t, got, len(vols))
+	require.False(t, got[0].HasRate, "one sample has no growth rate")
+}
+
+func TestCheckStorageCapacity(t *testing.T) {
+	sized := models.BlockNodeStorage{LiveSize: "1Gi", ArchiveSize: "1Gi", LogSize: "1Gi"}
+
+	in := forecastInputs(t, sized, "", "")
+	warnings, err := CheckStorageCapacity(in, nil)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	huge := sized
+	huge.ArchiveSize = "1000000Ti"
+	in = forecastInputs(t, huge, "", "")
+	warnings, err = CheckStorageCapacity(in, nil)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "needs")
+	require.Contains(t, warnings[0], "only")
+
+	// 1 MiB blocks: 96000 recent blocks need ~94 GiB of live storage, more than 1Gi.
+	in = forecastInputs(t, sized, "", "")
+	samples := []forecast.Sample{{Volume: BackupVolumeLive, Bytes: 10 << 20, Files: 10}}
+	warnings, err = CheckStorageCapacity(in, samples)
+	require.NoError(t, err)
+	require.NotEmpty(t, warnings)
+	require.Contains(t, warnings[0], "--recent-retention 96000 blocks needs about 93.75 GiB of live storage")
+
+	in = forecastInputs(t, sized, "500", "")
+	warnings, err = CheckStorageCapacity(in, samples)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	in = forecastInputs(t, sized, "", "x")
+	_, err = CheckStorageCapacity(in, nil)
+	require.ErrorContains(t, err, "invalid historic retention")
+}
+
+func TestWriteStorageForecast(t *testing.T) {
+	var b strings.Builder
+	WriteStorageForecast(&b, []forecast.VolumeForecast{
+		{
+			Volume:  forecast.Volume{Name: BackupVolumeLive, Path: "/mnt/bn/live"},
+			Files:   10,
+			Growth:  forecast.Growth{Used: 10 << 20, PerDay: 1 << 20, Cap: 20 << 20},
+			HasRate: true, DaysToCap: 10, DaysToFull: math.Inf(1),
+		},
+		{
+			Volume:  forecast.Volume{Name: BackupVolumeArchive, Path: "/mnt/bn/archive"},
+			Growth:  forecast.Growth{PerDay: 2 << 20},
+			HasRate: true, DaysToCap: math.Inf(1), DaysToFull: 42.25,
+		},
+	})
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, []string{"live", "/mnt/bn/live", "10.00", "MiB", "10", "1.00", "MiB", "20.00", "MiB", "10.0", "never"},
+		strings.Fields(lines[1]))
+	require.Equal(t, []string{"archive", "/mnt/bn/archive", "0", "B", "0", "2.00", "MiB", "none", "never", "42.2"},
+		strings.Fields(lines[2]))
+
+	b.Reset()
+	WriteStorageForecast(&b, []forecast.VolumeForecast{{Volume: forecast.Volume{Name: BackupVolumeLog, Path: "/mnt/bn/logs"}}})
+	require.Contains(t, b.String(), "n/a")
+	require.Contains(t, b.String(), "Growth needs samples spanning at least 1h0m0s")
+}
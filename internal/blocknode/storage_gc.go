@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashgraph/solo-weaver/internal/backup"
+	"github.com/hashgraph/solo-weaver/pkg/semver"
+	"github.com/joomcode/errorx"
+)
+
+// BackupKindRetiredStorage marks an archive of storage volumes the installed
+// chart no longer mounts, written by `block node storage gc --archive-to`.
+// It is kept for the operator to extract by hand; backup.Latest never bases
+// an incremental backup on it and restore refuses it.
+const BackupKindRetiredStorage = "retired-storage"
+
+// RetiredStorage is what is left of one OptionalStorage volume the chart no
+// longer mounts: its PV/PVC, if they still exist, and its host directory.
+type RetiredStorage struct {
+	Storage OptionalStorage
+	// PVC and PV are set to the resource names when the resources exist;
+	// Capacity is the PV's spec.capacity.storage.
+	PVC      string
+	PV       string
+	Capacity string
+	// Path is the volume's host directory, empty when it cannot be resolved
+	// or overlaps storage the installed chart still mounts. Files and Bytes
+	// count the regular files beneath it.
+	Path      string
+	DirExists bool
+	Files     int
+	Bytes     int64
+}
+
+// Present reports whether anything of the volume is left to collect.
+func (r RetiredStorage) Present() bool {
+	return r.PVC != "" || r.PV != "" || r.DirExists
+}
+
+// String summarises the leftovers for logs and the gc report, e.g.
+// "verification: PVC verification-storage-pvc, PV verification-storage-pv (10Gi), /mnt/bn/verification (1.20 GB in 42 files)".
+func (r RetiredStorage) String() string {
+	var parts []string
+	if r.PVC != "" {
+		parts = append(parts, "PVC "+r.PVC)
+	}
+	if r.PV != "" {
+		pv := "PV " + r.PV
+		if r.Capacity != "" {
+			pv += " (" + r.Capacity + ")"
+		}
+		parts = append(parts, pv)
+	}
+	if r.DirExists {
+		parts = append(parts, fmt.Sprintf("%s (%s in %d files)", r.Path, humanBytes(r.Bytes), r.Files))
+	}
+	if len(parts) == 0 {
+		return r.Storage.Name + ": nothing left"
+	}
+	return r.Storage.Name + ": " + strings.Join(parts, ", ")
+}
+
+// RetiredOptionalStorages returns the registry entries retired at or before
+// chartVersion, i.e. those with a MaxVersion that chartVersion has reached.
+// Entries whose MinVersion was never reached are not retired, just absent.
+func RetiredOptionalStorages(chartVersion string) []OptionalStorage {
+	v, err := semver.NewSemver(chartVersion)
+	if err != nil {
+		return nil
+	}
+	var retired []OptionalStorage
+	for _, optStor := range optionalStorages {
+		if optStor.MaxVersion == "" {
+			continue
+		}
+		maxVer, err := semver.NewSemver(optStor.MaxVersion)
+		if err != nil {
+			continue
+		}
+		if !v.LessThan(maxVer) {
+			retired = append(retired, optStor)
+		}
+	}
+	return retired
+}
+
+// FindRetiredStorage inspects the cluster and the host for the volumes
+// retired at the installed chart version (the configured one when the release
+// is not installed) and returns those with anything left to collect.
+func (m *Manager) FindRetiredStorage(ctx context.Context) ([]RetiredStorage, error) {
+	chartVersion, err := m.GetInstalledVersion()
+	if err != nil {
+		return nil, err
+	}
+	if chartVersion == "" {
+		chartVersion = m.blockNodeInputs.ChartVersion
+	}
+	if chartVersion == "" {
+		return nil, errorx.IllegalState.New("cannot determine the block node chart version to find retired storage for")
+	}
+
+	var found []RetiredStorage
+	for _, optStor := range RetiredOptionalStorages(chartVersion) {
+		r, err := m.inspectRetiredStorage(ctx, optStor)
+		if err != nil {
+			return nil, err
+		}
+		if r.Present() {
+			found = append(found, r)
+		}
+	}
+	return found, nil
+}
+
+// inspectRetiredStorage looks up optStor's PV/PVC and measures its host
+// directory.
+func (m *Manager) inspectRetiredStorage(ctx context.Context, optStor OptionalStorage) (RetiredStorage, error) {
+	r, err := m.retiredStorageDir(optStor)
+	if err != nil {
+		return r, err
+	}
+
+	ns := m.blockNodeInputs.Namespace
+	exists, err := m.kubeClient.ResourceExists(ctx, "v1", "PersistentVolumeClaim", ns, optStor.PVCName)
+	if err != nil {
+		return r, errorx.IllegalState.Wrap(err, "failed to look up PVC %s/%s", ns, optStor.PVCName)
+	}
+	if exists {
+		r.PVC = optStor.PVCName
+	}
+	pvName := m.pvPrefix() + optStor.PVName
+	exists, err = m.kubeClient.ResourceExists(ctx, "v1", "PersistentVolume", "", pvName)
+	if err != nil {
+		return r, errorx.IllegalState.Wrap(err, "failed to look up PV %s", pvName)
+	}
+	if exists {
+		r.PV = pvName
+		r.Capacity, _ = m.kubeClient.GetResourceNestedString(ctx, "v1", "PersistentVolume", "", pvName,
+			"spec", "capacity", "storage")
+	}
+	return r, nil
+}
+
+// retiredStorageDir resolves and measures optStor's host directory. A
+// directory that is, contains or lies inside a volume the configured chart
+// version still mounts (e.g. a verification path pointed at the live tier) is
+// never reported, so gc cannot remove live data.
+func (m *Manager) retiredStorageDir(optStor OptionalStorage) (RetiredStorage, error) {
+	r := RetiredStorage{Storage: optStor}
+
+	dir, _, err := m.resolveOptionalStoragePathAndSize(optStor)
+	if err != nil {
+		m.logger.Debug().Err(err).Str("storage", optStor.Name).Msg("Retired storage has no resolvable host directory")
+		return r, nil
+	}
+
+	vols, err := m.storageVolumes()
+	if err != nil {
+		return r, err
+	}
+	for _, v := range vols {
+		if PathsOverlap(dir, v.Path) {
+			m.logger.Warn().Str("storage", optStor.Name).Str("path", dir).Str("volume", v.Name).Str("volume_path", v.Path).
+				Msg("Retired storage directory overlaps a mounted volume; leaving it alone")
+			return r, nil
+		}
+	}
+
+	r.Path = dir
+	r.DirExists, r.Files, r.Bytes, err = dirUsage(dir)
+	if err != nil {
+		return r, errorx.ExternalError.Wrap(err, "failed to measure %s storage directory %s", optStor.Name, dir)
+	}
+	return r, nil
+}
+
+// CollectRetiredStorage removes the leftovers in found: PVC, then PV, then
+// the host directory. When archiveTo is set, the host directories are first
+// written to a BackupKindRetiredStorage archive under it, and nothing is
+// removed unless that archive completes.
+func (m *Manager) CollectRetiredStorage(ctx context.Context, found []RetiredStorage, archiveTo string) error {
+	if archiveTo != "" {
+		var sources []backup.Source
+		for _, r := range found {
+			if !r.DirExists {
+				continue
+			}
+			if within(archiveTo, r.Path) {
+				return errorx.IllegalArgument.New(
+					"archive target %s is inside the retired %s storage directory %s", archiveTo, r.Storage.Name, r.Path)
+			}
+			sources = append(sources, backup.Source{Name: r.Storage.Name, Path: r.Path})
+		}
+		if len(sources) > 0 {
+			dir, _, err := backup.Create(ctx, backup.Options{
+				Target:    archiveTo,
+				Namespace: m.blockNodeInputs.Namespace,
+				Release:   m.blockNodeInputs.Release,
+				Kind:      BackupKindRetiredStorage,
+				Volumes:   sources,
+			})
+			if err != nil {
+				return errorx.Decorate(err, "failed to archive retired storage; nothing was removed")
+			}
+			m.logger.Info().Str("archive", dir).Int("volumes", len(sources)).Msg("Retired storage archived")
+		}
+	}
+
+	ns := m.blockNodeInputs.Namespace
+	for _, r := range found {
+		if r.PVC != "" {
+			m.logger.Info().Str("storage", r.Storage.Name).Str("pvc", r.PVC).Msg("Deleting retired PVC")
+			if err := m.kubeClient.DeletePVC(ctx, ns, r.PVC); err != nil {
+				return errorx.IllegalState.Wrap(err, "failed to delete retired PVC %s", r.PVC)
+			}
+		}
+		if r.PV != "" {
+			m.logger.Info().Str("storage", r.Storage.Name).Str("pv", r.PV).Msg("Deleting retired PV")
+			if err := m.kubeClient.DeletePV(ctx, r.PV); err != nil {
+				return errorx.IllegalState.Wrap(err, "failed to delete retired PV %s", r.PV)
+			}
+		}
+		if r.DirExists {
+			m.logger.Info().Str("storage", r.Storage.Name).Str("path", r.Path).Int64("bytes", r.Bytes).
+				Msg("Removing retired storage directory")
+			if err := m.fsManager.RemoveAll(r.Path); err != nil {
+				return errorx.IllegalState.Wrap(err, "failed to remove retired storage directory %s", r.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// dirUsage counts the regular files under dir and their total size. A missing
+// dir is reported as not existing rather than as an error.
+func dirUsage(dir string) (exists bool, files int, bytes int64, err error) {
+	fi, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return false, 0, 0, nil
+	}
+	if err != nil {
+		return false, 0, 0, err
+	}
+	if !fi.IsDir() {
+		return false, 0, 0, errorx.IllegalState.New("%s is not a directory", dir)
+	}
+	err = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		bytes += info.Size()
+		return nil
+	})
+	return true, files, bytes, err
+}
+
+// humanBytes renders a byte count with a binary magnitude suffix.
+func humanBytes(b int64) string {
+	const k = 1024.0
+	f := float64(b)
+	switch {
+	case f >= k*k*k:
+		return fmt.Sprintf("%.2f GiB", f/(k*k*k))
+	case f >= k*k:
+		return fmt.Sprintf("%.2f MiB", f/(k*k))
+	case f >= k:
+		return fmt.Sprintf("%.2f KiB", f/k)
+	default:
+		return fmt.Sprintf("%d B", b)
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/backup"
+	"github.com/hashgraph/solo-weaver/pkg/fsx"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetiredOptionalStorages(t *testing.T) {
+	names := func(v string) []string {
+		var out []string
+		for _, o := range RetiredOptionalStorages(v) {
+			out = append(out, o.Name)
+		}
+		return out
+	}
+	require.Empty(t, names("0.36.2"))
+	require.Equal(t, []string{"verification"}, names("0.37.0-rc1"))
+	require.Equal(t, []string{"verification"}, names("0.38.0"))
+	require.Empty(t, names("not-a-version"))
+}
+
+func TestRetiredStorageDir_MeasuresAndGuardsMountedVolumes(t *testing.T) {
+	base := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "verification", "state"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "verification", "state", "a"), make([]byte, 1500), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "verification", "b"), make([]byte, 500), 0o644))
+
+	m := &Manager{logger: testLogger(), blockNodeInputs: models.BlockNodeInputs{
+		ChartVersion: "0.37.0",
+		Storage:      models.BlockNodeStorage{BasePath: base},
+	}}
+	verification := RetiredOptionalStorages("0.37.0")[0]
+
+	r, err := m.retiredStorageDir(verification)
+	require.NoError(t, err)
+	require.True(t, r.DirExists)
+	require.Equal(t, filepath.Join(base, "verification"), r.Path)
+	require.Equal(t, 2, r.Files)
+	require.EqualValues(t, 2000, r.Bytes)
+	require.Equal(t, "verification: "+r.Path+" (1.95 KiB in 2 files)", r.String())
+
+	// A verification path pointed at a mounted volume is never collected.
+	m.blockNodeInputs.Storage.VerificationPath = filepath.Join(base, "live")
+	r, err = m.retiredStorageDir(verification)
+	require.NoError(t, err)
+	require.False(t, r.Present())
+	require.Empty(t, r.Path)
+}
+
+func TestCollectRetiredStorage_ArchivesThenRemoves(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "verification")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "state"), []byte("old"), 0o644))
+
+	fsManager, err := fsx.NewManager()
+	require.NoError(t, err)
+	m := &Manager{fsManager: fsManager, logger: testLogger(), blockNodeInputs: models.BlockNodeInputs{
+		Namespace:    "block-node",
+		Release:      "bn",
+		ChartVersion: "0.37.0",
+		Storage:      models.BlockNodeStorage{BasePath: base},
+	}}
+	found := []RetiredStorage{{Storage: RetiredOptionalStorages("0.37.0")[0], Path: dir, DirExists: true}}
+
+	err = m.CollectRetiredStorage(context.Background(), found, filepath.Join(dir, "keep"))
+	require.ErrorContains(t, err, "inside the retired verification storage directory")
+	require.DirExists(t, dir)
+
+	target := t.TempDir()
+	require.NoError(t, m.CollectRetiredStorage(context.Background(), found, target))
+	require.NoDirExists(t, dir)
+
+	entries, err := os.ReadDir(target)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	man, err := backup.ReadManifest(filepath.Join(target, entries[0].Name()))
+	require.NoError(t, err)
+	require.Equal(t, BackupKindRetiredStorage, man.Kind)
+	require.NotNil(t, man.Volume("verification"))
+}
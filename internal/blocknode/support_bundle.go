@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/support"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// podLogLineBytes is the smallest average block node log line we expect. Pod
+// logs are requested with MaxSourceBytes/podLogLineBytes tail lines so the API
+// server never streams much more than the bundle keeps.
+const podLogLineBytes = 64
+
+// supportCollector collects, for every block node recorded on the host, its
+// PodDiagnostics, the values its Helm release was deployed with and the logs
+// of its pods' containers (and of their previous instance after a restart).
+type supportCollector struct{}
+
+// NewSupportCollector returns the block node collector of support bundles.
+func NewSupportCollector() support.Collector { return supportCollector{} }
+
+func (supportCollector) Name() string { return "blocknode" }
+
+func (supportCollector) Collect(ctx context.Context, b *support.Bundle) error {
+	releases, err := state.ReadBlockNodeReleasesFromDisk()
+	if err != nil {
+		return err
+	}
+	if len(releases) == 0 {
+		return errorx.IllegalState.New("no block node is recorded in the state file")
+	}
+
+	for _, r := range releases {
+		m, err := NewManager(models.BlockNodeInputs{Release: r.Release, Namespace: r.Namespace})
+		if err != nil {
+			b.AddError(r.Release, "block node release "+r.Release, err)
+			continue
+		}
+		m.collectSupport(ctx, b)
+	}
+	return nil
+}
+
+// collectSupport adds the manager's release to the bundle, under a directory
+// named after it.
+func (m *Manager) collectSupport(ctx context.Context, b *support.Bundle) {
+	release := m.blockNodeInputs.Release
+	ns := m.blockNodeInputs.Namespace
+
+	diag := m.PodDiagnostics(ctx)
+	b.AddBytes(path.Join(release, "pod-diagnostics.txt"), "pod diagnostics of namespace "+ns,
+		[]byte(strings.Join(diag, "\n")+"\n"))
+
+	values, err := m.GetReleaseValues()
+	if err != nil {
+		b.AddError(path.Join(release, "helm-values.yaml"), "Helm release "+release, err)
+	} else {
+		b.AddYAML(path.Join(release, "helm-values.yaml"), "Helm release "+release, values)
+	}
+
+	pods, err := m.kubeClient.List(ctx, kube.KindPod, ns, kube.WaitOptions{LabelSelector: PodLabelSelector})
+	if err != nil {
+		b.AddError(path.Join(release, "logs"), "pods of namespace "+ns, err)
+		return
+	}
+	for i := range pods.Items {
+		m.collectPodLogs(ctx, b, &pods.Items[i])
+	}
+}
+
+// collectPodLogs adds the logs of every container of pod within the bundle's
+// time window.
+func (m *Manager) collectPodLogs(ctx context.Context, b *support.Bundle, pod *unstructured.Unstructured) {
+	release := m.blockNodeInputs.Release
+	restarts := map[string]int64{}
+	for _, statusKey := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _, _ := unstructured.NestedSlice(pod.Object, "status", statusKey)
+		for _, s := range statuses {
+			sm, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(sm, "name")
+			count, _, _ := unstructured.NestedInt64(sm, "restartCount")
+			restarts[name] = count
+		}
+	}
+
+	for _, specKey := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", specKey)
+		for _, c := range containers {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			container, _, _ := unstructured.NestedString(cm, "name")
+			opts := kube.PodLogOptions{
+				Container: container,
+				Since:     b.Since(),
+				TailLines: b.MaxSourceBytes() / podLogLineBytes,
+			}
+			name := path.Join(release, "logs", fmt.Sprintf("%s_%s.log", pod.GetName(), container))
+			m.addPodLog(ctx, b, pod, name, opts)
+
+			if restarts[container] > 0 {
+				opts.Previous = true
+				name = path.Join(release, "logs", fmt.Sprintf("%s_%s.previous.log", pod.GetName(), container))
+				m.addPodLog(ctx, b, pod, name, opts)
+			}
+		}
+	}
+}
+
+func (m *Manager) addPodLog(ctx context.Context, b *support.Bundle, pod *unstructured.Unstructured, name string, opts kube.PodLogOptions) {
+	source := fmt.Sprintf("pod %s/%s container %s", pod.GetNamespace(), pod.GetName(), opts.Container)
+	if opts.Previous {
+		source += " (previous)"
+	}
+	logs, err := m.kubeClient.PodLogs(ctx, pod.GetNamespace(), pod.GetName(), opts)
+	if err != nil {
+		b.AddError(name, source, err)
+		return
+	}
+	b.AddBytes(name, source, logs)
+}
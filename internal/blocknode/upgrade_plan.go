@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	oslib "os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// Kinds of ValueChange.
+const (
+	ValueAdded   = "+"
+	ValueRemoved = "-"
+	ValueChanged = "~"
+)
+
+// ValueChange is one leaf of the Helm values that an upgrade adds, removes or
+// changes. Path is dotted, with keys that contain a dot quoted
+// (service.annotations["metallb.io/address-pool"]); lists are compared whole.
+type ValueChange struct {
+	Kind string
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// PlannedMigration is a storage migration an upgrade runs.
+type PlannedMigration struct {
+	ID          string
+	Description string
+}
+
+// UpgradePlan is what a block node upgrade or reconfigure will change, as
+// worked out by PlanUpgrade without touching the cluster.
+type UpgradePlan struct {
+	Release     string
+	FromVersion string
+	ToVersion   string
+	// Values are the differences between the values the release was deployed
+	// with and the values the upgrade will deploy it with.
+	Values     []ValueChange
+	Migrations []PlannedMigration
+	// DeleteStatefulSet is set when a migration changes the mounted volumes:
+	// the StatefulSet is deleted (orphan cascade) and re-created by the upgrade.
+	DeleteStatefulSet bool
+	// Restart says why the block node pod is restarted; empty when it only is
+	// if Helm changes the pod template.
+	Restart     string
+	Destructive []string
+}
+
+// PlanUpgrade works out what upgrading the deployed release to the manager's
+// inputs changes: the chart version delta, a diff of the values ComputeValuesFile
+// renders against those of the deployed release, the storage migrations that
+// apply and the steps that delete state. rolloutRestart is set when the caller
+// restarts the pod after the upgrade (reconfigure without --no-restart).
+//
+// Only the release is read from the cluster; the values file is rendered to the
+// local temp directory as the upgrade itself does.
+func (m *Manager) PlanUpgrade(profile, valuesFile string, rolloutRestart bool) (*UpgradePlan, error) {
+	installedVersion, err := m.GetInstalledVersion()
+	if err != nil {
+		return nil, errorx.IllegalState.Wrap(err, "failed to get installed version")
+	}
+	if installedVersion == "" {
+		return nil, errorx.IllegalState.New("block node release %q is not installed; nothing to plan", m.blockNodeInputs.Release).
+			WithProperty(models.ErrPropertyResolution, "use 'solo-provisioner block node install' to install the block node first")
+	}
+
+	_, migrations, err := m.applicableMigrations(installedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesFilePath, err := m.ComputeValuesFile(profile, valuesFile)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := oslib.ReadFile(valuesFilePath)
+	if err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to read computed values file")
+	}
+	desired := map[string]interface{}{}
+	if err := yaml.Unmarshal(raw, &desired); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to parse computed values file")
+	}
+
+	current, err := m.GetReleaseValues()
+	if err != nil {
+		return nil, err
+	}
+	// The migration path always upgrades with the rendered values alone; only
+	// the plain upgrade honours --reuse-values, where Helm keeps the deployed
+	// values the rendered ones do not set.
+	if m.blockNodeInputs.ReuseValues && len(migrations) == 0 {
+		desired = chartutil.MergeTables(desired, copyValues(current))
+	}
+
+	values, err := DiffValues(current, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &UpgradePlan{
+		Release:           m.blockNodeInputs.Release,
+		FromVersion:       installedVersion,
+		ToVersion:         m.blockNodeInputs.ChartVersion,
+		Values:            values,
+		DeleteStatefulSet: len(migrations) > 0,
+	}
+	for _, mg := range migrations {
+		plan.Migrations = append(plan.Migrations, PlannedMigration{ID: mg.ID(), Description: mg.Description()})
+	}
+
+	switch {
+	case m.blockNodeInputs.PurgeStorage:
+		plan.Destructive = append(plan.Destructive,
+			"delete all block data and the PVs/PVCs at the deployed storage paths, then re-create them at the configured paths")
+		plan.Restart = "storage purge scales the StatefulSet down and back up"
+	case m.blockNodeInputs.ResetStorage:
+		plan.Destructive = append(plan.Destructive,
+			"delete all block data at the deployed storage paths (PVs/PVCs are kept)")
+		plan.Restart = "storage reset scales the StatefulSet down and back up"
+	}
+	if plan.DeleteStatefulSet {
+		plan.Destructive = append(plan.Destructive,
+			"delete the StatefulSet (orphan cascade; PVCs and data are kept) so the upgrade re-creates it with the new volumes")
+		if plan.Restart == "" {
+			plan.Restart = "the StatefulSet is re-created"
+		}
+	}
+	plan.Destructive = append(plan.Destructive,
+		"delete the Helm-owned Services so the upgrade re-creates them (brief loss of the service address)")
+	if plan.Restart == "" && rolloutRestart {
+		plan.Restart = "rollout restart after the upgrade"
+	}
+
+	return plan, nil
+}
+
+// DiffValues returns the leaf-level differences between the current and the
+// desired values, sorted by path. Both sides are normalised through JSON first:
+// the deployed release's values come back from Helm's storage with every number
+// a float64, while freshly rendered YAML has ints.
+func DiffValues(current, desired map[string]interface{}) ([]ValueChange, error) {
+	cur, err := normaliseValues(current)
+	if err != nil {
+		return nil, err
+	}
+	des, err := normaliseValues(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	curLeaves := map[string]interface{}{}
+	flattenValues("", cur, curLeaves)
+	desLeaves := map[string]interface{}{}
+	flattenValues("", des, desLeaves)
+
+	var changes []ValueChange
+	for p, o := range curLeaves {
+		n, ok := desLeaves[p]
+		switch {
+		case !ok:
+			changes = append(changes, ValueChange{Kind: ValueRemoved, Path: p, Old: o})
+		case !reflect.DeepEqual(o, n):
+			changes = append(changes, ValueChange{Kind: ValueChanged, Path: p, Old: o, New: n})
+		}
+	}
+	for p, n := range desLeaves {
+		if _, ok := curLeaves[p]; !ok {
+			changes = append(changes, ValueChange{Kind: ValueAdded, Path: p, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// normaliseValues round-trips values through JSON so equal values compare
+// equal whatever decoder produced them.
+func normaliseValues(values map[string]interface{}) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	if len(values) == 0 {
+		return out, nil
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to encode Helm values")
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to decode Helm values")
+	}
+	return out, nil
+}
+
+// flattenValues records each leaf of values under its path. An empty map is a
+// leaf of its own so that adding or dropping it shows.
+func flattenValues(prefix string, values map[string]interface{}, leaves map[string]interface{}) {
+	for k, v := range values {
+		p := valuePath(prefix, k)
+		if sub, ok := v.(map[string]interface{}); ok && len(sub) > 0 {
+			flattenValues(p, sub, leaves)
+			continue
+		}
+		leaves[p] = v
+	}
+}
+
+func valuePath(prefix, key string) string {
+	if strings.Contains(key, ".") {
+		return prefix + `["` + key + `"]`
+	}
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// copyValues deep-copies values so merging into a copy leaves the release's
+// own map untouched.
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		if sub, ok := v.(map[string]interface{}); ok {
+			out[k] = copyValues(sub)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// WriteUpgradePlan renders the plan for the terminal.
+func WriteUpgradePlan(w io.Writer, plan *UpgradePlan) {
+	version := plan.FromVersion + " (unchanged)"
+	if plan.ToVersion != plan.FromVersion {
+		version = plan.FromVersion + " -> " + plan.ToVersion
+	}
+	_, _ = fmt.Fprintf(w, "Release:       %s\n", plan.Release)
+	_, _ = fmt.Fprintf(w, "Chart version: %s\n", version)
+
+	_, _ = fmt.Fprintf(w, "\nValues (%d change(s)):\n", len(plan.Values))
+	if len(plan.Values) == 0 {
+		_, _ = fmt.Fprintln(w, "  none")
+	}
+	for _, c := range plan.Values {
+		switch c.Kind {
+		case ValueAdded:
+			_, _ = fmt.Fprintf(w, "  + %s: %s\n", c.Path, formatValue(c.New))
+		case ValueRemoved:
+			_, _ = fmt.Fprintf(w, "  - %s: %s\n", c.Path, formatValue(c.Old))
+		default:
+			_, _ = fmt.Fprintf(w, "  ~ %s: %s -> %s\n", c.Path, formatValue(c.Old), formatValue(c.New))
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\nStorage migrations (%d):\n", len(plan.Migrations))
+	if len(plan.Migrations) == 0 {
+		_, _ = fmt.Fprintln(w, "  none")
+	}
+	for _, mg := range plan.Migrations {
+		_, _ = fmt.Fprintf(w, "  %s: %s\n", mg.ID, mg.Description)
+	}
+
+	deleteSts := "no"
+	if plan.DeleteStatefulSet {
+		deleteSts = "yes"
+	}
+	_, _ = fmt.Fprintf(w, "\nStatefulSet deleted: %s\n", deleteSts)
+	restart := "only if Helm changes the pod template"
+	if plan.Restart != "" {
+		restart = "yes, " + plan.Restart
+	}
+	_, _ = fmt.Fprintf(w, "Pod restart:         %s\n", restart)
+
+	_, _ = fmt.Fprintln(w, "\nDestructive steps:")
+	for _, d := range plan.Destructive {
+		_, _ = fmt.Fprintf(w, "  - %s\n", d)
+	}
+}
+
+// formatValue renders a leaf value as compact JSON, so strings are quoted and
+// lists stay on one line.
+func formatValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffValues(t *testing.T) {
+	// As decoded from the release: every number a float64.
+	current := map[string]interface{}{
+		"replicas": float64(1),
+		"blockNode": map[string]interface{}{
+			"config": map[string]interface{}{
+				"FILES_RECENT_BLOCK_RETENTION_THRESHOLD": "96000",
+				"OBSOLETE":                               "x",
+			},
+		},
+		"plugins": map[string]interface{}{"names": []interface{}{"a", "b"}},
+	}
+	// As rendered from YAML: ints.
+	desired := map[string]interface{}{
+		"replicas": 1,
+		"blockNode": map[string]interface{}{
+			"config": map[string]interface{}{
+				"FILES_RECENT_BLOCK_RETENTION_THRESHOLD": "48000",
+			},
+		},
+		"plugins": map[string]interface{}{"names": []interface{}{"a", "b", "c"}},
+		"service": map[string]interface{}{
+			"annotations": map[string]interface{}{"metallb.io/address-pool": "public"},
+		},
+	}
+
+	changes, err := DiffValues(current, desired)
+	require.NoError(t, err)
+	assert.Equal(t, []ValueChange{
+		{Kind: ValueChanged, Path: "blockNode.config.FILES_RECENT_BLOCK_RETENTION_THRESHOLD", Old: "96000", New: "48000"},
+		{Kind: ValueRemoved, Path: "blockNode.config.OBSOLETE", Old: "x"},
+		{Kind: ValueChanged, Path: "plugins.names", Old: []interface{}{"a", "b"}, New: []interface{}{"a", "b", "c"}},
+		{Kind: ValueAdded, Path: `service.annotations["metallb.io/address-pool"]`, New: "public"},
+	}, changes, "replicas is equal once normalised")
+}
+
+func TestDiffValues_NothingDeployed(t *testing.T) {
+	changes, err := DiffValues(nil, map[string]interface{}{"a": map[string]interface{}{}})
+	require.NoError(t, err)
+	assert.Equal(t, []ValueChange{{Kind: ValueAdded, Path: "a", New: map[string]interface{}{}}}, changes)
+}
+
+func TestWriteUpgradePlan(t *testing.T) {
+	var buf bytes.Buffer
+	WriteUpgradePlan(&buf, &UpgradePlan{
+		Release:     "block-node",
+		FromVersion: "0.36.2",
+		ToVersion:   "0.37.0",
+		Values: []ValueChange{
+			{Kind: ValueChanged, Path: "image.tag", Old: "0.36.2", New: "0.37.0"},
+		},
+		Migrations:        []PlannedMigration{{ID: "retire-verification", Description: "Retire the verification volume"}},
+		DeleteStatefulSet: true,
+		Restart:           "the StatefulSet is re-created",
+		Destructive:       []string{"delete the StatefulSet"},
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "Chart version: 0.36.2 -> 0.37.0")
+	assert.Contains(t, out, `~ image.tag: "0.36.2" -> "0.37.0"`)
+	assert.Contains(t, out, "retire-verification: Retire the verification volume")
+	assert.Contains(t, out, "StatefulSet deleted: yes")
+	assert.Contains(t, out, "Pod restart:         yes, the StatefulSet is re-created")
+	assert.Contains(t, out, "  - delete the StatefulSet")
+}
@@ -77,8 +77,19 @@ func (m *Manager) ComputeValuesFile(profile string, valuesFile string) (string,
 		return "", errorx.InternalError.Wrap(err, "failed to inject service annotations into values file")
 	}
 
-	// Write temporary copy to weaver's temp directory.
-	valuesFilePath := path.Join(models.Paths().TempDir, "block-node-values.yaml")
+	// Publish an additional instance on its own service port.
+	valuesContent, err = m.injectServicePort(valuesContent)
+	if err != nil {
+		return "", errorx.InternalError.Wrap(err, "failed to inject service port into values file")
+	}
+
+	// Write temporary copy to weaver's temp directory, one per instance so two
+	// block nodes on the host never render into the same file.
+	valuesFileName := "block-node-values.yaml"
+	if instance := models.BlockNodeInstance(m.blockNodeInputs.Release); instance != "" {
+		valuesFileName = "block-node-values-" + instance + ".yaml"
+	}
+	valuesFilePath := path.Join(models.Paths().TempDir, valuesFileName)
 	if err = oslib.WriteFile(valuesFilePath, valuesContent, models.DefaultFilePerm); err != nil {
 		return "", errorx.InternalError.Wrap(err, "failed to write block node values file")
 	}
@@ -108,9 +119,7 @@ func (m *Manager) renderDefaultValues(profile string) ([]byte, error) {
 		}
 	}
 
-	valuesTemplatePath := ValuesPath
 	if profile == models.ProfileLocal {
-		valuesTemplatePath = NanoValuesPath
 		logx.As().Info().
 			Bool("includeVerification", includeVerification).
 			Bool("includePlugins", includePlugins).
@@ -124,6 +133,25 @@ func (m *Manager) renderDefaultValues(profile string) ([]byte, error) {
 			Msg("Using full values configuration")
 	}
 
+	return renderValuesTemplate(profile, includeVerification, includePlugins, includeApplicationState)
+}
+
+// DefaultValues renders the built-in Helm values template of profile with
+// every optional storage section included, so that rendering the chart with
+// them names every container image an install of the profile can pull (see
+// internal/airgap).
+func DefaultValues(profile string) ([]byte, error) {
+	return renderValuesTemplate(profile, true, true, true)
+}
+
+// renderValuesTemplate renders the built-in Helm values template of profile:
+// the nano template for the local profile, the full one otherwise.
+func renderValuesTemplate(profile string, includeVerification, includePlugins, includeApplicationState bool) ([]byte, error) {
+	valuesTemplatePath := ValuesPath
+	if profile == models.ProfileLocal {
+		valuesTemplatePath = NanoValuesPath
+	}
+
 	rendered, err := templates.Render(valuesTemplatePath, struct {
 		IncludeVerification     bool
 		IncludePlugins          bool
@@ -542,10 +570,13 @@ func (m *Manager) injectServiceAnnotations(valuesContent []byte) ([]byte, error)
 		typeKey       = "type"
 		typeLB        = "LoadBalancer"
 		annotationKey = "metallb.io/address-pool"
-		defaultPool   = "public-address-pool"
 	)
 
 	lbEnabled := m.blockNodeInputs.LoadBalancerEnabled
+	pool := m.blockNodeInputs.LoadBalancerPool
+	if pool == "" {
+		pool = models.DefaultBlockNodeLoadBalancerPool
+	}
 	mutated := false
 
 	// Default the main service to LoadBalancer when the operator left the type unset. This
@@ -586,9 +617,9 @@ func (m *Manager) injectServiceAnnotations(valuesContent []byte) ([]byte, error)
 				Str(annotationKey, fmt.Sprintf("%v", existing)).
 				Msg("metallb.io/address-pool already set in values file; skipping injection")
 		} else {
-			annotations[annotationKey] = defaultPool
+			annotations[annotationKey] = pool
 			service["annotations"] = annotations
-			logx.As().Info().Msg("Injecting MetalLB address-pool annotation into service.annotations")
+			logx.As().Info().Str(annotationKey, pool).Msg("Injecting MetalLB address-pool annotation into service.annotations")
 			mutated = true
 		}
 	}
@@ -605,6 +636,42 @@ func (m *Manager) injectServiceAnnotations(valuesContent []byte) ([]byte, error)
 	return result, nil
 }
 
+// injectServicePort sets service.port to the resolved ServicePort when it is not
+// the chart's default, so an additional block node on the host is published on
+// a port of its own. The default port is left to the chart, keeping a
+// single-instance render unchanged, and an operator-set service.port wins.
+func (m *Manager) injectServicePort(valuesContent []byte) ([]byte, error) {
+	port := m.blockNodeInputs.ServicePort
+	if port == 0 || port == models.DefaultBlockNodeServicePort {
+		return valuesContent, nil
+	}
+
+	var vals map[string]interface{}
+	if err := yaml.Unmarshal(valuesContent, &vals); err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "failed to parse values YAML for service port injection")
+	}
+
+	service, ok := vals["service"].(map[string]interface{})
+	if !ok {
+		service = make(map[string]interface{})
+		vals["service"] = service
+	}
+	if existing, alreadySet := service["port"]; alreadySet {
+		logx.As().Debug().
+			Str("service.port", fmt.Sprintf("%v", existing)).
+			Msg("service.port already set in values file; skipping injection")
+		return valuesContent, nil
+	}
+	service["port"] = port
+	logx.As().Info().Int("service.port", port).Msg("Injecting block node service port into values")
+
+	result, err := yaml.Marshal(vals)
+	if err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to marshal values YAML after service port injection")
+	}
+	return result, nil
+}
+
 // chartOwnsLoadBalancer reports whether the merged values enable the block-node chart's own
 // loadBalancer block (loadBalancer.enabled: true). When enabled, the chart renders a dedicated
 // "-external" LoadBalancer Service and applies its annotations from .Values.loadBalancer.annotations,
@@ -32,6 +32,27 @@ type ComponentConfig struct {
 	// (components.block_node.statusz.poll_interval, already defaulted via
 	// StatuszConfig.EffectivePollInterval by the caller).
 	StatuszPollInterval time.Duration
+
+	// StorageSamplerEnabled turns on the storage sampler, which records the
+	// size of the volumes listed in StorageVolumesPath into StorageSamplesPath.
+	StorageSamplerEnabled bool
+	StorageVolumesPath    string
+	StorageSamplesPath    string
+
+	// ProbeEnabled turns on the probe monitor, which probes the target in
+	// ProbeTargetPath and appends the results to ProbeHistoryPath.
+	ProbeEnabled     bool
+	ProbeTargetPath  string
+	ProbeHistoryPath string
+
+	// Instance is the block node instance the component serves (empty for the
+	// default release); see models.BlockNodeInstance.
+	Instance string
+
+	// SharedEgressLink is set when another instance's traffic-shaper monitor
+	// already follows the host's egress link, so this one does not re-apply
+	// the same $EGRESS hierarchy a second time.
+	SharedEgressLink bool
 }
 
 // ComponentResult contains the monitors built by NewComponent and a reference
@@ -53,6 +74,13 @@ type ComponentResult struct {
 func NewComponent(cfg ComponentConfig) (ComponentResult, error) {
 	var monitors []daemonkit.MonitorRunner
 
+	if cfg.StorageSamplerEnabled {
+		monitors = append(monitors, NewStorageSamplerMonitor(cfg.StorageVolumesPath, cfg.StorageSamplesPath, 0))
+	}
+	if cfg.ProbeEnabled {
+		monitors = append(monitors, NewProbeMonitor(cfg.ProbeTargetPath, cfg.ProbeHistoryPath, cfg.Instance, 0))
+	}
+
 	var tsm *TrafficShaperMonitor
 	if cfg.TrafficShaperEnabled {
 		resolver, err := NewVethResolver(VethResolverConfig{
@@ -66,6 +94,10 @@ func NewComponent(cfg ComponentConfig) (ComponentResult, error) {
 			return ComponentResult{}, err
 		}
 		tsm = NewTrafficShaperMonitor(resolver, client, cfg.Namespace, cfg.StatuszBaseURL, cfg.StatuszPollInterval)
+		tsm.instance = cfg.Instance
+		if cfg.SharedEgressLink {
+			tsm.subscribeLinks = nil
+		}
 		monitors = append(monitors, tsm)
 	}
 
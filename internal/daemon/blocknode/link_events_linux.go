@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package blocknode
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"syscall"
+
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+)
+
+// rtmgrpLink is the RTMGRP_LINK multicast group bitmask: RTM_NEWLINK and
+// RTM_DELLINK notifications. Joining it needs no privilege, so the unprivileged
+// daemon can follow link churn directly.
+const rtmgrpLink = 0x1
+
+// ifInfomsg field offsets (struct ifinfomsg: family u8, pad u8, type u16,
+// index s32, flags u32, change u32).
+const (
+	ifInfomsgIndexOff = 4
+	ifInfomsgFlagsOff = 8
+)
+
+// subscribeLinkEvents opens an rtnetlink socket joined to RTMGRP_LINK and
+// streams its link notifications until ctx is cancelled or the socket fails,
+// then closes the channel. A kernel-side overrun (ENOBUFS) is surfaced as an
+// Overrun event so the watcher resyncs instead of silently missing a recreate.
+func subscribeLinkEvents(ctx context.Context) (<-chan linkEvent, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "open rtnetlink socket")
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: rtmgrpLink}); err != nil {
+		_ = syscall.Close(fd)
+		return nil, errorx.ExternalError.Wrap(err, "join rtnetlink link group")
+	}
+	// A 1 s receive timeout bounds how long the reader can sit in recvfrom
+	// after ctx is cancelled; closing the fd from another goroutine does not
+	// reliably wake a blocked recvfrom.
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &syscall.Timeval{Sec: 1}); err != nil {
+		_ = syscall.Close(fd)
+		return nil, errorx.ExternalError.Wrap(err, "set rtnetlink receive timeout")
+	}
+
+	out := make(chan linkEvent, 16)
+	go func() {
+		defer close(out)
+		defer func() { _ = syscall.Close(fd) }()
+
+		send := func(ev linkEvent) bool {
+			select {
+			case out <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		buf := make([]byte, 1<<16)
+		for ctx.Err() == nil {
+			n, _, err := syscall.Recvfrom(fd, buf, 0)
+			if err != nil {
+				switch {
+				case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.EINTR):
+					continue
+				case errors.Is(err, syscall.ENOBUFS):
+					if !send(linkEvent{Overrun: true}) {
+						return
+					}
+					continue
+				}
+				logx.As().Warn().Err(err).Msg("rtnetlink receive failed; closing link event stream")
+				return
+			}
+			events, err := parseLinkMessages(buf[:n])
+			if err != nil {
+				// A datagram we cannot parse may have carried the event we need;
+				// resync rather than drop it.
+				events = []linkEvent{{Overrun: true}}
+			}
+			for _, ev := range events {
+				if !send(ev) {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// parseLinkMessages decodes the RTM_NEWLINK/RTM_DELLINK messages in one
+// rtnetlink datagram. Other message types are skipped.
+func parseLinkMessages(buf []byte) ([]linkEvent, error) {
+	msgs, err := syscall.ParseNetlinkMessage(buf)
+	if err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "parse rtnetlink message")
+	}
+	var out []linkEvent
+	for i := range msgs {
+		msg := &msgs[i]
+		if msg.Header.Type != syscall.RTM_NEWLINK && msg.Header.Type != syscall.RTM_DELLINK {
+			continue
+		}
+		if len(msg.Data) < syscall.SizeofIfInfomsg {
+			continue
+		}
+		ev := linkEvent{
+			Index:   int(int32(binary.NativeEndian.Uint32(msg.Data[ifInfomsgIndexOff:]))),
+			Running: binary.NativeEndian.Uint32(msg.Data[ifInfomsgFlagsOff:])&syscall.IFF_RUNNING != 0,
+			Deleted: msg.Header.Type == syscall.RTM_DELLINK,
+		}
+		attrs, err := syscall.ParseNetlinkRouteAttr(msg)
+		if err != nil {
+			return nil, errorx.IllegalFormat.Wrap(err, "parse rtnetlink link attributes")
+		}
+		for _, a := range attrs {
+			if a.Attr.Type == syscall.IFLA_IFNAME {
+				ev.Name = strings.TrimRight(string(a.Value), "\x00")
+			}
+		}
+		if ev.Name != "" {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux && !integration
+
+package blocknode
+
+import (
+	"encoding/binary"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// linkMessage builds one rtnetlink link message carrying IFLA_IFNAME.
+func linkMessage(typ uint16, index int32, flags uint32, name string) []byte {
+	ifi := make([]byte, syscall.SizeofIfInfomsg)
+	ifi[0] = syscall.AF_UNSPEC
+	binary.NativeEndian.PutUint32(ifi[ifInfomsgIndexOff:], uint32(index))
+	binary.NativeEndian.PutUint32(ifi[ifInfomsgFlagsOff:], flags)
+
+	val := append([]byte(name), 0)
+	attrLen := syscall.SizeofRtAttr + len(val)
+	attr := make([]byte, (attrLen+3)&^3)
+	binary.NativeEndian.PutUint16(attr[0:], uint16(attrLen))
+	binary.NativeEndian.PutUint16(attr[2:], syscall.IFLA_IFNAME)
+	copy(attr[syscall.SizeofRtAttr:], val)
+
+	body := append(ifi, attr...)
+	msg := make([]byte, syscall.NLMSG_HDRLEN, syscall.NLMSG_HDRLEN+len(body))
+	binary.NativeEndian.PutUint32(msg[0:], uint32(syscall.NLMSG_HDRLEN+len(body)))
+	binary.NativeEndian.PutUint16(msg[4:], typ)
+	return append(msg, body...)
+}
+
+func TestParseLinkMessages(t *testing.T) {
+	buf := linkMessage(syscall.RTM_NEWLINK, 7, syscall.IFF_UP|syscall.IFF_RUNNING, "bond0")
+	buf = append(buf, linkMessage(syscall.RTM_NEWADDR, 7, 0, "ignored")...)
+	buf = append(buf, linkMessage(syscall.RTM_DELLINK, 4, syscall.IFF_UP, "eth0.100")...)
+
+	events, err := parseLinkMessages(buf)
+	require.NoError(t, err)
+	require.Equal(t, []linkEvent{
+		{Name: "bond0", Index: 7, Running: true},
+		{Name: "eth0.100", Index: 4, Deleted: true},
+	}, events)
+}
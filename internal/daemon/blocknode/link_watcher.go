@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+)
+
+// bandwidthShaperScriptPath mirrors shape.TcEgressScriptPath; duplicated (not
+// imported) to keep the daemon's import closure free of the provisioning
+// packages. Its NIC= line is the egress interface the HTB hierarchy was last
+// applied to — the interface the link watcher follows.
+const bandwidthShaperScriptPath = "/usr/local/sbin/solo-provisioner-bandwidth-shaper.sh"
+
+// sysClassNet is the sysfs directory the link watcher reads link speeds from.
+const sysClassNet = "/sys/class/net"
+
+// linkSettleDelay is how long the link watcher waits after the last relevant
+// event before re-applying. systemd-networkd builds a bond, VLAN or bridge in
+// several steps (create, enslave, up, carrier), each emitting RTM_NEWLINK; the
+// settle window collapses the burst into one re-apply against the finished
+// device. A var (not const) so tests can shrink it.
+var linkSettleDelay = 2 * time.Second
+
+// linkEvent is one rtnetlink link notification, reduced to what the egress
+// link watcher needs.
+type linkEvent struct {
+	Name    string
+	Index   int
+	Running bool // IFF_RUNNING: the link has carrier
+	Deleted bool // RTM_DELLINK
+	// Overrun is set (and every other field left zero) when the kernel dropped
+	// notifications because the socket buffer filled; the watcher resyncs from a
+	// fresh snapshot rather than trusting its incremental state.
+	Overrun bool
+}
+
+// linkState is the link watcher's view of the egress interface. A zero index
+// means the interface does not exist; a zero speed means it is unknown (link
+// down, or a virtual device that reports -1).
+type linkState struct {
+	index   int
+	running bool
+	speed   int
+}
+
+// runEgressLinkWatcher is the egress link-watcher responsibility. It follows
+// the egress interface recorded in the bandwidth-shaper boot script over
+// rtnetlink and, whenever that interface appears, is recreated (new ifindex) or
+// reports a new link speed, delegates `network shape reapply` to re-attach the
+// $EGRESS HTB hierarchy and re-resolve an "auto" trunk rate. This is what makes
+// egress shaping survive interface churn: a netplan bond, VLAN or bridge that
+// systemd-networkd creates after bandwidth-shaper.service has already failed, a
+// `netplan apply` that recreates the device, or a driver reload.
+//
+// An initial re-apply runs on entry when the interface exists, covering a boot
+// whose replay unit failed before the daemon started. The reapply worker is
+// idempotent — it leaves an intact hierarchy at an unchanged rate alone — so the
+// entry pass and spurious events cost one exec and no qdisc churn. A failed
+// re-apply or a closed event stream returns an error so the supervisor restarts
+// the watcher with back-off; the restart's entry pass is the retry.
+func (m *TrafficShaperMonitor) runEgressLinkWatcher(ctx context.Context) error {
+	if m.subscribeLinks == nil {
+		// Unconfigured (unit-test scaffolding only — NewTrafficShaperMonitor
+		// always wires the rtnetlink subscription). Block until shutdown.
+		<-ctx.Done()
+		return nil
+	}
+
+	// Subscribe before taking the snapshot so no event between the two is lost.
+	events, err := m.subscribeLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	nic := m.recordedEgressNIC()
+	state := m.snapshotLink(nic)
+
+	logx.As().Info().
+		Str("reason", "TrafficShaperLinkWatcherStarted").
+		Str("monitor", m.Name()).
+		Str("egress_iface", nic).
+		Msg("egress link watcher started")
+
+	var settle <-chan time.Time
+	var pending string
+	arm := func(why string) {
+		pending = why
+		settle = time.After(linkSettleDelay)
+	}
+	if state.index != 0 {
+		arm("startup")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case ev, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return errorx.ExternalError.New("rtnetlink link event stream closed")
+			}
+			// Re-read the recorded interface on every event: a reconfigure may
+			// have re-rendered the boot script for a different NIC.
+			if current := m.recordedEgressNIC(); current != nic {
+				nic, state = current, linkState{}
+			}
+			if nic == "" {
+				continue
+			}
+			if ev.Overrun {
+				ev = m.linkEventFromSnapshot(nic)
+			} else if ev.Name != nic {
+				continue
+			}
+			if why := m.observeLink(nic, &state, ev); why != "" {
+				arm(why)
+			}
+
+		case <-settle:
+			settle = nil
+			if nic == "" {
+				continue
+			}
+			logx.As().Info().
+				Str("reason", "TrafficShaperEgressReapply").
+				Str("monitor", m.Name()).
+				Str("egress_iface", nic).
+				Str("trigger", pending).
+				Msg("re-applying egress shaping after link change")
+			if err := m.delegator.ShapeReapplyEgress(ctx, nic); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
+
+// observeLink folds ev into state and returns why a re-apply is due, or "" when
+// the event changes nothing shaping depends on (an unrelated flag flip, a
+// carrier flap at the same speed).
+func (m *TrafficShaperMonitor) observeLink(nic string, state *linkState, ev linkEvent) string {
+	if ev.Deleted {
+		if state.index != 0 {
+			logx.As().Warn().
+				Str("reason", "TrafficShaperEgressLinkRemoved").
+				Str("monitor", m.Name()).
+				Str("egress_iface", nic).
+				Msg("egress interface removed — shaping resumes when it reappears")
+		}
+		*state = linkState{}
+		return ""
+	}
+
+	speed := 0
+	if ev.Running {
+		speed = m.linkSpeed(nic)
+	}
+	prev := *state
+	state.index, state.running = ev.Index, ev.Running
+	if speed > 0 {
+		state.speed = speed
+	}
+
+	switch {
+	case prev.index == 0:
+		return "appeared"
+	case prev.index != ev.Index:
+		return "recreated"
+	case speed > 0 && speed != prev.speed:
+		return "speed " + strconv.Itoa(prev.speed) + " -> " + strconv.Itoa(speed) + " Mbit/s"
+	}
+	return ""
+}
+
+// recordedEgressNIC returns the NIC= value of the rendered boot script, or ""
+// when no script exists (egress shaping not configured) or it cannot be parsed.
+func (m *TrafficShaperMonitor) recordedEgressNIC() string {
+	path := m.egressScriptPath
+	if path == "" {
+		path = bandwidthShaperScriptPath
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, `NIC="`) && strings.HasSuffix(line, `"`) && len(line) > len(`NIC=""`) {
+			return strings.TrimSuffix(strings.TrimPrefix(line, `NIC="`), `"`)
+		}
+	}
+	return ""
+}
+
+// snapshotLink reads the current state of nic directly (not from events).
+func (m *TrafficShaperMonitor) snapshotLink(nic string) linkState {
+	ev := m.linkEventFromSnapshot(nic)
+	if ev.Deleted {
+		return linkState{}
+	}
+	s := linkState{index: ev.Index, running: ev.Running}
+	if ev.Running {
+		s.speed = m.linkSpeed(nic)
+	}
+	return s
+}
+
+// linkEventFromSnapshot synthesises the event that describes nic's current
+// state: a delete when it does not exist. Used to resync after an overrun.
+func (m *TrafficShaperMonitor) linkEventFromSnapshot(nic string) linkEvent {
+	if nic == "" {
+		return linkEvent{Deleted: true}
+	}
+	lookup := m.interfaceByName
+	if lookup == nil {
+		lookup = net.InterfaceByName
+	}
+	iface, err := lookup(nic)
+	if err != nil {
+		return linkEvent{Name: nic, Deleted: true}
+	}
+	return linkEvent{Name: nic, Index: iface.Index, Running: iface.Flags&net.FlagRunning != 0}
+}
+
+// linkSpeed reads /sys/class/net/<nic>/speed in Mbit/s, returning 0 when it is
+// unavailable or not positive. Mirrors shape.ReadLinkSpeedMbit, which the
+// reapply worker uses for the actual rate re-resolution; the watcher only needs
+// it to notice a change.
+func (m *TrafficShaperMonitor) linkSpeed(nic string) int {
+	dir := m.sysClassNet
+	if dir == "" {
+		dir = sysClassNet
+	}
+	if strings.ContainsRune(nic, '/') {
+		return 0
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, nic, "speed"))
+	if err != nil {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
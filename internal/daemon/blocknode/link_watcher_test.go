@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package blocknode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// reapplyFakeDelegator records ShapeReapplyEgress calls; every other method is
+// inherited from fakeDelegator.
+type reapplyFakeDelegator struct {
+	fakeDelegator
+	mu        sync.Mutex
+	reapplied []string
+	err       error
+	called    chan struct{}
+}
+
+func (f *reapplyFakeDelegator) ShapeReapplyEgress(_ context.Context, nic string) error {
+	f.mu.Lock()
+	f.reapplied = append(f.reapplied, nic)
+	f.mu.Unlock()
+	f.called <- struct{}{}
+	return f.err
+}
+
+func (f *reapplyFakeDelegator) calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.reapplied...)
+}
+
+// linkHost is a fake host: a rendered boot script, a sysfs speed file per NIC
+// and a mutable interface table.
+type linkHost struct {
+	dir    string
+	mu     sync.Mutex
+	ifaces map[string]net.Interface
+}
+
+func newLinkHost(t *testing.T, nic string) *linkHost {
+	t.Helper()
+	h := &linkHost{dir: t.TempDir(), ifaces: map[string]net.Interface{}}
+	script := "#!/bin/sh\nset -e\n\nNIC=\"" + nic + "\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(h.dir, "bandwidth-shaper.sh"), []byte(script), 0o755))
+	return h
+}
+
+func (h *linkHost) setLink(t *testing.T, name string, index, speed int) {
+	t.Helper()
+	h.mu.Lock()
+	h.ifaces[name] = net.Interface{Name: name, Index: index, Flags: net.FlagUp | net.FlagRunning}
+	h.mu.Unlock()
+	require.NoError(t, os.MkdirAll(filepath.Join(h.dir, "net", name), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(h.dir, "net", name, "speed"), []byte(strconv.Itoa(speed)+"\n"), 0o644))
+}
+
+func (h *linkHost) lookup(name string) (*net.Interface, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i, ok := h.ifaces[name]
+	if !ok {
+		return nil, errors.New("no such network interface")
+	}
+	return &i, nil
+}
+
+func newLinkMonitor(h *linkHost, d *reapplyFakeDelegator, events chan linkEvent) *TrafficShaperMonitor {
+	return &TrafficShaperMonitor{
+		delegator: d,
+		subscribeLinks: func(context.Context) (<-chan linkEvent, error) {
+			return events, nil
+		},
+		egressScriptPath: filepath.Join(h.dir, "bandwidth-shaper.sh"),
+		sysClassNet:      filepath.Join(h.dir, "net"),
+		interfaceByName:  h.lookup,
+	}
+}
+
+func shrinkLinkSettle(t *testing.T) {
+	t.Helper()
+	prev := linkSettleDelay
+	linkSettleDelay = 10 * time.Millisecond
+	t.Cleanup(func() { linkSettleDelay = prev })
+}
+
+func waitReapply(t *testing.T, d *reapplyFakeDelegator) {
+	t.Helper()
+	select {
+	case <-d.called:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a reapply")
+	}
+}
+
+func TestEgressLinkWatcher_ReappliesWhenBondAppearsAfterBoot(t *testing.T) {
+	shrinkLinkSettle(t)
+	h := newLinkHost(t, "bond0")
+	d := &reapplyFakeDelegator{called: make(chan struct{}, 8)}
+	events := make(chan linkEvent, 8)
+	m := newLinkMonitor(h, d, events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.runEgressLinkWatcher(ctx) }()
+
+	// bond0 does not exist yet, so there is no entry pass. networkd then builds
+	// it in several steps; the burst collapses into one reapply.
+	h.setLink(t, "bond0", 7, 10000)
+	events <- linkEvent{Name: "eth1", Index: 3, Running: true}
+	events <- linkEvent{Name: "bond0", Index: 7}
+	events <- linkEvent{Name: "bond0", Index: 7, Running: true}
+	waitReapply(t, d)
+
+	// A carrier flap at the same speed is not a reason to touch shaping.
+	events <- linkEvent{Name: "bond0", Index: 7}
+	events <- linkEvent{Name: "bond0", Index: 7, Running: true}
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, []string{"bond0"}, d.calls())
+}
+
+func TestEgressLinkWatcher_EntryPassAndRecreate(t *testing.T) {
+	shrinkLinkSettle(t)
+	h := newLinkHost(t, "bond0")
+	h.setLink(t, "bond0", 7, 1000)
+	d := &reapplyFakeDelegator{called: make(chan struct{}, 8)}
+	events := make(chan linkEvent, 8)
+	m := newLinkMonitor(h, d, events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.runEgressLinkWatcher(ctx) }()
+	waitReapply(t, d) // entry pass: the interface already exists
+
+	// `netplan apply` deletes and recreates the bond under a new ifindex.
+	events <- linkEvent{Name: "bond0", Index: 7, Deleted: true}
+	h.setLink(t, "bond0", 9, 1000)
+	events <- linkEvent{Name: "bond0", Index: 9, Running: true}
+	waitReapply(t, d)
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, []string{"bond0", "bond0"}, d.calls())
+}
+
+func TestObserveLink_SpeedChangeAndOverrunResync(t *testing.T) {
+	h := newLinkHost(t, "eth0")
+	h.setLink(t, "eth0", 2, 1000)
+	m := newLinkMonitor(h, &reapplyFakeDelegator{}, nil)
+
+	state := m.snapshotLink("eth0")
+	require.Equal(t, linkState{index: 2, running: true, speed: 1000}, state)
+
+	require.Empty(t, m.observeLink("eth0", &state, linkEvent{Name: "eth0", Index: 2, Running: true}))
+
+	h.setLink(t, "eth0", 2, 10000)
+	require.Equal(t, "speed 1000 -> 10000 Mbit/s",
+		m.observeLink("eth0", &state, linkEvent{Name: "eth0", Index: 2, Running: true}))
+
+	// An overrun resync synthesises the current state; a recreate that was
+	// dropped by the kernel is still noticed.
+	h.setLink(t, "eth0", 5, 10000)
+	require.Equal(t, "recreated", m.observeLink("eth0", &state, m.linkEventFromSnapshot("eth0")))
+}
+
+func TestEgressLinkWatcher_FailedReapplyFaultsForRetry(t *testing.T) {
+	shrinkLinkSettle(t)
+	h := newLinkHost(t, "eth0")
+	h.setLink(t, "eth0", 2, 1000)
+	d := &reapplyFakeDelegator{called: make(chan struct{}, 8), err: errors.New("sudo: a password is required")}
+	m := newLinkMonitor(h, d, make(chan linkEvent))
+
+	err := m.runEgressLinkWatcher(context.Background())
+	require.ErrorContains(t, err, "password is required")
+}
+
+func TestEgressLinkWatcher_IdleWithoutRecordedScript(t *testing.T) {
+	shrinkLinkSettle(t)
+	h := newLinkHost(t, "eth0")
+	require.NoError(t, os.Remove(filepath.Join(h.dir, "bandwidth-shaper.sh")))
+	h.setLink(t, "eth0", 2, 1000)
+	d := &reapplyFakeDelegator{called: make(chan struct{}, 8)}
+	events := make(chan linkEvent, 1)
+	m := newLinkMonitor(h, d, events)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	events <- linkEvent{Name: "eth0", Index: 2, Running: true}
+	require.NoError(t, m.runEgressLinkWatcher(ctx))
+	require.Empty(t, d.calls(), "no boot script means egress shaping is not configured")
+}
+
+func TestEgressLinkWatcher_ClosedStreamFaults(t *testing.T) {
+	h := newLinkHost(t, "eth0")
+	events := make(chan linkEvent)
+	close(events)
+	m := newLinkMonitor(h, &reapplyFakeDelegator{}, events)
+	require.ErrorContains(t, m.runEgressLinkWatcher(context.Background()), "stream closed")
+}
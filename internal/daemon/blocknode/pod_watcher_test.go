@@ -14,6 +14,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hashgraph/solo-weaver/internal/daemon/privexec"
 )
 
 // fakeResolver returns queued (veth, err) results in order, repeating the last
@@ -58,10 +60,15 @@ func (f *fakeDelegator) TCDetach(_ context.Context, veth string) error {
 	f.detached = append(f.detached, veth)
 	return nil
 }
-func (f *fakeDelegator) ReconcileShaper(context.Context, string) error { return nil }
-func (f *fakeDelegator) ReconcileShaperCheck(context.Context, string) (string, error) {
+func (f *fakeDelegator) ReconcileShaper(context.Context, string, string) error { return nil }
+func (f *fakeDelegator) ShapeReapplyEgress(context.Context, string) error      { return nil }
+func (f *fakeDelegator) ReconcileShaperCheck(context.Context, string, string) (string, error) {
 	return "", nil
 }
+func (f *fakeDelegator) ClusterBackup(context.Context, string, int, time.Duration) error { return nil }
+func (f *fakeDelegator) SoftwareVerify(context.Context) ([]privexec.SoftwareVerifyFailure, error) {
+	return nil, nil
+}
 
 func newTestMonitor(r vethResolver, d *fakeDelegator) *TrafficShaperMonitor {
 	return &TrafficShaperMonitor{
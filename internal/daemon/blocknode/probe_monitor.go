@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"time"
+
+	"github.com/automa-saga/logx"
+
+	"github.com/hashgraph/solo-weaver/internal/blocknode/probe"
+)
+
+// defaultProbeInterval is the cadence of the probe monitor: often enough that
+// the history pins an outage to within minutes, rare enough that its calls
+// are noise next to real clients.
+const defaultProbeInterval = 5 * time.Minute
+
+// monitorProbeSamples is the sample count of a scheduled probe. Fewer than an
+// operator's on-demand probe, since the history supplies the rest.
+const monitorProbeSamples = 3
+
+// ProbeMonitor is the daemonkit.MonitorRunner that probes the block node's
+// external endpoint on a schedule and appends each result to the history
+// `block node probe --history` shows. The target is read from the file the CLI
+// writes whenever it resolves the LoadBalancer endpoint (install, upgrade,
+// reconfigure, `block node probe`), so a new IP or port is picked up on the
+// next tick without a daemon restart. Until that file exists the monitor
+// idles.
+type ProbeMonitor struct {
+	targetPath string
+	store      probe.Store
+	interval   time.Duration
+	instance   string
+	opts       probe.Options
+}
+
+// NewProbeMonitor creates a probe monitor reading its target from targetPath
+// and appending results to historyPath. A non-positive interval falls back to
+// defaultProbeInterval.
+func NewProbeMonitor(targetPath, historyPath, instance string, interval time.Duration) *ProbeMonitor {
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	return &ProbeMonitor{
+		targetPath: targetPath,
+		store:      probe.Store{Path: historyPath},
+		interval:   interval,
+		instance:   instance,
+		opts:       probe.Options{Samples: monitorProbeSamples},
+	}
+}
+
+// Name implements daemonkit.MonitorRunner.
+func (m *ProbeMonitor) Name() string { return "bn-probe-monitor" }
+
+// Run implements daemonkit.MonitorRunner. It probes once on entry and then
+// every interval until ctx is cancelled. A failed probe is logged and retried
+// on the next tick, so Run only returns on cancellation.
+func (m *ProbeMonitor) Run(ctx context.Context) error {
+	logx.As().Info().
+		Str("reason", "ProbeMonitorStarting").
+		Str("monitor", m.Name()).
+		Dur("interval", m.interval).
+		Msg("block-node probe monitor starting")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		if err := m.probe(ctx); err != nil && ctx.Err() == nil {
+			logx.As().Warn().Err(err).Str("monitor", m.Name()).Msg("Failed to probe the block node")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe checks the recorded target and appends the result. A block node that
+// fails the probe is logged, not returned: the result is what gets recorded.
+func (m *ProbeMonitor) probe(ctx context.Context) error {
+	target, err := probe.ReadTarget(m.targetPath)
+	if err != nil || target == nil {
+		return err
+	}
+	res, err := probe.Run(ctx, *target, m.opts)
+	if err != nil {
+		return err
+	}
+	res.Instance = m.instance
+	if res.Verdict != probe.VerdictHealthy {
+		logx.As().Warn().
+			Str("monitor", m.Name()).
+			Str("target", res.Target).
+			Str("verdict", string(res.Verdict)).
+			Strs("reasons", res.Reasons).
+			Msg("Block node probe did not pass")
+	}
+	return m.store.Append(res, res.Time)
+}
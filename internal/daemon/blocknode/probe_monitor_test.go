@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/blocknode/probe"
+)
+
+func TestProbeMonitor_IdlesWithoutTarget(t *testing.T) {
+	dir := t.TempDir()
+	m := NewProbeMonitor(filepath.Join(dir, "target.json"), filepath.Join(dir, "history.jsonl"), "", 0)
+	require.Equal(t, defaultProbeInterval, m.interval)
+
+	require.NoError(t, m.probe(context.Background()))
+	require.NoFileExists(t, filepath.Join(dir, "history.jsonl"))
+}
+
+func TestProbeMonitor_RecordsResults(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+
+	dir := t.TempDir()
+	targetPath := filepath.Join(dir, "target.json")
+	require.NoError(t, probe.WriteTarget(targetPath, probe.Target{Address: "127.0.0.1", ServicePort: port}))
+
+	m := NewProbeMonitor(targetPath, filepath.Join(dir, "history.jsonl"), "testnet", time.Hour)
+	m.opts = probe.Options{Samples: 1, Timeout: time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		results, err := m.store.Read()
+		return err == nil && len(results) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	results, err := m.store.Read()
+	require.NoError(t, err)
+	require.Equal(t, probe.VerdictRefused, results[0].Verdict)
+	require.Equal(t, "testnet", results[0].Instance)
+}
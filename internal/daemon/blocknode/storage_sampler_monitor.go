@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"time"
+
+	"github.com/automa-saga/logx"
+
+	"github.com/hashgraph/solo-weaver/internal/blocknode/forecast"
+)
+
+// defaultStorageSampleInterval is the cadence of the storage sampler. Block
+// node storage grows by gigabytes a day, so hourly samples give
+// `block node storage forecast` a growth rate within a few hours of install
+// at negligible cost.
+const defaultStorageSampleInterval = time.Hour
+
+// StorageSamplerMonitor is the daemonkit.MonitorRunner that records the
+// on-disk size of the block node's storage volumes. The volumes are read from
+// the file the CLI writes whenever it resolves the storage layout (install,
+// reconfigure, upgrade, `block node storage forecast`), so a path change is
+// picked up on the next tick without a daemon restart. Until that file exists
+// the monitor idles.
+type StorageSamplerMonitor struct {
+	volumesPath string
+	store       forecast.Store
+	interval    time.Duration
+	now         func() time.Time
+}
+
+// NewStorageSamplerMonitor creates a sampler reading volumes from volumesPath
+// and appending samples to samplesPath. A non-positive interval falls back to
+// defaultStorageSampleInterval.
+func NewStorageSamplerMonitor(volumesPath, samplesPath string, interval time.Duration) *StorageSamplerMonitor {
+	if interval <= 0 {
+		interval = defaultStorageSampleInterval
+	}
+	return &StorageSamplerMonitor{
+		volumesPath: volumesPath,
+		store:       forecast.Store{Path: samplesPath},
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// Name implements daemonkit.MonitorRunner.
+func (m *StorageSamplerMonitor) Name() string { return "bn-storage-sampler-monitor" }
+
+// Run implements daemonkit.MonitorRunner. It samples once on entry and then
+// every interval until ctx is cancelled. A failed sample is logged and retried
+// on the next tick, so Run only returns on cancellation.
+func (m *StorageSamplerMonitor) Run(ctx context.Context) error {
+	logx.As().Info().
+		Str("reason", "StorageSamplerMonitorStarting").
+		Str("monitor", m.Name()).
+		Dur("interval", m.interval).
+		Msg("block-node storage sampler monitor starting")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		if err := m.sample(); err != nil {
+			logx.As().Warn().Err(err).Str("monitor", m.Name()).Msg("Failed to sample block node storage")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample measures every registered volume and appends the result.
+func (m *StorageSamplerMonitor) sample() error {
+	vols, err := forecast.ReadVolumes(m.volumesPath)
+	if err != nil || len(vols) == 0 {
+		return err
+	}
+	now := m.now()
+	samples, err := forecast.Measure(vols, now)
+	if err != nil {
+		return err
+	}
+	return m.store.Append(samples, now)
+}
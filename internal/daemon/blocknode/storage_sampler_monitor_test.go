@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocknode
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/blocknode/forecast"
+)
+
+func TestStorageSamplerMonitor_IdlesWithoutVolumes(t *testing.T) {
+	dir := t.TempDir()
+	m := NewStorageSamplerMonitor(filepath.Join(dir, "volumes.json"), filepath.Join(dir, "samples.jsonl"), 0)
+	require.Equal(t, defaultStorageSampleInterval, m.interval)
+
+	require.NoError(t, m.sample())
+	require.NoFileExists(t, filepath.Join(dir, "samples.jsonl"))
+}
+
+func TestStorageSamplerMonitor_RecordsSamples(t *testing.T) {
+	dir := t.TempDir()
+	live := filepath.Join(dir, "live")
+	require.NoError(t, os.MkdirAll(live, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(live, "1.blk.zstd"), make([]byte, 42), 0o644))
+	volumesPath := filepath.Join(dir, "volumes.json")
+	require.NoError(t, forecast.WriteVolumes(volumesPath, []forecast.Volume{{Name: "live", Path: live}}))
+
+	m := NewStorageSamplerMonitor(volumesPath, filepath.Join(dir, "samples.jsonl"), time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		samples, err := m.store.Read()
+		return err == nil && len(samples) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	samples, err := m.store.Read()
+	require.NoError(t, err)
+	require.Equal(t, "live", samples[0].Volume)
+	require.EqualValues(t, 42, samples[0].Bytes)
+	require.Equal(t, 1, samples[0].Files)
+}
@@ -4,6 +4,7 @@ package blocknode
 
 import (
 	"context"
+	"net"
 	"sync"
 	"time"
 
@@ -31,18 +32,21 @@ var (
 const responsibilityBackoffFactor = 2.0
 
 // TrafficShaperMonitor is the daemonkit.MonitorRunner for the block-node
-// traffic-shaper workflow. It owns two long-lived responsibilities that run
+// traffic-shaper workflow. It owns three long-lived responsibilities that run
 // concurrently under Run:
 //
 //   - the pod-lifecycle watcher (resolves host-side veths and installs/rebinds
-//     ingress HTB qdiscs — implemented in #748/#749), and
+//     ingress HTB qdiscs — implemented in #748/#749),
 //   - the statusz poll loop, which reconciles the nft policy membership from
 //     statusz. Its reconcile logic lives in the `block node reconcile-shaper`
 //     CLI worker; this loop is the daemon-side scheduler that execs that worker
-//     once per poll tick (see runStatuszPoll).
+//     once per poll tick (see runStatuszPoll), and
+//   - the egress link watcher, which re-attaches the $EGRESS HTB hierarchy via
+//     `network shape reapply` when the egress interface appears, is recreated
+//     or changes speed (see runEgressLinkWatcher).
 //
 // Each responsibility is independently retried with exponential back-off so a
-// fault in one cannot stop the other or crash the daemon.
+// fault in one cannot stop the others or crash the daemon.
 type TrafficShaperMonitor struct {
 	// resolver resolves the host-side veth name for a BN pod (story #747). Held
 	// as an interface so the pod watcher can be unit-tested with a fake.
@@ -60,6 +64,10 @@ type TrafficShaperMonitor struct {
 	// namespace is the BN orbit namespace the pod watcher scopes its list/watch
 	// to.
 	namespace string
+	// instance is the block node instance the monitor shapes (empty for the
+	// default release). It selects the instance's policy sets in the
+	// reconcile-shaper worker the poll loop execs.
+	instance string
 
 	// statuszURL is the operator-configured statusz base URL
 	// (components.block_node.statusz.base_url). When set it is an explicit
@@ -104,6 +112,17 @@ type TrafficShaperMonitor struct {
 	// exists to close. Extra wake-ups are harmless — the digest gate skips the
 	// privileged apply when the desired state has not changed.
 	urlChanged chan struct{}
+
+	// subscribeLinks opens the rtnetlink link-event stream the egress link
+	// watcher follows. Nil only in unit tests that exercise the other
+	// responsibilities (runEgressLinkWatcher degrades to an idle block).
+	subscribeLinks func(ctx context.Context) (<-chan linkEvent, error)
+	// egressScriptPath, sysClassNet and interfaceByName are the link watcher's
+	// host-read seams; empty/nil means the production path (see
+	// link_watcher.go).
+	egressScriptPath string
+	sysClassNet      string
+	interfaceByName  func(name string) (*net.Interface, error)
 }
 
 // NewTrafficShaperMonitor constructs a TrafficShaperMonitor. resolver and client
@@ -123,6 +142,8 @@ func NewTrafficShaperMonitor(resolver *VethResolver, client kubernetes.Interface
 		attached:     make(map[types.UID]string),
 		inflight:     make(map[types.UID]bool),
 		urlChanged:   make(chan struct{}, 1),
+
+		subscribeLinks: subscribeLinkEvents,
 	}
 }
 
@@ -149,8 +170,9 @@ func (m *TrafficShaperMonitor) signalURLChanged() {
 // Name implements daemonkit.MonitorRunner.
 func (m *TrafficShaperMonitor) Name() string { return "bn-traffic-shaper-monitor" }
 
-// Run implements daemonkit.MonitorRunner. It starts the pod-lifecycle watcher
-// and the statusz poll loop concurrently and blocks until ctx is cancelled. It
+// Run implements daemonkit.MonitorRunner. It starts the pod-lifecycle watcher,
+// the statusz poll loop and the egress link watcher concurrently and blocks
+// until ctx is cancelled. It
 // always returns nil: subsystem faults are absorbed by superviseResponsibility,
 // so the only way Run returns is a clean ctx cancellation.
 func (m *TrafficShaperMonitor) Run(ctx context.Context) error {
@@ -160,7 +182,7 @@ func (m *TrafficShaperMonitor) Run(ctx context.Context) error {
 		Msg("block-node traffic-shaper monitor starting")
 
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(3)
 	go func() {
 		defer wg.Done()
 		m.superviseResponsibility(ctx, "pod-watcher", m.runPodWatcher)
@@ -169,13 +191,17 @@ func (m *TrafficShaperMonitor) Run(ctx context.Context) error {
 		defer wg.Done()
 		m.superviseResponsibility(ctx, "statusz-poll", m.runStatuszPoll)
 	}()
+	go func() {
+		defer wg.Done()
+		m.superviseResponsibility(ctx, "egress-link-watcher", m.runEgressLinkWatcher)
+	}()
 	wg.Wait()
 	return nil
 }
 
 // superviseResponsibility runs fn in a retry loop. A non-nil error from fn is
 // logged and retried after an exponential back-off (5 s → 5 min); the error is
-// never returned, so one responsibility faulting cannot stop the other or crash
+// never returned, so one responsibility faulting cannot stop the others or crash
 // the monitor. The back-off resets after fn runs without error. The loop exits
 // only when ctx is cancelled.
 func (m *TrafficShaperMonitor) superviseResponsibility(ctx context.Context, name string, fn func(context.Context) error) {
@@ -319,7 +345,7 @@ func (m *TrafficShaperMonitor) runStatuszPoll(ctx context.Context) error {
 			Str("statusz_url", statuszURL).
 			Msg("polling statusz")
 
-		digest, err := m.delegator.ReconcileShaperCheck(ctx, statuszURL)
+		digest, err := m.delegator.ReconcileShaperCheck(ctx, statuszURL, m.instance)
 		if err != nil {
 			return err
 		}
@@ -339,7 +365,7 @@ func (m *TrafficShaperMonitor) runStatuszPoll(ctx context.Context) error {
 			Str("monitor", m.Name()).
 			Str("statusz_url", statuszURL).
 			Msg("applying nft policy membership from statusz")
-		if err := m.delegator.ReconcileShaper(ctx, statuszURL); err != nil {
+		if err := m.delegator.ReconcileShaper(ctx, statuszURL, m.instance); err != nil {
 			return err
 		}
 		lastDigest = digest
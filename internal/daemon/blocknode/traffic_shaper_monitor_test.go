@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/daemon/privexec"
 )
 
 // pollFakeDelegator is a thread-safe Delegator fake for the statusz poll-loop
@@ -40,8 +42,15 @@ func (f *pollFakeDelegator) Run(context.Context, ...string) ([]byte, error)
 func (f *pollFakeDelegator) NetworkPolicySet(context.Context, string, []string) error { return nil }
 func (f *pollFakeDelegator) TCAttach(context.Context, string) error                   { return nil }
 func (f *pollFakeDelegator) TCDetach(context.Context, string) error                   { return nil }
+func (f *pollFakeDelegator) ShapeReapplyEgress(context.Context, string) error         { return nil }
+func (f *pollFakeDelegator) ClusterBackup(context.Context, string, int, time.Duration) error {
+	return nil
+}
+func (f *pollFakeDelegator) SoftwareVerify(context.Context) ([]privexec.SoftwareVerifyFailure, error) {
+	return nil, nil
+}
 
-func (f *pollFakeDelegator) ReconcileShaperCheck(ctx context.Context, url string) (string, error) {
+func (f *pollFakeDelegator) ReconcileShaperCheck(ctx context.Context, url, _ string) (string, error) {
 	n := f.checkCalls.Add(1)
 	if f.blockUntilCancel {
 		<-ctx.Done()
@@ -63,7 +72,7 @@ func (f *pollFakeDelegator) ReconcileShaperCheck(ctx context.Context, url string
 	return f.digests[idx], nil
 }
 
-func (f *pollFakeDelegator) ReconcileShaper(_ context.Context, url string) error {
+func (f *pollFakeDelegator) ReconcileShaper(_ context.Context, url, _ string) error {
 	f.applyCalls.Add(1)
 	f.mu.Lock()
 	f.lastURL = url
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cluster holds the daemon monitors of the host's kubeadm cluster
+// itself, as opposed to the workloads running on it.
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/automa-saga/logx"
+
+	"github.com/hashgraph/solo-weaver/internal/daemon/privexec"
+)
+
+// maxBackupCheckInterval caps how long the monitor waits between asking the
+// CLI whether a backup is due. The CLI's --if-older-than gate makes a check
+// that is not due cheap, so a daemon restarted midway through an interval
+// still backs up within an hour of the backup falling due.
+const maxBackupCheckInterval = time.Hour
+
+// BackupMonitor is the daemonkit.MonitorRunner that keeps the cluster's etcd
+// backed up on a schedule. The daemon is unprivileged and the backup target is
+// root-owned, so every check delegates `kube cluster backup --if-older-than
+// <interval>` under sudo and the CLI decides whether the newest backup is old
+// enough to need a new one; retention is applied with each backup it takes.
+type BackupMonitor struct {
+	delegator privexec.Delegator
+	target    string
+	keep      int
+	interval  time.Duration
+	check     time.Duration
+}
+
+// NewBackupMonitor creates a monitor that backs the cluster up into target
+// every interval, keeping the newest keep backups.
+func NewBackupMonitor(target string, keep int, interval time.Duration) *BackupMonitor {
+	return &BackupMonitor{
+		delegator: privexec.New(),
+		target:    target,
+		keep:      keep,
+		interval:  interval,
+		check:     min(interval, maxBackupCheckInterval),
+	}
+}
+
+// Name implements daemonkit.MonitorRunner.
+func (m *BackupMonitor) Name() string { return "cluster-backup-monitor" }
+
+// Run implements daemonkit.MonitorRunner. It checks once on entry and then
+// every check interval until ctx is cancelled. A failed backup is logged and
+// retried on the next tick, so Run only returns on cancellation.
+func (m *BackupMonitor) Run(ctx context.Context) error {
+	logx.As().Info().
+		Str("reason", "ClusterBackupMonitorStarting").
+		Str("monitor", m.Name()).
+		Str("target", m.target).
+		Dur("interval", m.interval).
+		Int("keep", m.keep).
+		Msg("cluster backup monitor starting")
+
+	ticker := time.NewTicker(m.check)
+	defer ticker.Stop()
+	for {
+		if err := m.delegator.ClusterBackup(ctx, m.target, m.keep, m.interval); err != nil && ctx.Err() == nil {
+			logx.As().Warn().Err(err).
+				Str("reason", "ClusterBackupFailed").
+				Str("monitor", m.Name()).
+				Str("target", m.target).
+				Msg("Scheduled cluster backup failed; retrying on the next check")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/daemon/privexec"
+)
+
+// backupFakeDelegator records the ClusterBackup delegations.
+type backupFakeDelegator struct {
+	privexec.Delegator
+
+	calls       atomic.Int32
+	target      string
+	keep        int
+	ifOlderThan time.Duration
+}
+
+func (f *backupFakeDelegator) ClusterBackup(_ context.Context, target string, keep int, ifOlderThan time.Duration) error {
+	f.target, f.keep, f.ifOlderThan = target, keep, ifOlderThan
+	f.calls.Add(1)
+	return errors.New("etcd is not running")
+}
+
+func TestNewBackupMonitor_CapsCheckInterval(t *testing.T) {
+	require.Equal(t, time.Hour, NewBackupMonitor("/backup", 7, 24*time.Hour).check)
+	require.Equal(t, 10*time.Minute, NewBackupMonitor("/backup", 7, 10*time.Minute).check)
+}
+
+func TestBackupMonitor_DelegatesEveryCheck(t *testing.T) {
+	fake := &backupFakeDelegator{}
+	m := NewBackupMonitor("/opt/solo/weaver/backup/cluster", 3, 6*time.Hour)
+	m.delegator = fake
+	m.check = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return fake.calls.Load() >= 2 }, 5*time.Second, 5*time.Millisecond,
+		"a failed backup is retried on the next check")
+	cancel()
+	require.NoError(t, <-done)
+
+	require.Equal(t, "/opt/solo/weaver/backup/cluster", fake.target)
+	require.Equal(t, 3, fake.keep)
+	require.Equal(t, 6*time.Hour, fake.ifOlderThan, "the CLI gates on the schedule interval, not the check interval")
+}
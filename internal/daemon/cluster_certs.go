@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/automa-saga/daemonkit"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/kube/certs"
+	"github.com/joomcode/errorx"
+)
+
+// AlertClusterCertificates keys the cluster certificate expiry alert in
+// StatusResponse.Alerts.
+const AlertClusterCertificates = "cluster-certificates"
+
+// clusterCertsAlert checks the kubeadm cluster's PKI under the sandbox and
+// returns an alert when a certificate expires within the configured window,
+// nil otherwise. A host without a cluster PKI raises nothing, and a PKI the
+// daemon cannot read is logged rather than alerted on: `kube cluster certs
+// check` run as root still covers it.
+//
+// The PKI is read on every call; it is a few dozen small files and GET /status
+// is called on demand, so there is no cache to keep fresh after a renew.
+func (d *Daemon) clusterCertsAlert(now time.Time) *daemonkit.StatusError {
+	if d.cfg.ClusterCerts != nil && d.cfg.ClusterCerts.Disabled {
+		return nil
+	}
+	pkiDir := filepath.Join(d.paths.SandboxDir, "etc", "kubernetes", "pki")
+	found, err := certs.Inspect(pkiDir)
+	if err != nil {
+		if !errorx.IsOfType(err, errorx.DataUnavailable) {
+			logx.As().Debug().Err(err).Str("reason", "ClusterCertificatesUnreadable").
+				Msg("Could not read the cluster certificates")
+		}
+		return nil
+	}
+
+	within := d.cfg.ClusterCerts.EffectiveWarnWithin()
+	expiring := certs.Expiring(found, now, within)
+	if len(expiring) == 0 {
+		return nil
+	}
+	return clusterCertsStatusError(expiring, now, within)
+}
+
+// clusterCertsStatusError renders the alert for expiring, soonest expiry
+// first. Since is when the first certificate entered the warning window.
+func clusterCertsStatusError(expiring []certs.Certificate, now time.Time, within time.Duration) *daemonkit.StatusError {
+	first := expiring[0]
+	se := &daemonkit.StatusError{
+		Reason:     "ClusterCertificateExpiring",
+		Message:    fmt.Sprintf("%s expires in %d day(s), on %s", first.Name, first.DaysRemaining(now), first.NotAfter.Format(time.RFC3339)),
+		Resolution: "sudo solo-provisioner kube cluster certs renew",
+		Since:      first.NotAfter.Add(-within).UTC().Format(time.RFC3339),
+	}
+	if first.Remaining(now) <= 0 {
+		se.Reason = "ClusterCertificateExpired"
+		se.Message = fmt.Sprintf("%s expired on %s", first.Name, first.NotAfter.Format(time.RFC3339))
+	}
+	if len(expiring) > 1 {
+		se.Message += fmt.Sprintf("; %d certificates expire within %s", len(expiring), within)
+	}
+	if first.CA {
+		se.Resolution = "the cluster CA must be rotated manually: " +
+			"https://kubernetes.io/docs/tasks/tls/manual-rotation-of-ca-certificates/"
+	}
+	return se
+}
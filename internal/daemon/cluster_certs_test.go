@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package daemon
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/solo-weaver/internal/kube/certs"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCert writes a self-signed certificate expiring at notAfter to path.
+func writeCert(t *testing.T, path string, notAfter time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: filepath.Base(path)},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644))
+}
+
+func TestClusterCertsAlert(t *testing.T) {
+	now := time.Now()
+	sandbox := t.TempDir()
+	pki := filepath.Join(sandbox, "etc", "kubernetes", "pki")
+	d := &Daemon{paths: models.WeaverPaths{SandboxDir: sandbox}}
+
+	assert.Nil(t, d.clusterCertsAlert(now), "a host without a cluster PKI raises nothing")
+
+	writeCert(t, filepath.Join(pki, "apiserver.crt"), now.AddDate(0, 0, 200))
+	assert.Nil(t, d.clusterCertsAlert(now))
+
+	writeCert(t, filepath.Join(pki, "etcd", "server.crt"), now.Add(10*24*time.Hour+time.Hour))
+	alert := d.clusterCertsAlert(now)
+	require.NotNil(t, alert)
+	assert.Equal(t, "ClusterCertificateExpiring", alert.Reason)
+	assert.Contains(t, alert.Message, "etcd/server.crt expires in 10 day(s)")
+	assert.Equal(t, "sudo solo-provisioner kube cluster certs renew", alert.Resolution)
+
+	d.cfg.ClusterCerts = &ClusterCertsConfig{WarnWithin: "240h"}
+	assert.Nil(t, d.clusterCertsAlert(now), "the window comes from cluster_certs.warn_within")
+
+	d.cfg.ClusterCerts = &ClusterCertsConfig{Disabled: true}
+	assert.Nil(t, d.clusterCertsAlert(now))
+}
+
+func TestClusterCertsStatusError_Expired(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	se := clusterCertsStatusError([]certs.Certificate{
+		{Name: "apiserver.crt", NotAfter: now.Add(-time.Hour)},
+		{Name: "admin.conf", NotAfter: now.Add(time.Hour)},
+	}, now, certs.DefaultWarnWithin)
+	assert.Equal(t, "ClusterCertificateExpired", se.Reason)
+	assert.Equal(t, "apiserver.crt expired on 2026-09-30T23:00:00Z; 2 certificates expire within 720h0m0s", se.Message)
+	assert.Equal(t, "2026-08-31T23:00:00Z", se.Since, "since is when the certificate entered the window")
+}
+
+func TestClusterCertsConfig(t *testing.T) {
+	var unset *ClusterCertsConfig
+	assert.Equal(t, certs.DefaultWarnWithin, unset.EffectiveWarnWithin())
+	assert.Equal(t, 48*time.Hour, (&ClusterCertsConfig{WarnWithin: "48h"}).EffectiveWarnWithin())
+
+	require.NoError(t, ClusterCertsConfig{}.Validate())
+	require.ErrorContains(t, ClusterCertsConfig{WarnWithin: "30d"}.Validate(), "not a valid Go duration")
+	require.ErrorContains(t, ClusterCertsConfig{WarnWithin: "-1h"}.Validate(), "must be positive")
+
+	cfg, err := ParseDaemonConfig([]byte(`schemaVersion: 1
+components:
+  block_node:
+    enabled: false
+cluster_certs:
+  warn_within: 1440h
+`), "daemon.yaml")
+	require.NoError(t, err)
+	require.NotNil(t, cfg.ClusterCerts)
+	assert.Equal(t, 1440*time.Hour, cfg.ClusterCerts.EffectiveWarnWithin())
+}
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/hashgraph/solo-weaver/internal/kube/certs"
+	"github.com/hashgraph/solo-weaver/pkg/models"
 	"gopkg.in/yaml.v3"
 )
 
@@ -55,6 +57,16 @@ const (
 //	    statusz:                     # optional local-fallback statusz source
 //	      base_url: http://127.0.0.1:8080
 //	      poll_interval: 5m
+//	cluster_certs:                 # optional; alerts on by default
+//	  warn_within: 720h
+//	cluster_backup:                # optional; off by default
+//	  enabled: true
+//	  interval: 24h
+//	  target: /opt/solo/weaver/backup/cluster
+//	  keep: 7
+//	software_audit:                # optional; off by default
+//	  enabled: true
+//	  interval: 6h
 type DaemonConfig struct {
 	// SchemaVersion identifies the config file format. Always written as
 	// CurrentSchemaVersion by WriteDaemonConfig. A value of 0 means the file
@@ -62,6 +74,76 @@ type DaemonConfig struct {
 	SchemaVersion int `yaml:"schemaVersion"`
 
 	Components DaemonComponents `yaml:"components"`
+
+	// ClusterCerts configures the cluster certificate expiry alert on GET
+	// /status. Nil alerts with the defaults (see ClusterCertsConfig).
+	ClusterCerts *ClusterCertsConfig `yaml:"cluster_certs,omitempty"`
+
+	// ClusterBackup configures the scheduled etcd backups of the cluster.
+	// Nil takes none (see ClusterBackupConfig).
+	ClusterBackup *ClusterBackupConfig `yaml:"cluster_backup,omitempty"`
+
+	// SoftwareAudit configures the scheduled integrity audit of the installed
+	// host software. Nil runs none (see SoftwareAuditConfig).
+	SoftwareAudit *SoftwareAuditConfig `yaml:"software_audit,omitempty"`
+}
+
+// ClusterCertsConfig configures the alert the daemon raises on GET /status
+// while a certificate of the kubeadm cluster is close to expiry. The daemon
+// reads the sandbox PKI only; the kubeconfigs are root-only and are covered
+// by `kube cluster certs check`.
+type ClusterCertsConfig struct {
+	// Disabled turns the alert off, e.g. on a host without a cluster.
+	Disabled bool `yaml:"disabled,omitempty"`
+
+	// WarnWithin is how close to expiry a certificate raises the alert, in Go
+	// duration form (e.g. "720h"). Empty defaults to certs.DefaultWarnWithin.
+	WarnWithin string `yaml:"warn_within,omitempty"`
+}
+
+// Cluster backup defaults, matching those of `kube cluster backup`. They are
+// restated rather than imported so the daemon's import closure stays clear of
+// the etcd backup code, which execs crictl and etcdctl.
+const (
+	DefaultClusterBackupInterval = 24 * time.Hour
+	DefaultClusterBackupKeep     = 7
+)
+
+// ClusterBackupConfig configures the scheduled etcd backups of the kubeadm
+// cluster (`kube cluster backup`). The daemon delegates each backup to the CLI
+// under sudo, which skips it while the newest backup is younger than Interval.
+type ClusterBackupConfig struct {
+	// Enabled turns the schedule on.
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is how often a backup is taken, in Go duration form (e.g.
+	// "24h"). Empty defaults to DefaultClusterBackupInterval.
+	Interval string `yaml:"interval,omitempty"`
+
+	// Target is the directory backups are written under. Empty defaults to
+	// <BackupDir>/cluster (/opt/solo/weaver/backup/cluster).
+	Target string `yaml:"target,omitempty"`
+
+	// Keep is how many backups are retained. Zero defaults to
+	// DefaultClusterBackupKeep.
+	Keep int `yaml:"keep,omitempty"`
+}
+
+// DefaultSoftwareAuditInterval is how often the installed host software is
+// audited when software_audit.interval is unset.
+const DefaultSoftwareAuditInterval = 6 * time.Hour
+
+// SoftwareAuditConfig configures the scheduled integrity audit of the installed
+// host software (`software verify --all`). The daemon delegates each audit to
+// the CLI under sudo and raises an alert on GET /status while the latest one
+// failed.
+type SoftwareAuditConfig struct {
+	// Enabled turns the schedule on.
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is how often the audit runs, in Go duration form (e.g. "6h").
+	// Empty defaults to DefaultSoftwareAuditInterval.
+	Interval string `yaml:"interval,omitempty"`
 }
 
 // DaemonComponents holds the per-component configuration blocks.
@@ -124,11 +206,58 @@ type BlockNodeComponentConfig struct {
 	// When set, BaseURL takes precedence over discovery — an explicit override
 	// pointing at a directly reachable BN statusz or a port-forward.
 	Statusz *StatuszConfig `yaml:"statusz,omitempty"`
+
+	// Instances are the additional block nodes on the host, each its own Helm
+	// release in its own orbit. The fields above describe the default release;
+	// an instance shares its kubeconfig (the component's RBAC is cluster-wide)
+	// and has its own enablement, orbit, monitors and statusz source.
+	Instances []BlockNodeInstanceConfig `yaml:"instances,omitempty"`
+}
+
+// BlockNodeInstanceConfig is the configuration of one additional block node
+// instance. Name is the instance's Helm release; it keys the instance's policy
+// set names and storage sample files (see models.BlockNodeInstance).
+type BlockNodeInstanceConfig struct {
+	Name     string            `yaml:"name"`
+	Enabled  bool              `yaml:"enabled"`
+	Orbit    string            `yaml:"orbit"`
+	Monitors BlockNodeMonitors `yaml:"monitors"`
+	Statusz  *StatuszConfig    `yaml:"statusz,omitempty"`
+}
+
+// InstanceConfigs returns every block node instance of the component, the
+// default release first (with an empty Name) followed by Instances.
+func (bn BlockNodeComponentConfig) InstanceConfigs() []BlockNodeInstanceConfig {
+	out := []BlockNodeInstanceConfig{{
+		Enabled:  bn.Enabled,
+		Orbit:    bn.Orbit,
+		Monitors: bn.Monitors,
+		Statusz:  bn.Statusz,
+	}}
+	return append(out, bn.Instances...)
+}
+
+// TrafficShaperEnabled reports whether any enabled instance of the component
+// runs the traffic-shaper monitor, i.e. whether the component needs its
+// kubeconfig and RBAC.
+func (bn BlockNodeComponentConfig) TrafficShaperEnabled() bool {
+	for _, inst := range bn.InstanceConfigs() {
+		if inst.Enabled && inst.Monitors.TrafficShaper {
+			return true
+		}
+	}
+	return false
 }
 
 // BlockNodeMonitors toggles individual monitors for the block-node component.
 type BlockNodeMonitors struct {
 	TrafficShaper bool `yaml:"traffic_shaper"`
+	// StorageSampler periodically records the on-disk size of the block
+	// node's storage volumes for `block node storage forecast`.
+	StorageSampler bool `yaml:"storage_sampler,omitempty"`
+	// Probe periodically checks the block node's external endpoint for
+	// `block node probe --history`.
+	Probe bool `yaml:"probe,omitempty"`
 }
 
 // StatuszConfig is an explicit override for the statusz source polled by the
@@ -163,6 +292,117 @@ func (s StatuszConfig) EffectivePollInterval() time.Duration {
 	return d
 }
 
+// EffectiveWarnWithin returns the configured warning window, or
+// certs.DefaultWarnWithin when unset. Like EffectivePollInterval it assumes
+// the value has passed Validate.
+func (c *ClusterCertsConfig) EffectiveWarnWithin() time.Duration {
+	if c == nil || c.WarnWithin == "" {
+		return certs.DefaultWarnWithin
+	}
+	d, err := time.ParseDuration(c.WarnWithin)
+	if err != nil || d <= 0 {
+		return certs.DefaultWarnWithin
+	}
+	return d
+}
+
+// Validate checks that WarnWithin, when set, is a positive Go duration.
+func (c ClusterCertsConfig) Validate() error {
+	if c.WarnWithin == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(c.WarnWithin)
+	if err != nil {
+		return ErrConfigMalformed.Wrap(err, "cluster_certs.warn_within %q is not a valid Go duration", c.WarnWithin)
+	}
+	if d <= 0 {
+		return ErrConfigMalformed.New("cluster_certs.warn_within must be positive, got %q", c.WarnWithin)
+	}
+	return nil
+}
+
+// EffectiveInterval returns the configured backup interval, or
+// DefaultClusterBackupInterval when unset. It assumes the value has passed
+// Validate.
+func (c *ClusterBackupConfig) EffectiveInterval() time.Duration {
+	if c == nil || c.Interval == "" {
+		return DefaultClusterBackupInterval
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return DefaultClusterBackupInterval
+	}
+	return d
+}
+
+// EffectiveTarget returns the configured backup target, or the default target
+// under paths.
+func (c *ClusterBackupConfig) EffectiveTarget(paths models.WeaverPaths) string {
+	if c == nil || c.Target == "" {
+		return filepath.Join(paths.BackupDir, "cluster")
+	}
+	return c.Target
+}
+
+// EffectiveKeep returns the configured retention, or DefaultClusterBackupKeep
+// when unset.
+func (c *ClusterBackupConfig) EffectiveKeep() int {
+	if c == nil || c.Keep == 0 {
+		return DefaultClusterBackupKeep
+	}
+	return c.Keep
+}
+
+// Validate checks that Interval, when set, is a positive Go duration, that
+// Target, when set, is absolute and that Keep is not negative.
+func (c ClusterBackupConfig) Validate() error {
+	if c.Interval != "" {
+		d, err := time.ParseDuration(c.Interval)
+		if err != nil {
+			return ErrConfigMalformed.Wrap(err, "cluster_backup.interval %q is not a valid Go duration", c.Interval)
+		}
+		if d <= 0 {
+			return ErrConfigMalformed.New("cluster_backup.interval must be positive, got %q", c.Interval)
+		}
+	}
+	if c.Target != "" && !filepath.IsAbs(c.Target) {
+		return ErrConfigMalformed.New("cluster_backup.target must be an absolute path, got %q", c.Target)
+	}
+	if c.Keep < 0 {
+		return ErrConfigMalformed.New("cluster_backup.keep must not be negative, got %d", c.Keep)
+	}
+	return nil
+}
+
+// EffectiveInterval returns the configured audit interval, or
+// DefaultSoftwareAuditInterval when unset. It assumes the value has passed
+// Validate.
+func (c *SoftwareAuditConfig) EffectiveInterval() time.Duration {
+	if c == nil || c.Interval == "" {
+		return DefaultSoftwareAuditInterval
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return DefaultSoftwareAuditInterval
+	}
+	return d
+}
+
+// Validate checks that Interval, when set, is a positive Go duration.
+func (c SoftwareAuditConfig) Validate() error {
+	if c.Interval == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return ErrConfigMalformed.Wrap(err, "software_audit.interval %q is not a valid Go duration", c.Interval)
+	}
+	if d <= 0 {
+		return ErrConfigMalformed.New("software_audit.interval must be positive, got %q", c.Interval)
+	}
+	return nil
+}
+
 // Validate checks the statusz block's fields: BaseURL, when set, must be an
 // http(s) URL with a host, and PollInterval, when set, must be a positive Go
 // duration.
@@ -203,6 +443,31 @@ func (bn BlockNodeComponentConfig) Validate() error {
 			return err
 		}
 	}
+	seen := make(map[string]bool, len(bn.Instances))
+	for _, inst := range bn.Instances {
+		if inst.Name == "" {
+			return ErrConfigMalformed.New("components.block_node.instances[].name is required")
+		}
+		if seen[inst.Name] {
+			return ErrConfigMalformed.New("components.block_node.instances has more than one entry named %q", inst.Name)
+		}
+		seen[inst.Name] = true
+		if inst.Enabled && inst.Monitors.TrafficShaper {
+			if bn.Kubeconfig == "" {
+				return ErrConfigMalformed.New(
+					"components.block_node.kubeconfig is required when instance %q runs the traffic-shaper monitor", inst.Name)
+			}
+			if inst.Orbit == "" {
+				return ErrConfigMalformed.New(
+					"components.block_node.instances[%s].orbit is required when monitors.traffic_shaper is true", inst.Name)
+			}
+		}
+		if inst.Statusz != nil {
+			if err := inst.Statusz.Validate(); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -245,6 +510,21 @@ func (c DaemonConfig) Validate() error {
 			return err
 		}
 	}
+	if cc := c.ClusterCerts; cc != nil {
+		if err := cc.Validate(); err != nil {
+			return err
+		}
+	}
+	if cb := c.ClusterBackup; cb != nil {
+		if err := cb.Validate(); err != nil {
+			return err
+		}
+	}
+	if sa := c.SoftwareAudit; sa != nil {
+		if err := sa.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
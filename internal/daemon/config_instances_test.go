@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package daemon_test
+
+import (
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/daemon"
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDaemonConfig_BlockNodeInstances(t *testing.T) {
+	content := `schemaVersion: 1
+components:
+  block_node:
+    enabled: true
+    kubeconfig: /opt/solo/weaver/config/daemon-bn.kubeconfig
+    orbit: block-node
+    monitors:
+      traffic_shaper: true
+    instances:
+      - name: testnet
+        enabled: true
+        orbit: block-node-testnet
+        monitors:
+          traffic_shaper: true
+        statusz:
+          base_url: http://127.0.0.1:8081
+`
+	path := writeTempConfig(t, content)
+
+	cfg, err := daemon.LoadDaemonConfig(path)
+	require.NoError(t, err)
+	bn := cfg.Components.BlockNode
+	require.NotNil(t, bn)
+
+	insts := bn.InstanceConfigs()
+	require.Len(t, insts, 2)
+	assert.Equal(t, "", insts[0].Name, "default instance comes first")
+	assert.Equal(t, "block-node", insts[0].Orbit)
+	assert.Equal(t, "testnet", insts[1].Name)
+	assert.Equal(t, "block-node-testnet", insts[1].Orbit)
+	require.NotNil(t, insts[1].Statusz)
+	assert.Equal(t, "http://127.0.0.1:8081", insts[1].Statusz.BaseURL)
+	assert.True(t, bn.TrafficShaperEnabled())
+}
+
+func TestBlockNodeComponentConfig_TrafficShaperEnabled(t *testing.T) {
+	bn := daemon.BlockNodeComponentConfig{
+		Instances: []daemon.BlockNodeInstanceConfig{
+			{Name: "testnet", Enabled: true, Orbit: "ns", Monitors: daemon.BlockNodeMonitors{TrafficShaper: true}},
+		},
+	}
+	assert.True(t, bn.TrafficShaperEnabled(), "an additional instance alone enables the shaper")
+
+	bn.Instances[0].Enabled = false
+	assert.False(t, bn.TrafficShaperEnabled())
+}
+
+func TestBlockNodeComponentConfig_ValidateInstances(t *testing.T) {
+	shaping := daemon.BlockNodeMonitors{TrafficShaper: true}
+	tests := []struct {
+		name      string
+		instances []daemon.BlockNodeInstanceConfig
+		noKube    bool
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			instances: []daemon.BlockNodeInstanceConfig{{Name: "a", Enabled: true, Orbit: "ns-a", Monitors: shaping}},
+		},
+		{
+			name:      "missing name",
+			instances: []daemon.BlockNodeInstanceConfig{{Orbit: "ns"}},
+			wantErr:   "name is required",
+		},
+		{
+			name: "duplicate name",
+			instances: []daemon.BlockNodeInstanceConfig{
+				{Name: "a", Orbit: "ns-a"},
+				{Name: "a", Orbit: "ns-b"},
+			},
+			wantErr: "more than one entry",
+		},
+		{
+			name:      "shaping instance without orbit",
+			instances: []daemon.BlockNodeInstanceConfig{{Name: "a", Enabled: true, Monitors: shaping}},
+			wantErr:   "orbit is required",
+		},
+		{
+			name:      "shaping instance without kubeconfig",
+			instances: []daemon.BlockNodeInstanceConfig{{Name: "a", Enabled: true, Orbit: "ns-a", Monitors: shaping}},
+			noKube:    true,
+			wantErr:   "kubeconfig is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn := daemon.BlockNodeComponentConfig{Kubeconfig: "/k", Instances: tt.instances}
+			if tt.noKube {
+				bn.Kubeconfig = ""
+			}
+			err := bn.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errorx.IsOfType(err, daemon.ErrConfigMalformed))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashgraph/solo-weaver/internal/daemon"
+	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/joomcode/errorx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -157,3 +159,58 @@ func TestLoadDaemonConfig_NewerVersionErrorIsErrConfigMalformed(t *testing.T) {
 	require.NotNil(t, ex, "error must be an errorx type for doctor.CheckErr to handle it")
 	assert.True(t, strings.Contains(ex.Error(), "newer binary"))
 }
+
+func TestClusterBackupConfig(t *testing.T) {
+	paths := models.WeaverPaths{BackupDir: "/opt/solo/weaver/backup"}
+	var unset *daemon.ClusterBackupConfig
+	assert.Equal(t, daemon.DefaultClusterBackupInterval, unset.EffectiveInterval())
+	assert.Equal(t, daemon.DefaultClusterBackupKeep, unset.EffectiveKeep())
+	assert.Equal(t, "/opt/solo/weaver/backup/cluster", unset.EffectiveTarget(paths))
+
+	require.NoError(t, daemon.ClusterBackupConfig{}.Validate())
+	require.ErrorContains(t, daemon.ClusterBackupConfig{Interval: "1d"}.Validate(), "not a valid Go duration")
+	require.ErrorContains(t, daemon.ClusterBackupConfig{Interval: "0s"}.Validate(), "must be positive")
+	require.ErrorContains(t, daemon.ClusterBackupConfig{Target: "backups"}.Validate(), "absolute path")
+	require.ErrorContains(t, daemon.ClusterBackupConfig{Keep: -1}.Validate(), "must not be negative")
+
+	path := writeTempConfig(t, `schemaVersion: 1
+components:
+  block_node:
+    enabled: false
+cluster_backup:
+  enabled: true
+  interval: 6h
+  target: /mnt/nfs/cluster
+  keep: 3
+`)
+	cfg, err := daemon.LoadDaemonConfig(path)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.ClusterBackup)
+	assert.True(t, cfg.ClusterBackup.Enabled)
+	assert.Equal(t, 6*time.Hour, cfg.ClusterBackup.EffectiveInterval())
+	assert.Equal(t, "/mnt/nfs/cluster", cfg.ClusterBackup.EffectiveTarget(paths))
+	assert.Equal(t, 3, cfg.ClusterBackup.EffectiveKeep())
+}
+
+func TestSoftwareAuditConfig(t *testing.T) {
+	var unset *daemon.SoftwareAuditConfig
+	assert.Equal(t, daemon.DefaultSoftwareAuditInterval, unset.EffectiveInterval())
+
+	require.NoError(t, daemon.SoftwareAuditConfig{}.Validate())
+	require.ErrorContains(t, daemon.SoftwareAuditConfig{Interval: "1d"}.Validate(), "not a valid Go duration")
+	require.ErrorContains(t, daemon.SoftwareAuditConfig{Interval: "-1h"}.Validate(), "must be positive")
+
+	path := writeTempConfig(t, `schemaVersion: 1
+components:
+  block_node:
+    enabled: false
+software_audit:
+  enabled: true
+  interval: 30m
+`)
+	cfg, err := daemon.LoadDaemonConfig(path)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.SoftwareAudit)
+	assert.True(t, cfg.SoftwareAudit.Enabled)
+	assert.Equal(t, 30*time.Minute, cfg.SoftwareAudit.EffectiveInterval())
+}
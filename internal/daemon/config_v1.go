@@ -20,6 +20,26 @@ package daemon
 type daemonConfigV1 struct {
 	SchemaVersion int                `yaml:"schemaVersion"`
 	Components    daemonComponentsV1 `yaml:"components"`
+	ClusterCerts  *clusterCertsV1    `yaml:"cluster_certs,omitempty"`
+	ClusterBackup *clusterBackupV1   `yaml:"cluster_backup,omitempty"`
+	SoftwareAudit *softwareAuditV1   `yaml:"software_audit,omitempty"`
+}
+
+type softwareAuditV1 struct {
+	Enabled  bool   `yaml:"enabled"`
+	Interval string `yaml:"interval,omitempty"`
+}
+
+type clusterBackupV1 struct {
+	Enabled  bool   `yaml:"enabled"`
+	Interval string `yaml:"interval,omitempty"`
+	Target   string `yaml:"target,omitempty"`
+	Keep     int    `yaml:"keep,omitempty"`
+}
+
+type clusterCertsV1 struct {
+	Disabled   bool   `yaml:"disabled,omitempty"`
+	WarnWithin string `yaml:"warn_within,omitempty"`
 }
 
 type daemonComponentsV1 struct {
@@ -42,15 +62,26 @@ type consensusNodeMonitorsV1 struct {
 }
 
 type blockNodeConfigV1 struct {
-	Enabled    bool                `yaml:"enabled"`
-	Kubeconfig string              `yaml:"kubeconfig"`
-	Orbit      string              `yaml:"orbit"`
-	Monitors   blockNodeMonitorsV1 `yaml:"monitors"`
-	Statusz    *statuszConfigV1    `yaml:"statusz,omitempty"`
+	Enabled    bool                  `yaml:"enabled"`
+	Kubeconfig string                `yaml:"kubeconfig"`
+	Orbit      string                `yaml:"orbit"`
+	Monitors   blockNodeMonitorsV1   `yaml:"monitors"`
+	Statusz    *statuszConfigV1      `yaml:"statusz,omitempty"`
+	Instances  []blockNodeInstanceV1 `yaml:"instances,omitempty"`
+}
+
+type blockNodeInstanceV1 struct {
+	Name     string              `yaml:"name"`
+	Enabled  bool                `yaml:"enabled"`
+	Orbit    string              `yaml:"orbit"`
+	Monitors blockNodeMonitorsV1 `yaml:"monitors"`
+	Statusz  *statuszConfigV1    `yaml:"statusz,omitempty"`
 }
 
 type blockNodeMonitorsV1 struct {
-	TrafficShaper bool `yaml:"traffic_shaper"`
+	TrafficShaper  bool `yaml:"traffic_shaper"`
+	StorageSampler bool `yaml:"storage_sampler,omitempty"`
+	Probe          bool `yaml:"probe,omitempty"`
 }
 
 type statuszConfigV1 struct {
@@ -86,16 +117,57 @@ func (v daemonConfigV1) migrateToLatest() DaemonConfig {
 			Kubeconfig: bn.Kubeconfig,
 			Orbit:      bn.Orbit,
 			Monitors: BlockNodeMonitors{
-				TrafficShaper: bn.Monitors.TrafficShaper,
+				TrafficShaper:  bn.Monitors.TrafficShaper,
+				StorageSampler: bn.Monitors.StorageSampler,
+				Probe:          bn.Monitors.Probe,
 			},
 		}
-		if s := bn.Statusz; s != nil {
-			blockNode.Statusz = &StatuszConfig{
-				BaseURL:      s.BaseURL,
-				PollInterval: s.PollInterval,
-			}
+		blockNode.Statusz = bn.Statusz.migrateToLatest()
+		for _, inst := range bn.Instances {
+			blockNode.Instances = append(blockNode.Instances, BlockNodeInstanceConfig{
+				Name:    inst.Name,
+				Enabled: inst.Enabled,
+				Orbit:   inst.Orbit,
+				Monitors: BlockNodeMonitors{
+					TrafficShaper:  inst.Monitors.TrafficShaper,
+					StorageSampler: inst.Monitors.StorageSampler,
+					Probe:          inst.Monitors.Probe,
+				},
+				Statusz: inst.Statusz.migrateToLatest(),
+			})
 		}
 		cfg.Components.BlockNode = blockNode
 	}
+	if cc := v.ClusterCerts; cc != nil {
+		cfg.ClusterCerts = &ClusterCertsConfig{
+			Disabled:   cc.Disabled,
+			WarnWithin: cc.WarnWithin,
+		}
+	}
+	if cb := v.ClusterBackup; cb != nil {
+		cfg.ClusterBackup = &ClusterBackupConfig{
+			Enabled:  cb.Enabled,
+			Interval: cb.Interval,
+			Target:   cb.Target,
+			Keep:     cb.Keep,
+		}
+	}
+	if sa := v.SoftwareAudit; sa != nil {
+		cfg.SoftwareAudit = &SoftwareAuditConfig{
+			Enabled:  sa.Enabled,
+			Interval: sa.Interval,
+		}
+	}
 	return cfg
 }
+
+// migrateToLatest converts an optional statusz block; nil stays nil.
+func (s *statuszConfigV1) migrateToLatest() *StatuszConfig {
+	if s == nil {
+		return nil
+	}
+	return &StatuszConfig{
+		BaseURL:      s.BaseURL,
+		PollInterval: s.PollInterval,
+	}
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/automa-saga/daemonkit"
 	"github.com/automa-saga/logx"
 	"github.com/hashgraph/solo-weaver/internal/daemon/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/daemon/cluster"
 	"github.com/hashgraph/solo-weaver/internal/daemon/consensus"
+	"github.com/hashgraph/solo-weaver/internal/daemon/host"
 	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/joomcode/errorx"
 	"golang.org/x/sync/errgroup"
@@ -53,6 +55,14 @@ type Daemon struct {
 	// nil = all probes passed (or no probes). Written by runComponentProbes,
 	// read by statusSnapshot — both via atomic.Pointer to avoid locks.
 	probeErrors atomic.Pointer[map[string]daemonkit.StatusError]
+	// softwareAudit is the host component's audit monitor, nil when
+	// software_audit is off. Its latest result is surfaced as an alert.
+	softwareAudit alertSource
+}
+
+// alertSource is a monitor that raises an alert on GET /status.
+type alertSource interface {
+	Alert() *daemonkit.StatusError
 }
 
 // New constructs a Daemon from WeaverPaths. It reads daemon.yaml from
@@ -108,36 +118,60 @@ func NewFromConfig(paths models.WeaverPaths, cfg DaemonConfig) (*Daemon, error)
 		}
 	}
 
-	bn := cfg.Components.BlockNode
-	if bn != nil && bn.Enabled {
-		// statusz is optional (see BlockNodeComponentConfig.Statusz): when it is
-		// nil or its base_url is empty, the poll loop idles. EffectivePollInterval
-		// already applies the 5m default.
-		var statuszBaseURL string
-		var statuszPollInterval time.Duration
-		if bn.Statusz != nil {
-			statuszBaseURL = bn.Statusz.BaseURL
-			statuszPollInterval = bn.Statusz.EffectivePollInterval()
-		}
-		result, err := blocknode.NewComponent(blocknode.ComponentConfig{
-			TrafficShaperEnabled: bn.Monitors.TrafficShaper,
-			KubeconfigPath:       bn.Kubeconfig,
-			Namespace:            bn.Orbit,
-			StatuszBaseURL:       statuszBaseURL,
-			StatuszPollInterval:  statuszPollInterval,
-		})
-		if err != nil {
-			logComponentBuildSkipped(ComponentNameBlockNode, bn.Kubeconfig, err)
-		} else if len(result.Monitors) > 0 {
+	// block_node: one component per enabled block node instance on the host,
+	// the default release first. They share the component's kubeconfig; only
+	// the first traffic-shaper monitor follows the egress link (the $EGRESS
+	// hierarchy is host-wide) and serves the /block_node/ routes.
+	if bn := cfg.Components.BlockNode; bn != nil {
+		egressFollowed := false
+		for _, inst := range bn.InstanceConfigs() {
+			if !inst.Enabled {
+				continue
+			}
+			name := BlockNodeComponentName(inst.Name)
+			// statusz is optional (see BlockNodeComponentConfig.Statusz): when it
+			// is nil or its base_url is empty, the poll loop idles.
+			// EffectivePollInterval already applies the 5m default.
+			var statuszBaseURL string
+			var statuszPollInterval time.Duration
+			if inst.Statusz != nil {
+				statuszBaseURL = inst.Statusz.BaseURL
+				statuszPollInterval = inst.Statusz.EffectivePollInterval()
+			}
+			volumesPath, samplesPath := paths.BlockNodeStoragePaths(inst.Name)
+			probeTargetPath, probeHistoryPath := paths.BlockNodeProbePaths(inst.Name)
+			result, err := blocknode.NewComponent(blocknode.ComponentConfig{
+				TrafficShaperEnabled:  inst.Monitors.TrafficShaper,
+				KubeconfigPath:        bn.Kubeconfig,
+				Namespace:             inst.Orbit,
+				StatuszBaseURL:        statuszBaseURL,
+				StatuszPollInterval:   statuszPollInterval,
+				StorageSamplerEnabled: inst.Monitors.StorageSampler,
+				StorageVolumesPath:    volumesPath,
+				StorageSamplesPath:    samplesPath,
+				ProbeEnabled:          inst.Monitors.Probe,
+				ProbeTargetPath:       probeTargetPath,
+				ProbeHistoryPath:      probeHistoryPath,
+				Instance:              inst.Name,
+				SharedEgressLink:      egressFollowed,
+			})
+			if err != nil {
+				logComponentBuildSkipped(name, bn.Kubeconfig, err)
+				continue
+			}
+			if len(result.Monitors) == 0 {
+				continue
+			}
 			comp := component{
-				name:     ComponentNameBlockNode,
+				name:     name,
 				monitors: result.Monitors,
 				probe:    nil,
 				tracker:  daemonkit.NewStatusTracker(),
 			}
 			components = append(components, comp)
 
-			if result.TrafficShaperMonitor != nil {
+			if result.TrafficShaperMonitor != nil && !egressFollowed {
+				egressFollowed = true
 				// trafficShaperStateFn captures comp.tracker by reference — safe
 				// because comp is appended to the slice and never moved after
 				// this point.
@@ -150,10 +184,36 @@ func NewFromConfig(paths models.WeaverPaths, cfg DaemonConfig) (*Daemon, error)
 		}
 	}
 
+	// cluster: host-level monitors of the kubeadm cluster itself. They need no
+	// kubeconfig, so the component has no probe.
+	if cb := cfg.ClusterBackup; cb != nil && cb.Enabled {
+		components = append(components, component{
+			name: ComponentNameCluster,
+			monitors: []daemonkit.MonitorRunner{
+				cluster.NewBackupMonitor(cb.EffectiveTarget(paths), cb.EffectiveKeep(), cb.EffectiveInterval()),
+			},
+			tracker: daemonkit.NewStatusTracker(),
+		})
+	}
+
+	// host: the integrity audit of the installed host software. Like the
+	// cluster monitors it needs no kubeconfig, so the component has no probe.
+	var softwareAudit alertSource
+	if sa := cfg.SoftwareAudit; sa != nil && sa.Enabled {
+		m := host.NewSoftwareAuditMonitor(sa.EffectiveInterval())
+		softwareAudit = m
+		components = append(components, component{
+			name:     ComponentNameHost,
+			monitors: []daemonkit.MonitorRunner{m},
+			tracker:  daemonkit.NewStatusTracker(),
+		})
+	}
+
 	d := &Daemon{
-		paths:      paths,
-		cfg:        cfg,
-		components: components,
+		paths:         paths,
+		cfg:           cfg,
+		components:    components,
+		softwareAudit: softwareAudit,
 	}
 	d.server = daemonkit.NewServer(paths.DaemonSockPath, daemonkit.ServerOptions{
 		StatusFn:          func() any { return d.statusSnapshot() },
@@ -303,7 +363,8 @@ func (d *Daemon) runComponentProbes(ctx context.Context) {
 
 // statusSnapshot builds a StatusResponse from the current tracker snapshots
 // and the latest probe results. ProbeErrors is non-empty when any component's
-// disk prerequisites are not yet satisfied.
+// disk prerequisites are not yet satisfied; Alerts when a cluster certificate
+// is close to expiry or the latest software audit failed.
 func (d *Daemon) statusSnapshot() StatusResponse {
 	resp := StatusResponse{
 		Components: make(map[string]ComponentStatus, len(d.components)),
@@ -336,6 +397,18 @@ func (d *Daemon) statusSnapshot() StatusResponse {
 	if pe := d.probeErrors.Load(); pe != nil && len(*pe) > 0 {
 		resp.ProbeErrors = *pe
 	}
+	alerts := map[string]daemonkit.StatusError{}
+	if alert := d.clusterCertsAlert(time.Now()); alert != nil {
+		alerts[AlertClusterCertificates] = *alert
+	}
+	if d.softwareAudit != nil {
+		if alert := d.softwareAudit.Alert(); alert != nil {
+			alerts[AlertSoftwareIntegrity] = *alert
+		}
+	}
+	if len(alerts) > 0 {
+		resp.Alerts = alerts
+	}
 	return resp
 }
 
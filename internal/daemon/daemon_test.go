@@ -257,6 +257,57 @@ func TestNewFromConfig_NoComponentsStillBuildsServer(t *testing.T) {
 	assert.NotNil(t, d.server, "server must always be constructed")
 }
 
+func TestNewFromConfig_ClusterBackup(t *testing.T) {
+	cfg := DaemonConfig{ClusterBackup: &ClusterBackupConfig{Enabled: false}}
+	d, err := NewFromConfig(models.WeaverPaths{DaemonSockPath: "/tmp/x.sock"}, cfg)
+	require.NoError(t, err)
+	assert.Empty(t, d.components, "a disabled schedule builds no component")
+
+	cfg.ClusterBackup.Enabled = true
+	d, err = NewFromConfig(models.WeaverPaths{DaemonSockPath: "/tmp/x.sock", BackupDir: "/opt/solo/weaver/backup"}, cfg)
+	require.NoError(t, err)
+	require.Len(t, d.components, 1)
+	assert.Equal(t, ComponentNameCluster, d.components[0].name)
+	assert.Nil(t, d.components[0].probe)
+	require.Len(t, d.components[0].monitors, 1)
+	assert.Equal(t, "cluster-backup-monitor", d.components[0].monitors[0].Name())
+}
+
+func TestNewFromConfig_SoftwareAudit(t *testing.T) {
+	cfg := DaemonConfig{SoftwareAudit: &SoftwareAuditConfig{Enabled: false}}
+	d, err := NewFromConfig(models.WeaverPaths{DaemonSockPath: "/tmp/x.sock"}, cfg)
+	require.NoError(t, err)
+	assert.Empty(t, d.components, "a disabled audit builds no component")
+	assert.Nil(t, d.softwareAudit)
+
+	cfg.SoftwareAudit.Enabled = true
+	d, err = NewFromConfig(models.WeaverPaths{DaemonSockPath: "/tmp/x.sock"}, cfg)
+	require.NoError(t, err)
+	require.Len(t, d.components, 1)
+	assert.Equal(t, ComponentNameHost, d.components[0].name)
+	assert.Nil(t, d.components[0].probe)
+	require.Len(t, d.components[0].monitors, 1)
+	assert.Equal(t, "software-audit-monitor", d.components[0].monitors[0].Name())
+	assert.Nil(t, d.statusSnapshot().Alerts, "no alert before the first audit")
+}
+
+// staticAlert is an alertSource raising a fixed alert.
+type staticAlert struct{ alert *daemonkit.StatusError }
+
+func (s staticAlert) Alert() *daemonkit.StatusError { return s.alert }
+
+func TestStatusSnapshot_SoftwareIntegrityAlert(t *testing.T) {
+	alert := &daemonkit.StatusError{Reason: "SoftwareTampered", Message: "kubelet failed verification"}
+	d := &Daemon{
+		paths:         models.WeaverPaths{SandboxDir: t.TempDir()},
+		softwareAudit: staticAlert{alert: alert},
+	}
+	assert.Equal(t, map[string]daemonkit.StatusError{AlertSoftwareIntegrity: *alert}, d.statusSnapshot().Alerts)
+
+	d.softwareAudit = staticAlert{}
+	assert.Nil(t, d.statusSnapshot().Alerts)
+}
+
 func TestNewFromConfig_DisabledComponentSkipped(t *testing.T) {
 	cfg := DaemonConfig{Components: DaemonComponents{
 		ConsensusNode: &ConsensusNodeComponentConfig{Enabled: false},
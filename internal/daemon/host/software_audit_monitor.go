@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package host holds the daemon monitors of the host's own installation, as
+// opposed to the cluster or the workloads running on it.
+package host
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/automa-saga/daemonkit"
+	"github.com/automa-saga/logx"
+
+	"github.com/hashgraph/solo-weaver/internal/daemon/privexec"
+)
+
+// SoftwareAuditMonitor is the daemonkit.MonitorRunner that audits the installed
+// host software for tampering on a schedule. The binaries and configs it
+// hashes are root-owned, so every audit delegates `software verify --all`
+// under sudo; the latest result is kept for GET /status.
+type SoftwareAuditMonitor struct {
+	delegator privexec.Delegator
+	interval  time.Duration
+
+	// alert is the alert raised by the latest audit, nil while it passed or
+	// before the first one completed. Written by Run, read by Alert.
+	alert atomic.Pointer[daemonkit.StatusError]
+	// since is when the audits started failing; only Run touches it.
+	since time.Time
+}
+
+// NewSoftwareAuditMonitor creates a monitor that audits the host software
+// every interval.
+func NewSoftwareAuditMonitor(interval time.Duration) *SoftwareAuditMonitor {
+	return &SoftwareAuditMonitor{
+		delegator: privexec.New(),
+		interval:  interval,
+	}
+}
+
+// Name implements daemonkit.MonitorRunner.
+func (m *SoftwareAuditMonitor) Name() string { return "software-audit-monitor" }
+
+// Alert returns the alert raised by the latest audit, or nil when it passed.
+func (m *SoftwareAuditMonitor) Alert() *daemonkit.StatusError {
+	return m.alert.Load()
+}
+
+// Run implements daemonkit.MonitorRunner. It audits once on entry and then
+// every interval until ctx is cancelled. An audit that cannot run is logged
+// and leaves the previous result in place, so Run only returns on
+// cancellation.
+func (m *SoftwareAuditMonitor) Run(ctx context.Context) error {
+	logx.As().Info().
+		Str("reason", "SoftwareAuditMonitorStarting").
+		Str("monitor", m.Name()).
+		Dur("interval", m.interval).
+		Msg("software audit monitor starting")
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.audit(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// audit runs one audit and records its outcome.
+func (m *SoftwareAuditMonitor) audit(ctx context.Context, now time.Time) {
+	failures, err := m.delegator.SoftwareVerify(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logx.As().Warn().Err(err).
+				Str("reason", "SoftwareAuditFailed").
+				Str("monitor", m.Name()).
+				Msg("Scheduled software audit could not run; retrying on the next check")
+		}
+		return
+	}
+
+	if len(failures) == 0 {
+		m.since = time.Time{}
+		m.alert.Store(nil)
+		return
+	}
+
+	if m.since.IsZero() {
+		m.since = now
+	}
+	alert := softwareTamperedStatusError(failures, m.since)
+	logx.As().Error().
+		Str("reason", alert.Reason).
+		Str("monitor", m.Name()).
+		Int("failures", len(failures)).
+		Msg(alert.Message)
+	m.alert.Store(alert)
+}
+
+// softwareTamperedStatusError renders the alert for failures, naming the
+// first failing file.
+func softwareTamperedStatusError(failures []privexec.SoftwareVerifyFailure, since time.Time) *daemonkit.StatusError {
+	first := failures[0]
+	msg := fmt.Sprintf("%s failed verification: %s", first.Path, strings.Join(first.Problems, "; "))
+	if len(failures) > 1 {
+		msg += fmt.Sprintf("; %d installed files failed in total", len(failures))
+	}
+	return &daemonkit.StatusError{
+		Reason:     "SoftwareTampered",
+		Message:    msg,
+		Resolution: "sudo solo-provisioner software verify --all",
+		Since:      since.UTC().Format(time.RFC3339),
+	}
+}
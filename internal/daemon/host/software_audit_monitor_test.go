@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !integration
+
+package host
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/daemon/privexec"
+)
+
+// auditFakeDelegator returns the scripted SoftwareVerify results in order,
+// holding the last one once exhausted.
+type auditFakeDelegator struct {
+	privexec.Delegator
+
+	calls   atomic.Int32
+	results []auditResult
+}
+
+type auditResult struct {
+	failures []privexec.SoftwareVerifyFailure
+	err      error
+}
+
+func (f *auditFakeDelegator) SoftwareVerify(context.Context) ([]privexec.SoftwareVerifyFailure, error) {
+	n := int(f.calls.Add(1)) - 1
+	r := f.results[min(n, len(f.results)-1)]
+	return r.failures, r.err
+}
+
+func TestSoftwareAuditMonitor_AlertFollowsAudits(t *testing.T) {
+	tampered := []privexec.SoftwareVerifyFailure{
+		{Artifact: "kubelet", Path: "/opt/solo/weaver/sandbox/bin/kubelet", Problems: []string{"sha512 mismatch"}},
+		{Path: "/opt/solo/weaver/sandbox/bin/dropped", Problems: []string{"not installed by any recorded software"}},
+	}
+	m := NewSoftwareAuditMonitor(time.Hour)
+	m.delegator = &auditFakeDelegator{results: []auditResult{
+		{failures: tampered},
+		{err: errors.New("sudo: a password is required")},
+		{failures: tampered[1:]},
+		{},
+	}}
+
+	ctx := context.Background()
+	first := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	m.audit(ctx, first)
+	alert := m.Alert()
+	require.NotNil(t, alert)
+	require.Equal(t, "SoftwareTampered", alert.Reason)
+	require.Equal(t, "/opt/solo/weaver/sandbox/bin/kubelet failed verification: sha512 mismatch; "+
+		"2 installed files failed in total", alert.Message)
+	require.Equal(t, "sudo solo-provisioner software verify --all", alert.Resolution)
+	require.Equal(t, "2026-10-18T12:00:00Z", alert.Since)
+
+	m.audit(ctx, first.Add(time.Hour))
+	require.Equal(t, alert, m.Alert(), "an audit that cannot run keeps the last result")
+
+	m.audit(ctx, first.Add(2*time.Hour))
+	require.Equal(t, "2026-10-18T12:00:00Z", m.Alert().Since, "Since is when the audits started failing")
+
+	m.audit(ctx, first.Add(3*time.Hour))
+	require.Nil(t, m.Alert(), "a passing audit clears the alert")
+}
+
+func TestSoftwareAuditMonitor_AuditsEveryInterval(t *testing.T) {
+	fake := &auditFakeDelegator{results: []auditResult{{}}}
+	m := NewSoftwareAuditMonitor(10 * time.Millisecond)
+	m.delegator = fake
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return fake.calls.Load() >= 2 }, 5*time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -27,7 +27,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/automa-saga/daemonkit"
 )
@@ -81,11 +83,18 @@ type Delegator interface {
 	// clean re-attach.
 	TCDetach(ctx context.Context, veth string) error
 
+	// ShapeReapplyEgress delegates `network shape reapply --iface <nic>` — the
+	// egress link watcher's re-attach path. It re-resolves an "auto" egress rate
+	// and re-runs the bandwidth-shaper boot script when the rate changed or the
+	// live root hierarchy on nic is missing; an intact hierarchy is left alone.
+	ShapeReapplyEgress(ctx context.Context, nic string) error
+
 	// ReconcileShaper delegates `block node reconcile-shaper --statusz-url <url>`
 	// under sudo — the traffic-shaper poll loop's privileged apply path. The
 	// worker fetches statusz, diffs the live nft policy sets, and rewrites only
-	// the policies whose membership changed.
-	ReconcileShaper(ctx context.Context, statuszURL string) error
+	// the policies whose membership changed. A non-empty instance selects that
+	// block node instance's policy sets (`--release <instance>`).
+	ReconcileShaper(ctx context.Context, statuszURL, instance string) error
 
 	// ReconcileShaperCheck delegates the unprivileged
 	// `block node reconcile-shaper --statusz-url <url> --check --output json`
@@ -93,7 +102,28 @@ type Delegator interface {
 	// poll loop calls it every tick and only invokes the privileged
 	// ReconcileShaper when the digest changed, so a steady-state roster costs no
 	// root escalation.
-	ReconcileShaperCheck(ctx context.Context, statuszURL string) (digest string, err error)
+	ReconcileShaperCheck(ctx context.Context, statuszURL, instance string) (digest string, err error)
+
+	// ClusterBackup delegates `kube cluster backup --target <target> --keep
+	// <keep> --if-older-than <ifOlderThan>` — the cluster backup monitor's
+	// scheduled etcd snapshot. The CLI skips the snapshot while the newest
+	// backup under target is younger than ifOlderThan, so the schedule survives
+	// daemon restarts without the daemon reading the root-owned target.
+	ClusterBackup(ctx context.Context, target string, keep int, ifOlderThan time.Duration) error
+
+	// SoftwareVerify delegates `software verify --all --output json` — the
+	// software audit monitor's periodic integrity check — and returns the
+	// installed files that failed it. A failed audit is a result, not an error:
+	// err is non-nil only when the audit could not run or its report could not
+	// be parsed.
+	SoftwareVerify(ctx context.Context) ([]SoftwareVerifyFailure, error)
+}
+
+// SoftwareVerifyFailure is one installed file that failed `software verify`.
+type SoftwareVerifyFailure struct {
+	Artifact string   `json:"artifact"`
+	Path     string   `json:"path"`
+	Problems []string `json:"problems"`
 }
 
 // execDelegator is the production Delegator. Its resolution and exec seams are
@@ -185,7 +215,19 @@ func (d *execDelegator) TCDetach(ctx context.Context, veth string) error {
 	return d.tcAttach(ctx, veth, true)
 }
 
-func (d *execDelegator) ReconcileShaper(ctx context.Context, statuszURL string) error {
+func (d *execDelegator) ShapeReapplyEgress(ctx context.Context, nic string) error {
+	if strings.TrimSpace(nic) == "" {
+		return &daemonkit.ProbeError{
+			Reason:     "EgressInterfaceEmpty",
+			Message:    "network shape reapply requires a non-empty egress interface",
+			Resolution: "this is a daemon bug; report it with the daemon logs",
+		}
+	}
+	_, err := d.Run(ctx, "network", "shape", "reapply", "--iface", nic)
+	return err
+}
+
+func (d *execDelegator) ReconcileShaper(ctx context.Context, statuszURL, instance string) error {
 	if strings.TrimSpace(statuszURL) == "" {
 		return &daemonkit.ProbeError{
 			Reason:     "StatuszURLEmpty",
@@ -193,17 +235,28 @@ func (d *execDelegator) ReconcileShaper(ctx context.Context, statuszURL string)
 			Resolution: "this is a daemon bug; report it with the daemon logs",
 		}
 	}
-	_, err := d.Run(ctx, "block", "node", "reconcile-shaper", "--statusz-url", statuszURL)
+	_, err := d.Run(ctx, reconcileShaperArgs(statuszURL, instance)...)
 	return err
 }
 
+// reconcileShaperArgs is the reconcile-shaper worker invocation for the
+// policy sets of instance; the default instance (empty) passes no --release, so
+// its argv is unchanged from a single-instance host.
+func reconcileShaperArgs(statuszURL, instance string) []string {
+	args := []string{"block", "node", "reconcile-shaper", "--statusz-url", statuszURL}
+	if instance != "" {
+		args = append(args, "--release", instance)
+	}
+	return args
+}
+
 // ReconcileShaperCheck execs the reconcile-shaper worker's unprivileged --check
 // path directly (not under sudo): it fetches statusz and prints the
 // desired-membership digest as JSON without reading or writing any nft state, so
 // no escalation is needed. It parses `desired-digest` from that JSON and returns
 // it. The daemon-facing name of the CLI is resolved the same way Run resolves
 // it, so the sibling-preference and granted-path fallbacks apply identically.
-func (d *execDelegator) ReconcileShaperCheck(ctx context.Context, statuszURL string) (string, error) {
+func (d *execDelegator) ReconcileShaperCheck(ctx context.Context, statuszURL, instance string) (string, error) {
 	if strings.TrimSpace(statuszURL) == "" {
 		return "", &daemonkit.ProbeError{
 			Reason:     "StatuszURLEmpty",
@@ -220,7 +273,7 @@ func (d *execDelegator) ReconcileShaperCheck(ctx context.Context, statuszURL str
 	// No sudo: --check reads only statusz over HTTP and touches no nft state, so
 	// it runs as the unprivileged daemon user. --output json makes the digest
 	// machine-parseable.
-	args := []string{"block", "node", "reconcile-shaper", "--statusz-url", statuszURL, "--check", "--output", "json"}
+	args := append(reconcileShaperArgs(statuszURL, instance), "--check", "--output", "json")
 	out, err := d.output(ctx, cliBin, args...)
 	if err != nil {
 		return "", &daemonkit.ProbeError{
@@ -256,6 +309,71 @@ func (d *execDelegator) ReconcileShaperCheck(ctx context.Context, statuszURL str
 	return res.Digest, nil
 }
 
+func (d *execDelegator) ClusterBackup(ctx context.Context, target string, keep int, ifOlderThan time.Duration) error {
+	if strings.TrimSpace(target) == "" {
+		return &daemonkit.ProbeError{
+			Reason:     "BackupTargetEmpty",
+			Message:    "kube cluster backup requires a non-empty target directory",
+			Resolution: "this is a daemon bug; report it with the daemon logs",
+		}
+	}
+	_, err := d.Run(ctx, "kube", "cluster", "backup",
+		"--target", target,
+		"--keep", strconv.Itoa(keep),
+		"--if-older-than", ifOlderThan.String())
+	return err
+}
+
+func (d *execDelegator) SoftwareVerify(ctx context.Context) ([]SoftwareVerifyFailure, error) {
+	// The CLI exits non-zero when a file fails, so the report is parsed before
+	// the exec error is considered: only a missing report means the audit did
+	// not run.
+	out, runErr := d.Run(ctx, "software", "verify", "--all", "--output", "json")
+	report, ok := parseSoftwareVerifyReport(out)
+	if !ok {
+		if runErr != nil {
+			return nil, runErr
+		}
+		return nil, &daemonkit.ProbeError{
+			Reason:     "SoftwareVerifyParseFailed",
+			Message:    "could not parse the software verify --output json report",
+			Resolution: "this is a daemon/CLI contract bug; report it with the daemon logs",
+		}
+	}
+
+	var failures []SoftwareVerifyFailure
+	for _, f := range report.Files {
+		if f.Status == "fail" {
+			failures = append(failures, f.SoftwareVerifyFailure)
+		}
+	}
+	return failures, nil
+}
+
+// softwareVerifyReport is the part of the `software verify --output json`
+// report the daemon reads.
+type softwareVerifyReport struct {
+	Passed *bool `json:"passed"`
+	Files  []struct {
+		SoftwareVerifyFailure
+		Status string `json:"status"`
+	} `json:"files"`
+}
+
+// parseSoftwareVerifyReport finds the report in the CLI's stdout. In JSON
+// output mode the CLI also writes its log lines to stdout as NDJSON, so the
+// report is the last line that carries a "passed" field.
+func parseSoftwareVerifyReport(out []byte) (softwareVerifyReport, bool) {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		var report softwareVerifyReport
+		if json.Unmarshal([]byte(lines[i]), &report) == nil && report.Passed != nil {
+			return report, true
+		}
+	}
+	return softwareVerifyReport{}, false
+}
+
 // tcAttach delegates the `block node tc-attach --veth <veth> [--detach]` exec.
 // The veth-name format is validated by the CLI/shape layer the exec reaches;
 // here we only guard against an empty name so a daemon bug surfaces as a clear
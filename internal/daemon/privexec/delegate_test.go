@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/automa-saga/daemonkit"
 	"github.com/stretchr/testify/require"
@@ -142,6 +143,111 @@ func TestTCAttach_EmptyVethIsGuarded(t *testing.T) {
 	require.Empty(t, call.name, "exec must not run when the veth name is empty")
 }
 
+func TestShapeReapplyEgress_BuildsSudoArgv(t *testing.T) {
+	d, call := fakeDelegator(
+		[]string{"/usr/bin/sudo", "/opt/solo/weaver/bin/solo-provisioner"},
+		"/opt/solo/weaver/bin/solo-provisioner-daemon",
+		nil, nil,
+	)
+
+	require.NoError(t, d.ShapeReapplyEgress(context.Background(), "bond0"))
+	require.Equal(t, "/usr/bin/sudo", call.name)
+	require.Equal(t, []string{
+		"-n",
+		"/opt/solo/weaver/bin/solo-provisioner",
+		"network", "shape", "reapply", "--iface", "bond0",
+	}, call.args)
+}
+
+func TestShapeReapplyEgress_EmptyNICIsGuarded(t *testing.T) {
+	d, call := fakeDelegator([]string{"/usr/bin/sudo", "/usr/local/bin/solo-provisioner"}, "", nil, nil)
+
+	err := d.ShapeReapplyEgress(context.Background(), "")
+	require.Error(t, err)
+	var pe *daemonkit.ProbeError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "EgressInterfaceEmpty", pe.Reason)
+	require.Empty(t, call.name, "exec must not run when the interface is empty")
+}
+
+func TestClusterBackup_BuildsSudoArgv(t *testing.T) {
+	d, call := fakeDelegator(
+		[]string{"/usr/bin/sudo", "/opt/solo/weaver/bin/solo-provisioner"},
+		"/opt/solo/weaver/bin/solo-provisioner-daemon",
+		nil, nil,
+	)
+
+	require.NoError(t, d.ClusterBackup(context.Background(), "/opt/solo/weaver/backup/cluster", 7, 24*time.Hour))
+	require.Equal(t, "/usr/bin/sudo", call.name)
+	require.Equal(t, []string{
+		"-n",
+		"/opt/solo/weaver/bin/solo-provisioner",
+		"kube", "cluster", "backup",
+		"--target", "/opt/solo/weaver/backup/cluster",
+		"--keep", "7",
+		"--if-older-than", "24h0m0s",
+	}, call.args)
+}
+
+func TestClusterBackup_EmptyTargetIsGuarded(t *testing.T) {
+	d, call := fakeDelegator([]string{"/usr/bin/sudo", "/usr/local/bin/solo-provisioner"}, "", nil, nil)
+
+	err := d.ClusterBackup(context.Background(), " ", 7, time.Hour)
+	var pe *daemonkit.ProbeError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "BackupTargetEmpty", pe.Reason)
+	require.Empty(t, call.name, "exec must not run when the target is empty")
+}
+
+func TestSoftwareVerify_ReturnsFailuresFromReport(t *testing.T) {
+	// The CLI exits non-zero on a failed audit and logs NDJSON to stdout ahead
+	// of the report; the failures are still read from the report line.
+	stdout := []byte(`{"level":"info","message":"Loading catalog"}
+{"checkedAt":"2026-10-18T00:00:00Z","passed":false,"files":[` +
+		`{"artifact":"kubelet","kind":"binary","path":"/opt/solo/weaver/sandbox/bin/kubelet","status":"pass"},` +
+		`{"kind":"unexpected","path":"/opt/solo/weaver/sandbox/bin/dropped","status":"fail","problems":["not installed by any recorded software"]}]}
+`)
+	d, call := fakeDelegator(
+		[]string{"/usr/bin/sudo", "/opt/solo/weaver/bin/solo-provisioner"},
+		"/opt/solo/weaver/bin/solo-provisioner-daemon",
+		stdout, errors.New("exit status 1"),
+	)
+
+	failures, err := d.SoftwareVerify(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []SoftwareVerifyFailure{{
+		Path:     "/opt/solo/weaver/sandbox/bin/dropped",
+		Problems: []string{"not installed by any recorded software"},
+	}}, failures)
+	require.Equal(t, "/usr/bin/sudo", call.name)
+	require.Equal(t, []string{
+		"-n",
+		"/opt/solo/weaver/bin/solo-provisioner",
+		"software", "verify", "--all", "--output", "json",
+	}, call.args)
+}
+
+func TestSoftwareVerify_NoReportIsAnError(t *testing.T) {
+	d, _ := fakeDelegator(
+		[]string{"/usr/bin/sudo", "/opt/solo/weaver/bin/solo-provisioner"},
+		"/opt/solo/weaver/bin/solo-provisioner-daemon",
+		nil, errors.New("exit status 1"),
+	)
+	_, err := d.SoftwareVerify(context.Background())
+	var pe *daemonkit.ProbeError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "PrivilegedExecFailed", pe.Reason)
+
+	d, _ = fakeDelegator(
+		[]string{"/usr/bin/sudo", "/opt/solo/weaver/bin/solo-provisioner"},
+		"/opt/solo/weaver/bin/solo-provisioner-daemon",
+		[]byte(`{"level":"info"}`), nil,
+	)
+	_, err = d.SoftwareVerify(context.Background())
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "SoftwareVerifyParseFailed", pe.Reason)
+}
+
 func TestReconcileShaper_BuildsSudoArgv(t *testing.T) {
 	d, call := fakeDelegator(
 		[]string{"/usr/bin/sudo", "/opt/solo/weaver/bin/solo-provisioner"},
@@ -149,7 +255,7 @@ func TestReconcileShaper_BuildsSudoArgv(t *testing.T) {
 		nil, nil,
 	)
 
-	require.NoError(t, d.ReconcileShaper(context.Background(), "http://127.0.0.1:8080"))
+	require.NoError(t, d.ReconcileShaper(context.Background(), "http://127.0.0.1:8080", ""))
 	require.Equal(t, "/usr/bin/sudo", call.name)
 	require.Equal(t, []string{
 		"-n",
@@ -158,10 +264,26 @@ func TestReconcileShaper_BuildsSudoArgv(t *testing.T) {
 	}, call.args)
 }
 
+func TestReconcileShaper_PassesInstanceAsRelease(t *testing.T) {
+	d, call := fakeDelegator(
+		[]string{"/usr/bin/sudo", "/opt/solo/weaver/bin/solo-provisioner"},
+		"/opt/solo/weaver/bin/solo-provisioner-daemon",
+		nil, nil,
+	)
+
+	require.NoError(t, d.ReconcileShaper(context.Background(), "http://127.0.0.1:8080", "bn-testnet"))
+	require.Equal(t, []string{
+		"-n",
+		"/opt/solo/weaver/bin/solo-provisioner",
+		"block", "node", "reconcile-shaper", "--statusz-url", "http://127.0.0.1:8080",
+		"--release", "bn-testnet",
+	}, call.args)
+}
+
 func TestReconcileShaper_EmptyURLIsGuarded(t *testing.T) {
 	d, call := fakeDelegator([]string{"/usr/bin/sudo", "/usr/local/bin/solo-provisioner"}, "", nil, nil)
 
-	err := d.ReconcileShaper(context.Background(), "  ")
+	err := d.ReconcileShaper(context.Background(), "  ", "")
 	require.Error(t, err)
 	var pe *daemonkit.ProbeError
 	require.ErrorAs(t, err, &pe)
@@ -178,7 +300,7 @@ func TestReconcileShaperCheck_BuildsUnprivilegedArgvAndParsesDigest(t *testing.T
 		[]byte(`{"desired-digest":"abc123","desired":{}}`), nil,
 	)
 
-	digest, err := d.ReconcileShaperCheck(context.Background(), "http://127.0.0.1:8080")
+	digest, err := d.ReconcileShaperCheck(context.Background(), "http://127.0.0.1:8080", "")
 	require.NoError(t, err)
 	require.Equal(t, "abc123", digest)
 
@@ -195,7 +317,7 @@ func TestReconcileShaperCheck_BuildsUnprivilegedArgvAndParsesDigest(t *testing.T
 func TestReconcileShaperCheck_EmptyURLIsGuarded(t *testing.T) {
 	d, call := fakeDelegator([]string{"/usr/local/bin/solo-provisioner"}, "", nil, nil)
 
-	_, err := d.ReconcileShaperCheck(context.Background(), "")
+	_, err := d.ReconcileShaperCheck(context.Background(), "", "")
 	require.Error(t, err)
 	var pe *daemonkit.ProbeError
 	require.ErrorAs(t, err, &pe)
@@ -210,7 +332,7 @@ func TestReconcileShaperCheck_BadJSONReportsParseError(t *testing.T) {
 		[]byte("not json at all"), nil,
 	)
 
-	_, err := d.ReconcileShaperCheck(context.Background(), "http://127.0.0.1:8080")
+	_, err := d.ReconcileShaperCheck(context.Background(), "http://127.0.0.1:8080", "")
 	require.Error(t, err)
 	var pe *daemonkit.ProbeError
 	require.ErrorAs(t, err, &pe)
@@ -226,7 +348,7 @@ func TestReconcileShaperCheck_EmptyDigestReportsContractError(t *testing.T) {
 		[]byte(`{"desired":{}}`), nil,
 	)
 
-	_, err := d.ReconcileShaperCheck(context.Background(), "http://127.0.0.1:8080")
+	_, err := d.ReconcileShaperCheck(context.Background(), "http://127.0.0.1:8080", "")
 	require.Error(t, err)
 	var pe *daemonkit.ProbeError
 	require.ErrorAs(t, err, &pe)
@@ -243,7 +365,7 @@ func TestReconcileShaperCheck_ExecFailureReportsProbeError(t *testing.T) {
 		nil, exitErr,
 	)
 
-	_, err := d.ReconcileShaperCheck(context.Background(), "http://127.0.0.1:8080")
+	_, err := d.ReconcileShaperCheck(context.Background(), "http://127.0.0.1:8080", "")
 	require.Error(t, err)
 	var pe *daemonkit.ProbeError
 	require.ErrorAs(t, err, &pe)
@@ -8,8 +8,24 @@ import "github.com/automa-saga/daemonkit"
 const (
 	ComponentNameConsensusNode = "consensus-node"
 	ComponentNameBlockNode     = "block-node"
+	ComponentNameCluster       = "cluster"
+	ComponentNameHost          = "host"
 )
 
+// AlertSoftwareIntegrity keys the software audit alert in
+// StatusResponse.Alerts.
+const AlertSoftwareIntegrity = "software-integrity"
+
+// BlockNodeComponentName returns the component name of a block node instance:
+// ComponentNameBlockNode for the default instance (empty), and
+// "block-node/<instance>" for every other one.
+func BlockNodeComponentName(instance string) string {
+	if instance == "" {
+		return ComponentNameBlockNode
+	}
+	return ComponentNameBlockNode + "/" + instance
+}
+
 // HealthResponse is returned by GET /health.
 type HealthResponse struct {
 	Status string `json:"status"`
@@ -30,6 +46,10 @@ type StatusResponse struct {
 	// Each entry includes a Reason code, the error Message, an operator-actionable
 	// Resolution hint, and the Since timestamp of when the failure was first seen.
 	ProbeErrors map[string]daemonkit.StatusError `json:"probe_errors,omitempty"`
+	// Alerts maps a host-wide check (e.g. AlertClusterCertificates) to the
+	// condition the operator has to act on before it becomes an outage. Empty
+	// when nothing needs attention.
+	Alerts map[string]daemonkit.StatusError `json:"alerts,omitempty"`
 }
 
 // ComponentStatus holds the per-monitor states for one component.
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package certs reads the expiry of the kubeadm cluster's certificates: the
+// PKI kubeadm issues under the sandbox (certificatesDir in kubeadm-init.yaml)
+// and the client certificates embedded in the kubeconfigs it writes next to it.
+//
+// kubeadm issues the leaf certificates for one year (certificateValidityPeriod)
+// and the CAs for ten; a cluster whose leaf certificates lapse loses its API
+// server. `kube cluster certs check` reports them, `kube cluster certs renew`
+// renews them and the daemon alerts on GET /status as they near expiry.
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultWarnWithin is how close to expiry a certificate is reported as
+// expiring: 30 days, the window a monthly maintenance round still catches.
+const DefaultWarnWithin = 30 * 24 * time.Hour
+
+// kubeconfigNames are the kubeconfigs kubeadm writes into the kubernetes
+// directory with an embedded client certificate. kubelet.conf is left out: it
+// points at the kubelet's own certificate, which the kubelet rotates itself.
+var kubeconfigNames = []string{"admin.conf", "super-admin.conf", "controller-manager.conf", "scheduler.conf"}
+
+// Certificate is the expiry of one certificate.
+type Certificate struct {
+	// Name is the certificate's file name relative to the PKI directory
+	// (e.g. "apiserver.crt", "etcd/server.crt") or its kubeconfig's name.
+	Name string `json:"name"`
+	// Path is the file the certificate was read from.
+	Path     string    `json:"path"`
+	Subject  string    `json:"subject"`
+	CA       bool      `json:"ca"`
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// Remaining returns the time left until the certificate expires, negative
+// once it has.
+func (c Certificate) Remaining(now time.Time) time.Duration {
+	return c.NotAfter.Sub(now)
+}
+
+// DaysRemaining returns the whole days left until the certificate expires,
+// rounded down; an expired certificate has a negative count.
+func (c Certificate) DaysRemaining(now time.Time) int {
+	left := c.Remaining(now)
+	if left < 0 {
+		return -int((-left + 24*time.Hour - 1) / (24 * time.Hour))
+	}
+	return int(left / (24 * time.Hour))
+}
+
+// Kubeconfigs returns the kubeadm kubeconfigs of kubernetesDir (the directory
+// holding admin.conf) that exist.
+func Kubeconfigs(kubernetesDir string) []string {
+	var paths []string
+	for _, name := range kubeconfigNames {
+		p := filepath.Join(kubernetesDir, name)
+		if _, err := os.Stat(p); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// Inspect reads every certificate (*.crt) under pkiDir, including the etcd
+// PKI below it, and the client certificate embedded in each of kubeconfigs.
+// The result is sorted soonest expiry first. A missing pkiDir is a NotFound
+// error: the cluster was never initialised on this host.
+func Inspect(pkiDir string, kubeconfigs ...string) ([]Certificate, error) {
+	if _, err := os.Stat(pkiDir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errorx.DataUnavailable.New("no cluster PKI at %s: the cluster is not initialised on this host", pkiDir)
+		}
+		return nil, errorx.ExternalError.Wrap(err, "failed to read cluster PKI %s", pkiDir)
+	}
+
+	var out []Certificate
+	err := filepath.WalkDir(pkiDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".crt" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		cert, err := parsePEM(data)
+		if err != nil {
+			return errorx.IllegalFormat.Wrap(err, "failed to parse certificate %s", path)
+		}
+		rel, _ := filepath.Rel(pkiDir, path)
+		out = append(out, newCertificate(filepath.ToSlash(rel), path, cert))
+		return nil
+	})
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read cluster PKI %s", pkiDir)
+	}
+
+	for _, path := range kubeconfigs {
+		c, err := kubeconfigCertificate(path)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			out = append(out, *c)
+		}
+	}
+
+	slices.SortStableFunc(out, func(a, b Certificate) int {
+		if c := a.NotAfter.Compare(b.NotAfter); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out, nil
+}
+
+// kubeconfigCertificate returns the client certificate of the kubeconfig's
+// current context, nil when it has none embedded.
+func kubeconfigCertificate(path string) (*Certificate, error) {
+	cfg, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "failed to load kubeconfig %s", path)
+	}
+	kctx, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return nil, nil
+	}
+	auth, ok := cfg.AuthInfos[kctx.AuthInfo]
+	if !ok || len(auth.ClientCertificateData) == 0 {
+		return nil, nil
+	}
+	cert, err := parsePEM(auth.ClientCertificateData)
+	if err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "failed to parse the client certificate of kubeconfig %s", path)
+	}
+	c := newCertificate(filepath.Base(path), path, cert)
+	return &c, nil
+}
+
+// parsePEM parses the first certificate of a PEM bundle.
+func parsePEM(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("no PEM certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+func newCertificate(name, path string, cert *x509.Certificate) Certificate {
+	return Certificate{
+		Name:     name,
+		Path:     path,
+		Subject:  cert.Subject.CommonName,
+		CA:       cert.IsCA,
+		NotAfter: cert.NotAfter.UTC(),
+	}
+}
+
+// Expiring returns the certificates of certs that expire within warnWithin of
+// now, expired ones included, in the order of certs.
+func Expiring(certs []Certificate, now time.Time, warnWithin time.Duration) []Certificate {
+	var out []Certificate
+	for _, c := range certs {
+		if c.Remaining(now) <= warnWithin {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// WriteReport prints certs as a table with the days each has left, marking
+// the ones that expire within warnWithin of now.
+func WriteReport(w io.Writer, certs []Certificate, now time.Time, warnWithin time.Duration) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "CERTIFICATE\tEXPIRES\tDAYS LEFT\tCA\tSTATUS")
+	for _, c := range certs {
+		status := "ok"
+		switch {
+		case c.Remaining(now) <= 0:
+			status = "EXPIRED"
+		case c.Remaining(now) <= warnWithin:
+			status = "expiring"
+		}
+		ca := "no"
+		if c.CA {
+			ca = "yes"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n",
+			c.Name, c.NotAfter.Format(time.RFC3339), c.DaysRemaining(now), ca, status)
+	}
+	_ = tw.Flush()
+}
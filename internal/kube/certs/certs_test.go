@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package certs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func certPEM(t *testing.T, cn string, ca bool, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  ca,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func writeKubeconfig(t *testing.T, path string, cert []byte) {
+	t.Helper()
+	cfg := clientcmdapi.NewConfig()
+	cfg.AuthInfos["kubernetes-admin"] = &clientcmdapi.AuthInfo{ClientCertificateData: cert}
+	cfg.Contexts["kubernetes-admin@k8s"] = &clientcmdapi.Context{Cluster: "k8s", AuthInfo: "kubernetes-admin"}
+	cfg.CurrentContext = "kubernetes-admin@k8s"
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, clientcmd.WriteToFile(*cfg, path))
+}
+
+func TestInspect(t *testing.T) {
+	dir := t.TempDir()
+	pki := filepath.Join(dir, "pki")
+	writeFile(t, filepath.Join(pki, "ca.crt"), certPEM(t, "kubernetes", true, now.AddDate(9, 0, 0)))
+	writeFile(t, filepath.Join(pki, "apiserver.crt"), certPEM(t, "kube-apiserver", false, now.AddDate(0, 0, 12)))
+	writeFile(t, filepath.Join(pki, "apiserver.key"), []byte("not a certificate"))
+	writeFile(t, filepath.Join(pki, "etcd", "server.crt"), certPEM(t, "etcd", false, now.AddDate(0, 6, 0)))
+	writeKubeconfig(t, filepath.Join(dir, "admin.conf"), certPEM(t, "kubernetes-admin", false, now.Add(-time.Hour)))
+
+	kubeconfigs := Kubeconfigs(dir)
+	require.Equal(t, []string{filepath.Join(dir, "admin.conf")}, kubeconfigs, "only the kubeconfigs present are returned")
+
+	got, err := Inspect(pki, kubeconfigs...)
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+
+	var names []string
+	for _, c := range got {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"admin.conf", "apiserver.crt", "etcd/server.crt", "ca.crt"}, names, "soonest expiry first")
+	assert.Equal(t, "kubernetes-admin", got[0].Subject)
+	assert.Equal(t, -1, got[0].DaysRemaining(now))
+	assert.Equal(t, 12, got[1].DaysRemaining(now))
+	assert.True(t, got[3].CA)
+
+	expiring := Expiring(got, now, DefaultWarnWithin)
+	require.Len(t, expiring, 2)
+	assert.Equal(t, "apiserver.crt", expiring[1].Name)
+}
+
+func TestInspect_Errors(t *testing.T) {
+	_, err := Inspect(filepath.Join(t.TempDir(), "pki"))
+	require.ErrorContains(t, err, "not initialised")
+
+	pki := t.TempDir()
+	writeFile(t, filepath.Join(pki, "ca.crt"), []byte("garbage"))
+	_, err = Inspect(pki)
+	require.ErrorContains(t, err, "failed to parse certificate")
+}
+
+func TestWriteReport(t *testing.T) {
+	certs := []Certificate{
+		{Name: "admin.conf", NotAfter: now.Add(-time.Hour)},
+		{Name: "apiserver.crt", NotAfter: now.AddDate(0, 0, 12)},
+		{Name: "ca.crt", CA: true, NotAfter: now.AddDate(9, 0, 0)},
+	}
+	var out bytes.Buffer
+	WriteReport(&out, certs, now, DefaultWarnWithin)
+	assert.Equal(t, `CERTIFICATE    EXPIRES               DAYS LEFT  CA   STATUS
+admin.conf     2026-10-01T11:00:00Z  -1         no   EXPIRED
+apiserver.crt  2026-10-13T12:00:00Z  12         no   expiring
+ca.crt         2035-10-01T12:00:00Z  3287       yes  ok
+`, out.String())
+}
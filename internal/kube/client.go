@@ -5,6 +5,7 @@ package kube
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"io"
 	"os"
@@ -18,6 +19,7 @@ import (
 	"github.com/joomcode/errorx"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +31,7 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
@@ -155,6 +158,9 @@ type CheckFunc func(obj *unstructured.Unstructured, err error) (bool, error)
 type Client struct {
 	Dyn    dynamic.Interface
 	Mapper *restmapper.DeferredDiscoveryRESTMapper
+	// Core is the typed client for the subresources the dynamic client cannot
+	// reach (pod logs). Nil in clients built without NewClient.
+	Core kubernetes.Interface
 }
 
 // ClientProviderFromContext is a function that provides a kube client instance from context.
@@ -200,7 +206,22 @@ func NewClient() (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newClientForConfig(config)
+}
+
+// NewClientForKubeconfig creates a Kubernetes client from the kubeconfig file
+// at path, e.g. the kubelet's on a worker node, which has no admin kubeconfig.
+func NewClientForKubeconfig(path string) (*Client, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", path)
+	if err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "failed to load kubeconfig at %s", path)
+	}
+	return newClientForConfig(config)
+}
 
+// newClientForConfig prepares the dynamic client, discovery mapper and core
+// client for config.
+func newClientForConfig(config *rest.Config) (*Client, error) {
 	// Create dynamic client
 	dyn, err := dynamic.NewForConfig(config)
 	if err != nil {
@@ -217,12 +238,18 @@ func NewClient() (*Client, error) {
 	memcache := memory.NewMemCacheClient(disco)
 	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memcache)
 
+	core, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to create core client")
+	}
+
 	// Warm discovery cache to avoid first-call latency
 	_, _ = mapper.ResourcesFor(schema.GroupVersionResource{Resource: "pods"})
 
 	return &Client{
 		Dyn:    dyn,
 		Mapper: mapper,
+		Core:   core,
 	}, nil
 }
 
@@ -330,6 +357,28 @@ func (c *Client) processResources(ctx context.Context, objs []*unstructured.Unst
 // Apply / Delete Manifests
 // =====================
 
+// FieldManager is the server-side apply field manager weaver owns the fields
+// of its manifests under. Fields owned by any other manager (kubectl edit,
+// kubectl apply, a controller) are hand edits or controller state.
+const FieldManager = "solo-weaver"
+
+// applyOptions holds the settings ApplyManifest is called with.
+type applyOptions struct {
+	force  bool
+	record string
+}
+
+// ApplyOption configures ApplyManifest.
+type ApplyOption func(*applyOptions)
+
+// WithForceConflicts sets whether ApplyManifest takes ownership of fields
+// another field manager owns (true, the default) or fails with an
+// errorx.IllegalState naming the conflicting fields and managers (false), so
+// a hand edit is not silently overwritten.
+func WithForceConflicts(force bool) ApplyOption {
+	return func(o *applyOptions) { o.force = force }
+}
+
 // ApplyManifest applies resources defined in the given manifest file using
 // Server-Side Apply (SSA). A single PATCH per resource replaces the previous
 // Get → Create/Update two-step, eliminating the 409 Conflict race that occurs
@@ -338,17 +387,24 @@ func (c *Client) processResources(ctx context.Context, objs []*unstructured.Unst
 //
 // SSA rules that matter here:
 //   - No resourceVersion is sent — the API server handles optimistic concurrency.
-//   - fieldManager "solo-weaver" declares ownership of every field in the manifest.
-//   - force:true tells the API server to accept the manifest's values even when
-//     they conflict with fields managed by other actors, resolving ownership
-//     conflicts in favor of "solo-weaver" and potentially changing existing values.
+//   - FieldManager declares ownership of every field in the manifest.
+//   - By default force:true tells the API server to accept the manifest's
+//     values even when they conflict with fields managed by other actors,
+//     resolving ownership conflicts in favor of FieldManager and potentially
+//     changing existing values. WithForceConflicts(false) fails instead.
 //   - Create-or-update is handled automatically by the API server; no explicit
 //     IsNotFound branch is needed.
 //
 // Manifests must only contain desired-state fields (no status, resourceVersion,
 // uid, managedFields). The block-node storage-config templates already satisfy
-// this requirement.
-func (c *Client) ApplyManifest(ctx context.Context, manifestPath string) error {
+// this requirement. DiffManifest reports what an apply would change, and
+// WithRecord keeps the applied manifest for DiffRecordedManifests.
+func (c *Client) ApplyManifest(ctx context.Context, manifestPath string, opts ...ApplyOption) error {
+	o := applyOptions{force: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	objs, err := parseManifests(manifestPath)
 	if err != nil {
 		return err
@@ -360,23 +416,43 @@ func (c *Client) ApplyManifest(ctx context.Context, manifestPath string) error {
 			return errorx.InternalError.Wrap(err, "error marshaling %s/%s for server-side apply", mapping.Resource.Resource, u.GetName())
 		}
 
-		force := true
+		force := o.force
 		if _, err := dr.Patch(ctx, u.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
-			FieldManager: "solo-weaver",
+			FieldManager: FieldManager,
 			Force:        &force,
 		}); err != nil {
-			if kerrors.IsBadRequest(err) || kerrors.IsInvalid(err) {
-				return errorx.IllegalArgument.Wrap(err, "error during apply %s/%s", mapping.Resource.Resource, u.GetName())
-			}
-			if kerrors.IsForbidden(err) || kerrors.IsUnauthorized(err) {
-				return errorx.ExternalError.Wrap(err, "error during apply %s/%s", mapping.Resource.Resource, u.GetName())
-			}
-			return errorx.InternalError.Wrap(err, "error during apply %s/%s", mapping.Resource.Resource, u.GetName())
+			return applyError(err, mapping, u)
 		}
 		return nil
 	}
 
-	return c.processResources(ctx, objs, handler)
+	if err := c.processResources(ctx, objs, handler); err != nil {
+		return err
+	}
+	if o.record != "" {
+		return recordManifest(o.record, manifestPath)
+	}
+	return nil
+}
+
+// applyError maps a server-side apply failure onto the errorx type callers
+// branch on.
+func applyError(err error, mapping *meta.RESTMapping, u *unstructured.Unstructured) error {
+	if kerrors.IsConflict(err) {
+		return errorx.IllegalState.Wrap(err, "%s/%s has fields owned by another field manager: %s",
+			mapping.Resource.Resource, u.GetName(), strings.Join(applyConflicts(err), "; ")).
+			WithProperty(models.ErrPropertyResolution, []string{
+				"the object was changed outside weaver, e.g. with kubectl edit",
+				"move the change into weaver's configuration, or re-apply forcing conflicts to take the fields back",
+			})
+	}
+	if kerrors.IsBadRequest(err) || kerrors.IsInvalid(err) {
+		return errorx.IllegalArgument.Wrap(err, "error during apply %s/%s", mapping.Resource.Resource, u.GetName())
+	}
+	if kerrors.IsForbidden(err) || kerrors.IsUnauthorized(err) {
+		return errorx.ExternalError.Wrap(err, "error during apply %s/%s", mapping.Resource.Resource, u.GetName())
+	}
+	return errorx.InternalError.Wrap(err, "error during apply %s/%s", mapping.Resource.Resource, u.GetName())
 }
 
 // DeleteManifest deletes resources defined in the given manifest file
@@ -565,6 +641,35 @@ func (c *Client) GetSecretKeys(ctx context.Context, namespace, name string) ([]s
 	return keys, nil
 }
 
+// GetSecretData returns the decoded data of a Kubernetes Secret.
+// Returns nil and no error if the secret does not exist.
+func (c *Client) GetSecretData(ctx context.Context, namespace, name string) (map[string]string, error) {
+	dr := c.Dyn.Resource(schema.GroupVersionResource{
+		Group:    "",
+		Version:  "v1",
+		Resource: "secrets",
+	}).Namespace(namespace)
+
+	obj, err := dr.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errorx.InternalError.Wrap(err, "failed to get Secret %s/%s", namespace, name)
+	}
+
+	data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+	out := make(map[string]string, len(data))
+	for k, v := range data {
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, errorx.IllegalFormat.Wrap(err, "failed to decode key %s of Secret %s/%s", k, namespace, name)
+		}
+		out[k] = string(decoded)
+	}
+	return out, nil
+}
+
 // =====================
 // Generic WaitFor
 // =====================
@@ -651,6 +756,44 @@ func (c *Client) List(ctx context.Context, kind ResourceKind, namespace string,
 	return filtered, nil
 }
 
+// PodLogOptions selects the part of a container log PodLogs returns.
+type PodLogOptions struct {
+	Container string
+	// Previous returns the log of the previous, terminated instance of the
+	// container.
+	Previous bool
+	// Since, when non-zero, drops the lines older than this.
+	Since time.Duration
+	// TailLines, when positive, keeps only the last this many lines.
+	TailLines int64
+}
+
+// PodLogs returns the log of a container of a pod.
+func (c *Client) PodLogs(ctx context.Context, namespace, pod string, opts PodLogOptions) ([]byte, error) {
+	if c.Core == nil {
+		return nil, errorx.IllegalState.New("kube client has no core client to read pod logs with")
+	}
+
+	logOpts := &corev1.PodLogOptions{
+		Container:  opts.Container,
+		Previous:   opts.Previous,
+		Timestamps: true,
+	}
+	if opts.Since > 0 {
+		seconds := int64(opts.Since.Seconds())
+		logOpts.SinceSeconds = &seconds
+	}
+	if opts.TailLines > 0 {
+		logOpts.TailLines = &opts.TailLines
+	}
+
+	out, err := c.Core.CoreV1().Pods(namespace).GetLogs(pod, logOpts).DoRaw(ctx)
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read logs of pod %s/%s container %s", namespace, pod, opts.Container)
+	}
+	return out, nil
+}
+
 // WaitForResources waits until all resources of the given kind in the specified namespace
 // satisfy the condition defined by checkFn within the timeout.
 // It returns an error if the timeout is reached or if any fatal API errors occur.
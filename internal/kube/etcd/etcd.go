@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package etcd backs up and restores the kubeadm cluster's control plane.
+//
+// A cluster backup is a BackupKind directory in the internal/backup format
+// (a manifest.json and one checksummed tar/zstd archive per volume) holding:
+//   - snapshot: an etcd snapshot taken with the sandboxed etcd client certs
+//   - pki: the cluster PKI (certificatesDir in kubeadm-init.yaml)
+//   - kubeadm: the kubeadm-init.yaml the cluster was initialised with
+//   - tools: the etcdutl of the running etcd, so a host whose etcd no longer
+//     starts can still be restored with a matching version
+//
+// etcd runs as a static pod from a distroless image, so the etcdctl and
+// etcdutl binaries are run from the etcd container's root filesystem on the
+// host rather than through `crictl exec`.
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/joomcode/errorx"
+	"gopkg.in/yaml.v3"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+const (
+	// BackupRelease names cluster backups <BackupRelease>-etcd-<timestamp>.
+	BackupRelease = "cluster"
+
+	// BackupKind marks a backup of the cluster's control plane — an etcd
+	// snapshot with the PKI and kubeadm config — written by
+	// `kube cluster backup`. Block node restore refuses it.
+	BackupKind = "etcd"
+
+	// Volume names inside a cluster backup.
+	VolumeSnapshot = "snapshot"
+	VolumePKI      = "pki"
+	VolumeKubeadm  = "kubeadm"
+	VolumeTools    = "tools"
+
+	snapshotFile      = "snapshot.db"
+	kubeadmConfigFile = "kubeadm-init.yaml"
+	etcdctlBin        = "etcdctl"
+	etcdutlBin        = "etcdutl"
+
+	// containerBinDir is where the etcd image installs its binaries.
+	containerBinDir = "usr/local/bin"
+
+	// DefaultKeep is how many cluster backups are retained by default.
+	DefaultKeep = 7
+)
+
+// DefaultBackupTarget returns the directory cluster backups are written under
+// unless another target is given.
+func DefaultBackupTarget(paths models.WeaverPaths) string {
+	return filepath.Join(paths.BackupDir, BackupRelease)
+}
+
+// Layout is where the sandboxed cluster keeps the files a backup reads and a
+// restore writes.
+type Layout struct {
+	// PKIDir is the cluster's certificatesDir.
+	PKIDir string
+	// DataDir is the etcd data directory (etcd.local.dataDir).
+	DataDir string
+	// KubeadmConfig is the kubeadm-init.yaml the cluster was initialised with.
+	KubeadmConfig string
+	// Manifest is the etcd static pod manifest.
+	Manifest string
+	// Crictl is the sandboxed crictl binary and CRISocket the CRI-O socket.
+	Crictl    string
+	CRISocket string
+}
+
+// NewLayout returns the layout of the cluster sandboxed under paths.
+func NewLayout(paths models.WeaverPaths) Layout {
+	return Layout{
+		PKIDir:        filepath.Join(paths.SandboxDir, "etc", "kubernetes", "pki"),
+		DataDir:       filepath.Join(paths.SandboxDir, "var", "lib", "etcd"),
+		KubeadmConfig: filepath.Join(paths.SandboxDir, "etc", "weaver", kubeadmConfigFile),
+		Manifest:      filepath.Join(paths.SandboxDir, "etc", "kubernetes", "manifests", "etcd.yaml"),
+		Crictl:        filepath.Join(paths.SandboxLocalBinDir, "crictl"),
+		CRISocket:     "unix://" + filepath.Join(paths.SandboxDir, "var", "run", "crio", "crio.sock"),
+	}
+}
+
+// Member is the identity of the cluster's etcd member as its static pod
+// manifest declares it: a snapshot is restored as the same member so the
+// restarted etcd accepts the data directory.
+type Member struct {
+	Name                     string
+	InitialCluster           string
+	InitialAdvertisePeerURLs string
+	// Endpoint is the client URL etcdctl connects to.
+	Endpoint string
+}
+
+// ReadMember reads the etcd member from the static pod manifest at path.
+func ReadMember(path string) (Member, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Member{}, errorx.IllegalState.New("no etcd static pod manifest at %s: the cluster is not initialised on this host", path).
+				WithProperty(models.ErrPropertyResolution, "install the cluster first: sudo solo-provisioner kube cluster install")
+		}
+		return Member{}, errorx.ExternalError.Wrap(err, "failed to read etcd manifest %s", path)
+	}
+	var pod struct {
+		Spec struct {
+			Containers []struct {
+				Name    string   `yaml:"name"`
+				Command []string `yaml:"command"`
+			} `yaml:"containers"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(raw, &pod); err != nil {
+		return Member{}, errorx.IllegalFormat.Wrap(err, "malformed etcd manifest %s", path)
+	}
+
+	flags := map[string]string{}
+	for _, c := range pod.Spec.Containers {
+		if c.Name != "etcd" {
+			continue
+		}
+		for _, arg := range c.Command {
+			if k, v, ok := strings.Cut(strings.TrimPrefix(arg, "--"), "="); ok && strings.HasPrefix(arg, "--") {
+				flags[k] = v
+			}
+		}
+	}
+	m := Member{
+		Name:                     flags["name"],
+		InitialCluster:           flags["initial-cluster"],
+		InitialAdvertisePeerURLs: flags["initial-advertise-peer-urls"],
+	}
+	if urls := flags["listen-client-urls"]; urls != "" {
+		m.Endpoint, _, _ = strings.Cut(urls, ",")
+	}
+	if m.Name == "" || m.InitialCluster == "" || m.InitialAdvertisePeerURLs == "" || m.Endpoint == "" {
+		return Member{}, errorx.IllegalFormat.New(
+			"etcd manifest %s lacks --name, --initial-cluster, --initial-advertise-peer-urls or --listen-client-urls", path)
+	}
+	return m, nil
+}
+
+// containerRootFS returns the host path of a container's root filesystem
+// from its `crictl inspect` output.
+func containerRootFS(inspect []byte) (string, error) {
+	var out struct {
+		Info struct {
+			RuntimeSpec struct {
+				Root struct {
+					Path string `json:"path"`
+				} `json:"root"`
+			} `json:"runtimeSpec"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(inspect, &out); err != nil {
+		return "", errorx.IllegalFormat.Wrap(err, "failed to parse crictl inspect output")
+	}
+	if out.Info.RuntimeSpec.Root.Path == "" {
+		return "", errorx.IllegalFormat.New("crictl inspect output carries no root filesystem path")
+	}
+	return out.Info.RuntimeSpec.Root.Path, nil
+}
+
+// execCommand runs name with args and returns its stdout; a failure carries
+// the command's stderr.
+func execCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, errorx.ExternalError.Wrap(err, "%s %s failed: %s",
+			filepath.Base(name), strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package etcd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+const manifestYAML = `apiVersion: v1
+kind: Pod
+metadata:
+  name: etcd
+  namespace: kube-system
+spec:
+  containers:
+  - name: etcd
+    command:
+    - etcd
+    - --advertise-client-urls=https://10.0.0.5:2379
+    - --data-dir=/var/lib/etcd
+    - --initial-advertise-peer-urls=https://10.0.0.5:2380
+    - --initial-cluster=node-1=https://10.0.0.5:2380
+    - --listen-client-urls=https://127.0.0.1:2379,https://10.0.0.5:2379
+    - --name=node-1
+`
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+func TestReadMember(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "etcd.yaml")
+	writeFile(t, path, manifestYAML)
+
+	m, err := ReadMember(path)
+	require.NoError(t, err)
+	assert.Equal(t, Member{
+		Name:                     "node-1",
+		InitialCluster:           "node-1=https://10.0.0.5:2380",
+		InitialAdvertisePeerURLs: "https://10.0.0.5:2380",
+		Endpoint:                 "https://127.0.0.1:2379",
+	}, m)
+
+	_, err = ReadMember(filepath.Join(dir, "missing.yaml"))
+	require.ErrorContains(t, err, "not initialised")
+
+	writeFile(t, path, strings.ReplaceAll(manifestYAML, "    - --name=node-1\n", ""))
+	_, err = ReadMember(path)
+	require.ErrorContains(t, err, "lacks --name")
+}
+
+func TestContainerRootFS(t *testing.T) {
+	root, err := containerRootFS([]byte(`{"info":{"runtimeSpec":{"root":{"path":"/var/lib/containers/abc/merged"}}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "/var/lib/containers/abc/merged", root)
+
+	_, err = containerRootFS([]byte(`{"info":{}}`))
+	require.Error(t, err)
+}
+
+// fakeCluster stands in for crictl, etcdctl and etcdutl on a sandbox in a
+// temporary directory.
+type fakeCluster struct {
+	t       *testing.T
+	layout  Layout
+	rootfs  string
+	calls   []string
+	stopped []string
+	started []string
+}
+
+func newFakeCluster(t *testing.T) (*Manager, *fakeCluster) {
+	home := t.TempDir()
+	paths := models.NewWeaverPaths(home)
+	f := &fakeCluster{t: t, layout: NewLayout(*paths), rootfs: filepath.Join(home, "rootfs")}
+
+	writeFile(t, f.layout.Manifest, manifestYAML)
+	writeFile(t, filepath.Join(f.layout.PKIDir, "ca.crt"), "ca")
+	writeFile(t, filepath.Join(f.layout.PKIDir, "etcd", "ca.crt"), "etcd-ca")
+	writeFile(t, f.layout.KubeadmConfig, "kind: InitConfiguration\n")
+	writeFile(t, filepath.Join(f.layout.DataDir, "member", "snap", "db"), "live")
+	writeFile(t, filepath.Join(f.rootfs, containerBinDir, etcdutlBin), "#!etcdutl")
+
+	m := &Manager{
+		layout: f.layout,
+		exec:   f.exec,
+		stopService: func(_ context.Context, name string) error {
+			f.stopped = append(f.stopped, name)
+			return nil
+		},
+		startService: func(_ context.Context, name string) error {
+			f.started = append(f.started, name)
+			return nil
+		},
+		now: func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) },
+	}
+	return m, f
+}
+
+func (f *fakeCluster) exec(_ context.Context, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, filepath.Base(name)+" "+strings.Join(args, " "))
+	switch {
+	case filepath.Base(name) == "crictl" && contains(args, "ps"):
+		return []byte("c0ffee\n"), nil
+	case filepath.Base(name) == "crictl" && contains(args, "inspect"):
+		return []byte(`{"info":{"runtimeSpec":{"root":{"path":"` + f.rootfs + `"}}}}`), nil
+	case filepath.Base(name) == "crictl" && contains(args, "pods"):
+		return []byte("pod-a\npod-b\n"), nil
+	case filepath.Base(name) == etcdctlBin:
+		writeFile(f.t, args[len(args)-1], "snapshot")
+	case filepath.Base(name) == etcdutlBin && contains(args, "status"):
+		return []byte(`{"hash":1,"revision":42,"totalKey":7}`), nil
+	case filepath.Base(name) == etcdutlBin && contains(args, "restore"):
+		for _, a := range args {
+			if dir, ok := strings.CutPrefix(a, "--data-dir="); ok {
+				writeFile(f.t, filepath.Join(dir, "member", "snap", "db"), "restored")
+			}
+		}
+	}
+	return nil, nil
+}
+
+func contains(args []string, want string) bool {
+	for _, a := range args {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	m, f := newFakeCluster(t)
+	target := filepath.Join(t.TempDir(), "backups")
+
+	res, err := m.Backup(context.Background(), BackupOptions{Target: target, Keep: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), res.Revision)
+	assert.Equal(t, BackupKind, res.Manifest.Kind)
+	for _, v := range []string{VolumeSnapshot, VolumePKI, VolumeKubeadm, VolumeTools} {
+		assert.NotNil(t, res.Manifest.Volume(v), v)
+	}
+	assert.Contains(t, f.calls[2], "--endpoints=https://127.0.0.1:2379")
+
+	dir, man, err := Latest(target)
+	require.NoError(t, err)
+	assert.Equal(t, res.Dir, dir)
+	assert.NotNil(t, man)
+	entries, err := os.ReadDir(target)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the staging directory is removed")
+
+	// Lose the kubeadm config; the restore puts it back but keeps the PKI.
+	require.NoError(t, os.Remove(f.layout.KubeadmConfig))
+	writeFile(t, filepath.Join(f.layout.PKIDir, "ca.crt"), "renewed")
+
+	previous, err := m.Restore(context.Background(), res.Dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kubelet"}, f.stopped)
+	assert.Equal(t, []string{"kubelet"}, f.started)
+	assert.Contains(t, f.calls[len(f.calls)-1], "stopp pod-a pod-b")
+
+	got, err := os.ReadFile(filepath.Join(f.layout.DataDir, "member", "snap", "db"))
+	require.NoError(t, err)
+	assert.Equal(t, "restored", string(got))
+	got, err = os.ReadFile(filepath.Join(previous, "member", "snap", "db"))
+	require.NoError(t, err)
+	assert.Equal(t, "live", string(got))
+	got, err = os.ReadFile(filepath.Join(f.layout.PKIDir, "ca.crt"))
+	require.NoError(t, err)
+	assert.Equal(t, "renewed", string(got))
+	assert.FileExists(t, f.layout.KubeadmConfig)
+	assert.NoDirExists(t, f.layout.DataDir+".restore-20261001T120000Z")
+}
+
+func TestRestore_Corrupt(t *testing.T) {
+	m, f := newFakeCluster(t)
+	target := t.TempDir()
+	res, err := m.Backup(context.Background(), BackupOptions{Target: target})
+	require.NoError(t, err)
+
+	archive := filepath.Join(res.Dir, res.Manifest.Volume(VolumeSnapshot).Archive)
+	require.NoError(t, os.WriteFile(archive, []byte("tampered"), 0o600))
+
+	_, err = m.Restore(context.Background(), res.Dir)
+	require.ErrorContains(t, err, "corrupt")
+	assert.Empty(t, f.stopped, "nothing is stopped before the backup verifies")
+}
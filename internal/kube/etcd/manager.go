@@ -0,0 +1,345 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/internal/backup"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	pkgos "github.com/hashgraph/solo-weaver/pkg/os"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// timestampLayout stamps the directories a restore leaves next to the data
+// directory, matching the backup directory names.
+const timestampLayout = "20060102T150405Z"
+
+// Manager takes and restores cluster backups. Its exec and service seams are
+// injectable so tests run without crictl, etcd or systemd.
+type Manager struct {
+	layout Layout
+	exec   func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+	stopService  func(ctx context.Context, name string) error
+	startService func(ctx context.Context, name string) error
+	now          func() time.Time
+}
+
+// NewManager returns a Manager for the cluster sandboxed under paths.
+func NewManager(paths models.WeaverPaths) *Manager {
+	return &Manager{
+		layout:       NewLayout(paths),
+		exec:         execCommand,
+		stopService:  pkgos.StopService,
+		startService: pkgos.StartService,
+		now:          time.Now,
+	}
+}
+
+// BackupOptions configures Backup.
+type BackupOptions struct {
+	// Target is the directory backups are written under.
+	Target string
+	// Keep is how many cluster backups to retain under Target once this one
+	// is written; below 1 keeps every backup.
+	Keep int
+}
+
+// BackupResult is what Backup wrote and pruned.
+type BackupResult struct {
+	Dir      string
+	Manifest *backup.Manifest
+	// Revision is the etcd revision the snapshot was taken at.
+	Revision int64
+	// Pruned lists the older backups removed to honour Keep.
+	Pruned []string
+}
+
+// Latest returns the newest cluster backup under target, or a nil manifest.
+func Latest(target string) (string, *backup.Manifest, error) {
+	dirs, mans, err := backup.List(target, BackupRelease, BackupKind)
+	if err != nil || len(mans) == 0 {
+		return "", nil, err
+	}
+	return dirs[len(dirs)-1], mans[len(mans)-1], nil
+}
+
+// Backup snapshots the running etcd, checks the snapshot with etcdutl and
+// writes it with the PKI, the kubeadm config and etcdutl as a cluster backup
+// under opts.Target, then prunes the backups beyond opts.Keep. The cluster
+// keeps serving throughout.
+func (m *Manager) Backup(ctx context.Context, opts BackupOptions) (*BackupResult, error) {
+	if opts.Target == "" {
+		return nil, errorx.IllegalArgument.New("backup target directory is required")
+	}
+	member, err := ReadMember(m.layout.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	binDir, err := m.etcdBinDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(opts.Target, 0o750); err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to create backup target %s", opts.Target)
+	}
+	// Stage next to the backups so the archives never cross a filesystem; the
+	// dot keeps the staging directory out of backup.List.
+	staging, err := os.MkdirTemp(opts.Target, ".staging-")
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to create a staging directory under %s", opts.Target)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	dirs := map[string]string{}
+	for _, name := range []string{VolumeSnapshot, VolumeKubeadm, VolumeTools} {
+		dirs[name] = filepath.Join(staging, name)
+		if err := os.Mkdir(dirs[name], 0o700); err != nil {
+			return nil, errorx.ExternalError.Wrap(err, "failed to create %s", dirs[name])
+		}
+	}
+
+	snapshot := filepath.Join(dirs[VolumeSnapshot], snapshotFile)
+	logx.As().Info().Str("endpoint", member.Endpoint).Msg("Taking an etcd snapshot")
+	if _, err := m.exec(ctx, filepath.Join(binDir, etcdctlBin),
+		"--endpoints="+member.Endpoint,
+		"--cacert="+filepath.Join(m.layout.PKIDir, "etcd", "ca.crt"),
+		"--cert="+filepath.Join(m.layout.PKIDir, "etcd", "healthcheck-client.crt"),
+		"--key="+filepath.Join(m.layout.PKIDir, "etcd", "healthcheck-client.key"),
+		"snapshot", "save", snapshot,
+	); err != nil {
+		return nil, errorx.Decorate(err, "failed to take the etcd snapshot")
+	}
+	revision, err := m.snapshotRevision(ctx, filepath.Join(binDir, etcdutlBin), snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := copyFile(filepath.Join(binDir, etcdutlBin), filepath.Join(dirs[VolumeTools], etcdutlBin), 0o755); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(m.layout.KubeadmConfig); err == nil {
+		if err := copyFile(m.layout.KubeadmConfig, filepath.Join(dirs[VolumeKubeadm], kubeadmConfigFile), 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, man, err := backup.Create(ctx, backup.Options{
+		Target:  opts.Target,
+		Release: BackupRelease,
+		Kind:    BackupKind,
+		Volumes: []backup.Source{
+			{Name: VolumeSnapshot, Path: dirs[VolumeSnapshot]},
+			{Name: VolumePKI, Path: m.layout.PKIDir},
+			{Name: VolumeKubeadm, Path: dirs[VolumeKubeadm]},
+			{Name: VolumeTools, Path: dirs[VolumeTools]},
+		},
+		Now: m.now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pruned, err := backup.Prune(opts.Target, BackupRelease, BackupKind, opts.Keep)
+	if err != nil {
+		return nil, err
+	}
+	return &BackupResult{Dir: dir, Manifest: man, Revision: revision, Pruned: pruned}, nil
+}
+
+// Restore replaces the etcd data directory with the snapshot of the cluster
+// backup in dir:
+//  1. verify every archive of the backup against its manifest checksum
+//  2. restore the snapshot with the backup's etcdutl as the current member
+//     into a new directory, which also checks the snapshot's own hash
+//  3. stop the kubelet and the control-plane pods
+//  4. swap the restored directory in, keeping the old one as
+//     <dataDir>.pre-restore-<timestamp>
+//  5. start the kubelet, which brings the control plane back on the restored
+//     data
+//
+// The PKI and kubeadm config are restored only where the host has lost them;
+// existing ones are the ones the kubeconfigs and workloads trust. It returns
+// the path the previous data directory was moved to.
+func (m *Manager) Restore(ctx context.Context, dir string) (string, error) {
+	man, err := backup.ReadManifest(dir)
+	if err != nil {
+		return "", err
+	}
+	if man.Kind != BackupKind {
+		return "", errorx.IllegalArgument.New("%s is not a cluster backup", dir).
+			WithProperty(models.ErrPropertyResolution, "pass a "+BackupRelease+"-"+BackupKind+"-<timestamp> directory written by 'kube cluster backup'")
+	}
+	if err := backup.Verify(ctx, dir); err != nil {
+		return "", err
+	}
+	member, err := ReadMember(m.layout.Manifest)
+	if err != nil {
+		return "", err
+	}
+
+	stamp := m.now().UTC().Format(timestampLayout)
+	work := m.layout.DataDir + ".restore-" + stamp
+	restored := m.layout.DataDir + ".restored-" + stamp
+	previous := m.layout.DataDir + ".pre-restore-" + stamp
+	for _, name := range []string{VolumeSnapshot, VolumeTools} {
+		if err := os.MkdirAll(filepath.Join(work, name), 0o700); err != nil {
+			return "", errorx.ExternalError.Wrap(err, "failed to create %s", work)
+		}
+		if err := backup.RestoreVolume(ctx, dir, name, filepath.Join(work, name)); err != nil {
+			_ = os.RemoveAll(work)
+			return "", err
+		}
+	}
+	defer func() { _ = os.RemoveAll(work) }()
+
+	if err := m.restoreIfMissing(ctx, dir, VolumePKI, m.layout.PKIDir, filepath.Join(m.layout.PKIDir, "ca.crt")); err != nil {
+		return "", err
+	}
+	if err := m.restoreIfMissing(ctx, dir, VolumeKubeadm, filepath.Dir(m.layout.KubeadmConfig), m.layout.KubeadmConfig); err != nil {
+		return "", err
+	}
+
+	logx.As().Info().Str("member", member.Name).Str("data_dir", restored).Msg("Restoring the etcd snapshot")
+	if _, err := m.exec(ctx, filepath.Join(work, VolumeTools, etcdutlBin),
+		"snapshot", "restore", filepath.Join(work, VolumeSnapshot, snapshotFile),
+		"--name="+member.Name,
+		"--initial-cluster="+member.InitialCluster,
+		"--initial-advertise-peer-urls="+member.InitialAdvertisePeerURLs,
+		"--data-dir="+restored,
+	); err != nil {
+		_ = os.RemoveAll(restored)
+		return "", errorx.Decorate(err, "failed to restore the etcd snapshot")
+	}
+
+	logx.As().Info().Msg("Stopping the kubelet and the control plane")
+	if err := m.stopService(ctx, software.KubeletServiceName); err != nil {
+		_ = os.RemoveAll(restored)
+		return "", errorx.ExternalError.Wrap(err, "failed to stop the kubelet")
+	}
+	swapErr := m.stopControlPlane(ctx)
+	if swapErr == nil {
+		swapErr = swapDir(m.layout.DataDir, restored, previous)
+	}
+	if err := m.startService(ctx, software.KubeletServiceName); err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to start the kubelet").
+			WithProperty(models.ErrPropertyResolution, "start it by hand: sudo systemctl start kubelet")
+	}
+	if swapErr != nil {
+		_ = os.RemoveAll(restored)
+		return "", swapErr
+	}
+	return previous, nil
+}
+
+// restoreIfMissing restores the named backup volume into dest when marker,
+// the file that shows dest is populated, does not exist.
+func (m *Manager) restoreIfMissing(ctx context.Context, dir, name, dest, marker string) error {
+	if _, err := os.Stat(marker); err == nil {
+		return nil
+	}
+	logx.As().Warn().Str("volume", name).Str("dest", dest).Msg("Host lacks it; restoring it from the backup")
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to create %s", dest)
+	}
+	return backup.RestoreVolume(ctx, dir, name, dest)
+}
+
+// etcdBinDir returns the directory holding etcdctl and etcdutl inside the
+// running etcd container's root filesystem.
+func (m *Manager) etcdBinDir(ctx context.Context) (string, error) {
+	out, err := m.exec(ctx, m.layout.Crictl, "--runtime-endpoint", m.layout.CRISocket,
+		"ps", "--name", "^etcd$", "--state", "Running", "--quiet")
+	if err != nil {
+		return "", errorx.Decorate(err, "failed to find the etcd container")
+	}
+	id, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	if id == "" {
+		return "", errorx.IllegalState.New("etcd is not running").
+			WithProperty(models.ErrPropertyResolution, "check the control plane: sudo solo-provisioner kube cluster certs check, sudo journalctl -u kubelet")
+	}
+	inspect, err := m.exec(ctx, m.layout.Crictl, "--runtime-endpoint", m.layout.CRISocket, "inspect", id)
+	if err != nil {
+		return "", errorx.Decorate(err, "failed to inspect the etcd container")
+	}
+	root, err := containerRootFS(inspect)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, containerBinDir), nil
+}
+
+// snapshotRevision checks the snapshot with `etcdutl snapshot status` and
+// returns the revision it holds.
+func (m *Manager) snapshotRevision(ctx context.Context, etcdutl, snapshot string) (int64, error) {
+	out, err := m.exec(ctx, etcdutl, "snapshot", "status", snapshot, "--write-out=json")
+	if err != nil {
+		return 0, errorx.Decorate(err, "etcd snapshot %s failed its integrity check", snapshot)
+	}
+	var status struct {
+		Revision int64 `json:"revision"`
+	}
+	if err := json.Unmarshal(out, &status); err != nil {
+		return 0, errorx.IllegalFormat.Wrap(err, "failed to parse etcdutl snapshot status")
+	}
+	return status.Revision, nil
+}
+
+// stopControlPlane stops the control-plane pods. With the kubelet stopped
+// nothing restarts them until it starts again.
+func (m *Manager) stopControlPlane(ctx context.Context) error {
+	crictl := []string{"--runtime-endpoint", m.layout.CRISocket}
+	out, err := m.exec(ctx, m.layout.Crictl, append(crictl, "pods", "--namespace", "kube-system", "--label", "tier=control-plane", "--quiet")...)
+	if err != nil {
+		return errorx.Decorate(err, "failed to list the control-plane pods")
+	}
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := m.exec(ctx, m.layout.Crictl, append(append(crictl, "stopp"), ids...)...); err != nil {
+		return errorx.Decorate(err, "failed to stop the control-plane pods")
+	}
+	return nil
+}
+
+// swapDir moves current to previous and restored to current, moving current
+// back when the second rename fails.
+func swapDir(current, restored, previous string) error {
+	if err := os.Rename(current, previous); err != nil && !os.IsNotExist(err) {
+		return errorx.ExternalError.Wrap(err, "failed to move %s aside", current)
+	}
+	if err := os.Rename(restored, current); err != nil {
+		_ = os.Rename(previous, current)
+		return errorx.ExternalError.Wrap(err, "failed to move the restored data into %s", current)
+	}
+	return nil
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to open %s", src)
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to create %s", dst)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return errorx.ExternalError.Wrap(err, "failed to copy %s to %s", src, dst)
+	}
+	return out.Close()
+}
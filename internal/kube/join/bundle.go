@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package join lets another host join the kubeadm cluster as a worker or as an
+// additional control-plane node.
+//
+// On a control-plane node, Mint issues a join bundle: a bootstrap token, the
+// hash of the cluster CA's public key the new node pins the API server to, and
+// the key the control-plane certificates are uploaded to the cluster under.
+// On the new host, Join renders a kubeadm JoinConfiguration from the bundle
+// and runs `kubeadm join` with it through the sandboxed kubeadm.
+package join
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// bundlePrefix marks an encoded bundle and versions its format.
+const bundlePrefix = "weaver-join-v1:"
+
+var (
+	tokenPattern          = regexp.MustCompile(`^[a-z0-9]{6}\.[a-z0-9]{16}$`)
+	caCertHashPattern     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+	certificateKeyPattern = regexp.MustCompile(`^[a-f0-9]{64}$`)
+)
+
+// Bundle is what a host needs to join the cluster.
+type Bundle struct {
+	// Endpoint is the cluster's controlPlaneEndpoint (host:port).
+	Endpoint string `json:"endpoint"`
+	// Token is the bootstrap token the node authenticates with until its
+	// kubelet has a certificate.
+	Token string `json:"token"`
+	// CACertHash pins the cluster CA ("sha256:<hex>").
+	CACertHash string `json:"caCertHash"`
+	// CertificateKey decrypts the control-plane certificates kubeadm uploaded
+	// to the kubeadm-certs Secret; only control-plane joins use it.
+	CertificateKey string `json:"certificateKey,omitempty"`
+	// KubernetesVersion is the version the control plane runs.
+	KubernetesVersion string `json:"kubernetesVersion"`
+	// ExpiresAt is when the token expires and CertificateKeyExpiresAt when
+	// kubeadm deletes the uploaded certificates.
+	ExpiresAt               time.Time `json:"expiresAt"`
+	CertificateKeyExpiresAt time.Time `json:"certificateKeyExpiresAt,omitempty"`
+}
+
+// Encode returns the bundle as a single line that can be copied to the new
+// host or written to a file.
+func (b *Bundle) Encode() (string, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return "", errorx.InternalError.Wrap(err, "failed to encode the join bundle")
+	}
+	return bundlePrefix + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeBundle parses an encoded bundle and validates its fields.
+func DecodeBundle(s string) (*Bundle, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(s), bundlePrefix)
+	if !ok {
+		return nil, errorx.IllegalFormat.New("not a join bundle: it does not start with %q", bundlePrefix).
+			WithProperty(models.ErrPropertyResolution,
+				"mint a bundle on a control-plane node: sudo solo-provisioner kube cluster join-token")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "the join bundle is corrupt")
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, errorx.IllegalFormat.Wrap(err, "the join bundle is corrupt")
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// LoadBundle returns the bundle ref holds: either an encoded bundle or the path
+// of a file containing one.
+func LoadBundle(ref string) (*Bundle, error) {
+	if strings.HasPrefix(strings.TrimSpace(ref), bundlePrefix) {
+		return DecodeBundle(ref)
+	}
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "failed to read the join bundle from %s", ref)
+	}
+	return DecodeBundle(string(data))
+}
+
+func (b *Bundle) validate() error {
+	if _, _, err := net.SplitHostPort(b.Endpoint); err != nil {
+		return errorx.IllegalFormat.Wrap(err, "the join bundle's endpoint %q is not host:port", b.Endpoint)
+	}
+	if !tokenPattern.MatchString(b.Token) {
+		return errorx.IllegalFormat.New("the join bundle's token is malformed")
+	}
+	if !caCertHashPattern.MatchString(b.CACertHash) {
+		return errorx.IllegalFormat.New("the join bundle's CA certificate hash %q is malformed", b.CACertHash)
+	}
+	if b.CertificateKey != "" && !certificateKeyPattern.MatchString(b.CertificateKey) {
+		return errorx.IllegalFormat.New("the join bundle's certificate key is malformed")
+	}
+	if b.KubernetesVersion == "" {
+		return errorx.IllegalFormat.New("the join bundle does not name the cluster's Kubernetes version")
+	}
+	return nil
+}
+
+// Check returns an error unless the bundle can still join a node in the given
+// role at now.
+func (b *Bundle) Check(controlPlane bool, now time.Time) error {
+	mint := "sudo solo-provisioner kube cluster join-token"
+	if !b.ExpiresAt.IsZero() && !now.Before(b.ExpiresAt) {
+		return errorx.IllegalState.New("the join bundle expired at %s", b.ExpiresAt.Format(time.RFC3339)).
+			WithProperty(models.ErrPropertyResolution, "mint a new bundle on a control-plane node: "+mint)
+	}
+	if !controlPlane {
+		return nil
+	}
+	if b.CertificateKey == "" {
+		return errorx.IllegalArgument.New("the join bundle carries no certificate key, so it cannot join a control-plane node").
+			WithProperty(models.ErrPropertyResolution, "mint a bundle with the control-plane certificates: "+mint)
+	}
+	if !b.CertificateKeyExpiresAt.IsZero() && !now.Before(b.CertificateKeyExpiresAt) {
+		return errorx.IllegalState.New("the join bundle's control-plane certificates expired at %s",
+			b.CertificateKeyExpiresAt.Format(time.RFC3339)).
+			WithProperty(models.ErrPropertyResolution,
+				"kubeadm deletes the uploaded certificates after two hours; mint a new bundle: "+mint)
+	}
+	return nil
+}
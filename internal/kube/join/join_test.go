@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package join
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/kube/etcd"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+const (
+	testToken = "abcdef.0123456789abcdef"
+	testKey   = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	initConfig = `apiVersion: kubeadm.k8s.io/v1beta4
+kind: InitConfiguration
+localAPIEndpoint:
+  advertiseAddress: 10.0.0.1
+---
+apiVersion: kubeadm.k8s.io/v1beta4
+kind: ClusterConfiguration
+controlPlaneEndpoint: "10.0.0.1:6443"
+kubernetesVersion: v1.33.4
+`
+)
+
+var testNow = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+// writeCA writes a self-signed CA to dir/ca.crt and returns its kubeadm hash.
+func writeCA(t *testing.T, dir string) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             testNow,
+		NotAfter:              testNow.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "ca.crt"), string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// fakeKubeadm records the kubeadm invocations and answers them like kubeadm.
+type fakeKubeadm struct {
+	calls []string
+}
+
+func (f *fakeKubeadm) run(_ context.Context, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, strings.Join(args, " "))
+	switch {
+	case len(args) > 1 && args[0] == "token" && args[1] == "create":
+		return []byte(testToken + "\n"), nil
+	case len(args) > 1 && args[0] == "certs" && args[1] == "certificate-key":
+		return []byte(testKey + "\n"), nil
+	}
+	return nil, nil
+}
+
+func newTestManager(t *testing.T) (*Manager, *fakeKubeadm) {
+	t.Helper()
+	home := t.TempDir()
+	paths := models.NewWeaverPaths(home)
+	f := &fakeKubeadm{}
+	m := &Manager{
+		layout:      etcd.NewLayout(*paths),
+		joinConfig:  filepath.Join(paths.SandboxDir, "etc", "weaver", joinConfigFile),
+		kubeletConf: filepath.Join(home, "kubelet.conf"),
+		sandboxDir:  paths.SandboxDir,
+		kubeadm:     f.run,
+		hostname:    func() (string, error) { return "node-2", nil },
+		machineIP:   func() (string, error) { return "10.0.0.2", nil },
+		now:         func() time.Time { return testNow },
+	}
+	return m, f
+}
+
+func TestMint(t *testing.T) {
+	m, f := newTestManager(t)
+	writeFile(t, m.layout.KubeadmConfig, initConfig)
+	hash := writeCA(t, m.layout.PKIDir)
+
+	b, err := m.Mint(context.Background(), 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, &Bundle{
+		Endpoint:                "10.0.0.1:6443",
+		Token:                   testToken,
+		CACertHash:              hash,
+		CertificateKey:          testKey,
+		KubernetesVersion:       "1.33.4",
+		ExpiresAt:               testNow.Add(2 * time.Hour),
+		CertificateKeyExpiresAt: testNow.Add(certificateKeyTTL),
+	}, b)
+	assert.Equal(t, []string{
+		"token create --ttl 2h0m0s --description " + tokenPurpose,
+		"certs certificate-key",
+		"init phase upload-certs --upload-certs --certificate-key " + testKey + " --config " + m.layout.KubeadmConfig,
+	}, f.calls)
+
+	encoded, err := b.Encode()
+	require.NoError(t, err)
+	decoded, err := DecodeBundle(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, b, decoded, "the bundle survives encoding")
+}
+
+func TestMint_NotAControlPlane(t *testing.T) {
+	m, f := newTestManager(t)
+	_, err := m.Mint(context.Background(), DefaultTokenTTL)
+	require.ErrorContains(t, err, "kubeadm-init.yaml")
+	assert.Empty(t, f.calls, "no token is created")
+}
+
+func testBundle() *Bundle {
+	return &Bundle{
+		Endpoint:                "10.0.0.1:6443",
+		Token:                   testToken,
+		CACertHash:              "sha256:" + strings.Repeat("a", 64),
+		CertificateKey:          testKey,
+		KubernetesVersion:       "1.33.4",
+		ExpiresAt:               testNow.Add(time.Hour),
+		CertificateKeyExpiresAt: testNow.Add(time.Hour),
+	}
+}
+
+func TestJoin(t *testing.T) {
+	for _, controlPlane := range []bool{false, true} {
+		m, f := newTestManager(t)
+		node, err := m.Join(context.Background(), testBundle(), controlPlane)
+		require.NoError(t, err)
+		assert.Equal(t, "node-2", node)
+		assert.Equal(t, []string{"join --config " + m.joinConfig + " --v=3"}, f.calls)
+
+		data, err := os.ReadFile(m.joinConfig)
+		require.NoError(t, err)
+		cfg := string(data)
+		assert.Contains(t, cfg, `apiServerEndpoint: "10.0.0.1:6443"`)
+		assert.Contains(t, cfg, "token: "+testToken)
+		assert.Contains(t, cfg, "unix://"+m.sandboxDir+"/var/run/crio/crio.sock")
+		assert.Contains(t, cfg, "value: 10.0.0.2")
+		assert.Equal(t, controlPlane, strings.Contains(cfg, "certificateKey: "+testKey),
+			"only a control-plane join carries the certificate key")
+		assert.Equal(t, controlPlane, strings.Contains(cfg, "advertiseAddress: 10.0.0.2"))
+	}
+}
+
+func TestJoin_Refuses(t *testing.T) {
+	tests := []struct {
+		name         string
+		mutate       func(*Bundle)
+		controlPlane bool
+		joined       bool
+		want         string
+	}{
+		{name: "expired token", mutate: func(b *Bundle) { b.ExpiresAt = testNow }, want: "expired"},
+		{name: "no certificate key", mutate: func(b *Bundle) { b.CertificateKey = "" }, controlPlane: true, want: "no certificate key"},
+		{
+			name: "certificates deleted", controlPlane: true, want: "control-plane certificates expired",
+			mutate: func(b *Bundle) { b.CertificateKeyExpiresAt = testNow.Add(-time.Minute) },
+		},
+		{name: "already joined", joined: true, want: "already belongs to a cluster"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, f := newTestManager(t)
+			if tt.joined {
+				writeFile(t, m.kubeletConf, "kubeconfig")
+			}
+			b := testBundle()
+			if tt.mutate != nil {
+				tt.mutate(b)
+			}
+			_, err := m.Join(context.Background(), b, tt.controlPlane)
+			require.ErrorContains(t, err, tt.want)
+			assert.Empty(t, f.calls, "kubeadm join is not run")
+		})
+	}
+}
+
+func TestLoadBundle(t *testing.T) {
+	encoded, err := testBundle().Encode()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "join.bundle")
+	writeFile(t, path, encoded+"\n")
+	for _, ref := range []string{encoded, path} {
+		b, err := LoadBundle(ref)
+		require.NoError(t, err)
+		assert.Equal(t, testBundle(), b)
+	}
+
+	_, err = DecodeBundle("abcdef.0123456789abcdef")
+	require.ErrorContains(t, err, "not a join bundle")
+
+	bad := testBundle()
+	bad.CACertHash = "md5:abc"
+	encoded, err = bad.Encode()
+	require.NoError(t, err)
+	_, err = DecodeBundle(encoded)
+	require.ErrorContains(t, err, "CA certificate hash")
+}
+
+func TestCheckVersions(t *testing.T) {
+	b := testBundle()
+	require.NoError(t, CheckVersions(b, "1.33.4", "1.33.4", true))
+	require.NoError(t, CheckVersions(b, "1.33.1", "1.31.0", false), "a worker's kubelet may trail the control plane")
+	require.ErrorContains(t, CheckVersions(b, "1.33.4", "1.31.0", true), "kubelet 1.31.0 cannot join")
+	require.ErrorContains(t, CheckVersions(b, "1.32.8", "1.32.8", false), "kubeadm 1.32.8 cannot join")
+	require.ErrorContains(t, CheckVersions(b, "1.33.4", "1.34.0", false), "kubelet 1.34.0 cannot join")
+	require.ErrorContains(t, CheckVersions(b, "1.33.4", "1.29.0", false), "kubelet 1.29.0 cannot join")
+}
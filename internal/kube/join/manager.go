@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package join
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"gopkg.in/yaml.v3"
+
+	"github.com/hashgraph/solo-weaver/internal/kube/etcd"
+	"github.com/hashgraph/solo-weaver/internal/network"
+	"github.com/hashgraph/solo-weaver/internal/templates"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+const (
+	// DefaultTokenTTL is how long a minted bootstrap token stays valid unless
+	// another TTL is given.
+	DefaultTokenTTL = 24 * time.Hour
+
+	// certificateKeyTTL is how long kubeadm keeps the uploaded control-plane
+	// certificates before deleting the kubeadm-certs Secret.
+	certificateKeyTTL = 2 * time.Hour
+
+	// KubeletKubeconfig is the kubeconfig `kubeadm join` writes for the
+	// kubelet; a worker has no other credentials for the cluster.
+	KubeletKubeconfig = "/etc/kubernetes/kubelet.conf"
+
+	joinConfigFile = "kubeadm-join.yaml"
+	tokenPurpose   = "solo-provisioner join bundle"
+)
+
+// Runner runs the sandboxed kubeadm with args and returns its stdout.
+type Runner func(ctx context.Context, args ...string) ([]byte, error)
+
+// Manager mints join bundles on a control-plane node and joins hosts with
+// them. Its kubeadm runner and host lookups are injectable so tests run
+// without kubeadm or a cluster.
+type Manager struct {
+	layout      etcd.Layout
+	joinConfig  string
+	kubeletConf string
+	sandboxDir  string
+
+	kubeadm   Runner
+	hostname  func() (string, error)
+	machineIP func() (string, error)
+	now       func() time.Time
+}
+
+// NewManager returns a Manager for the cluster sandboxed under paths.
+func NewManager(paths models.WeaverPaths) *Manager {
+	return &Manager{
+		layout:      etcd.NewLayout(paths),
+		joinConfig:  filepath.Join(paths.SandboxDir, "etc", "weaver", joinConfigFile),
+		kubeletConf: KubeletKubeconfig,
+		sandboxDir:  paths.SandboxDir,
+		kubeadm:     commandRunner(filepath.Join(paths.SandboxBinDir, "kubeadm")),
+		hostname:    os.Hostname,
+		machineIP:   network.GetMachineIP,
+		now:         time.Now,
+	}
+}
+
+// ClusterConfig is what the cluster's kubeadm ClusterConfiguration says about
+// where and what it runs.
+type ClusterConfig struct {
+	Endpoint          string `yaml:"controlPlaneEndpoint"`
+	KubernetesVersion string `yaml:"kubernetesVersion"`
+}
+
+// ClusterConfig reads the ClusterConfiguration from the kubeadm-init.yaml the
+// cluster was initialised (or last upgraded) with.
+func (m *Manager) ClusterConfig() (ClusterConfig, error) {
+	f, err := os.Open(m.layout.KubeadmConfig)
+	if err != nil {
+		return ClusterConfig{}, errorx.IllegalState.Wrap(err, "failed to read %s", m.layout.KubeadmConfig).
+			WithProperty(models.ErrPropertyResolution, "run this command on a control-plane node of the cluster")
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	for {
+		var doc struct {
+			Kind          string `yaml:"kind"`
+			ClusterConfig `yaml:",inline"`
+		}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ClusterConfig{}, errorx.IllegalFormat.Wrap(err, "failed to parse %s", m.layout.KubeadmConfig)
+		}
+		if doc.Kind != "ClusterConfiguration" {
+			continue
+		}
+		if doc.Endpoint == "" || doc.KubernetesVersion == "" {
+			return ClusterConfig{}, errorx.IllegalFormat.New("the ClusterConfiguration in %s sets no controlPlaneEndpoint or kubernetesVersion",
+				m.layout.KubeadmConfig)
+		}
+		doc.ClusterConfig.KubernetesVersion = strings.TrimPrefix(doc.ClusterConfig.KubernetesVersion, "v")
+		return doc.ClusterConfig, nil
+	}
+	return ClusterConfig{}, errorx.IllegalFormat.New("%s holds no ClusterConfiguration", m.layout.KubeadmConfig)
+}
+
+// CACertHash returns the hash `kubeadm join` pins the cluster CA with: the
+// sha256 of the CA certificate's DER-encoded public key.
+func (m *Manager) CACertHash() (string, error) {
+	path := filepath.Join(m.layout.PKIDir, "ca.crt")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to read the cluster CA %s", path)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", errorx.IllegalFormat.New("%s is not a PEM certificate", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", errorx.IllegalFormat.Wrap(err, "failed to parse the cluster CA %s", path)
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// Mint issues a join bundle on a control-plane node: a bootstrap token valid
+// for ttl, the CA hash, and a fresh certificate key the control-plane
+// certificates are uploaded to the kubeadm-certs Secret under.
+func (m *Manager) Mint(ctx context.Context, ttl time.Duration) (*Bundle, error) {
+	if ttl <= 0 {
+		return nil, errorx.IllegalArgument.New("the token TTL must be positive, got %s", ttl)
+	}
+	cfg, err := m.ClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := m.CACertHash()
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.now()
+	out, err := m.kubeadm(ctx, "token", "create", "--ttl", ttl.String(), "--description", tokenPurpose)
+	if err != nil {
+		return nil, err
+	}
+	token := strings.TrimSpace(string(out))
+	if !tokenPattern.MatchString(token) {
+		return nil, errorx.IllegalFormat.New("kubeadm token create printed no token")
+	}
+
+	out, err = m.kubeadm(ctx, "certs", "certificate-key")
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimSpace(string(out))
+	if !certificateKeyPattern.MatchString(key) {
+		return nil, errorx.IllegalFormat.New("kubeadm certs certificate-key printed no key")
+	}
+	// The config points kubeadm at the sandboxed certificatesDir.
+	if _, err := m.kubeadm(ctx, "init", "phase", "upload-certs", "--upload-certs",
+		"--certificate-key", key, "--config", m.layout.KubeadmConfig); err != nil {
+		return nil, err
+	}
+
+	return &Bundle{
+		Endpoint:                cfg.Endpoint,
+		Token:                   token,
+		CACertHash:              hash,
+		CertificateKey:          key,
+		KubernetesVersion:       cfg.KubernetesVersion,
+		ExpiresAt:               now.Add(ttl).UTC(),
+		CertificateKeyExpiresAt: now.Add(certificateKeyTTL).UTC(),
+	}, nil
+}
+
+// Joined reports whether the host already belongs to a cluster, which
+// `kubeadm join` refuses.
+func (m *Manager) Joined() bool {
+	_, err := os.Stat(m.kubeletConf)
+	return err == nil
+}
+
+// Join renders a JoinConfiguration from b and joins the host to the cluster
+// as a worker or, with controlPlane, as a control-plane node. It returns the
+// name the node registered under.
+func (m *Manager) Join(ctx context.Context, b *Bundle, controlPlane bool) (string, error) {
+	if err := b.Check(controlPlane, m.now()); err != nil {
+		return "", err
+	}
+	if m.Joined() {
+		return "", errorx.IllegalState.New("this host already belongs to a cluster (%s exists)", m.kubeletConf).
+			WithProperty(models.ErrPropertyResolution,
+				"remove it from its cluster first: sudo solo-provisioner kube cluster uninstall")
+	}
+
+	machineIP, err := m.machineIP()
+	if err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to get machine IP address")
+	}
+	hostname, err := m.hostname()
+	if err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to get hostname")
+	}
+
+	data := templates.KubeadmJoinData{
+		SandboxDir:   m.sandboxDir,
+		MachineIP:    machineIP,
+		Hostname:     hostname,
+		Endpoint:     b.Endpoint,
+		Token:        b.Token,
+		CACertHash:   b.CACertHash,
+		ControlPlane: controlPlane,
+	}
+	if controlPlane {
+		data.CertificateKey = b.CertificateKey
+	}
+	rendered, err := templates.Render("files/kubeadm/"+joinConfigFile, data)
+	if err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to render kubeadm join configuration template")
+	}
+
+	// The configuration carries the token, so only root may read it.
+	if err := os.MkdirAll(filepath.Dir(m.joinConfig), 0o755); err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to create directory for kubeadm join configuration")
+	}
+	if err := os.WriteFile(m.joinConfig, []byte(rendered), 0o600); err != nil {
+		return "", errorx.IllegalState.Wrap(err, "failed to write kubeadm join configuration file")
+	}
+
+	if _, err := m.kubeadm(ctx, "join", "--config", m.joinConfig, "--v=3"); err != nil {
+		return "", err
+	}
+	return hostname, nil
+}
+
+// Reset undoes a join: `kubeadm reset` removes the node's kubelet
+// configuration, static pods and, on a control-plane node, its etcd member.
+func (m *Manager) Reset(ctx context.Context) error {
+	if _, err := m.kubeadm(ctx, "reset", "--force", "--cri-socket", m.layout.CRISocket); err != nil {
+		return err
+	}
+	if err := os.Remove(m.joinConfig); err != nil && !os.IsNotExist(err) {
+		return errorx.IllegalState.Wrap(err, "failed to remove %s", m.joinConfig)
+	}
+	return nil
+}
+
+// commandRunner returns a Runner executing bin; a failure carries the
+// command's stderr.
+func commandRunner(bin string) Runner {
+	return func(ctx context.Context, args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, bin, args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return out, errorx.ExternalError.Wrap(err, "kubeadm %s failed: %s",
+				strings.Join(redact(args), " "), strings.TrimSpace(stderr.String()))
+		}
+		return out, nil
+	}
+}
+
+// redact hides the certificate key in logged kubeadm arguments.
+func redact(args []string) []string {
+	out := make([]string, len(args))
+	copy(out, args)
+	for i := 1; i < len(out); i++ {
+		if out[i-1] == "--certificate-key" {
+			out[i] = "<redacted>"
+		}
+	}
+	return out
+}
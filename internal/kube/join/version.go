@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package join
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// maxKubeletSkew is how many minors a worker's kubelet may trail the API
+// server by.
+const maxKubeletSkew = 3
+
+// CheckVersions checks that the kubeadm and kubelet versions the host
+// installs can join the cluster b was minted for. kubeadm has to match the
+// control plane's minor, as does a control-plane node's kubelet; a worker's
+// kubelet may trail it within the version skew policy but never be newer.
+func CheckVersions(b *Bundle, kubeadm, kubelet string, controlPlane bool) error {
+	cluster, err := semver.NewVersion(b.KubernetesVersion)
+	if err != nil {
+		return errorx.IllegalFormat.Wrap(err, "the join bundle's Kubernetes version %q is invalid", b.KubernetesVersion)
+	}
+	clusterMinor := fmt.Sprintf("%d.%d", cluster.Major(), cluster.Minor())
+	hint := fmt.Sprintf("join with a solo-provisioner release that installs Kubernetes %s, or upgrade the cluster first", clusterMinor)
+
+	check := func(component, version string, allowOlder bool) error {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return errorx.IllegalFormat.Wrap(err, "the %s version %q is invalid", component, version)
+		}
+		sameMinor := v.Major() == cluster.Major() && v.Minor() == cluster.Minor()
+		if sameMinor {
+			return nil
+		}
+		if allowOlder && v.Major() == cluster.Major() && v.LessThan(cluster) && cluster.Minor()-v.Minor() <= maxKubeletSkew {
+			return nil
+		}
+		return errorx.IllegalState.New("%s %s cannot join a cluster running Kubernetes %s", component, v, cluster).
+			WithProperty(models.ErrPropertyResolution, hint)
+	}
+
+	if err := check("kubeadm", kubeadm, false); err != nil {
+		return err
+	}
+	return check("kubelet", kubelet, !controlPlane)
+}
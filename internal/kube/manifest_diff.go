@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/joomcode/errorx"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// DiffAction is what applying a manifest would do to one object.
+type DiffAction string
+
+const (
+	DiffCreate    DiffAction = "create"
+	DiffUpdate    DiffAction = "update"
+	DiffUnchanged DiffAction = "unchanged"
+)
+
+// FieldChange is a field the manifest sets whose live value differs. Path is
+// in the dotted form server-side apply reports, e.g. ".spec.addresses"; map
+// keys that are not plain identifiers are bracketed, e.g.
+// ".metadata.labels[app.kubernetes.io/name]". Live is nil for a field the
+// live object does not set.
+type FieldChange struct {
+	Path    string `json:"path" yaml:"path"`
+	Live    any    `json:"live,omitempty" yaml:"live,omitempty"`
+	Desired any    `json:"desired" yaml:"desired"`
+}
+
+// ObjectDiff is the drift between one manifest object and its live
+// counterpart.
+type ObjectDiff struct {
+	APIVersion string        `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string        `json:"kind" yaml:"kind"`
+	Namespace  string        `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Name       string        `json:"name" yaml:"name"`
+	Action     DiffAction    `json:"action" yaml:"action"`
+	Changes    []FieldChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+	// Conflicts lists the manifest's fields another field manager owns, as
+	// reported by a dry-run apply, e.g. `.data.config: conflict with
+	// "kubectl-edit" using v1`. A non-forced ApplyManifest fails on them.
+	Conflicts []string `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+}
+
+// HasDrift reports whether applying the manifest would change the object or
+// take fields over from another field manager.
+func (d ObjectDiff) HasDrift() bool {
+	return d.Action != DiffUnchanged || len(d.Conflicts) > 0
+}
+
+// String identifies the object as Kind namespace/name.
+func (d ObjectDiff) String() string {
+	if d.Namespace == "" {
+		return fmt.Sprintf("%s %s", d.Kind, d.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", d.Kind, d.Namespace, d.Name)
+}
+
+// DiffManifest compares every object in the manifest file with its live
+// counterpart without changing the cluster. Only the fields the manifest sets
+// are compared, so defaults and controller-managed fields are not reported;
+// of the metadata only labels and annotations are. For objects that exist a
+// dry-run server-side apply reports fields another field manager owns, which
+// is how a hand edit of a weaver-managed field shows up even when its value
+// was later put back.
+func (c *Client) DiffManifest(ctx context.Context, manifestPath string) ([]ObjectDiff, error) {
+	objs, err := parseManifests(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []ObjectDiff
+	handler := func(ctx context.Context, mapping *meta.RESTMapping, dr dynamic.ResourceInterface, u *unstructured.Unstructured) error {
+		d := ObjectDiff{APIVersion: u.GetAPIVersion(), Kind: u.GetKind(), Name: u.GetName(), Action: DiffUnchanged}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			d.Namespace = u.GetNamespace()
+			if d.Namespace == "" {
+				d.Namespace = "default"
+			}
+		}
+
+		live, err := dr.Get(ctx, u.GetName(), metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			d.Action = DiffCreate
+			diffs = append(diffs, d)
+			return nil
+		}
+		if err != nil {
+			return errorx.InternalError.Wrap(err, "error getting %s/%s", mapping.Resource.Resource, u.GetName())
+		}
+
+		d.Changes = diffFields("", desiredFields(u.Object), live.Object)
+		if len(d.Changes) > 0 {
+			d.Action = DiffUpdate
+		}
+
+		data, err := u.MarshalJSON()
+		if err != nil {
+			return errorx.InternalError.Wrap(err, "error marshaling %s/%s for server-side apply", mapping.Resource.Resource, u.GetName())
+		}
+		force := false
+		if _, err := dr.Patch(ctx, u.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+			FieldManager: FieldManager,
+			Force:        &force,
+			DryRun:       []string{metav1.DryRunAll},
+		}); err != nil {
+			if !kerrors.IsConflict(err) {
+				return applyError(err, mapping, u)
+			}
+			d.Conflicts = applyConflicts(err)
+		}
+
+		diffs = append(diffs, d)
+		return nil
+	}
+
+	if err := c.processResources(ctx, objs, handler); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// desiredFields returns the parts of a manifest object that are compared with
+// the live object: everything but apiVersion, kind, status and the metadata
+// other than labels and annotations.
+func desiredFields(obj map[string]any) map[string]any {
+	out := make(map[string]any, len(obj))
+	for k, v := range obj {
+		switch k {
+		case "apiVersion", "kind", "status":
+		case "metadata":
+			md, _ := v.(map[string]any)
+			kept := map[string]any{}
+			for _, mk := range []string{"labels", "annotations"} {
+				if mv, ok := md[mk]; ok {
+					kept[mk] = mv
+				}
+			}
+			if len(kept) > 0 {
+				out[k] = kept
+			}
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// diffFields walks desired and returns the fields whose value in live
+// differs, in path order. Maps are compared key by key; lists and scalars as
+// a whole, by their JSON encoding so that a decoded float64 3 equals a live
+// int64 3.
+func diffFields(prefix string, desired, live map[string]any) []FieldChange {
+	keys := make([]string, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var changes []FieldChange
+	for _, k := range keys {
+		path := prefix + fieldPathSegment(k)
+		want := desired[k]
+		got, found := live[k]
+		if wantMap, ok := want.(map[string]any); ok {
+			if gotMap, ok := got.(map[string]any); ok {
+				changes = append(changes, diffFields(path, wantMap, gotMap)...)
+				continue
+			}
+		}
+		if !found || !jsonEqual(want, got) {
+			changes = append(changes, FieldChange{Path: path, Live: got, Desired: want})
+		}
+	}
+	return changes
+}
+
+// fieldPathSegment renders a map key as a path segment.
+func fieldPathSegment(key string) string {
+	if strings.ContainsAny(key, "./[]") {
+		return "[" + key + "]"
+	}
+	return "." + key
+}
+
+func jsonEqual(a, b any) bool {
+	aj, errA := json.Marshal(a)
+	bj, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aj) == string(bj)
+}
+
+// applyConflicts extracts the field manager conflicts from a server-side
+// apply Conflict error, one "<field>: <message>" entry each.
+func applyConflicts(err error) []string {
+	var conflicts []string
+	var status kerrors.APIStatus
+	if errors.As(err, &status) && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s", cause.Field, cause.Message))
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		conflicts = append(conflicts, err.Error())
+	}
+	return conflicts
+}
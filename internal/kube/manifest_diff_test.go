@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kube
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/restmapper"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+const driftManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: alloy-config
+  namespace: monitoring
+  labels:
+    app.kubernetes.io/name: alloy
+data:
+  config.alloy: desired
+  replicas: "2"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: alloy-extra
+  namespace: monitoring
+data:
+  extra: "1"
+`
+
+// newFakeManifestClient returns a Client over a fake dynamic client holding
+// objs, with a REST mapper that knows ConfigMaps.
+func newFakeManifestClient(t *testing.T, objs ...runtime.Object) (*Client, *fakedynamic.FakeDynamicClient) {
+	t.Helper()
+	disco := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: []string{"get", "patch"}}},
+	}}}}
+	dyn := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	return &Client{Dyn: dyn, Mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disco))}, dyn
+}
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// conflictErr is the error the API server returns for a non-forced apply of a
+// field kubectl edit took over.
+func conflictErr(name string) error {
+	return kerrors.NewApplyConflict([]metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldManagerConflict,
+		Message: `conflict with "kubectl-edit" using v1`,
+		Field:   ".data.config.alloy",
+	}}, "Apply failed with 1 conflict: conflict with \"kubectl-edit\" using v1: .data.config.alloy on "+name)
+}
+
+// rejectUnforcedApply fails every non-forced apply with a conflict, accepts
+// forced ones and records the options patches were sent with. The fake
+// tracker cannot apply to unstructured objects, so the reactor answers all
+// patches itself.
+func rejectUnforcedApply(dyn *fakedynamic.FakeDynamicClient, sent *[]metav1.PatchOptions) {
+	dyn.PrependReactor("patch", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchActionImpl)
+		*sent = append(*sent, patch.GetPatchOptions())
+		if opts := patch.GetPatchOptions(); opts.Force == nil || !*opts.Force {
+			return true, nil, conflictErr(patch.GetName())
+		}
+		return true, &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": patch.GetName(), "namespace": patch.GetNamespace()},
+		}}, nil
+	})
+}
+
+func TestDiffManifest(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":            "alloy-config",
+			"namespace":       "monitoring",
+			"resourceVersion": "42",
+			"labels":          map[string]any{"app.kubernetes.io/name": "alloy"},
+		},
+		"data": map[string]any{"config.alloy": "edited by hand", "replicas": "2", "other": "kept"},
+	}}
+	c, dyn := newFakeManifestClient(t, live)
+	var sent []metav1.PatchOptions
+	rejectUnforcedApply(dyn, &sent)
+
+	diffs, err := c.DiffManifest(context.Background(), writeManifest(t, driftManifest))
+	require.NoError(t, err)
+	require.Len(t, diffs, 2)
+
+	assert.Equal(t, "ConfigMap monitoring/alloy-config", diffs[0].String())
+	assert.Equal(t, DiffUpdate, diffs[0].Action)
+	assert.Equal(t, []FieldChange{{Path: ".data[config.alloy]", Live: "edited by hand", Desired: "desired"}}, diffs[0].Changes,
+		"only fields the manifest sets are compared")
+	assert.Equal(t, []string{`.data.config.alloy: conflict with "kubectl-edit" using v1`}, diffs[0].Conflicts)
+	assert.True(t, diffs[0].HasDrift())
+
+	assert.Equal(t, DiffCreate, diffs[1].Action)
+	assert.Empty(t, diffs[1].Changes)
+
+	require.Len(t, sent, 1, "only existing objects are dry-run applied")
+	assert.Equal(t, []string{metav1.DryRunAll}, sent[0].DryRun)
+	assert.Equal(t, FieldManager, sent[0].FieldManager)
+
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	got, err := dyn.Resource(gvr).Namespace("monitoring").Get(context.Background(), "alloy-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "edited by hand", got.Object["data"].(map[string]any)["config.alloy"], "a diff does not change the cluster")
+}
+
+func TestDiffFields(t *testing.T) {
+	desired := map[string]any{"spec": map[string]any{"replicas": float64(3), "ports": []any{"80"}, "new": true}}
+	live := map[string]any{"spec": map[string]any{"replicas": int64(3), "ports": []any{"80", "443"}, "extra": "x"}}
+
+	assert.Equal(t, []FieldChange{
+		{Path: ".spec.new", Desired: true},
+		{Path: ".spec.ports", Live: []any{"80", "443"}, Desired: []any{"80"}},
+	}, diffFields("", desired, live), "numbers compare by value and lists as a whole")
+}
+
+func TestApplyManifest_Conflicts(t *testing.T) {
+	cm := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "alloy-config", "namespace": "monitoring"},
+	}}
+
+	t.Run("fails without force", func(t *testing.T) {
+		c, dyn := newFakeManifestClient(t, cm.DeepCopy())
+		var sent []metav1.PatchOptions
+		rejectUnforcedApply(dyn, &sent)
+
+		err := c.ApplyManifest(context.Background(), writeManifest(t, driftManifest), WithForceConflicts(false))
+		require.Error(t, err)
+		assert.True(t, errorx.IsOfType(err, errorx.IllegalState))
+		assert.ErrorContains(t, err, `conflict with "kubectl-edit"`)
+	})
+
+	t.Run("forces by default", func(t *testing.T) {
+		c, dyn := newFakeManifestClient(t, cm.DeepCopy())
+		var sent []metav1.PatchOptions
+		rejectUnforcedApply(dyn, &sent)
+
+		require.NoError(t, c.ApplyManifest(context.Background(), writeManifest(t, driftManifest)))
+		require.Len(t, sent, 2)
+		for _, opts := range sent {
+			assert.True(t, *opts.Force)
+			assert.Equal(t, FieldManager, opts.FieldManager)
+			assert.Empty(t, opts.DryRun)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// Names templated manifests are recorded under (see WithRecord).
+const (
+	ManifestMetalLBConfig    = "metallb-config"
+	ManifestAlloyConfigMap   = "alloy-configmap"
+	ManifestBlockNodeStorage = "block-node-storage"
+)
+
+// manifestExt is the extension of a recorded manifest.
+const manifestExt = ".yaml"
+
+// WithRecord makes ApplyManifest keep a copy of the manifest it applied under
+// models.Paths().ManifestsDir as <name>.yaml, replacing the previous one.
+// Templated manifests are rendered to the temp directory, so the copy is what
+// DiffRecordedManifests compares the live objects with after the run.
+func WithRecord(name string) ApplyOption {
+	return func(o *applyOptions) { o.record = name }
+}
+
+// recordManifest copies the manifest file to the recorded manifest name.
+func recordManifest(name, manifestPath string) error {
+	p, err := recordedManifestPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return errorx.IllegalArgument.Wrap(err, "error reading manifest")
+	}
+	if err := os.MkdirAll(filepath.Dir(p), models.DefaultDirOrExecPerm); err != nil {
+		return errorx.InternalError.Wrap(err, "failed to create %s", filepath.Dir(p))
+	}
+	if err := os.WriteFile(p, data, models.DefaultFilePerm); err != nil {
+		return errorx.InternalError.Wrap(err, "failed to record manifest %s", name)
+	}
+	return nil
+}
+
+// ForgetManifest removes the recorded manifest name once the objects it
+// declares are deleted, so they are no longer reported as drift. A manifest
+// that was never recorded is not an error.
+func ForgetManifest(name string) error {
+	p, err := recordedManifestPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return errorx.InternalError.Wrap(err, "failed to forget manifest %s", name)
+	}
+	return nil
+}
+
+func recordedManifestPath(name string) (string, error) {
+	if name == "" || filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+		return "", errorx.IllegalArgument.New("invalid manifest name %q", name)
+	}
+	return filepath.Join(models.Paths().ManifestsDir, name+manifestExt), nil
+}
+
+// RecordedManifest is a manifest kept by WithRecord.
+type RecordedManifest struct {
+	Name string
+	Path string
+}
+
+// RecordedManifests lists the recorded manifests by name. There are none
+// before the first recording apply.
+func RecordedManifests() ([]RecordedManifest, error) {
+	entries, err := os.ReadDir(models.Paths().ManifestsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to list the recorded manifests")
+	}
+
+	var found []RecordedManifest
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != manifestExt {
+			continue
+		}
+		found = append(found, RecordedManifest{
+			Name: strings.TrimSuffix(e.Name(), manifestExt),
+			Path: filepath.Join(models.Paths().ManifestsDir, e.Name()),
+		})
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found, nil
+}
+
+// ManifestDrift is the drift of the objects of one recorded manifest.
+type ManifestDrift struct {
+	Manifest string       `json:"manifest" yaml:"manifest"`
+	Objects  []ObjectDiff `json:"objects" yaml:"objects"`
+}
+
+// HasDrift reports whether any object of the manifest drifted.
+func (m ManifestDrift) HasDrift() bool {
+	for _, o := range m.Objects {
+		if o.HasDrift() {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffRecordedManifests runs DiffManifest over every recorded manifest.
+func (c *Client) DiffRecordedManifests(ctx context.Context) ([]ManifestDrift, error) {
+	recorded, err := RecordedManifests()
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]ManifestDrift, 0, len(recorded))
+	for _, r := range recorded {
+		objs, err := c.DiffManifest(ctx, r.Path)
+		if err != nil {
+			return nil, errorx.Decorate(err, "failed to diff manifest %s", r.Name)
+		}
+		drifts = append(drifts, ManifestDrift{Manifest: r.Name, Objects: objs})
+	}
+	return drifts, nil
+}
+
+// WriteDriftReport writes one line per object, followed by its changed and
+// conflicting fields.
+func WriteDriftReport(w io.Writer, drifts []ManifestDrift) {
+	if len(drifts) == 0 {
+		_, _ = fmt.Fprintln(w, "No recorded manifests; they are recorded when weaver applies them.")
+		return
+	}
+	for _, m := range drifts {
+		for _, o := range m.Objects {
+			state := "in sync"
+			switch {
+			case o.Action == DiffCreate:
+				state = "missing"
+			case o.HasDrift():
+				state = "drifted"
+			}
+			_, _ = fmt.Fprintf(w, "%-20s %-50s %s\n", m.Manifest, o.String(), state)
+			for _, ch := range o.Changes {
+				live := any("(unset)")
+				if ch.Live != nil {
+					live = ch.Live
+				}
+				_, _ = fmt.Fprintf(w, "  %s: %v -> %v\n", ch.Path, live, ch.Desired)
+			}
+			for _, conflict := range o.Conflicts {
+				_, _ = fmt.Fprintf(w, "  %s\n", conflict)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kube
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+func TestApplyManifest_WithRecord(t *testing.T) {
+	defer models.SetPaths(t.TempDir())()
+
+	c, dyn := newFakeManifestClient(t)
+	var sent []metav1.PatchOptions
+	rejectUnforcedApply(dyn, &sent)
+
+	found, err := RecordedManifests()
+	require.NoError(t, err)
+	assert.Empty(t, found, "nothing is recorded before the first apply")
+
+	require.NoError(t, c.ApplyManifest(context.Background(), writeManifest(t, driftManifest)))
+	found, err = RecordedManifests()
+	require.NoError(t, err)
+	assert.Empty(t, found, "applies without WithRecord are not recorded")
+
+	require.NoError(t, c.ApplyManifest(context.Background(), writeManifest(t, driftManifest), WithRecord(ManifestAlloyConfigMap)))
+	found, err = RecordedManifests()
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, ManifestAlloyConfigMap, found[0].Name)
+	data, err := os.ReadFile(found[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, driftManifest, string(data))
+
+	require.NoError(t, ForgetManifest(ManifestAlloyConfigMap))
+	require.NoError(t, ForgetManifest(ManifestAlloyConfigMap), "forgetting twice is not an error")
+	found, err = RecordedManifests()
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	assert.Error(t, ForgetManifest("../state"), "names cannot leave the manifests directory")
+}
+
+func TestDiffRecordedManifests(t *testing.T) {
+	defer models.SetPaths(t.TempDir())()
+
+	live := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "alloy-config", "namespace": "monitoring"},
+		"data":       map[string]any{"config.alloy": "edited by hand", "replicas": "2"},
+	}}
+	c, dyn := newFakeManifestClient(t, live)
+	var sent []metav1.PatchOptions
+	rejectUnforcedApply(dyn, &sent)
+	require.NoError(t, recordManifest(ManifestAlloyConfigMap, writeManifest(t, driftManifest)))
+
+	drifts, err := c.DiffRecordedManifests(context.Background())
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, ManifestAlloyConfigMap, drifts[0].Manifest)
+	assert.True(t, drifts[0].HasDrift())
+
+	var out bytes.Buffer
+	WriteDriftReport(&out, drifts)
+	assert.Contains(t, out.String(), "ConfigMap monitoring/alloy-config")
+	assert.Contains(t, out.String(), "drifted")
+	assert.Contains(t, out.String(), ".data[config.alloy]: edited by hand -> desired")
+	assert.Contains(t, out.String(), ".metadata.labels: (unset) -> map[app.kubernetes.io/name:alloy]")
+	assert.Contains(t, out.String(), "ConfigMap monitoring/alloy-extra")
+	assert.Contains(t, out.String(), "missing")
+}
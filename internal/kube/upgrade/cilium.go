@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/joomcode/errorx"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+const (
+	ciliumNamespace = "kube-system"
+	ciliumDaemonSet = "cilium"
+)
+
+// ciliumKubernetes maps a Cilium minor release to the oldest and newest
+// Kubernetes minor it is tested against, as listed in the Cilium docs'
+// "Kubernetes Compatibility" page.
+var ciliumKubernetes = map[string][2]string{
+	"1.16": {"1.27", "1.30"},
+	"1.17": {"1.29", "1.32"},
+	"1.18": {"1.30", "1.33"},
+}
+
+// CheckCilium returns an error unless the Cilium release cilium supports
+// Kubernetes version kubernetes.
+func CheckCilium(cilium, kubernetes string) error {
+	cv, err := parseVersion("Cilium", cilium)
+	if err != nil {
+		return err
+	}
+	kv, err := parseVersion("Kubernetes", kubernetes)
+	if err != nil {
+		return err
+	}
+
+	minor := fmt.Sprintf("%d.%d", cv.Major(), cv.Minor())
+	bounds, ok := ciliumKubernetes[minor]
+	if !ok {
+		return errorx.IllegalState.New("the Kubernetes compatibility of Cilium %s is unknown", cv).
+			WithProperty(models.ErrPropertyResolution,
+				"use a solo-provisioner release that knows this Cilium release before upgrading Kubernetes")
+	}
+
+	k := semver.New(kv.Major(), kv.Minor(), 0, "", "")
+	lo, hi := semver.MustParse(bounds[0]), semver.MustParse(bounds[1])
+	if k.LessThan(lo) || k.GreaterThan(hi) {
+		return errorx.IllegalState.New("Cilium %s supports Kubernetes %s to %s, not %s", cv, bounds[0], bounds[1], kv).
+			WithProperty(models.ErrPropertyResolution,
+				fmt.Sprintf("upgrade Cilium to a release that supports Kubernetes %d.%d first", kv.Major(), kv.Minor()))
+	}
+	return nil
+}
+
+// LiveCiliumVersion returns the version of the Cilium agent the cluster runs,
+// read from the image tag of the cilium DaemonSet's agent container.
+func LiveCiliumVersion(ctx context.Context, kc *kube.Client) (string, error) {
+	gvr, err := kube.ToGroupVersionResource(kube.KindDaemonSet)
+	if err != nil {
+		return "", err
+	}
+	ds, err := kc.Dyn.Resource(gvr).Namespace(ciliumNamespace).Get(ctx, ciliumDaemonSet, metav1.GetOptions{})
+	if err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to read the %s/%s DaemonSet", ciliumNamespace, ciliumDaemonSet)
+	}
+	return ciliumVersion(ds)
+}
+
+// ciliumVersion extracts the version from the image of the DaemonSet's
+// cilium-agent container, e.g. "quay.io/cilium/cilium:v1.18.1@sha256:…".
+func ciliumVersion(ds *unstructured.Unstructured) (string, error) {
+	containers, _, err := unstructured.NestedSlice(ds.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return "", errorx.IllegalState.Wrap(err, "malformed cilium DaemonSet")
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok || container["name"] != "cilium-agent" {
+			continue
+		}
+		image, _ := container["image"].(string)
+		image, _, _ = strings.Cut(image, "@")
+		if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+			return strings.TrimPrefix(image[i+1:], "v"), nil
+		}
+		return "", errorx.IllegalState.New("the cilium-agent image %q has no version tag", image)
+	}
+	return "", errorx.IllegalState.New("the cilium DaemonSet has no cilium-agent container")
+}
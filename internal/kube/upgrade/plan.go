@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package upgrade plans moving the kubeadm cluster to a newer Kubernetes
+// version: which catalog version each host component moves from and to, and
+// whether the move is allowed by the Kubernetes version skew policy and by the
+// Cilium release running in the cluster.
+//
+// Every version a plan moves to or rolls back to must be declared in the
+// infrastructure catalog, so the binaries are always staged through the
+// installers with their catalog checksums.
+package upgrade
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// maxKubeletSkew is how many minors the kubelet may trail the API server by.
+const maxKubeletSkew = 3
+
+// Component is a host component the upgrade moves between catalog versions.
+type Component struct {
+	// Key is the component's installer and MachineState.Software key.
+	Key string
+	// Artifact is the component's infrastructure catalog name.
+	Artifact string
+	// From is the version installed now; a rollback reinstalls it.
+	From string
+	// To is the version the upgrade installs.
+	To string
+}
+
+// components are the host components an upgrade moves, in upgrade order:
+// kubeadm upgrades the control plane before the kubelet, CRI-O and kubectl
+// follow it.
+var components = []struct{ key, artifact string }{
+	{software.KubeadmBinaryName, software.KubeadmBinaryName},
+	{software.KubeletBinaryName, software.KubeletBinaryName},
+	{software.CrioBinaryName, software.CrioArtifactName},
+	{software.KubectlBinaryName, software.KubectlBinaryName},
+}
+
+// Current is what the cluster runs before the upgrade.
+type Current struct {
+	// ControlPlane is the API server's version (e.g. "v1.33.4").
+	ControlPlane string
+	// Software holds the versions recorded in MachineState.Software by
+	// installer key; a component without a record runs the catalog default.
+	Software map[string]string
+	// Cilium is the version of the running Cilium agent (e.g. "1.18.1").
+	Cilium string
+}
+
+// Plan is a validated Kubernetes upgrade.
+type Plan struct {
+	// From and To are the Kubernetes versions, without a leading "v".
+	From string
+	To   string
+	// Components lists the host components in upgrade order.
+	Components []Component
+}
+
+// Component returns the planned component with the given key.
+func (p *Plan) Component(key string) (Component, bool) {
+	for _, c := range p.Components {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Component{}, false
+}
+
+// NewPlan validates moving the cluster described by cur to Kubernetes version
+// to against the catalog and returns the plan. It enforces:
+//   - to is newer than the control plane and at most one minor ahead of it,
+//     the most kubeadm upgrades in one step
+//   - kubeadm, the kubelet and kubectl declare exactly to in the catalog, and
+//     CRI-O declares a version of the same minor (the highest is chosen)
+//   - the kubelet is not newer than the control plane nor further behind it
+//     than the skew policy allows
+//   - every component's current version is in the catalog, so a rollback can
+//     reinstall it with checksum verification
+//   - the running Cilium supports to
+func NewPlan(catalog *software.InfrastructureCatalog, cur Current, to string) (*Plan, error) {
+	target, err := parseVersion("target", to)
+	if err != nil {
+		return nil, err
+	}
+	from, err := parseVersion("control plane", cur.ControlPlane)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case target.Equal(from):
+		return nil, errorx.IllegalArgument.New("the cluster already runs Kubernetes %s", from).
+			WithProperty(models.ErrPropertyResolution, "pass a newer version with --to")
+	case target.LessThan(from):
+		return nil, errorx.IllegalArgument.New("cannot downgrade Kubernetes from %s to %s", from, target).
+			WithProperty(models.ErrPropertyResolution,
+				"kubeadm does not support downgrades; restore a cluster backup taken before the upgrade instead")
+	case target.Major() != from.Major() || target.Minor() > from.Minor()+1:
+		next := fmt.Sprintf("%d.%d", from.Major(), from.Minor()+1)
+		return nil, errorx.IllegalArgument.New("cannot upgrade Kubernetes from %s to %s: kubeadm upgrades one minor version at a time", from, target).
+			WithProperty(models.ErrPropertyResolution, fmt.Sprintf("upgrade to a %s release first", next))
+	}
+
+	plan := &Plan{From: from.String(), To: target.String()}
+	for _, c := range components {
+		artifact, err := catalog.GetHostArtifact(c.artifact)
+		if err != nil {
+			return nil, err
+		}
+
+		comp := Component{Key: c.key, Artifact: c.artifact, From: cur.Software[c.key]}
+		if comp.From == "" {
+			comp.From = string(artifact.Default)
+		}
+		if _, ok := artifact.Versions[software.Version(comp.From)]; !ok {
+			return nil, errorx.IllegalState.New("%s %s is not in the infrastructure catalog, so an upgrade could not roll it back", c.artifact, comp.From).
+				WithProperty(models.ErrPropertyResolution,
+					fmt.Sprintf("use a solo-provisioner release whose catalog declares %s %s", c.artifact, comp.From))
+		}
+
+		if c.key == software.CrioBinaryName {
+			comp.To, err = highestOfMinor(artifact, target)
+		} else {
+			comp.To, err = declared(artifact, target)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if c.key == software.KubeletBinaryName {
+			if err := checkKubeletSkew(comp.From, from); err != nil {
+				return nil, err
+			}
+		}
+		plan.Components = append(plan.Components, comp)
+	}
+
+	if err := CheckCilium(cur.Cilium, plan.To); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// parseVersion parses a Kubernetes version with or without a leading "v".
+func parseVersion(what, v string) (*semver.Version, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, errorx.IllegalArgument.New("the %s version is unknown", what)
+	}
+	parsed, err := semver.StrictNewVersion(strings.TrimPrefix(strings.TrimSpace(v), "v"))
+	if err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "invalid %s version %q", what, v).
+			WithProperty(models.ErrPropertyResolution, "use a full Kubernetes version such as 1.33.4")
+	}
+	return parsed, nil
+}
+
+// declared returns target if the artifact declares it.
+func declared(artifact *software.ArtifactMetadata, target *semver.Version) (string, error) {
+	if _, ok := artifact.Versions[software.Version(target.String())]; !ok {
+		return "", errorx.IllegalArgument.New("%s %s is not in the infrastructure catalog", artifact.Name, target).
+			WithProperty(models.ErrPropertyResolution,
+				fmt.Sprintf("choose one of the declared %s versions: %s", artifact.Name, strings.Join(versions(artifact), ", ")))
+	}
+	return target.String(), nil
+}
+
+// highestOfMinor returns the artifact's highest version sharing target's
+// major and minor. CRI-O's minor releases track Kubernetes'.
+func highestOfMinor(artifact *software.ArtifactMetadata, target *semver.Version) (string, error) {
+	var best *semver.Version
+	for _, v := range versions(artifact) {
+		parsed, err := semver.NewVersion(v)
+		if err != nil || parsed.Major() != target.Major() || parsed.Minor() != target.Minor() {
+			continue
+		}
+		if best == nil || parsed.GreaterThan(best) {
+			best = parsed
+		}
+	}
+	if best == nil {
+		return "", errorx.IllegalArgument.New("the infrastructure catalog has no %s %d.%d release for Kubernetes %s",
+			artifact.Name, target.Major(), target.Minor(), target).
+			WithProperty(models.ErrPropertyResolution,
+				fmt.Sprintf("choose a Kubernetes version with a matching %s release: %s", artifact.Name, strings.Join(versions(artifact), ", ")))
+	}
+	return best.Original(), nil
+}
+
+// checkKubeletSkew checks the kubelet against the control plane it runs under.
+func checkKubeletSkew(kubelet string, controlPlane *semver.Version) error {
+	v, err := parseVersion("kubelet", kubelet)
+	if err != nil {
+		return err
+	}
+	if v.Major() != controlPlane.Major() || v.GreaterThan(controlPlane) {
+		return errorx.IllegalState.New("the kubelet (%s) is newer than the control plane (%s)", v, controlPlane).
+			WithProperty(models.ErrPropertyResolution, "finish the previous upgrade before starting another")
+	}
+	if controlPlane.Minor()-v.Minor() > maxKubeletSkew {
+		return errorx.IllegalState.New("the kubelet (%s) is more than %d minor versions behind the control plane (%s)",
+			v, maxKubeletSkew, controlPlane).
+			WithProperty(models.ErrPropertyResolution, "upgrade the kubelet to the control plane's version before upgrading the cluster")
+	}
+	return nil
+}
+
+// versions lists the artifact's declared versions in ascending order.
+func versions(artifact *software.ArtifactMetadata) []string {
+	out := make([]string, 0, len(artifact.Versions))
+	for v := range artifact.Versions {
+		out = append(out, string(v))
+	}
+	slices.SortFunc(out, func(a, b string) int {
+		va, errA := semver.NewVersion(a)
+		vb, errB := semver.NewVersion(b)
+		if errA != nil || errB != nil {
+			return strings.Compare(a, b)
+		}
+		return va.Compare(vb)
+	})
+	return out
+}
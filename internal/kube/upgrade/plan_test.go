@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+func artifact(name, def string, versions ...string) software.ArtifactMetadata {
+	a := software.ArtifactMetadata{
+		Name:     name,
+		Default:  software.Version(def),
+		Versions: map[software.Version]software.VersionDetails{},
+	}
+	for _, v := range versions {
+		a.Versions[software.Version(v)] = software.VersionDetails{}
+	}
+	return a
+}
+
+func testCatalog() *software.InfrastructureCatalog {
+	return &software.InfrastructureCatalog{Host: []software.ArtifactMetadata{
+		artifact("kubeadm", "1.32.8", "1.32.8", "1.33.4"),
+		artifact("kubelet", "1.32.8", "1.32.8", "1.33.4"),
+		artifact("kubectl", "1.32.8", "1.32.8", "1.33.4"),
+		artifact("cri-o", "1.32.7", "1.32.7", "1.33.1", "1.33.4", "1.34.0"),
+	}}
+}
+
+func current() Current {
+	return Current{
+		ControlPlane: "v1.32.8",
+		Software:     map[string]string{"kubeadm": "1.32.8", "kubelet": "1.32.8", "crio": "1.32.7"},
+		Cilium:       "1.18.1",
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	plan, err := NewPlan(testCatalog(), current(), "v1.33.4")
+	require.NoError(t, err)
+	assert.Equal(t, "1.32.8", plan.From)
+	assert.Equal(t, "1.33.4", plan.To)
+	assert.Equal(t, []Component{
+		{Key: "kubeadm", Artifact: "kubeadm", From: "1.32.8", To: "1.33.4"},
+		{Key: "kubelet", Artifact: "kubelet", From: "1.32.8", To: "1.33.4"},
+		{Key: "crio", Artifact: "cri-o", From: "1.32.7", To: "1.33.4"},
+		{Key: "kubectl", Artifact: "kubectl", From: "1.32.8", To: "1.33.4"},
+	}, plan.Components, "kubectl falls back to the catalog default and CRI-O takes the highest 1.33 release")
+
+	crio, ok := plan.Component("crio")
+	require.True(t, ok)
+	assert.Equal(t, "1.33.4", crio.To)
+}
+
+func TestNewPlan_Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Current, *software.InfrastructureCatalog)
+		to     string
+		want   string
+	}{
+		{name: "same version", to: "1.32.8", want: "already runs"},
+		{name: "downgrade", to: "1.31.0", want: "cannot downgrade"},
+		{name: "minor skip", to: "1.34.0", want: "one minor version at a time"},
+		{name: "invalid", to: "latest", want: "invalid target version"},
+		{
+			name: "undeclared kubeadm", to: "1.33.5", want: "kubeadm 1.33.5 is not in the infrastructure catalog",
+		},
+		{
+			name: "no matching cri-o", to: "1.33.4", want: "no cri-o 1.33 release",
+			mutate: func(_ *Current, c *software.InfrastructureCatalog) {
+				c.Host[3] = artifact("cri-o", "1.32.7", "1.32.7", "1.34.0")
+			},
+		},
+		{
+			name: "kubelet ahead", to: "1.33.4", want: "newer than the control plane",
+			mutate: func(cur *Current, _ *software.InfrastructureCatalog) { cur.Software["kubelet"] = "1.33.4" },
+		},
+		{
+			name: "rollback target delisted", to: "1.33.4", want: "could not roll it back",
+			mutate: func(cur *Current, _ *software.InfrastructureCatalog) { cur.Software["crio"] = "1.32.1" },
+		},
+		{
+			name: "cilium too old", to: "1.33.4", want: "Cilium 1.16.5 supports Kubernetes 1.27 to 1.30",
+			mutate: func(cur *Current, _ *software.InfrastructureCatalog) { cur.Cilium = "1.16.5" },
+		},
+		{
+			name: "cilium unknown", to: "1.33.4", want: "the Cilium version is unknown",
+			mutate: func(cur *Current, _ *software.InfrastructureCatalog) { cur.Cilium = "" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur, catalog := current(), testCatalog()
+			if tt.mutate != nil {
+				tt.mutate(&cur, catalog)
+			}
+			_, err := NewPlan(catalog, cur, tt.to)
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestCheckCilium(t *testing.T) {
+	require.NoError(t, CheckCilium("1.18.1", "1.33.4"))
+	require.NoError(t, CheckCilium("v1.17.0", "v1.29.0"))
+	require.ErrorContains(t, CheckCilium("1.18.1", "1.34.0"), "supports Kubernetes 1.30 to 1.33")
+	require.ErrorContains(t, CheckCilium("1.99.0", "1.33.4"), "compatibility of Cilium 1.99.0 is unknown")
+}
+
+func TestCiliumVersion(t *testing.T) {
+	ds := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "cilium-agent", "image": "quay.io/cilium/cilium:v1.18.1@sha256:abc"},
+			},
+		}}},
+	}}
+	v, err := ciliumVersion(ds)
+	require.NoError(t, err)
+	assert.Equal(t, "1.18.1", v)
+
+	ds.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"] =
+		[]interface{}{map[string]interface{}{"name": "cilium-agent", "image": "localhost:5000/cilium"}}
+	_, err = ciliumVersion(ds)
+	require.ErrorContains(t, err, "no version tag")
+}
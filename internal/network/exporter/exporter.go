@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package exporter publishes the traffic shaper's kernel counters in the
+// Prometheus text exposition format: per-class tc stats — per tin under a
+// CAKE root, the root qdisc under fq — (the numbers `network shape watch`
+// turns into a terminal table) and the per-policy nft
+// match counters the `inet weaver-workload-policy` table declares for every
+// stamp and deny rule. It is served by `solo-provisioner network exporter`, which
+// the solo-provisioner-network-exporter.service unit runs on every node with
+// traffic shaping enabled, and scraped by the traffic-shaper Alloy module.
+//
+// The exporter is a read-only view: every scrape re-reads the kernel (tc and
+// nft) and holds no state between scrapes, so counter resets — a qdisc
+// reinstalled, the policy table re-applied — surface as ordinary Prometheus
+// counter resets for rate() to absorb.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/network"
+	"github.com/hashgraph/solo-weaver/internal/network/policy"
+	"github.com/hashgraph/solo-weaver/internal/network/shape"
+	"github.com/joomcode/errorx"
+)
+
+// DefaultListenPort is the port `network exporter` listens on when --listen is
+// not given. It is the one the Alloy traffic-shaper module
+// (internal/templates/files/alloy/traffic-shaper.alloy) scrapes on every node;
+// change both together.
+const DefaultListenPort = "9469"
+
+// machineIP resolves the node's own IP. Tests replace it.
+var machineIP = network.GetMachineIP
+
+// DefaultListenAddr returns the address `network exporter` listens on when
+// --listen is not given: DefaultListenPort on the node's own IP, which is the
+// address the Alloy module scrapes, so the unauthenticated endpoint is not
+// bound on every interface. Without a usable node IP it falls back to
+// loopback.
+func DefaultListenAddr() string {
+	ip, err := machineIP()
+	if err != nil || ip == "" {
+		logx.As().Warn().Err(err).
+			Msg("could not determine the node IP; traffic shaper exporter listens on loopback only")
+		ip = "127.0.0.1"
+	}
+	return net.JoinHostPort(ip, DefaultListenPort)
+}
+
+// MetricsPath is the HTTP path the metrics are served on.
+const MetricsPath = "/metrics"
+
+// ClassSampler reads labeled tc class counters. Satisfied by *shape.Manager.
+type ClassSampler interface {
+	SampleClasses(ctx context.Context, dir, iface string) ([]shape.ClassSample, error)
+	EgressInterface() (string, error)
+}
+
+// PolicyCounterReader reads the per-policy nft match counters. Satisfied by
+// *policy.Manager.
+type PolicyCounterReader interface {
+	Counters(ctx context.Context) ([]policy.PolicyCounter, error)
+}
+
+// Exporter collects the shape and policy counters on demand and renders them
+// as a Prometheus text exposition.
+type Exporter struct {
+	classes       ClassSampler
+	policies      PolicyCounterReader
+	egressIface   string
+	ingressIfaces func() ([]string, error)
+}
+
+// Config customises an Exporter. The zero value is not useful; prefer New.
+type Config struct {
+	Classes  ClassSampler
+	Policies PolicyCounterReader
+	// EgressIface pins the interface sampled for egress classes. Empty means
+	// detect it the same way the egress apply path does.
+	EgressIface string
+	// IngressIfaces lists the interfaces sampled for ingress classes — the
+	// per-pod host veths, which come and go with the pods. Nil means every
+	// non-loopback interface that is up.
+	IngressIfaces func() ([]string, error)
+}
+
+// New returns an Exporter wired to the live kernel.
+func New() *Exporter {
+	return NewWithConfig(Config{})
+}
+
+// NewWithConfig returns an Exporter, filling unset Config fields with their
+// production defaults.
+func NewWithConfig(cfg Config) *Exporter {
+	e := &Exporter{
+		classes:       cfg.Classes,
+		policies:      cfg.Policies,
+		egressIface:   cfg.EgressIface,
+		ingressIfaces: cfg.IngressIfaces,
+	}
+	if e.classes == nil {
+		e.classes = shape.NewManager()
+	}
+	if e.policies == nil {
+		e.policies = policy.NewManager()
+	}
+	if e.ingressIfaces == nil {
+		e.ingressIfaces = upInterfaces
+	}
+	return e
+}
+
+// upInterfaces returns every non-loopback interface that is administratively
+// up. Sampling one without an HTB hierarchy is harmless — it yields no samples
+// — so the exporter needs no knowledge of which veth belongs to which pod.
+func upInterfaces() ([]string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to list network interfaces")
+	}
+	var out []string
+	for _, i := range ifaces {
+		if i.Flags&net.FlagLoopback != 0 || i.Flags&net.FlagUp == 0 {
+			continue
+		}
+		out = append(out, i.Name)
+	}
+	return out, nil
+}
+
+// Handler returns the HTTP handler serving MetricsPath.
+func (e *Exporter) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+MetricsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		e.WriteMetrics(r.Context(), w)
+	})
+	return mux
+}
+
+// Serve listens on addr and serves the metrics until ctx is cancelled, then
+// shuts the server down gracefully. It returns nil on a clean shutdown.
+func (e *Exporter) Serve(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           e.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logx.As().Info().Str("addr", addr).Str("path", MetricsPath).Msg("traffic shaper exporter listening")
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return errorx.ExternalError.Wrap(err, "traffic shaper exporter failed to serve on %s", addr)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errorx.ExternalError.Wrap(err, "failed to shut down traffic shaper exporter")
+	}
+	return nil
+}
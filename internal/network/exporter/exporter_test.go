@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/internal/network/policy"
+	"github.com/hashgraph/solo-weaver/internal/network/shape"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClasses returns canned samples keyed by direction+iface and records the
+// interfaces it was asked to sample.
+type fakeClasses struct {
+	egress    string
+	egressErr error
+	samples   map[string][]shape.ClassSample
+	errs      map[string]error
+	sampled   []string
+}
+
+func (f *fakeClasses) SampleClasses(_ context.Context, dir, iface string) ([]shape.ClassSample, error) {
+	f.sampled = append(f.sampled, dir+"/"+iface)
+	if err := f.errs[iface]; err != nil {
+		return nil, err
+	}
+	return f.samples[dir+"/"+iface], nil
+}
+
+func (f *fakeClasses) EgressInterface() (string, error) { return f.egress, f.egressErr }
+
+type fakePolicies struct {
+	counters []policy.PolicyCounter
+	err      error
+}
+
+func (f *fakePolicies) Counters(context.Context) ([]policy.PolicyCounter, error) {
+	return f.counters, f.err
+}
+
+func newTestExporter(c *fakeClasses, p *fakePolicies, ingress ...string) *Exporter {
+	return NewWithConfig(Config{
+		Classes:       c,
+		Policies:      p,
+		IngressIfaces: func() ([]string, error) { return ingress, nil },
+	})
+}
+
+func TestWriteMetrics_ClassAndPolicyFamilies(t *testing.T) {
+	c := &fakeClasses{
+		egress: "enp0s1",
+		samples: map[string][]shape.ClassSample{
+			"egress/enp0s1": {{Class: "partner", Dir: "egress", Iface: "enp0s1",
+				ClassStat: shape.ClassStat{ClassID: "1:40", Bytes: 3000, Packets: 2, Drops: 1, Overlimits: 7, Backlog: 64}}},
+			"ingress/lxc1a2b3c": {{Class: "publisher", Dir: "ingress", Iface: "lxc1a2b3c",
+				ClassStat: shape.ClassStat{ClassID: "1:10", Bytes: 500}}},
+		},
+	}
+	p := &fakePolicies{counters: []policy.PolicyCounter{
+		{Policy: "bn-publisher", Action: policy.ActionStamp, Class: "publisher", Direction: policy.DirectionIngress,
+			CounterValue: policy.CounterValue{Packets: 10, Bytes: 15000}},
+		{Policy: "bn-restricted", Action: policy.ActionDeny, CounterValue: policy.CounterValue{Packets: 3, Bytes: 180}},
+	}}
+	e := newTestExporter(c, p, "enp0s1", "lxc1a2b3c")
+
+	var b bytes.Buffer
+	e.WriteMetrics(context.Background(), &b)
+	out := b.String()
+
+	require.Contains(t, out, "# TYPE weaver_shape_class_bytes_total counter\n")
+	require.Contains(t, out, `weaver_shape_class_bytes_total{class="partner",device="enp0s1",direction="egress"} 3000`+"\n")
+	require.Contains(t, out, `weaver_shape_class_packets_total{class="partner",device="enp0s1",direction="egress"} 2`+"\n")
+	require.Contains(t, out, `weaver_shape_class_drops_total{class="partner",device="enp0s1",direction="egress"} 1`+"\n")
+	require.Contains(t, out, `weaver_shape_class_overlimits_total{class="partner",device="enp0s1",direction="egress"} 7`+"\n")
+	require.Contains(t, out, "# TYPE weaver_shape_class_backlog_bytes gauge\n")
+	require.Contains(t, out, `weaver_shape_class_backlog_bytes{class="partner",device="enp0s1",direction="egress"} 64`+"\n")
+	require.Contains(t, out, `weaver_shape_class_bytes_total{class="publisher",device="lxc1a2b3c",direction="ingress"} 500`+"\n")
+
+	require.Contains(t, out, `weaver_policy_packets_total{action="stamp",class="publisher",direction="ingress",policy="bn-publisher"} 10`+"\n")
+	require.Contains(t, out, `weaver_policy_bytes_total{action="deny",class="",direction="",policy="bn-restricted"} 180`+"\n")
+
+	require.Contains(t, out, `weaver_exporter_collector_up{collector="policy"} 1`+"\n")
+	require.Contains(t, out, `weaver_exporter_collector_up{collector="shape"} 1`+"\n")
+
+	require.Equal(t, []string{"egress/enp0s1", "ingress/lxc1a2b3c"}, c.sampled,
+		"the egress NIC is never re-sampled as an ingress veth")
+}
+
+func TestWriteMetrics_FailingCollectorIsReportedDown(t *testing.T) {
+	c := &fakeClasses{egressErr: errors.New("no default route")}
+	p := &fakePolicies{counters: []policy.PolicyCounter{
+		{Policy: "bn-restricted", Action: policy.ActionDeny, CounterValue: policy.CounterValue{Packets: 1, Bytes: 60}},
+	}}
+	var b bytes.Buffer
+	newTestExporter(c, p).WriteMetrics(context.Background(), &b)
+	out := b.String()
+
+	require.Contains(t, out, `weaver_exporter_collector_up{collector="shape"} 0`+"\n")
+	require.Contains(t, out, `weaver_exporter_collector_up{collector="policy"} 1`+"\n")
+	require.Contains(t, out, `weaver_policy_packets_total{action="deny",class="",direction="",policy="bn-restricted"} 1`+"\n",
+		"a failing tc read must not blank the nft metrics")
+}
+
+func TestWriteMetrics_VanishedVethIsSkipped(t *testing.T) {
+	c := &fakeClasses{
+		egress: "enp0s1",
+		errs:   map[string]error{"lxcgone": errors.New("Cannot find device")},
+		samples: map[string][]shape.ClassSample{
+			"ingress/lxc1a2b3c": {{Class: "publisher", Dir: "ingress", Iface: "lxc1a2b3c"}},
+		},
+	}
+	var b bytes.Buffer
+	newTestExporter(c, &fakePolicies{}, "lxcgone", "lxc1a2b3c").WriteMetrics(context.Background(), &b)
+	out := b.String()
+
+	require.Contains(t, out, `weaver_exporter_collector_up{collector="shape"} 1`+"\n")
+	require.Contains(t, out, `device="lxc1a2b3c"`)
+	require.NotContains(t, out, "lxcgone")
+}
+
+func TestWriteSample_EscapesLabelValues(t *testing.T) {
+	var b bytes.Buffer
+	writeSample(&b, "m", 1, "l", "a\"b\\c\nd")
+	require.Equal(t, `m{l="a\"b\\c\nd"} 1`+"\n", b.String())
+}
+
+func TestHandler_ServesMetrics(t *testing.T) {
+	e := newTestExporter(&fakeClasses{egress: "enp0s1"}, &fakePolicies{})
+	srv := httptest.NewServer(e.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + MetricsPath)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/plain; version=0.0.4")
+
+	resp2, err := http.Post(srv.URL+MetricsPath, "text/plain", nil)
+	require.NoError(t, err)
+	_ = resp2.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp2.StatusCode)
+}
+
+func TestDefaultListenAddr_BindsNodeIPOrLoopback(t *testing.T) {
+	prev := machineIP
+	t.Cleanup(func() { machineIP = prev })
+
+	machineIP = func() (string, error) { return "10.0.0.5", nil }
+	require.Equal(t, "10.0.0.5:"+DefaultListenPort, DefaultListenAddr())
+
+	machineIP = func() (string, error) { return "", errors.New("no interface up") }
+	require.Equal(t, "127.0.0.1:"+DefaultListenPort, DefaultListenAddr(),
+		"without a node IP the exporter never binds every interface")
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/network/policy"
+	"github.com/hashgraph/solo-weaver/internal/network/shape"
+)
+
+// Metric names. Class metrics carry class, direction and device labels; policy
+// metrics carry policy, action, class and direction (class and direction are
+// empty for a deny policy).
+const (
+	metricClassBytes      = "weaver_shape_class_bytes_total"
+	metricClassPackets    = "weaver_shape_class_packets_total"
+	metricClassDrops      = "weaver_shape_class_drops_total"
+	metricClassOverlimits = "weaver_shape_class_overlimits_total"
+	metricClassBacklog    = "weaver_shape_class_backlog_bytes"
+	metricPolicyPackets   = "weaver_policy_packets_total"
+	metricPolicyBytes     = "weaver_policy_bytes_total"
+	metricCollectorUp     = "weaver_exporter_collector_up"
+)
+
+// Collector names reported by metricCollectorUp.
+const (
+	collectorShape  = "shape"
+	collectorPolicy = "policy"
+)
+
+// snapshot is one scrape's worth of kernel state.
+type snapshot struct {
+	classes  []shape.ClassSample
+	policies []policy.PolicyCounter
+	up       map[string]bool
+}
+
+// collect reads both collectors. A failing collector is logged and reported
+// as down rather than failing the scrape, so a broken nft read does not also
+// blank the tc metrics (and vice versa).
+func (e *Exporter) collect(ctx context.Context) snapshot {
+	s := snapshot{up: map[string]bool{}}
+
+	classes, err := e.collectClasses(ctx)
+	if err != nil {
+		logx.As().Warn().Err(err).Msg("traffic shaper exporter: failed to read tc class stats")
+	}
+	s.classes, s.up[collectorShape] = classes, err == nil
+
+	policies, err := e.policies.Counters(ctx)
+	if err != nil {
+		logx.As().Warn().Err(err).Msg("traffic shaper exporter: failed to read nft policy counters")
+	}
+	s.policies, s.up[collectorPolicy] = policies, err == nil
+
+	return s
+}
+
+// collectClasses samples the egress classes on the egress NIC and the ingress
+// classes on every candidate veth. A veth that fails to sample is skipped: it
+// most likely vanished with its pod between the interface listing and the tc
+// read, which is routine churn rather than a collector failure.
+func (e *Exporter) collectClasses(ctx context.Context) ([]shape.ClassSample, error) {
+	egress := e.egressIface
+	if egress == "" {
+		nic, err := e.classes.EgressInterface()
+		if err != nil {
+			return nil, err
+		}
+		egress = nic
+	}
+	out, err := e.classes.SampleClasses(ctx, shape.DirEgress, egress)
+	if err != nil {
+		return nil, err
+	}
+
+	ifaces, err := e.ingressIfaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, iface := range ifaces {
+		if iface == egress {
+			continue
+		}
+		samples, err := e.classes.SampleClasses(ctx, shape.DirIngress, iface)
+		if err != nil {
+			logx.As().Debug().Err(err).Str("iface", iface).Msg("traffic shaper exporter: skipping interface")
+			continue
+		}
+		out = append(out, samples...)
+	}
+	return out, nil
+}
+
+// WriteMetrics collects the current counters and writes them to w in the
+// Prometheus text exposition format (version 0.0.4).
+func (e *Exporter) WriteMetrics(ctx context.Context, w io.Writer) {
+	writeSnapshot(w, e.collect(ctx))
+}
+
+// writeSnapshot renders s. Families are written in a fixed order and samples
+// within a family in collection order, so the output is deterministic for a
+// given snapshot.
+func writeSnapshot(w io.Writer, s snapshot) {
+	classFamily := func(name, typ, help string, value func(shape.ClassSample) uint64) {
+		writeHeader(w, name, typ, help)
+		for _, c := range s.classes {
+			writeSample(w, name, value(c), "class", c.Class, "device", c.Iface, "direction", c.Dir)
+		}
+	}
+	classFamily(metricClassBytes, "counter", "Bytes sent through the tc class, CAKE tin or fq root.",
+		func(c shape.ClassSample) uint64 { return c.Bytes })
+	classFamily(metricClassPackets, "counter", "Packets sent through the tc class, CAKE tin or fq root.",
+		func(c shape.ClassSample) uint64 { return c.Packets })
+	classFamily(metricClassDrops, "counter", "Packets dropped by the tc class, CAKE tin or fq root.",
+		func(c shape.ClassSample) uint64 { return c.Drops })
+	classFamily(metricClassOverlimits, "counter", "Times the tc class or fq root exceeded its rate and had to borrow or delay.",
+		func(c shape.ClassSample) uint64 { return c.Overlimits })
+	classFamily(metricClassBacklog, "gauge", "Bytes currently queued in the tc class, CAKE tin or fq root.",
+		func(c shape.ClassSample) uint64 { return c.Backlog })
+
+	policyFamily := func(name, help string, value func(policy.PolicyCounter) uint64) {
+		writeHeader(w, name, "counter", help)
+		for _, p := range s.policies {
+			writeSample(w, name, value(p),
+				"action", string(p.Action), "class", p.Class, "direction", string(p.Direction), "policy", p.Policy)
+		}
+	}
+	policyFamily(metricPolicyPackets, "Packets matched by the policy's stamp or deny rules.",
+		func(p policy.PolicyCounter) uint64 { return p.Packets })
+	policyFamily(metricPolicyBytes, "Bytes matched by the policy's stamp or deny rules.",
+		func(p policy.PolicyCounter) uint64 { return p.Bytes })
+
+	writeHeader(w, metricCollectorUp, "gauge", "Whether the collector's last kernel read succeeded (1) or failed (0).")
+	names := make([]string, 0, len(s.up))
+	for n := range s.up {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		var v uint64
+		if s.up[n] {
+			v = 1
+		}
+		writeSample(w, metricCollectorUp, v, "collector", n)
+	}
+}
+
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// writeSample writes one sample line. labels alternates name, value.
+func writeSample(w io.Writer, name string, value uint64, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteString("{")
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(labels[i+1]))
+		b.WriteString(`"`)
+	}
+	b.WriteString("} ")
+	fmt.Fprintf(w, "%s%d\n", b.String(), value)
+}
+
+// labelEscaper applies the exposition format's label-value escaping.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
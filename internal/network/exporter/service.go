@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+const (
+	// ExporterService is the systemd unit that runs `solo-provisioner network
+	// exporter` on every node with traffic shaping enabled.
+	ExporterService = "solo-provisioner-network-exporter.service"
+
+	// ExporterServiceUnitPath is the absolute path where the unit file is
+	// installed so systemd can discover it.
+	ExporterServiceUnitPath = "/usr/lib/systemd/system/" + ExporterService
+
+	// exporterServiceTemplate is the static embedded unit file.
+	exporterServiceTemplate = "files/network/" + ExporterService
+)
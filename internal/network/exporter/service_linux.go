@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/templates"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	soos "github.com/hashgraph/solo-weaver/pkg/os"
+	"github.com/joomcode/errorx"
+)
+
+// EnsureExporterService installs (or updates) the
+// solo-provisioner-network-exporter.service unit, enables it for boot and
+// makes sure it runs. The on-disk unit is compared against the embedded copy,
+// so an unchanged, running unit is a fast no-op; a changed unit is written,
+// daemon-reloaded and restarted so it takes effect without a reboot. It
+// reports whether it wrote the unit or (re)started the service.
+func EnsureExporterService(ctx context.Context) (bool, error) {
+	content, err := templates.Files.ReadFile(exporterServiceTemplate)
+	if err != nil {
+		return false, errorx.InternalError.Wrap(err, "failed to read embedded %s", exporterServiceTemplate)
+	}
+
+	current, err := os.ReadFile(ExporterServiceUnitPath)
+	changed := err != nil || !bytes.Equal(current, content)
+	if changed {
+		if err := os.MkdirAll(filepath.Dir(ExporterServiceUnitPath), models.DefaultDirOrExecPerm); err != nil {
+			return false, errorx.ExternalError.Wrap(err, "failed to create %s", filepath.Dir(ExporterServiceUnitPath))
+		}
+		if err := os.WriteFile(ExporterServiceUnitPath, content, models.DefaultFilePerm); err != nil {
+			return false, errorx.ExternalError.Wrap(err, "failed to write %s", ExporterServiceUnitPath)
+		}
+		if err := soos.DaemonReload(ctx); err != nil {
+			return true, err
+		}
+		if err := soos.EnableService(ctx, ExporterService); err != nil {
+			logx.As().Warn().Err(err).Str("service", ExporterService).Msg("could not enable network exporter service at boot")
+		}
+	}
+
+	if !changed {
+		if running, _ := soos.IsServiceRunning(ctx, ExporterService); running {
+			return false, nil
+		}
+	}
+	return true, soos.RestartService(ctx, ExporterService)
+}
+
+// RemoveExporterService is the teardown counterpart to EnsureExporterService:
+// it stops and disables solo-provisioner-network-exporter.service, removes
+// the unit file, then daemon-reloads.
+//
+// Idempotent: an already-absent unit is not an error, and a stop that fails
+// is logged and ignored.
+func RemoveExporterService(ctx context.Context) error {
+	if _, err := os.Stat(ExporterServiceUnitPath); err != nil {
+		return nil
+	}
+
+	if running, _ := soos.IsServiceRunning(ctx, ExporterService); running {
+		if err := soos.StopService(ctx, ExporterService); err != nil {
+			logx.As().Warn().Err(err).Str("service", ExporterService).
+				Msg("could not stop network exporter service; continuing teardown")
+		}
+	}
+	if err := soos.DisableService(ctx, ExporterService); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to disable %s", ExporterService)
+	}
+	if err := os.Remove(ExporterServiceUnitPath); err != nil && !os.IsNotExist(err) {
+		return errorx.ExternalError.Wrap(err, "failed to remove unit file %s", ExporterServiceUnitPath)
+	}
+	return soos.DaemonReload(ctx)
+}
@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package exporter
+
+import "context"
+
+// EnsureExporterService is a no-op on non-Linux platforms. The exporter unit
+// is a Linux systemd concept; the package compiles and tests on all
+// platforms, but the service management calls only run on Linux.
+func EnsureExporterService(_ context.Context) (bool, error) { return false, nil }
+
+// RemoveExporterService is a no-op on non-Linux platforms, for the same
+// reason as EnsureExporterService.
+func RemoveExporterService(_ context.Context) error { return nil }
@@ -3,9 +3,14 @@
 package policy
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/hashgraph/solo-weaver/pkg/sanity"
 	"github.com/joomcode/errorx"
 )
 
@@ -42,7 +47,9 @@ type class struct {
 // classMap is the stable class→mark/priority/direction map. The `network shape`
 // command sets each class's bandwidth; the name→priority encoding itself is
 // fixed here in code and does not depend on shape state. `--stamp` /
-// `--reply-stamp` referencing a name absent from this map is a create-time error.
+// `--reply-stamp` referencing a name absent from this map is a create-time
+// error unless it names a custom class registered with `network shape create
+// --class <name> --direction <dir>` (see lookupCustomClass).
 var classMap = map[string]class{
 	"publisher":         {Mark: 0x10, Priority: 0x10010, Direction: DirectionIngress},
 	"backfill-response": {Mark: 0x20, Priority: 0x10020, Direction: DirectionIngress},
@@ -52,11 +59,32 @@ var classMap = map[string]class{
 	"reserve-egress":    {Mark: 0x60, Priority: 0x10060, Direction: DirectionEgress},
 }
 
+// customClassDir is the shape custom-class registry read by lookupClass. A var
+// (rather than ShapeCustomClassDir directly) so tests can point it at a temp
+// dir.
+var customClassDir = ShapeCustomClassDir
+
+// customClassRef is the minimal view of a shape custom class definition needed
+// to stamp it: its direction and hex classid minor. Avoids importing
+// internal/network/shape.
+type customClassRef struct {
+	Dir   string `json:"dir"`
+	Minor string `json:"minor"`
+}
+
 // lookupClass resolves a class name to its mark/priority, returning an
 // IllegalArgument error naming the known classes when the reference is
-// undeclared.
+// undeclared. Names outside classMap resolve against the shape custom-class
+// registry, using the same encoding as the built-in classes: the mark is the
+// classid minor and the priority is 0x10000 | minor.
 func lookupClass(name string) (class, error) {
-	c, ok := classMap[name]
+	if c, ok := classMap[name]; ok {
+		return c, nil
+	}
+	c, ok, err := lookupCustomClass(name)
+	if err != nil {
+		return class{}, err
+	}
 	if !ok {
 		return class{}, errorx.IllegalArgument.New(
 			"unknown class %q: must be one of %s", name, strings.Join(knownClasses(), ", "))
@@ -64,13 +92,50 @@ func lookupClass(name string) (class, error) {
 	return c, nil
 }
 
-// knownClasses returns the declared class names in sorted order for stable
-// error messages.
+// lookupCustomClass reads name's definition from the shape custom-class
+// registry. It returns ok=false (and no error) when no such class is
+// registered.
+func lookupCustomClass(name string) (class, bool, error) {
+	if sanity.ValidateIdentifier(name) != nil {
+		return class{}, false, nil
+	}
+	path := filepath.Join(customClassDir, name+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return class{}, false, nil
+		}
+		return class{}, false, errorx.ExternalError.Wrap(err, "failed to read custom class %s", path)
+	}
+	var ref customClassRef
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return class{}, false, errorx.IllegalFormat.Wrap(err, "failed to parse custom class %s", path)
+	}
+	minor, err := strconv.ParseUint(ref.Minor, 16, 16)
+	if err != nil || minor == 0 {
+		return class{}, false, errorx.IllegalFormat.New("custom class %s has an invalid classid minor %q", path, ref.Minor)
+	}
+	dir := Direction(ref.Dir)
+	if dir != DirectionIngress && dir != DirectionEgress {
+		return class{}, false, errorx.IllegalFormat.New("custom class %s has an invalid direction %q", path, ref.Dir)
+	}
+	return class{Mark: uint32(minor), Priority: 0x10000 | uint32(minor), Direction: dir}, true, nil
+}
+
+// knownClasses returns the declared class names — built-in and registered
+// custom classes — in sorted order for stable error messages.
 func knownClasses() []string {
 	names := make([]string, 0, len(classMap))
 	for n := range classMap {
 		names = append(names, n)
 	}
+	if entries, err := os.ReadDir(customClassDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+				names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+			}
+		}
+	}
 	sort.Strings(names)
 	return names
 }
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeCustomClass drops a shape custom-class definition into a temp registry
+// and points lookupClass at it for the duration of the test.
+func writeCustomClass(t *testing.T, name, body string) {
+	t.Helper()
+	dir := t.TempDir()
+	prev := customClassDir
+	customClassDir = dir
+	t.Cleanup(func() { customClassDir = prev })
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".json"), []byte(body), 0o644))
+}
+
+func TestLookupClass_CustomClass(t *testing.T) {
+	writeCustomClass(t, "mirror-importer", `{"name":"mirror-importer","dir":"egress","minor":"7a"}`)
+
+	c, err := lookupClass("mirror-importer")
+	require.NoError(t, err)
+	require.Equal(t, class{Mark: 0x7a, Priority: 0x1007a, Direction: DirectionEgress}, c)
+	require.Contains(t, knownClasses(), "mirror-importer")
+}
+
+func TestLookupClass_CustomClassInvalid(t *testing.T) {
+	writeCustomClass(t, "broken", `{"name":"broken","dir":"sideways","minor":"70"}`)
+
+	_, err := lookupClass("broken")
+	require.ErrorContains(t, err, "invalid direction")
+}
+
+func TestValidate_StampCustomClass(t *testing.T) {
+	writeCustomClass(t, "sidecar", `{"name":"sidecar","dir":"egress","minor":"70"}`)
+
+	p := &Policy{Name: "sidecar-out", Action: ActionStamp, Stamp: "sidecar", Ports: []string{"5432"}}
+	require.NoError(t, p.Validate([]string{"10.2.0.0/16"}))
+	require.Equal(t, DirectionEgress, p.Direction, "direction is derived from the custom class")
+
+	doc, err := Render([]*Policy{p}, nil, "10.4.0.0/24")
+	require.NoError(t, err)
+	require.Contains(t, doc, "meta priority set 0x10070")
+}
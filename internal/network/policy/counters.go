@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import "context"
+
+// CounterValue is the live value of one nft named counter. Both fields are
+// cumulative since the table was last applied.
+type CounterValue struct {
+	Packets uint64
+	Bytes   uint64
+}
+
+// PolicyCounter is one registry policy's match counter, labeled with the
+// registry fields a dashboard groups by. Class and Direction are empty for a
+// deny policy, which stamps nothing and has no direction of its own.
+type PolicyCounter struct {
+	Policy    string
+	Action    Action
+	Class     string
+	Direction Direction
+	CounterValue
+}
+
+// Counters returns the live match counter of every registry policy, in name
+// order. A policy whose counter is absent from the kernel (the table has not
+// been re-rendered since the policy was created, or has not been applied at
+// all) is omitted rather than reported as zero, so a scraper never sees a
+// spurious reset. No lock is taken — Counters is read-only.
+func (m *Manager) Counters(ctx context.Context) ([]PolicyCounter, error) {
+	policies, err := loadAll(m.registryDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	live, err := m.runner.ListCounters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PolicyCounter, 0, len(policies))
+	for _, p := range policies {
+		v, ok := live[CounterName(p.Name)]
+		if !ok {
+			continue
+		}
+		out = append(out, PolicyCounter{
+			Policy:       p.Name,
+			Action:       p.Action,
+			Class:        p.Stamp,
+			Direction:    p.Direction,
+			CounterValue: v,
+		})
+	}
+	return out, nil
+}
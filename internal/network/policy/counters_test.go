@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounters_LabelsRegistryPoliciesAndSkipsMissing(t *testing.T) {
+	r := newFakeRunner()
+	m, _, regDir := newTestManager(t, r)
+	for _, p := range sampleBNPolicies() {
+		require.NoError(t, writeEntry(regDir, p))
+	}
+	r.counters = map[string]CounterValue{
+		CounterName("bn-publisher"):  {Packets: 10, Bytes: 15000},
+		CounterName("bn-restricted"): {Packets: 3, Bytes: 180},
+		"unrelated":                  {Packets: 1, Bytes: 1},
+	}
+
+	got, err := m.Counters(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []PolicyCounter{
+		{Policy: "bn-publisher", Action: ActionStamp, Class: "publisher", Direction: DirectionIngress,
+			CounterValue: CounterValue{Packets: 10, Bytes: 15000}},
+		{Policy: "bn-restricted", Action: ActionDeny,
+			CounterValue: CounterValue{Packets: 3, Bytes: 180}},
+	}, got, "policies without a live counter are omitted, and unregistered counters ignored")
+}
+
+func TestCounters_EmptyRegistry(t *testing.T) {
+	m, _, _ := newTestManager(t, newFakeRunner())
+	got, err := m.Counters(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
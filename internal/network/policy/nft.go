@@ -5,6 +5,7 @@ package policy
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"os/exec"
 	"strings"
@@ -45,6 +46,11 @@ type Runner interface {
 	// membership before the destructive delete/recreate Apply() performs, so
 	// it can be restored afterward (see Manager.Create for why).
 	ListElements(ctx context.Context, set string) ([]string, error)
+	// ListCounters returns the live value of every named counter in the table
+	// (`nft -j list counters table inet weaver-workload-policy`), keyed by
+	// counter name, or nil if the table does not exist. Read-only; backs
+	// Manager.Counters for the metrics exporter.
+	ListCounters(ctx context.Context) (map[string]CounterValue, error)
 	// List returns the rendered inet weaver-workload-policy table (`nft list table inet weaver-workload-policy`).
 	List(ctx context.Context) (string, error)
 	// Delete removes the inet weaver-workload-policy table (`nft delete table inet weaver-workload-policy`).
@@ -202,6 +208,53 @@ func parseElementsLine(output string) []string {
 	return out
 }
 
+// nftCountersJSON is the subset of `nft -j list counters table …` output we
+// consume: the top-level array of objects, of which only the "counter" entries
+// matter (the leading "metainfo" entry is skipped).
+type nftCountersJSON struct {
+	Nftables []struct {
+		Counter *struct {
+			Name    string `json:"name"`
+			Packets uint64 `json:"packets"`
+			Bytes   uint64 `json:"bytes"`
+		} `json:"counter"`
+	} `json:"nftables"`
+}
+
+func (r *execRunner) ListCounters(ctx context.Context) (map[string]CounterValue, error) {
+	args := append([]string{"-j", "list", "counters", "table"}, tableArgs...)
+	cmd := exec.CommandContext(ctx, r.bin, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Same reasoning as ListElements: an absent table is "no counters",
+		// anything else is a real failure the exporter must surface.
+		if isSetNotExistError(stderr.String()) {
+			return nil, nil
+		}
+		return nil, errorx.ExternalError.Wrap(err, "nft list counters table %s failed: %s", TableName, strings.TrimSpace(stderr.String()))
+	}
+	return parseCountersJSON(stdout.Bytes())
+}
+
+// parseCountersJSON decodes `nft -j list counters` output into a name-keyed
+// map. Split from ListCounters so the decoding is unit-testable without nft.
+func parseCountersJSON(out []byte) (map[string]CounterValue, error) {
+	var raw nftCountersJSON
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to parse nft counters JSON")
+	}
+	counters := make(map[string]CounterValue)
+	for _, o := range raw.Nftables {
+		if o.Counter == nil || o.Counter.Name == "" {
+			continue
+		}
+		counters[o.Counter.Name] = CounterValue{Packets: o.Counter.Packets, Bytes: o.Counter.Bytes}
+	}
+	return counters, nil
+}
+
 func (r *execRunner) List(ctx context.Context) (string, error) {
 	cmd := exec.CommandContext(ctx, r.bin, append([]string{"list", "table"}, tableArgs...)...)
 	var stdout, stderr bytes.Buffer
@@ -13,3 +13,18 @@ func TestIsSetNotExistError(t *testing.T) {
 	require.False(t, isSetNotExistError("Error: Operation not permitted"))
 	require.False(t, isSetNotExistError(""))
 }
+
+func TestParseCountersJSON(t *testing.T) {
+	out := []byte(`{"nftables": [{"metainfo": {"version": "1.0.9", "json_schema_version": 1}},
+		{"counter": {"family": "inet", "name": "bn-publisher_cnt", "table": "weaver-workload-policy", "handle": 3, "packets": 12, "bytes": 3456}},
+		{"counter": {"family": "inet", "name": "bn-restricted_cnt", "table": "weaver-workload-policy", "handle": 4, "packets": 0, "bytes": 0}}]}`)
+	got, err := parseCountersJSON(out)
+	require.NoError(t, err)
+	require.Equal(t, map[string]CounterValue{
+		"bn-publisher_cnt":  {Packets: 12, Bytes: 3456},
+		"bn-restricted_cnt": {},
+	}, got)
+
+	_, err = parseCountersJSON([]byte("not json"))
+	require.Error(t, err)
+}
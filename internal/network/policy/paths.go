@@ -32,6 +32,12 @@ const (
 	// reboot.
 	RegistryDir = "/etc/solo-provisioner/policies"
 
+	// ShapeCustomClassDir mirrors internal/network/shape.CustomClassConfigDir:
+	// the registry of operator-defined shape classes that --stamp /
+	// --reply-stamp may reference beyond the built-in classMap. Duplicated here
+	// to avoid a cross-package import; the values must stay in sync.
+	ShapeCustomClassDir = "/etc/solo-provisioner/network/shape/custom-classes"
+
 	// NetworkNftService is the shared oneshot unit that loads the network nft
 	// tables at boot. It is shared with internal/network/firewall; this package
 	// ensures it is installed and enabled on the first policy mutation but never
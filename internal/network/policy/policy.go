@@ -284,6 +284,14 @@ func PortsSetName(policyName string) string {
 	return policyName + "_ports"
 }
 
+// CounterName returns the name of the nft counter object that tallies a
+// policy's matches (`<name>_cnt`). Rendered by renderCounterDecls, referenced by
+// every stamp and deny rule the policy renders (in both family chains, so one
+// counter covers v4 and v6), and read back by Manager.Counters for the exporter.
+func CounterName(policyName string) string {
+	return policyName + "_cnt"
+}
+
 // isSpecific reports whether p is a "specific" stamp policy for tier-3/4
 // grouping and overlap purposes: an --stamp policy that is not --from-entity
 // world (i.e. one that renders an IP-set clause). Deny policies and
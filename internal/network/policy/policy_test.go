@@ -36,6 +36,7 @@ type fakeRunner struct {
 	applyErr     error
 	listElemErr  error
 	setElemOrder []string // set names in the order SetElements was called
+	counters     map[string]CounterValue
 }
 
 func newFakeRunner() *fakeRunner { return &fakeRunner{elements: map[string][]string{}} }
@@ -170,6 +171,9 @@ func (f *fakeRunner) ListElements(_ context.Context, set string) ([]string, erro
 	}
 	return append([]string(nil), f.elements[set]...), nil
 }
+func (f *fakeRunner) ListCounters(context.Context) (map[string]CounterValue, error) {
+	return f.counters, nil
+}
 func (f *fakeRunner) List(context.Context) (string, error) { return f.applied, nil }
 func (f *fakeRunner) Delete(context.Context) error {
 	// Mirrors `nft delete table`: the table and every set in it are gone.
@@ -243,7 +247,7 @@ func TestRender_DualStackGolden(t *testing.T) {
 	// Spot-check the key v6 constructs are present.
 	require.Contains(t, doc, "set bn-publisher6 { type ipv6_addr; flags interval; }")
 	require.Contains(t, doc, "set bn-backfill6 { type ipv6_addr . inet_service; }")
-	require.Contains(t, doc, "ip6 saddr @bn-restricted6 drop")
+	require.Contains(t, doc, "ip6 saddr @bn-restricted6 counter name \"bn-restricted_cnt\" drop")
 	require.Contains(t, doc, "ip6 daddr 2001:db8:c0de::/64 ip6 saddr @bn-publisher6")
 }
 
@@ -334,8 +338,8 @@ func TestRender_TierOrderInvariants(t *testing.T) {
 	for _, tc := range []struct {
 		chain, deny, specific, fallthr string
 	}{
-		{chainV4, "ip saddr @bn-restricted drop", "@bn-partner-out ", "@bn-public-out_ports"},
-		{chainV6, "ip6 saddr @bn-restricted6 drop", "@bn-partner-out6 ", "@bn-public-out_ports"},
+		{chainV4, "ip saddr @bn-restricted counter name \"bn-restricted_cnt\" drop", "@bn-partner-out ", "@bn-public-out_ports"},
+		{chainV6, "ip6 saddr @bn-restricted6 counter name \"bn-restricted_cnt\" drop", "@bn-partner-out6 ", "@bn-public-out_ports"},
 	} {
 		t.Run(tc.chain, func(t *testing.T) {
 			body := chainBody(t, doc, tc.chain)
@@ -414,14 +418,14 @@ func TestRender_WorkedExamples(t *testing.T) {
 	require.NoError(t, err)
 
 	// publisher stamp.
-	require.Contains(t, doc, "ip daddr 10.4.0.0/24 ip saddr @bn-publisher tcp dport @bn-publisher_ports meta priority set 0x10010 accept")
+	require.Contains(t, doc, "ip daddr 10.4.0.0/24 ip saddr @bn-publisher tcp dport @bn-publisher_ports counter name \"bn-publisher_cnt\" meta priority set 0x10010 accept")
 	// from-entity world fallthrough (no @set clause).
-	require.Contains(t, doc, "ip daddr 10.4.0.0/24 tcp dport @bn-subscriber-in_ports meta priority set 0x10030 accept")
+	require.Contains(t, doc, "ip daddr 10.4.0.0/24 tcp dport @bn-subscriber-in_ports counter name \"bn-subscriber-in_cnt\" meta priority set 0x10030 accept")
 	// deny (both directions).
-	require.Contains(t, doc, "ip saddr @bn-restricted drop")
-	require.Contains(t, doc, "ip daddr @bn-restricted drop")
+	require.Contains(t, doc, "ip saddr @bn-restricted counter name \"bn-restricted_cnt\" drop")
+	require.Contains(t, doc, "ip daddr @bn-restricted counter name \"bn-restricted_cnt\" drop")
 	// reply-stamp compound-key forward rule + ct mark write.
-	require.Contains(t, doc, "ip saddr 10.4.0.0/24 ip daddr . tcp dport @bn-backfill ct mark set 0x20 meta priority set 0x10060 accept")
+	require.Contains(t, doc, "ip saddr 10.4.0.0/24 ip daddr . tcp dport @bn-backfill counter name \"bn-backfill_cnt\" ct mark set 0x20 meta priority set 0x10060 accept")
 	// compound set schema, no `flags interval`.
 	require.Contains(t, doc, "set bn-backfill { type ipv4_addr . inet_service; }")
 }
@@ -457,7 +461,7 @@ func TestRender_DenyOnlyDoesNotRequirePodCIDR(t *testing.T) {
 	deny := []*Policy{{Name: "bn-restricted", Action: ActionDeny, CreatedAt: fixedTime()}}
 	doc, err := Render(deny, nil, "")
 	require.NoError(t, err, "a deny-only chain never references POD_CIDR")
-	require.Contains(t, doc, "ip saddr @bn-restricted drop")
+	require.Contains(t, doc, "ip saddr @bn-restricted counter name \"bn-restricted_cnt\" drop")
 }
 
 func TestCreate_PersistsAndSeedsMembership(t *testing.T) {
@@ -66,6 +66,7 @@ func Render(policies []*Policy, membership map[string][]string, podCIDRs ...stri
 	if err != nil {
 		return "", err
 	}
+	counterLines := renderCounterDecls(policies)
 	v4Lines, err := renderFamilyChain(policies, familyV4(podV4))
 	if err != nil {
 		return "", err
@@ -87,6 +88,10 @@ func Render(policies []*Policy, membership map[string][]string, podCIDRs ...stri
 		b.WriteString(strings.Join(setLines, "\n"))
 		b.WriteString("\n\n")
 	}
+	if len(counterLines) > 0 {
+		b.WriteString(strings.Join(counterLines, "\n"))
+		b.WriteString("\n\n")
+	}
 	b.WriteString(strings.Join(baseChainLines(), "\n"))
 	b.WriteString("\n\n")
 	writeChain(&b, chainV4, v4Lines)
@@ -288,6 +293,30 @@ func renderSetDecls(policies []*Policy, membership map[string][]string) ([]strin
 	return lines, nil
 }
 
+// renderCounterDecls emits one named counter object per policy, name-sorted like
+// the sets. Every policy renders at least one stamp or deny rule that references
+// its counter, so the declarations are unconditional; a counter whose rules are
+// suppressed (a stamp policy in a family with no pod CIDR) simply stays at zero.
+//
+// The counters are reset whenever the table is re-applied -- the document's
+// `delete table` prefix recreates every object -- so a scraper must treat them
+// as ordinary resetting Prometheus counters, not lifetime totals.
+func renderCounterDecls(policies []*Policy) []string {
+	lines := make([]string, 0, len(policies))
+	for _, p := range policies {
+		lines = append(lines, fmt.Sprintf("\tcounter %s { }", CounterName(p.Name)))
+	}
+	return lines
+}
+
+// counterRef renders the statement that feeds a rule's matches into the
+// policy's named counter. It sits after every match clause and ahead of any
+// mangling statement or verdict, so it counts exactly the packets the rule acts
+// on.
+func counterRef(p *Policy) string {
+	return fmt.Sprintf(" counter name \"%s\"", CounterName(p.Name))
+}
+
 // setDecl renders one set declaration, appending an `elements = { … }` clause
 // when the set has members. Elements are canonicalized so the render is a pure
 // function of set contents — RenderWeaverNft's SHA-256 skip and the daemon's
@@ -482,8 +511,8 @@ func orderByGroupThenCreatedAt(policies []*Policy) []*Policy {
 func renderDenyRules(p *Policy, f family) []string {
 	if !p.hasPortsSet() {
 		return []string{
-			fmt.Sprintf("\t\t%s saddr @%s drop", f.proto, f.setName(p.Name)),
-			fmt.Sprintf("\t\t%s daddr @%s drop", f.proto, f.setName(p.Name)),
+			fmt.Sprintf("\t\t%s saddr @%s%s drop", f.proto, f.setName(p.Name), counterRef(p)),
+			fmt.Sprintf("\t\t%s daddr @%s%s drop", f.proto, f.setName(p.Name), counterRef(p)),
 		}
 	}
 	if f.podCIDR == "" {
@@ -495,7 +524,7 @@ func renderDenyRules(p *Policy, f family) []string {
 	if p.hasCIDRSet() {
 		fmt.Fprintf(&b, " %s saddr @%s", f.proto, f.setName(p.Name))
 	}
-	fmt.Fprintf(&b, " tcp dport @%s ct direction original drop", PortsSetName(p.Name))
+	fmt.Fprintf(&b, " tcp dport @%s ct direction original%s drop", PortsSetName(p.Name), counterRef(p))
 	return []string{b.String()}
 }
 
@@ -531,8 +560,8 @@ func renderStampRule(p *Policy, f family) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("\t\t%s saddr %s %s daddr . tcp dport @%s ct mark set %s meta priority set %s accept",
-			f.proto, f.podCIDR, f.proto, f.setName(p.Name), hex(reply.Mark), hex(fwd.Priority)), nil
+		return fmt.Sprintf("\t\t%s saddr %s %s daddr . tcp dport @%s%s ct mark set %s meta priority set %s accept",
+			f.proto, f.podCIDR, f.proto, f.setName(p.Name), counterRef(p), hex(reply.Mark), hex(fwd.Priority)), nil
 	}
 
 	return renderPlainStampRule(p, f, fwd)
@@ -564,6 +593,7 @@ func renderPlainStampRule(p *Policy, f family, fwd class) (string, error) {
 	default:
 		return "", errorx.AssertionFailed.New("stamp policy %q has no direction", p.Name)
 	}
+	b.WriteString(counterRef(p))
 	b.WriteString(fmt.Sprintf(" meta priority set %s accept", hex(fwd.Priority)))
 	return b.String(), nil
 }
@@ -24,8 +24,8 @@ func TestRender_PortScopedDenyIsPodScopedInBothFamilies(t *testing.T) {
 	require.NotContains(t, doc, "set bn-health {",
 		"--from-entity world renders no membership set to match on")
 
-	require.Contains(t, doc, "\t\tip daddr 10.4.0.0/24 tcp dport @bn-health_ports ct direction original drop")
-	require.Contains(t, doc, "\t\tip6 daddr 2001:db8:c0de::/64 tcp dport @bn-health_ports ct direction original drop")
+	require.Contains(t, doc, "\t\tip daddr 10.4.0.0/24 tcp dport @bn-health_ports ct direction original counter name \"bn-health_cnt\" drop")
+	require.Contains(t, doc, "\t\tip6 daddr 2001:db8:c0de::/64 tcp dport @bn-health_ports ct direction original counter name \"bn-health_cnt\" drop")
 }
 
 // A listener port sits inside the default ephemeral range (32768-60999), so an
@@ -56,7 +56,7 @@ func TestRender_PortScopedDenySkipsFamilyWithoutPodCIDR(t *testing.T) {
 	doc, err := Render([]*Policy{healthDeny()}, nil, "10.4.0.0/24")
 	require.NoError(t, err)
 
-	require.Contains(t, doc, "\t\tip daddr 10.4.0.0/24 tcp dport @bn-health_ports ct direction original drop")
+	require.Contains(t, doc, "\t\tip daddr 10.4.0.0/24 tcp dport @bn-health_ports ct direction original counter name \"bn-health_cnt\" drop")
 	require.NotContains(t, doc, "ip6 daddr",
 		"a family with no pod CIDR has no pods to protect, so it renders no drop")
 	require.Contains(t, doc, "chain forward_ipv6 { }")
@@ -80,8 +80,8 @@ func TestRender_MembershipOnlyDenyIsUnchanged(t *testing.T) {
 	doc, err := Render([]*Policy{{Name: "bn-restricted", Action: ActionDeny}}, nil, "10.4.0.0/24")
 	require.NoError(t, err)
 
-	require.Contains(t, doc, "\t\tip saddr @bn-restricted drop")
-	require.Contains(t, doc, "\t\tip daddr @bn-restricted drop")
+	require.Contains(t, doc, "\t\tip saddr @bn-restricted counter name \"bn-restricted_cnt\" drop")
+	require.Contains(t, doc, "\t\tip daddr @bn-restricted counter name \"bn-restricted_cnt\" drop")
 	body := chainBody(t, doc, chainV4)
 	require.NotContains(t, body, "ct direction original",
 		"the membership deny drops on the set alone; there is no port to collide with")
@@ -93,7 +93,7 @@ func TestRender_PortScopedDenyKeepsItsMembershipClause(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Contains(t, doc,
-		"\t\tip daddr 10.4.0.0/24 ip saddr @bn-quarantine-http tcp dport @bn-quarantine-http_ports ct direction original drop")
+		"\t\tip daddr 10.4.0.0/24 ip saddr @bn-quarantine-http tcp dport @bn-quarantine-http_ports ct direction original counter name \"bn-quarantine-http_cnt\" drop")
 	require.NotContains(t, doc, "tcp sport @bn-quarantine-http_ports")
 }
 
@@ -108,8 +108,8 @@ func TestRender_PortScopedDenyPrecedesClassification(t *testing.T) {
 	require.NoError(t, err)
 
 	body := chainBody(t, doc, chainV4)
-	dropIdx := strings.Index(body, "@bn-health_ports ct direction original drop")
-	stampIdx := strings.Index(body, "tcp dport @bn-subscriber-in_ports meta priority")
+	dropIdx := strings.Index(body, "@bn-health_ports ct direction original counter name \"bn-health_cnt\" drop")
+	stampIdx := strings.Index(body, "tcp dport @bn-subscriber-in_ports counter name \"bn-subscriber-in_cnt\" meta priority")
 	// Guard both lookups: a missing substring indexes to -1, which would satisfy
 	// the ordering assertion below and let the test pass with no drop at all.
 	require.Positive(t, dropIdx, "the health drop is missing from the chain")
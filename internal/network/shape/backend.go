@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package shape
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/joomcode/errorx"
+)
+
+// Root qdisc backends selectable per direction via DeviceConfig.Qdisc.
+const (
+	// QdiscHTB is the default backend: a root HTB qdisc, a trunk class 1:1 and
+	// one HTB class per shape class, each with its own leaf qdisc.
+	QdiscHTB = "htb"
+	// QdiscCake is a single root CAKE qdisc shaping the whole device at the
+	// trunk rate, with every class mapped onto one of its diffserv4 tins.
+	QdiscCake = "cake"
+	// QdiscFq is a single root fq qdisc pacing every flow to at most the trunk
+	// rate. It is classless: per-class rate/ceil/prio are recorded but not
+	// enforced.
+	QdiscFq = "fq"
+)
+
+// Leaf qdiscs selectable under the HTB backend via DeviceConfig.Leaf.
+const (
+	LeafFqCodel = "fq_codel" // default
+	LeafCake    = "cake"
+	LeafFq      = "fq"
+)
+
+// cakeDefaultFilterPref is the tc filter preference of the catch-all filter
+// that steers unstamped traffic into the default class's tin. It sorts after
+// every per-class filter, whose preference is the class minor (at most 0xff).
+const cakeDefaultFilterPref = "1000"
+
+// cakeTin is one diffserv4 tin. Index is the tin's position in CAKE's
+// priority-override order: skb->priority 1:<Index> selects it directly.
+type cakeTin struct {
+	Index int
+	Name  string
+}
+
+// cakeTins lists the diffserv4 tins in priority-override order (lowest first),
+// which is also the order `tc -s qdisc show` reports their counters in.
+var cakeTins = []cakeTin{
+	{Index: 1, Name: "bulk"},
+	{Index: 2, Name: "best-effort"},
+	{Index: 3, Name: "video"},
+	{Index: 4, Name: "voice"},
+}
+
+// cakeTinForPrio maps an HTB class priority (0 = highest) onto a diffserv4 tin:
+// 0 → voice, 1–2 → video, 3–5 → best-effort, 6–7 → bulk. The built-in classes
+// therefore land in distinct tins in both directions (partner/voice,
+// reserve-egress/video, public/best-effort; publisher/voice,
+// reserve-ingress/video, backfill-response/bulk).
+func cakeTinForPrio(prio int) cakeTin {
+	switch {
+	case prio <= 0:
+		return cakeTins[3]
+	case prio <= 2:
+		return cakeTins[2]
+	case prio <= 5:
+		return cakeTins[1]
+	default:
+		return cakeTins[0]
+	}
+}
+
+// qdiscBackend is the resolved root/leaf qdisc selection for one direction,
+// with the defaults filled in.
+type qdiscBackend struct {
+	Root string
+	Leaf string
+}
+
+// backend resolves the device's qdisc selection, defaulting an unset root to
+// HTB and an unset leaf to fq_codel (every device recorded before the backend
+// became selectable).
+func (d *DeviceConfig) backend() qdiscBackend {
+	b := qdiscBackend{Root: d.Qdisc, Leaf: d.Leaf}
+	if b.Root == "" {
+		b.Root = QdiscHTB
+	}
+	if b.Leaf == "" {
+		b.Leaf = LeafFqCodel
+	}
+	return b
+}
+
+// validateQdiscBackend checks a device's root and leaf qdisc selection. The
+// leaf only has meaning under the HTB root; CAKE and fq are classless roots.
+func validateQdiscBackend(root, leaf string) error {
+	switch root {
+	case "", QdiscHTB:
+	case QdiscCake, QdiscFq:
+		if leaf != "" {
+			return errorx.IllegalArgument.New(
+				"--leaf only applies to the %q root qdisc; the %q root has no per-class leaves", QdiscHTB, root)
+		}
+	default:
+		return errorx.IllegalArgument.New(
+			"--qdisc must be one of %q, %q or %q, got %q", QdiscHTB, QdiscCake, QdiscFq, root)
+	}
+	switch leaf {
+	case "", LeafFqCodel, LeafCake, LeafFq:
+		return nil
+	default:
+		return errorx.IllegalArgument.New(
+			"--leaf must be one of %q, %q or %q, got %q", LeafFqCodel, LeafCake, LeafFq, leaf)
+	}
+}
+
+// leafQdiscSpec returns the qdisc kind and options for an HTB leaf. A CAKE
+// leaf runs unlimited (its HTB class already shapes) in a single best-effort
+// tin, keeping only its flow and per-host isolation.
+func leafQdiscSpec(leaf string) []string {
+	switch leaf {
+	case LeafCake:
+		return []string{"cake", "unlimited", "besteffort"}
+	case LeafFq:
+		return []string{"fq"}
+	default:
+		return []string{"fq_codel"}
+	}
+}
+
+// stampPriority returns the skb->priority the policy plane stamps for a class
+// minor (0x10000 | minor, the classid 1:<minor>) in the 0x-prefixed hex form
+// tc's meta ematch accepts.
+func stampPriority(minor string) (string, error) {
+	v, err := strconv.ParseUint(minor, 16, 16)
+	if err != nil {
+		return "", errorx.IllegalArgument.Wrap(err, "invalid class minor %q", minor)
+	}
+	return "0x" + strconv.FormatUint(0x10000|v, 16), nil
+}
+
+// filterPref returns the tc filter preference for a class's tin filter: the
+// class minor in decimal, unique per class.
+func filterPref(minor string) (string, error) {
+	v, err := strconv.ParseUint(minor, 16, 16)
+	if err != nil {
+		return "", errorx.IllegalArgument.Wrap(err, "invalid class minor %q", minor)
+	}
+	return strconv.FormatUint(v, 10), nil
+}
+
+// tinFilter is one class's CAKE tin filter: traffic the policy plane stamped
+// with Priority is re-stamped with the tin's priority-override handle 1:<Tin>.
+// CAKE runs its filters before it selects a tin, so the stamp the policy plane
+// writes (which CAKE would otherwise ignore) picks the class's tin.
+type tinFilter struct {
+	Pref     string // tc filter preference (decimal class minor)
+	Priority string // stamped skb->priority, e.g. "0x10040"
+	Tin      int    // cakeTin.Index
+}
+
+// cakeTinFilters returns one tinFilter per class, in the order given.
+func cakeTinFilters(classes []*ClassConfig) ([]tinFilter, error) {
+	out := make([]tinFilter, 0, len(classes))
+	for _, cls := range classes {
+		ci, err := lookupClassInfo(cls.Name)
+		if err != nil {
+			return nil, err
+		}
+		pref, err := filterPref(ci.Minor)
+		if err != nil {
+			return nil, err
+		}
+		prio, err := stampPriority(ci.Minor)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, tinFilter{Pref: pref, Priority: prio, Tin: cakeTinForPrio(cls.Prio).Index})
+	}
+	return out, nil
+}
+
+// defaultClassTin returns the tin unstamped traffic is steered into: the tin of
+// the default class's recorded priority, or best-effort when that class has
+// no recorded config.
+func defaultClassTin(defaultMinor string, classes []*ClassConfig) cakeTin {
+	for _, cls := range classes {
+		if ci, err := lookupClassInfo(cls.Name); err == nil && ci.Minor == defaultMinor {
+			return cakeTinForPrio(cls.Prio)
+		}
+	}
+	return cakeTins[1]
+}
+
+// describeBackend renders the backend for `network shape show`.
+func describeBackend(b qdiscBackend) string {
+	switch b.Root {
+	case QdiscCake:
+		return "cake (diffserv4 tins: " + strings.Join(cakeTinNames(), ", ") + ")"
+	case QdiscFq:
+		return "fq (flat, per-flow maxrate pacing; classes not enforced)"
+	default:
+		return "htb (leaf " + b.Leaf + ")"
+	}
+}
+
+func cakeTinNames() []string {
+	names := make([]string, len(cakeTins))
+	for i, t := range cakeTins {
+		names[i] = t.Name
+	}
+	return names
+}
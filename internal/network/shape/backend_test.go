@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package shape
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateQdiscBackend(t *testing.T) {
+	require.NoError(t, validateQdiscBackend("", ""))
+	require.NoError(t, validateQdiscBackend(QdiscHTB, LeafCake))
+	require.NoError(t, validateQdiscBackend(QdiscCake, ""))
+	require.NoError(t, validateQdiscBackend(QdiscFq, ""))
+
+	err := validateQdiscBackend("prio", "")
+	require.ErrorContains(t, err, "--qdisc must be one of")
+	err = validateQdiscBackend(QdiscHTB, "sfq")
+	require.ErrorContains(t, err, "--leaf must be one of")
+	err = validateQdiscBackend(QdiscCake, LeafFq)
+	require.ErrorContains(t, err, "no per-class leaves")
+}
+
+func TestDeviceBackendDefaults(t *testing.T) {
+	require.Equal(t, qdiscBackend{Root: QdiscHTB, Leaf: LeafFqCodel}, (&DeviceConfig{}).backend())
+	require.Equal(t, qdiscBackend{Root: QdiscCake, Leaf: LeafFqCodel}, (&DeviceConfig{Qdisc: QdiscCake}).backend())
+}
+
+func TestCakeTinForPrio(t *testing.T) {
+	want := []string{"voice", "video", "video", "best-effort", "best-effort", "best-effort", "bulk", "bulk"}
+	for prio, name := range want {
+		require.Equal(t, name, cakeTinForPrio(prio).Name, "prio %d", prio)
+	}
+}
+
+func TestCakeTinFilters_MatchThePolicyPlaneStamp(t *testing.T) {
+	useTempCustomClassDir(t)
+	require.NoError(t, writeCustomClass(&CustomClass{Name: "mirror", Dir: DirEgress, Minor: "ff", Rate: "10mbit"}))
+
+	got, err := cakeTinFilters([]*ClassConfig{
+		{Name: "partner", Prio: 0},
+		{Name: "mirror", Prio: 6},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []tinFilter{
+		{Pref: "64", Priority: "0x10040", Tin: 4},
+		{Pref: "255", Priority: "0x100ff", Tin: 1},
+	}, got)
+}
+
+func TestDefaultClassTin(t *testing.T) {
+	classes := []*ClassConfig{{Name: "public", Prio: 5}, {Name: "reserve-egress", Prio: 1}}
+	require.Equal(t, "video", defaultClassTin("60", classes).Name)
+	require.Equal(t, "best-effort", defaultClassTin("60", nil).Name,
+		"a default class without a recorded config falls to best-effort")
+}
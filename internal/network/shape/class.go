@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashgraph/solo-weaver/pkg/sanity"
 	"github.com/joomcode/errorx"
 )
 
@@ -39,30 +40,57 @@ var classInfoMap = map[string]classInfo{
 }
 
 // lookupClassInfo resolves a class name to its classid/direction, returning an
-// error naming the known classes when the name is not recognised.
+// error naming the known classes when the name is not recognised. Built-in
+// classes resolve from classInfoMap; anything else is looked up in the custom
+// class registry (see RegisterCustomClass).
 func lookupClassInfo(name string) (classInfo, error) {
-	c, ok := classInfoMap[name]
-	if !ok {
-		return classInfo{}, errorx.IllegalArgument.New(
-			"unknown class %q: must be one of %s", name, strings.Join(knownClassNames(), ", "))
+	if c, ok := classInfoMap[name]; ok {
+		return c, nil
 	}
-	return c, nil
+	if sanity.ValidateIdentifier(name) == nil {
+		def, err := readCustomClass(name)
+		if err != nil {
+			return classInfo{}, err
+		}
+		if def != nil {
+			return def.classInfo(), nil
+		}
+	}
+	return classInfo{}, errorx.IllegalArgument.New(
+		"unknown class %q: must be one of %s", name, strings.Join(knownClassNames(), ", "))
 }
 
-// knownClassNames returns class names sorted for stable error messages.
+// allClassInfo returns the built-in classes merged with every registered
+// custom class.
+func allClassInfo() map[string]classInfo {
+	custom := customClassInfoMap()
+	out := make(map[string]classInfo, len(classInfoMap)+len(custom))
+	for n, c := range custom {
+		out[n] = c
+	}
+	for n, c := range classInfoMap {
+		out[n] = c
+	}
+	return out
+}
+
+// knownClassNames returns class names (built-in and custom) sorted for stable
+// error messages.
 func knownClassNames() []string {
-	names := make([]string, 0, len(classInfoMap))
-	for n := range classInfoMap {
+	all := allClassInfo()
+	names := make([]string, 0, len(all))
+	for n := range all {
 		names = append(names, n)
 	}
 	sort.Strings(names)
 	return names
 }
 
-// knownClassNamesForDir returns class names for the given direction, sorted.
+// knownClassNamesForDir returns class names (built-in and custom) for the
+// given direction, sorted.
 func knownClassNamesForDir(dir string) []string {
 	var names []string
-	for n, c := range classInfoMap {
+	for n, c := range allClassInfo() {
 		if c.Dir == dir {
 			names = append(names, n)
 		}
@@ -0,0 +1,295 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package shape
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/pkg/sanity"
+	"github.com/joomcode/errorx"
+)
+
+// Custom classes extend the six built-in classes in classInfoMap with
+// operator-defined HTB classes (e.g. a mirror importer sidecar on a block node
+// host). Their classid minors are auto-allocated from a reserved range that sits
+// above every built-in minor (0x10–0x60), so a custom class can never collide
+// with a built-in one, and the policy plane's mark/priority encoding
+// (mark = minor, priority = 0x10000 | minor) stays unique per class.
+const (
+	// CustomMinorFirst is the first classid minor handed out to custom classes.
+	CustomMinorFirst = 0x70
+	// CustomMinorLast is the last classid minor handed out to custom classes.
+	CustomMinorLast = 0xff
+)
+
+// customClassDir is the registry directory read by the custom-class lookups.
+// A var (rather than CustomClassConfigDir directly) so tests can point the
+// lookups at a temp dir.
+var customClassDir = CustomClassConfigDir
+
+// CustomClass is the persisted definition of one operator-defined tc class: its
+// name, direction and allocated classid minor, plus the default bandwidth the
+// class is created with. One JSON file per class under CustomClassConfigDir.
+//
+// The definition is the custom-class counterpart of a classInfoMap entry; the
+// class's live bandwidth is still the ClassConfig under ClassConfigDir, so
+// `network shape set` tunes a custom class exactly like a built-in one.
+type CustomClass struct {
+	Name      string    `json:"name"`
+	Dir       string    `json:"dir"`   // "ingress" or "egress"
+	Minor     string    `json:"minor"` // hex tc classid minor, e.g. "70"
+	Rate      string    `json:"rate"`
+	Ceil      string    `json:"ceil,omitempty"`
+	Prio      int       `json:"prio"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// classInfo converts the definition into the descriptor shared with built-in
+// classes. The fq_codel handle follows the built-in convention: "1" followed
+// by the minor.
+func (c *CustomClass) classInfo() classInfo {
+	return classInfo{Minor: c.Minor, Handle: "1" + c.Minor, Dir: c.Dir}
+}
+
+// customClassPath returns the on-disk path of a custom class definition.
+func customClassPath(name string) string {
+	return filepath.Join(customClassDir, name+".json")
+}
+
+// readCustomClass loads a custom class definition by name, returning nil if
+// not found.
+func readCustomClass(name string) (*CustomClass, error) {
+	return readConfigJSON[CustomClass](customClassPath(name))
+}
+
+// writeCustomClass atomically writes the custom class definition JSON.
+func writeCustomClass(c *CustomClass) error { return writeConfigJSON(customClassPath(c.Name), c) }
+
+// removeCustomClass deletes a custom class definition, ignoring not-found.
+func removeCustomClass(name string) error { return removeConfigFile(customClassPath(name)) }
+
+// removeCustomClassesForDir deletes every custom class definition for dir.
+// Used by the wholesale teardown paths.
+func removeCustomClassesForDir(dir string) error {
+	defs, err := loadCustomClasses()
+	if err != nil {
+		return err
+	}
+	for _, d := range defs {
+		if d.Dir != dir {
+			continue
+		}
+		if err := removeCustomClass(d.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCustomClasses loads every custom class definition, sorted by name. A
+// missing registry directory yields an empty slice.
+func loadCustomClasses() ([]*CustomClass, error) {
+	entries, err := os.ReadDir(customClassDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errorx.ExternalError.Wrap(err, "failed to read custom class dir %s", customClassDir)
+	}
+	var out []*CustomClass
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		c, err := readCustomClass(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// customClassInfoMap returns the name→classInfo map for every registered custom
+// class. A registry read error is logged and treated as "no custom classes" so
+// the built-in classes always keep resolving.
+func customClassInfoMap() map[string]classInfo {
+	defs, err := loadCustomClasses()
+	if err != nil {
+		logx.As().Warn().Err(err).Msg("failed to load custom shape classes; only built-in classes are available")
+		return nil
+	}
+	out := make(map[string]classInfo, len(defs))
+	for _, d := range defs {
+		out[d.Name] = d.classInfo()
+	}
+	return out
+}
+
+// IsBuiltinClass reports whether name is one of the fixed built-in classes.
+func IsBuiltinClass(name string) bool {
+	_, ok := classInfoMap[name]
+	return ok
+}
+
+// allocateCustomMinor returns the lowest free minor in
+// [CustomMinorFirst, CustomMinorLast], as tc hex notation, given the minors
+// already used by registered custom classes.
+func allocateCustomMinor(used []string) (string, error) {
+	taken := make(map[int64]bool, len(used))
+	for _, m := range used {
+		if v, err := strconv.ParseInt(m, 16, 32); err == nil {
+			taken[v] = true
+		}
+	}
+	for v := int64(CustomMinorFirst); v <= CustomMinorLast; v++ {
+		if !taken[v] {
+			return strconv.FormatInt(v, 16), nil
+		}
+	}
+	return "", errorx.IllegalState.New(
+		"no free classid minor left for a custom class: the reserved range 0x%x-0x%x is exhausted",
+		CustomMinorFirst, CustomMinorLast)
+}
+
+// validateCustomClassName checks that name is a safe identifier and does not
+// shadow a built-in class.
+func validateCustomClassName(name string) error {
+	if err := sanity.ValidateIdentifier(name); err != nil {
+		return errorx.IllegalArgument.Wrap(err, "invalid custom class name %q", name)
+	}
+	if IsBuiltinClass(name) {
+		return errorx.IllegalArgument.New("class %q is a built-in class and cannot be registered as a custom class", name)
+	}
+	return nil
+}
+
+// RegisterCustomClass records an operator-defined class — definition plus its
+// initial bandwidth config at def's default rate/ceil/prio — and, for egress
+// classes, re-renders TcEgressScriptPath and restarts bandwidth-shaper.service.
+// Ingress classes are written only; the daemon pod-lifecycle watcher installs
+// them on the next veth attach. The device for def.Dir must already exist.
+//
+// def.Minor is always allocated here from the reserved range; an existing
+// definition keeps its minor on --force so live policies that stamp the class
+// stay valid.
+//
+// Returns true if the class was registered or replaced, false if it already
+// existed and force was not set.
+func (m *Manager) RegisterCustomClass(ctx context.Context, def *CustomClass, force bool) (bool, error) {
+	if err := validateCustomClassName(def.Name); err != nil {
+		return false, err
+	}
+	if err := validateDir(def.Dir); err != nil {
+		return false, err
+	}
+	if err := validateClassFields(def.Rate, def.Ceil, def.Prio); err != nil {
+		return false, err
+	}
+
+	var changed bool
+	err := m.withLock(func() error {
+		dev, err := readDevice(def.Dir)
+		if err != nil {
+			return err
+		}
+		if dev == nil {
+			return errorx.IllegalState.New(
+				"device %q is not configured; run `network shape create --device %s` first",
+				def.Dir, def.Dir)
+		}
+
+		defs, err := loadCustomClasses()
+		if err != nil {
+			return err
+		}
+		var existing *CustomClass
+		used := make([]string, 0, len(defs))
+		for _, d := range defs {
+			if d.Name == def.Name {
+				existing = d
+				continue
+			}
+			used = append(used, d.Minor)
+		}
+		if existing != nil && !force {
+			logx.As().Warn().Str("class", def.Name).Msg(
+				"custom shape class already registered — flags not applied; pass --force to replace")
+			return nil
+		}
+		if existing != nil {
+			if existing.Dir != def.Dir {
+				return errorx.IllegalArgument.New(
+					"custom class %q is registered for the %s device; delete it before re-registering it for %s",
+					def.Name, existing.Dir, def.Dir)
+			}
+			def.Minor = existing.Minor
+			def.CreatedAt = existing.CreatedAt
+		} else {
+			if def.Minor, err = allocateCustomMinor(used); err != nil {
+				return err
+			}
+			if def.CreatedAt.IsZero() {
+				def.CreatedAt = time.Now().UTC()
+			}
+		}
+
+		cls := &ClassConfig{Name: def.Name, Rate: def.Rate, Ceil: def.Ceil, Prio: def.Prio, CreatedAt: def.CreatedAt}
+		siblings, err := loadClassesForDir(def.Dir)
+		if err != nil {
+			return err
+		}
+		if err := validateSumRates(siblings, cls, dev.Rate); err != nil {
+			return err
+		}
+
+		if err := writeCustomClass(def); err != nil {
+			return err
+		}
+		if err := writeClass(cls); err != nil {
+			return err
+		}
+		if def.Dir == DirEgress {
+			if err := m.applyEgressScript(ctx, "", applyRestart); err != nil {
+				return err
+			}
+		}
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+// ListCustomClasses returns a human-readable summary of the registered custom
+// class definitions.
+func (m *Manager) ListCustomClasses() (string, error) {
+	defs, err := loadCustomClasses()
+	if err != nil {
+		return "", err
+	}
+	if len(defs) == 0 {
+		return "no custom classes registered\n", nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "custom classes:\n")
+	for _, d := range defs {
+		ceil := d.Ceil
+		if ceil == "" {
+			ceil = d.Rate
+		}
+		fmt.Fprintf(&b, "  %-20s %-7s 1:%-3s default rate=%-10s ceil=%-10s prio=%d\n",
+			d.Name, d.Dir, d.Minor, d.Rate, ceil, d.Prio)
+	}
+	return b.String(), nil
+}
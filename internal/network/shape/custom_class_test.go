@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package shape
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// useTempCustomClassDir points the custom-class registry at a temp dir for the
+// duration of the test.
+func useTempCustomClassDir(t *testing.T) {
+	t.Helper()
+	prev := customClassDir
+	customClassDir = t.TempDir()
+	t.Cleanup(func() { customClassDir = prev })
+}
+
+func TestAllocateCustomMinor(t *testing.T) {
+	got, err := allocateCustomMinor(nil)
+	require.NoError(t, err)
+	require.Equal(t, "70", got, "first custom minor is the start of the reserved range")
+
+	got, err = allocateCustomMinor([]string{"70", "71", "73"})
+	require.NoError(t, err)
+	require.Equal(t, "72", got, "lowest free minor is reused")
+
+	var all []string
+	for v := CustomMinorFirst; v <= CustomMinorLast; v++ {
+		all = append(all, fmt.Sprintf("%x", v))
+	}
+	_, err = allocateCustomMinor(all)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exhausted")
+}
+
+func TestValidateCustomClassName(t *testing.T) {
+	require.NoError(t, validateCustomClassName("mirror-importer"))
+	require.ErrorContains(t, validateCustomClassName("partner"), "built-in")
+	require.ErrorContains(t, validateCustomClassName("../etc"), "invalid custom class name")
+	require.ErrorContains(t, validateCustomClassName(""), "invalid custom class name")
+}
+
+func TestLookupClassInfo_CustomClass(t *testing.T) {
+	useTempCustomClassDir(t)
+	require.NoError(t, writeCustomClass(&CustomClass{
+		Name: "mirror-importer", Dir: DirEgress, Minor: "7a", Rate: "50mbit", Prio: 3,
+	}))
+
+	ci, err := lookupClassInfo("mirror-importer")
+	require.NoError(t, err)
+	require.Equal(t, classInfo{Minor: "7a", Handle: "17a", Dir: DirEgress}, ci)
+
+	require.Contains(t, knownClassNamesForDir(DirEgress), "mirror-importer")
+	require.NotContains(t, knownClassNamesForDir(DirIngress), "mirror-importer")
+
+	_, err = lookupClassInfo("not-registered")
+	require.ErrorContains(t, err, "mirror-importer", "error lists the custom classes alongside the built-ins")
+}
+
+func TestLookupClassInfo_BuiltinWinsOverCustomFile(t *testing.T) {
+	useTempCustomClassDir(t)
+	// A hand-written definition shadowing a built-in name must not change it.
+	require.NoError(t, writeCustomClass(&CustomClass{Name: "partner", Dir: DirIngress, Minor: "80"}))
+
+	ci, err := lookupClassInfo("partner")
+	require.NoError(t, err)
+	require.Equal(t, "40", ci.Minor)
+	require.Equal(t, DirEgress, ci.Dir)
+}
+
+func TestRenderTcEgressScriptFromConfig_CustomClass(t *testing.T) {
+	useTempCustomClassDir(t)
+	require.NoError(t, writeCustomClass(&CustomClass{Name: "mirror-importer", Dir: DirEgress, Minor: "70"}))
+
+	dev := &DeviceConfig{Dir: DirEgress, Rate: "1gbit", DefaultClass: "reserve-egress"}
+	classes := []*ClassConfig{
+		{Name: "mirror-importer", Rate: "100mbit", Ceil: "200mbit", Prio: 4},
+		{Name: "reserve-egress", Rate: "300mbit", Ceil: "1gbit", Prio: 1},
+	}
+	out, err := renderTcEgressScriptFromConfig("eth0", dev, classes)
+	require.NoError(t, err)
+	require.Contains(t, out, `classid 1:70 htb rate "100mbit" ceil "200mbit" prio 4`)
+	require.Contains(t, out, `parent 1:70 handle 170: fq_codel`)
+}
+
+func TestRemoveCustomClassesForDir(t *testing.T) {
+	useTempCustomClassDir(t)
+	require.NoError(t, writeCustomClass(&CustomClass{Name: "sidecar-in", Dir: DirIngress, Minor: "70"}))
+	require.NoError(t, writeCustomClass(&CustomClass{Name: "sidecar-out", Dir: DirEgress, Minor: "71"}))
+
+	require.NoError(t, removeCustomClassesForDir(DirEgress))
+
+	defs, err := loadCustomClasses()
+	require.NoError(t, err)
+	require.Len(t, defs, 1)
+	require.Equal(t, "sidecar-in", defs[0].Name)
+}
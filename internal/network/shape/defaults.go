@@ -95,6 +95,7 @@ func buildDefaultConfig(p defaultProfile, trunkRate string) (*DeviceConfig, []*C
 //
 //   - CreatedAt is carried over for every record that already exists — a
 //     re-provision updates the registry, it does not recreate it.
+//   - The device's qdisc backend (Qdisc/Leaf) is carried over verbatim.
 //   - When the requested trunk rate is the same bandwidth as the one already
 //     recorded on the device, each class keeps its recorded rate/ceil/prio
 //     instead of being reset to the profile proportions. This is what makes
@@ -116,6 +117,9 @@ func mergeExistingConfig(dev *DeviceConfig, classes []*ClassConfig, existingDev
 	if !existingDev.CreatedAt.IsZero() {
 		dev.CreatedAt = existingDev.CreatedAt
 	}
+	// The qdisc backend is an operator choice made with `network shape create
+	// --device --qdisc/--leaf`; a re-provision never reverts it.
+	dev.Qdisc, dev.Leaf = existingDev.Qdisc, existingDev.Leaf
 	keepClassRates := sameBandwidth(existingDev.Rate, dev.Rate)
 	if keepClassRates {
 		// Same bandwidth, possibly spelled differently (1gbit vs 1000mbit):
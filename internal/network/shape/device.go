@@ -10,9 +10,22 @@ import "time"
 // Dir "egress" targets the $EGRESS physical NIC and drives the re-rendered
 // bandwidth-shaper.sh boot script. Dir "ingress" targets $VETH and is consumed by
 // the daemon pod-lifecycle watcher; no script is rendered for it.
+//
+// AutoRate is set on an egress device created with rate "auto": Rate still
+// holds the concrete speed resolved at create time, but ReapplyEgress re-reads
+// the link speed and rescales the trunk and every class proportionally when the
+// link renegotiates (see ReapplyEgress).
+//
+// Qdisc selects the root qdisc backend ("htb", the default when empty, "cake"
+// or "fq") and Leaf the per-class leaf under HTB ("fq_codel", the default when
+// empty, "cake" or "fq"). Neither changes the policy plane's skb->priority
+// stamping; see backend.go for how classes map onto each backend.
 type DeviceConfig struct {
-	Dir          string    `json:"dir"`           // "ingress" or "egress"
-	Rate         string    `json:"rate"`          // root HTB trunk class rate
-	DefaultClass string    `json:"default_class"` // class name; unmatched traffic falls here
+	Dir          string    `json:"dir"`                 // "ingress" or "egress"
+	Rate         string    `json:"rate"`                // root HTB trunk class rate
+	DefaultClass string    `json:"default_class"`       // class name; unmatched traffic falls here
+	AutoRate     bool      `json:"auto_rate,omitempty"` // Rate was resolved from "auto"; re-resolved on link speed change
+	Qdisc        string    `json:"qdisc,omitempty"`     // root qdisc backend: htb (default), cake or fq
+	Leaf         string    `json:"leaf,omitempty"`      // HTB leaf qdisc: fq_codel (default), cake or fq
 	CreatedAt    time.Time `json:"created_at"`
 }
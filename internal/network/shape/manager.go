@@ -100,6 +100,9 @@ func (m *Manager) CreateDevice(ctx context.Context, dev *DeviceConfig, force boo
 	if err := validateDefaultClass(dev.DefaultClass, dev.Dir); err != nil {
 		return false, err
 	}
+	if err := validateQdiscBackend(dev.Qdisc, dev.Leaf); err != nil {
+		return false, err
+	}
 
 	var changed bool
 	err := m.withLock(func() error {
@@ -200,6 +203,9 @@ func (m *Manager) CreateClass(ctx context.Context, cls *ClassConfig, force bool)
 // class. Only non-nil pointer fields are changed; nil means "keep current value".
 // For egress classes: runs `tc class change` on the live kernel and re-renders
 // the boot script for reboot persistence. For ingress classes: updates config only.
+// Under a classless (CAKE or fq) egress root there is no HTB class to change;
+// the boot script is re-rendered instead (and re-run under CAKE, whose tin
+// filters follow the class prio).
 func (m *Manager) SetClass(ctx context.Context, name string, rate, ceil *string, prio *int) error {
 	ci, err := lookupClassInfo(name)
 	if err != nil {
@@ -247,6 +253,21 @@ func (m *Manager) SetClass(ctx context.Context, name string, rate, ceil *string,
 			return err
 		}
 
+		if ci.Dir == DirEgress && dev != nil && dev.backend().Root != QdiscHTB {
+			// A classless root has no HTB class to change live. Under CAKE the
+			// prio picks the class's tin filter, so rebuild; under fq the class
+			// budgets are not enforced, so only the boot script needs syncing.
+			mode := applyPersistOnly
+			if dev.backend().Root == QdiscCake {
+				mode = applyRestart
+			}
+			if err := m.applyEgressScript(ctx, "", mode); err != nil {
+				return errorx.Decorate(err,
+					"class config updated on disk but boot script re-render failed; restart bandwidth-shaper.service to sync")
+			}
+			return nil
+		}
+
 		if ci.Dir == DirEgress {
 			nic, err := m.nicDetect()
 			if err != nil {
@@ -309,15 +330,25 @@ func (m *Manager) ShowDevice(dir string) (string, error) {
 	})
 	var b strings.Builder
 	fmt.Fprintf(&b, "device %s\n", dev.Dir)
-	fmt.Fprintf(&b, "  rate:    %s\n", dev.Rate)
+	if dev.AutoRate {
+		fmt.Fprintf(&b, "  rate:    %s (auto: follows link speed)\n", dev.Rate)
+	} else {
+		fmt.Fprintf(&b, "  rate:    %s\n", dev.Rate)
+	}
 	fmt.Fprintf(&b, "  default: %s\n", dev.DefaultClass)
+	be := dev.backend()
+	fmt.Fprintf(&b, "  qdisc:   %s\n", describeBackend(be))
 	fmt.Fprintf(&b, "  created: %s\n", dev.CreatedAt.Format(time.RFC3339))
 	if len(classes) > 0 {
 		fmt.Fprintf(&b, "  classes:\n")
 		for _, cls := range classes {
 			ci, _ := lookupClassInfo(cls.Name)
-			fmt.Fprintf(&b, "    %-20s rate=%-10s ceil=%-10s prio=%d (1:%s)\n",
-				cls.Name, cls.Rate, cls.effectiveCeil(), cls.Prio, ci.Minor)
+			tin := ""
+			if be.Root == QdiscCake {
+				tin = " tin=" + cakeTinForPrio(cls.Prio).Name
+			}
+			fmt.Fprintf(&b, "    %-20s rate=%-10s ceil=%-10s prio=%d (1:%s)%s\n",
+				cls.Name, cls.Rate, cls.effectiveCeil(), cls.Prio, ci.Minor, tin)
 		}
 	}
 	return b.String(), nil
@@ -349,6 +380,7 @@ func (m *Manager) ShowAll() (string, error) {
 
 // DeleteClass removes a class configuration. Fails if the class is referenced
 // as the device's default class or by any policy's --stamp/--reply-stamp.
+// Deleting a custom class also removes its definition, releasing its minor.
 // For egress classes: re-renders TcEgressScriptPath and restarts bandwidth-shaper.service.
 func (m *Manager) DeleteClass(ctx context.Context, name string) error {
 	ci, err := lookupClassInfo(name)
@@ -360,7 +392,13 @@ func (m *Manager) DeleteClass(ctx context.Context, name string) error {
 		if err != nil {
 			return err
 		}
+		custom := !IsBuiltinClass(name)
 		if cls == nil {
+			if custom {
+				// A definition without a bandwidth config (e.g. an interrupted
+				// register): dropping the definition is all that is left to do.
+				return removeCustomClass(name)
+			}
 			return errorx.IllegalState.New("class %q is not configured", name)
 		}
 
@@ -389,6 +427,11 @@ func (m *Manager) DeleteClass(ctx context.Context, name string) error {
 		if err := removeClass(name); err != nil {
 			return err
 		}
+		if custom {
+			if err := removeCustomClass(name); err != nil {
+				return err
+			}
+		}
 		if ci.Dir == DirEgress {
 			if err := m.applyEgressScript(ctx, "", applyRestart); err != nil {
 				return errorx.Decorate(err,
@@ -441,8 +484,9 @@ func (m *Manager) DeleteDevice(ctx context.Context, dir string) error {
 }
 
 // TeardownEgress removes the entire egress tc shape configuration — every egress
-// class and the egress device root — then re-renders the boot script to its empty
-// default and applies it, dropping the live HTB hierarchy on the physical NIC.
+// class (custom class definitions included) and the egress device root — then
+// re-renders the boot script to its empty default and applies it, dropping the
+// live HTB hierarchy on the physical NIC.
 //
 // Unlike DeleteClass/DeleteDevice (the operator-facing single-object verbs, which
 // deliberately refuse to delete a device's default class or a still-referenced
@@ -462,6 +506,9 @@ func (m *Manager) TeardownEgress(ctx context.Context) error {
 				return err
 			}
 		}
+		if err := removeCustomClassesForDir(DirEgress); err != nil {
+			return err
+		}
 		if err := removeDevice(DirEgress); err != nil {
 			return err
 		}
@@ -480,10 +527,11 @@ func (m *Manager) TeardownEgress(ctx context.Context) error {
 }
 
 // TeardownIngress removes the entire ingress tc shape configuration — every
-// ingress class and the ingress device root — from the shape registry. Unlike
-// TeardownEgress there is no boot script to re-render: the $VETH HTB is
-// ephemeral (Cilium recreates the veth per pod) and was never persisted for
-// boot replay. Idempotent — with no ingress config present it returns nil.
+// ingress class (custom class definitions included) and the ingress device
+// root — from the shape registry. Unlike TeardownEgress there is no boot
+// script to re-render: the $VETH HTB is ephemeral (Cilium recreates the veth
+// per pod) and was never persisted for boot replay. Idempotent — with no
+// ingress config present it returns nil.
 func (m *Manager) TeardownIngress(_ context.Context) error {
 	return m.withLock(func() error {
 		classes, err := loadClassesForDir(DirIngress)
@@ -495,6 +543,9 @@ func (m *Manager) TeardownIngress(_ context.Context) error {
 				return err
 			}
 		}
+		if err := removeCustomClassesForDir(DirIngress); err != nil {
+			return err
+		}
 		return removeDevice(DirIngress)
 	})
 }
@@ -538,6 +589,7 @@ func (m *Manager) resolveAutoRate(dev *DeviceConfig) {
 	if dev.Dir != DirEgress {
 		return
 	}
+	dev.AutoRate = isAutoRate(dev.Rate)
 	dev.Rate = m.resolveAutoRateString(dev.Rate)
 }
 
@@ -675,11 +727,13 @@ func (m *Manager) writeEgressScript(rendered string) error {
 // config only). Shared by ProvisionDefaultEgress and ProvisionDefaultIngress so
 // the two directions cannot drift.
 func (m *Manager) provisionDefaults(prof defaultProfile, trunkRate string, overrides map[string]ClassOverride, afterWrite func() error) error {
+	auto := isAutoRate(trunkRate)
 	trunkRate = m.resolveAutoRateString(trunkRate)
 	dev, classes, err := buildDefaultConfig(prof, trunkRate)
 	if err != nil {
 		return err
 	}
+	dev.AutoRate = auto && prof.dir == DirEgress
 	return m.withLock(func() error {
 		existingDev, existingClasses, err := loadExistingConfig(prof.dir)
 		if err != nil {
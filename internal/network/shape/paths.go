@@ -46,6 +46,12 @@ const (
 	// reads this directory to reinstall $VETH classes on each pod create event.
 	ClassConfigDir = ShapeConfigDir + "/classes"
 
+	// CustomClassConfigDir holds one JSON file per operator-defined class
+	// (see RegisterCustomClass): its direction, allocated classid minor and
+	// default bandwidth. The policy plane reads it to resolve --stamp targets
+	// beyond the built-in classes.
+	CustomClassConfigDir = ShapeConfigDir + "/custom-classes"
+
 	// ShapeLockDir is the directory containing the tc apply lock (on tmpfs so
 	// it is auto-cleared on reboot).
 	ShapeLockDir = "/run/solo-provisioner/network"
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package shape
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+)
+
+// ReapplyResult reports what ReapplyEgress did.
+type ReapplyResult struct {
+	NIC     string // egress interface the hierarchy targets
+	Rate    string // trunk rate after re-resolution ("" for a registry-less legacy install)
+	Applied bool   // the boot script was re-run
+	Reason  string // why it was (or was not) re-run
+}
+
+// ReapplyEgress re-attaches the $EGRESS HTB hierarchy after interface churn. It
+// is the privileged half of the daemon's egress link watcher, which calls it
+// (via `network shape reapply`) whenever the recorded egress interface appears,
+// is recreated, or renegotiates its link speed — the cases the boot-replay unit
+// cannot cover on its own, e.g. a netplan bond, VLAN or bridge that
+// systemd-networkd creates only after bandwidth-shaper.service has already run
+// and failed.
+//
+// nic defaults to the interface recorded in the boot script; an install that
+// never rendered one, or whose shaping was torn down (unshape script), is left
+// alone. When the egress device was created with rate "auto", the link speed
+// is re-read and, if it changed, the trunk and every egress class are rescaled
+// proportionally and persisted. The script is then re-run when the rate
+// changed, when the live root hierarchy is missing, or when force is set; an
+// intact hierarchy at an unchanged rate is left untouched so a carrier flap or a
+// repeated event costs no qdisc teardown.
+func (m *Manager) ReapplyEgress(ctx context.Context, nic string, force bool) (*ReapplyResult, error) {
+	res := &ReapplyResult{NIC: nic}
+	err := m.withLock(func() error {
+		existing, err := os.ReadFile(m.scriptPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				res.Reason = "no bandwidth-shaper script rendered; egress shaping is not configured"
+				return nil
+			}
+			return errorx.ExternalError.Wrap(err, "failed to read %s", m.scriptPath)
+		}
+		if res.NIC == "" {
+			if res.NIC, err = scriptNIC(string(existing)); err != nil {
+				return err
+			}
+		}
+		if unshape, err := renderTcEgressUnshapeScript(res.NIC); err == nil && unshape == string(existing) {
+			res.Reason = "egress shaping is disabled"
+			return nil
+		}
+
+		rateChanged, err := m.reresolveAutoRate(res)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case force:
+			res.Reason = "forced"
+		case rateChanged:
+			res.Reason = "link speed changed"
+		default:
+			present, err := m.hierarchyPresent(ctx, res.NIC)
+			if err != nil {
+				return errorx.Decorate(err, "cannot inspect the live hierarchy on %s", res.NIC)
+			}
+			if present {
+				res.Reason = "hierarchy present at the recorded rate"
+				return nil
+			}
+			res.Reason = "hierarchy missing"
+		}
+
+		if err := m.applyEgressScript(ctx, res.NIC, applyRestart); err != nil {
+			return err
+		}
+		res.Applied = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// hierarchyPresent reports whether the recorded egress backend is installed
+// on nic: the HTB trunk class 1:1, or a root qdisc of the recorded classless
+// kind (CAKE or fq).
+func (m *Manager) hierarchyPresent(ctx context.Context, nic string) (bool, error) {
+	be := (&DeviceConfig{}).backend()
+	dev, err := readDevice(DirEgress)
+	if err != nil {
+		return false, err
+	}
+	if dev != nil {
+		be = dev.backend()
+	}
+	if be.Root == QdiscHTB {
+		stats, err := m.tcRunner.ClassStats(ctx, nic)
+		if err != nil {
+			return false, err
+		}
+		_, ok := stats["1:1"]
+		return ok, nil
+	}
+	qdiscs, err := m.tcRunner.QdiscStats(ctx, nic)
+	if err != nil {
+		return false, err
+	}
+	for _, q := range qdiscs {
+		if q.Root && q.Kind == be.Root {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reresolveAutoRate re-reads the link speed of res.NIC for an egress device
+// recorded with AutoRate and, when it no longer matches the recorded trunk
+// rate, rescales and persists the device and its classes. It reports whether
+// the rate changed. An unreadable speed (link down, virtual NIC) keeps the
+// recorded rate rather than falling back to DefaultLinkSpeedMbit: a link that
+// is merely down has not renegotiated.
+func (m *Manager) reresolveAutoRate(res *ReapplyResult) (bool, error) {
+	dev, err := readDevice(DirEgress)
+	if err != nil || dev == nil {
+		return false, err
+	}
+	res.Rate = dev.Rate
+	if !dev.AutoRate {
+		return false, nil
+	}
+	mbit, ok := m.speedDetect(res.NIC)
+	if !ok {
+		return false, nil
+	}
+	newRate := FormatSpeedHint(mbit)
+	if sameBandwidth(dev.Rate, newRate) {
+		return false, nil
+	}
+
+	classes, err := loadClassesForDir(DirEgress)
+	if err != nil {
+		return false, err
+	}
+	if err := rescaleClasses(classes, dev.Rate, newRate); err != nil {
+		return false, err
+	}
+	logx.As().Info().Str("nic", res.NIC).Str("from", dev.Rate).Str("to", newRate).Msg(
+		"egress link speed changed; rescaling auto-rate shaping")
+	dev.Rate = newRate
+	if err := writeDevice(dev); err != nil {
+		return false, err
+	}
+	for _, cls := range classes {
+		if err := writeClass(cls); err != nil {
+			return false, err
+		}
+	}
+	res.Rate = newRate
+	return true, nil
+}
+
+// rescaleClasses rescales every class's rate and ceil, in place, by
+// newTrunk/oldTrunk, so the hierarchy keeps the proportions the operator tuned
+// against the old link speed. The arithmetic is done in kbit and rounds down, so
+// the class rates can never sum past the new trunk. A class whose rate does not
+// parse (a legacy shell expression) is left untouched.
+func rescaleClasses(classes []*ClassConfig, oldTrunk, newTrunk string) error {
+	oldBps, err := parseBandwidthBps(oldTrunk)
+	if err != nil {
+		return errorx.IllegalState.Wrap(err, "cannot rescale classes from trunk rate %q", oldTrunk)
+	}
+	newBps, err := parseBandwidthBps(newTrunk)
+	if err != nil {
+		return errorx.IllegalArgument.Wrap(err, "cannot rescale classes to trunk rate %q", newTrunk)
+	}
+	oldKbit, newKbit := oldBps/1000, newBps/1000
+	scale := func(rate string) string {
+		bps, err := parseBandwidthBps(rate)
+		if err != nil {
+			return rate
+		}
+		if bps == oldBps {
+			// A ceil pinned to the trunk follows the trunk verbatim.
+			return newTrunk
+		}
+		return formatKbit(bps / 1000 * newKbit / oldKbit)
+	}
+	for _, c := range classes {
+		c.Rate = scale(c.Rate)
+		if c.Ceil != "" {
+			c.Ceil = scale(c.Ceil)
+		}
+	}
+	return nil
+}
+
+// formatKbit renders a kbit value in the largest tc unit that represents it
+// exactly (e.g. 400000 → "400mbit", 1500 → "1500kbit").
+func formatKbit(kbit int64) string {
+	switch {
+	case kbit <= 0:
+		return "1kbit"
+	case kbit%1_000_000 == 0:
+		return fmt.Sprintf("%dgbit", kbit/1_000_000)
+	case kbit%1_000 == 0:
+		return fmt.Sprintf("%dmbit", kbit/1_000)
+	default:
+		return fmt.Sprintf("%dkbit", kbit)
+	}
+}
+
+// scriptNIC extracts the NIC="<name>" assignment from a rendered
+// bandwidth-shaper script.
+func scriptNIC(script string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, `NIC="`) || !strings.HasSuffix(line, `"`) {
+			continue
+		}
+		nic := strings.TrimSuffix(strings.TrimPrefix(line, `NIC="`), `"`)
+		if !nicNameRe.MatchString(nic) {
+			return "", errorx.IllegalState.New("bandwidth-shaper script records an invalid interface name %q", nic)
+		}
+		return nic, nil
+	}
+	return "", errorx.IllegalState.New("bandwidth-shaper script records no egress interface (NIC= line missing)")
+}
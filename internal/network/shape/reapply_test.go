@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package shape
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newReapplyManager returns a manager whose boot script holds script (nothing
+// is written when script is empty) and whose ClassStats reports stats. applied
+// counts bandwidth-shaper restarts.
+func newReapplyManager(t *testing.T, script string, stats map[string]ClassStat, applied *int) *Manager {
+	t.Helper()
+	dir := t.TempDir()
+	scriptPath := filepath.Join(dir, "bandwidth-shaper.sh")
+	if script != "" {
+		require.NoError(t, os.WriteFile(scriptPath, []byte(script), 0o755))
+	}
+	return NewManagerWithConfig(Config{
+		ScriptPath:  scriptPath,
+		LockPath:    filepath.Join(dir, ".tc-applying"),
+		NICDetect:   func() (string, error) { return "", os.ErrNotExist },
+		ApplyEgress: func(context.Context) error { *applied++; return nil },
+		TCRunner:    &scriptedStatsRunner{samples: []map[string]ClassStat{stats}},
+	})
+}
+
+func TestReapplyEgress_ReattachesMissingHierarchyOnRecordedNIC(t *testing.T) {
+	script, err := renderTcEgressScript("bond0")
+	require.NoError(t, err)
+	var applied int
+	m := newReapplyManager(t, script, map[string]ClassStat{}, &applied)
+
+	res, err := m.ReapplyEgress(context.Background(), "", false)
+	require.NoError(t, err)
+	require.Equal(t, "bond0", res.NIC, "the NIC comes from the rendered script, not route detection")
+	require.True(t, res.Applied)
+	require.Equal(t, "hierarchy missing", res.Reason)
+	require.Equal(t, 1, applied)
+}
+
+func TestReapplyEgress_IntactHierarchyIsLeftAlone(t *testing.T) {
+	script, err := renderTcEgressScript("bond0")
+	require.NoError(t, err)
+	var applied int
+	m := newReapplyManager(t, script, map[string]ClassStat{"1:1": {ClassID: "1:1"}}, &applied)
+
+	res, err := m.ReapplyEgress(context.Background(), "", false)
+	require.NoError(t, err)
+	require.False(t, res.Applied)
+	require.Zero(t, applied)
+
+	res, err = m.ReapplyEgress(context.Background(), "", true)
+	require.NoError(t, err)
+	require.True(t, res.Applied, "--force re-runs the script regardless")
+	require.Equal(t, 1, applied)
+}
+
+func TestReapplyEgress_SkipsWhenShapingIsNotConfigured(t *testing.T) {
+	var applied int
+	m := newReapplyManager(t, "", nil, &applied)
+	res, err := m.ReapplyEgress(context.Background(), "", true)
+	require.NoError(t, err)
+	require.False(t, res.Applied)
+	require.Zero(t, applied)
+
+	unshape, err := renderTcEgressUnshapeScript("bond0")
+	require.NoError(t, err)
+	m = newReapplyManager(t, unshape, map[string]ClassStat{}, &applied)
+	res, err = m.ReapplyEgress(context.Background(), "", true)
+	require.NoError(t, err)
+	require.False(t, res.Applied, "a torn-down install must not be re-shaped")
+	require.Equal(t, "egress shaping is disabled", res.Reason)
+	require.Zero(t, applied)
+}
+
+func TestRescaleClasses_KeepsProportions(t *testing.T) {
+	classes := []*ClassConfig{
+		{Name: "partner", Rate: "400mbit", Ceil: "700mbit"},
+		{Name: "public", Rate: "300mbit", Ceil: "700mbit"},
+		{Name: "reserve-egress", Rate: "300mbit", Ceil: "1gbit"},
+		{Name: "legacy", Rate: "$(( SPEED * 40 / 100 ))mbit"},
+	}
+	require.NoError(t, rescaleClasses(classes, "1gbit", "10gbit"))
+	require.Equal(t, "4gbit", classes[0].Rate)
+	require.Equal(t, "7gbit", classes[0].Ceil)
+	require.Equal(t, "3gbit", classes[1].Rate)
+	require.Equal(t, "10gbit", classes[2].Ceil, "a ceil pinned to the trunk follows the new trunk")
+	require.Equal(t, "$(( SPEED * 40 / 100 ))mbit", classes[3].Rate, "unparseable rates are left alone")
+
+	classes = []*ClassConfig{{Name: "partner", Rate: "333mbit"}}
+	require.NoError(t, rescaleClasses(classes, "1gbit", "100mbit"))
+	require.Equal(t, "33300kbit", classes[0].Rate)
+}
+
+func TestScriptNIC(t *testing.T) {
+	script, err := renderTcEgressScript("enp0s1.100")
+	require.NoError(t, err)
+	nic, err := scriptNIC(script)
+	require.NoError(t, err)
+	require.Equal(t, "enp0s1.100", nic)
+
+	_, err = scriptNIC("#!/bin/sh\nset -e\n")
+	require.ErrorContains(t, err, "NIC= line missing")
+	_, err = scriptNIC("NIC=\"eth0; reboot\"\n")
+	require.ErrorContains(t, err, "invalid interface name")
+}
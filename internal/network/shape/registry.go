@@ -70,8 +70,8 @@ func readClass(name string) (*ClassConfig, error) {
 }
 
 // loadClassesForDir loads all class configs for the given direction, sorted by
-// name. Classes whose name is not in classInfoMap (e.g. hand-edited files) are
-// silently skipped.
+// name. Classes that are neither built-in nor registered custom classes (e.g.
+// hand-edited files) are silently skipped.
 func loadClassesForDir(dir string) ([]*ClassConfig, error) {
 	entries, err := os.ReadDir(ClassConfigDir)
 	if err != nil {
@@ -80,13 +80,14 @@ func loadClassesForDir(dir string) ([]*ClassConfig, error) {
 		}
 		return nil, errorx.ExternalError.Wrap(err, "failed to read class config dir %s", ClassConfigDir)
 	}
+	known := allClassInfo()
 	var names []string
 	for _, e := range entries {
 		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
 			continue
 		}
 		name := strings.TrimSuffix(e.Name(), ".json")
-		info, ok := classInfoMap[name]
+		info, ok := known[name]
 		if !ok || info.Dir != dir {
 			continue
 		}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/hashgraph/solo-weaver/internal/templates"
 	"github.com/joomcode/errorx"
@@ -32,15 +33,22 @@ type scriptData struct {
 type deviceRenderData struct {
 	DefaultMinor string // hex tc classid minor for the default class, e.g. "60"
 	Rate         string // root trunk class rate (may be a shell expr for legacy renders)
+	Qdisc        string // root qdisc backend: "htb", "cake" or "fq"
+	Leaf         string // HTB leaf qdisc kind and options, e.g. "fq_codel"
+	DefaultTin   int    // cake: tin unstamped traffic falls to
+	DefaultPref  string // cake: preference of the catch-all tin filter
 }
 
 // classRenderData is the per-class template context.
 type classRenderData struct {
-	Minor  string // hex tc classid minor, e.g. "40"
-	Handle string // fq_codel qdisc handle, e.g. "140"
-	Rate   string // htb rate (may be a shell expr for legacy renders)
-	Ceil   string // htb ceil (may be a shell expr for legacy renders)
-	Prio   int
+	Minor    string // hex tc classid minor, e.g. "40"
+	Handle   string // leaf qdisc handle, e.g. "140"
+	Rate     string // htb rate (may be a shell expr for legacy renders)
+	Ceil     string // htb ceil (may be a shell expr for legacy renders)
+	Prio     int
+	Pref     string // cake: tin filter preference
+	Priority string // cake: stamped skb->priority the tin filter matches
+	Tin      int    // cake: tin the class maps onto
 }
 
 // renderTcEgressScript renders the bandwidth-shaper.sh template with default
@@ -67,26 +75,38 @@ func renderTcEgressScriptFromConfig(nicName string, dev *DeviceConfig, classes [
 	if err != nil {
 		return "", err
 	}
+	filters, err := cakeTinFilters(classes)
+	if err != nil {
+		return "", err
+	}
 	crs := make([]classRenderData, 0, len(classes))
-	for _, cls := range classes {
+	for i, cls := range classes {
 		ci, err := lookupClassInfo(cls.Name)
 		if err != nil {
 			return "", err
 		}
 		crs = append(crs, classRenderData{
-			Minor:  ci.Minor,
-			Handle: ci.Handle,
-			Rate:   cls.Rate,
-			Ceil:   cls.effectiveCeil(),
-			Prio:   cls.Prio,
+			Minor:    ci.Minor,
+			Handle:   ci.Handle,
+			Rate:     cls.Rate,
+			Ceil:     cls.effectiveCeil(),
+			Prio:     cls.Prio,
+			Pref:     filters[i].Pref,
+			Priority: filters[i].Priority,
+			Tin:      filters[i].Tin,
 		})
 	}
+	be := dev.backend()
 	return renderTcEgressScriptFromData(scriptData{
 		NIC:       nicName,
 		SpeedMbit: -1, // all rates are explicit; SPEED variable not needed
 		Device: deviceRenderData{
 			DefaultMinor: info.Minor,
 			Rate:         dev.Rate,
+			Qdisc:        be.Root,
+			Leaf:         strings.Join(leafQdiscSpec(be.Leaf), " "),
+			DefaultTin:   defaultClassTin(info.Minor, classes).Index,
+			DefaultPref:  cakeDefaultFilterPref,
 		},
 		Classes: crs,
 	})
@@ -102,6 +122,8 @@ func defaultScriptData(nicName string) scriptData {
 		Device: deviceRenderData{
 			DefaultMinor: "60",
 			Rate:         "${SPEED}mbit",
+			Qdisc:        QdiscHTB,
+			Leaf:         LeafFqCodel,
 		},
 		Classes: []classRenderData{
 			{Minor: "40", Handle: "140", Rate: `$(( SPEED * 40 / 100 ))mbit`, Ceil: `$(( SPEED * 70 / 100 ))mbit`, Prio: 0},
@@ -8,6 +8,7 @@ import (
 	"io"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joomcode/errorx"
@@ -17,12 +18,52 @@ import (
 // counters, as read from `tc -s class show dev <device>`. The byte/packet/drop/
 // overlimit counters are monotonic since the class (qdisc) was installed, so a
 // throughput reading is the delta between two snapshots over the elapsed time.
+// Backlog is the exception: a gauge of the bytes currently queued in the class.
 type ClassStat struct {
 	ClassID    string // tc handle, e.g. "1:40"
 	Bytes      uint64
 	Packets    uint64
 	Drops      uint64
 	Overlimits uint64
+	Backlog    uint64
+	Marks      uint64 // ECN marks; only CAKE tins report them
+}
+
+// QdiscStat is a point-in-time snapshot of one qdisc's cumulative counters, as
+// read from `tc -s qdisc show dev <device>`. It is how the classless CAKE and fq
+// backends are observed: they have no HTB classes to read per-class counters
+// from. Tins holds CAKE's per-tin counters in priority-override order (bulk,
+// best-effort, video, voice); it is empty for every other kind.
+type QdiscStat struct {
+	Kind       string // e.g. "htb", "cake", "fq", "fq_codel"
+	Handle     string // e.g. "1:"
+	Root       bool   // attached at the device root
+	Bytes      uint64
+	Packets    uint64
+	Drops      uint64
+	Overlimits uint64
+	Backlog    uint64
+	Tins       []TinStat
+}
+
+// TinStat is one CAKE tin's counters.
+type TinStat struct {
+	Bytes   uint64
+	Packets uint64
+	Drops   uint64
+	Marks   uint64 // ECN CE marks
+	Backlog uint64
+}
+
+// ClassSample is one class's live counters on one interface, labeled with the
+// class name and direction. It is what the metrics exporter publishes per
+// scrape: the cumulative counters as-is, leaving rate computation to the
+// scraper (unlike ClassDelta, which `network shape watch` computes itself).
+type ClassSample struct {
+	Class string // class name, e.g. "partner"
+	Dir   string // "egress" or "ingress"
+	Iface string // interface the counters were read from
+	ClassStat
 }
 
 // ClassDelta is the change in one class's counters between two samples, plus the
@@ -32,11 +73,20 @@ type ClassStat struct {
 // the Prometheus counters).
 type ClassDelta struct {
 	Name            string  // class name ("partner") when the classid is known, else the raw classid
-	ClassID         string  // tc handle, e.g. "1:40"
+	ClassID         string  // tc handle, e.g. "1:40"; the tin name under CAKE
 	RateBitsPerSec  float64 // byte delta * 8 / interval seconds
 	BytesDelta      uint64
 	OverlimitsDelta uint64
 	DropsDelta      uint64
+	MarksDelta      uint64
+}
+
+// watchRow is one line of `network shape watch` output: the label printed in
+// the CLASS column and the key its counters are sampled under — an HTB classid,
+// a CAKE tin name, or the fq root handle.
+type watchRow struct {
+	Name string
+	Key  string
 }
 
 // WatchSpec parameterises Manager.WatchClasses. Both Device (the traffic
@@ -73,14 +123,28 @@ func (m *Manager) WatchClasses(ctx context.Context, spec WatchSpec, w io.Writer)
 	if err != nil {
 		return err
 	}
+	be, configs, err := recordedBackend(dir)
+	if err != nil {
+		return err
+	}
+	return m.watch(ctx, spec, dir, be, watchRows(be, classes, configs), w)
+}
+
+// watch is the sampling loop of WatchClasses, split out so it runs against an
+// explicit backend and row set rather than the on-disk registry.
+func (m *Manager) watch(ctx context.Context, spec WatchSpec, dir string, be qdiscBackend, rows []watchRow, w io.Writer) error {
 	iface := spec.Iface
 
-	fmt.Fprintf(w, "watching tc classes on %s (%s device), interval %s — Ctrl-C to stop\n",
-		iface, dir, spec.Interval)
-	writeWatchHeader(w)
+	label := dir + " device"
+	if be.Root != QdiscHTB {
+		label += ", " + be.Root + " root"
+	}
+	fmt.Fprintf(w, "watching tc classes on %s (%s), interval %s — Ctrl-C to stop\n",
+		iface, label, spec.Interval)
+	writeWatchHeader(w, be)
 
 	// Establish the baseline sample; deltas are printed from the second sample on.
-	prev, prevAt, stopped, err := m.sampleStats(ctx, iface)
+	prev, prevAt, stopped, err := m.sampleStats(ctx, iface, be)
 	if stopped {
 		return nil
 	}
@@ -99,7 +163,7 @@ func (m *Manager) WatchClasses(ctx context.Context, spec WatchSpec, w io.Writer)
 		case <-ticker.C:
 		}
 
-		cur, curAt, stopped, err := m.sampleStats(ctx, iface)
+		cur, curAt, stopped, err := m.sampleStats(ctx, iface, be)
 		if stopped {
 			return nil
 		}
@@ -109,7 +173,7 @@ func (m *Manager) WatchClasses(ctx context.Context, spec WatchSpec, w io.Writer)
 		// Rate over the ACTUAL elapsed time between the two reads (the interval
 		// plus the time spent running tc), not the nominal interval — dividing by
 		// spec.Interval over-reports throughput, most visibly on the first tick.
-		writeWatchRows(w, classDeltas(prev, cur, classes, curAt.Sub(prevAt)))
+		writeWatchRows(w, be, rowDeltas(prev, cur, rows, curAt.Sub(prevAt)))
 		prev, prevAt = cur, curAt
 
 		printed++
@@ -119,13 +183,114 @@ func (m *Manager) WatchClasses(ctx context.Context, spec WatchSpec, w io.Writer)
 	}
 }
 
-// sampleStats reads the live class counters and stamps the read time. A failure
-// caused by context cancellation (the operator hit Ctrl-C mid-sample) is reported
-// as a clean stop (stopped=true, err=nil), so WatchClasses honors its "returns nil
-// on cancellation" contract even when the cancellation lands during a tc exec
-// rather than while waiting on the ticker.
-func (m *Manager) sampleStats(ctx context.Context, iface string) (stats map[string]ClassStat, at time.Time, stopped bool, err error) {
-	stats, err = m.tcRunner.ClassStats(ctx, iface)
+// SampleClasses reads the live counters of every known class in dir (built-in
+// and custom) from iface, one sample per watch row of dir's recorded backend:
+// per class in classid order under HTB, per tin under CAKE (labelled with the
+// classes sharing it) and the root qdisc under fq. Classes of the other
+// direction and the 1:1 trunk are skipped, so a caller can sample any
+// interface without knowing which hierarchy it carries; an interface with no
+// hierarchy installed yields no samples. A direction with no recorded device
+// also yields none — there is nothing shaped to report. It is read-only and
+// takes no lock.
+func (m *Manager) SampleClasses(ctx context.Context, dir, iface string) ([]ClassSample, error) {
+	if err := validateDir(dir); err != nil {
+		return nil, err
+	}
+	dev, err := readDevice(dir)
+	if err != nil {
+		return nil, err
+	}
+	if dev == nil {
+		return nil, nil
+	}
+	be, configs, err := recordedBackend(dir)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := m.backendStats(ctx, iface, be)
+	if err != nil {
+		return nil, err
+	}
+	return classSamples(stats, watchRows(be, knownClassNamesForDir(dir), configs), dir, iface), nil
+}
+
+// classSamples labels the sampled stats with the watch row names, in row
+// order, dropping rows not installed on iface.
+func classSamples(stats map[string]ClassStat, rows []watchRow, dir, iface string) []ClassSample {
+	var out []ClassSample
+	for _, r := range rows {
+		s, ok := stats[r.Key]
+		if !ok {
+			continue
+		}
+		out = append(out, ClassSample{Class: r.Name, Dir: dir, Iface: iface, ClassStat: s})
+	}
+	return out
+}
+
+// ShowDeviceStats renders the cumulative live counters of dir's recorded
+// backend on iface for `network shape show --iface`: one line per watch row
+// (per class under HTB, per tin under CAKE, the root qdisc under fq). Rows not
+// installed on iface are omitted. It is read-only and takes no lock.
+func (m *Manager) ShowDeviceStats(ctx context.Context, dir, iface string) (string, error) {
+	if err := validateDir(dir); err != nil {
+		return "", err
+	}
+	be, configs, err := recordedBackend(dir)
+	if err != nil {
+		return "", err
+	}
+	stats, err := m.backendStats(ctx, iface, be)
+	if err != nil {
+		return "", err
+	}
+	return formatDeviceStats(iface, be, watchRows(be, knownClassNamesForDir(dir), configs), stats), nil
+}
+
+// formatDeviceStats renders ShowDeviceStats output from already-sampled
+// counters.
+func formatDeviceStats(iface string, be qdiscBackend, rows []watchRow, stats map[string]ClassStat) string {
+	var b strings.Builder
+	id, col := "CLASSID", "OVERLIMITS"
+	if be.Root == QdiscCake {
+		id, col = "TIN", "MARKED"
+	}
+	fmt.Fprintf(&b, "  live counters on %s (%s root):\n", iface, be.Root)
+	fmt.Fprintf(&b, "    %-18s %-11s %12s %12s %11s %8s %10s\n",
+		"CLASS", id, "SENT", "PACKETS", col, "DROPPED", "BACKLOG")
+	shown := 0
+	for _, r := range rows {
+		c, ok := stats[r.Key]
+		if !ok {
+			continue
+		}
+		fourth := c.Overlimits
+		if be.Root == QdiscCake {
+			fourth = c.Marks
+		}
+		fmt.Fprintf(&b, "    %-18s %-11s %12s %12d %11d %8d %10s\n",
+			r.Name, r.Key, humanBytes(c.Bytes), c.Packets, fourth, c.Drops, humanBytes(c.Backlog))
+		shown++
+	}
+	if shown == 0 {
+		fmt.Fprintf(&b, "    (no %s hierarchy installed on %s)\n", be.Root, iface)
+	}
+	return b.String()
+}
+
+// EgressInterface returns the interface the egress hierarchy is installed on,
+// using the same detection as the egress apply path.
+func (m *Manager) EgressInterface() (string, error) {
+	return m.nicDetect()
+}
+
+// sampleStats reads the live counters for the backend and stamps the read time.
+// A failure caused by context cancellation (the operator hit Ctrl-C mid-sample)
+// is reported as a clean stop (stopped=true, err=nil), so WatchClasses honors
+// its "returns nil on cancellation" contract even when the cancellation lands
+// during a tc exec rather than while waiting on the ticker.
+func (m *Manager) sampleStats(ctx context.Context, iface string, be qdiscBackend) (stats map[string]ClassStat, at time.Time, stopped bool, err error) {
+	stats, err = m.backendStats(ctx, iface, be)
 	if err != nil {
 		if ctx.Err() != nil {
 			return nil, time.Time{}, true, nil
@@ -162,43 +327,157 @@ func resolveWatchClasses(spec WatchSpec) (dir string, classes []string, err erro
 	return spec.Device, knownClassNamesForDir(spec.Device), nil
 }
 
-// classDeltas computes the per-class delta between two samples for the named
-// classes, in classid order. A class absent from the current sample (not
+// classDeltas computes the per-class delta between two HTB samples for the
+// named classes, in classid order. A class absent from the current sample (not
 // installed on the live device) is skipped; a counter that went backwards (the
 // qdisc was reinstalled between samples) yields a zero delta for that tick rather
 // than a spurious huge number.
 func classDeltas(prev, cur map[string]ClassStat, classes []string, elapsed time.Duration) []ClassDelta {
+	out := rowDeltas(prev, cur, htbWatchRows(classes), elapsed)
+	sort.Slice(out, func(i, j int) bool { return out[i].ClassID < out[j].ClassID })
+	return out
+}
+
+// rowDeltas computes the delta between two samples for each row, in row order,
+// with the same skip and counter-reset rules as classDeltas.
+func rowDeltas(prev, cur map[string]ClassStat, rows []watchRow, elapsed time.Duration) []ClassDelta {
 	secs := elapsed.Seconds()
-	out := make([]ClassDelta, 0, len(classes))
-	for _, name := range classes {
-		ci, err := lookupClassInfo(name)
-		if err != nil {
-			continue
-		}
-		classid := "1:" + ci.Minor
-		c, ok := cur[classid]
+	out := make([]ClassDelta, 0, len(rows))
+	for _, r := range rows {
+		c, ok := cur[r.Key]
 		if !ok {
 			continue
 		}
-		p := prev[classid] // zero value when the class is new this tick
+		p := prev[r.Key] // zero value when the row is new this tick
 		bytesDelta := monotonicDelta(p.Bytes, c.Bytes)
 		var rate float64
 		if secs > 0 {
 			rate = float64(bytesDelta) * 8 / secs
 		}
 		out = append(out, ClassDelta{
-			Name:            name,
-			ClassID:         classid,
+			Name:            r.Name,
+			ClassID:         r.Key,
 			RateBitsPerSec:  rate,
 			BytesDelta:      bytesDelta,
 			OverlimitsDelta: monotonicDelta(p.Overlimits, c.Overlimits),
 			DropsDelta:      monotonicDelta(p.Drops, c.Drops),
+			MarksDelta:      monotonicDelta(p.Marks, c.Marks),
 		})
 	}
-	sort.Slice(out, func(i, j int) bool { return out[i].ClassID < out[j].ClassID })
 	return out
 }
 
+// htbWatchRows maps class names onto their HTB classids. Unknown names are
+// dropped.
+func htbWatchRows(classes []string) []watchRow {
+	rows := make([]watchRow, 0, len(classes))
+	for _, name := range classes {
+		ci, err := lookupClassInfo(name)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, watchRow{Name: name, Key: "1:" + ci.Minor})
+	}
+	return rows
+}
+
+// watchRows returns the rows `network shape watch` prints for the backend:
+//
+//   - htb: one row per class, read from its HTB class, in classid order.
+//   - cake: one row per tin the classes map onto (highest priority first),
+//     labelled with every class sharing that tin; CAKE counts per tin, not per
+//     class. configs supplies each class's recorded prio; a class without one
+//     is skipped.
+//   - fq: a single row for the root qdisc; fq has no per-class counters.
+func watchRows(be qdiscBackend, classes []string, configs []*ClassConfig) []watchRow {
+	switch be.Root {
+	case QdiscCake:
+		prio := make(map[string]int, len(configs))
+		for _, c := range configs {
+			prio[c.Name] = c.Prio
+		}
+		byTin := make(map[string][]string)
+		for _, name := range classes {
+			p, ok := prio[name]
+			if !ok {
+				continue
+			}
+			tin := cakeTinForPrio(p).Name
+			byTin[tin] = append(byTin[tin], name)
+		}
+		var rows []watchRow
+		for i := len(cakeTins) - 1; i >= 0; i-- {
+			names := byTin[cakeTins[i].Name]
+			if len(names) == 0 {
+				continue
+			}
+			sort.Strings(names)
+			rows = append(rows, watchRow{Name: strings.Join(names, ","), Key: cakeTins[i].Name})
+		}
+		return rows
+	case QdiscFq:
+		return []watchRow{{Name: "(all classes)", Key: "1:"}}
+	default:
+		rows := htbWatchRows(classes)
+		sort.Slice(rows, func(i, j int) bool { return rows[i].Key < rows[j].Key })
+		return rows
+	}
+}
+
+// recordedBackend returns the recorded backend for dir and the recorded class
+// configs of that direction. A direction with no recorded device reports the
+// default HTB backend, so watching an unconfigured direction behaves as before
+// the backend became selectable.
+func recordedBackend(dir string) (qdiscBackend, []*ClassConfig, error) {
+	dev, err := readDevice(dir)
+	if err != nil {
+		return qdiscBackend{}, nil, err
+	}
+	if dev == nil {
+		return (&DeviceConfig{}).backend(), nil, nil
+	}
+	classes, err := loadClassesForDir(dir)
+	if err != nil {
+		return qdiscBackend{}, nil, err
+	}
+	return dev.backend(), classes, nil
+}
+
+// backendStats reads the live counters for the backend, keyed the way its
+// watch rows are: HTB classids from `tc class show`; CAKE tin names and the fq
+// root handle "1:" from `tc qdisc show`. A CAKE or fq root that is not installed
+// (e.g. the boot script has not run yet) yields no counters rather than an
+// error, like an interface with no HTB hierarchy.
+func (m *Manager) backendStats(ctx context.Context, iface string, be qdiscBackend) (map[string]ClassStat, error) {
+	if be.Root == QdiscHTB {
+		return m.tcRunner.ClassStats(ctx, iface)
+	}
+	qdiscs, err := m.tcRunner.QdiscStats(ctx, iface)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]ClassStat)
+	for _, q := range qdiscs {
+		if !q.Root || q.Kind != be.Root {
+			continue
+		}
+		if be.Root == QdiscFq {
+			stats["1:"] = ClassStat{ClassID: "1:", Bytes: q.Bytes, Packets: q.Packets,
+				Drops: q.Drops, Overlimits: q.Overlimits, Backlog: q.Backlog}
+			continue
+		}
+		for i, t := range q.Tins {
+			if i >= len(cakeTins) {
+				break
+			}
+			name := cakeTins[i].Name
+			stats[name] = ClassStat{ClassID: name, Bytes: t.Bytes, Packets: t.Packets,
+				Drops: t.Drops, Marks: t.Marks, Backlog: t.Backlog}
+		}
+	}
+	return stats, nil
+}
+
 // monotonicDelta returns cur-prev for a monotonic counter, clamping to 0 when the
 // counter appears to have reset (cur < prev) so a qdisc reinstall between samples
 // does not report a bogus multi-exabyte delta.
@@ -209,16 +488,27 @@ func monotonicDelta(prev, cur uint64) uint64 {
 	return cur - prev
 }
 
-func writeWatchHeader(w io.Writer) {
-	fmt.Fprintf(w, "%-18s %-8s %15s %12s %11s %8s\n",
-		"CLASS", "CLASSID", "RATE", "SENT", "OVERLIMITS", "DROPPED")
+// writeWatchHeader prints the column header. CAKE has no overlimits counter;
+// its fourth column is the ECN marks it applies instead of dropping, and its
+// CLASSID column names the tin.
+func writeWatchHeader(w io.Writer, be qdiscBackend) {
+	id, col := "CLASSID", "OVERLIMITS"
+	if be.Root == QdiscCake {
+		id, col = "TIN", "MARKED"
+	}
+	fmt.Fprintf(w, "%-18s %-11s %15s %12s %11s %8s\n",
+		"CLASS", id, "RATE", "SENT", col, "DROPPED")
 }
 
-func writeWatchRows(w io.Writer, deltas []ClassDelta) {
+func writeWatchRows(w io.Writer, be qdiscBackend, deltas []ClassDelta) {
 	for _, d := range deltas {
-		fmt.Fprintf(w, "%-18s %-8s %15s %12s %11s %8s\n",
+		fourth := d.OverlimitsDelta
+		if be.Root == QdiscCake {
+			fourth = d.MarksDelta
+		}
+		fmt.Fprintf(w, "%-18s %-11s %15s %12s %11s %8s\n",
 			d.Name, d.ClassID, humanRate(d.RateBitsPerSec), humanBytes(d.BytesDelta),
-			"+"+strconv.FormatUint(d.OverlimitsDelta, 10),
+			"+"+strconv.FormatUint(fourth, 10),
 			"+"+strconv.FormatUint(d.DropsDelta, 10))
 	}
 	fmt.Fprintln(w) // blank line separates ticks
@@ -56,6 +56,48 @@ func TestClassDeltas_FirstSampleFromZeroBaseline(t *testing.T) {
 	require.Equal(t, uint64(2_000), got[0].BytesDelta)
 }
 
+func TestClassSamples_LabelsOnlyTheRequestedDirection(t *testing.T) {
+	useTempCustomClassDir(t)
+	require.NoError(t, writeCustomClass(&CustomClass{Name: "mirror", Dir: DirEgress, Minor: "70", Rate: "10mbit"}))
+
+	stats := map[string]ClassStat{
+		"1:1":  {ClassID: "1:1", Bytes: 9_999},                                          // trunk: never a class
+		"1:10": {ClassID: "1:10", Bytes: 1},                                             // ingress class on an egress NIC: skipped
+		"1:40": {ClassID: "1:40", Bytes: 3_000, Packets: 2, Overlimits: 1, Backlog: 64}, // partner
+		"1:70": {ClassID: "1:70", Bytes: 700, Drops: 5},                                 // custom egress class
+	}
+	htb := qdiscBackend{Root: QdiscHTB}
+	got := classSamples(stats, watchRows(htb, knownClassNamesForDir(DirEgress), nil), DirEgress, "enp0s1")
+	require.Equal(t, []ClassSample{
+		{Class: "partner", Dir: DirEgress, Iface: "enp0s1", ClassStat: stats["1:40"]},
+		{Class: "mirror", Dir: DirEgress, Iface: "enp0s1", ClassStat: stats["1:70"]},
+	}, got)
+
+	require.Empty(t, classSamples(map[string]ClassStat{}, watchRows(htb, knownClassNamesForDir(DirIngress), nil), DirIngress, "lxc1a2b3c"),
+		"an interface with no HTB hierarchy yields no samples")
+}
+
+func TestClassSamples_CakeAndFqRoots(t *testing.T) {
+	cake := qdiscBackend{Root: QdiscCake}
+	configs := []*ClassConfig{{Name: "partner", Prio: 0}, {Name: "public", Prio: 4}}
+	stats := map[string]ClassStat{
+		"voice":       {ClassID: "voice", Bytes: 3_000, Marks: 2},
+		"best-effort": {ClassID: "best-effort", Bytes: 900},
+	}
+	got := classSamples(stats, watchRows(cake, []string{"partner", "public"}, configs), DirEgress, "enp0s1")
+	require.Equal(t, []ClassSample{
+		{Class: "partner", Dir: DirEgress, Iface: "enp0s1", ClassStat: stats["voice"]},
+		{Class: "public", Dir: DirEgress, Iface: "enp0s1", ClassStat: stats["best-effort"]},
+	}, got, "CAKE is sampled per tin, labelled with the classes it carries")
+
+	fq := qdiscBackend{Root: QdiscFq}
+	stats = map[string]ClassStat{"1:": {ClassID: "1:", Bytes: 4_000, Drops: 1}}
+	got = classSamples(stats, watchRows(fq, []string{"partner"}, nil), DirEgress, "enp0s1")
+	require.Equal(t, []ClassSample{
+		{Class: "(all classes)", Dir: DirEgress, Iface: "enp0s1", ClassStat: stats["1:"]},
+	}, got, "fq is sampled at the root qdisc")
+}
+
 func TestHumanRate(t *testing.T) {
 	require.Equal(t, "0 bit/s", humanRate(0))
 	require.Equal(t, "1.00 Kbit/s", humanRate(1_000))
@@ -218,3 +260,92 @@ func TestResolveWatchClasses_UnknownClass(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), "unknown class"))
 }
+
+// qdiscStatsRunner returns a fixed sequence of qdisc samples from QdiscStats,
+// repeating the last one once exhausted.
+type qdiscStatsRunner struct {
+	recordingTCRunner
+	samples [][]QdiscStat
+	idx     int
+}
+
+func (r *qdiscStatsRunner) QdiscStats(ctx context.Context, _ string) ([]QdiscStat, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i := min(r.idx, len(r.samples)-1)
+	r.idx++
+	return r.samples[i], nil
+}
+
+func TestWatchRows_CakeGroupsClassesByTin(t *testing.T) {
+	be := qdiscBackend{Root: QdiscCake, Leaf: LeafFqCodel}
+	configs := []*ClassConfig{
+		{Name: "partner", Prio: 0},
+		{Name: "public", Prio: 5},
+		{Name: "reserve-egress", Prio: 4},
+	}
+	got := watchRows(be, []string{"partner", "public", "reserve-egress"}, configs)
+	require.Equal(t, []watchRow{
+		{Name: "partner", Key: "voice"},
+		{Name: "public,reserve-egress", Key: "best-effort"},
+	}, got)
+
+	require.Equal(t, []watchRow{{Name: "(all classes)", Key: "1:"}},
+		watchRows(qdiscBackend{Root: QdiscFq}, []string{"partner"}, configs))
+}
+
+func TestBackendStats_CakeTinsAndFqRoot(t *testing.T) {
+	cake := []QdiscStat{
+		{Kind: "fq_codel", Handle: "8001:"}, // not the root: ignored
+		{Kind: "cake", Handle: "1:", Root: true, Tins: []TinStat{
+			{Bytes: 1}, {Bytes: 2}, {Bytes: 3}, {Bytes: 4, Drops: 1, Marks: 7},
+		}},
+	}
+	m := newWatchManager(&qdiscStatsRunner{samples: [][]QdiscStat{cake}})
+	got, err := m.backendStats(context.Background(), "enp0s1", qdiscBackend{Root: QdiscCake})
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+	require.Equal(t, ClassStat{ClassID: "voice", Bytes: 4, Drops: 1, Marks: 7}, got["voice"])
+	require.Equal(t, uint64(1), got["bulk"].Bytes)
+
+	// The recorded backend is CAKE but an fq root is installed: nothing matches.
+	got, err = m.backendStats(context.Background(), "enp0s1", qdiscBackend{Root: QdiscFq})
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestWatch_CakeReportsPerTinRows(t *testing.T) {
+	tins := func(voice uint64) []QdiscStat {
+		return []QdiscStat{{Kind: "cake", Handle: "1:", Root: true, Tins: []TinStat{
+			{}, {}, {}, {Bytes: voice, Marks: voice / 1000},
+		}}}
+	}
+	tc := &qdiscStatsRunner{samples: [][]QdiscStat{tins(0), tins(125_000)}}
+	m := newWatchManager(tc)
+	be := qdiscBackend{Root: QdiscCake, Leaf: LeafFqCodel}
+	rows := watchRows(be, []string{"partner"}, []*ClassConfig{{Name: "partner", Prio: 0}})
+
+	var buf bytes.Buffer
+	spec := WatchSpec{Device: "egress", Iface: "enp0s1", Interval: time.Millisecond, Count: 1}
+	require.NoError(t, m.watch(context.Background(), spec, DirEgress, be, rows, &buf))
+	out := buf.String()
+	require.Contains(t, out, "(egress device, cake root)")
+	require.Contains(t, out, "TIN")
+	require.Contains(t, out, "MARKED")
+	require.Contains(t, out, "partner")
+	require.Contains(t, out, "voice")
+	require.Contains(t, out, "+125")
+}
+
+func TestFormatDeviceStats(t *testing.T) {
+	be := qdiscBackend{Root: QdiscFq, Leaf: LeafFqCodel}
+	rows := watchRows(be, nil, nil)
+	out := formatDeviceStats("enp0s1", be, rows, map[string]ClassStat{"1:": {Bytes: 2048, Packets: 3, Drops: 1}})
+	require.Contains(t, out, "live counters on enp0s1 (fq root)")
+	require.Contains(t, out, "(all classes)")
+	require.Contains(t, out, "2.00 KB")
+
+	out = formatDeviceStats("enp0s1", be, rows, map[string]ClassStat{})
+	require.Contains(t, out, "no fq hierarchy installed on enp0s1")
+}
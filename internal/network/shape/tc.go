@@ -39,14 +39,37 @@ type TCRunner interface {
 	// rate <rate> ceil <ceil> prio <prio>`, a per-class leaf under the trunk.
 	ClassAdd(ctx context.Context, nic, minor, rate, ceil string, prio int) error
 
-	// QdiscAddFqCodel runs `tc qdisc add dev <nic> parent 1:<minor> handle
-	// <handle>: fq_codel`, the leaf qdisc for a class.
-	QdiscAddFqCodel(ctx context.Context, nic, minor, handle string) error
+	// QdiscAddLeaf runs `tc qdisc add dev <nic> parent 1:<minor> handle
+	// <handle>: <leaf>`, the leaf qdisc for a class (fq_codel, cake or fq).
+	QdiscAddLeaf(ctx context.Context, nic, minor, handle, leaf string) error
+
+	// QdiscAddRootCake runs `tc qdisc add dev <nic> root handle 1: cake
+	// bandwidth <rate> diffserv4`, the classless CAKE backend.
+	QdiscAddRootCake(ctx context.Context, nic, rate string) error
+
+	// QdiscAddRootFq runs `tc qdisc add dev <nic> root handle 1: fq maxrate
+	// <rate>`, the classless fq backend.
+	QdiscAddRootFq(ctx context.Context, nic, rate string) error
+
+	// FilterAddTin runs `tc filter add dev <nic> parent 1: protocol all prio
+	// <pref> basic match meta(priority eq <priority>) action skbedit priority
+	// 1:<tin>`, steering one class's stamped traffic into a CAKE tin.
+	FilterAddTin(ctx context.Context, nic string, f tinFilter) error
+
+	// FilterAddDefaultTin runs `tc filter add dev <nic> parent 1: protocol all
+	// prio 1000 matchall action skbedit priority 1:<tin>`, steering traffic no
+	// class filter matched into the default class's CAKE tin.
+	FilterAddDefaultTin(ctx context.Context, nic string, tin int) error
 
 	// ClassStats runs `tc -s -j class show dev <dev>` and returns each class's
 	// cumulative counters keyed by tc handle (e.g. "1:40"). It is the read
 	// counterpart to the write verbs above, backing `network shape watch`.
 	ClassStats(ctx context.Context, dev string) (map[string]ClassStat, error)
+
+	// QdiscStats runs `tc -s -j qdisc show dev <dev>` and returns every qdisc's
+	// cumulative counters, including CAKE's per-tin counters. It backs the
+	// per-backend stats of the classless CAKE and fq roots.
+	QdiscStats(ctx context.Context, dev string) ([]QdiscStat, error)
 }
 
 // The tc*Args helpers are the single source of the tc command argument
@@ -88,8 +111,39 @@ func tcClassChangeArgs(nic, minor, rate, ceil string, prio int) []string {
 	return []string{"class", "change", "dev", nic, "parent", "1:1", "classid", "1:" + minor, "htb", "rate", rate, "ceil", ceil, "prio", strconv.Itoa(prio)}
 }
 
-// tcQdiscAddFqCodelArgs builds `qdisc add dev <nic> parent 1:<minor> handle
-// <handle>: fq_codel` — the leaf qdisc for a class.
-func tcQdiscAddFqCodelArgs(nic, minor, handle string) []string {
-	return []string{"qdisc", "add", "dev", nic, "parent", "1:" + minor, "handle", handle + ":", "fq_codel"}
+// tcQdiscAddLeafArgs builds `qdisc add dev <nic> parent 1:<minor> handle
+// <handle>: <leaf...>` — the leaf qdisc for a class, with the options
+// leafQdiscSpec adds for it.
+func tcQdiscAddLeafArgs(nic, minor, handle, leaf string) []string {
+	return append([]string{"qdisc", "add", "dev", nic, "parent", "1:" + minor, "handle", handle + ":"}, leafQdiscSpec(leaf)...)
+}
+
+// tcQdiscAddRootCakeArgs builds `qdisc add dev <nic> root handle 1: cake
+// bandwidth <rate> diffserv4`.
+func tcQdiscAddRootCakeArgs(nic, rate string) []string {
+	return []string{"qdisc", "add", "dev", nic, "root", "handle", "1:", "cake", "bandwidth", rate, "diffserv4"}
+}
+
+// tcQdiscAddRootFqArgs builds `qdisc add dev <nic> root handle 1: fq maxrate
+// <rate>`.
+func tcQdiscAddRootFqArgs(nic, rate string) []string {
+	return []string{"qdisc", "add", "dev", nic, "root", "handle", "1:", "fq", "maxrate", rate}
+}
+
+// tcFilterAddTinArgs builds `filter add dev <nic> parent 1: protocol all prio
+// <pref> basic match meta(priority eq <priority>) action skbedit priority
+// 1:<tin>`. The ematch is passed as separate words: tc's ematch parser joins
+// them, and the boot script quotes it only to keep the parentheses from the
+// shell.
+func tcFilterAddTinArgs(nic string, f tinFilter) []string {
+	return []string{"filter", "add", "dev", nic, "parent", "1:", "protocol", "all", "prio", f.Pref,
+		"basic", "match", "meta(priority", "eq", f.Priority + ")",
+		"action", "skbedit", "priority", "1:" + strconv.Itoa(f.Tin)}
+}
+
+// tcFilterAddDefaultTinArgs builds `filter add dev <nic> parent 1: protocol all
+// prio 1000 matchall action skbedit priority 1:<tin>`.
+func tcFilterAddDefaultTinArgs(nic string, tin int) []string {
+	return []string{"filter", "add", "dev", nic, "parent", "1:", "protocol", "all", "prio", cakeDefaultFilterPref,
+		"matchall", "action", "skbedit", "priority", "1:" + strconv.Itoa(tin)}
 }
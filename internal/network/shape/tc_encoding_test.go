@@ -63,10 +63,78 @@ func TestBootScriptTcEncodingMatchesArgBuilders(t *testing.T) {
 		}
 		expected = append(expected,
 			tcClassAddArgs(nic, ci.Minor, c.Rate, c.effectiveCeil(), c.Prio),
-			tcQdiscAddFqCodelArgs(nic, ci.Minor, ci.Handle),
+			tcQdiscAddLeafArgs(nic, ci.Minor, ci.Handle, LeafFqCodel),
 		)
 	}
 
+	requireTcLinesMatch(t, rendered, expected)
+}
+
+// TestBootScriptTcEncodingMatchesArgBuilders_Backends extends the lockstep
+// guard to the selectable qdisc backends: an HTB root with CAKE leaves, the
+// CAKE root with its tin filters, and the flat fq root.
+func TestBootScriptTcEncodingMatchesArgBuilders_Backends(t *testing.T) {
+	const nic = "$NIC"
+	classes := []*ClassConfig{
+		{Name: "partner", Rate: "200mbit", Ceil: "350mbit", Prio: 0},
+		{Name: "public", Rate: "150mbit", Ceil: "350mbit", Prio: 5},
+		{Name: "reserve-egress", Rate: "150mbit", Prio: 1},
+	}
+
+	t.Run("htb with cake leaves", func(t *testing.T) {
+		dev := &DeviceConfig{Dir: DirEgress, Rate: "500mbit", DefaultClass: "reserve-egress", Leaf: LeafCake}
+		rendered, err := renderTcEgressScriptFromConfig("enp0s1", dev, classes)
+		if err != nil {
+			t.Fatalf("renderTcEgressScriptFromConfig: %v", err)
+		}
+		expected := [][]string{
+			tcQdiscDelRootArgs(nic),
+			tcQdiscAddRootArgs(nic, "60"),
+			tcClassAddRootArgs(nic, dev.Rate, dev.Rate),
+			tcClassAddArgs(nic, "40", "200mbit", "350mbit", 0),
+			tcQdiscAddLeafArgs(nic, "40", "140", LeafCake),
+			tcClassAddArgs(nic, "50", "150mbit", "350mbit", 5),
+			tcQdiscAddLeafArgs(nic, "50", "150", LeafCake),
+			tcClassAddArgs(nic, "60", "150mbit", "150mbit", 1),
+			tcQdiscAddLeafArgs(nic, "60", "160", LeafCake),
+		}
+		requireTcLinesMatch(t, rendered, expected)
+	})
+
+	t.Run("cake root", func(t *testing.T) {
+		dev := &DeviceConfig{Dir: DirEgress, Rate: "500mbit", DefaultClass: "reserve-egress", Qdisc: QdiscCake}
+		rendered, err := renderTcEgressScriptFromConfig("enp0s1", dev, classes)
+		if err != nil {
+			t.Fatalf("renderTcEgressScriptFromConfig: %v", err)
+		}
+		expected := [][]string{
+			tcQdiscDelRootArgs(nic),
+			tcQdiscAddRootCakeArgs(nic, dev.Rate),
+			tcFilterAddTinArgs(nic, tinFilter{Pref: "64", Priority: "0x10040", Tin: 4}),
+			tcFilterAddTinArgs(nic, tinFilter{Pref: "80", Priority: "0x10050", Tin: 2}),
+			tcFilterAddTinArgs(nic, tinFilter{Pref: "96", Priority: "0x10060", Tin: 3}),
+			tcFilterAddDefaultTinArgs(nic, 3),
+		}
+		requireTcLinesMatch(t, rendered, expected)
+	})
+
+	t.Run("fq root", func(t *testing.T) {
+		dev := &DeviceConfig{Dir: DirEgress, Rate: "500mbit", DefaultClass: "reserve-egress", Qdisc: QdiscFq}
+		rendered, err := renderTcEgressScriptFromConfig("enp0s1", dev, classes)
+		if err != nil {
+			t.Fatalf("renderTcEgressScriptFromConfig: %v", err)
+		}
+		requireTcLinesMatch(t, rendered, [][]string{
+			tcQdiscDelRootArgs(nic),
+			tcQdiscAddRootFqArgs(nic, dev.Rate),
+		})
+	})
+}
+
+// requireTcLinesMatch asserts the rendered script's `tc ...` lines equal the
+// expected arg-builder encodings, in order.
+func requireTcLinesMatch(t *testing.T, rendered string, expected [][]string) {
+	t.Helper()
 	var actual [][]string
 	for _, ln := range strings.Split(rendered, "\n") {
 		if strings.HasPrefix(strings.TrimSpace(ln), "tc ") {
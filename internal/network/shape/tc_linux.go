@@ -29,14 +29,37 @@ type tcClassJSON struct {
 	Packets    uint64 `json:"packets"`
 	Drops      uint64 `json:"drops"`
 	Overlimits uint64 `json:"overlimits"`
+	Backlog    uint64 `json:"backlog"`
 	Stats      *struct {
 		Bytes      uint64 `json:"bytes"`
 		Packets    uint64 `json:"packets"`
 		Drops      uint64 `json:"drops"`
 		Overlimits uint64 `json:"overlimits"`
+		Backlog    uint64 `json:"backlog"`
 	} `json:"stats"`
 }
 
+// tcQdiscJSON is the subset of one element of `tc -s -j qdisc show` output we
+// consume. Tins is only present for CAKE, one entry per tin in
+// priority-override order.
+type tcQdiscJSON struct {
+	Kind       string `json:"kind"`
+	Handle     string `json:"handle"`
+	Root       bool   `json:"root"`
+	Bytes      uint64 `json:"bytes"`
+	Packets    uint64 `json:"packets"`
+	Drops      uint64 `json:"drops"`
+	Overlimits uint64 `json:"overlimits"`
+	Backlog    uint64 `json:"backlog"`
+	Tins       []struct {
+		SentBytes   uint64 `json:"sent_bytes"`
+		SentPackets uint64 `json:"sent_packets"`
+		Drops       uint64 `json:"drops"`
+		ECNMark     uint64 `json:"ecn_mark"`
+		Backlog     uint64 `json:"backlog_bytes"`
+	} `json:"tins"`
+}
+
 type execTCRunner struct{}
 
 // run execs `tc <args...>` and wraps any non-zero exit with the combined output.
@@ -72,8 +95,24 @@ func (r *execTCRunner) ClassAdd(ctx context.Context, nic, minor, rate, ceil stri
 	return r.run(ctx, tcClassAddArgs(nic, minor, rate, ceil, prio)...)
 }
 
-func (r *execTCRunner) QdiscAddFqCodel(ctx context.Context, nic, minor, handle string) error {
-	return r.run(ctx, tcQdiscAddFqCodelArgs(nic, minor, handle)...)
+func (r *execTCRunner) QdiscAddLeaf(ctx context.Context, nic, minor, handle, leaf string) error {
+	return r.run(ctx, tcQdiscAddLeafArgs(nic, minor, handle, leaf)...)
+}
+
+func (r *execTCRunner) QdiscAddRootCake(ctx context.Context, nic, rate string) error {
+	return r.run(ctx, tcQdiscAddRootCakeArgs(nic, rate)...)
+}
+
+func (r *execTCRunner) QdiscAddRootFq(ctx context.Context, nic, rate string) error {
+	return r.run(ctx, tcQdiscAddRootFqArgs(nic, rate)...)
+}
+
+func (r *execTCRunner) FilterAddTin(ctx context.Context, nic string, f tinFilter) error {
+	return r.run(ctx, tcFilterAddTinArgs(nic, f)...)
+}
+
+func (r *execTCRunner) FilterAddDefaultTin(ctx context.Context, nic string, tin int) error {
+	return r.run(ctx, tcFilterAddDefaultTinArgs(nic, tin)...)
 }
 
 func (r *execTCRunner) ClassStats(ctx context.Context, dev string) (map[string]ClassStat, error) {
@@ -103,6 +142,7 @@ func (r *execTCRunner) ClassStats(ctx context.Context, dev string) (map[string]C
 			Packets:    c.Packets,
 			Drops:      c.Drops,
 			Overlimits: c.Overlimits,
+			Backlog:    c.Backlog,
 		}
 		// Fallback only when the flat form carried nothing: some iproute2 builds
 		// nest the counters under "stats" instead. Never overwrite populated
@@ -112,12 +152,55 @@ func (r *execTCRunner) ClassStats(ctx context.Context, dev string) (map[string]C
 			s.Packets = c.Stats.Packets
 			s.Drops = c.Stats.Drops
 			s.Overlimits = c.Stats.Overlimits
+			s.Backlog = c.Stats.Backlog
 		}
 		stats[c.Handle] = s
 	}
 	return stats, nil
 }
 
+func (r *execTCRunner) QdiscStats(ctx context.Context, dev string) ([]QdiscStat, error) {
+	cmd := exec.CommandContext(ctx, tcBin, "-s", "-j", "qdisc", "show", "dev", dev)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err,
+			"tc -s -j qdisc show dev %s failed: %s", dev, strings.TrimSpace(stderr.String()))
+	}
+
+	var raw []tcQdiscJSON
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, errorx.ExternalError.Wrap(err,
+			"failed to parse tc qdisc stats JSON for dev %s", dev)
+	}
+
+	stats := make([]QdiscStat, 0, len(raw))
+	for _, q := range raw {
+		s := QdiscStat{
+			Kind:       q.Kind,
+			Handle:     q.Handle,
+			Root:       q.Root,
+			Bytes:      q.Bytes,
+			Packets:    q.Packets,
+			Drops:      q.Drops,
+			Overlimits: q.Overlimits,
+			Backlog:    q.Backlog,
+		}
+		for _, t := range q.Tins {
+			s.Tins = append(s.Tins, TinStat{
+				Bytes:   t.SentBytes,
+				Packets: t.SentPackets,
+				Drops:   t.Drops,
+				Marks:   t.ECNMark,
+				Backlog: t.Backlog,
+			})
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
 // newExecTCRunner returns the production TC runner that shells out to /sbin/tc.
 func newExecTCRunner() TCRunner {
 	return &execTCRunner{}
@@ -32,7 +32,21 @@ func (r *noopTCRunner) ClassAdd(_ context.Context, _, _, _, _ string, _ int) err
 	return errUnsupported()
 }
 
-func (r *noopTCRunner) QdiscAddFqCodel(_ context.Context, _, _, _ string) error {
+func (r *noopTCRunner) QdiscAddLeaf(_ context.Context, _, _, _, _ string) error {
+	return errUnsupported()
+}
+
+func (r *noopTCRunner) QdiscAddRootCake(_ context.Context, _, _ string) error {
+	return errUnsupported()
+}
+
+func (r *noopTCRunner) QdiscAddRootFq(_ context.Context, _, _ string) error { return errUnsupported() }
+
+func (r *noopTCRunner) FilterAddTin(_ context.Context, _ string, _ tinFilter) error {
+	return errUnsupported()
+}
+
+func (r *noopTCRunner) FilterAddDefaultTin(_ context.Context, _ string, _ int) error {
 	return errUnsupported()
 }
 
@@ -40,6 +54,10 @@ func (r *noopTCRunner) ClassStats(_ context.Context, _ string) (map[string]Class
 	return nil, errUnsupported()
 }
 
+func (r *noopTCRunner) QdiscStats(_ context.Context, _ string) ([]QdiscStat, error) {
+	return nil, errUnsupported()
+}
+
 // newExecTCRunner returns a no-op runner on non-Linux platforms.
 func newExecTCRunner() TCRunner {
 	return &noopTCRunner{}
@@ -47,8 +47,11 @@ func effectiveIngressRootRate(ingressRate, egressRate string) (string, error) {
 //
 // The hierarchy is: root HTB qdisc (default → the ingress default class), a
 // trunk class 1:1 at the device root rate, one leaf class per recorded ingress
-// class (1:10 / 1:20 / 1:30) with an fq_codel qdisc, and no tc filters — HTB
-// classifies natively on skb->priority set by the nft classification rules.
+// class (1:10 / 1:20 / 1:30) with its leaf qdisc (fq_codel unless the device
+// selects another), and no tc filters — HTB classifies natively on
+// skb->priority set by the nft classification rules. A device recorded with the
+// CAKE or fq backend gets that root instead; CAKE adds tin filters keyed on the
+// same skb->priority.
 //
 // The apply is idempotent: the root qdisc is torn down first (cascading to all
 // classes and leaf qdiscs), so a rebind on a recycled veth name starts clean.
@@ -92,30 +95,37 @@ func (m *Manager) ApplyIngressVeth(ctx context.Context, veth string) error {
 			"no ingress shape classes recorded; run `network shape create --class <name> --rate <bandwidth>` for the ingress classes first (normally done by `block node install`)")
 	}
 
-	if err := m.applyVethHierarchy(ctx, veth, defInfo.Minor, rootRate, classes); err != nil {
+	be := dev.backend()
+	if err := m.applyVethHierarchy(ctx, veth, be, defInfo.Minor, rootRate, classes); err != nil {
 		return err
 	}
 
 	logx.As().Info().
 		Str("veth", veth).
+		Str("qdisc", be.Root).
 		Str("default_class", dev.DefaultClass).
 		Int("classes", len(classes)).
-		Msg("installed $VETH ingress HTB hierarchy")
+		Msg("installed $VETH ingress shaping hierarchy")
 	return nil
 }
 
 // applyVethHierarchy installs the §5.1 HTB hierarchy on veth under the tc lock:
 // tear down any existing root qdisc, add the root HTB qdisc (default →
-// defaultMinor), the trunk class 1:1 at rootRate, then one leaf class + fq_codel
-// per recorded class. Split from ApplyIngressVeth (which does the disk reads and
-// validation) so the tc-call sequence is unit-testable with a fake TCRunner.
-func (m *Manager) applyVethHierarchy(ctx context.Context, veth, defaultMinor, rootRate string, classes []*ClassConfig) error {
+// defaultMinor), the trunk class 1:1 at rootRate, then one leaf class + leaf
+// qdisc per recorded class. Under the CAKE or fq backend the root is that
+// qdisc instead (see applyVethClassless). Split from ApplyIngressVeth (which
+// does the disk reads and validation) so the tc-call sequence is unit-testable
+// with a fake TCRunner.
+func (m *Manager) applyVethHierarchy(ctx context.Context, veth string, be qdiscBackend, defaultMinor, rootRate string, classes []*ClassConfig) error {
 	return m.withLock(func() error {
 		// Tear down any existing hierarchy so a rebind on a recycled veth name
 		// (or a partially-applied prior attempt) starts from a clean slate.
 		if err := m.tcRunner.QdiscDelRoot(ctx, veth); err != nil {
 			return err
 		}
+		if be.Root != QdiscHTB {
+			return m.applyVethClassless(ctx, veth, be, defaultMinor, rootRate, classes)
+		}
 		if err := m.tcRunner.QdiscAddRoot(ctx, veth, defaultMinor); err != nil {
 			return err
 		}
@@ -130,7 +140,7 @@ func (m *Manager) applyVethHierarchy(ctx context.Context, veth, defaultMinor, ro
 			if err := m.tcRunner.ClassAdd(ctx, veth, ci.Minor, cls.Rate, cls.effectiveCeil(), cls.Prio); err != nil {
 				return err
 			}
-			if err := m.tcRunner.QdiscAddFqCodel(ctx, veth, ci.Minor, ci.Handle); err != nil {
+			if err := m.tcRunner.QdiscAddLeaf(ctx, veth, ci.Minor, ci.Handle, be.Leaf); err != nil {
 				return err
 			}
 		}
@@ -138,6 +148,28 @@ func (m *Manager) applyVethHierarchy(ctx context.Context, veth, defaultMinor, ro
 	})
 }
 
+// applyVethClassless installs a CAKE or fq root on veth, with the root qdisc
+// already torn down and the tc lock held. CAKE gets one tin filter per class
+// plus the catch-all for the default class; fq takes no filters.
+func (m *Manager) applyVethClassless(ctx context.Context, veth string, be qdiscBackend, defaultMinor, rootRate string, classes []*ClassConfig) error {
+	if be.Root == QdiscFq {
+		return m.tcRunner.QdiscAddRootFq(ctx, veth, rootRate)
+	}
+	filters, err := cakeTinFilters(classes)
+	if err != nil {
+		return err
+	}
+	if err := m.tcRunner.QdiscAddRootCake(ctx, veth, rootRate); err != nil {
+		return err
+	}
+	for _, f := range filters {
+		if err := m.tcRunner.FilterAddTin(ctx, veth, f); err != nil {
+			return err
+		}
+	}
+	return m.tcRunner.FilterAddDefaultTin(ctx, veth, defaultClassTin(defaultMinor, classes).Index)
+}
+
 // RemoveIngressVeth tears down the $VETH ingress HTB hierarchy on the given
 // veth. It is best-effort: the kernel auto-removes veth-attached qdiscs when the
 // veth disappears on pod delete, so this is mostly for the proactive
@@ -44,15 +44,30 @@ func (r *recordingTCRunner) ClassAddRoot(_ context.Context, nic, rate, ceil stri
 func (r *recordingTCRunner) ClassAdd(_ context.Context, nic, minor, rate, ceil string, prio int) error {
 	return r.record(fmt.Sprintf("class-add %s 1:%s %s %s prio=%d", nic, minor, rate, ceil, prio))
 }
-func (r *recordingTCRunner) QdiscAddFqCodel(_ context.Context, nic, minor, handle string) error {
-	return r.record(fmt.Sprintf("qdisc-fqcodel %s 1:%s handle=%s", nic, minor, handle))
+func (r *recordingTCRunner) QdiscAddLeaf(_ context.Context, nic, minor, handle, leaf string) error {
+	return r.record(fmt.Sprintf("qdisc-leaf %s 1:%s handle=%s %s", nic, minor, handle, leaf))
+}
+func (r *recordingTCRunner) QdiscAddRootCake(_ context.Context, nic, rate string) error {
+	return r.record(fmt.Sprintf("qdisc-add-root-cake %s %s", nic, rate))
+}
+func (r *recordingTCRunner) QdiscAddRootFq(_ context.Context, nic, rate string) error {
+	return r.record(fmt.Sprintf("qdisc-add-root-fq %s %s", nic, rate))
+}
+func (r *recordingTCRunner) FilterAddTin(_ context.Context, nic string, f tinFilter) error {
+	return r.record(fmt.Sprintf("filter-tin %s pref=%s %s -> 1:%d", nic, f.Pref, f.Priority, f.Tin))
+}
+func (r *recordingTCRunner) FilterAddDefaultTin(_ context.Context, nic string, tin int) error {
+	return r.record(fmt.Sprintf("filter-default-tin %s -> 1:%d", nic, tin))
 }
 
-// ClassStats is unused by the write-path tests; the watch tests use a dedicated
-// scripted fake (see stats_test.go).
+// ClassStats and QdiscStats are unused by the write-path tests; the watch
+// tests use a dedicated scripted fake (see stats_test.go).
 func (r *recordingTCRunner) ClassStats(_ context.Context, _ string) (map[string]ClassStat, error) {
 	return nil, nil
 }
+func (r *recordingTCRunner) QdiscStats(_ context.Context, _ string) ([]QdiscStat, error) {
+	return nil, nil
+}
 
 func newRecordingManager(t *testing.T, tc TCRunner) *Manager {
 	t.Helper()
@@ -62,6 +77,9 @@ func newRecordingManager(t *testing.T, tc TCRunner) *Manager {
 	})
 }
 
+// htbDefault is the backend of a device recorded without a qdisc selection.
+var htbDefault = (&DeviceConfig{}).backend()
+
 // ingressClasses returns the three §5.1 ingress classes in the sorted order
 // loadClassesForDir would yield (alphabetical by name).
 func ingressClasses() []*ClassConfig {
@@ -77,7 +95,7 @@ func TestApplyVethHierarchy_InstallsSection51Sequence(t *testing.T) {
 	m := newRecordingManager(t, tc)
 
 	// default class reserve-ingress → minor 30.
-	if err := m.applyVethHierarchy(context.Background(), "lxc1a2b3c", "30", "1gbit", ingressClasses()); err != nil {
+	if err := m.applyVethHierarchy(context.Background(), "lxc1a2b3c", htbDefault, "30", "1gbit", ingressClasses()); err != nil {
 		t.Fatalf("applyVethHierarchy: %v", err)
 	}
 
@@ -87,13 +105,13 @@ func TestApplyVethHierarchy_InstallsSection51Sequence(t *testing.T) {
 		"class-add-root lxc1a2b3c 1:1 1gbit 1gbit",
 		// backfill-response → 1:20 / handle 120
 		"class-add lxc1a2b3c 1:20 100mbit 1gbit prio=7",
-		"qdisc-fqcodel lxc1a2b3c 1:20 handle=120",
+		"qdisc-leaf lxc1a2b3c 1:20 handle=120 fq_codel",
 		// publisher → 1:10 / handle 110
 		"class-add lxc1a2b3c 1:10 800mbit 1gbit prio=0",
-		"qdisc-fqcodel lxc1a2b3c 1:10 handle=110",
+		"qdisc-leaf lxc1a2b3c 1:10 handle=110 fq_codel",
 		// reserve-ingress → 1:30 / handle 130
 		"class-add lxc1a2b3c 1:30 100mbit 1gbit prio=1",
-		"qdisc-fqcodel lxc1a2b3c 1:30 handle=130",
+		"qdisc-leaf lxc1a2b3c 1:30 handle=130 fq_codel",
 	}
 	if len(tc.calls) != len(want) {
 		t.Fatalf("call count = %d, want %d\ngot:  %v\nwant: %v", len(tc.calls), len(want), tc.calls, want)
@@ -105,13 +123,48 @@ func TestApplyVethHierarchy_InstallsSection51Sequence(t *testing.T) {
 	}
 }
 
+func TestApplyVethHierarchy_LeafQdiscFollowsBackend(t *testing.T) {
+	tc := &recordingTCRunner{}
+	m := newRecordingManager(t, tc)
+
+	be := qdiscBackend{Root: QdiscHTB, Leaf: LeafCake}
+	classes := []*ClassConfig{{Name: "publisher", Rate: "800mbit", Prio: 0}}
+	require.NoError(t, m.applyVethHierarchy(context.Background(), "lxc9", be, "30", "1gbit", classes))
+	require.Equal(t, "qdisc-leaf lxc9 1:10 handle=110 cake", tc.calls[len(tc.calls)-1])
+}
+
+func TestApplyVethHierarchy_CakeRootMapsClassesOntoTins(t *testing.T) {
+	tc := &recordingTCRunner{}
+	m := newRecordingManager(t, tc)
+
+	be := qdiscBackend{Root: QdiscCake, Leaf: LeafFqCodel}
+	require.NoError(t, m.applyVethHierarchy(context.Background(), "lxc1a2b3c", be, "30", "1gbit", ingressClasses()))
+	require.Equal(t, []string{
+		"qdisc-del-root lxc1a2b3c",
+		"qdisc-add-root-cake lxc1a2b3c 1gbit",
+		"filter-tin lxc1a2b3c pref=32 0x10020 -> 1:1", // backfill-response, prio 7 → bulk
+		"filter-tin lxc1a2b3c pref=16 0x10010 -> 1:4", // publisher, prio 0 → voice
+		"filter-tin lxc1a2b3c pref=48 0x10030 -> 1:3", // reserve-ingress, prio 1 → video
+		"filter-default-tin lxc1a2b3c -> 1:3",         // default reserve-ingress
+	}, tc.calls)
+}
+
+func TestApplyVethHierarchy_FqRootIsFlat(t *testing.T) {
+	tc := &recordingTCRunner{}
+	m := newRecordingManager(t, tc)
+
+	be := qdiscBackend{Root: QdiscFq, Leaf: LeafFqCodel}
+	require.NoError(t, m.applyVethHierarchy(context.Background(), "lxc1", be, "30", "500mbit", ingressClasses()))
+	require.Equal(t, []string{"qdisc-del-root lxc1", "qdisc-add-root-fq lxc1 500mbit"}, tc.calls)
+}
+
 func TestApplyVethHierarchy_DefaultCeilFallsBackToRate(t *testing.T) {
 	tc := &recordingTCRunner{}
 	m := newRecordingManager(t, tc)
 
 	// A class with an empty Ceil should render ceil == rate.
 	classes := []*ClassConfig{{Name: "publisher", Rate: "800mbit", Ceil: "", Prio: 0}}
-	if err := m.applyVethHierarchy(context.Background(), "lxc9", "30", "1gbit", classes); err != nil {
+	if err := m.applyVethHierarchy(context.Background(), "lxc9", htbDefault, "30", "1gbit", classes); err != nil {
 		t.Fatalf("applyVethHierarchy: %v", err)
 	}
 	found := false
@@ -129,7 +182,7 @@ func TestApplyVethHierarchy_StopsOnMidSequenceFailure(t *testing.T) {
 	tc := &recordingTCRunner{failOn: map[string]bool{"class-add": true}}
 	m := newRecordingManager(t, tc)
 
-	err := m.applyVethHierarchy(context.Background(), "lxc1", "30", "1gbit", ingressClasses())
+	err := m.applyVethHierarchy(context.Background(), "lxc1", htbDefault, "30", "1gbit", ingressClasses())
 	if err == nil {
 		t.Fatal("expected error when a class-add fails, got nil")
 	}
@@ -139,7 +192,7 @@ func TestApplyVethHierarchy_StopsOnMidSequenceFailure(t *testing.T) {
 		t.Errorf("expected to stop on the failing class-add, last call = %q", got)
 	}
 	for _, c := range tc.calls {
-		if strings.HasPrefix(c, "qdisc-fqcodel") {
+		if strings.HasPrefix(c, "qdisc-leaf") {
 			t.Errorf("must not proceed to fq_codel after a class-add failure; calls: %v", tc.calls)
 		}
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/automa-saga/logx"
 	"github.com/hashgraph/solo-weaver/internal/kube"
 	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/deps"
 	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/joomcode/errorx"
 	"helm.sh/helm/v3/pkg/release"
@@ -20,8 +21,11 @@ import (
 // blockNodeChecker probes the BlockNode Helm release and associated Kubernetes
 // PersistentVolumes to build a BlockNodeState.
 // It depends only on injectable helm/kube factories and a cluster probe.
+// release selects which block node on the host is probed; empty means the
+// default release.
 type blockNodeChecker struct {
 	sm            state.Manager
+	release       string
 	newHelm       func() (HelmManager, error)
 	newKube       func() (KubeClient, error)
 	clusterExists ClusterProbe
@@ -35,18 +39,40 @@ func NewBlockNodeChecker(
 	newHelm func() (HelmManager, error),
 	newKube func() (KubeClient, error),
 	clusterExists ClusterProbe,
+) (Checker[state.BlockNodeState], error) {
+	return NewBlockNodeReleaseChecker(sm, "", newHelm, newKube, clusterExists)
+}
+
+// NewBlockNodeReleaseChecker constructs a blockNodeChecker for the block node
+// deployed as release, for hosts running more than one block node.
+func NewBlockNodeReleaseChecker(
+	sm state.Manager,
+	release string,
+	newHelm func() (HelmManager, error),
+	newKube func() (KubeClient, error),
+	clusterExists ClusterProbe,
 ) (Checker[state.BlockNodeState], error) {
 	return &blockNodeChecker{
 		sm:            sm,
+		release:       release,
 		newHelm:       newHelm,
 		newKube:       newKube,
 		clusterExists: clusterExists,
 	}, nil
 }
 
+// releaseName returns the Helm release this checker probes.
+func (b *blockNodeChecker) releaseName() string {
+	if b.release == "" {
+		return deps.BLOCK_NODE_RELEASE
+	}
+	return b.release
+}
+
 func (b *blockNodeChecker) RefreshState(ctx context.Context) (state.BlockNodeState, error) {
 	now := htime.Now()
-	bn := b.sm.State().BlockNodeState
+	st := b.sm.State()
+	bn := st.BlockNode(b.release)
 
 	exists, err := b.clusterExists()
 	if !exists {
@@ -84,6 +110,9 @@ func (b *blockNodeChecker) RefreshState(ctx context.Context) (state.BlockNodeSta
 	// drop it — otherwise upgrade/reconfigure would auto-detect the NIC/rate instead
 	// of re-asserting the operator's original shaping.
 	shaping := bn.Shaping
+	// The service port and LoadBalancer pool are weaver's per-instance
+	// allocations; carry them over for the same reason.
+	servicePort, loadBalancerPool := bn.ServicePort, bn.LoadBalancerPool
 	bn = state.BlockNodeState{
 		ReleaseInfo: state.HelmReleaseInfo{
 			Name:          re.Name,
@@ -100,6 +129,8 @@ func (b *blockNodeChecker) RefreshState(ctx context.Context) (state.BlockNodeSta
 		Storage:                models.BlockNodeStorage{},
 		TrafficShapingDisabled: trafficShapingDisabled,
 		Shaping:                shaping,
+		ServicePort:            servicePort,
+		LoadBalancerPool:       loadBalancerPool,
 		LastSync:               now,
 	}
 
@@ -124,7 +155,8 @@ func (b *blockNodeChecker) RefreshState(ctx context.Context) (state.BlockNodeSta
 }
 
 // findBlockNodeHelmRelease iterates all Helm releases and returns the first one
-// whose manifest contains a StatefulSet labelled app.kubernetes.io/instance=block-node.
+// whose manifest contains a StatefulSet labelled app.kubernetes.io/instance with
+// the checker's release name (block-node unless another release was selected).
 // Returns (nil, nil) when no matching release is found.
 func (b *blockNodeChecker) findBlockNodeHelmRelease() (*release.Release, error) {
 	helm, err := b.newHelm()
@@ -149,7 +181,7 @@ func (b *blockNodeChecker) findBlockNodeHelmRelease() (*release.Release, error)
 			if manifest.GetKind() != "StatefulSet" {
 				continue
 			}
-			if manifest.GetLabels()["app.kubernetes.io/instance"] == "block-node" {
+			if manifest.GetLabels()["app.kubernetes.io/instance"] == b.releaseName() {
 				logx.As().Debug().
 					Str("releaseName", re.Name).
 					Str("namespace", manifest.GetNamespace()).
@@ -25,6 +25,11 @@ func NewClusterChecker(sm state.Manager, clusterExists ClusterProbe) (Checker[st
 
 func (c *clusterChecker) RefreshState(_ context.Context) (state.ClusterState, error) {
 	cs := state.NewClusterState()
+	// The recorded network layout is not observable from the cluster; carry it
+	// over so a refresh does not lose it.
+	if c.sm != nil {
+		cs.Config = c.sm.State().ClusterState.Config
+	}
 
 	exists, err := c.clusterExists()
 	if !exists {
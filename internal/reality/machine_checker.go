@@ -22,6 +22,9 @@ type machineChecker struct {
 	sandboxBinDir      string
 	stateDir           string
 	softwareInstallers map[string]software.Software
+	// installerAt returns the named component's installer pinned to version,
+	// or nil when the catalog does not declare that version.
+	installerAt func(name, version string) (software.Software, error)
 }
 
 // NewMachineChecker constructs a machineChecker.
@@ -42,9 +45,35 @@ func NewMachineChecker(sm state.Manager, sandboxBinDir, stateDir string) (Checke
 		sandboxBinDir:      sandboxBinDir,
 		stateDir:           stateDir,
 		softwareInstallers: softwareInstallers,
+		installerAt:        catalogInstallerAt,
 	}, nil
 }
 
+// catalogInstallerAt builds the named component's installer pinned to version
+// when the infrastructure catalog declares that version.
+func catalogInstallerAt(name, version string) (software.Software, error) {
+	newInstaller, ok := software.Installers()[name]
+	if !ok {
+		return nil, nil
+	}
+	catalog, err := software.LoadInfrastructureCatalog()
+	if err != nil {
+		return nil, err
+	}
+	inst, err := newInstaller(software.WithVersion(version))
+	if err != nil {
+		return nil, err
+	}
+	artifact, err := catalog.GetHostArtifact(inst.GetSoftwareName())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := artifact.Versions[software.Version(version)]; !ok {
+		return nil, nil
+	}
+	return inst, nil
+}
+
 // RefreshState collects current host software and hardware state.
 // Software state merges: persisted new state > legacy sidecar files > live binary stat.
 func (m *machineChecker) RefreshState(_ context.Context) (state.MachineState, error) {
@@ -57,7 +86,7 @@ func (m *machineChecker) RefreshState(_ context.Context) (state.MachineState, er
 	current := m.sm.State()
 
 	ms := current.MachineState
-	ms.Software = m.refreshSoftwareState()
+	ms.Software = m.refreshSoftwareState(current.MachineState.Software)
 	ms.Hardware = m.refreshHardwareState()
 	ms.LastSync = htime.Now()
 
@@ -65,9 +94,24 @@ func (m *machineChecker) RefreshState(_ context.Context) (state.MachineState, er
 }
 
 // refreshSoftwareState checks the presence and versions of relevant software binaries on the host.
-func (m *machineChecker) refreshSoftwareState() map[string]state.SoftwareState {
+// A component recorded at a catalog version other than its default (e.g. after
+// `kube cluster upgrade`) is verified at the recorded version, so in-place
+// upgraded binaries are not reported as missing.
+func (m *machineChecker) refreshSoftwareState(recorded map[string]state.SoftwareState) map[string]state.SoftwareState {
 	result := make(map[string]state.SoftwareState)
 	for name, inst := range m.softwareInstallers {
+		if rec, ok := recorded[name]; ok && rec.Version != "" && rec.Version != inst.Version() && m.installerAt != nil {
+			pinned, err := m.installerAt(name, rec.Version)
+			if err != nil {
+				logx.As().Error().Err(err).Str("software", name).Str("version", rec.Version).
+					Msg("Error creating installer for the recorded software version")
+				continue
+			}
+			if pinned != nil {
+				inst = pinned
+			}
+		}
+
 		st, err := inst.VerifyInstallation()
 		if err != nil {
 			logx.As().Error().Err(err).Str("software", name).Msg("Error verifying software installation")
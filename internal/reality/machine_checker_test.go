@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/software"
 )
 
 // fakeMachineStateManager satisfies state.Manager by embedding the interface
@@ -63,3 +64,53 @@ func TestMachineRefreshState_PreservesFirewall(t *testing.T) {
 		t.Errorf("Firewall SSH port not preserved: got %d, want 2222", got.Firewall.SSHPort)
 	}
 }
+
+// fakeSoftware satisfies software.Software by embedding the interface (nil) and
+// overriding only what refreshSoftwareState calls.
+type fakeSoftware struct {
+	software.Software
+	version string
+}
+
+func (f fakeSoftware) Version() string { return f.version }
+
+func (f fakeSoftware) VerifyInstallation() (*state.SoftwareState, error) {
+	return &state.SoftwareState{Name: "kubeadm", Version: f.version, Installed: f.version == "1.34.1"}, nil
+}
+
+// TestMachineRefreshState_VerifiesRecordedVersion verifies that a component
+// upgraded in place is verified at its recorded version, not the catalog
+// default, and that an undeclared recorded version falls back to the default.
+func TestMachineRefreshState_VerifiesRecordedVersion(t *testing.T) {
+	full := state.State{}
+	full.MachineState = state.NewMachineState()
+	full.MachineState.Software = map[string]state.SoftwareState{
+		"kubeadm": {Name: "kubeadm", Version: "1.34.1", Installed: true},
+		"kubelet": {Name: "kubelet", Version: "0.0.0-delisted", Installed: true},
+	}
+
+	checker := &machineChecker{
+		sm: fakeMachineStateManager{st: full},
+		softwareInstallers: map[string]software.Software{
+			"kubeadm": fakeSoftware{version: "1.33.4"},
+			"kubelet": fakeSoftware{version: "1.33.4"},
+		},
+		installerAt: func(name, version string) (software.Software, error) {
+			if version == "1.34.1" {
+				return fakeSoftware{version: version}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	got, err := checker.RefreshState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sw := got.Software["kubeadm"]; sw.Version != "1.34.1" || !sw.Installed {
+		t.Errorf("kubeadm must be verified at its recorded version, got %+v", sw)
+	}
+	if sw := got.Software["kubelet"]; sw.Version != "1.33.4" {
+		t.Errorf("an undeclared recorded version must fall back to the default installer, got %+v", sw)
+	}
+}
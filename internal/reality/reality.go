@@ -58,6 +58,7 @@ type checkerConfig struct {
 	newHelm       func() (HelmManager, error)
 	newKube       func() (KubeClient, error)
 	clusterExists ClusterProbe
+	release       string
 }
 
 // CheckerOption configures the Checker.
@@ -83,6 +84,12 @@ func WithClusterProbe(fn ClusterProbe) CheckerOption {
 	return func(c *checkerConfig) { c.clusterExists = fn }
 }
 
+// WithBlockNodeRelease selects the block node the BlockNode checker probes on
+// a host running several; the default release is probed otherwise.
+func WithBlockNodeRelease(release string) CheckerOption {
+	return func(c *checkerConfig) { c.release = release }
+}
+
 // ---------------------------------------------------------------------------
 // NewCheckers — production factory
 // ---------------------------------------------------------------------------
@@ -110,7 +117,7 @@ func NewCheckers(sm state.Manager, opts ...CheckerOption) (Checkers, error) {
 		return Checkers{}, errorx.IllegalState.Wrap(err, "failed to create machine checker")
 	}
 
-	blocknode, err := NewBlockNodeChecker(cc.sm, cc.newHelm, cc.newKube, cc.clusterExists)
+	blocknode, err := NewBlockNodeReleaseChecker(cc.sm, cc.release, cc.newHelm, cc.newKube, cc.clusterExists)
 	if err != nil {
 		return Checkers{}, errorx.IllegalState.Wrap(err, "failed to create block node checker")
 	}
@@ -18,6 +18,7 @@ import (
 // into every layer that needs it, eliminating package-level singletons.
 type RuntimeResolver struct {
 	sm               state.Manager
+	blockNodeRelease string // release BlockNodeRuntime tracks; see state.StateRecord.BlockNode
 	BlockNodeRuntime Resolver[state.BlockNodeState, models.BlockNodeInputs]
 	ClusterRuntime   Resolver[state.ClusterState, models.ClusterInputs]
 	MachineRuntime   Resolver[state.MachineState, models.MachineInputs]
@@ -38,7 +39,7 @@ func NewRuntimeResolver(
 		return nil, errorx.IllegalState.Wrap(err, "failed to initialise cluster runtime")
 	}
 
-	blockNodeRuntime, err := NewBlockNodeRuntimeResolver(cfg, currentState.BlockNodeState, realityChecker.BlockNode, refreshInterval)
+	blockNodeRuntime, err := NewBlockNodeRuntimeResolver(cfg, currentState.BlockNode(cfg.BlockNode.Release), realityChecker.BlockNode, refreshInterval)
 	if err != nil {
 		return nil, errorx.IllegalState.Wrap(err, "failed to initialise block-node runtime")
 	}
@@ -55,6 +56,7 @@ func NewRuntimeResolver(
 
 	return &RuntimeResolver{
 		sm:               sm,
+		blockNodeRelease: cfg.BlockNode.Release,
 		ClusterRuntime:   clusterRuntime,
 		BlockNodeRuntime: blockNodeRuntime,
 		MachineRuntime:   machineRuntime,
@@ -140,7 +142,7 @@ func (r *RuntimeResolver) CurrentState() (state.State, error) {
 	}
 
 	currentState.ClusterState = clusterState
-	currentState.BlockNodeState = blockNodeState
+	currentState.SetBlockNode(r.blockNodeRelease, blockNodeState)
 	currentState.MachineState = machineState
 	currentState.TeleportState = teleportState
 
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"sort"
+
+	"github.com/hashgraph/solo-weaver/pkg/deps"
+)
+
+// A host can run several block nodes side by side, each its own Helm release.
+// BlockNodeState predates that and stays the slot of the default release (or
+// of whichever release a single-instance state file recorded there), so old
+// state files load unchanged; every other release lives in BlockNodes.
+
+// ownsPrimary reports whether release is kept in BlockNodeState.
+func (r *StateRecord) ownsPrimary(release string) bool {
+	if _, ok := r.BlockNodes[release]; ok {
+		return false
+	}
+	name := r.BlockNodeState.ReleaseInfo.Name
+	return release == "" || release == name || (name == "" && release == deps.BLOCK_NODE_RELEASE)
+}
+
+// BlockNode returns the state of the block node deployed as release. An empty
+// release is the instance in BlockNodeState. A release with no recorded state
+// gets NewBlockNodeState.
+func (r *StateRecord) BlockNode(release string) BlockNodeState {
+	if bn, ok := r.BlockNodes[release]; ok {
+		return bn
+	}
+	if r.ownsPrimary(release) {
+		return r.BlockNodeState
+	}
+	return NewBlockNodeState()
+}
+
+// SetBlockNode records bn as the state of the block node deployed as release.
+// An additional instance whose release is gone (no release name, as reality
+// reports an uninstalled block node) is dropped rather than kept as an empty
+// entry.
+func (r *StateRecord) SetBlockNode(release string, bn BlockNodeState) {
+	if r.ownsPrimary(release) {
+		r.BlockNodeState = bn
+		return
+	}
+	if bn.ReleaseInfo.Name == "" {
+		delete(r.BlockNodes, release)
+		return
+	}
+	if r.BlockNodes == nil {
+		r.BlockNodes = make(map[string]BlockNodeState)
+	}
+	r.BlockNodes[release] = bn
+}
+
+// BlockNodeReleases returns the release names of every block node recorded on
+// the host, sorted.
+func (r *StateRecord) BlockNodeReleases() []string {
+	var out []string
+	if name := r.BlockNodeState.ReleaseInfo.Name; name != "" {
+		out = append(out, name)
+	}
+	for release := range r.BlockNodes {
+		out = append(out, release)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -16,6 +16,9 @@ import (
 const ModelVersion = "v2" // State model version (from v0.14.0) — increment on breaking changes requiring migration
 const StateFileName = "state.yaml"
 
+// ActionHistoryFileName is the append-only action log kept beside the state file.
+const ActionHistoryFileName = "action_history.yaml"
+
 // State is the on-disk envelope.  Envelope fields (Hash, HashAlgo, StateFile,
 // LastSync) are bookkeeping metadata that never participate in hashing.
 //
@@ -64,6 +67,10 @@ type StateRecord struct {
 	BlockNodeState   BlockNodeState  `yaml:"blockNodeState" json:"blockNodeState"`
 	TeleportState    TeleportState   `yaml:"teleportState" json:"teleportState"`
 	LastAction       ActionHistory   `yaml:"lastAction,omitempty" json:"lastAction,omitempty"` // last action performed, used for tracking and debugging
+	// BlockNodes holds every block node instance on the host other than the
+	// one in BlockNodeState, keyed by Helm release name. Read and write
+	// instances through BlockNode and SetBlockNode rather than either field.
+	BlockNodes map[string]BlockNodeState `yaml:"blockNodes,omitempty" json:"blockNodes,omitempty"`
 }
 
 // Hashable returns a deep copy of the domain StateRecord with all reconciliation
@@ -83,6 +90,16 @@ func (s State) Hashable() StateRecord {
 	r.BlockNodeState.LastSync = htime.Time{}
 	r.TeleportState.LastSync = htime.Time{}
 
+	// Additional block node instances — same copy-before-mutation pattern
+	if len(r.BlockNodes) > 0 {
+		bns := make(map[string]BlockNodeState, len(r.BlockNodes))
+		for k, v := range r.BlockNodes {
+			v.LastSync = htime.Time{}
+			bns[k] = v
+		}
+		r.BlockNodes = bns
+	}
+
 	// Software map — copy before mutation to avoid aliasing the original
 	if len(r.MachineState.Software) > 0 {
 		sw := make(map[string]SoftwareState, len(r.MachineState.Software))
@@ -130,7 +147,34 @@ type MachineState struct {
 	// block-node uninstall. Nil means "never configured" (old state files, or a
 	// host this tool never managed the firewall on). See HostFirewallState.
 	Firewall *HostFirewallState `yaml:"firewall,omitempty" json:"firewall,omitempty"`
-	LastSync htime.Time         `yaml:"lastSync,omitempty" json:"lastSync,omitempty"` // last time state was reconciled
+	// Cluster records the host's membership of the kubeadm cluster: the role it
+	// runs and which cluster it belongs to. It is host-scoped for the same
+	// reason as Firewall, and is written by `kube cluster install` and
+	// `kube cluster join` and cleared by `kube cluster uninstall`. Nil means
+	// the host is not (known to be) a cluster member. See ClusterMembershipState.
+	Cluster  *ClusterMembershipState `yaml:"cluster,omitempty" json:"cluster,omitempty"`
+	LastSync htime.Time              `yaml:"lastSync,omitempty" json:"lastSync,omitempty"` // last time state was reconciled
+}
+
+// Cluster roles a host can hold in ClusterMembershipState.Role.
+const (
+	ClusterRoleControlPlane = "control-plane"
+	ClusterRoleWorker       = "worker"
+)
+
+// ClusterMembershipState is the persisted record of how the host belongs to
+// the kubeadm cluster. Endpoint and CACertHash identify the cluster: the
+// control-plane endpoint the node talks to and the sha256 hash of the cluster
+// CA's public key, as kubeadm prints them for `kubeadm join`.
+type ClusterMembershipState struct {
+	Role       string `yaml:"role" json:"role"`
+	NodeName   string `yaml:"nodeName" json:"nodeName"`
+	Endpoint   string `yaml:"endpoint" json:"endpoint"`
+	CACertHash string `yaml:"caCertHash" json:"caCertHash"`
+	// Initialized is true on the host that ran `kubeadm init`; joined hosts
+	// leave it false.
+	Initialized bool       `yaml:"initialized,omitempty" json:"initialized,omitempty"`
+	Since       htime.Time `yaml:"since" json:"since"` // when the host initialised or joined the cluster
 }
 
 // HostFirewallState is the persisted record of the operator's last-chosen host
@@ -189,6 +233,12 @@ type BlockNodeState struct {
 	// and upgrade read it to durably skip re-provisioning tc shaping for a block
 	// node that was deliberately installed without it.
 	TrafficShapingDisabled bool `yaml:"trafficShapingDisabled,omitempty" json:"trafficShapingDisabled,omitempty"`
+	// ServicePort and LoadBalancerPool record the port and MetalLB pool the
+	// block node service was published on, so the other instances on the host
+	// can be kept off them. Zero/empty means the defaults
+	// (models.DefaultBlockNodeServicePort, models.DefaultBlockNodeLoadBalancerPool).
+	ServicePort      int    `yaml:"servicePort,omitempty" json:"servicePort,omitempty"`
+	LoadBalancerPool string `yaml:"loadBalancerPool,omitempty" json:"loadBalancerPool,omitempty"`
 	// Shaping records the last-resolved traffic-shaping content (egress NIC, link
 	// rate, per-class overrides) that cannot be recovered from the Helm release or
 	// the live cluster. It is written on install/reconfigure when traffic shaping is
@@ -248,6 +298,10 @@ type ClusterState struct {
 	models.ClusterInfo
 	Created  bool       `yaml:"created" json:"created"`                       // whether the cluster was created by the provisioner
 	LastSync htime.Time `yaml:"lastSync,omitempty" json:"lastSync,omitempty"` // last time state was reconciled
+	// Config is the network layout and MetalLB pools the cluster was
+	// initialised with, so reconfigure and upgrade render the same values. It
+	// is recorded by `kube cluster install` and carried across refreshes.
+	Config *models.ClusterConfig `yaml:"config,omitempty" json:"config,omitempty"`
 }
 
 // TeleportState represents the persisted state of Teleport agents.
@@ -144,6 +144,18 @@ func (s *State) Clone() (*State, error) {
 		return nil, err
 	}
 
+	var bns map[string]BlockNodeState
+	if s.BlockNodes != nil {
+		bns = make(map[string]BlockNodeState, len(s.BlockNodes))
+		for release, bn := range s.BlockNodes {
+			c, err := bn.Clone()
+			if err != nil {
+				return nil, err
+			}
+			bns[release] = *c
+		}
+	}
+
 	return &State{
 		// Envelope fields
 		Hash:      s.Hash,
@@ -157,6 +169,7 @@ func (s *State) Clone() (*State, error) {
 			MachineState:     *ms,
 			ClusterState:     *cs,
 			BlockNodeState:   *bs,
+			BlockNodes:       bns,
 			LastAction:       s.LastAction,
 		},
 	}, nil
@@ -15,6 +15,7 @@ import (
 	"github.com/automa-saga/logx"
 	"github.com/automa-saga/version"
 	"github.com/hashgraph/solo-weaver/pkg/fsx"
+	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/joomcode/errorx"
 	"gopkg.in/yaml.v3"
 	htime "helm.sh/helm/v3/pkg/time"
@@ -162,6 +163,73 @@ func PersistProvisionerVersion(opts ...ManagerOption) error {
 	return sm.Set(s).FlushState()
 }
 
+// PersistSoftwareVersion records in the on-disk state file that version of the
+// host component is installed. component is the MachineState.Software key (the
+// installer name, e.g. "crio") and name the catalog artifact name the
+// installers record with it (e.g. "cri-o").
+//
+// Workflows that replace an installed component's binaries in place (e.g.
+// `kube cluster upgrade`) call it as soon as the binaries land, so the
+// time-of-use checksum checks and the next reality refresh verify them against
+// the version now on disk rather than the one they replaced.
+func PersistSoftwareVersion(component, name, version string, opts ...ManagerOption) error {
+	sm, err := NewStateManager(opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.Refresh(); err != nil && !errorx.IsOfType(err, NotFoundError) {
+		return err
+	}
+
+	s := sm.State()
+	if s.MachineState.Software == nil {
+		s.MachineState.Software = make(map[string]SoftwareState)
+	}
+	sw := s.MachineState.Software[component]
+	sw.Name = name
+	sw.Version = version
+	sw.Installed = true
+	sw.LastSync = htime.Now()
+	s.MachineState.Software[component] = sw
+	return sm.Set(s).FlushState()
+}
+
+// PersistClusterMembership records in the on-disk state file how the host
+// belongs to the kubeadm cluster; a nil m clears the record once the host has
+// left the cluster.
+func PersistClusterMembership(m *ClusterMembershipState, opts ...ManagerOption) error {
+	sm, err := NewStateManager(opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.Refresh(); err != nil && !errorx.IsOfType(err, NotFoundError) {
+		return err
+	}
+
+	s := sm.State()
+	s.MachineState.Cluster = m
+	return sm.Set(s).FlushState()
+}
+
+// PersistClusterConfig records the network layout the cluster was initialised
+// with in ClusterState.Config; a nil cfg clears the record.
+func PersistClusterConfig(cfg *models.ClusterConfig, opts ...ManagerOption) error {
+	sm, err := NewStateManager(opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.Refresh(); err != nil && !errorx.IsOfType(err, NotFoundError) {
+		return err
+	}
+
+	s := sm.State()
+	s.ClusterState.Config = cfg
+	return sm.Set(s).FlushState()
+}
+
 // State returns a copy of the current in-memory state (thread-safe).
 // Returns a value copy so callers cannot mutate the manager's internals
 // through the returned value.
@@ -365,7 +433,7 @@ func (m *stateManager) flushActionHistory() error {
 
 	// Append action history entries.
 	// Do this before writing the state file to ensure history is preserved even if the state file flush fails (since pending actions are cleared on successful flush).
-	actionHistoryFile := filepath.Join(filepath.Dir(snapshot.StateFile), "action_history.yaml")
+	actionHistoryFile := filepath.Join(filepath.Dir(snapshot.StateFile), ActionHistoryFileName)
 	for _, entry := range pendingActions {
 		entryBytes, marshalErr := yaml.Marshal(entry)
 		if marshalErr != nil {
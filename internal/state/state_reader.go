@@ -11,7 +11,9 @@ package state
 import (
 	"os"
 	"path/filepath"
+	"sort"
 
+	"github.com/hashgraph/solo-weaver/pkg/deps"
 	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/joomcode/errorx"
 	"gopkg.in/yaml.v3"
@@ -75,32 +77,37 @@ type PromptDefaultsDoc struct {
 				InClusterPorts  []int    `yaml:"inClusterPorts"`
 			} `yaml:"firewall"`
 		} `yaml:"machineState"`
-		BlockNodeState struct {
-			Name                   string `yaml:"name"`
-			Namespace              string `yaml:"namespace"`
-			ChartVersion           string `yaml:"version"`
-			HistoricRetention      string `yaml:"historicRetention"`
-			RecentRetention        string `yaml:"recentRetention"`
-			PluginPreset           string `yaml:"pluginPreset"`
-			PluginList             string `yaml:"pluginList"`
-			TrafficShapingDisabled bool   `yaml:"trafficShapingDisabled"`
-			Shaping                *struct {
-				EgressInterface string `yaml:"egressInterface"`
-				LinkRate        string `yaml:"linkRate"`
-			} `yaml:"shaping"`
-			Storage struct {
-				BasePath             string `yaml:"basePath"`
-				ArchivePath          string `yaml:"archivePath"`
-				LivePath             string `yaml:"livePath"`
-				LogPath              string `yaml:"logPath"`
-				VerificationPath     string `yaml:"verificationPath"`
-				PluginsPath          string `yaml:"pluginsPath"`
-				ApplicationStatePath string `yaml:"applicationStatePath"`
-			} `yaml:"storage"`
-		} `yaml:"blockNodeState"`
+		BlockNodeState promptBlockNodeDoc            `yaml:"blockNodeState"`
+		BlockNodes     map[string]promptBlockNodeDoc `yaml:"blockNodes"`
 	} `yaml:"state"`
 }
 
+// promptBlockNodeDoc is the prompt-relevant part of one block node's state, as
+// recorded in blockNodeState or in a blockNodes entry.
+type promptBlockNodeDoc struct {
+	Name                   string `yaml:"name"`
+	Namespace              string `yaml:"namespace"`
+	ChartVersion           string `yaml:"version"`
+	HistoricRetention      string `yaml:"historicRetention"`
+	RecentRetention        string `yaml:"recentRetention"`
+	PluginPreset           string `yaml:"pluginPreset"`
+	PluginList             string `yaml:"pluginList"`
+	TrafficShapingDisabled bool   `yaml:"trafficShapingDisabled"`
+	Shaping                *struct {
+		EgressInterface string `yaml:"egressInterface"`
+		LinkRate        string `yaml:"linkRate"`
+	} `yaml:"shaping"`
+	Storage struct {
+		BasePath             string `yaml:"basePath"`
+		ArchivePath          string `yaml:"archivePath"`
+		LivePath             string `yaml:"livePath"`
+		LogPath              string `yaml:"logPath"`
+		VerificationPath     string `yaml:"verificationPath"`
+		PluginsPath          string `yaml:"pluginsPath"`
+		ApplicationStatePath string `yaml:"applicationStatePath"`
+	} `yaml:"storage"`
+}
+
 // SoftwareVersionsDoc is the minimal YAML shape for reading each installed host
 // component's recorded version from machineState.software. The map key (installer
 // key, e.g. "crio") and the nested name (catalog artifact name, e.g. "cri-o") may
@@ -109,13 +116,34 @@ type SoftwareVersionsDoc struct {
 	State struct {
 		MachineState struct {
 			Software map[string]struct {
-				Name    string `yaml:"name"`
-				Version string `yaml:"version"`
+				Name      string `yaml:"name"`
+				Version   string `yaml:"version"`
+				Installed bool   `yaml:"installed"`
 			} `yaml:"software"`
 		} `yaml:"machineState"`
 	} `yaml:"state"`
 }
 
+// ClusterMembershipDoc is the minimal YAML shape for reading the host's
+// cluster membership from machineState.cluster.
+type ClusterMembershipDoc struct {
+	State struct {
+		MachineState struct {
+			Cluster *ClusterMembershipState `yaml:"cluster"`
+		} `yaml:"machineState"`
+	} `yaml:"state"`
+}
+
+// ClusterConfigDoc is the minimal YAML shape for reading the cluster's
+// recorded network layout from clusterState.config.
+type ClusterConfigDoc struct {
+	State struct {
+		ClusterState struct {
+			Config *models.ClusterConfig `yaml:"config"`
+		} `yaml:"clusterState"`
+	} `yaml:"state"`
+}
+
 // ReadProvisionerVersionFromDisk extracts the provisioner version from the on-disk state file
 // without loading the full state into memory. Returns an empty string when no state file exists.
 func ReadProvisionerVersionFromDisk() (string, error) {
@@ -149,6 +177,57 @@ func ReadSoftwareVersionFromDisk(name string) (string, error) {
 	return softwareVersionFromDoc(doc, name), nil
 }
 
+// ReadInstalledSoftwareFromDisk reads the recorded version of every host
+// component marked installed in the on-disk state file, keyed by catalog
+// artifact name. Returns nil when no state file exists.
+func ReadInstalledSoftwareFromDisk() (map[string]string, error) {
+	data, err := readStateFileBytes()
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var doc SoftwareVersionsDoc
+	if err := unmarshalStateDoc(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return installedSoftwareFromDoc(doc), nil
+}
+
+// ReadClusterMembershipFromDisk reads the host's recorded cluster membership
+// from the on-disk state file without loading the full state. Returns nil when
+// the state file or the record is absent.
+func ReadClusterMembershipFromDisk() (*ClusterMembershipState, error) {
+	data, err := readStateFileBytes()
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var doc ClusterMembershipDoc
+	if err := unmarshalStateDoc(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc.State.MachineState.Cluster, nil
+}
+
+// ReadClusterConfigFromDisk reads the network layout recorded for the cluster
+// from the on-disk state file without loading the full state. Returns nil when
+// the state file or the record is absent.
+func ReadClusterConfigFromDisk() (*models.ClusterConfig, error) {
+	data, err := readStateFileBytes()
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var doc ClusterConfigDoc
+	if err := unmarshalStateDoc(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc.State.ClusterState.Config, nil
+}
+
 // softwareVersionFromDoc resolves a component's recorded version, preferring a
 // direct map-key hit and falling back to matching an entry's recorded name (so a
 // lookup by artifact name finds an entry keyed by its installer key). Returns an
@@ -166,6 +245,23 @@ func softwareVersionFromDoc(doc SoftwareVersionsDoc, name string) string {
 	return ""
 }
 
+// installedSoftwareFromDoc maps each installed component of doc to its recorded
+// version, keyed by its recorded name, or by its map key when it has none.
+func installedSoftwareFromDoc(doc SoftwareVersionsDoc) map[string]string {
+	out := map[string]string{}
+	for key, entry := range doc.State.MachineState.Software {
+		if !entry.Installed {
+			continue
+		}
+		name := entry.Name
+		if name == "" {
+			name = key
+		}
+		out[name] = entry.Version
+	}
+	return out
+}
+
 // BlockNodeSummary holds the prompt-relevant fields from BlockNodeState
 // read from the on-disk state file.  HelmReleaseInfo is yaml:",inline"
 // inside BlockNodeState, so its keys (name, namespace, version) live
@@ -206,6 +302,13 @@ type PromptDefaults struct {
 // and BlockNodeInputPrompts run in the same prompt flow.
 // Returns a zero-value struct when no state file exists.
 func ReadPromptDefaultsFromDisk() (PromptDefaults, error) {
+	return ReadBlockNodePromptDefaultsFromDisk("")
+}
+
+// ReadBlockNodePromptDefaultsFromDisk is ReadPromptDefaultsFromDisk with the
+// block node fields taken from the instance deployed as release (see
+// StateRecord.BlockNode); an empty release reads the default instance.
+func ReadBlockNodePromptDefaultsFromDisk(release string) (PromptDefaults, error) {
 	data, err := readStateFileBytes()
 	if err != nil || data == nil {
 		return PromptDefaults{}, err
@@ -229,6 +332,11 @@ func ReadPromptDefaultsFromDisk() (PromptDefaults, error) {
 	}
 
 	bn := doc.State.BlockNodeState
+	if entry, ok := doc.State.BlockNodes[release]; ok {
+		bn = entry
+	} else if release != "" && release != bn.Name && (bn.Name != "" || release != deps.BLOCK_NODE_RELEASE) {
+		bn = promptBlockNodeDoc{}
+	}
 	var egressInterface, linkRate string
 	if bn.Shaping != nil {
 		egressInterface = bn.Shaping.EgressInterface
@@ -260,3 +368,40 @@ func ReadPromptDefaultsFromDisk() (PromptDefaults, error) {
 		},
 	}, nil
 }
+
+// BlockNodeRelease names a block node recorded in the on-disk state file.
+type BlockNodeRelease struct {
+	Release   string
+	Namespace string
+}
+
+// ReadBlockNodeReleasesFromDisk returns every block node recorded in the
+// on-disk state file, sorted by release (see StateRecord.BlockNodeReleases).
+// Returns nil when no state file exists.
+func ReadBlockNodeReleasesFromDisk() ([]BlockNodeRelease, error) {
+	data, err := readStateFileBytes()
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var doc PromptDefaultsDoc
+	if err := unmarshalStateDoc(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return blockNodeReleasesFromDoc(doc), nil
+}
+
+// blockNodeReleasesFromDoc lists the block nodes of doc: the one in
+// blockNodeState, when it has a release, then those in blockNodes.
+func blockNodeReleasesFromDoc(doc PromptDefaultsDoc) []BlockNodeRelease {
+	var out []BlockNodeRelease
+	if bn := doc.State.BlockNodeState; bn.Name != "" {
+		out = append(out, BlockNodeRelease{Release: bn.Name, Namespace: bn.Namespace})
+	}
+	for release, bn := range doc.State.BlockNodes {
+		out = append(out, BlockNodeRelease{Release: release, Namespace: bn.Namespace})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Release < out[j].Release })
+	return out
+}
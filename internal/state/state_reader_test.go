@@ -63,6 +63,86 @@ state:
 	}
 }
 
+func TestReadInstalledSoftware_ListsInstalledComponents(t *testing.T) {
+	data := []byte(`
+state:
+  machineState:
+    software:
+      cilium:
+        name: cilium
+        version: "0.18.7"
+        installed: true
+      crio:
+        name: cri-o
+        version: "1.30.0"
+        installed: true
+      k9s:
+        version: "0.50.9"
+        installed: true
+      teleport:
+        name: teleport
+        version: "18.6.4"
+        installed: false
+`)
+	var doc SoftwareVersionsDoc
+	if err := unmarshalStateDoc(data, &doc); err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	got := installedSoftwareFromDoc(doc)
+	want := map[string]string{"cilium": "0.18.7", "cri-o": "1.30.0", "k9s": "0.50.9"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for name, version := range want {
+		if got[name] != version {
+			t.Fatalf("expected %s version %q, got %q", name, version, got[name])
+		}
+	}
+}
+
+func TestReadBlockNodeReleases_ListsEveryInstance(t *testing.T) {
+	data := []byte(`
+state:
+  blockNodeState:
+    name: block-node
+    namespace: block-node
+  blockNodes:
+    bn-b:
+      name: bn-b
+      namespace: bn-b
+    bn-a:
+      name: bn-a
+      namespace: bn-a-ns
+`)
+	var doc PromptDefaultsDoc
+	if err := unmarshalStateDoc(data, &doc); err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	got := blockNodeReleasesFromDoc(doc)
+	want := []BlockNodeRelease{
+		{Release: "block-node", Namespace: "block-node"},
+		{Release: "bn-a", Namespace: "bn-a-ns"},
+		{Release: "bn-b", Namespace: "bn-b"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d releases, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("release %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	// An uninstalled default instance (no release name) is not listed.
+	var empty PromptDefaultsDoc
+	if err := unmarshalStateDoc([]byte("state:\n  blockNodeState: {}\n"), &empty); err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if got := blockNodeReleasesFromDoc(empty); len(got) != 0 {
+		t.Fatalf("expected no releases, got %v", got)
+	}
+}
+
 func TestReadPromptDefaults_ParsesProfile(t *testing.T) {
 	data := []byte(`
 state:
@@ -0,0 +1,358 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/automa-saga/logx"
+	"github.com/automa-saga/version"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"gopkg.in/yaml.v3"
+)
+
+// IndexFileName is the name of the index at the root of a bundle.
+const IndexFileName = "index.yaml"
+
+// bundleFilePerm keeps the tarball private: it is redacted, but still holds
+// the host's logs and configuration.
+const bundleFilePerm = 0o600
+
+// Index describes a bundle: how it was made and, per collector, every file in
+// it and every source that could not be read.
+type Index struct {
+	CreatedAt          time.Time         `yaml:"createdAt"`
+	Host               string            `yaml:"host"`
+	ProvisionerVersion string            `yaml:"provisionerVersion"`
+	Since              string            `yaml:"since"`
+	MaxSourceBytes     int64             `yaml:"maxSourceBytes"`
+	Collectors         []CollectorResult `yaml:"collectors"`
+}
+
+// CollectorResult is the part of the index one collector produced.
+type CollectorResult struct {
+	Name     string       `yaml:"name"`
+	Duration string       `yaml:"duration"`
+	Error    string       `yaml:"error,omitempty"`
+	Files    []IndexEntry `yaml:"files,omitempty"`
+}
+
+// IndexEntry is one source: the file it was written to, or why it was not.
+type IndexEntry struct {
+	Path   string `yaml:"path,omitempty"`
+	Source string `yaml:"source"`
+	Bytes  int64  `yaml:"bytes"`
+	// OriginalBytes is the size of the source before the size limit, set
+	// when the file is truncated.
+	OriginalBytes int64  `yaml:"originalBytes,omitempty"`
+	Truncated     bool   `yaml:"truncated,omitempty"`
+	Redactions    int    `yaml:"redactions,omitempty"`
+	Error         string `yaml:"error,omitempty"`
+}
+
+// Bundle is what a collector adds its files to.
+type Bundle struct {
+	opts     Options
+	now      time.Time
+	root     string
+	tw       *tar.Writer
+	redactor *Redactor
+
+	// current is the result of the collector running; its files go to its
+	// directory.
+	current *CollectorResult
+	// err is the first write error; once set the bundle is abandoned.
+	err error
+}
+
+// runCommand runs a collected command; a var so tests can fake it.
+var runCommand = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, bin, args...).CombinedOutput()
+}
+
+// Since returns the time window of the bundle; zero keeps everything.
+func (b *Bundle) Since() time.Duration { return b.opts.Since }
+
+// Cutoff returns the oldest time the bundle keeps; the zero time when it keeps
+// everything.
+func (b *Bundle) Cutoff() time.Time {
+	if b.opts.Since == 0 {
+		return time.Time{}
+	}
+	return b.now.Add(-b.opts.Since)
+}
+
+// MaxSourceBytes returns the size limit of each file.
+func (b *Bundle) MaxSourceBytes() int64 { return b.opts.MaxSourceBytes }
+
+// AddBytes adds data as name, under the running collector's directory.
+func (b *Bundle) AddBytes(name, source string, data []byte) {
+	b.add(name, source, data, int64(len(data)))
+}
+
+// AddYAML adds v rendered as YAML. A value that implements models.Redactable
+// is redacted through it first.
+func (b *Bundle) AddYAML(name, source string, v any) {
+	data, err := yaml.Marshal(redactValue(v))
+	if err != nil {
+		b.AddError(name, source, errorx.InternalError.Wrap(err, "failed to render %s", source))
+		return
+	}
+	b.AddBytes(name, source, data)
+}
+
+// AddFile adds the file at file as name. A .gz file is added decompressed. A
+// file larger than the size limit keeps its last bytes from the start of a
+// line. A missing file is recorded as such.
+func (b *Bundle) AddFile(name, file string) {
+	data, size, err := readTail(file, b.opts.MaxSourceBytes)
+	if err != nil {
+		b.AddError(name, file, err)
+		return
+	}
+	b.add(name, file, data, size)
+}
+
+// AddCommand runs bin (an absolute path) with args and adds its combined
+// output as name. A failing command is recorded with its output.
+func (b *Bundle) AddCommand(ctx context.Context, name, bin string, args ...string) {
+	source := strings.Join(append([]string{bin}, args...), " ")
+	out, err := runCommand(ctx, bin, args...)
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		redacted, _ := b.redactor.Redact([]byte(msg))
+		b.record(IndexEntry{Source: source, Error: string(redacted)})
+		return
+	}
+	b.AddBytes(name, source, out)
+}
+
+// AddError records that source could not be collected as name.
+func (b *Bundle) AddError(name, source string, err error) {
+	msg := err.Error()
+	if errors.Is(err, fs.ErrNotExist) {
+		msg = "not found"
+	}
+	redacted, _ := b.redactor.Redact([]byte(msg))
+	b.record(IndexEntry{Source: source, Error: string(redacted)})
+	logx.As().Debug().Str("collector", b.current.Name).Str("file", name).Err(err).Msg("Support bundle source skipped")
+}
+
+func (b *Bundle) add(name, source string, data []byte, originalSize int64) {
+	if b.err != nil {
+		return
+	}
+	data, redactions := b.redactor.Redact(data)
+	entry := IndexEntry{
+		Path:       path.Join(b.current.Name, name),
+		Source:     source,
+		Redactions: redactions,
+		Truncated:  originalSize > b.opts.MaxSourceBytes,
+	}
+	if int64(len(data)) > b.opts.MaxSourceBytes {
+		data = dropPartialLine(data[int64(len(data))-b.opts.MaxSourceBytes:])
+		entry.Truncated = true
+	}
+	if entry.Truncated {
+		entry.OriginalBytes = originalSize
+	}
+	entry.Bytes = int64(len(data))
+
+	if err := b.writeFile(entry.Path, data); err != nil {
+		b.err = err
+		return
+	}
+	b.record(entry)
+}
+
+func (b *Bundle) record(entry IndexEntry) {
+	b.current.Files = append(b.current.Files, entry)
+}
+
+func (b *Bundle) writeFile(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    path.Join(b.root, name),
+		Mode:    models.DefaultFilePerm,
+		Size:    int64(len(data)),
+		ModTime: b.now,
+	}
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to write %s to the support bundle", name)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to write %s to the support bundle", name)
+	}
+	return nil
+}
+
+// Create runs the collectors and writes their files and the index to a new
+// support-bundle-<host>-<timestamp>.tar.gz in opts.Dir. Collectors run one
+// after the other, each bounded by opts.CollectorTimeout; a failing collector
+// is recorded in the index. It returns the tarball's path and the index.
+func Create(ctx context.Context, opts Options, collectors []Collector) (string, *Index, error) {
+	if err := opts.Validate(); err != nil {
+		return "", nil, err
+	}
+	opts = opts.withDefaults()
+
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	now := time.Now().UTC()
+	root := fmt.Sprintf("support-bundle-%s-%s", host, now.Format("20060102-150405"))
+
+	if err := os.MkdirAll(opts.Dir, models.DefaultDirOrExecPerm); err != nil {
+		return "", nil, errorx.ExternalError.Wrap(err, "failed to create support bundle directory %s", opts.Dir)
+	}
+	bundlePath := filepath.Join(opts.Dir, root+".tar.gz")
+	f, err := os.OpenFile(bundlePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, bundleFilePerm)
+	if err != nil {
+		return "", nil, errorx.ExternalError.Wrap(err, "failed to create support bundle %s", bundlePath)
+	}
+	gz := gzip.NewWriter(f)
+	b := &Bundle{
+		opts:     opts,
+		now:      now,
+		root:     root,
+		tw:       tar.NewWriter(gz),
+		redactor: NewRedactor(opts.Secrets...),
+	}
+
+	index := &Index{
+		CreatedAt:          now,
+		Host:               host,
+		ProvisionerVersion: version.Get().Version,
+		Since:              "all",
+		MaxSourceBytes:     opts.MaxSourceBytes,
+	}
+	if opts.Since > 0 {
+		index.Since = opts.Since.String()
+	}
+
+	for _, c := range collectors {
+		result := CollectorResult{Name: c.Name()}
+		b.current = &result
+		start := time.Now()
+		cctx, cancel := context.WithTimeout(ctx, opts.CollectorTimeout)
+		if err := c.Collect(cctx, b); err != nil {
+			redacted, _ := b.redactor.Redact([]byte(err.Error()))
+			result.Error = string(redacted)
+		}
+		cancel()
+		result.Duration = time.Since(start).Round(time.Millisecond).String()
+		index.Collectors = append(index.Collectors, result)
+		if b.err != nil {
+			break
+		}
+	}
+
+	if b.err == nil {
+		data, err := yaml.Marshal(index)
+		if err != nil {
+			b.err = errorx.InternalError.Wrap(err, "failed to render the support bundle index")
+		} else {
+			b.err = b.writeFile(IndexFileName, data)
+		}
+	}
+	for _, closer := range []io.Closer{b.tw, gz, f} {
+		if err := closer.Close(); err != nil && b.err == nil {
+			b.err = errorx.ExternalError.Wrap(err, "failed to write support bundle %s", bundlePath)
+		}
+	}
+	if b.err != nil {
+		_ = os.Remove(bundlePath)
+		return "", nil, b.err
+	}
+	return bundlePath, index, nil
+}
+
+// readTail returns the last limit bytes of file (decompressed when it ends in
+// .gz), starting at a line, and the size of the whole content.
+func readTail(file string, limit int64) ([]byte, int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+	if strings.HasSuffix(file, ".gz") {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, 0, errorx.IllegalFormat.Wrap(err, "failed to decompress %s", file)
+		}
+		defer func() { _ = gr.Close() }()
+		r = gr
+	} else if info, err := f.Stat(); err == nil && info.Size() > limit {
+		// Plain files are seeked to the tail instead of read whole.
+		if _, err := f.Seek(info.Size()-limit, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, 0, err
+		}
+		return dropPartialLine(data), info.Size(), nil
+	}
+
+	tail := &tailBuffer{limit: limit}
+	n, err := io.Copy(tail, r)
+	if err != nil {
+		return nil, 0, errorx.ExternalError.Wrap(err, "failed to read %s", file)
+	}
+	data := tail.Bytes()
+	if n > limit {
+		data = dropPartialLine(data)
+	}
+	return data, n, nil
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	limit int64
+	buf   []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	// Compact only once the buffer holds twice the limit, so the copy is
+	// amortised over many writes.
+	if int64(len(t.buf)) > 2*t.limit {
+		t.buf = append(t.buf[:0], t.buf[int64(len(t.buf))-t.limit:]...)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) Bytes() []byte {
+	if int64(len(t.buf)) > t.limit {
+		return t.buf[int64(len(t.buf))-t.limit:]
+	}
+	return t.buf
+}
+
+// dropPartialLine drops the bytes before the first newline of a truncated
+// tail, so the kept content starts at a line.
+func dropPartialLine(data []byte) []byte {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 && i < len(data)-1 {
+		return data[i+1:]
+	}
+	return data
+}
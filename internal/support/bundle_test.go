@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// fakeCollector runs collect as its Collect.
+type fakeCollector struct {
+	name    string
+	collect func(ctx context.Context, b *Bundle) error
+}
+
+func (f fakeCollector) Name() string { return f.name }
+
+func (f fakeCollector) Collect(ctx context.Context, b *Bundle) error { return f.collect(ctx, b) }
+
+// readBundle returns the files of the tarball at path, keyed by their path
+// below the bundle root.
+func readBundle(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := map[string]string{}
+	root := strings.TrimSuffix(filepath.Base(path), ".tar.gz")
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(hdr.Name, root+"/"), "entry %s outside the bundle root", hdr.Name)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[strings.TrimPrefix(hdr.Name, root+"/")] = string(data)
+	}
+	return files
+}
+
+func TestCreate_WritesFilesAndIndex(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "app.log")
+	require.NoError(t, os.WriteFile(logFile, []byte("line 1\nline 2\n"), 0o644))
+
+	collectors := []Collector{
+		fakeCollector{name: "first", collect: func(ctx context.Context, b *Bundle) error {
+			b.AddBytes("notes.txt", "notes", []byte("token: abcdef123\n"))
+			b.AddFile("app.log", logFile)
+			b.AddFile("missing.log", filepath.Join(dir, "missing.log"))
+			b.AddYAML("config.yaml", "config", models.Config{
+				Teleport: models.TeleportConfig{NodeAgentToken: "0123456789abcdef"},
+			})
+			return nil
+		}},
+		fakeCollector{name: "broken", collect: func(ctx context.Context, b *Bundle) error {
+			return errors.New("cluster unreachable; password=hunter22")
+		}},
+	}
+
+	out := filepath.Join(dir, "out")
+	path, index, err := Create(context.Background(), Options{Dir: out, Since: time.Hour}, collectors)
+	require.NoError(t, err)
+	assert.Equal(t, out, filepath.Dir(path))
+	assert.True(t, strings.HasPrefix(filepath.Base(path), "support-bundle-"))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(bundleFilePerm), info.Mode().Perm())
+
+	files := readBundle(t, path)
+	assert.Equal(t, "token: ***\n", files["first/notes.txt"])
+	assert.Equal(t, "line 1\nline 2\n", files["first/app.log"])
+	assert.Contains(t, files["first/config.yaml"], "nodeAgentToken: '***'")
+	assert.NotContains(t, files["first/config.yaml"], "0123456789abcdef")
+	require.Contains(t, files, IndexFileName)
+
+	var onDisk Index
+	require.NoError(t, yaml.Unmarshal([]byte(files[IndexFileName]), &onDisk))
+	assert.Equal(t, "1h0m0s", onDisk.Since)
+	assert.Equal(t, int64(DefaultMaxSourceBytes), onDisk.MaxSourceBytes)
+	require.Len(t, onDisk.Collectors, 2)
+
+	first := onDisk.Collectors[0]
+	assert.Equal(t, "first", first.Name)
+	assert.Empty(t, first.Error)
+	require.Len(t, first.Files, 4)
+	assert.Equal(t, "first/notes.txt", first.Files[0].Path)
+	assert.Equal(t, 1, first.Files[0].Redactions)
+	assert.Equal(t, logFile, first.Files[1].Source)
+	assert.Empty(t, first.Files[2].Path)
+	assert.Equal(t, "not found", first.Files[2].Error)
+
+	broken := onDisk.Collectors[1]
+	assert.Equal(t, "cluster unreachable; password=***", broken.Error)
+	assert.Equal(t, index.Collectors[1].Error, broken.Error)
+}
+
+func TestCreate_TruncatesToNewestBytes(t *testing.T) {
+	dir := t.TempDir()
+	var log bytes.Buffer
+	for i := 0; i < 100; i++ {
+		log.WriteString("0123456789 line\n")
+	}
+	log.WriteString("newest\n")
+	plain := filepath.Join(dir, "big.log")
+	require.NoError(t, os.WriteFile(plain, log.Bytes(), 0o644))
+
+	var zipped bytes.Buffer
+	gz := gzip.NewWriter(&zipped)
+	_, _ = gz.Write(log.Bytes())
+	require.NoError(t, gz.Close())
+	compressed := filepath.Join(dir, "big.log.1.gz")
+	require.NoError(t, os.WriteFile(compressed, zipped.Bytes(), 0o644))
+
+	collectors := []Collector{fakeCollector{name: "logs", collect: func(ctx context.Context, b *Bundle) error {
+		b.AddFile("plain.log", plain)
+		b.AddFile("rotated.log", compressed)
+		b.AddBytes("bytes.log", "bytes", log.Bytes())
+		return nil
+	}}}
+
+	path, index, err := Create(context.Background(), Options{Dir: dir, MaxSourceBytes: 64}, collectors)
+	require.NoError(t, err)
+	files := readBundle(t, path)
+
+	for _, name := range []string{"logs/plain.log", "logs/rotated.log", "logs/bytes.log"} {
+		got := files[name]
+		assert.LessOrEqual(t, len(got), 64, name)
+		assert.True(t, strings.HasSuffix(got, "newest\n"), "%s keeps the newest line: %q", name, got)
+		assert.True(t, strings.HasPrefix(got, "0123456789 line\n"), "%s starts at a line: %q", name, got)
+	}
+	for _, f := range index.Collectors[0].Files {
+		assert.True(t, f.Truncated, f.Path)
+		assert.Equal(t, int64(log.Len()), f.OriginalBytes, f.Path)
+	}
+}
+
+func TestCreate_RecordsFailedCommand(t *testing.T) {
+	orig := runCommand
+	defer func() { runCommand = orig }()
+	runCommand = func(ctx context.Context, bin string, args ...string) ([]byte, error) {
+		if bin == "/sbin/ok" {
+			return []byte("ok output\n"), nil
+		}
+		return []byte("Operation not permitted\n"), errors.New("exit status 1")
+	}
+
+	collectors := []Collector{fakeCollector{name: "network", collect: func(ctx context.Context, b *Bundle) error {
+		b.AddCommand(ctx, "ok.txt", "/sbin/ok", "show")
+		b.AddCommand(ctx, "denied.txt", "/sbin/denied", "list", "ruleset")
+		return nil
+	}}}
+
+	path, index, err := Create(context.Background(), Options{Dir: t.TempDir()}, collectors)
+	require.NoError(t, err)
+	files := readBundle(t, path)
+	assert.Equal(t, "ok output\n", files["network/ok.txt"])
+	assert.NotContains(t, files, "network/denied.txt")
+
+	entries := index.Collectors[0].Files
+	require.Len(t, entries, 2)
+	assert.Equal(t, "/sbin/ok show", entries[0].Source)
+	assert.Equal(t, "/sbin/denied list ruleset", entries[1].Source)
+	assert.Equal(t, "Operation not permitted", entries[1].Error)
+}
+
+func TestCreate_BoundsCollectorsByTimeout(t *testing.T) {
+	collectors := []Collector{fakeCollector{name: "slow", collect: func(ctx context.Context, b *Bundle) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}}
+
+	_, index, err := Create(context.Background(), Options{Dir: t.TempDir(), CollectorTimeout: 10 * time.Millisecond}, collectors)
+	require.NoError(t, err)
+	assert.Contains(t, index.Collectors[0].Error, "deadline exceeded")
+}
+
+func TestOptions_Validate(t *testing.T) {
+	assert.Error(t, Options{}.Validate())
+	assert.Error(t, Options{Dir: "/tmp", Since: -time.Hour}.Validate())
+	assert.NoError(t, Options{Dir: "/tmp"}.Validate())
+}
+
+func TestHistorySince(t *testing.T) {
+	data := []byte(`---
+intent:
+    action: install
+    target: blocknode
+timestamp: 2026-10-16T10:00:00Z
+---
+intent:
+    action: upgrade
+    target: blocknode
+timestamp: 2026-10-18T10:00:00Z
+`)
+	got, err := historySince(data, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Contains(t, string(got), "action: upgrade")
+	assert.NotContains(t, string(got), "action: install")
+
+	all, err := historySince(data, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(all), "---\n"))
+
+	_, err = historySince([]byte("- : :\n\t"), time.Time{})
+	assert.Error(t, err)
+}
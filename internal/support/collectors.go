@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/solo-weaver/internal/network/shape"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/sysctl"
+	"github.com/hashgraph/solo-weaver/pkg/config"
+	"github.com/hashgraph/solo-weaver/pkg/hardware"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+	"gopkg.in/yaml.v3"
+)
+
+// Absolute paths of the host tools the network collector runs; never looked up
+// on PATH (see docs/dev/security-model.md).
+const tcBin = "/sbin/tc"
+
+var nftBinCandidates = []string{"/usr/sbin/nft", "/sbin/nft", "/usr/bin/nft"}
+
+// sysClassNet lists the host's network devices; a var so tests can point it
+// at a fake tree.
+var sysClassNet = "/sys/class/net"
+
+// ciliumVethPrefix is the prefix of the host side of Cilium's pod veths, which
+// carry the block node's ingress shaping.
+const ciliumVethPrefix = "lxc"
+
+// logsCollector collects solo-provisioner's and the daemon's log files,
+// rotated ones included, that were written within the time window.
+type logsCollector struct {
+	dir string
+}
+
+// NewLogsCollector returns the collector of the solo-provisioner logs.
+func NewLogsCollector() Collector {
+	return &logsCollector{dir: models.Paths().LogsDir}
+}
+
+func (c *logsCollector) Name() string { return "logs" }
+
+func (c *logsCollector) Collect(_ context.Context, b *Bundle) error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to read log directory %s", c.dir)
+	}
+	cutoff := b.Cutoff()
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "solo-provisioner") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.ModTime().Before(cutoff) {
+			continue
+		}
+		b.AddFile(strings.TrimSuffix(e.Name(), ".gz"), filepath.Join(c.dir, e.Name()))
+	}
+	return nil
+}
+
+// stateCollector collects the state file and the action history entries
+// recorded within the time window.
+type stateCollector struct {
+	dir string
+}
+
+// NewStateCollector returns the collector of the state file and the action
+// history.
+func NewStateCollector() Collector {
+	return &stateCollector{dir: models.Paths().StateDir}
+}
+
+func (c *stateCollector) Name() string { return "state" }
+
+func (c *stateCollector) Collect(_ context.Context, b *Bundle) error {
+	b.AddFile(state.StateFileName, filepath.Join(c.dir, state.StateFileName))
+
+	historyFile := filepath.Join(c.dir, state.ActionHistoryFileName)
+	data, err := os.ReadFile(historyFile)
+	if err != nil {
+		b.AddError(state.ActionHistoryFileName, historyFile, err)
+		return nil
+	}
+	entries, err := historySince(data, b.Cutoff())
+	if err != nil {
+		b.AddError(state.ActionHistoryFileName, historyFile, err)
+		return nil
+	}
+	b.AddBytes(state.ActionHistoryFileName, historyFile, entries)
+	return nil
+}
+
+// historySince returns the documents of an action history file whose
+// timestamp is not before cutoff, in file order.
+func historySince(data []byte, cutoff time.Time) ([]byte, error) {
+	var out bytes.Buffer
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, errorx.IllegalFormat.Wrap(err, "failed to parse action history")
+		}
+		var entry struct {
+			Timestamp time.Time `yaml:"timestamp"`
+		}
+		if err := doc.Decode(&entry); err != nil || entry.Timestamp.Before(cutoff) {
+			continue
+		}
+		b, err := yaml.Marshal(&doc)
+		if err != nil {
+			return nil, errorx.InternalError.Wrap(err, "failed to render action history entry")
+		}
+		out.WriteString("---\n")
+		out.Write(b)
+	}
+	return out.Bytes(), nil
+}
+
+// configCollector collects the effective configuration, redacted.
+type configCollector struct{}
+
+// NewConfigCollector returns the collector of the effective configuration.
+func NewConfigCollector() Collector { return configCollector{} }
+
+func (configCollector) Name() string { return "config" }
+
+func (configCollector) Collect(_ context.Context, b *Bundle) error {
+	b.AddYAML("config.yaml", "effective configuration", config.Get())
+	return nil
+}
+
+// daemonCollector collects the daemon's /status and its configuration file.
+type daemonCollector struct {
+	sockPath   string
+	configPath string
+}
+
+// NewDaemonCollector returns the collector of the solo-provisioner-daemon
+// status.
+func NewDaemonCollector() Collector {
+	paths := models.Paths()
+	return &daemonCollector{sockPath: paths.DaemonSockPath, configPath: paths.DaemonConfigPath}
+}
+
+func (c *daemonCollector) Name() string { return "daemon" }
+
+func (c *daemonCollector) Collect(ctx context.Context, b *Bundle) error {
+	b.AddFile("daemon.yaml", c.configPath)
+
+	source := "GET /status on " + c.sockPath
+	status, err := fetchDaemonStatus(ctx, c.sockPath)
+	if err != nil {
+		b.AddError("status.json", source, err)
+		return nil
+	}
+	b.AddBytes("status.json", source, status)
+	return nil
+}
+
+// fetchDaemonStatus returns the raw body of the daemon's GET /status.
+func fetchDaemonStatus(ctx context.Context, sockPath string) ([]byte, error) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", sockPath)
+			},
+			Proxy: nil,
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://local/status", nil)
+	if err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to build daemon status request")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "daemon status endpoint unavailable")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errorx.ExternalError.Wrap(err, "failed to read daemon status")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorx.ExternalError.New("daemon status returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// networkCollector collects the nftables ruleset and the tc qdiscs and
+// classes of the egress NIC and the pod veths.
+type networkCollector struct{}
+
+// NewNetworkCollector returns the collector of the host firewall and traffic
+// shaping state.
+func NewNetworkCollector() Collector { return networkCollector{} }
+
+func (networkCollector) Name() string { return "network" }
+
+func (networkCollector) Collect(ctx context.Context, b *Bundle) error {
+	nftBin := nftBinCandidates[0]
+	for _, c := range nftBinCandidates {
+		if _, err := os.Stat(c); err == nil {
+			nftBin = c
+			break
+		}
+	}
+	b.AddCommand(ctx, "nft-ruleset.txt", nftBin, "list", "ruleset")
+	b.AddCommand(ctx, "tc-qdisc.txt", tcBin, "-s", "qdisc", "show")
+
+	var devices []string
+	if egress, err := shape.DetectEgressInterface(); err == nil {
+		devices = append(devices, egress)
+	} else {
+		b.AddError("tc-class-egress.txt", "egress interface", err)
+	}
+	veths, err := podVeths()
+	if err != nil {
+		b.AddError("tc-class-veths.txt", sysClassNet, err)
+	}
+	devices = append(devices, veths...)
+	for _, dev := range devices {
+		b.AddCommand(ctx, "tc-class-"+dev+".txt", tcBin, "-s", "class", "show", "dev", dev)
+	}
+	return nil
+}
+
+// podVeths returns the host-side pod veths, sorted.
+func podVeths() ([]string, error) {
+	entries, err := os.ReadDir(sysClassNet)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ciliumVethPrefix) {
+			out = append(out, e.Name())
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// hostCollector collects the hardware profile, the sysctl settings
+// solo-provisioner manages and the mounts.
+type hostCollector struct{}
+
+// NewHostCollector returns the collector of the host's hardware, sysctl and
+// mount state.
+func NewHostCollector() Collector { return hostCollector{} }
+
+func (hostCollector) Name() string { return "host" }
+
+// sysctlSetting is a managed sysctl key's value against the one
+// solo-provisioner configures.
+type sysctlSetting struct {
+	Desired string `yaml:"desired"`
+	Current string `yaml:"current"`
+}
+
+func (hostCollector) Collect(_ context.Context, b *Bundle) error {
+	hp := hardware.GetHostProfile()
+	b.AddYAML("hardware.yaml", "host hardware profile", map[string]any{
+		"osVendor":          hp.GetOSVendor(),
+		"osVersion":         hp.GetOSVersion(),
+		"cpuCores":          hp.GetCPUCores(),
+		"totalMemoryGB":     hp.GetTotalMemoryGB(),
+		"availableMemoryGB": hp.GetAvailableMemoryGB(),
+		"totalStorageGB":    hp.GetTotalStorageGB(),
+		"ssdStorageGB":      hp.GetSSDStorageGB(),
+		"hddStorageGB":      hp.GetHDDStorageGB(),
+	})
+
+	if settings, err := sysctlSettings(); err != nil {
+		b.AddError("sysctl.yaml", "sysctl", err)
+	} else {
+		b.AddYAML("sysctl.yaml", "sysctl settings managed by solo-provisioner", settings)
+	}
+
+	b.AddFile("mountinfo", "/proc/self/mountinfo")
+	b.AddFile("fstab", "/etc/fstab")
+	return nil
+}
+
+func sysctlSettings() (map[string]sysctlSetting, error) {
+	desired, err := sysctl.DesiredCandidateSettings()
+	if err != nil {
+		return nil, err
+	}
+	current, err := sysctl.CurrentCandidateSettings()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]sysctlSetting, len(desired))
+	for k, v := range desired {
+		out[k] = sysctlSetting{Desired: v, Current: current[k]}
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// redactedValue replaces every secret, as models.Redactable implementations
+// mask theirs.
+const redactedValue = "***"
+
+// minSecretLen keeps short literal secrets from masking unrelated text: a
+// two-character "secret" would redact half the bundle.
+const minSecretLen = 4
+
+var (
+	// secretAssignment matches a value assigned to a key that names a secret,
+	// in YAML (`password: x`), JSON (`"token": "x"`), env (`LOKI_PASSWORD_OPS=x`)
+	// and flag (`--token=x`) form. Group 1 is everything up to the value.
+	secretAssignment = regexp.MustCompile(
+		`(?i)([\w.-]*(?:password|passwd|secret|token|api[_-]?key|key-data|private[_-]?key)[\w.-]*["']?\s*[:=]\s*["']?)([^\s"',}&]+)`)
+
+	// bearerToken matches an HTTP bearer credential.
+	bearerToken = regexp.MustCompile(`(?i)(bearer\s+)([A-Za-z0-9._~+/=-]+)`)
+
+	// privateKeyBlock matches a PEM private key.
+	privateKeyBlock = regexp.MustCompile(
+		`-----BEGIN ([A-Z ]*)PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// Redactor masks secrets in the files of a bundle: known literal values, and
+// values of keys that name a secret (passwords, tokens, keys) whatever they are.
+type Redactor struct {
+	secrets [][]byte
+}
+
+// NewRedactor returns a redactor that also masks the given literal values.
+// Values shorter than four characters are ignored.
+func NewRedactor(secrets ...string) *Redactor {
+	r := &Redactor{}
+	for _, s := range secrets {
+		if len(s) >= minSecretLen && s != redactedValue {
+			r.secrets = append(r.secrets, []byte(s))
+		}
+	}
+	// Longest first, so a secret containing another is masked whole.
+	sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	return r
+}
+
+// Redact returns data with its secrets masked and the number of values masked.
+func (r *Redactor) Redact(data []byte) ([]byte, int) {
+	count := 0
+	for _, s := range r.secrets {
+		if n := bytes.Count(data, s); n > 0 {
+			count += n
+			data = bytes.ReplaceAll(data, s, []byte(redactedValue))
+		}
+	}
+
+	data = privateKeyBlock.ReplaceAllFunc(data, func(m []byte) []byte {
+		count++
+		kind := privateKeyBlock.FindSubmatch(m)[1]
+		return []byte("-----BEGIN " + string(kind) + "PRIVATE KEY-----\n" + redactedValue +
+			"\n-----END " + string(kind) + "PRIVATE KEY-----")
+	})
+
+	for _, re := range []*regexp.Regexp{secretAssignment, bearerToken} {
+		data = re.ReplaceAllFunc(data, func(m []byte) []byte {
+			sub := re.FindSubmatch(m)
+			if string(sub[2]) == redactedValue {
+				return m
+			}
+			count++
+			return append(append([]byte{}, sub[1]...), redactedValue...)
+		})
+	}
+	return data, count
+}
+
+// redactValue returns v.Redacted() when v implements models.Redactable for its
+// own type, as models.Config and models.UserInputs do, and v otherwise.
+func redactValue(v any) any {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return v
+	}
+	m := rv.MethodByName("Redacted")
+	if !m.IsValid() {
+		return v
+	}
+	mt := m.Type()
+	if mt.NumIn() != 0 || mt.NumOut() != 1 || mt.Out(0) != rv.Type() {
+		return v
+	}
+	return m.Call(nil)[0].Interface()
+}
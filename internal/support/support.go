@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package support builds support bundles: one redacted, timestamped tarball of
+// everything it takes to troubleshoot a host, with an index file describing
+// each file in it.
+//
+// What goes into a bundle is decided by collectors. Every subsystem that has
+// something worth collecting implements Collector and registers it at startup
+// (see RegisterSupportCollectors in cmd/cli/commands/root.go):
+//
+//	type myCollector struct{}
+//
+//	func (myCollector) Name() string { return "my-subsystem" }
+//	func (myCollector) Collect(ctx context.Context, b *support.Bundle) error {
+//		b.AddCommand(ctx, "status.txt", "/usr/bin/my-tool", "status")
+//		return nil
+//	}
+//
+//	support.Register(myCollector{})
+//
+// Collectors never write to the tarball directly: Bundle redacts every file,
+// applies the per-source size limit and records the file in the index.
+package support
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joomcode/errorx"
+)
+
+// Defaults of Options.
+const (
+	DefaultSince            = 24 * time.Hour
+	DefaultMaxSourceBytes   = 10 << 20
+	DefaultCollectorTimeout = 2 * time.Minute
+)
+
+// Collector gathers one subsystem's share of a support bundle.
+type Collector interface {
+	// Name is the collector's directory in the bundle and its key in the
+	// index. Use lowercase letters, digits and '-'.
+	Name() string
+
+	// Collect adds the collector's files to the bundle. A source that cannot
+	// be read should be recorded with Bundle.AddError so the rest still are;
+	// a returned error is recorded in the index and does not fail the bundle.
+	Collect(ctx context.Context, b *Bundle) error
+}
+
+// Global registry of collectors, in registration order.
+var (
+	registry []Collector
+	mu       sync.RWMutex
+)
+
+// Register adds a collector to the global registry. Collectors run in the
+// order they are registered.
+func Register(c Collector) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry = append(registry, c)
+}
+
+// Collectors returns the registered collectors.
+func Collectors() []Collector {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Collector, len(registry))
+	copy(out, registry)
+	return out
+}
+
+// Options control what a bundle keeps.
+type Options struct {
+	// Dir is the directory the tarball is written to.
+	Dir string
+	// Since is the time window: log lines, log files and action history
+	// entries older than this are left out. Zero keeps everything.
+	Since time.Duration
+	// MaxSourceBytes caps each file of the bundle; a larger source keeps its
+	// newest (last) bytes. Zero or negative means DefaultMaxSourceBytes.
+	MaxSourceBytes int64
+	// CollectorTimeout bounds each collector. Zero means
+	// DefaultCollectorTimeout.
+	CollectorTimeout time.Duration
+	// Secrets are literal values masked wherever they appear, on top of the
+	// patterns the redactor always masks.
+	Secrets []string
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxSourceBytes <= 0 {
+		o.MaxSourceBytes = DefaultMaxSourceBytes
+	}
+	if o.CollectorTimeout <= 0 {
+		o.CollectorTimeout = DefaultCollectorTimeout
+	}
+	return o
+}
+
+// Validate checks the options.
+func (o Options) Validate() error {
+	if o.Dir == "" {
+		return errorx.IllegalArgument.New("support bundle directory is required")
+	}
+	if o.Since < 0 {
+		return errorx.IllegalArgument.New("support bundle time window must not be negative, got %s", o.Since)
+	}
+	return nil
+}
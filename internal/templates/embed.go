@@ -15,11 +15,49 @@ type KubeadmInitData struct {
 	MachineIP          string
 	Hostname           string
 	KubernetesVersion  string
+	ClusterName        string
+	DNSDomain          string
+	ServiceSubnet      string
 	PodSubnet          string
+	NodeCIDRMaskSize   int
 }
 
+// KubeadmJoinData is rendered into the kubeadm JoinConfiguration a host joins
+// the cluster with. CertificateKey is only set for a control-plane join.
+type KubeadmJoinData struct {
+	SandboxDir     string
+	MachineIP      string
+	Hostname       string
+	Endpoint       string
+	Token          string
+	CACertHash     string
+	ControlPlane   bool
+	CertificateKey string
+}
+
+// MetallbData is rendered into the MetalLB custom resources: an
+// IPAddressPool per pool, an L2Advertisement over L2Pools and a
+// BGPAdvertisement over BGPPools announced to BGPPeers. Pool addresses are
+// final, i.e. the machine IP is already substituted.
 type MetallbData struct {
-	MachineIP string
+	Pools    []MetallbPool
+	L2Pools  []string
+	BGPPools []string
+	BGPPeers []MetallbBGPPeer
+}
+
+// MetallbPool is a MetalLB IPAddressPool.
+type MetallbPool struct {
+	Name      string
+	Addresses []string
+}
+
+// MetallbBGPPeer is a MetalLB BGPPeer.
+type MetallbBGPPeer struct {
+	Name        string
+	PeerAddress string
+	PeerASN     uint32
+	MyASN       uint32
 }
 
 // AlloyRemote represents a single remote endpoint for Prometheus or Loki.
@@ -95,6 +95,11 @@ func TestRender_KubeadmInitYAML(t *testing.T) {
 		MachineIP:          "1.2.3.4",
 		Hostname:           "testnode",
 		KubernetesVersion:  "v1.29.0",
+		ClusterName:        "site-a",
+		DNSDomain:          "cluster.site-a",
+		ServiceSubnet:      "172.20.0.0/16",
+		PodSubnet:          "172.24.0.0/14",
+		NodeCIDRMaskSize:   26,
 	}
 
 	// Use the actual Render function if it wraps text/template
@@ -114,4 +119,53 @@ func TestRender_KubeadmInitYAML(t *testing.T) {
 		!strings.Contains(rendered, data.KubernetesVersion) {
 		t.Errorf("expected values not found in rendered output")
 	}
+	for _, want := range []string{
+		"clusterName: site-a",
+		"dnsDomain: cluster.site-a",
+		"serviceSubnet: 172.20.0.0/16",
+		"podSubnet: 172.24.0.0/14",
+		`value: "26"`,
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("expected %q in rendered output", want)
+		}
+	}
+}
+
+func TestRender_MetallbYAML(t *testing.T) {
+	data := MetallbData{
+		Pools: []MetallbPool{
+			{Name: "private", Addresses: []string{"192.168.50.0/24"}},
+			{Name: "public", Addresses: []string{"203.0.113.16/28", "203.0.113.40-203.0.113.45"}},
+		},
+		L2Pools:  []string{"private"},
+		BGPPools: []string{"public"},
+		BGPPeers: []MetallbBGPPeer{{Name: "tor1", PeerAddress: "10.10.0.1", PeerASN: 64512, MyASN: 64513}},
+	}
+
+	rendered, err := Render("files/metallb/metallb.yaml", data)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{
+		"kind: IPAddressPool\nmetadata:\n  name: public\n  namespace: metallb-system\nspec:\n  addresses:\n    - 203.0.113.16/28\n    - 203.0.113.40-203.0.113.45\n",
+		"kind: L2Advertisement\nmetadata:\n  name: primary-l2-advertisement\n  namespace: metallb-system\nspec:\n  ipAddressPools:\n    - private\n",
+		"kind: BGPAdvertisement\nmetadata:\n  name: primary-bgp-advertisement\n  namespace: metallb-system\nspec:\n  ipAddressPools:\n    - public\n",
+		"peerAddress: 10.10.0.1\n  peerASN: 64512\n  myASN: 64513",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("expected %q in rendered output:\n%s", want, rendered)
+		}
+	}
+
+	l2Only, err := Render("files/metallb/metallb.yaml", MetallbData{
+		Pools:   []MetallbPool{{Name: "private", Addresses: []string{"192.168.50.0/24"}}},
+		L2Pools: []string{"private"},
+	})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if strings.Contains(l2Only, "BGPAdvertisement") || strings.Contains(l2Only, "BGPPeer") {
+		t.Errorf("an L2-only configuration renders no BGP resources:\n%s", l2Only)
+	}
 }
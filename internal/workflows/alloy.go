@@ -9,8 +9,10 @@ import (
 
 // NewAlloyInstallWorkflow creates a workflow to install the Alloy observability stack.
 // This installs Prometheus Operator CRDs, Node Exporter, and Grafana Alloy.
-func NewAlloyInstallWorkflow() *automa.WorkflowBuilder {
-	return steps.SetupAlloyStack()
+// skipValuesValidation installs the charts without checking their values
+// (--skip-values-validation).
+func NewAlloyInstallWorkflow(skipValuesValidation bool) *automa.WorkflowBuilder {
+	return steps.SetupAlloyStack(skipValuesValidation)
 }
 
 // NewAlloyUninstallWorkflow creates a workflow to uninstall the Alloy observability stack.
@@ -4,8 +4,12 @@ package workflows
 
 import (
 	"context"
+	"slices"
 
 	"github.com/automa-saga/automa"
+	"github.com/hashgraph/solo-weaver/internal/kube/etcd"
+	"github.com/hashgraph/solo-weaver/internal/kube/join"
+	"github.com/hashgraph/solo-weaver/internal/kube/upgrade"
 	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
 	"github.com/hashgraph/solo-weaver/internal/workflows/steps"
 	"github.com/hashgraph/solo-weaver/pkg/config"
@@ -43,32 +47,11 @@ func InstallClusterWorkflow(skipHardwareChecks bool, mr software.MachineRuntime)
 // composed both by InstallClusterWorkflow (cluster install) and by node install handlers
 // (e.g. block node install) after their own workload preflight + system setup.
 func KubernetesSetupWorkflow(mr software.MachineRuntime) *automa.WorkflowBuilder {
-	wfSteps := []automa.Builder{
-		// setup env for k8s
-		steps.DisableSwap(),
-		steps.ConfigureSysctlForKubernetes(),
-		steps.SetupBindMounts(),
-
-		// kubelet
-		steps.SetupKubelet(mr),
-		steps.VerifyExecutablesStep("kubelet"),
-		steps.SetupSystemdService(software.KubeletServiceName),
-
-		// setup cli tools
-		steps.SetupKubectl(mr),
-		steps.SetupHelm(mr), // required by MetalLB setup, so we install it earlier
-		steps.SetupK9s(mr),
-
-		// CRI-O
-		steps.SetupCrio(mr),
-		steps.VerifyExecutablesStep(software.CrioArtifactName),
-		steps.SetupSystemdService(software.CrioServiceName),
-
-		// kubeadm
-		steps.SetupKubeadm(mr),
-
+	wfSteps := append(kubernetesHostSteps(mr, software.CiliumBinaryName),
 		// init cluster
 		steps.InitializeCluster(),
+		steps.RecordClusterMembership(),
+		steps.RecordClusterConfig(),
 
 		steps.SetupCilium(mr),
 		steps.StartCilium(),
@@ -76,7 +59,7 @@ func KubernetesSetupWorkflow(mr software.MachineRuntime) *automa.WorkflowBuilder
 		steps.SetupMetalLB(),
 
 		steps.DeployMetricsServer(nil),
-	}
+	)
 
 	if config.Get().SoloOperator.Enabled {
 		wfSteps = append(wfSteps, steps.InstallSoloOperator())
@@ -99,6 +82,85 @@ func KubernetesSetupWorkflow(mr software.MachineRuntime) *automa.WorkflowBuilder
 		})
 }
 
+// kubernetesHostArtifacts are the host artifacts kubernetesHostSteps installs.
+var kubernetesHostArtifacts = []string{
+	software.KubeletBinaryName,
+	software.KubectlBinaryName,
+	software.HelmBinaryName,
+	software.K9sBinaryName,
+	software.CrioArtifactName,
+	software.KubeadmBinaryName,
+}
+
+// kubernetesHostSteps prepares the host for a kubeadm node: the kernel
+// settings and bind mounts, then the kubelet, the CLI tools, CRI-O and
+// kubeadm. Both the host that initialises the cluster and hosts joining it run
+// them. It first downloads their artifacts, and the host artifacts named in
+// prefetch that later steps install, in parallel.
+func kubernetesHostSteps(mr software.MachineRuntime, prefetch ...string) []automa.Builder {
+	return []automa.Builder{
+		steps.PrefetchArtifacts(mr, append(slices.Clone(kubernetesHostArtifacts), prefetch...)...),
+
+		// setup env for k8s
+		steps.DisableSwap(),
+		steps.ConfigureSysctlForKubernetes(),
+		steps.SetupBindMounts(),
+
+		// kubelet
+		steps.SetupKubelet(mr),
+		steps.VerifyExecutablesStep("kubelet"),
+		steps.SetupSystemdService(software.KubeletServiceName),
+
+		// setup cli tools
+		steps.SetupKubectl(mr),
+		steps.SetupHelm(mr), // required by MetalLB setup, so we install it earlier
+		steps.SetupK9s(mr),
+
+		// CRI-O
+		steps.SetupCrio(mr),
+		steps.VerifyExecutablesStep(software.CrioArtifactName),
+		steps.SetupSystemdService(software.CrioServiceName),
+
+		// kubeadm
+		steps.SetupKubeadm(mr),
+	}
+}
+
+// JoinClusterWorkflow creates a workflow that joins the host to the cluster b
+// was minted for, as a worker or, with controlPlane, as a control-plane node.
+//
+// It runs the same substrate preflight and host setup as InstallClusterWorkflow
+// but joins the existing cluster instead of initialising one: Cilium, MetalLB
+// and the metrics server already run there and schedule onto the new node
+// themselves. A control-plane node finally checks the cluster's health; a
+// worker has no admin credentials to check it with.
+func JoinClusterWorkflow(skipHardwareChecks bool, mr software.MachineRuntime, b *join.Bundle, controlPlane bool) *automa.WorkflowBuilder {
+	joinSteps := append(kubernetesHostSteps(mr), steps.JoinCluster(b, controlPlane))
+	if controlPlane {
+		joinSteps = append(joinSteps, steps.CheckClusterHealth())
+	}
+
+	return automa.NewWorkflowBuilder().
+		WithId("join-kubernetes").
+		Steps(
+			SubstrateSetupWorkflow(skipHardwareChecks),
+			automa.NewWorkflowBuilder().
+				WithId("kubernetes-join").
+				Steps(joinSteps...).
+				WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+					notify.As().PhaseStart(ctx, stp, "Kubernetes Join")
+					return ctx, nil
+				}).
+				WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+					notify.As().PhaseFailure(ctx, stp, rpt, "Kubernetes Join")
+				}).
+				WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+					notify.As().PhaseCompletion(ctx, stp, rpt, "Kubernetes Join")
+				}),
+			steps.RecordProvisionerVersion(),
+		)
+}
+
 // WithWorkflowExecutionMode applies the given WorkflowExecutionOptions to the provided WorkflowBuilder.
 // If opts is nil, it uses DefaultWorkflowExecutionOptions.
 func WithWorkflowExecutionMode(wf *automa.WorkflowBuilder, opts *models.WorkflowExecutionOptions) *automa.WorkflowBuilder {
@@ -114,6 +176,8 @@ func UninstallClusterWorkflow() *automa.WorkflowBuilder {
 	teardownSteps := []automa.Builder{
 		// Reset the Kubernetes cluster
 		steps.ResetCluster(),
+		steps.ForgetClusterMembership(),
+		steps.ForgetClusterConfig(),
 
 		// Stop services
 		steps.TeardownSystemdService(software.CrioServiceName),
@@ -136,3 +200,60 @@ func UninstallClusterWorkflow() *automa.WorkflowBuilder {
 		WithId("teardown-kubernetes").
 		Steps(teardownSteps...)
 }
+
+// RenewClusterCertificatesWorkflow creates a workflow that renews the cluster's
+// leaf certificates and then checks that the cluster is healthy on them.
+func RenewClusterCertificatesWorkflow() *automa.WorkflowBuilder {
+	return automa.NewWorkflowBuilder().
+		WithId("renew-cluster-certificates").
+		Steps(
+			steps.RenewClusterCertificates(),
+			steps.CheckClusterHealth(),
+		)
+}
+
+// BackupClusterWorkflow creates a workflow that writes a cluster etcd backup.
+func BackupClusterWorkflow(opts etcd.BackupOptions) *automa.WorkflowBuilder {
+	return automa.NewWorkflowBuilder().
+		WithId("backup-cluster").
+		Steps(
+			steps.BackupClusterEtcd(opts),
+		)
+}
+
+// RestoreClusterWorkflow creates a workflow that restores the cluster's etcd
+// from the backup in dir and then checks that the cluster is healthy on it.
+func RestoreClusterWorkflow(dir string) *automa.WorkflowBuilder {
+	return automa.NewWorkflowBuilder().
+		WithId("restore-cluster").
+		Steps(
+			steps.RestoreClusterEtcd(dir),
+			steps.CheckClusterHealth(),
+		)
+}
+
+// UpgradeClusterWorkflow creates a workflow that upgrades the cluster as
+// planned: it backs the cluster's etcd up to backup, replaces kubeadm and
+// upgrades the control plane with it, then replaces the kubelet, CRI-O and
+// kubectl in that order, and finally checks that Cilium supports the new
+// version and that the cluster is healthy.
+//
+// Run it with automa.RollbackOnError so a failure reinstalls the previous
+// binaries of every component already replaced.
+func UpgradeClusterWorkflow(plan *upgrade.Plan, backup etcd.BackupOptions) *automa.WorkflowBuilder {
+	wfSteps := []automa.Builder{steps.BackupClusterEtcd(backup)}
+	for _, c := range plan.Components {
+		wfSteps = append(wfSteps, steps.UpgradeClusterComponent(c))
+		if c.Key == software.KubeadmBinaryName {
+			wfSteps = append(wfSteps, steps.UpgradeControlPlane(plan))
+		}
+	}
+	wfSteps = append(wfSteps,
+		steps.CheckCiliumCompatibility(),
+		steps.CheckClusterHealth(),
+	)
+
+	return automa.NewWorkflowBuilder().
+		WithId("upgrade-cluster").
+		Steps(wfSteps...)
+}
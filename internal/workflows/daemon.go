@@ -41,10 +41,13 @@ func buildComponentSpecs(cfg daemon.DaemonConfig, paths models.WeaverPaths) []st
 	// watcher lists/watches BN pods (pods: get/list/watch) and the veth resolver
 	// execs into a pod to read eth0's iflink (pods/exec: create). The statusz poll
 	// loop talks HTTP + `sudo network policy set`, so it needs no API access.
-	if bn := cfg.Components.BlockNode; bn != nil && bn.Enabled && bn.Monitors.TrafficShaper {
+	// The ClusterRole is cluster-wide, so one ServiceAccount serves every block
+	// node instance on the host; it lives in the orbit of the first instance
+	// that runs the monitor.
+	if bn := cfg.Components.BlockNode; bn != nil && bn.TrafficShaperEnabled() {
 		specs = append(specs, steps.DaemonComponentSpec{
 			ShortName:      "bn",
-			Namespace:      bn.Orbit,
+			Namespace:      blockNodeServiceAccountOrbit(*bn),
 			KubeconfigPath: paths.DaemonBNKubeconfigPath,
 			PolicyRules: []rbacv1.PolicyRule{
 				{
@@ -64,6 +67,17 @@ func buildComponentSpecs(cfg daemon.DaemonConfig, paths models.WeaverPaths) []st
 	return specs
 }
 
+// blockNodeServiceAccountOrbit returns the orbit of the first enabled block
+// node instance that runs the traffic-shaper monitor.
+func blockNodeServiceAccountOrbit(bn daemon.BlockNodeComponentConfig) string {
+	for _, inst := range bn.InstanceConfigs() {
+		if inst.Enabled && inst.Monitors.TrafficShaper {
+			return inst.Orbit
+		}
+	}
+	return bn.Orbit
+}
+
 // loadComponentSpecs reads daemon.yaml from paths and rebuilds the component
 // spec slice. Used by uninstall which must derive specs from the on-disk config
 // rather than a caller-supplied config.
@@ -84,7 +98,7 @@ func daemonExtraReadWritePaths(cfg daemon.DaemonConfig) []string {
 	if cn := cfg.Components.ConsensusNode; cn != nil && cn.Enabled {
 		paths = append(paths, "/opt/hgcapp")
 	}
-	if bn := cfg.Components.BlockNode; bn != nil && bn.Enabled && bn.Monitors.TrafficShaper {
+	if bn := cfg.Components.BlockNode; bn != nil && bn.TrafficShaperEnabled() {
 		// The traffic-shaper monitor's sudo'd children rewrite
 		// network-weaver-workload-policy.nft to persist statusz-derived set
 		// membership, so it survives a reboot. Those children inherit this
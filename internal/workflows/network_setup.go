@@ -21,6 +21,10 @@ import (
 type NetworkPlaneOptions struct {
 	// Force is the operator's --force, threaded into NetworkPolicyCreate.
 	Force bool
+	// Instance is the block node instance the plane belongs to (see
+	// models.BlockNodeInstance): it names the policies and the daemon.yaml
+	// entry the steps write. Empty for the default release.
+	Instance string
 	// TrafficShapingEnabled is the resolved traffic-shaping target: the BN
 	// workload policy plane + tc HTB shaping + daemon monitor as one bundle.
 	TrafficShapingEnabled bool
@@ -46,6 +50,10 @@ type NetworkPlaneOptions struct {
 	// it (turning a feature off deletes it); install/upgrade leave it false and
 	// re-assert create-if-missing only, so a routine run never tears anything down.
 	AllowTeardown bool
+	// SharedShaping is set when another block node instance on the host still
+	// shapes its traffic. The disable path then removes only this instance's
+	// policies and keeps the host-wide tc egress hierarchy in place.
+	SharedShaping bool
 	// WithDaemonReload appends the daemon-config write + service restart inline
 	// after the shaping steps so a live daemon reloads the changed daemon.yaml
 	// (it reads the file only at startup — no hot-reload). reconfigure/upgrade set
@@ -91,7 +99,8 @@ func NetworkPlaneSteps(opts NetworkPlaneOptions) []automa.Builder {
 	switch {
 	case opts.TrafficShapingEnabled:
 		// Enable: create the BN policy plane, persist the weaver table, (re)provision
-		// tc egress/ingress shaping, then — for the reload callers — enable the
+		// tc egress/ingress shaping, start the exporter that publishes their counters,
+		// then — for the reload callers — enable the
 		// daemon's traffic-shaper monitor and restart the daemon so the change takes
 		// effect. The daemon reads daemon.yaml only at startup (no hot-reload) and the
 		// post-workflow ensureBlockNodeDaemon is a no-op when the daemon is already
@@ -100,37 +109,38 @@ func NetworkPlaneSteps(opts NetworkPlaneOptions) []automa.Builder {
 		// (re)installed. RestartDaemonServiceStep self-skips when the daemon is not
 		// running (fresh enable), where post-workflow ensureBlockNodeDaemon installs it.
 		out = append(out,
-			steps.NetworkPolicyCreate(opts.Force, opts.HealthPort),
+			steps.NetworkPolicyCreate(opts.Force, opts.HealthPort, opts.Instance),
 			steps.NftWeaverPersist(),
 			steps.TcEgressPersist(opts.EgressInterface, opts.LinkRate, opts.ShapeOverrides),
 			steps.TcIngressRecord(opts.EgressInterface, opts.LinkRate, opts.ShapeOverrides),
+			steps.NetworkExporterSetup(),
 		)
 		if opts.WithDaemonReload {
 			out = append(out,
-				steps.WriteBlockNodeDaemonConfigStep(models.Paths(), opts.Namespace, opts.Statusz, true),
+				steps.WriteBlockNodeInstanceDaemonConfigStep(models.Paths(), opts.Instance, opts.Namespace, opts.Statusz, true),
 				steps.RestartDaemonServiceStep(),
 			)
 		}
 	case opts.AllowTeardown:
 		// Disable (reconfigure only): remove every BN policy (the last delete tears
 		// the inet weaver-workload-policy table down, so no NftWeaverPersist is needed), drop the tc
-		// egress hierarchy, then turn the daemon's monitor off and restart the daemon.
+		// egress hierarchy and its exporter, then turn the daemon's monitor off and restart the daemon.
 		// The restart matters because the daemon reads daemon.yaml only at startup:
 		// without it the live monitor keeps re-applying the ingress $VETH HTB on the
 		// next BN pod create (triggered by the rollout-restart that runs right after
 		// these steps). Restarting here — before that pod churn — ensures the monitor
 		// is gone when the new veth appears, so it comes up unshaped.
 		// RestartDaemonServiceStep self-skips when the daemon is not running.
-		out = append(out,
-			steps.NetworkPolicyDeleteAll(),
-			steps.TcEgressTeardown(),
-		)
+		out = append(out, steps.NetworkPolicyDeleteAll(opts.Instance))
+		if !opts.SharedShaping {
+			out = append(out, steps.TcEgressTeardown(), steps.NetworkExporterTeardown())
+		}
 		if opts.WithDaemonReload {
 			out = append(out,
 				// Disable path carries no statusz override: turning the monitor off must
 				// never touch an operator-set statusz block, and the step's per-field
 				// merge leaves any on-disk block intact for a zero-value statusz.
-				steps.WriteBlockNodeDaemonConfigStep(models.Paths(), opts.Namespace, daemon.StatuszConfig{}, false),
+				steps.WriteBlockNodeInstanceDaemonConfigStep(models.Paths(), opts.Instance, opts.Namespace, daemon.StatuszConfig{}, false),
 				steps.RestartDaemonServiceStep(),
 			)
 		}
@@ -157,13 +167,16 @@ func NetworkPlaneSteps(opts NetworkPlaneOptions) []automa.Builder {
 // included — it is gated separately by hostCfg.Disabled inside its own step).
 // When false, none of the four steps are added: there is no inet weaver-workload-policy table
 // to persist and no tc config to shape traffic with.
-func NetworkSetupWorkflow(egressInterface, linkRate string, shapeOverrides map[string]shape.ClassOverride, force bool, trafficShapingEnabled bool, healthPort string) *automa.WorkflowBuilder {
+//
+// instance is the block node instance being installed (see NetworkPlaneOptions).
+func NetworkSetupWorkflow(egressInterface, linkRate string, shapeOverrides map[string]shape.ClassOverride, force bool, trafficShapingEnabled bool, healthPort, instance string) *automa.WorkflowBuilder {
 	// Install shares the network-plane bundle with reconfigure/upgrade via
 	// NetworkPlaneSteps but never tears down (AllowTeardown=false) and defers
 	// the daemon config write + restart to BlockNodeDaemonConfigWorkflow, after
 	// the deployment it watches is in place (WithDaemonReload=false).
 	stepList := NetworkPlaneSteps(NetworkPlaneOptions{
 		Force:                 force,
+		Instance:              instance,
 		TrafficShapingEnabled: trafficShapingEnabled,
 		HealthPort:            healthPort,
 		EgressInterface:       egressInterface,
@@ -203,14 +216,16 @@ func NetworkSetupWorkflow(egressInterface, linkRate string, shapeOverrides map[s
 //
 // statusz carries the operator-supplied overrides (--statusz-base-url /
 // --statusz-poll-interval), merged per-field into daemon.yaml by the step; an
-// empty field leaves the existing on-disk statusz untouched.
-func BlockNodeDaemonConfigWorkflow(namespace string, statusz daemon.StatuszConfig) *automa.WorkflowBuilder {
+// empty field leaves the existing on-disk statusz untouched. instance is the
+// block node instance whose daemon.yaml entry is written and whose component
+// the probe expects.
+func BlockNodeDaemonConfigWorkflow(instance, namespace string, statusz daemon.StatuszConfig) *automa.WorkflowBuilder {
 	return automa.NewWorkflowBuilder().
 		WithId("block-node-daemon-config").
 		Steps(
-			steps.WriteBlockNodeDaemonConfigStep(models.Paths(), namespace, statusz, true),
+			steps.WriteBlockNodeInstanceDaemonConfigStep(models.Paths(), instance, namespace, statusz, true),
 			steps.RestartDaemonServiceStep(),
-			steps.CheckDaemonComponentPrerequisitesStep(models.Paths().DaemonSockPath, daemon.ComponentNameBlockNode),
+			steps.CheckDaemonComponentPrerequisitesStep(models.Paths().DaemonSockPath, daemon.BlockNodeComponentName(instance)),
 		).
 		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
 			notify.As().PhaseStart(ctx, stp, "Traffic-shaper Monitor")
@@ -18,7 +18,7 @@ import (
 // install's daemon-config phase must restart it (and probe the result) or a
 // daemon left running by uninstall keeps its stale config.
 func TestBlockNodeDaemonConfigWorkflow_PairsWriteWithRestartAndProbe(t *testing.T) {
-	wb := BlockNodeDaemonConfigWorkflow("hedera-block-node", daemon.StatuszConfig{})
+	wb := BlockNodeDaemonConfigWorkflow("", "hedera-block-node", daemon.StatuszConfig{})
 
 	stp, err := wb.Build()
 	require.NoError(t, err)
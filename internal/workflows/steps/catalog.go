@@ -54,6 +54,10 @@ type helmChartSpec struct {
 	Namespace string             // Kubernetes namespace to install into
 	Release   string             // Helm release name
 	Type      software.ChartType // classic vs oci, to gate repo-add side-effects
+
+	// Provenance is the chart's signature verification policy from the
+	// catalog's `provenance:` section, keys resolved in WeaverPaths.KeysDir.
+	Provenance helm.Provenance
 }
 
 // chartSpec returns the install plan for a named cluster component. It
@@ -104,6 +108,8 @@ func resolveCatalogChart(name string) (*helmChartSpec, error) {
 		Namespace: meta.Namespace,
 		Release:   meta.Release,
 		Type:      meta.Type,
+
+		Provenance: catalog.ProvenanceFor(meta.Chart).HelmProvenance(models.Paths().KeysDir),
 	}, nil
 }
 
@@ -45,11 +45,13 @@ const (
 // It is a bare container that composes three top-level phases, each of which emits
 // phase-level TUI progress (see PreflightAlloy, SetupPrometheusOperatorCRDs, SetupAlloy).
 // Mirrors InstallClusterWorkflow in internal/workflows/cluster.go.
-func SetupAlloyStack() *automa.WorkflowBuilder {
+// skipValuesValidation installs the charts without checking their values
+// against the charts (--skip-values-validation).
+func SetupAlloyStack(skipValuesValidation bool) *automa.WorkflowBuilder {
 	return automa.NewWorkflowBuilder().WithId("setup-alloy-stack").Steps(
-		PreflightAlloy(),              // Phase 1: verify prerequisites (cluster reachable, K8s secrets, remotes)
-		SetupPrometheusOperatorCRDs(), // Phase 2: install CRDs for ServiceMonitor/PodMonitor
-		SetupAlloy(),                  // Phase 3: install Alloy with Node Exporter
+		PreflightAlloy(),                 // Phase 1: verify prerequisites (cluster reachable, K8s secrets, remotes)
+		SetupPrometheusOperatorCRDs(),    // Phase 2: install CRDs for ServiceMonitor/PodMonitor
+		SetupAlloy(skipValuesValidation), // Phase 3: install Alloy with Node Exporter
 	)
 }
 
@@ -224,6 +226,7 @@ func preCheckAlloy() automa.Builder {
 				Int("prometheusRemotes", len(cfg.PrometheusRemotes)).
 				Int("lokiRemotes", len(cfg.LokiRemotes)).
 				Bool("monitorBlockNode", cfg.MonitorBlockNode).
+				Bool("monitorTrafficShaper", cfg.MonitorTrafficShaper).
 				Msg("Alloy prerequisites verified")
 
 			return automa.StepSuccessReport(stp.Id(), automa.WithMetadata(meta))
@@ -241,13 +244,13 @@ func preCheckAlloy() automa.Builder {
 }
 
 // SetupAlloy returns a workflow builder that sets up Grafana Alloy for observability.
-func SetupAlloy() *automa.WorkflowBuilder {
+func SetupAlloy(skipValuesValidation bool) *automa.WorkflowBuilder {
 	return automa.NewWorkflowBuilder().WithId(SetupAlloyStepId).Steps(
 		createAlloyNamespace(),
-		installNodeExporter(),
+		installNodeExporter(skipValuesValidation),
 		isNodeExporterPodsReady(),
 		deployAlloyConfig(),
-		installAlloy(),
+		installAlloy(skipValuesValidation),
 		deployBlockNodeMonitoring(),
 		isAlloyPodsReady(),
 	).
@@ -263,7 +266,7 @@ func SetupAlloy() *automa.WorkflowBuilder {
 		})
 }
 
-func installNodeExporter() automa.Builder {
+func installNodeExporter(skipValuesValidation bool) automa.Builder {
 	spec := chartSpec("node-exporter")
 	return automa.NewStepBuilder().WithId(InstallNodeExporterStepId).
 		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
@@ -285,7 +288,7 @@ func installNodeExporter() automa.Builder {
 				return automa.StepSuccessReport(stp.Id(), automa.WithMetadata(meta))
 			}
 
-			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum)
+			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum, helm.WithProvenance(spec.Provenance))
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
@@ -314,10 +317,12 @@ func installNodeExporter() automa.Builder {
 					ValueOpts: &values.Options{
 						Values: helmValues,
 					},
-					CreateNamespace: true,
-					Atomic:          true,
-					Wait:            true,
-					Timeout:         helm.DefaultTimeout,
+					CreateNamespace:      true,
+					Atomic:               true,
+					Wait:                 true,
+					Timeout:              helm.DefaultTimeout,
+					SkipValuesValidation: skipValuesValidation,
+					RejectUnknownValues:  true,
 				},
 			)
 			if err != nil {
@@ -430,7 +435,7 @@ func createAlloyNamespace() automa.Builder {
 		})
 }
 
-func installAlloy() automa.Builder {
+func installAlloy(skipValuesValidation bool) automa.Builder {
 	spec := chartSpec("alloy")
 	return automa.NewStepBuilder().WithId(InstallAlloyStepId).
 		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
@@ -464,7 +469,7 @@ func installAlloy() automa.Builder {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
 
-			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum)
+			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum, helm.WithProvenance(spec.Provenance))
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
@@ -501,10 +506,12 @@ func installAlloy() automa.Builder {
 					ValueOpts: &values.Options{
 						Values: helmValues,
 					},
-					CreateNamespace: true,
-					Atomic:          true,
-					Wait:            true,
-					Timeout:         helm.DefaultTimeout,
+					CreateNamespace:      true,
+					Atomic:               true,
+					Wait:                 true,
+					Timeout:              helm.DefaultTimeout,
+					SkipValuesValidation: skipValuesValidation,
+					RejectUnknownValues:  true,
 				},
 			)
 			if err != nil {
@@ -581,6 +588,7 @@ func deployAlloyConfig() automa.Builder {
 				Strs("modules", moduleNames).
 				Str("clusterName", cb.ClusterName()).
 				Bool("monitorBlockNode", cb.MonitorBlockNode()).
+				Bool("monitorTrafficShaper", cb.MonitorTrafficShaper()).
 				Msg("Alloy configuration modules")
 
 			// Create ConfigMap manifest with multiple .alloy files
@@ -597,7 +605,9 @@ func deployAlloyConfig() automa.Builder {
 			}
 
 			// Apply the ConfigMap
-			err = k.ApplyManifest(ctx, configMapManifestPath)
+			err = k.ApplyManifest(ctx, configMapManifestPath,
+				kube.WithForceConflicts(config.Get().Kube.ForceConflicts()),
+				kube.WithRecord(kube.ManifestAlloyConfigMap))
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
@@ -629,6 +639,9 @@ func deployAlloyConfig() automa.Builder {
 				if err != nil {
 					return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 				}
+				if err := kube.ForgetManifest(kube.ManifestAlloyConfigMap); err != nil {
+					return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+				}
 			}
 
 			return automa.StepSuccessReport(stp.Id())
@@ -233,7 +233,7 @@ func uninstallBlockNode(profile string, valuesFile string, getManager func() (*b
 // became ready), it fetches live pod state and recent Warning events from the
 // cluster and includes them in the error so the operator sees the actual failure
 // reason (e.g. ImagePullBackOff) without having to run kubectl manually.
-// action is "install" or "upgrade".
+// action is "install", "upgrade" or "rollback".
 func blockNodeChartError(ctx context.Context, err error, manager *blocknode.Manager, action string) error {
 	namespace := manager.Namespace()
 	if blocknode.IsHelmTimeoutError(err) {
@@ -784,8 +784,9 @@ func RecreateBlockNodeStorage(inputs models.BlockNodeInputs) *automa.WorkflowBui
 }
 
 // verifyBlockNodeReachable TCP-dials the block-node LoadBalancer Service from
-// the solo-provisioner host process. No-ops when LoadBalancerEnabled is false
-// (local profile). See `blocknode.Manager.VerifyExternalReachable`.
+// the solo-provisioner host process, then records the endpoint for the
+// daemon's probe monitor. No-ops when LoadBalancerEnabled is false (local
+// profile). See `blocknode.Manager.VerifyExternalReachable`.
 func verifyBlockNodeReachable(getManager func() (*blocknode.Manager, error)) automa.Builder {
 	return automa.NewStepBuilder().WithId(VerifyBlockNodeReachableStepId).
 		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
@@ -796,6 +797,11 @@ func verifyBlockNodeReachable(getManager func() (*blocknode.Manager, error)) aut
 			if err := manager.VerifyExternalReachable(ctx); err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
+			// The daemon keeps probing the last recorded target; a stale one
+			// only costs it failed probes, so this never fails the install.
+			if err := manager.RecordProbeTarget(ctx); err != nil {
+				logx.As().Warn().Err(err).Msg("Could not record the Block Node probe target for the daemon")
+			}
 			return automa.StepSuccessReport(stp.Id())
 		}).
 		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
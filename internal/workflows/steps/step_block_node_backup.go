@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+)
+
+const (
+	BackupBlockNodeStepId         = "backup-block-node"
+	BackupBlockNodeStorageStepId  = "backup-block-node-storage"
+	RestoreBlockNodeStepId        = "restore-block-node"
+	VerifyBlockNodeBackupStepId   = "verify-block-node-backup"
+	RestoreBlockNodeStorageStepId = "restore-block-node-storage"
+
+	// BackupDirMetadataKey is the step metadata key carrying the directory of
+	// the backup a backup step wrote.
+	BackupDirMetadataKey = "backupDir"
+)
+
+// BackupBlockNode quiesces the block node, archives its storage under
+// inputs.BackupTarget and brings the node back up.
+func BackupBlockNode(inputs models.BlockNodeInputs) *automa.WorkflowBuilder {
+	managerProvider := newBlockNodeManagerProvider(inputs)
+
+	return automa.NewWorkflowBuilder().WithId(BackupBlockNodeStepId).Steps(
+		scaleDownBlockNode(managerProvider),
+		waitForBlockNodeTerminated(managerProvider),
+		backupBlockNodeStorage(inputs.BackupTarget, inputs.BackupIncremental, managerProvider),
+		scaleUpBlockNode(managerProvider),
+		waitForBlockNode(managerProvider),
+	).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Backing up Block Node storage")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to back up Block Node storage")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node storage backed up successfully")
+		})
+}
+
+// RestoreBlockNode verifies the backup in inputs.RestoreFrom while the node is
+// still serving, then quiesces it, replaces its storage with the backup and
+// brings it back up. force is forwarded to the compatibility check.
+func RestoreBlockNode(inputs models.BlockNodeInputs, force bool) *automa.WorkflowBuilder {
+	managerProvider := newBlockNodeManagerProvider(inputs)
+
+	return automa.NewWorkflowBuilder().WithId(RestoreBlockNodeStepId).Steps(
+		verifyBlockNodeBackup(inputs.RestoreFrom, force, managerProvider),
+		scaleDownBlockNode(managerProvider),
+		waitForBlockNodeTerminated(managerProvider),
+		restoreBlockNodeStorage(inputs.RestoreFrom, force, managerProvider),
+		scaleUpBlockNode(managerProvider),
+		waitForBlockNode(managerProvider),
+	).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Restoring Block Node storage")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to restore Block Node storage")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node storage restored successfully")
+		})
+}
+
+// backupBlockNodeStorage writes the backup. A backup never changes the node's
+// data, so a failed one scales the node straight back up rather than leaving
+// a healthy node down until the operator intervenes.
+func backupBlockNodeStorage(target string, incremental bool, getManager func() (*blocknode.Manager, error)) automa.Builder {
+	return automa.NewStepBuilder().WithId(BackupBlockNodeStorageStepId).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			manager, err := getManager()
+			if err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			dir, err := manager.BackupStorage(ctx, target, incremental)
+			if err != nil {
+				if upErr := manager.ScaleStatefulSet(ctx, 1); upErr != nil {
+					logx.As().Warn().Err(upErr).Msg("Failed to scale Block Node back up after a failed backup")
+				}
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			return automa.StepSuccessReport(stp.Id(), automa.WithMetadata(map[string]string{
+				BackupDirMetadataKey: dir,
+			}))
+		}).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Archiving Block Node storage volumes")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to archive Block Node storage volumes")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node storage volumes archived")
+		})
+}
+
+// verifyBlockNodeBackup checks compatibility and checksums before anything is
+// stopped or cleared.
+func verifyBlockNodeBackup(dir string, force bool, getManager func() (*blocknode.Manager, error)) automa.Builder {
+	return automa.NewStepBuilder().WithId(VerifyBlockNodeBackupStepId).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			manager, err := getManager()
+			if err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			if err := manager.VerifyBackup(ctx, dir, force); err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			return automa.StepSuccessReport(stp.Id())
+		}).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Verifying Block Node backup")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Block Node backup failed verification")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node backup verified")
+		})
+}
+
+// restoreBlockNodeStorage repopulates storage from the backup. Unlike a failed
+// backup, a failed restore leaves the node scaled down: its storage is
+// partially replaced and must not be served.
+func restoreBlockNodeStorage(dir string, force bool, getManager func() (*blocknode.Manager, error)) automa.Builder {
+	return automa.NewStepBuilder().WithId(RestoreBlockNodeStorageStepId).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			manager, err := getManager()
+			if err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			if err := manager.RestoreStorage(ctx, dir, force); err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			return automa.StepSuccessReport(stp.Id(), automa.WithMetadata(map[string]string{
+				ConfiguredByThisStep: "true",
+			}))
+		}).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Restoring Block Node storage volumes")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to restore Block Node storage volumes")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node storage volumes restored")
+		})
+}
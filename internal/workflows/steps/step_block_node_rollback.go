@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+const (
+	RollbackBlockNodeStepId      = "rollback-block-node"
+	RollbackBlockNodeChartStepId = "rollback-block-node-chart"
+
+	// RollbackFromRevisionMetadataKey and RollbackToRevisionMetadataKey are the
+	// step metadata keys carrying the Helm revisions the rollback moved between.
+	RollbackFromRevisionMetadataKey = "fromRevision"
+	RollbackToRevisionMetadataKey   = "toRevision"
+)
+
+// RollbackBlockNode rolls the block node release back to inputs.RollbackRevision
+// (the previous successfully deployed revision when zero), re-creating the
+// storage the target chart version mounts, then waits for the pod and probes
+// the service the same way an upgrade does. force lets the rollback cross a
+// storage boundary that does not support it.
+func RollbackBlockNode(inputs models.BlockNodeInputs, force bool) *automa.WorkflowBuilder {
+	blockNodeManagerProvider := newBlockNodeManagerProvider(inputs)
+
+	return automa.NewWorkflowBuilder().WithId(RollbackBlockNodeStepId).Steps(
+		EnsureHederaOwnerStep(),
+		rollbackBlockNodeChart(inputs.RollbackRevision, force, blockNodeManagerProvider),
+		waitForBlockNode(blockNodeManagerProvider),
+		verifyBlockNodeReachable(blockNodeManagerProvider),
+	).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Rolling back Block Node")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to roll back Block Node")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node rolled back successfully")
+		})
+}
+
+// rollbackBlockNodeChart plans the rollback against the live release history
+// and carries it out. As in upgradeBlockNode, the helm-owned Services are
+// deleted only at the commit point, after planning, so a refused or failed
+// plan leaves the node reachable.
+func rollbackBlockNodeChart(toRevision int, force bool, getManager func() (*blocknode.Manager, error)) automa.Builder {
+	return automa.NewStepBuilder().WithId(RollbackBlockNodeChartStepId).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			manager, err := getManager()
+			if err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			plan, err := manager.PlanRollback(toRevision, force)
+			if err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+			logx.As().Info().Str("plan", plan.String()).Msg("Rolling back Block Node chart")
+			for _, optStor := range plan.Irreversible {
+				logx.As().Warn().Str("storage", optStor.Name).
+					Msg("Forced rollback across a storage boundary that does not support it; " +
+						"the block node may not read its state until a backup from before the upgrade is restored")
+			}
+
+			// Commit point: delete Services immediately before the helm rollback.
+			if err := manager.DeleteHelmOwnedServices(ctx); err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			if err := manager.RollbackChart(ctx, plan); err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(blockNodeChartError(ctx, err, manager, "rollback")))
+			}
+
+			return automa.StepSuccessReport(stp.Id(), automa.WithMetadata(map[string]string{
+				RollbackFromRevisionMetadataKey: strconv.Itoa(plan.FromRevision),
+				RollbackToRevisionMetadataKey:   strconv.Itoa(plan.ToRevision),
+			}))
+		}).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Rolling back Block Node chart")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to roll back Block Node chart")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node chart rolled back successfully")
+		})
+}
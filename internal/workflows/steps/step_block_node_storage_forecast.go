@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/internal/blocknode/forecast"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+)
+
+const (
+	StorageForecastBlockNodeStepId      = "storage-forecast-block-node"
+	RecordBlockNodeStorageSampleStepId  = "record-block-node-storage-sample"
+	CheckBlockNodeStorageCapacityStepId = "check-block-node-storage-capacity"
+
+	// StorageCapacityWarningsMetadataKey is the step metadata key carrying how
+	// many capacity warnings the preflight raised.
+	StorageCapacityWarningsMetadataKey = "storageCapacityWarnings"
+)
+
+// blockNodeStorageFiles returns the volumes file and the sample store of the
+// block node deployed as inputs.Release, both shared with that instance's
+// daemon storage sampler.
+func blockNodeStorageFiles(inputs models.BlockNodeInputs) (string, forecast.Store) {
+	volumes, samples := models.Paths().BlockNodeStoragePaths(models.BlockNodeInstance(inputs.Release))
+	return volumes, forecast.Store{Path: samples}
+}
+
+// RecordBlockNodeStorageSample measures the block node's storage volumes and
+// appends the sample to the history `block node storage forecast` projects
+// from.
+func RecordBlockNodeStorageSample(inputs models.BlockNodeInputs) *automa.WorkflowBuilder {
+	return automa.NewWorkflowBuilder().WithId(StorageForecastBlockNodeStepId).Steps(
+		recordBlockNodeStorageSample(inputs),
+	).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Sampling Block Node storage")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to sample Block Node storage")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node storage sampled successfully")
+		})
+}
+
+func recordBlockNodeStorageSample(inputs models.BlockNodeInputs) automa.Builder {
+	return automa.NewStepBuilder().WithId(RecordBlockNodeStorageSampleStepId).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			volumes, store := blockNodeStorageFiles(inputs)
+			if err := blocknode.RecordStorageSample(inputs, volumes, store, time.Now()); err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+			return automa.StepSuccessReport(stp.Id())
+		}).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Measuring storage volume usage")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to measure storage volume usage")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Storage volume usage measured")
+		})
+}
+
+// CheckBlockNodeStorageCapacity is the install/reconfigure preflight that
+// warns when the configured volume sizes and retention thresholds cannot fit
+// the filesystem behind the storage base path. It is advisory: it never fails
+// the workflow, since only the operator knows whether the disk will grow or
+// the archive will be offloaded. It also records a storage sample and the
+// volumes for the daemon's storage sampler, so the forecast starts from the
+// deployed layout.
+func CheckBlockNodeStorageCapacity(inputs models.BlockNodeInputs) automa.Builder {
+	return automa.NewStepBuilder().WithId(CheckBlockNodeStorageCapacityStepId).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			volumes, store := blockNodeStorageFiles(inputs)
+			if err := blocknode.RecordStorageSample(inputs, volumes, store, time.Now()); err != nil {
+				logx.As().Warn().Err(err).Msg("Could not record a Block Node storage sample")
+			}
+			samples, err := store.Read()
+			if err != nil {
+				logx.As().Warn().Err(err).Msg("Could not read Block Node storage samples")
+			}
+
+			warnings, err := blocknode.CheckStorageCapacity(inputs, samples)
+			if err != nil {
+				logx.As().Warn().Err(err).Msg("Could not check Block Node storage capacity")
+				return automa.StepSkippedReport(stp.Id())
+			}
+			for _, w := range warnings {
+				logx.As().Warn().Msg(w)
+			}
+			if len(warnings) > 0 {
+				logx.As().Warn().Msg("Review the storage sizes and --recent-retention/--historic-retention; " +
+					"run 'solo-provisioner block node storage forecast' to project days-to-full")
+			}
+
+			return automa.StepSuccessReport(stp.Id(), automa.WithMetadata(map[string]string{
+				StorageCapacityWarningsMetadataKey: strconv.Itoa(len(warnings)),
+			}))
+		}).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Checking Block Node storage capacity")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to check Block Node storage capacity")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Block Node storage capacity checked")
+		})
+}
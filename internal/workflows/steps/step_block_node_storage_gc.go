@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/blocknode"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+)
+
+const (
+	StorageGCBlockNodeStepId             = "storage-gc-block-node"
+	CollectRetiredBlockNodeStorageStepId = "collect-retired-block-node-storage"
+
+	// RetiredStorageCountMetadataKey is the step metadata key carrying how many
+	// retired volumes were collected.
+	RetiredStorageCountMetadataKey = "retiredVolumes"
+)
+
+// CollectRetiredBlockNodeStorage archives (when inputs.StorageGCArchiveTo is
+// set) and deletes the PV/PVCs and host directories of optional storage the
+// installed chart version has retired. The node keeps running: retired volumes
+// are by definition no longer mounted.
+func CollectRetiredBlockNodeStorage(inputs models.BlockNodeInputs) *automa.WorkflowBuilder {
+	managerProvider := newBlockNodeManagerProvider(inputs)
+
+	return automa.NewWorkflowBuilder().WithId(StorageGCBlockNodeStepId).Steps(
+		collectRetiredBlockNodeStorage(inputs.StorageGCArchiveTo, managerProvider),
+	).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Collecting retired Block Node storage")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to collect retired Block Node storage")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Retired Block Node storage collected successfully")
+		})
+}
+
+// collectRetiredBlockNodeStorage re-inspects the retired volumes at execution
+// time rather than trusting an earlier report, so a volume already collected
+// is simply skipped.
+func collectRetiredBlockNodeStorage(archiveTo string, getManager func() (*blocknode.Manager, error)) automa.Builder {
+	return automa.NewStepBuilder().WithId(CollectRetiredBlockNodeStorageStepId).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			manager, err := getManager()
+			if err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			found, err := manager.FindRetiredStorage(ctx)
+			if err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+			if len(found) == 0 {
+				logx.As().Info().Msg("No retired Block Node storage to collect")
+				return automa.StepSkippedReport(stp.Id())
+			}
+			for _, r := range found {
+				logx.As().Info().Str("retired", r.String()).Msg("Collecting retired Block Node storage")
+			}
+
+			if err := manager.CollectRetiredStorage(ctx, found, archiveTo); err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
+
+			return automa.StepSuccessReport(stp.Id(), automa.WithMetadata(map[string]string{
+				RetiredStorageCountMetadataKey: strconv.Itoa(len(found)),
+			}))
+		}).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Archiving and deleting retired storage volumes")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to collect retired storage volumes")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Retired storage volumes collected")
+		})
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+
+	"github.com/hashgraph/solo-weaver/internal/kube/etcd"
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// BackupClusterEtcd snapshots the cluster's etcd with the sandboxed etcd
+// client certificates and writes it, the PKI and the kubeadm config as a
+// checksummed cluster backup under opts.Target, then prunes the backups beyond
+// opts.Keep. The control plane keeps serving throughout.
+func BackupClusterEtcd(opts etcd.BackupOptions) *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("backup-cluster-etcd").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Backing up the Kubernetes cluster etcd")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to back up the Kubernetes cluster etcd")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Kubernetes cluster etcd backed up successfully")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if err := verifyExecutables(software.CrioArtifactName); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			res, err := etcd.NewManager(models.Paths()).Backup(ctx, opts)
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			logx.As().Info().
+				Str("backup", res.Dir).
+				Int64("revision", res.Revision).
+				Strs("pruned", res.Pruned).
+				Msg("Cluster backup written")
+
+			return automa.SuccessReport(stp, automa.WithDetail(res.Dir))
+		})
+}
+
+// RestoreClusterEtcd restores the cluster's etcd from the cluster backup in
+// dir: it verifies the backup's checksums, restores the snapshot into a new
+// data directory, stops the kubelet and the control plane, swaps the restored
+// data in and starts the kubelet again, then waits for the API server to
+// answer. The replaced data directory is kept next to the restored one.
+func RestoreClusterEtcd(dir string) *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("restore-cluster-etcd").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Restoring the Kubernetes cluster etcd")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to restore the Kubernetes cluster etcd")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Kubernetes cluster etcd restored successfully")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if err := verifyExecutables(software.CrioArtifactName); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			previous, err := etcd.NewManager(models.Paths()).Restore(ctx, dir)
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			logx.As().Info().Str("previous_data_dir", previous).
+				Msg("Kept the replaced etcd data directory; remove it once the restored cluster is verified")
+
+			if err := waitForAPIServer(ctx, apiServerRestartTimeout); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			return automa.SuccessReport(stp, automa.WithDetail(previous))
+		})
+}
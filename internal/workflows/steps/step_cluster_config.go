@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/templates"
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/config"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// The seams the cluster layout is read from and recorded through. Vars so
+// tests can run the steps without a state file.
+var (
+	readClusterConfig    = state.ReadClusterConfigFromDisk
+	persistClusterConfig = state.PersistClusterConfig
+)
+
+// effectiveClusterConfig returns the cluster network layout the steps render:
+// the `cluster` config block (already carrying the CLI flags), then the layout
+// recorded when the cluster was initialised, then the defaults.
+func effectiveClusterConfig() (models.ClusterConfig, error) {
+	cfg := config.Get().Cluster
+	recorded, err := readClusterConfig()
+	if err != nil {
+		return models.ClusterConfig{}, err
+	}
+	if recorded != nil {
+		cfg = cfg.Merge(*recorded)
+	}
+	cfg = cfg.Merge(models.DefaultClusterConfig())
+	if err := cfg.Validate(); err != nil {
+		return models.ClusterConfig{}, err
+	}
+	return cfg, nil
+}
+
+// metalLBData maps the load balancer configuration onto the MetalLB template,
+// substituting machineIP for models.MachineIPAddress.
+func metalLBData(lb models.LoadBalancerConfig, machineIP string) templates.MetallbData {
+	var data templates.MetallbData
+	for _, p := range lb.Pools {
+		pool := templates.MetallbPool{Name: p.Name}
+		for _, a := range p.Addresses {
+			if a == models.MachineIPAddress {
+				a = machineIP + "/32"
+			}
+			pool.Addresses = append(pool.Addresses, a)
+		}
+		data.Pools = append(data.Pools, pool)
+
+		if p.Advertisement == models.AdvertisementBGP {
+			data.BGPPools = append(data.BGPPools, p.Name)
+		} else {
+			data.L2Pools = append(data.L2Pools, p.Name)
+		}
+	}
+	for _, peer := range lb.BGPPeers {
+		data.BGPPeers = append(data.BGPPeers, templates.MetallbBGPPeer{
+			Name:        peer.Name,
+			PeerAddress: peer.PeerAddress,
+			PeerASN:     peer.PeerASN,
+			MyASN:       peer.MyASN,
+		})
+	}
+	return data
+}
+
+// RecordClusterConfig records the network layout and MetalLB pools the cluster
+// runs with in ClusterState.Config, so reconfigure and upgrade render the same
+// values rather than the defaults.
+func RecordClusterConfig() *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("record-cluster-config").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Recording cluster network configuration")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to record cluster network configuration")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Cluster network configuration recorded")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			cfg, err := effectiveClusterConfig()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			if err := persistClusterConfig(&cfg); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			pools := make([]string, 0, len(cfg.LoadBalancer.Pools))
+			for _, p := range cfg.LoadBalancer.Pools {
+				pools = append(pools, p.Name)
+			}
+			return automa.SuccessReport(stp, automa.WithDetail(fmt.Sprintf("%s: services %s, pods %s, pools %s",
+				cfg.Name, cfg.ServiceSubnet, cfg.PodSubnet, strings.Join(pools, ", "))))
+		})
+}
+
+// ForgetClusterConfig clears the recorded layout once the cluster has been
+// reset, so the next install starts from the config file and defaults.
+func ForgetClusterConfig() *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("forget-cluster-config").
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if err := persistClusterConfig(nil); err != nil {
+				// Teardown is best-effort; a stale record only supplies defaults to the next install.
+				logx.As().Warn().Err(err).Msg("Failed to clear the recorded cluster network configuration")
+			}
+			return automa.SuccessReport(stp)
+		})
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/templates"
+	"github.com/hashgraph/solo-weaver/pkg/config"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// stubClusterConfig sets the `cluster` config block and replaces the state
+// file with recorded, capturing what the steps persist into *persisted.
+func stubClusterConfig(t *testing.T, cfg models.ClusterConfig, recorded *models.ClusterConfig, persisted **models.ClusterConfig) {
+	t.Helper()
+	saved := config.Get()
+	c := saved
+	c.Cluster = cfg
+	require.NoError(t, config.Set(&c))
+
+	origRead, origPersist := readClusterConfig, persistClusterConfig
+	t.Cleanup(func() {
+		_ = config.Set(&saved)
+		readClusterConfig, persistClusterConfig = origRead, origPersist
+	})
+	readClusterConfig = func() (*models.ClusterConfig, error) { return recorded, nil }
+	persistClusterConfig = func(c *models.ClusterConfig, _ ...state.ManagerOption) error {
+		*persisted = c
+		return nil
+	}
+}
+
+func TestEffectiveClusterConfig(t *testing.T) {
+	recorded := models.DefaultClusterConfig()
+	recorded.PodSubnet = "172.24.0.0/14"
+	var persisted *models.ClusterConfig
+	stubClusterConfig(t, models.ClusterConfig{Name: "site-a"}, &recorded, &persisted)
+
+	cfg, err := effectiveClusterConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "site-a", cfg.Name, "the config block wins")
+	assert.Equal(t, "172.24.0.0/14", cfg.PodSubnet, "the recorded layout wins over the default")
+	assert.Equal(t, models.DefaultClusterServiceSubnet, cfg.ServiceSubnet)
+}
+
+func TestMetalLBData(t *testing.T) {
+	data := metalLBData(models.LoadBalancerConfig{
+		Pools: []models.AddressPool{
+			{Name: "private", Addresses: []string{"192.168.50.0/24"}, Advertisement: models.AdvertisementL2},
+			{Name: "public", Addresses: []string{models.MachineIPAddress, "203.0.113.16/28"}, Advertisement: models.AdvertisementBGP},
+		},
+		BGPPeers: []models.BGPPeer{{Name: "tor1", PeerAddress: "10.10.0.1", PeerASN: 64512, MyASN: 64513}},
+	}, "10.0.0.5")
+
+	assert.Equal(t, templates.MetallbData{
+		Pools: []templates.MetallbPool{
+			{Name: "private", Addresses: []string{"192.168.50.0/24"}},
+			{Name: "public", Addresses: []string{"10.0.0.5/32", "203.0.113.16/28"}},
+		},
+		L2Pools:  []string{"private"},
+		BGPPools: []string{"public"},
+		BGPPeers: []templates.MetallbBGPPeer{{Name: "tor1", PeerAddress: "10.10.0.1", PeerASN: 64512, MyASN: 64513}},
+	}, data)
+
+	defaults := metalLBData(models.DefaultClusterConfig().LoadBalancer, "10.0.0.5")
+	assert.Equal(t, []string{"private-address-pool", "public-address-pool"}, defaults.L2Pools,
+		"the default pools keep the resources clusters were deployed with")
+	assert.Empty(t, defaults.BGPPools)
+}
+
+func TestRecordClusterConfig(t *testing.T) {
+	var persisted *models.ClusterConfig
+	stubClusterConfig(t, models.ClusterConfig{ServiceSubnet: "172.20.0.0/16"}, nil, &persisted)
+
+	step, err := RecordClusterConfig().Build()
+	require.NoError(t, err)
+	report := step.Execute(context.Background())
+	require.NoError(t, report.Error)
+
+	require.NotNil(t, persisted)
+	want := models.ClusterConfig{ServiceSubnet: "172.20.0.0/16"}.Merge(models.DefaultClusterConfig())
+	assert.Equal(t, want, *persisted)
+}
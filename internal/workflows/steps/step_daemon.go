@@ -1004,9 +1004,11 @@ func fetchDaemonStatusAfterRestart(ctx context.Context, sockPath string, retry b
 // errors or degraded monitor states are present, or an empty string when
 // everything is healthy.
 //
-// Two classes of issue are reported:
+// Three classes of issue are reported:
 //   - Probe errors (disk prerequisites): the component's required directories
 //     are missing, wrongly owned, or not writable. Operator must act.
+//   - Alerts: a host-wide condition heading for an outage, e.g. a cluster
+//     certificate close to expiry. Operator must act before it lapses.
 //   - Degraded monitors: a monitor's last watch/list cycle failed (e.g. RBAC
 //     revoked). The monitor retries automatically; operator should investigate.
 //
@@ -1047,6 +1049,24 @@ func componentPrerequisiteWarnings(status *daemon.StatusResponse) string {
 		sb.WriteString("\n")
 	}
 
+	// Host-wide alerts — e.g. cluster certificates close to expiry.
+	alerts := make([]string, 0, len(status.Alerts))
+	for a := range status.Alerts {
+		alerts = append(alerts, a)
+	}
+	sort.Strings(alerts)
+	for _, a := range alerts {
+		hasIssues = true
+		al := status.Alerts[a]
+		sb.WriteString(fmt.Sprintf("  [ALERT] %s (%s): %s\n", a, al.Reason, al.Message))
+		if al.Resolution != "" {
+			sb.WriteString(fmt.Sprintf("    Resolution: %s\n", al.Resolution))
+		}
+		if al.Since != "" {
+			sb.WriteString(fmt.Sprintf("    Since: %s\n", al.Since))
+		}
+	}
+
 	// Degraded monitors — connectivity failures visible inside a running goroutine.
 	for compName, cs := range status.Components {
 		for monName, ms := range cs.Monitors {
@@ -41,9 +41,10 @@ const (
 // — merges the operator-owned statusz block (see below), preserves the
 // consensus_node block, then writes it back.
 //
-// enabled drives both Components.BlockNode.Enabled and Monitors.TrafficShaper:
-//   - true  (install / reconfigure enable): the traffic-shaper monitor runs once
-//     the daemon is up.
+// enabled drives Components.BlockNode.Enabled, Monitors.TrafficShaper,
+// Monitors.StorageSampler and Monitors.Probe:
+//   - true  (install / reconfigure enable): the traffic-shaper, storage
+//     sampler and probe monitors run once the daemon is up.
 //   - false (reconfigure disable): the block-node component and its traffic-shaper
 //     monitor are turned off WITHOUT uninstalling the daemon binary/service, so a
 //     co-located component (e.g. consensus-node monitoring) that shares the same
@@ -62,6 +63,15 @@ const (
 // reversed on rollback (the file is restored to its prior content, or removed if
 // it did not exist).
 func WriteBlockNodeDaemonConfigStep(paths models.WeaverPaths, orbit string, statusz daemon.StatuszConfig, enabled bool) *automa.StepBuilder {
+	return WriteBlockNodeInstanceDaemonConfigStep(paths, "", orbit, statusz, enabled)
+}
+
+// WriteBlockNodeInstanceDaemonConfigStep is WriteBlockNodeDaemonConfigStep for
+// the block node instance named instance (see models.BlockNodeInstance). The
+// default instance (empty) owns the block_node block itself; any other instance
+// is recorded as its components.block_node.instances entry, leaving the block's
+// own enablement — and every other instance — untouched.
+func WriteBlockNodeInstanceDaemonConfigStep(paths models.WeaverPaths, instance, orbit string, statusz daemon.StatuszConfig, enabled bool) *automa.StepBuilder {
 	cfgPath := paths.DaemonConfigPath
 	kubeconfig := paths.DaemonBNKubeconfigPath
 	if orbit == "" {
@@ -124,27 +134,39 @@ func WriteBlockNodeDaemonConfigStep(paths models.WeaverPaths, orbit string, stat
 			// (--statusz-base-url / --statusz-poll-interval). An unset override
 			// preserves the existing on-disk value, so a bare reconfigure/upgrade
 			// never clobbers a hand-edited statusz block.
-			bn := &daemon.BlockNodeComponentConfig{
-				Enabled:    enabled,
-				Kubeconfig: kubeconfig,
-				Orbit:      orbit,
-				Monitors:   daemon.BlockNodeMonitors{TrafficShaper: enabled},
+			inst := daemon.BlockNodeInstanceConfig{
+				Name:     instance,
+				Enabled:  enabled,
+				Orbit:    orbit,
+				Monitors: daemon.BlockNodeMonitors{TrafficShaper: enabled, StorageSampler: enabled, Probe: enabled},
 			}
-			if cfg.Components.BlockNode != nil {
-				bn.Statusz = cfg.Components.BlockNode.Statusz
-			}
-			if statusz.BaseURL != "" || statusz.PollInterval != "" {
-				merged := daemon.StatuszConfig{}
-				if bn.Statusz != nil {
-					merged = *bn.Statusz
-				}
-				if statusz.BaseURL != "" {
-					merged.BaseURL = statusz.BaseURL
+			onDisk := cfg.Components.BlockNode
+			idx := -1
+			if onDisk != nil {
+				if instance == "" {
+					inst.Statusz = onDisk.Statusz
 				}
-				if statusz.PollInterval != "" {
-					merged.PollInterval = statusz.PollInterval
+				for i, other := range onDisk.Instances {
+					if instance != "" && other.Name == instance {
+						idx = i
+						inst.Statusz = other.Statusz
+					}
 				}
-				bn.Statusz = &merged
+			}
+			inst.Statusz = mergeStatusz(inst.Statusz, statusz)
+
+			bn := &daemon.BlockNodeComponentConfig{Kubeconfig: kubeconfig}
+			if onDisk != nil {
+				bn.Enabled, bn.Orbit, bn.Monitors, bn.Statusz = onDisk.Enabled, onDisk.Orbit, onDisk.Monitors, onDisk.Statusz
+				bn.Instances = append([]daemon.BlockNodeInstanceConfig(nil), onDisk.Instances...)
+			}
+			switch {
+			case instance == "":
+				bn.Enabled, bn.Orbit, bn.Monitors, bn.Statusz = inst.Enabled, inst.Orbit, inst.Monitors, inst.Statusz
+			case idx >= 0:
+				bn.Instances[idx] = inst
+			default:
+				bn.Instances = append(bn.Instances, inst)
 			}
 			cfg.Components.BlockNode = bn
 
@@ -167,6 +189,7 @@ func WriteBlockNodeDaemonConfigStep(paths models.WeaverPaths, orbit string, stat
 			stp.State().Local().Set(daemonConfigPriorContentKey, string(prior))
 			logx.As().Info().
 				Str("path", cfgPath).
+				Str("instance", instance).
 				Str("orbit", orbit).
 				Bool("enabled", enabled).
 				Msg("Updated block-node traffic-shaper monitor in daemon.yaml")
@@ -189,3 +212,22 @@ func WriteBlockNodeDaemonConfigStep(paths models.WeaverPaths, orbit string, stat
 			return automa.SuccessReport(stp)
 		})
 }
+
+// mergeStatusz overlays each non-empty field of override on the statusz block
+// already on disk (current, possibly nil). An empty override keeps current.
+func mergeStatusz(current *daemon.StatuszConfig, override daemon.StatuszConfig) *daemon.StatuszConfig {
+	if override.BaseURL == "" && override.PollInterval == "" {
+		return current
+	}
+	merged := daemon.StatuszConfig{}
+	if current != nil {
+		merged = *current
+	}
+	if override.BaseURL != "" {
+		merged.BaseURL = override.BaseURL
+	}
+	if override.PollInterval != "" {
+		merged.PollInterval = override.PollInterval
+	}
+	return &merged
+}
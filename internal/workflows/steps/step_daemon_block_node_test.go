@@ -39,6 +39,8 @@ func TestWriteBlockNodeDaemonConfig_FreshFile(t *testing.T) {
 	require.NotNil(t, cfg.Components.BlockNode)
 	assert.True(t, cfg.Components.BlockNode.Enabled)
 	assert.True(t, cfg.Components.BlockNode.Monitors.TrafficShaper)
+	assert.True(t, cfg.Components.BlockNode.Monitors.StorageSampler)
+	assert.True(t, cfg.Components.BlockNode.Monitors.Probe)
 	assert.Equal(t, "block-node", cfg.Components.BlockNode.Orbit)
 	assert.Equal(t, paths.DaemonBNKubeconfigPath, cfg.Components.BlockNode.Kubeconfig)
 
@@ -96,6 +98,8 @@ func TestWriteBlockNodeDaemonConfig_DisableTurnsMonitorOff(t *testing.T) {
 	require.NotNil(t, cfg.Components.BlockNode)
 	assert.False(t, cfg.Components.BlockNode.Enabled)
 	assert.False(t, cfg.Components.BlockNode.Monitors.TrafficShaper)
+	assert.False(t, cfg.Components.BlockNode.Monitors.StorageSampler)
+	assert.False(t, cfg.Components.BlockNode.Monitors.Probe)
 	require.NotNil(t, cfg.Components.BlockNode.Statusz)
 	assert.Equal(t, "http://127.0.0.1:8080", cfg.Components.BlockNode.Statusz.BaseURL)
 	// consensus_node component is untouched — disabling BN must not affect it.
@@ -108,3 +108,19 @@ func TestCheckDaemonComponentPrerequisitesStep_DegradedMonitorFails(t *testing.T
 	require.Error(t, report.Error)
 	assert.Equal(t, automa.StatusFailed, report.Status)
 }
+
+func TestComponentPrerequisiteWarnings_Alerts(t *testing.T) {
+	got := componentPrerequisiteWarnings(&daemon.StatusResponse{
+		Alerts: map[string]daemonkit.StatusError{
+			daemon.AlertClusterCertificates: {
+				Reason:     "ClusterCertificateExpiring",
+				Message:    "apiserver.crt expires in 12 day(s)",
+				Resolution: "sudo solo-provisioner kube cluster certs renew",
+			},
+		},
+	})
+	assert.Contains(t, got, "[ALERT] cluster-certificates (ClusterCertificateExpiring): apiserver.crt expires in 12 day(s)")
+	assert.Contains(t, got, "Resolution: sudo solo-provisioner kube cluster certs renew")
+
+	assert.Empty(t, componentPrerequisiteWarnings(&daemon.StatusResponse{}))
+}
@@ -89,7 +89,7 @@ func installESOChart(ctx context.Context, hm helm.Manager, spec *helmChartSpec)
 		return false, err
 	}
 
-	localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum)
+	localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum, helm.WithProvenance(spec.Provenance))
 	if err != nil {
 		return false, err
 	}
@@ -28,7 +28,7 @@ func Test_installESOChart_FreshInstall(t *testing.T) {
 	hm.EXPECT().IsInstalled(spec.Release, spec.Namespace).Return(false, nil)
 	hm.EXPECT().AddRepo(spec.RepoAlias, spec.Repo, gomock.Any()).Return(nil, nil)
 	hm.EXPECT().
-		PullAndVerify(gomock.Any(), gomock.Any(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum).
+		PullAndVerify(gomock.Any(), gomock.Any(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum, gomock.Any()).
 		Return(localChart, nil)
 	hm.EXPECT().
 		InstallChart(gomock.Any(), spec.Release, localChart, "", spec.Namespace, gomock.Any()).
@@ -78,7 +78,7 @@ func Test_installESOChart_NamespaceOverride(t *testing.T) {
 	hm.EXPECT().IsInstalled(spec.Release, customNS).Return(false, nil)
 	hm.EXPECT().AddRepo(spec.RepoAlias, spec.Repo, gomock.Any()).Return(nil, nil)
 	hm.EXPECT().
-		PullAndVerify(gomock.Any(), gomock.Any(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum).
+		PullAndVerify(gomock.Any(), gomock.Any(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum, gomock.Any()).
 		Return(localChart, nil)
 	hm.EXPECT().
 		InstallChart(gomock.Any(), spec.Release, localChart, "", customNS, gomock.Any()).
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/automa/automa_steps"
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// apiServerRestartTimeout bounds the wait for the API server to answer again
+// once its static pod has been restarted on the renewed certificates.
+const apiServerRestartTimeout = 3 * time.Minute
+
+// RenewClusterCertificates renews the kubeadm cluster's leaf certificates and
+// the kubeconfigs embedding them through the sandboxed kubeadm, using the
+// kubeadm-init.yaml the cluster was initialised with so the certificates keep
+// its certificatesDir and validity period. The control-plane static pods only
+// read their certificates at start, so the step restarts them, waits for the
+// API server to answer and then refreshes the kubeconfig copies
+// KubeConfigManager keeps for root, the sudo user and the weaver account.
+//
+// The CAs are not renewed: kubeadm issues them for ten years and rotating
+// them is a manual procedure.
+func RenewClusterCertificates() *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("renew-cluster-certificates").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Renewing Kubernetes cluster certificates")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to renew Kubernetes cluster certificates")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Kubernetes cluster certificates renewed successfully")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			// Verify the kubeadm and crictl binaries before executing them.
+			if err := verifyExecutables("kubeadm"); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			if err := verifyExecutables(software.CrioArtifactName); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			// Step 1: renew every leaf certificate and the kubeconfigs embedding one
+			renewCmd := []string{
+				fmt.Sprintf("sudo %s/kubeadm certs renew all --config %s/etc/weaver/kubeadm-init.yaml", models.Paths().SandboxBinDir, models.Paths().SandboxDir),
+			}
+			if _, err := automa_steps.RunBashScript(renewCmd, ""); err != nil {
+				return automa.FailureReport(stp, automa.WithError(errorx.ExternalError.Wrap(err, "failed to renew cluster certificates with kubeadm")))
+			}
+
+			// Step 2: restart the control-plane static pods; the kubelet recreates
+			// them from their manifests and they load the renewed certificates
+			logx.As().Info().Msg("Restarting control-plane pods to load the renewed certificates...")
+			crictl := fmt.Sprintf("sudo %s/crictl --runtime-endpoint unix://%s/var/run/crio/crio.sock",
+				models.Paths().SandboxLocalBinDir, models.Paths().SandboxDir)
+			restartCmd := []string{
+				fmt.Sprintf("%s pods --namespace kube-system --label tier=control-plane -q | xargs -r %s rmp --force", crictl, crictl),
+			}
+			if _, err := automa_steps.RunBashScript(restartCmd, ""); err != nil {
+				return automa.FailureReport(stp, automa.WithError(errorx.ExternalError.Wrap(err, "failed to restart the control-plane pods")))
+			}
+
+			// Step 3: wait for the API server to come back
+			if err := waitForAPIServer(ctx, apiServerRestartTimeout); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			// Step 4: refresh the kubeconfig copies from the renewed admin.conf
+			kubeConfigManager, err := kube.NewKubeConfigManager()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(errorx.InternalError.Wrap(err, "failed to create kubeconfig manager")))
+			}
+			if err := kubeConfigManager.Configure(); err != nil {
+				return automa.FailureReport(stp, automa.WithError(errorx.ExternalError.Wrap(err, "failed to refresh kubeconfig")))
+			}
+
+			return automa.SuccessReport(stp, automa.WithDetail("Cluster certificates renewed"))
+		})
+}
+
+// waitForAPIServer polls the API server through the admin kubeconfig until it
+// answers or timeout passes.
+func waitForAPIServer(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := runCmd(fmt.Sprintf("%s --kubeconfig /etc/kubernetes/admin.conf", kubectlGetNodesCmd))
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errorx.ExternalError.Wrap(err, "the API server did not answer within %s of the control-plane restart", timeout).
+				WithProperty(models.ErrPropertyResolution, []string{
+					"check the control-plane pods: sudo crictl pods --namespace kube-system",
+					"check the kubelet: sudo journalctl -u kubelet",
+				})
+		}
+		if err := Sleep(ctx, 5*time.Second); err != nil {
+			return err
+		}
+	}
+}
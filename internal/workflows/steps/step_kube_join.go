@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+	htime "helm.sh/helm/v3/pkg/time"
+
+	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/internal/kube/join"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// nodeReadyTimeout bounds the wait for a joined node to turn Ready, which
+// includes the Cilium agent starting on it.
+const nodeReadyTimeout = 10 * time.Minute
+
+// clusterJoiner joins the host to the cluster and undoes it; *join.Manager in
+// production.
+type clusterJoiner interface {
+	Join(ctx context.Context, b *join.Bundle, controlPlane bool) (string, error)
+	Reset(ctx context.Context) error
+}
+
+// The seams the join steps reach kubeadm, the cluster and the state file
+// through. Vars so tests can run the steps with a mocked kubeadm.
+var (
+	newClusterJoiner = func() clusterJoiner { return join.NewManager(models.Paths()) }
+
+	waitForNodeReady = func(ctx context.Context, node string, timeout time.Duration) error {
+		// The kubelet's kubeconfig is the only one a worker has; a node may
+		// read its own Node object with it.
+		kc, err := kube.NewClientForKubeconfig(join.KubeletKubeconfig)
+		if err != nil {
+			return err
+		}
+		return kc.WaitForResource(ctx, kube.KindNode, "", node, kube.IsNodeReady, timeout)
+	}
+
+	configureKubeconfig = func() error {
+		m, err := kube.NewKubeConfigManager()
+		if err != nil {
+			return err
+		}
+		return m.Configure()
+	}
+
+	persistClusterMembership = state.PersistClusterMembership
+	readClusterMembership    = state.ReadClusterMembershipFromDisk
+)
+
+// clusterRole returns the ClusterMembershipState role for a join.
+func clusterRole(controlPlane bool) string {
+	if controlPlane {
+		return state.ClusterRoleControlPlane
+	}
+	return state.ClusterRoleWorker
+}
+
+// JoinCluster joins the host to the cluster b was minted for as a worker or,
+// with controlPlane, as a control-plane node through `kubeadm join`, waits for
+// the node to turn Ready and records the membership in MachineState.Cluster.
+// A control-plane node also gets the kubeconfig copies `kube cluster install`
+// sets up.
+//
+// Its rollback runs `kubeadm reset`, which removes the node's kubelet
+// configuration and, on a control-plane node, its etcd member, and clears the
+// membership record. The Node object stays in the cluster until it is deleted
+// there with kubectl.
+func JoinCluster(b *join.Bundle, controlPlane bool) *automa.StepBuilder {
+	role := clusterRole(controlPlane)
+	return automa.NewStepBuilder().WithId("join-cluster").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, fmt.Sprintf("Joining the Kubernetes cluster at %s as a %s node", b.Endpoint, role))
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to join the Kubernetes cluster")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, fmt.Sprintf("Joined the Kubernetes cluster as a %s node", role))
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			// Verify the kubeadm binary before executing it.
+			if err := verifyExecutables("kubeadm"); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			joiner := newClusterJoiner()
+			node, err := joiner.Join(ctx, b, controlPlane)
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			// kubeadm may have joined partway, so the rollback has to reset.
+			stp.State().Local().Set(InstalledByThisStep, true)
+
+			if controlPlane {
+				if err := configureKubeconfig(); err != nil {
+					return automa.FailureReport(stp, automa.WithError(errorx.ExternalError.Wrap(err, "failed to configure kubeconfig")))
+				}
+			}
+
+			logx.As().Info().Str("node", node).Msg("Waiting for the node to become Ready...")
+			if err := waitForNodeReady(ctx, node, nodeReadyTimeout); err != nil {
+				return automa.FailureReport(stp, automa.WithError(errorx.IllegalState.Wrap(err, "node %s did not become Ready", node).
+					WithProperty(models.ErrPropertyResolution, []string{
+						"check the kubelet on this host: sudo journalctl -u kubelet",
+						"check that the Cilium agent runs on the node: kubectl -n kube-system get pods -o wide -l k8s-app=cilium",
+					})))
+			}
+
+			if err := persistClusterMembership(&state.ClusterMembershipState{
+				Role:       role,
+				NodeName:   node,
+				Endpoint:   b.Endpoint,
+				CACertHash: b.CACertHash,
+				Since:      htime.Now(),
+			}); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			return automa.SuccessReport(stp, automa.WithDetail(fmt.Sprintf("%s (%s) joined %s", node, role, b.Endpoint)))
+		}).
+		WithRollback(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if v, ok := stp.State().Local().Bool(InstalledByThisStep); !ok || !v {
+				return automa.SkippedReport(stp, automa.WithDetail("the host did not join the cluster in this step, skipping rollback"))
+			}
+
+			if err := newClusterJoiner().Reset(ctx); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			if err := persistClusterMembership(nil); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			return automa.SuccessReport(stp)
+		})
+}
+
+// RecordClusterMembership records on the host that initialised the cluster
+// that it is its first control-plane node, identified by the endpoint and CA
+// hash a join bundle minted on it carries. A host that already has a record,
+// e.g. a control-plane node that joined the cluster, keeps it.
+func RecordClusterMembership() *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("record-cluster-membership").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Recording cluster membership")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to record cluster membership")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Cluster membership recorded")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			recorded, err := readClusterMembership()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			if recorded != nil {
+				return automa.SkippedReport(stp, automa.WithDetail(
+					fmt.Sprintf("already recorded as a %s node of %s", recorded.Role, recorded.Endpoint)))
+			}
+
+			m := join.NewManager(models.Paths())
+			cfg, err := m.ClusterConfig()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			hash, err := m.CACertHash()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			hostname, err := os.Hostname()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(errorx.IllegalState.Wrap(err, "failed to get hostname")))
+			}
+
+			if err := persistClusterMembership(&state.ClusterMembershipState{
+				Role:        state.ClusterRoleControlPlane,
+				NodeName:    hostname,
+				Endpoint:    cfg.Endpoint,
+				CACertHash:  hash,
+				Initialized: true,
+				Since:       htime.Now(),
+			}); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			return automa.SuccessReport(stp, automa.WithDetail(fmt.Sprintf("control-plane node %s of %s", hostname, cfg.Endpoint)))
+		})
+}
+
+// ForgetClusterMembership clears the membership record once the host has
+// been reset out of the cluster.
+func ForgetClusterMembership() *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("forget-cluster-membership").
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if err := persistClusterMembership(nil); err != nil {
+				// Teardown is best-effort; a stale record is corrected by the next install or join.
+				logx.As().Warn().Err(err).Msg("Failed to clear the cluster membership record")
+			}
+			return automa.SuccessReport(stp)
+		})
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/kube/join"
+	"github.com/hashgraph/solo-weaver/internal/state"
+)
+
+// fakeJoiner records the joins and resets the join step asks for.
+type fakeJoiner struct {
+	calls *[]string
+}
+
+func (f fakeJoiner) Join(_ context.Context, b *join.Bundle, controlPlane bool) (string, error) {
+	*f.calls = append(*f.calls, "join "+b.Endpoint+" "+clusterRole(controlPlane))
+	return "node-2", nil
+}
+
+func (f fakeJoiner) Reset(context.Context) error {
+	*f.calls = append(*f.calls, "reset")
+	return nil
+}
+
+// stubJoinSeams replaces kubeadm, the cluster and the state file with
+// recorders; ready is what the wait for the node returns.
+func stubJoinSeams(t *testing.T, calls *[]string, recorded **state.ClusterMembershipState, ready error) {
+	t.Helper()
+	origJoiner, origWait, origKubeconfig, origPersist := newClusterJoiner, waitForNodeReady, configureKubeconfig, persistClusterMembership
+	t.Cleanup(func() {
+		newClusterJoiner, waitForNodeReady, configureKubeconfig, persistClusterMembership = origJoiner, origWait, origKubeconfig, origPersist
+	})
+	newClusterJoiner = func() clusterJoiner { return fakeJoiner{calls: calls} }
+	waitForNodeReady = func(_ context.Context, node string, _ time.Duration) error {
+		*calls = append(*calls, "wait "+node)
+		return ready
+	}
+	configureKubeconfig = func() error {
+		*calls = append(*calls, "kubeconfig")
+		return nil
+	}
+	persistClusterMembership = func(m *state.ClusterMembershipState, _ ...state.ManagerOption) error {
+		*recorded = m
+		return nil
+	}
+	stubVerifyExecutables(t, func(string) error { return nil })
+}
+
+func testJoinBundle() *join.Bundle {
+	return &join.Bundle{Endpoint: "10.0.0.1:6443", CACertHash: "sha256:abc"}
+}
+
+func TestJoinCluster_RecordsMembership(t *testing.T) {
+	for _, tt := range []struct {
+		controlPlane bool
+		want         []string
+	}{
+		{controlPlane: false, want: []string{"join 10.0.0.1:6443 worker", "wait node-2"}},
+		{controlPlane: true, want: []string{"join 10.0.0.1:6443 control-plane", "kubeconfig", "wait node-2"}},
+	} {
+		var calls []string
+		var recorded *state.ClusterMembershipState
+		stubJoinSeams(t, &calls, &recorded, nil)
+
+		step, err := JoinCluster(testJoinBundle(), tt.controlPlane).Build()
+		require.NoError(t, err)
+		report := step.Execute(context.Background())
+		require.NoError(t, report.Error)
+		assert.Equal(t, tt.want, calls)
+
+		require.NotNil(t, recorded)
+		assert.Equal(t, clusterRole(tt.controlPlane), recorded.Role)
+		assert.Equal(t, "node-2", recorded.NodeName)
+		assert.Equal(t, "10.0.0.1:6443", recorded.Endpoint)
+		assert.Equal(t, "sha256:abc", recorded.CACertHash)
+		assert.False(t, recorded.Initialized)
+	}
+}
+
+func TestJoinCluster_NotReadyRollsBack(t *testing.T) {
+	var calls []string
+	var recorded *state.ClusterMembershipState
+	stubJoinSeams(t, &calls, &recorded, errors.New("timeout"))
+
+	step, err := JoinCluster(testJoinBundle(), false).Build()
+	require.NoError(t, err)
+	report := step.Execute(context.Background())
+	require.ErrorContains(t, report.Error, "node node-2 did not become Ready")
+	assert.Nil(t, recorded, "a node that never turned Ready is not recorded")
+
+	report = step.Rollback(context.Background())
+	require.NoError(t, report.Error)
+	assert.Equal(t, []string{"join 10.0.0.1:6443 worker", "wait node-2", "reset"}, calls)
+	assert.Nil(t, recorded)
+}
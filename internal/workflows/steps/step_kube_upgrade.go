@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/automa/automa_steps"
+	"github.com/automa-saga/logx"
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/internal/kube"
+	"github.com/hashgraph/solo-weaver/internal/kube/upgrade"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	pkgos "github.com/hashgraph/solo-weaver/pkg/os"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// The seams the component upgrade steps record versions and restart services
+// through. Vars so tests can run the steps without a state file or systemd.
+var (
+	persistSoftwareVersion = state.PersistSoftwareVersion
+	stopHostService        = pkgos.StopService
+	startHostService       = pkgos.StartService
+	reloadSystemd          = pkgos.DaemonReload
+)
+
+// upgradeServices maps the components that run as a systemd service to it.
+var upgradeServices = map[string]string{
+	software.KubeletBinaryName: software.KubeletServiceName,
+	software.CrioBinaryName:    software.CrioServiceName,
+}
+
+// UpgradeClusterComponent replaces the host component c's binaries with
+// version c.To through its installer, which downloads them with checksum
+// verification, and records the new version in MachineState.Software right
+// away. A component that runs as a service (the kubelet, CRI-O) is stopped
+// while its binaries are replaced and started again afterwards.
+//
+// Its rollback reinstalls c.From the same way, so a failed upgrade leaves the
+// host running the previous binaries.
+func UpgradeClusterComponent(c upgrade.Component) *automa.StepBuilder {
+	var installerAt func(version string) (software.Software, error)
+	if provider, ok := software.Installers()[c.Key]; ok {
+		installerAt = func(version string) (software.Software, error) {
+			// kubeadm re-renders kubeadm-init.yaml; keep the cluster's layout in it.
+			cluster, err := effectiveClusterConfig()
+			if err != nil {
+				return nil, err
+			}
+			return provider(software.WithVersion(version), software.WithClusterConfig(cluster))
+		}
+	}
+	return upgradeClusterComponent(c, installerAt, upgradeServices[c.Key])
+}
+
+func upgradeClusterComponent(c upgrade.Component, installerAt func(version string) (software.Software, error), service string) *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("upgrade-" + c.Key).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, fmt.Sprintf("Upgrading %s from %s to %s", c.Artifact, c.From, c.To))
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, fmt.Sprintf("Failed to upgrade %s", c.Artifact))
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, fmt.Sprintf("%s upgraded to %s", c.Artifact, c.To))
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if installerAt == nil {
+				return automa.FailureReport(stp, automa.WithError(
+					errorx.IllegalArgument.New("no installer for %s", c.Key)))
+			}
+
+			// Stage the new version before touching the running one, so a failed
+			// download leaves the host as it was.
+			installer, err := stageComponent(installerAt, c.To)
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			// From here on the previous binaries may be partly replaced, so the
+			// rollback has to reinstall them.
+			stp.State().Local().Set(InstalledByThisStep, true)
+			if err := replaceComponent(ctx, c, installer, c.To, service); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			return automa.SuccessReport(stp, automa.WithDetail(fmt.Sprintf("%s %s -> %s", c.Artifact, c.From, c.To)))
+		}).
+		WithRollback(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if v, ok := stp.State().Local().Bool(InstalledByThisStep); !ok || !v {
+				return automa.SkippedReport(stp, automa.WithDetail(c.Artifact+" was not replaced by this step, skipping rollback"))
+			}
+
+			installer, err := stageComponent(installerAt, c.From)
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			if err := replaceComponent(ctx, c, installer, c.From, service); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			logx.As().Info().Str("software", c.Artifact).Str("version", c.From).Msg("Rolled back to the previous binaries")
+			return automa.SuccessReport(stp)
+		})
+}
+
+// stageComponent downloads and extracts version through the component's
+// installer; both verify the catalog checksums.
+func stageComponent(installerAt func(version string) (software.Software, error), version string) (software.Software, error) {
+	installer, err := installerAt(version)
+	if err != nil {
+		return nil, err
+	}
+	if err := installer.Download(); err != nil {
+		return nil, err
+	}
+	if err := installer.Extract(); err != nil {
+		return nil, err
+	}
+	return installer, nil
+}
+
+// replaceComponent installs the staged binaries over the installed ones,
+// records version and, for a service, restarts it on the new binaries once
+// they pass the time-of-use checksum check.
+func replaceComponent(ctx context.Context, c upgrade.Component, installer software.Software, version, service string) error {
+	// A running binary cannot be overwritten in place.
+	if service != "" {
+		if err := stopHostService(ctx, service); err != nil {
+			return err
+		}
+	}
+
+	if err := installer.Install(); err != nil {
+		return err
+	}
+	if err := installer.Configure(); err != nil {
+		return err
+	}
+	if err := persistSoftwareVersion(c.Key, c.Artifact, version); err != nil {
+		return err
+	}
+	if err := installer.Cleanup(); err != nil {
+		logx.As().Warn().Err(err).Str("software", c.Artifact).Msg("Failed to clean up the staged binaries")
+	}
+
+	if service == "" {
+		return nil
+	}
+	if err := verifyExecutables(c.Artifact); err != nil {
+		return err
+	}
+	if err := reloadSystemd(ctx); err != nil {
+		return err
+	}
+	return startHostService(ctx, service)
+}
+
+// UpgradeControlPlane upgrades the control plane to plan.To with the sandboxed
+// kubeadm, which must already be at that version: `kubeadm upgrade plan`
+// checks that the cluster can be upgraded and `kubeadm upgrade apply` upgrades
+// the control-plane static pods and etcd one by one, then the step waits for
+// the API server to answer.
+//
+// The step has no rollback: kubeadm restores the previous static pod manifests
+// itself when apply fails, and once it has succeeded going back means
+// restoring the cluster backup taken before the upgrade.
+func UpgradeControlPlane(plan *upgrade.Plan) *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("upgrade-control-plane").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, fmt.Sprintf("Upgrading the Kubernetes control plane to %s", plan.To))
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to upgrade the Kubernetes control plane")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, fmt.Sprintf("Kubernetes control plane upgraded to %s", plan.To))
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			// Verify the kubeadm binary before executing it.
+			if err := verifyExecutables("kubeadm"); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			kubeadm := fmt.Sprintf("sudo %s/kubeadm", models.Paths().SandboxBinDir)
+
+			// Step 1: let kubeadm check the upgrade path and the cluster's health
+			planCmd := []string{fmt.Sprintf("%s upgrade plan v%s", kubeadm, plan.To)}
+			out, err := automa_steps.RunBashScript(planCmd, "")
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(errorx.ExternalError.Wrap(err, "kubeadm upgrade plan failed")))
+			}
+			logx.As().Info().Msg(out)
+
+			// Step 2: upgrade the control plane
+			logx.As().Info().Msg("Upgrading the control plane, this may take a while...")
+			applyCmd := []string{fmt.Sprintf("%s upgrade apply v%s --yes --v=3", kubeadm, plan.To)}
+			if _, err := automa_steps.RunBashScript(applyCmd, ""); err != nil {
+				return automa.FailureReport(stp, automa.WithError(errorx.ExternalError.Wrap(err, "kubeadm upgrade apply failed").
+					WithProperty(models.ErrPropertyResolution, []string{
+						"kubeadm restores the previous control-plane manifests when apply fails; check them with: sudo crictl pods --namespace kube-system",
+						"if the control plane does not recover, restore the pre-upgrade backup: sudo solo-provisioner kube cluster restore --from <backup>",
+					})))
+			}
+
+			// Step 3: wait for the API server on the new version
+			if err := waitForAPIServer(ctx, apiServerRestartTimeout); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			return automa.SuccessReport(stp, automa.WithDetail(fmt.Sprintf("%s -> %s", plan.From, plan.To)))
+		})
+}
+
+// CheckCiliumCompatibility checks that the Cilium release running in the
+// cluster supports the Kubernetes version the API server now reports.
+func CheckCiliumCompatibility() *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("check-cilium-compatibility").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Checking Cilium compatibility")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Cilium is not compatible with the cluster's Kubernetes version")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Cilium is compatible with the cluster's Kubernetes version")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			info, err := kube.RetrieveClusterInfo()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			kc, err := kube.NewClient()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			cilium, err := upgrade.LiveCiliumVersion(ctx, kc)
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			if err := upgrade.CheckCilium(cilium, info.ServerVersion.GitVersion); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			return automa.SuccessReport(stp, automa.WithDetail(
+				fmt.Sprintf("Cilium %s, Kubernetes %s", cilium, info.ServerVersion.GitVersion)))
+		})
+}
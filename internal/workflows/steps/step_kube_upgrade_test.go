@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/internal/kube/upgrade"
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// fakeUpgradeInstaller satisfies software.Software by embedding the interface
+// (nil) and recording the calls the component upgrade step makes.
+type fakeUpgradeInstaller struct {
+	software.Software
+	version string
+	calls   *[]string
+	fail    string
+}
+
+func (f fakeUpgradeInstaller) do(call string) error {
+	*f.calls = append(*f.calls, call+" "+f.version)
+	if call == f.fail {
+		return errors.New(call + " failed")
+	}
+	return nil
+}
+
+func (f fakeUpgradeInstaller) Download() error  { return f.do("download") }
+func (f fakeUpgradeInstaller) Extract() error   { return f.do("extract") }
+func (f fakeUpgradeInstaller) Install() error   { return f.do("install") }
+func (f fakeUpgradeInstaller) Configure() error { return f.do("configure") }
+func (f fakeUpgradeInstaller) Cleanup() error   { return f.do("cleanup") }
+
+// stubUpgradeSeams replaces the state and systemd seams and records their calls
+// in the same log as the installer.
+func stubUpgradeSeams(t *testing.T, calls *[]string) {
+	t.Helper()
+	origPersist, origStop, origStart, origReload := persistSoftwareVersion, stopHostService, startHostService, reloadSystemd
+	t.Cleanup(func() {
+		persistSoftwareVersion, stopHostService, startHostService, reloadSystemd = origPersist, origStop, origStart, origReload
+	})
+	persistSoftwareVersion = func(component, name, version string, _ ...state.ManagerOption) error {
+		*calls = append(*calls, "record "+component+"="+version)
+		return nil
+	}
+	stopHostService = func(_ context.Context, name string) error {
+		*calls = append(*calls, "stop "+name)
+		return nil
+	}
+	startHostService = func(_ context.Context, name string) error {
+		*calls = append(*calls, "start "+name)
+		return nil
+	}
+	reloadSystemd = func(context.Context) error { return nil }
+	stubVerifyExecutables(t, func(string) error { return nil })
+}
+
+func TestUpgradeClusterComponent_ReplacesAndRollsBack(t *testing.T) {
+	var calls []string
+	stubUpgradeSeams(t, &calls)
+	installerAt := func(version string) (software.Software, error) {
+		return fakeUpgradeInstaller{version: version, calls: &calls}, nil
+	}
+	c := upgrade.Component{Key: "kubelet", Artifact: "kubelet", From: "1.32.8", To: "1.33.4"}
+
+	step, err := upgradeClusterComponent(c, installerAt, software.KubeletServiceName).Build()
+	require.NoError(t, err)
+	report := step.Execute(context.Background())
+	require.NoError(t, report.Error)
+	assert.Equal(t, []string{
+		"download 1.33.4", "extract 1.33.4",
+		"stop kubelet", "install 1.33.4", "configure 1.33.4", "record kubelet=1.33.4", "cleanup 1.33.4",
+		"start kubelet",
+	}, calls, "the new version is staged before the service stops and recorded before it starts")
+
+	calls = calls[:0]
+	calls = append(calls, "rollback")
+	report = step.Rollback(context.Background())
+	require.NoError(t, report.Error)
+	assert.Equal(t, []string{
+		"rollback",
+		"download 1.32.8", "extract 1.32.8",
+		"stop kubelet", "install 1.32.8", "configure 1.32.8", "record kubelet=1.32.8", "cleanup 1.32.8",
+		"start kubelet",
+	}, calls, "the rollback reinstalls the previous version the same way")
+}
+
+func TestUpgradeClusterComponent_FailedStagingLeavesHostAlone(t *testing.T) {
+	var calls []string
+	stubUpgradeSeams(t, &calls)
+	installerAt := func(version string) (software.Software, error) {
+		return fakeUpgradeInstaller{version: version, calls: &calls, fail: "download"}, nil
+	}
+	c := upgrade.Component{Key: "crio", Artifact: "cri-o", From: "1.32.7", To: "1.33.4"}
+
+	step, err := upgradeClusterComponent(c, installerAt, software.CrioServiceName).Build()
+	require.NoError(t, err)
+	report := step.Execute(context.Background())
+	require.ErrorContains(t, report.Error, "download failed")
+	assert.Equal(t, []string{"download 1.33.4"}, calls, "nothing is stopped before the new version is staged")
+
+	report = step.Rollback(context.Background())
+	require.NoError(t, report.Error)
+	assert.Equal(t, []string{"download 1.33.4"}, calls, "there is nothing to roll back")
+}
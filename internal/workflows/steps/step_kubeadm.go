@@ -118,7 +118,12 @@ func configureKubeadm(provider func(opts ...software.InstallerOption) (software.
 			return ctx, nil
 		}).
 		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
-			installer, err := provider(software.WithMachineRuntime(mr))
+			cluster, err := effectiveClusterConfig()
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+
+			installer, err := provider(software.WithMachineRuntime(mr), software.WithClusterConfig(cluster))
 			if err != nil {
 				return automa.FailureReport(stp,
 					automa.WithError(err))
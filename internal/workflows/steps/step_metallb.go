@@ -16,6 +16,7 @@ import (
 	"github.com/hashgraph/solo-weaver/internal/network"
 	"github.com/hashgraph/solo-weaver/internal/templates"
 	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/config"
 	"github.com/hashgraph/solo-weaver/pkg/helm"
 	"helm.sh/helm/v3/pkg/cli/values"
 )
@@ -82,7 +83,7 @@ func installMetalLB() automa.Builder {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
 
-			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum)
+			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum, helm.WithProvenance(spec.Provenance))
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
@@ -153,11 +154,12 @@ func prepareMetalLBConfigFile(configFilePath string) automa.Builder {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
 
-			tmplData := templates.MetallbData{
-				MachineIP: machineIp,
+			cluster, err := effectiveClusterConfig()
+			if err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
 
-			rendered, err := templates.Render(MetalLBTemplatePath, tmplData)
+			rendered, err := templates.Render(MetalLBTemplatePath, metalLBData(cluster.LoadBalancer, machineIp))
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
@@ -188,7 +190,9 @@ func deployMetalLBConfig(configFilePath string) automa.Builder {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
 
-			err = k.ApplyManifest(ctx, configFilePath)
+			err = k.ApplyManifest(ctx, configFilePath,
+				kube.WithForceConflicts(config.Get().Kube.ForceConflicts()),
+				kube.WithRecord(kube.ManifestMetalLBConfig))
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
@@ -212,6 +216,9 @@ func deployMetalLBConfig(configFilePath string) automa.Builder {
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
+			if err := kube.ForgetManifest(kube.ManifestMetalLBConfig); err != nil {
+				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+			}
 
 			return automa.StepSuccessReport(stp.Id())
 		}).
@@ -64,7 +64,7 @@ func installMetricsServer(valueOptions *values.Options) *automa.StepBuilder {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
 
-			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum)
+			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum, helm.WithProvenance(spec.Provenance))
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
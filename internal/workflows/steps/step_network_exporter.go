@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+
+	"github.com/automa-saga/automa"
+	"github.com/automa-saga/logx"
+	"github.com/hashgraph/solo-weaver/internal/network/exporter"
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/joomcode/errorx"
+)
+
+const (
+	// NetworkExporterSetupStepId is the step ID for NetworkExporterSetup.
+	NetworkExporterSetupStepId = "network-exporter-setup"
+	// NetworkExporterTeardownStepId is the step ID for NetworkExporterTeardown.
+	NetworkExporterTeardownStepId = "network-exporter-teardown"
+)
+
+// NetworkExporterSetup installs, enables and starts the
+// solo-provisioner-network-exporter.service unit, which serves the tc class and
+// nft policy counters on the node IP for the Alloy traffic-shaper module to
+// scrape. It runs wherever traffic shaping is enabled, after the shaping and
+// policy state it reports on is in place. Idempotent: an unchanged, running
+// unit is left alone, and rollback only removes a unit this step changed.
+func NetworkExporterSetup() *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId(NetworkExporterSetupStepId).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Starting network exporter service")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to start network exporter service")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "network exporter service running")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			meta := map[string]string{}
+			changed, err := exporter.EnsureExporterService(ctx)
+			if changed {
+				meta[ConfiguredByThisStep] = "true"
+				stp.State().Local().Set(ConfiguredByThisStep, true)
+			}
+			if err != nil {
+				return automa.FailureReport(stp, automa.WithMetadata(meta), automa.WithError(
+					errorx.Decorate(err, "failed to start the network exporter service").
+						WithProperty(models.ErrPropertyResolution, []string{
+							"Inspect the unit: systemctl status " + exporter.ExporterService,
+							"Check its logs: journalctl -u " + exporter.ExporterService,
+						})))
+			}
+			if !changed {
+				meta[AlreadyConfigured] = "true"
+			}
+			logx.As().Info().Str("service", exporter.ExporterService).
+				Msg("network exporter service running")
+			return automa.SuccessReport(stp, automa.WithMetadata(meta))
+		}).
+		WithRollback(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if v, ok := stp.State().Local().Bool(ConfiguredByThisStep); !ok || !v {
+				return automa.SkippedReport(stp, automa.WithDetail("network exporter service was not changed by this step, skipping rollback"))
+			}
+			if err := exporter.RemoveExporterService(ctx); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			return automa.SuccessReport(stp)
+		})
+}
+
+// NetworkExporterTeardown stops, disables and removes the
+// solo-provisioner-network-exporter.service unit. It runs when traffic shaping
+// goes away from the host — the last block node uninstalled, shaping disabled
+// on reconfigure, or weaver itself uninstalled. The step is idempotent: an
+// already-absent unit is not an error.
+func NetworkExporterTeardown() *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId(NetworkExporterTeardownStepId).
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Removing network exporter service")
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to remove network exporter service")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "network exporter service removed")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if err := exporter.RemoveExporterService(ctx); err != nil {
+				return automa.FailureReport(stp, automa.WithError(
+					errorx.Decorate(err, "failed to remove the network exporter service").
+						WithProperty(models.ErrPropertyResolution, []string{
+							"Disable manually: systemctl disable --now " + exporter.ExporterService,
+							"Remove the unit file: rm " + exporter.ExporterServiceUnitPath,
+							"Then reload systemd: systemctl daemon-reload",
+						})))
+			}
+			logx.As().Info().Str("service", exporter.ExporterService).
+				Msg("network exporter service removed")
+			return automa.SuccessReport(stp)
+		}).
+		WithRollback(func(ctx context.Context, stp automa.Step) *automa.Report {
+			return automa.SkippedReport(stp,
+				automa.WithDetail("network exporter teardown rollback is a no-op; re-enable via block node install"))
+		})
+}
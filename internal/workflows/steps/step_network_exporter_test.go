@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/automa-saga/automa"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkExporterSetup_RollbackSkipsUnchangedService(t *testing.T) {
+	step, err := NetworkExporterSetup().Build()
+	require.NoError(t, err)
+
+	// Execute never ran (or left the unit alone), so there is nothing this
+	// step changed to undo.
+	report := step.Rollback(context.Background())
+	require.NoError(t, report.Error)
+	require.Equal(t, automa.StatusSkipped, report.Status)
+}
@@ -147,7 +147,12 @@ func (c canonicalPolicy) toPolicy(healthPort string) *policy.Policy {
 // blocknode.ResolveHealthPort against the operator's effective values), used for
 // bn-health so the port solo-weaver drops tracks the port the BN actually
 // listens on rather than a value baked into solo-weaver.
-func NetworkPolicyCreate(force bool, healthPort string) *automa.StepBuilder {
+// instance names the block node instance the plane is for (see
+// models.BlockNodeInstance): every policy takes that instance's name (see
+// models.BlockNodePolicyName), so the planes of several block nodes on one host
+// sit side by side in the table. Obsolete policies are only ever the default
+// instance's.
+func NetworkPolicyCreate(force bool, healthPort, instance string) *automa.StepBuilder {
 	return automa.NewStepBuilder().WithId(NetworkPolicyCreateStepId).
 		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
 			notify.As().StepStart(ctx, stp, "Creating network policies (inet weaver-workload-policy)")
@@ -182,6 +187,7 @@ func NetworkPolicyCreate(force bool, healthPort string) *automa.StepBuilder {
 			mgr := newPolicyManager()
 			var created []string
 			for _, c := range canonicalBNPolicies {
+				c.name = models.BlockNodePolicyName(instance, c.name)
 				// Record for rollback only policies that did not already exist:
 				// Manager.Create also returns changed=true when it replaces a
 				// pre-existing policy (--force) or self-heals a missing live table
@@ -221,6 +227,9 @@ func NetworkPolicyCreate(force bool, healthPort string) *automa.StepBuilder {
 			// keep orphaned sets alive. Idempotent: absent policies are skipped,
 			// so a fresh install (where they never existed) is a no-op.
 			for _, name := range obsoleteBNPolicies {
+				if instance != "" {
+					break
+				}
 				exists, err := policy.Exists(name)
 				if err != nil {
 					stp.State().Local().Set(policyCreatedNamesKey, created)
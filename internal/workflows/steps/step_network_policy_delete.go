@@ -27,7 +27,10 @@ const NetworkPolicyDeleteAllStepId = "network-policy-delete-all"
 // without that policy; deleting the last remaining policy tears the whole `inet
 // weaver` table down and removes the persisted network-weaver-workload-policy.nft, so no
 // separate NftWeaverPersist is needed on the disable path.
-func NetworkPolicyDeleteAll() *automa.StepBuilder {
+//
+// Only the policies of the given block node instance are deleted (see
+// NetworkPolicyCreate); another instance's plane on the same host stays.
+func NetworkPolicyDeleteAll(instance string) *automa.StepBuilder {
 	return automa.NewStepBuilder().WithId(NetworkPolicyDeleteAllStepId).
 		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
 			notify.As().StepStart(ctx, stp, "Removing network policies (inet weaver-workload-policy)")
@@ -43,6 +46,7 @@ func NetworkPolicyDeleteAll() *automa.StepBuilder {
 			mgr := newPolicyManager()
 			var deleted int
 			for _, c := range canonicalBNPolicies {
+				c.name = models.BlockNodePolicyName(instance, c.name)
 				exists, err := policy.Exists(c.name)
 				if err != nil {
 					return automa.FailureReport(stp, automa.WithError(
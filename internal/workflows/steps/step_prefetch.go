@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"strings"
+
+	"github.com/automa-saga/automa"
+
+	"github.com/hashgraph/solo-weaver/internal/workflows/notify"
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+// prefetchHostArtifacts is a package var so tests can stub the downloads.
+var prefetchHostArtifacts = software.PrefetchHostArtifacts
+
+// PrefetchArtifacts downloads every file the named host artifacts need in
+// parallel, ahead of the steps that install them one at a time. Those steps
+// then find their downloads in place and verified.
+func PrefetchArtifacts(mr software.MachineRuntime, names ...string) *automa.StepBuilder {
+	return automa.NewStepBuilder().WithId("prefetch-artifacts").
+		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
+			notify.As().StepStart(ctx, stp, "Downloading "+strings.Join(names, ", "))
+			return ctx, nil
+		}).
+		WithOnFailure(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepFailure(ctx, stp, rpt, "Failed to download artifacts")
+		}).
+		WithOnCompletion(func(ctx context.Context, stp automa.Step, rpt *automa.Report) {
+			notify.As().StepCompletion(ctx, stp, rpt, "Artifacts downloaded")
+		}).
+		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
+			if err := prefetchHostArtifacts(ctx, mr, names...); err != nil {
+				return automa.FailureReport(stp, automa.WithError(err))
+			}
+			return automa.SuccessReport(stp)
+		})
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package steps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/automa-saga/automa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/pkg/software"
+)
+
+func stubPrefetchHostArtifacts(t *testing.T, err error) *[]string {
+	t.Helper()
+	orig := prefetchHostArtifacts
+	requested := &[]string{}
+	prefetchHostArtifacts = func(_ context.Context, _ software.MachineRuntime, names ...string) error {
+		*requested = append(*requested, names...)
+		return err
+	}
+	t.Cleanup(func() { prefetchHostArtifacts = orig })
+	return requested
+}
+
+func TestPrefetchArtifacts(t *testing.T) {
+	requested := stubPrefetchHostArtifacts(t, nil)
+
+	step, err := PrefetchArtifacts(nil, "kubelet", "cri-o").Build()
+	require.NoError(t, err)
+
+	report := step.Execute(context.Background())
+	require.NoError(t, report.Error)
+	assert.Equal(t, automa.StatusSuccess, report.Status)
+	assert.Equal(t, []string{"kubelet", "cri-o"}, *requested)
+}
+
+func TestPrefetchArtifacts_FailsWhenDownloadFails(t *testing.T) {
+	stubPrefetchHostArtifacts(t, assert.AnError)
+
+	step, err := PrefetchArtifacts(nil, "kubeadm").Build()
+	require.NoError(t, err)
+
+	report := step.Execute(context.Background())
+	require.ErrorIs(t, report.Error, assert.AnError)
+	assert.Equal(t, automa.StatusFailed, report.Status)
+}
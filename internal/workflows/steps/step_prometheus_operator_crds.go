@@ -61,7 +61,7 @@ func installPrometheusOperatorCRDs() automa.Builder {
 				return automa.StepSuccessReport(stp.Id(), automa.WithMetadata(meta))
 			}
 
-			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum)
+			localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, spec.Version, spec.Algorithm, spec.Checksum, helm.WithProvenance(spec.Provenance))
 			if err != nil {
 				return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 			}
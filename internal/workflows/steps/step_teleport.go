@@ -61,11 +61,13 @@ func SetupTeleportNodeAgent(mr software.MachineRuntime) *automa.WorkflowBuilder
 // This provides secure, identity-aware access to the Kubernetes cluster with full audit logging.
 // All configuration including RBAC is provided via the Helm values file.
 // Used by 'solol-provisioner teleport cluster install' command.
-func SetupTeleportClusterAgent() *automa.WorkflowBuilder {
+// skipValuesValidation installs the values file without checking it against
+// the chart (--skip-values-validation).
+func SetupTeleportClusterAgent(skipValuesValidation bool) *automa.WorkflowBuilder {
 	return automa.NewWorkflowBuilder().WithId(SetupTeleportStepId).
 		Steps(
 			CreateTeleportNamespace(),
-			InstallTeleportKubeAgent(),
+			InstallTeleportKubeAgent(skipValuesValidation),
 			IsTeleportPodsReady(),
 		).
 		WithPrepare(func(ctx context.Context, stp automa.Step) (context.Context, error) {
@@ -454,7 +456,7 @@ func CreateTeleportNamespace() automa.Builder {
 		})
 }
 
-func InstallTeleportKubeAgent() automa.Builder {
+func InstallTeleportKubeAgent(skipValuesValidation bool) automa.Builder {
 	spec := chartSpec("teleport-cluster-agent")
 	return automa.NewStepBuilder().WithId(InstallTeleportStepId).
 		WithExecute(func(ctx context.Context, stp automa.Step) *automa.Report {
@@ -500,7 +502,16 @@ func InstallTeleportKubeAgent() automa.Builder {
 
 			var chartRef string
 			if expectedChecksum != "" {
-				localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, chartVersion, spec.Algorithm, expectedChecksum)
+				localChart, err := hm.PullAndVerify(ctx, chartDownloadsDir(), spec.Chart, chartVersion, spec.Algorithm, expectedChecksum, helm.WithProvenance(spec.Provenance))
+				if err != nil {
+					return automa.StepFailureReport(stp.Id(), automa.WithError(err))
+				}
+				chartRef = localChart
+				chartVersion = ""
+			} else if spec.Provenance.Enabled() {
+				// No checksum pins an operator-pinned version, but the chart's
+				// signature can still vouch for it.
+				localChart, err := hm.PullAndVerifyProvenance(ctx, chartDownloadsDir(), spec.Chart, chartVersion, spec.Provenance)
 				if err != nil {
 					return automa.StepFailureReport(stp.Id(), automa.WithError(err))
 				}
@@ -521,10 +532,12 @@ func InstallTeleportKubeAgent() automa.Builder {
 					ValueOpts: &values.Options{
 						ValueFiles: []string{cfg.ValuesFile},
 					},
-					CreateNamespace: true,
-					Atomic:          true,
-					Wait:            true,
-					Timeout:         helm.DefaultTimeout,
+					CreateNamespace:      true,
+					Atomic:               true,
+					Wait:                 true,
+					Timeout:              helm.DefaultTimeout,
+					SkipValuesValidation: skipValuesValidation,
+					RejectUnknownValues:  true,
 				},
 			)
 			if err != nil {
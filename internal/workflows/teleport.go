@@ -16,8 +16,8 @@ func NewTeleportNodeAgentInstallWorkflow(mr software.MachineRuntime) *automa.Wor
 
 // NewTeleportClusterAgentInstallWorkflow creates a workflow to install the Teleport Kubernetes cluster agent.
 // This provides secure kubectl access via Teleport with full audit logging.
-func NewTeleportClusterAgentInstallWorkflow() *automa.WorkflowBuilder {
-	return steps.SetupTeleportClusterAgent()
+func NewTeleportClusterAgentInstallWorkflow(skipValuesValidation bool) *automa.WorkflowBuilder {
+	return steps.SetupTeleportClusterAgent(skipValuesValidation)
 }
 
 // NewTeleportClusterAgentUninstallWorkflow creates a workflow to uninstall the Teleport Kubernetes cluster agent.
@@ -45,6 +45,7 @@ func NewSelfUninstallWorkflow() *automa.WorkflowBuilder {
 		steps.RemoveNetworkConfig(),
 		steps.NftServiceTeardown(),
 		steps.TcEgressServiceTeardown(),
+		steps.NetworkExporterTeardown(),
 		steps.RemoveSudoersStep(),
 		steps.UninstallWeaver(paths.BinDir),
 	)
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/sanity"
+)
+
+// ChecksumSuffix is appended to an archive's path to name its checksum file,
+// a single `sha256sum` line.
+const ChecksumSuffix = ".sha256"
+
+// Pack writes the bundle directory dir as the tar.gz archive at archivePath,
+// with its checksum file beside it, and returns the archive's SHA256.
+func Pack(dir, archivePath string) (string, error) {
+	out, err := os.OpenFile(archivePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, models.DefaultFilePerm)
+	if err != nil {
+		return "", errorx.InternalError.Wrap(err, "failed to create bundle archive %s", archivePath)
+	}
+	hasher := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(out, hasher))
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return fmt.Errorf("%s is neither a file nor a directory", p)
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return "", errorx.InternalError.Wrap(err, "failed to write bundle archive %s", archivePath)
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(archivePath))
+	if err := os.WriteFile(archivePath+ChecksumSuffix, []byte(line), models.DefaultFilePerm); err != nil {
+		return "", errorx.InternalError.Wrap(err, "failed to write bundle checksum %s", archivePath+ChecksumSuffix)
+	}
+	return sum, nil
+}
+
+// Unpack verifies the archive at archivePath against its checksum file,
+// extracts it into destDir, replacing what was there, and returns the
+// bundle once every file in it matches the manifest.
+func Unpack(archivePath, destDir string) (*Bundle, error) {
+	expected, err := readChecksumFile(archivePath + ChecksumSuffix)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := SHA256File(archivePath)
+	if err != nil {
+		return nil, ErrInvalid.Wrap(err, "failed to read bundle archive %s", archivePath)
+	}
+	if actual != expected {
+		return nil, ErrChecksumMismatch.New("bundle archive %s does not match %s: expected sha256=%s, got sha256=%s",
+			archivePath, archivePath+ChecksumSuffix, expected, actual).
+			WithProperty(models.ErrPropertyResolution, "the archive is truncated or was modified; copy it again with its .sha256 file")
+	}
+
+	// Extract beside destDir and swap it in only once the bundle verifies, so
+	// a failed install leaves the previous bundle in place.
+	if err := os.MkdirAll(filepath.Dir(destDir), models.DefaultDirOrExecPerm); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to create %s", filepath.Dir(destDir))
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(destDir), ".bundle-")
+	if err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to create a staging directory for bundle %s", archivePath)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	if err := extract(archivePath, staging); err != nil {
+		return nil, err
+	}
+	b, err := Open(staging)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Verify(); err != nil {
+		return nil, err
+	}
+
+	if err := os.RemoveAll(destDir); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to remove the previous bundle %s", destDir)
+	}
+	if err := os.Rename(staging, destDir); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to move bundle into %s", destDir)
+	}
+	if err := os.Chmod(destDir, models.DefaultDirOrExecPerm); err != nil {
+		return nil, errorx.InternalError.Wrap(err, "failed to set permissions of %s", destDir)
+	}
+	return Open(destDir)
+}
+
+// readChecksumFile returns the digest of a `sha256sum` checksum file.
+func readChecksumFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", ErrInvalid.Wrap(err, "bundle checksum file %s is not readable", path).
+			WithProperty(models.ErrPropertyResolution, "copy the .sha256 file `bundle create` wrote beside the archive")
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields) > 2 || len(fields[0]) != sha256.Size*2 {
+		return "", ErrInvalid.New("bundle checksum file %s is not a single sha256sum line", path)
+	}
+	if _, err := hex.DecodeString(fields[0]); err != nil {
+		return "", ErrInvalid.Wrap(err, "bundle checksum file %s is not hex-encoded", path)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// extract unpacks the tar.gz at archivePath into dir. Only regular files and
+// directories are accepted, and none may land outside dir.
+func extract(archivePath, dir string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return ErrInvalid.Wrap(err, "failed to open bundle archive %s", archivePath)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return ErrInvalid.Wrap(err, "bundle archive %s is not gzip-compressed", archivePath)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return ErrInvalid.Wrap(err, "failed to read bundle archive %s", archivePath)
+		}
+		target := filepath.Join(dir, hdr.Name)
+		if _, err := sanity.ValidatePathWithinBase(dir, target); err != nil {
+			return ErrInvalid.New("bundle archive %s holds %q outside the bundle", archivePath, hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, models.DefaultDirOrExecPerm); err != nil {
+				return errorx.InternalError.Wrap(err, "failed to create %s", target)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), models.DefaultDirOrExecPerm); err != nil {
+				return errorx.InternalError.Wrap(err, "failed to create %s", filepath.Dir(target))
+			}
+			if err := writeFile(target, tr); err != nil {
+				return errorx.InternalError.Wrap(err, "failed to extract %s", hdr.Name)
+			}
+		default:
+			return ErrInvalid.New("bundle archive %s holds %q, which is neither a file nor a directory", archivePath, hdr.Name)
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, models.DefaultFilePerm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, r)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
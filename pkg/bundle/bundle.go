@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bundle reads and activates air-gap install bundles: a directory (or
+// a tar.gz of one) holding every host artifact, Helm chart and container image
+// an install needs, described by a manifest.yaml. While a bundle is active,
+// software.Downloader copies catalog URLs from it and helm.Manager pulls
+// charts from it instead of reaching the network.
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"gopkg.in/yaml.v3"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/hashgraph/solo-weaver/pkg/sanity"
+)
+
+const (
+	// ManifestFile is the name of the manifest at the root of a bundle.
+	ManifestFile = "manifest.yaml"
+	// SchemaVersion is the manifest schema this build reads and writes.
+	SchemaVersion = 1
+
+	// ArtifactsDir, ChartsDir and ImagesDir are the bundle subdirectories
+	// host artifacts, chart archives and the OCI image layout live in.
+	ArtifactsDir = "artifacts"
+	ChartsDir    = "charts"
+	ImagesDir    = "images"
+)
+
+// Manifest describes a bundle: what it was made for and every file in it.
+type Manifest struct {
+	SchemaVersion      int        `yaml:"schemaVersion"`
+	CreatedAt          time.Time  `yaml:"createdAt"`
+	ProvisionerVersion string     `yaml:"provisionerVersion"`
+	NodeType           string     `yaml:"nodeType"`
+	Profile            string     `yaml:"profile"`
+	OS                 string     `yaml:"os"`
+	Arch               string     `yaml:"arch"`
+	Artifacts          []Artifact `yaml:"artifacts"`
+	Charts             []Chart    `yaml:"charts"`
+	Images             []Image    `yaml:"images,omitempty"`
+}
+
+// File is a file in the bundle: its path relative to the bundle root and
+// the SHA256 it was recorded with.
+type File struct {
+	Path   string `yaml:"path"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// Artifact is a host artifact file, keyed by the catalog URL it was
+// downloaded from. Algorithm and Checksum are the catalog's integrity record,
+// which the installer verifies again after copying the file out.
+type Artifact struct {
+	File      `yaml:",inline"`
+	Software  string `yaml:"software"`
+	URL       string `yaml:"url"`
+	Algorithm string `yaml:"algorithm,omitempty"`
+	Checksum  string `yaml:"checksum,omitempty"`
+}
+
+// Chart is a Helm chart archive, keyed by chart reference and version.
+// Checksum is the value the catalog pins the chart with (SHA256 of the .tgz
+// for classic charts, the manifest digest for OCI charts, bare hex), and Repo
+// the classic repository URL. SignatureVerified records that the chart's
+// signature verified when the bundle was created.
+type Chart struct {
+	File              `yaml:",inline"`
+	Chart             string `yaml:"chart"`
+	Version           string `yaml:"version"`
+	Repo              string `yaml:"repo,omitempty"`
+	Checksum          string `yaml:"checksum"`
+	SignatureVerified bool   `yaml:"signatureVerified,omitempty"`
+}
+
+// Image is a container image in the bundle's OCI layout, tagged there with
+// its full reference (e.g. "docker.io/library/busybox:latest").
+type Image struct {
+	Ref    string `yaml:"ref"`
+	Digest string `yaml:"digest"`
+}
+
+// Bundle is an unpacked bundle directory.
+type Bundle struct {
+	dir      string
+	manifest Manifest
+}
+
+// Open reads the manifest of the bundle directory dir. It does not verify
+// the files; each is verified when it is copied out (see CopyTo), and Verify
+// checks all of them at once.
+func Open(dir string) (*Bundle, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, ErrInvalid.Wrap(err, "%s is not a bundle directory", dir).
+			WithProperty(models.ErrPropertyResolution, "pass the directory `bundle install` extracted into, or the bundle archive itself")
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, ErrInvalid.Wrap(err, "failed to parse the manifest of bundle %s", dir)
+	}
+	if m.SchemaVersion != SchemaVersion {
+		return nil, ErrInvalid.New("bundle %s has manifest schema version %d, this build reads %d", dir, m.SchemaVersion, SchemaVersion).
+			WithProperty(models.ErrPropertyResolution, "create the bundle with the same solo-provisioner version that installs it")
+	}
+	for _, f := range m.files() {
+		if _, err := sanity.ValidatePathWithinBase(dir, filepath.Join(dir, f.Path)); err != nil || f.Path == "" {
+			return nil, ErrInvalid.New("bundle %s lists file %q outside the bundle", dir, f.Path)
+		}
+	}
+	return &Bundle{dir: dir, manifest: m}, nil
+}
+
+// Dir returns the bundle directory.
+func (b *Bundle) Dir() string {
+	return b.dir
+}
+
+// Manifest returns the bundle's manifest.
+func (b *Bundle) Manifest() Manifest {
+	return b.manifest
+}
+
+// ImagesDir returns the directory of the bundle's OCI image layout.
+func (b *Bundle) ImagesDir() string {
+	return filepath.Join(b.dir, ImagesDir)
+}
+
+// Artifact returns the host artifact downloaded from url.
+func (b *Bundle) Artifact(url string) (Artifact, bool) {
+	for _, a := range b.manifest.Artifacts {
+		if a.URL == url {
+			return a, true
+		}
+	}
+	return Artifact{}, false
+}
+
+// Chart returns the archive of chartRef at version.
+func (b *Bundle) Chart(chartRef, version string) (Chart, bool) {
+	for _, c := range b.manifest.Charts {
+		if c.Chart == chartRef && c.Version == version {
+			return c, true
+		}
+	}
+	return Chart{}, false
+}
+
+// HasRepo reports whether the bundle holds a chart from the classic
+// repository at url, i.e. whether its index need not be downloaded.
+func (b *Bundle) HasRepo(url string) bool {
+	for _, c := range b.manifest.Charts {
+		if c.Repo != "" && strings.TrimSuffix(c.Repo, "/") == strings.TrimSuffix(url, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// CopyTo copies f out of the bundle to dest, failing with
+// ErrChecksumMismatch, and removing dest, when the copy does not match the
+// digest f was recorded with.
+func (b *Bundle) CopyTo(f File, dest string) error {
+	src, err := os.Open(filepath.Join(b.dir, f.Path))
+	if err != nil {
+		return ErrNotFound.Wrap(err, "bundle %s is missing %s", b.dir, f.Path)
+	}
+	defer src.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, models.DefaultFilePerm)
+	if err != nil {
+		return errorx.InternalError.Wrap(err, "failed to create %s", dest)
+	}
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, hasher), src)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(dest)
+		return errorx.InternalError.Wrap(err, "failed to copy %s out of bundle %s", f.Path, b.dir)
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, f.SHA256) {
+		_ = os.Remove(dest)
+		return checksumMismatch(b.dir, f, actual)
+	}
+	return nil
+}
+
+// Verify checks every artifact and chart file against its recorded digest.
+// Images are verified by digest as they are copied out of the OCI layout.
+func (b *Bundle) Verify() error {
+	for _, f := range b.manifest.files() {
+		actual, err := SHA256File(filepath.Join(b.dir, f.Path))
+		if err != nil {
+			return ErrNotFound.Wrap(err, "bundle %s is missing %s", b.dir, f.Path)
+		}
+		if !strings.EqualFold(actual, f.SHA256) {
+			return checksumMismatch(b.dir, f, actual)
+		}
+	}
+	return nil
+}
+
+// WriteManifest writes m into the bundle directory dir.
+func WriteManifest(dir string, m Manifest) error {
+	m.SchemaVersion = SchemaVersion
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return errorx.InternalError.Wrap(err, "failed to render the bundle manifest")
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), data, models.DefaultFilePerm); err != nil {
+		return errorx.InternalError.Wrap(err, "failed to write the bundle manifest")
+	}
+	return nil
+}
+
+// SHA256File returns the lowercase hex SHA256 of the file at path.
+func SHA256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func (m Manifest) files() []File {
+	files := make([]File, 0, len(m.Artifacts)+len(m.Charts))
+	for _, a := range m.Artifacts {
+		files = append(files, a.File)
+	}
+	for _, c := range m.Charts {
+		files = append(files, c.File)
+	}
+	return files
+}
+
+func checksumMismatch(dir string, f File, actual string) error {
+	return ErrChecksumMismatch.New("%s in bundle %s does not match its manifest: expected sha256=%s, got sha256=%s",
+		f.Path, dir, f.SHA256, actual).
+		WithProperty(models.ErrPropertyResolution, "the bundle is corrupt or was modified; copy it again from where it was created")
+}
+
+var (
+	activeMu sync.RWMutex
+	active   *Bundle
+)
+
+// Activate makes b the bundle downloads and chart pulls resolve from; nil
+// deactivates it.
+func Activate(b *Bundle) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	active = b
+}
+
+// Active returns the active bundle, or nil when there is none.
+func Active() *Bundle {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	return active
+}
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBundleDir writes a bundle directory holding one artifact and one
+// chart and returns it with its manifest.
+func newTestBundleDir(t *testing.T) (string, Manifest) {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(rel, content string) File {
+		p := filepath.Join(dir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+		sum := sha256.Sum256([]byte(content))
+		return File{Path: rel, SHA256: hex.EncodeToString(sum[:])}
+	}
+
+	m := Manifest{
+		NodeType: "block",
+		Profile:  "local",
+		OS:       "linux",
+		Arch:     "amd64",
+		Artifacts: []Artifact{{
+			File:     write("artifacts/crio/crio.tar.gz", "crio"),
+			Software: "crio",
+			URL:      "https://example.com/crio.tar.gz",
+		}},
+		Charts: []Chart{{
+			File:    write("charts/metallb-0.15.2.tgz", "metallb"),
+			Chart:   "metallb/metallb",
+			Version: "0.15.2",
+			Repo:    "https://metallb.github.io/metallb/",
+		}},
+	}
+	require.NoError(t, WriteManifest(dir, m))
+	return dir, m
+}
+
+func TestOpen(t *testing.T) {
+	dir, _ := newTestBundleDir(t)
+
+	b, err := Open(dir)
+	require.NoError(t, err)
+	assert.Equal(t, dir, b.Dir())
+	assert.Equal(t, SchemaVersion, b.Manifest().SchemaVersion)
+
+	a, ok := b.Artifact("https://example.com/crio.tar.gz")
+	require.True(t, ok)
+	assert.Equal(t, "crio", a.Software)
+	_, ok = b.Artifact("https://example.com/other.tar.gz")
+	assert.False(t, ok)
+
+	_, ok = b.Chart("metallb/metallb", "0.15.2")
+	assert.True(t, ok)
+	_, ok = b.Chart("metallb/metallb", "0.15.3")
+	assert.False(t, ok)
+
+	assert.True(t, b.HasRepo("https://metallb.github.io/metallb"))
+	assert.False(t, b.HasRepo("https://charts.jetstack.io"))
+
+	require.NoError(t, b.Verify())
+}
+
+func TestOpen_NotABundle(t *testing.T) {
+	_, err := Open(t.TempDir())
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, ErrInvalid))
+}
+
+func TestOpen_RejectsPathOutsideBundle(t *testing.T) {
+	dir, m := newTestBundleDir(t)
+	m.Artifacts[0].Path = "../outside"
+	require.NoError(t, WriteManifest(dir, m))
+
+	_, err := Open(dir)
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, ErrInvalid))
+	assert.Contains(t, err.Error(), "outside the bundle")
+}
+
+func TestOpen_RejectsOtherSchemaVersion(t *testing.T) {
+	dir, _ := newTestBundleDir(t)
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	require.NoError(t, err)
+	data = []byte(strings.Replace(string(data), "schemaVersion: 1\n", "schemaVersion: 99\n", 1))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644))
+
+	_, err = Open(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema version 99")
+}
+
+func TestCopyTo(t *testing.T) {
+	dir, m := newTestBundleDir(t)
+	b, err := Open(dir)
+	require.NoError(t, err)
+
+	dest := filepath.Join(t.TempDir(), "crio.tar.gz")
+	require.NoError(t, b.CopyTo(m.Artifacts[0].File, dest))
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "crio", string(got))
+}
+
+func TestCopyTo_TamperedFile(t *testing.T) {
+	dir, m := newTestBundleDir(t)
+	b, err := Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, m.Artifacts[0].Path), []byte("tampered"), 0o644))
+
+	dest := filepath.Join(t.TempDir(), "crio.tar.gz")
+	err = b.CopyTo(m.Artifacts[0].File, dest)
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, ErrChecksumMismatch))
+	assert.NoFileExists(t, dest)
+
+	err = b.Verify()
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, ErrChecksumMismatch))
+}
+
+func TestPackUnpack(t *testing.T) {
+	dir, m := newTestBundleDir(t)
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+
+	sum, err := Pack(dir, archive)
+	require.NoError(t, err)
+	actual, err := SHA256File(archive)
+	require.NoError(t, err)
+	assert.Equal(t, actual, sum)
+	sidecar, err := os.ReadFile(archive + ChecksumSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, sum+"  bundle.tar.gz\n", string(sidecar))
+
+	dest := filepath.Join(t.TempDir(), "bundle")
+	require.NoError(t, os.MkdirAll(dest, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "stale"), []byte("x"), 0o644))
+
+	b, err := Unpack(archive, dest)
+	require.NoError(t, err)
+	assert.Equal(t, dest, b.Dir())
+	assert.Equal(t, m.Artifacts, b.Manifest().Artifacts)
+	assert.NoFileExists(t, filepath.Join(dest, "stale"), "the previous bundle is replaced")
+	require.NoError(t, b.Verify())
+}
+
+func TestUnpack_ArchiveChecksumMismatch(t *testing.T) {
+	dir, _ := newTestBundleDir(t)
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	_, err := Pack(dir, archive)
+	require.NoError(t, err)
+
+	f, err := os.OpenFile(archive, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("trailing"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	dest := filepath.Join(t.TempDir(), "bundle")
+	_, err = Unpack(archive, dest)
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, ErrChecksumMismatch))
+	assert.NoDirExists(t, dest)
+}
+
+func TestUnpack_MissingChecksumFile(t *testing.T) {
+	dir, _ := newTestBundleDir(t)
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	_, err := Pack(dir, archive)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(archive+ChecksumSuffix))
+
+	_, err = Unpack(archive, filepath.Join(t.TempDir(), "bundle"))
+	require.Error(t, err)
+}
+
+func TestUnpack_RejectsEntryOutsideBundle(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../escape", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err = tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	sum, err := SHA256File(archive)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(archive+ChecksumSuffix, []byte(sum+"  bundle.tar.gz\n"), 0o644))
+
+	base := t.TempDir()
+	_, err = Unpack(archive, filepath.Join(base, "bundle"))
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(base, "escape"))
+}
+
+func TestActivate(t *testing.T) {
+	t.Cleanup(func() { Activate(nil) })
+	dir, _ := newTestBundleDir(t)
+	b, err := Open(dir)
+	require.NoError(t, err)
+
+	assert.Nil(t, Active())
+	Activate(b)
+	assert.Same(t, b, Active())
+	Activate(nil)
+	assert.Nil(t, Active())
+}
@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import "github.com/joomcode/errorx"
+
+var (
+	ErrNamespace = errorx.NewNamespace("bundle")
+
+	ErrInvalid          = ErrNamespace.NewType("invalid")                      // Manifest or archive missing, unreadable or inconsistent
+	ErrNotFound         = ErrNamespace.NewType("not_found", errorx.NotFound()) // File listed in the manifest missing from the bundle
+	ErrChecksumMismatch = ErrNamespace.NewType("checksum_mismatch")            // File or archive does not match its recorded digest
+)
@@ -4,6 +4,7 @@ package config
 
 import (
 	"os"
+	"path"
 
 	"github.com/automa-saga/logx"
 	"github.com/hashgraph/solo-weaver/pkg/deps"
@@ -131,6 +132,48 @@ func DefaultsConfig() models.Config {
 	}
 }
 
+// InstanceDefaultsConfig is DefaultsConfig for the block node deployed as
+// release: an additional instance (see models.BlockNodeInstance) defaults to a
+// namespace named after the release and to storage under a directory of that
+// name below the default base path, so it never shares either with the
+// default instance.
+func InstanceDefaultsConfig(release string) models.Config {
+	c := DefaultsConfig()
+	if release != "" {
+		c.BlockNode.Release = release
+	}
+	applyBlockNodeInstance(&c.BlockNode)
+	return c
+}
+
+// SelectBlockNodeInstance points the block node configuration at the instance
+// deployed as release. For an additional instance, a namespace or base path
+// still at its compile-time default is re-derived as in InstanceDefaultsConfig;
+// values set in the config file are kept. An empty release leaves the
+// configuration as loaded.
+func SelectBlockNodeInstance(release string) {
+	if release == "" {
+		return
+	}
+	globalConfig.BlockNode.Release = release
+	applyBlockNodeInstance(&globalConfig.BlockNode)
+}
+
+// applyBlockNodeInstance moves defaulted namespace and storage of an
+// additional block node instance out of the default instance's way.
+func applyBlockNodeInstance(bn *models.BlockNodeConfig) {
+	instance := models.BlockNodeInstance(bn.Release)
+	if instance == "" {
+		return
+	}
+	if bn.Namespace == "" || bn.Namespace == deps.BLOCK_NODE_NAMESPACE {
+		bn.Namespace = instance
+	}
+	if bn.Storage.BasePath == deps.BLOCK_NODE_STORAGE_BASE_PATH {
+		bn.Storage.BasePath = path.Join(deps.BLOCK_NODE_STORAGE_BASE_PATH, instance)
+	}
+}
+
 // EnvConfig returns a models.Config populated exclusively from SOLO_PROVISIONER_*
 // environment variables. Fields with no matching env var are left at their zero value.
 //
@@ -225,6 +268,7 @@ func OverrideBlockNodeConfig(overrides models.BlockNodeConfig) {
 // Note: Passwords are managed via Vault and External Secrets Operator.
 func OverrideAlloyConfig(overrides models.AlloyConfig) {
 	globalConfig.Alloy.MonitorBlockNode = overrides.MonitorBlockNode
+	globalConfig.Alloy.MonitorTrafficShaper = overrides.MonitorTrafficShaper
 	if overrides.ClusterName != "" {
 		globalConfig.Alloy.ClusterName = overrides.ClusterName
 	}
@@ -280,6 +324,16 @@ func OverrideHostConfig(overrides models.HostConfig) {
 	globalConfig.Host = overrides
 }
 
+// OverrideClusterConfig replaces the cluster network configuration with
+// overrides. Its caller (ResolveClusterConfig) has already merged flags over
+// the config file, the layout recorded in the state and the defaults, so
+// overrides is the complete layout the steps render. DefaultsConfig leaves the
+// block empty for the same reason: a compiled-in default must not win over the
+// layout an existing cluster was initialised with.
+func OverrideClusterConfig(overrides models.ClusterConfig) {
+	globalConfig.Cluster = overrides
+}
+
 // SetProxy updates the global proxy configuration.
 func SetProxy(cfg models.ProxyConfig) {
 	globalConfig.Proxy = cfg
@@ -129,6 +129,30 @@ func TestDefaultsConfig_ReturnsDepsConstants(t *testing.T) {
 	}
 }
 
+// TestInstanceDefaultsConfig_SeparatesAdditionalInstance verifies that an
+// additional block node release gets its own namespace and storage base path
+// while the default release keeps the deps constants.
+func TestInstanceDefaultsConfig_SeparatesAdditionalInstance(t *testing.T) {
+	def := DefaultsConfig()
+
+	cfg := InstanceDefaultsConfig("")
+	if cfg.BlockNode.Namespace != def.BlockNode.Namespace || cfg.BlockNode.Storage.BasePath != def.BlockNode.Storage.BasePath {
+		t.Errorf("default release: expected deps defaults, got namespace %q base path %q",
+			cfg.BlockNode.Namespace, cfg.BlockNode.Storage.BasePath)
+	}
+
+	cfg = InstanceDefaultsConfig("testnet")
+	if cfg.BlockNode.Release != "testnet" {
+		t.Errorf("Release: expected %q, got %q", "testnet", cfg.BlockNode.Release)
+	}
+	if cfg.BlockNode.Namespace != "testnet" {
+		t.Errorf("Namespace: expected %q, got %q", "testnet", cfg.BlockNode.Namespace)
+	}
+	if want := def.BlockNode.Storage.BasePath + "/testnet"; cfg.BlockNode.Storage.BasePath != want {
+		t.Errorf("Storage.BasePath: expected %q, got %q", want, cfg.BlockNode.Storage.BasePath)
+	}
+}
+
 // TestEnvConfig_ReadsEnvVars verifies that EnvConfig populates fields from
 // SOLO_PROVISIONER_* environment variables and leaves unset fields at zero value.
 func TestEnvConfig_ReadsEnvVars(t *testing.T) {
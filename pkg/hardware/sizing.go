@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package hardware
+
+import (
+	"sync"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// SizingHint is a hardware floor a block node plugin preset declares for a set
+// of profiles. The plugin-preset catalog (internal/blocknode) turns each preset
+// file's hints into SizingHints and installs them with SetBlockNodeSizing.
+type SizingHint struct {
+	// Preset is the plugin preset ID the floor applies to.
+	Preset string
+	// Default also applies the floor when the selected preset declares no
+	// hints of its own: no preset, custom, none or an unknown ID.
+	Default bool
+	// Profiles the floor applies to; empty means every profile.
+	Profiles []string
+	// Plugins, when set, must all be in the deployment's plugin list.
+	Plugins []string
+	Contribution
+}
+
+// defaultBlockNodeSizing are the preset floors used until a catalog installs
+// its own. They match the hints of the embedded tier1-lfh and tier1-rfh files.
+//
+// LFH and RFH floors are mutually exclusive: LFH is the default, so it fires
+// for every preset but RFH. RFH has lower CPU/memory/disk than LFH, so a shared
+// baseline would prevent the Max reducer from ever landing on the smaller RFH
+// numbers. Mainnet LFH runs on bare metal (lfh_count=0) and is outside the scope
+// of provisioner hardware checks, so it has no floor.
+var defaultBlockNodeSizing = []SizingHint{
+	{
+		Preset: "tier1-lfh", Default: true, Profiles: []string{models.ProfileTestnet, models.ProfilePerfnet},
+		Contribution: Contribution{CpuCores: 16, MemoryGB: 64, StorageGB: 5000, Why: "block node testnet LFH: n2d-standard-16, 5 TB local disk"},
+	},
+	{
+		Preset: "tier1-lfh", Default: true, Profiles: []string{models.ProfilePreviewnet},
+		Contribution: Contribution{CpuCores: 16, MemoryGB: 64, StorageGB: 3000, Why: "block node previewnet LFH: n2d-standard-16, 3 TB local disk"},
+	},
+	{
+		Preset: "tier1-rfh", Profiles: []string{models.ProfileTestnet, models.ProfilePerfnet},
+		Contribution: Contribution{CpuCores: 8, MemoryGB: 32, StorageGB: 150, Why: "block node testnet RFH: c3d-standard-8, 150 GB local disk"},
+	},
+	{
+		Preset: "tier1-rfh", Profiles: []string{models.ProfilePreviewnet},
+		Contribution: Contribution{CpuCores: 8, MemoryGB: 32, StorageGB: 150, Why: "block node previewnet RFH: c3d-standard-8, 150 GB local disk"},
+	},
+	{
+		Preset: "tier1-rfh", Profiles: []string{models.ProfileMainnet},
+		Contribution: Contribution{CpuCores: 32, MemoryGB: 256, StorageGB: 150, Why: "block node mainnet cloud minimum: n2d-highmem-32, 150 GB local disk"},
+	},
+}
+
+var (
+	sizingMu         sync.RWMutex
+	blockNodeSizing  = defaultBlockNodeSizing
+	blockNodePresets = sizingRules(defaultBlockNodeSizing)
+)
+
+// DefaultBlockNodeSizing returns a copy of the built-in preset floors.
+func DefaultBlockNodeSizing() []SizingHint {
+	out := make([]SizingHint, len(defaultBlockNodeSizing))
+	copy(out, defaultBlockNodeSizing)
+	return out
+}
+
+// BlockNodeSizing returns a copy of the preset floors in use.
+func BlockNodeSizing() []SizingHint {
+	sizingMu.RLock()
+	defer sizingMu.RUnlock()
+	out := make([]SizingHint, len(blockNodeSizing))
+	copy(out, blockNodeSizing)
+	return out
+}
+
+// SetBlockNodeSizing replaces the preset floors the block node provider uses.
+func SetBlockNodeSizing(hints []SizingHint) {
+	rules := sizingRules(hints)
+	sizingMu.Lock()
+	defer sizingMu.Unlock()
+	blockNodeSizing = append([]SizingHint(nil), hints...)
+	blockNodePresets = rules
+}
+
+// sizingRules turns hints into rules. A Default hint fires for its own preset
+// and for any preset that declares no hints.
+func sizingRules(hints []SizingHint) []Rule {
+	hinted := map[string]bool{}
+	for _, h := range hints {
+		hinted[h.Preset] = true
+	}
+
+	rules := make([]Rule, 0, len(hints))
+	for _, h := range hints {
+		when := []func(DeploymentSpec) bool{presetPredicate(h.Preset)}
+		if h.Default {
+			var others []func(DeploymentSpec) bool
+			for p := range hinted {
+				if p != h.Preset {
+					others = append(others, presetPredicate(p))
+				}
+			}
+			when = []func(DeploymentSpec) bool{not(or(others...))}
+		}
+		if len(h.Profiles) > 0 {
+			when = append(when, anyProfile(h.Profiles...))
+		}
+		for _, pl := range h.Plugins {
+			when = append(when, hasPlugin(pl))
+		}
+		rules = append(rules, Rule{When: and(when...), Then: h.Contribution})
+	}
+	return rules
+}
@@ -14,5 +14,8 @@ var (
 	ErrInstallFailed          = ErrNamespace.NewType("install_failed")               // Helm install failed
 	ErrUpgradeFailed          = ErrNamespace.NewType("upgrade_failed")               // Helm upgrade failed
 	ErrUninstallFailed        = ErrNamespace.NewType("uninstall_failed")             // Helm uninstall failed
+	ErrRollbackFailed         = ErrNamespace.NewType("rollback_failed")              // Helm rollback failed
+	ErrValuesInvalid          = ErrNamespace.NewType("values_invalid")               // Values do not match the chart schema or defaults
+	ErrProvenanceInvalid      = ErrNamespace.NewType("provenance_invalid")           // Chart signature missing or not verifiable
 	ErrWaitTimeout            = ErrNamespace.NewType("wait_timeout", errorx.Timeout())
 )
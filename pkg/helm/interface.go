@@ -38,7 +38,23 @@ type Manager interface {
 	// in-flight pull therefore runs to completion regardless of cancellation;
 	// a slow pull will block the step until the underlying HTTP transport's
 	// own deadlines fire.
-	PullAndVerify(ctx context.Context, destDir, chartRef, version, algorithm, expectedChecksum string) (string, error)
+	//
+	// WithProvenance additionally verifies the chart's signature (see
+	// Provenance) after the checksum matches.
+	PullAndVerify(ctx context.Context, destDir, chartRef, version, algorithm, expectedChecksum string, opts ...PullOption) (string, error)
+
+	// PullAndVerifyProvenance pulls a Helm chart the infrastructure catalog
+	// does not pin (e.g. a block node chart version chosen with
+	// --chart-version) into destDir and verifies its signature according to
+	// p, which must be optional or required. It returns the local .tgz path.
+	PullAndVerifyProvenance(ctx context.Context, destDir, chartRef, version string, p Provenance) (string, error)
+
+	// PullChart pulls a Helm chart the infrastructure catalog does not pin
+	// into destDir without a checksum to compare against, verifying its
+	// signature when WithProvenance is enabled. It returns the local .tgz
+	// path and the chart's checksum in the catalog's form: the SHA256 of the
+	// .tgz for classic charts, the manifest digest for OCI charts, bare hex.
+	PullChart(ctx context.Context, destDir, chartRef, version string, opts ...PullOption) (string, string, error)
 
 	// InstallChart installs a Helm chart with the given options
 	InstallChart(ctx context.Context, releaseName, chartRef, chartVersion, namespace string, o InstallChartOptions) (*release.Release, error)
@@ -68,6 +84,14 @@ type Manager interface {
 	// It considers only releases in deployed state as "installed"
 	IsInstalled(releaseName, namespace string) (bool, error)
 
+	// History returns every stored revision of a Helm release in the specified
+	// namespace, oldest first. It returns ErrNotFound if the release does not exist
+	History(releaseName, namespace string) ([]*release.Release, error)
+
+	// RollbackChart rolls a Helm release back to an earlier revision with the given options
+	// This is equivalent to "helm rollback <release> <revision>"
+	RollbackChart(ctx context.Context, releaseName, namespace string, o RollbackChartOptions) error
+
 	// WaitFor waits until the Helm release reaches the desired status or the timeout is reached
 	WaitFor(rel *release.Release, status Status, timeout time.Duration) error
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,10 +25,15 @@ import (
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
 	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
 )
 
 type helmManager struct {
 	log zerolog.Logger
+	// signatureTarget overrides where OCI chart signatures are read from;
+	// nil reads them from the chart's registry.
+	signatureTarget signatureTarget
 }
 
 type Option func(*helmManager)
@@ -138,7 +144,12 @@ func (h *helmManager) AddRepo(name, url string, o RepoAddOptions) (*repo.ChartRe
 		r.CachePath = o.RepoCache
 	}
 
-	if _, err := r.DownloadIndexFile(); err != nil {
+	// An air-gap bundle holding this repository's charts serves them without
+	// its index, which could not be downloaded anyway.
+	if b := bundle.Active(); b != nil && b.HasRepo(url) {
+		h.log.Info().Str("name", name).Str("url", url).Str("bundle", b.Dir()).
+			Msg("Skipping Helm repository index download, its charts come from the air-gap bundle")
+	} else if _, err := r.DownloadIndexFile(); err != nil {
 		return r, errorx.InternalError.Wrap(err, "looks like %q is not a valid chart repository or cannot be reached", url)
 	}
 
@@ -250,6 +261,14 @@ func (h *helmManager) InstallChart(ctx context.Context, releaseName, chartRef, c
 		}
 	}
 
+	// Helm's own schema check runs late and reports Go-template paths; ours
+	// runs first and also catches unknown keys for charts without a schema.
+	if o.SkipValuesValidation {
+		installClient.SkipSchemaValidation = true
+	} else if err := h.validateValues(chartRequested, chartValues, o.RejectUnknownValues); err != nil {
+		return nil, err
+	}
+
 	rel, err := installClient.RunWithContext(ctx, chartRequested, chartValues)
 	if err != nil {
 		return nil, ErrInstallFailed.Wrap(err, "failed to install chart")
@@ -385,6 +404,12 @@ func (h *helmManager) UpgradeChart(ctx context.Context, releaseName, chartRef, c
 		}
 	}
 
+	if o.SkipValuesValidation {
+		upgradeClient.SkipSchemaValidation = true
+	} else if err := h.validateValues(chart, chartValues, o.RejectUnknownValues); err != nil {
+		return nil, err
+	}
+
 	rel, err := upgradeClient.RunWithContext(ctx, releaseName, chart, chartValues)
 	if err != nil {
 		if errors.Is(err, driver.ErrReleaseNotFound) {
@@ -508,6 +533,74 @@ func (h *helmManager) IsInstalled(releaseName, namespace string) (bool, error) {
 	return rel.Info.Status == release.StatusDeployed, nil
 }
 
+// History returns every stored revision of a Helm release, oldest first
+// It returns ErrNotFound if the release does not exist
+func (h *helmManager) History(releaseName, namespace string) ([]*release.Release, error) {
+	l := h.log.With().Str("releaseName", releaseName).Str("namespace", namespace).Logger()
+	l.Info().Msg("Getting Helm release history")
+
+	settings := h.WithNamespace(namespace)
+	actionConfig, err := initActionConfig(settings, l.Printf)
+	if err != nil {
+		return nil, errorx.IllegalArgument.Wrap(err, "failed to init action config")
+	}
+
+	historyClient := action.NewHistory(actionConfig)
+
+	revisions, err := historyClient.Run(releaseName)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, ErrNotFound.Wrap(err, "release %q not found in namespace %q", releaseName, namespace)
+		}
+		return nil, errorx.InternalError.Wrap(err, "failed to run history action")
+	}
+
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Version < revisions[j].Version })
+	return revisions, nil
+}
+
+// RollbackChart rolls a Helm release back to an earlier revision
+// It assumes that the release and the revision exist
+func (h *helmManager) RollbackChart(ctx context.Context, releaseName, namespace string, o RollbackChartOptions) error {
+	l := h.log.With().Str("releaseName", releaseName).
+		Str("namespace", namespace).
+		Int("revision", o.Revision).Logger()
+	l.Info().Msg("Rolling back Helm release")
+
+	settings := h.WithNamespace(namespace)
+	actionConfig, err := initActionConfig(settings, l.Printf)
+	if err != nil {
+		return errorx.IllegalArgument.Wrap(err, "failed to init action config")
+	}
+
+	rollbackClient := action.NewRollback(actionConfig)
+	rollbackClient.Version = o.Revision
+	rollbackClient.Wait = o.Wait
+	rollbackClient.WaitForJobs = o.Wait
+	rollbackClient.CleanupOnFail = o.CleanupOnFail
+	rollbackClient.Timeout = o.Timeout
+
+	if rollbackClient.Timeout == 0 {
+		rollbackClient.Timeout = DefaultTimeout
+	}
+
+	// The Helm SDK's Rollback takes no context; honour a cancellation that
+	// arrived before the rollback started at least.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := rollbackClient.Run(releaseName); err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return ErrNotFound.Wrap(err, "release %q not found in namespace %q", releaseName, namespace)
+		}
+		return ErrRollbackFailed.Wrap(err, "failed to roll back release %q", releaseName)
+	}
+
+	l.Info().Msg("Helm release rolled back successfully")
+	return nil
+}
+
 // DeployChart installs or upgrades a Helm chart with the given options
 // This is equivalent to "helm upgrade --install"
 func (h *helmManager) DeployChart(ctx context.Context, releaseName, chartRef, chartVersion, namespace string, o DeployChartOptions) (*release.Release, error) {
@@ -539,6 +632,9 @@ func (h *helmManager) DeployChart(ctx context.Context, releaseName, chartRef, ch
 				Wait:            o.Wait,
 				Timeout:         o.Timeout,
 				Labels:          o.Labels,
+
+				SkipValuesValidation: o.SkipValuesValidation,
+				RejectUnknownValues:  o.RejectUnknownValues,
 			})
 		}
 
@@ -555,6 +651,9 @@ func (h *helmManager) DeployChart(ctx context.Context, releaseName, chartRef, ch
 		ReuseValues: o.ReuseValues,
 		Timeout:     o.Timeout,
 		Labels:      o.Labels,
+
+		SkipValuesValidation: o.SkipValuesValidation,
+		RejectUnknownValues:  o.RejectUnknownValues,
 	})
 }
 
@@ -22,6 +22,13 @@ type InstallChartOptions struct {
 	Timeout         time.Duration
 	CreateNamespace bool
 	Labels          map[string]string
+	// SkipValuesValidation installs the values without checking them
+	// against the chart's schema or default keys (see ValidateValues).
+	SkipValuesValidation bool
+	// RejectUnknownValues fails on keys a chart without a schema does not
+	// define in its default values, instead of logging them. Set it where the
+	// operator supplies the values.
+	RejectUnknownValues bool
 }
 
 // UpgradeChartOptions for upgrading Helm charts
@@ -32,6 +39,10 @@ type UpgradeChartOptions struct {
 	Timeout     time.Duration
 	ReuseValues bool
 	Labels      map[string]string
+	// SkipValuesValidation upgrades with the values unchecked (see ValidateValues).
+	SkipValuesValidation bool
+	// RejectUnknownValues fails on unknown keys (see InstallChartOptions).
+	RejectUnknownValues bool
 }
 
 // DeployChartOptions for idempotent install/upgrade
@@ -43,4 +54,16 @@ type DeployChartOptions struct {
 	CreateNamespace bool
 	ReuseValues     bool
 	Labels          map[string]string
+	// SkipValuesValidation deploys the values unchecked (see ValidateValues).
+	SkipValuesValidation bool
+	// RejectUnknownValues fails on unknown keys (see InstallChartOptions).
+	RejectUnknownValues bool
+}
+
+// RollbackChartOptions for rolling a Helm release back to an earlier revision
+type RollbackChartOptions struct {
+	Revision      int // 0 rolls back to the previous revision
+	Wait          bool
+	Timeout       time.Duration
+	CleanupOnFail bool
 }
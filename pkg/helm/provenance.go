@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joomcode/errorx"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/downloader"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"oras.land/oras-go/v2/registry/remote/retry"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// ProvenancePolicy says whether a pulled chart's signature must verify.
+type ProvenancePolicy string
+
+const (
+	// ProvenanceOff skips signature verification.
+	ProvenanceOff ProvenancePolicy = "off"
+	// ProvenanceOptional verifies the signature when both the key and the
+	// signature are available, and logs a warning when either is missing or
+	// the signature cannot be fetched. A signature that is present but does
+	// not verify still fails the pull.
+	ProvenanceOptional ProvenancePolicy = "optional"
+	// ProvenanceRequired fails the pull unless the signature verifies.
+	ProvenanceRequired ProvenancePolicy = "required"
+)
+
+const (
+	// cosignSignatureAnnotation holds the base64 signature over a cosign
+	// simple-signing payload layer.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// cosignPayloadMediaType is the media type of a cosign signature layer.
+	cosignPayloadMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+)
+
+// Provenance configures the signature verification of a pulled chart. Classic
+// charts are verified by their Helm provenance file (<chart>.tgz.prov) against
+// the OpenPGP keyring at Keyring; OCI charts by their cosign signature against
+// the PEM public key at CosignKey. The zero value verifies nothing.
+type Provenance struct {
+	Policy    ProvenancePolicy
+	Keyring   string
+	CosignKey string
+}
+
+// Enabled reports whether p verifies anything, i.e. its policy is optional
+// or required.
+func (p Provenance) Enabled() bool {
+	return p.Policy == ProvenanceOptional || p.Policy == ProvenanceRequired
+}
+
+// PullOption configures PullAndVerify.
+type PullOption func(*pullOptions)
+
+type pullOptions struct {
+	provenance Provenance
+}
+
+// WithProvenance verifies the pulled chart's signature according to p, in
+// addition to its checksum.
+func WithProvenance(p Provenance) PullOption {
+	return func(o *pullOptions) {
+		o.provenance = p
+	}
+}
+
+// signatureTarget returns where the cosign signatures of the OCI repository
+// (e.g. "ghcr.io/hiero-ledger/hiero-block-node/block-node-server") are read
+// from. Tests replace it with an in-memory store.
+type signatureTarget func(repository string) (oras.ReadOnlyTarget, error)
+
+// skipOrFail handles a verification that cannot run (no key, no signature):
+// a required policy fails with reason, an optional one logs it and goes on.
+func (h *helmManager) skipOrFail(p Provenance, chartRef, version, reason string) error {
+	if p.Policy == ProvenanceRequired {
+		return ErrProvenanceInvalid.New("chart %q version %q: %s", chartRef, version, reason).
+			WithProperty(models.ErrPropertyResolution, []string{
+				"install the signing key the infrastructure catalog names into the keys directory beside the binary",
+				"check that the chart version was published with a signature",
+			})
+	}
+	h.log.Warn().
+		Str("chart", chartRef).
+		Str("version", version).
+		Msgf("Skipping Helm chart signature verification: %s", reason)
+	return nil
+}
+
+// verifyClassicProvenance checks the provenance file Helm fetched beside tgz
+// against p.Keyring.
+func (h *helmManager) verifyClassicProvenance(tgz, chartRef, version string, p Provenance) error {
+	if p.Keyring == "" {
+		return h.skipOrFail(p, chartRef, version, "no keyring is configured")
+	}
+	if _, err := os.Stat(p.Keyring); err != nil {
+		return h.skipOrFail(p, chartRef, version, fmt.Sprintf("keyring %s is not available", p.Keyring))
+	}
+	if _, err := os.Stat(tgz + ".prov"); err != nil {
+		return h.skipOrFail(p, chartRef, version, "the repository has no provenance file for it")
+	}
+
+	verification, err := downloader.VerifyChart(tgz, p.Keyring)
+	if err != nil {
+		return ErrProvenanceInvalid.Wrap(err, "provenance of chart %q version %q does not verify against keyring %s",
+			chartRef, version, p.Keyring)
+	}
+
+	signers := make([]string, 0, len(verification.SignedBy.Identities))
+	for name := range verification.SignedBy.Identities {
+		signers = append(signers, name)
+	}
+	h.log.Info().
+		Str("chart", chartRef).
+		Str("version", version).
+		Strs("signedBy", signers).
+		Str("fileHash", verification.FileHash).
+		Msg("Helm chart provenance verified")
+	return nil
+}
+
+// verifyOCISignature checks that the OCI chart whose manifest has
+// manifestDigest carries a cosign signature made with p.CosignKey.
+func (h *helmManager) verifyOCISignature(ctx context.Context, chartRef, version, manifestDigest string, p Provenance) error {
+	if p.CosignKey == "" {
+		return h.skipOrFail(p, chartRef, version, "no cosign public key is configured")
+	}
+	keyPEM, err := os.ReadFile(p.CosignKey)
+	if err != nil {
+		return h.skipOrFail(p, chartRef, version, fmt.Sprintf("cosign public key %s is not available", p.CosignKey))
+	}
+	pub, err := parsePublicKey(keyPEM)
+	if err != nil {
+		return ErrProvenanceInvalid.Wrap(err, "invalid cosign public key %s", p.CosignKey)
+	}
+
+	targetFor := h.signatureTarget
+	if targetFor == nil {
+		targetFor = h.remoteSignatureTarget
+	}
+	repository := strings.TrimPrefix(chartRef, "oci://")
+	target, err := targetFor(repository)
+	if err != nil {
+		return errorx.InternalError.Wrap(err, "failed to open OCI repository %q", repository)
+	}
+
+	err = verifyCosignSignature(ctx, target, manifestDigest, pub)
+	if errors.Is(err, errdef.ErrNotFound) {
+		return h.skipOrFail(p, chartRef, version, "the registry has no cosign signature for it")
+	}
+	if errorx.IsOfType(err, errorx.ExternalError) {
+		if p.Policy == ProvenanceRequired {
+			return errorx.Decorate(err, "failed to fetch the cosign signature of chart %q version %q", chartRef, version)
+		}
+		return h.skipOrFail(p, chartRef, version, fmt.Sprintf("its cosign signature could not be fetched: %v", err))
+	}
+	if err != nil {
+		return ErrProvenanceInvalid.Wrap(err, "cosign signature of chart %q version %q does not verify against %s",
+			chartRef, version, p.CosignKey)
+	}
+
+	h.log.Info().
+		Str("chart", chartRef).
+		Str("version", version).
+		Str("digest", manifestDigest).
+		Str("key", p.CosignKey).
+		Msg("Helm chart cosign signature verified")
+	return nil
+}
+
+// remoteSignatureTarget opens the OCI repository with the credentials Helm's
+// registry client uses (`helm registry login`), falling back to anonymous
+// access.
+func (h *helmManager) remoteSignatureTarget(repository string) (oras.ReadOnlyTarget, error) {
+	repo, err := remote.NewRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	client := &auth.Client{Client: retry.DefaultClient, Cache: auth.NewCache()}
+	if store, err := credentials.NewFileStore(h.WithNamespace("").RegistryConfig); err == nil {
+		client.Credential = credentials.Credential(store)
+	}
+	repo.Client = client
+	return repo, nil
+}
+
+// verifyCosignSignature resolves the cosign signature tag of manifestDigest
+// ("sha256-<hex>.sig") in target and returns nil when one of its layers is a
+// simple-signing payload for manifestDigest signed by pub. It returns an
+// error wrapping errdef.ErrNotFound when there is no signature tag, and an
+// errorx.ExternalError when the registry cannot be read.
+func verifyCosignSignature(ctx context.Context, target oras.ReadOnlyTarget, manifestDigest string, pub crypto.PublicKey) error {
+	tag := strings.Replace(manifestDigest, ":", "-", 1) + ".sig"
+	desc, err := target.Resolve(ctx, tag)
+	if errors.Is(err, errdef.ErrNotFound) {
+		return fmt.Errorf("resolve signature %s: %w", tag, err)
+	}
+	if err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to resolve signature %s", tag)
+	}
+	raw, err := content.FetchAll(ctx, target, desc)
+	if err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to fetch signature manifest %s", tag)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return fmt.Errorf("decode signature manifest %s: %w", tag, err)
+	}
+
+	var failures []string
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != cosignPayloadMediaType {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			failures = append(failures, fmt.Sprintf("layer %s: missing or malformed signature annotation", layer.Digest))
+			continue
+		}
+		payload, err := content.FetchAll(ctx, target, layer)
+		if err != nil {
+			return errorx.ExternalError.Wrap(err, "failed to fetch signature payload %s", layer.Digest)
+		}
+		if err := verifySignature(pub, payload, sig); err != nil {
+			failures = append(failures, fmt.Sprintf("layer %s: %v", layer.Digest, err))
+			continue
+		}
+		signed, err := signedManifestDigest(payload)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("layer %s: %v", layer.Digest, err))
+			continue
+		}
+		if signed != manifestDigest {
+			failures = append(failures, fmt.Sprintf("layer %s: signs %s, not %s", layer.Digest, signed, manifestDigest))
+			continue
+		}
+		return nil
+	}
+	if len(failures) == 0 {
+		return fmt.Errorf("signature manifest %s has no cosign signature layers", tag)
+	}
+	return fmt.Errorf("no signature matches the key: %s", strings.Join(failures, "; "))
+}
+
+// signedManifestDigest returns the manifest digest a cosign simple-signing
+// payload vouches for.
+func signedManifestDigest(payload []byte) (string, error) {
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return "", fmt.Errorf("decode simple-signing payload: %w", err)
+	}
+	if simpleSigning.Critical.Image.DockerManifestDigest == "" {
+		return "", errors.New("simple-signing payload names no manifest digest")
+	}
+	return simpleSigning.Critical.Image.DockerManifestDigest, nil
+}
+
+// parsePublicKey parses a PEM-encoded PKIX public key, as written by
+// `cosign generate-key-pair`.
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// verifySignature checks sig over payload the way cosign signs: ECDSA and RSA
+// keys sign the SHA-256 of the payload, Ed25519 keys the payload itself.
+func verifySignature(pub crypto.PublicKey, payload, sig []byte) error {
+	digest := sha256.Sum256(payload)
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], sig) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, sig) {
+			return errors.New("invalid Ed25519 signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
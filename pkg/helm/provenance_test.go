@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/joomcode/errorx"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// chartDigest stands in for the manifest digest of a pulled OCI chart.
+var chartDigest = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("block-node-server 0.20.0")))
+
+// newCosignKey generates an ECDSA key pair the way `cosign generate-key-pair`
+// does and writes the public half as PEM into dir.
+func newCosignKey(t *testing.T, dir, name string) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	return key, path
+}
+
+// pushCosignSignature stores a cosign signature of signedDigest, made with
+// key, in store under the signature tag of tagDigest, as `cosign sign` lays
+// it out in a registry.
+func pushCosignSignature(t *testing.T, store *memory.Store, key *ecdsa.PrivateKey, tagDigest, signedDigest string) {
+	t.Helper()
+	ctx := context.Background()
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"ghcr.io/hiero-ledger/hiero-block-node/block-node-server"},`+
+		`"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, signedDigest))
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+
+	layer, err := oras.PushBytes(ctx, store, cosignPayloadMediaType, payload)
+	require.NoError(t, err)
+	layer.Annotations = map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)}
+	cfg, err := oras.PushBytes(ctx, store, "application/vnd.oci.image.config.v1+json", []byte("{}"))
+	require.NoError(t, err)
+
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    cfg,
+		Layers:    []ocispec.Descriptor{layer},
+	})
+	require.NoError(t, err)
+	_, err = oras.TagBytes(ctx, store, ocispec.MediaTypeImageManifest, manifest,
+		strings.Replace(tagDigest, ":", "-", 1)+".sig")
+	require.NoError(t, err)
+}
+
+func TestVerifyCosignSignature(t *testing.T) {
+	dir := t.TempDir()
+	key, _ := newCosignKey(t, dir, "cosign.pub")
+	other, _ := newCosignKey(t, dir, "other.pub")
+
+	store := memory.New()
+	pushCosignSignature(t, store, key, chartDigest, chartDigest)
+	ctx := context.Background()
+
+	require.NoError(t, verifyCosignSignature(ctx, store, chartDigest, &key.PublicKey))
+
+	err := verifyCosignSignature(ctx, store, chartDigest, &other.PublicKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid ECDSA signature")
+
+	replayed := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("block-node-server 0.21.0")))
+	replayStore := memory.New()
+	pushCosignSignature(t, replayStore, key, replayed, chartDigest)
+	err = verifyCosignSignature(ctx, replayStore, replayed, &key.PublicKey)
+	require.Error(t, err, "a signature copied from another version does not verify")
+	assert.Contains(t, err.Error(), "signs "+chartDigest)
+
+	unsigned := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("unsigned")))
+	assert.ErrorIs(t, verifyCosignSignature(ctx, store, unsigned, &key.PublicKey), errdef.ErrNotFound)
+}
+
+func TestVerifyOCISignature_Policy(t *testing.T) {
+	dir := t.TempDir()
+	key, keyPath := newCosignKey(t, dir, "cosign.pub")
+	store := memory.New()
+	pushCosignSignature(t, store, key, chartDigest, chartDigest)
+
+	hm := newTestHelmManager(t)
+	var opened string
+	hm.signatureTarget = func(repository string) (oras.ReadOnlyTarget, error) {
+		opened = repository
+		return store, nil
+	}
+	const chartRef = "oci://ghcr.io/hiero-ledger/hiero-block-node/block-node-server"
+	ctx := context.Background()
+	unsigned := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("unsigned")))
+	missingKey := filepath.Join(dir, "missing.pub")
+
+	require.NoError(t, hm.verifyOCISignature(ctx, chartRef, "0.20.0", chartDigest,
+		Provenance{Policy: ProvenanceRequired, CosignKey: keyPath}))
+	assert.Equal(t, "ghcr.io/hiero-ledger/hiero-block-node/block-node-server", opened)
+
+	for _, tc := range []struct {
+		name   string
+		digest string
+		key    string
+	}{
+		{name: "no signature", digest: unsigned, key: keyPath},
+		{name: "no key", digest: chartDigest, key: missingKey},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := hm.verifyOCISignature(ctx, chartRef, "0.20.0", tc.digest, Provenance{Policy: ProvenanceRequired, CosignKey: tc.key})
+			require.Error(t, err)
+			assert.True(t, errorx.IsOfType(err, ErrProvenanceInvalid))
+
+			assert.NoError(t, hm.verifyOCISignature(ctx, chartRef, "0.20.0", tc.digest, Provenance{Policy: ProvenanceOptional, CosignKey: tc.key}),
+				"an optional policy only warns")
+		})
+	}
+
+	_, otherPath := newCosignKey(t, dir, "other.pub")
+	err := hm.verifyOCISignature(ctx, chartRef, "0.20.0", chartDigest, Provenance{Policy: ProvenanceOptional, CosignKey: otherPath})
+	require.Error(t, err, "a signature that does not verify fails even an optional policy")
+	assert.True(t, errorx.IsOfType(err, ErrProvenanceInvalid))
+
+	hm.signatureTarget = func(string) (oras.ReadOnlyTarget, error) {
+		return unreachableTarget{store}, nil
+	}
+	err = hm.verifyOCISignature(ctx, chartRef, "0.20.0", chartDigest, Provenance{Policy: ProvenanceRequired, CosignKey: keyPath})
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, errorx.ExternalError), "a registry failure is not an invalid signature")
+	assert.NoError(t, hm.verifyOCISignature(ctx, chartRef, "0.20.0", chartDigest, Provenance{Policy: ProvenanceOptional, CosignKey: keyPath}),
+		"an optional policy only warns when the signature cannot be fetched")
+}
+
+// unreachableTarget fails every lookup the way a registry outage does.
+type unreachableTarget struct {
+	oras.ReadOnlyTarget
+}
+
+func (unreachableTarget) Resolve(context.Context, string) (ocispec.Descriptor, error) {
+	return ocispec.Descriptor{}, errors.New("dial tcp: connection refused")
+}
+
+// newSignedChart saves a chart archive into dir and, when signer is set,
+// writes its provenance file beside it as `helm package --sign` does.
+func newSignedChart(t *testing.T, dir string, signer *openpgp.Entity) string {
+	t.Helper()
+	tgz, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{Name: "alloy", Version: "1.4.0", APIVersion: chart.APIVersionV2},
+		Values:   map[string]any{"replicas": 1},
+	}, dir)
+	require.NoError(t, err)
+	if signer != nil {
+		sig, err := (&provenance.Signatory{Entity: signer}).ClearSign(tgz)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(tgz+".prov", []byte(sig), 0o600))
+	}
+	return tgz
+}
+
+// newKeyring generates an OpenPGP key and writes its public half as a
+// keyring file into dir.
+func newKeyring(t *testing.T, dir, name string) (*openpgp.Entity, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Chart Signer", "", "charts@example.com", nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, entity.Serialize(&buf))
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+	return entity, path
+}
+
+func TestVerifyClassicProvenance(t *testing.T) {
+	keys := t.TempDir()
+	signer, keyring := newKeyring(t, keys, "charts.gpg")
+	_, otherKeyring := newKeyring(t, keys, "other.gpg")
+	hm := newTestHelmManager(t)
+
+	signed := newSignedChart(t, t.TempDir(), signer)
+	require.NoError(t, hm.verifyClassicProvenance(signed, "grafana/alloy", "1.4.0",
+		Provenance{Policy: ProvenanceRequired, Keyring: keyring}))
+
+	err := hm.verifyClassicProvenance(signed, "grafana/alloy", "1.4.0", Provenance{Policy: ProvenanceOptional, Keyring: otherKeyring})
+	require.Error(t, err, "a provenance file signed by another key fails even an optional policy")
+	assert.True(t, errorx.IsOfType(err, ErrProvenanceInvalid))
+
+	require.NoError(t, os.WriteFile(signed, append(mustRead(t, signed), 0), 0o600))
+	err = hm.verifyClassicProvenance(signed, "grafana/alloy", "1.4.0", Provenance{Policy: ProvenanceRequired, Keyring: keyring})
+	require.Error(t, err, "a tampered archive does not verify")
+	assert.True(t, errorx.IsOfType(err, ErrProvenanceInvalid))
+
+	unsigned := newSignedChart(t, t.TempDir(), nil)
+	err = hm.verifyClassicProvenance(unsigned, "grafana/alloy", "1.4.0", Provenance{Policy: ProvenanceRequired, Keyring: keyring})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no provenance file")
+	assert.NoError(t, hm.verifyClassicProvenance(unsigned, "grafana/alloy", "1.4.0",
+		Provenance{Policy: ProvenanceOptional, Keyring: keyring}))
+}
+
+func TestPullAndVerifyProvenance_PolicyOff(t *testing.T) {
+	hm := newTestHelmManager(t)
+	_, err := hm.PullAndVerifyProvenance(context.Background(), t.TempDir(), "grafana/alloy", "1.4.0", Provenance{Policy: ProvenanceOff})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not verify anything")
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
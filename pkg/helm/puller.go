@@ -16,6 +16,8 @@ import (
 	"github.com/joomcode/errorx"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/registry"
+
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
 )
 
 // ErrChecksumMismatch is returned when a pulled chart's digest does not match
@@ -33,7 +35,8 @@ const sha256Algorithm = "sha256"
 // For OCI charts the algorithm is the manifest digest reported by the Helm
 // registry client (which is itself a sha256 of the manifest descriptor).
 // In both cases the catalog stores a bare hex string (no "sha256:" prefix),
-// so the OCI path strips the prefix before comparison.
+// so the OCI path strips the prefix before comparison. WithProvenance
+// additionally verifies the chart's signature once the checksum matches.
 //
 // destDir is created on demand (mode 0o755). The caller decides where the
 // pulled .tgz lives — workflow steps point at <WeaverPaths.DownloadsDir>/charts;
@@ -46,8 +49,8 @@ const sha256Algorithm = "sha256"
 // runs to completion (success or timeout) regardless of ctx. Workflow steps
 // remain cancellable between catalog lookups and the pull call, but a slow
 // pull will block until the underlying HTTP transport's own deadlines fire.
-func (h *helmManager) PullAndVerify(ctx context.Context, destDir, chartRef, version, algorithm, expectedChecksum string) (string, error) {
-	_ = ctx // intentionally unused; see context-cancellation note above
+// Only the cosign signature lookup of OCI charts honours ctx.
+func (h *helmManager) PullAndVerify(ctx context.Context, destDir, chartRef, version, algorithm, expectedChecksum string, opts ...PullOption) (string, error) {
 	if algorithm != sha256Algorithm {
 		return "", errorx.IllegalArgument.New(
 			"unsupported checksum algorithm %q for chart %q (only %q is supported)",
@@ -56,35 +59,129 @@ func (h *helmManager) PullAndVerify(ctx context.Context, destDir, chartRef, vers
 	if expectedChecksum == "" {
 		return "", errorx.IllegalArgument.New("expected checksum is empty for chart %q version %q", chartRef, version)
 	}
+
+	o := pullOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tgz, _, err := h.pull(ctx, destDir, chartRef, version, expectedChecksum, o.provenance)
+	return tgz, err
+}
+
+// PullAndVerifyProvenance implements Manager.PullAndVerifyProvenance. It is
+// PullAndVerify without a checksum, for charts the catalog does not pin.
+func (h *helmManager) PullAndVerifyProvenance(ctx context.Context, destDir, chartRef, version string, p Provenance) (string, error) {
+	if !p.Enabled() {
+		return "", errorx.IllegalArgument.New(
+			"provenance policy %q for chart %q does not verify anything (must be %q or %q)",
+			p.Policy, chartRef, ProvenanceOptional, ProvenanceRequired)
+	}
+	tgz, _, err := h.pull(ctx, destDir, chartRef, version, "", p)
+	return tgz, err
+}
+
+// PullChart implements Manager.PullChart.
+func (h *helmManager) PullChart(ctx context.Context, destDir, chartRef, version string, opts ...PullOption) (string, string, error) {
+	o := pullOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return h.pull(ctx, destDir, chartRef, version, "", o.provenance)
+}
+
+// pull fetches the chart into destDir and verifies it: against expected when
+// it is set, then against p. It returns the .tgz path and the chart's
+// checksum. While an air-gap bundle is active, a chart the bundle holds is
+// copied out of it instead of pulled.
+func (h *helmManager) pull(ctx context.Context, destDir, chartRef, version, expected string, p Provenance) (string, string, error) {
 	if destDir == "" {
-		return "", errorx.IllegalArgument.New("destDir is empty for chart %q version %q", chartRef, version)
+		return "", "", errorx.IllegalArgument.New("destDir is empty for chart %q version %q", chartRef, version)
 	}
 	if err := os.MkdirAll(destDir, 0o755); err != nil {
-		return "", errorx.InternalError.Wrap(err, "failed to create chart downloads dir %q", destDir)
+		return "", "", errorx.InternalError.Wrap(err, "failed to create chart downloads dir %q", destDir)
+	}
+
+	if b := bundle.Active(); b != nil {
+		if c, ok := b.Chart(chartRef, version); ok {
+			return h.pullBundled(b, c, destDir, expected, p)
+		}
 	}
 
 	if registry.IsOCI(chartRef) {
-		return h.pullAndVerifyOCI(destDir, chartRef, version, expectedChecksum)
+		tgz, digest, err := h.pullOCI(destDir, chartRef, version, expected)
+		if err != nil {
+			return "", "", err
+		}
+		if p.Enabled() {
+			if err := h.verifyOCISignature(ctx, chartRef, version, digest, p); err != nil {
+				return "", "", err
+			}
+		}
+		return tgz, strings.TrimPrefix(digest, "sha256:"), nil
+	}
+
+	tgz, sum, err := h.pullClassic(destDir, chartRef, version, expected, p.Enabled())
+	if err != nil {
+		return "", "", err
 	}
-	return h.pullAndVerifyClassic(destDir, chartRef, version, expectedChecksum)
+	if p.Enabled() {
+		if err := h.verifyClassicProvenance(tgz, chartRef, version, p); err != nil {
+			return "", "", err
+		}
+	}
+	return tgz, sum, nil
 }
 
-// pullAndVerifyClassic uses action.Pull (the SDK equivalent of `helm pull`)
-// to fetch a chart from a classic repo into destDir and compares the SHA256
-// of the resulting .tgz to the catalog-recorded value. The repository alias
-// in chartRef (e.g. "metallb/metallb") must have been registered via AddRepo
-// before this call so the repository config can resolve the URL.
-func (h *helmManager) pullAndVerifyClassic(destDir, chartRef, version, expected string) (string, error) {
+// pullBundled copies chart c out of bundle b into destDir. The bundle
+// verifies the copy against the digest it recorded; the checksum it recorded
+// is then held to expected like a pulled chart's. The signature cannot be
+// checked offline, so p is satisfied only by the verification `bundle create`
+// recorded.
+func (h *helmManager) pullBundled(b *bundle.Bundle, c bundle.Chart, destDir, expected string, p Provenance) (string, string, error) {
+	if expected != "" && !strings.EqualFold(c.Checksum, expected) {
+		return "", "", ErrChecksumMismatch.New(
+			"bundled chart %q version %q checksum mismatch: expected sha256=%s, bundle has sha256=%s",
+			c.Chart, c.Version, expected, c.Checksum)
+	}
+	if p.Enabled() && !c.SignatureVerified {
+		if err := h.skipOrFail(p, c.Chart, c.Version, "its signature was not verified when the bundle was created"); err != nil {
+			return "", "", err
+		}
+	}
+
+	tgz := filepath.Join(destDir, fmt.Sprintf("%s-%s.tgz", path.Base(c.Chart), c.Version))
+	if err := b.CopyTo(c.File, tgz); err != nil {
+		return "", "", err
+	}
+	h.log.Info().
+		Str("chart", c.Chart).
+		Str("version", c.Version).
+		Str("bundle", b.Dir()).
+		Str("checksum", c.Checksum).
+		Bool("signatureVerified", c.SignatureVerified).
+		Msg("Helm chart resolved from the air-gap bundle")
+	return tgz, c.Checksum, nil
+}
+
+// pullClassic uses action.Pull (the SDK equivalent of `helm pull`) to fetch a
+// chart from a classic repo into destDir and, when expected is set, compares
+// the SHA256 of the resulting .tgz to the catalog-recorded value. With
+// fetchProv the repository's .prov file is fetched beside the .tgz when it
+// exists; it is not verified here. The repository alias in chartRef (e.g.
+// "metallb/metallb") must have been registered via AddRepo before this call
+// so the repository config can resolve the URL. It returns the .tgz path and
+// its SHA256.
+func (h *helmManager) pullClassic(destDir, chartRef, version, expected string, fetchProv bool) (string, string, error) {
 	settings := h.WithNamespace("")
 
 	registryClient, err := newRegistryClient(settings)
 	if err != nil {
-		return "", errorx.InternalError.Wrap(err, "failed to create registry client")
+		return "", "", errorx.InternalError.Wrap(err, "failed to create registry client")
 	}
 
 	actionConfig, err := initActionConfig(settings, h.log.Printf)
 	if err != nil {
-		return "", errorx.IllegalArgument.Wrap(err, "failed to init action config")
+		return "", "", errorx.IllegalArgument.Wrap(err, "failed to init action config")
 	}
 	actionConfig.RegistryClient = registryClient
 
@@ -93,10 +190,11 @@ func (h *helmManager) pullAndVerifyClassic(destDir, chartRef, version, expected
 	pull.DestDir = destDir
 	pull.Untar = false
 	pull.Version = version
+	pull.VerifyLater = fetchProv
 	pull.SetRegistryClient(registryClient)
 
 	if _, err := pull.Run(chartRef); err != nil {
-		return "", ErrChartLoadFailed.Wrap(err, "helm pull failed for chart %q version %q", chartRef, version)
+		return "", "", ErrChartLoadFailed.Wrap(err, "helm pull failed for chart %q version %q", chartRef, version)
 	}
 
 	// `helm pull <repo>/<chart>` writes <chart>-<version>.tgz (using the last
@@ -104,34 +202,30 @@ func (h *helmManager) pullAndVerifyClassic(destDir, chartRef, version, expected
 	tgz := filepath.Join(destDir, fmt.Sprintf("%s-%s.tgz", path.Base(chartRef), version))
 	actual, err := sha256File(tgz)
 	if err != nil {
-		return "", errorx.InternalError.Wrap(err, "failed to compute SHA256 of pulled chart %q", tgz)
+		return "", "", errorx.InternalError.Wrap(err, "failed to compute SHA256 of pulled chart %q", tgz)
 	}
-	if !strings.EqualFold(actual, expected) {
-		return "", ErrChecksumMismatch.New(
+	if expected != "" && !strings.EqualFold(actual, expected) {
+		return "", "", ErrChecksumMismatch.New(
 			"chart %q version %q checksum mismatch: expected sha256=%s, got sha256=%s",
 			chartRef, version, expected, actual)
 	}
 
-	h.log.Info().
-		Str("chart", chartRef).
-		Str("version", version).
-		Str("algorithm", sha256Algorithm).
-		Str("checksum", actual).
-		Msg("Helm chart pulled and verified")
-
-	return tgz, nil
+	h.logPulled(chartRef, version, actual, expected != "")
+	return tgz, actual, nil
 }
 
-// pullAndVerifyOCI calls the registry client directly so we can read the
-// manifest digest from PullResult.Manifest.Digest (which is reported by
-// `helm pull` as the "Digest: sha256:..." line). The chart layer bytes are
-// then written to disk so callers can pass the local path to InstallChart.
-func (h *helmManager) pullAndVerifyOCI(destDir, chartRef, version, expected string) (string, error) {
+// pullOCI calls the registry client directly so we can read the manifest
+// digest from PullResult.Manifest.Digest (which is reported by `helm pull` as
+// the "Digest: sha256:..." line) and, when expected is set, compare it to the
+// catalog-recorded value. The chart layer bytes are then written to disk so
+// callers can pass the local path to InstallChart. It returns the .tgz path
+// and the manifest digest ("sha256:<hex>").
+func (h *helmManager) pullOCI(destDir, chartRef, version, expected string) (string, string, error) {
 	settings := h.WithNamespace("")
 
 	registryClient, err := newRegistryClient(settings)
 	if err != nil {
-		return "", errorx.InternalError.Wrap(err, "failed to create registry client")
+		return "", "", errorx.InternalError.Wrap(err, "failed to create registry client")
 	}
 
 	// `helm pull oci://host/path/chart --version X` translates internally to
@@ -139,32 +233,39 @@ func (h *helmManager) pullAndVerifyOCI(destDir, chartRef, version, expected stri
 	ref := fmt.Sprintf("%s:%s", strings.TrimPrefix(chartRef, "oci://"), version)
 	result, err := registryClient.Pull(ref, registry.PullOptWithChart(true))
 	if err != nil {
-		return "", ErrChartLoadFailed.Wrap(err, "helm pull failed for OCI chart %q version %q", chartRef, version)
+		return "", "", ErrChartLoadFailed.Wrap(err, "helm pull failed for OCI chart %q version %q", chartRef, version)
 	}
 	if result == nil || result.Manifest == nil || result.Chart == nil {
-		return "", ErrChartLoadFailed.New("helm pull returned incomplete result for OCI chart %q version %q", chartRef, version)
+		return "", "", ErrChartLoadFailed.New("helm pull returned incomplete result for OCI chart %q version %q", chartRef, version)
 	}
 
 	actual := strings.TrimPrefix(result.Manifest.Digest, "sha256:")
-	if !strings.EqualFold(actual, expected) {
-		return "", ErrChecksumMismatch.New(
+	if expected != "" && !strings.EqualFold(actual, expected) {
+		return "", "", ErrChecksumMismatch.New(
 			"chart %q version %q manifest digest mismatch: expected sha256=%s, got sha256=%s",
 			chartRef, version, expected, actual)
 	}
 
 	tgz := filepath.Join(destDir, fmt.Sprintf("%s-%s.tgz", path.Base(chartRef), version))
 	if err := os.WriteFile(tgz, result.Chart.Data, 0o600); err != nil {
-		return "", errorx.InternalError.Wrap(err, "failed to write pulled OCI chart to %q", tgz)
+		return "", "", errorx.InternalError.Wrap(err, "failed to write pulled OCI chart to %q", tgz)
 	}
 
+	h.logPulled(chartRef, version, actual, expected != "")
+	return tgz, result.Manifest.Digest, nil
+}
+
+func (h *helmManager) logPulled(chartRef, version, checksum string, verified bool) {
+	msg := "Helm chart pulled and verified"
+	if !verified {
+		msg = "Helm chart pulled"
+	}
 	h.log.Info().
 		Str("chart", chartRef).
 		Str("version", version).
 		Str("algorithm", sha256Algorithm).
-		Str("checksum", actual).
-		Msg("Helm chart pulled and verified")
-
-	return tgz, nil
+		Str("checksum", checksum).
+		Msg(msg)
 }
 
 // sha256File returns the lowercase hex SHA256 of the file at path.
@@ -10,8 +10,11 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/joomcode/errorx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
 )
 
 func TestPullAndVerify_UnsupportedAlgorithm(t *testing.T) {
@@ -60,3 +63,69 @@ func newTestHelmManager(t *testing.T) *helmManager {
 	t.Setenv("HELM_REPOSITORY_CACHE", filepath.Join(home, "cache", "repository"))
 	return &helmManager{}
 }
+
+// activateTestBundle activates a bundle holding one chart archive and returns
+// the archive's SHA256.
+func activateTestBundle(t *testing.T, chartRef, version string, signatureVerified bool) string {
+	t.Helper()
+	dir := t.TempDir()
+	payload := []byte("chart archive")
+	sum := sha256.Sum256(payload)
+	hexSum := hex.EncodeToString(sum[:])
+
+	rel := filepath.Join(bundle.ChartsDir, "chart.tgz")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, bundle.ChartsDir), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, rel), payload, 0o600))
+	require.NoError(t, bundle.WriteManifest(dir, bundle.Manifest{
+		Charts: []bundle.Chart{{
+			File:              bundle.File{Path: rel, SHA256: hexSum},
+			Chart:             chartRef,
+			Version:           version,
+			Checksum:          hexSum,
+			SignatureVerified: signatureVerified,
+		}},
+	}))
+	b, err := bundle.Open(dir)
+	require.NoError(t, err)
+	bundle.Activate(b)
+	t.Cleanup(func() { bundle.Activate(nil) })
+	return hexSum
+}
+
+func TestPullAndVerify_FromActiveBundle(t *testing.T) {
+	hm := newTestHelmManager(t)
+	sum := activateTestBundle(t, "metallb/metallb", "0.15.2", false)
+
+	// The repository alias was never added: the chart can only come from the bundle.
+	tgz, err := hm.PullAndVerify(context.Background(), t.TempDir(), "metallb/metallb", "0.15.2", "sha256", sum)
+	require.NoError(t, err)
+	assert.Equal(t, "metallb-0.15.2.tgz", filepath.Base(tgz))
+	got, err := sha256File(tgz)
+	require.NoError(t, err)
+	assert.Equal(t, sum, got)
+
+	_, err = hm.PullAndVerify(context.Background(), t.TempDir(), "metallb/metallb", "0.15.2", "sha256", "deadbeef")
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, ErrChecksumMismatch))
+}
+
+func TestPullChart_FromActiveBundle_SignaturePolicy(t *testing.T) {
+	hm := newTestHelmManager(t)
+	const chartRef = "oci://ghcr.io/hiero-ledger/hiero-block-node/block-node-server"
+	sum := activateTestBundle(t, chartRef, "0.20.0", false)
+
+	_, _, err := hm.PullChart(context.Background(), t.TempDir(), chartRef, "0.20.0",
+		WithProvenance(Provenance{Policy: ProvenanceRequired}))
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, ErrProvenanceInvalid))
+
+	_, got, err := hm.PullChart(context.Background(), t.TempDir(), chartRef, "0.20.0",
+		WithProvenance(Provenance{Policy: ProvenanceOptional}))
+	require.NoError(t, err)
+	assert.Equal(t, sum, got)
+
+	activateTestBundle(t, chartRef, "0.20.0", true)
+	_, _, err = hm.PullChart(context.Background(), t.TempDir(), chartRef, "0.20.0",
+		WithProvenance(Provenance{Policy: ProvenanceRequired}))
+	require.NoError(t, err)
+}
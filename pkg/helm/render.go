@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"strings"
+
+	"github.com/joomcode/errorx"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// RenderManifests renders the chart archive at chartPath the way
+// `helm template --include-crds` does, without a cluster, and returns the
+// manifests of the release followed by those of its hooks. kubeVersion sets
+// .Capabilities.KubeVersion (e.g. "v1.33.4"); empty keeps Helm's default.
+func RenderManifests(chartPath, releaseName, namespace, kubeVersion string, vals map[string]interface{}) (string, error) {
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return "", ErrChartLoadFailed.Wrap(err, "failed to load chart %q", chartPath)
+	}
+
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = true
+	install.ReleaseName = releaseName
+	install.Namespace = namespace
+	if kubeVersion != "" {
+		kv, err := chartutil.ParseKubeVersion(kubeVersion)
+		if err != nil {
+			return "", errorx.IllegalArgument.Wrap(err, "invalid Kubernetes version %q", kubeVersion)
+		}
+		install.KubeVersion = kv
+	}
+
+	rel, err := install.Run(chrt, vals)
+	if err != nil {
+		return "", ErrInstallFailed.Wrap(err, "failed to render chart %q", chartPath)
+	}
+
+	var out strings.Builder
+	out.WriteString(rel.Manifest)
+	for _, hook := range rel.Hooks {
+		out.WriteString("\n---\n")
+		out.WriteString(hook.Manifest)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// schemaURL is the location the chart's values.schema.json is compiled
+// under; it only appears in error messages.
+const schemaURL = "file:///values.schema.json"
+
+// ValuesIssue is one problem ValidateValues found. Path is a JSON pointer into
+// the values, e.g. "/blockNode/persistence/live/size".
+type ValuesIssue struct {
+	Path    string
+	Message string
+}
+
+func (i ValuesIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Message)
+}
+
+// ValidateValues checks the values vals (the merged value files and --set
+// values) for chrt before it is installed or upgraded, so a typo fails the
+// command instead of silently becoming a no-op key.
+//
+// A chart (or subchart) that ships values.schema.json is checked against its
+// schema, with vals coalesced over the chart's defaults as Helm renders them.
+// A chart without one is checked for top-level keys its default values.yaml
+// does not define; subchart and "global" keys are always allowed. Templates
+// may read keys values.yaml does not list, so those keys only fail when
+// rejectUnknown is set, i.e. when the values come from the operator; otherwise
+// they are returned for the caller to warn about. Returns an ErrValuesInvalid
+// listing every rejected issue, or nil.
+func ValidateValues(chrt *chart.Chart, vals map[string]any, rejectUnknown bool) ([]ValuesIssue, error) {
+	merged, err := chartutil.CoalesceValues(chrt, vals)
+	if err != nil {
+		return nil, ErrValuesInvalid.Wrap(err, "failed to validate values for chart %s", chrt.Name())
+	}
+
+	issues, unknown, err := chartValuesIssues(chrt, vals, merged, "")
+	if err != nil {
+		return nil, ErrValuesInvalid.Wrap(err, "failed to validate values for chart %s", chrt.Name())
+	}
+	if rejectUnknown {
+		issues = append(issues, unknown...)
+		unknown = nil
+	}
+	if len(issues) == 0 {
+		return unknown, nil
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	lines := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	resolution := []string{"fix the listed keys in the values file; paths are JSON pointers into the values"}
+	if rejectUnknown {
+		// Only the commands that take operator values offer the flag.
+		resolution = append(resolution, "pass --skip-values-validation to install the values unchecked")
+	}
+	return unknown, ErrValuesInvalid.New("values for chart %s %s are invalid:\n  %s",
+		chrt.Name(), chrt.Metadata.Version, strings.Join(lines, "\n  ")).
+		WithProperty(models.ErrPropertyResolution, resolution)
+}
+
+// chartValuesIssues checks user (the values addressed to chrt) and merged
+// (user coalesced over the chart's defaults) for chrt and its subcharts, and
+// returns the schema violations and the unknown keys of schemaless charts
+// apart. prefix is the JSON pointer of chrt's values within the top-level
+// values.
+func chartValuesIssues(chrt *chart.Chart, user, merged map[string]any, prefix string) ([]ValuesIssue, []ValuesIssue, error) {
+	var issues, unknown []ValuesIssue
+	if len(chrt.Schema) > 0 {
+		schemaIssues, err := validateAgainstSchema(chrt.Schema, merged, prefix)
+		if err != nil {
+			return nil, nil, err
+		}
+		issues = append(issues, schemaIssues...)
+	} else {
+		unknown = append(unknown, unknownKeys(chrt, user, prefix)...)
+	}
+
+	for key, sub := range subcharts(chrt) {
+		subUser, _ := user[key].(map[string]any)
+		subMerged, _ := merged[key].(map[string]any)
+		if len(subUser) == 0 {
+			continue
+		}
+		subIssues, subUnknown, err := chartValuesIssues(sub, subUser, subMerged, prefix+"/"+escapePointer(key))
+		if err != nil {
+			return nil, nil, err
+		}
+		issues = append(issues, subIssues...)
+		unknown = append(unknown, subUnknown...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	sort.SliceStable(unknown, func(i, j int) bool { return unknown[i].Path < unknown[j].Path })
+	return issues, unknown, nil
+}
+
+// validateValues runs ValidateValues and logs the unknown keys it did not
+// reject.
+func (h *helmManager) validateValues(chrt *chart.Chart, vals map[string]any, rejectUnknown bool) error {
+	unknown, err := ValidateValues(chrt, vals, rejectUnknown)
+	for _, issue := range unknown {
+		h.log.Warn().
+			Str("chart", chrt.Name()).
+			Str("path", issue.Path).
+			Msg("Helm value is not defined in the chart's default values")
+	}
+	return err
+}
+
+// subcharts returns chrt's dependencies by the key their values live under:
+// the alias when Chart.yaml sets one, else the chart name.
+func subcharts(chrt *chart.Chart) map[string]*chart.Chart {
+	aliases := map[string]string{}
+	if chrt.Metadata != nil {
+		for _, dep := range chrt.Metadata.Dependencies {
+			if dep.Alias != "" {
+				aliases[dep.Name] = dep.Alias
+			}
+		}
+	}
+	out := map[string]*chart.Chart{}
+	for _, dep := range chrt.Dependencies() {
+		key := dep.Name()
+		if alias, ok := aliases[key]; ok {
+			key = alias
+		}
+		out[key] = dep
+	}
+	return out
+}
+
+// unknownKeys reports the top-level keys of user that chrt's default values,
+// its subcharts and Helm's "global" block do not define. A chart without
+// default values accepts anything.
+func unknownKeys(chrt *chart.Chart, user map[string]any, prefix string) []ValuesIssue {
+	if len(chrt.Values) == 0 {
+		return nil
+	}
+	subs := subcharts(chrt)
+	var issues []ValuesIssue
+	for key := range user {
+		if _, ok := chrt.Values[key]; ok {
+			continue
+		}
+		if _, ok := subs[key]; ok || key == chartutil.GlobalKey {
+			continue
+		}
+		issues = append(issues, ValuesIssue{
+			Path:    prefix + "/" + escapePointer(key),
+			Message: fmt.Sprintf("unknown key; chart %s does not define it in its default values", chrt.Name()),
+		})
+	}
+	return issues
+}
+
+// validateAgainstSchema validates vals against the JSON schema and returns one
+// issue per failing leaf. Remote $refs are not fetched: a schema that needs
+// them fails to compile, and the error is returned.
+func validateAgainstSchema(schemaJSON []byte, vals map[string]any, prefix string) ([]ValuesIssue, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid values.schema.json: %w", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("invalid values.schema.json: %w", err)
+	}
+	schema, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values.schema.json: %w", err)
+	}
+
+	// Round-trip through JSON so the instance only holds the types the
+	// validator understands (YAML-decoded values may hold int64 or nested
+	// map[string]interface{} aliases).
+	raw, err := json.Marshal(vals)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	var verr *jsonschema.ValidationError
+	if err := schema.Validate(instance); err == nil {
+		return nil, nil
+	} else if !errors.As(err, &verr) {
+		return nil, err
+	}
+
+	printer := message.NewPrinter(language.English)
+	var issues []ValuesIssue
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			path := prefix + jsonPointer(e.InstanceLocation)
+			if path == "" {
+				path = "/"
+			}
+			issues = append(issues, ValuesIssue{
+				Path:    path,
+				Message: e.ErrorKind.LocalizedString(printer),
+			})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(verr)
+	return issues, nil
+}
+
+// jsonPointer renders a validator instance location as a JSON pointer; the
+// document root is "".
+func jsonPointer(location []string) string {
+	var sb strings.Builder
+	for _, token := range location {
+		sb.WriteString("/")
+		sb.WriteString(escapePointer(token))
+	}
+	return sb.String()
+}
+
+// escapePointer escapes a key for use as a JSON pointer token (RFC 6901).
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const blockNodeSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "replicas": {"type": "integer", "minimum": 1},
+    "image": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {"repository": {"type": "string"}, "tag": {"type": "string"}}
+    },
+    "global": {"type": "object"}
+  }
+}`
+
+func testChart(name string, values map[string]any, schema string) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: name, Version: "0.1.0", APIVersion: chart.APIVersionV2},
+		Values:   values,
+		Schema:   []byte(schema),
+	}
+}
+
+func TestValidateValues_Schema(t *testing.T) {
+	chrt := testChart("block-node", map[string]any{"replicas": 1, "image": map[string]any{"tag": "v0.1"}}, blockNodeSchema)
+
+	_, err := ValidateValues(chrt, map[string]any{"replicas": 3, "image": map[string]any{"tag": "v0.2"}}, false)
+	require.NoError(t, err)
+
+	_, err = ValidateValues(chrt, map[string]any{
+		"replicas": "three",
+		"image":    map[string]any{"tga": "v0.2"},
+	}, false)
+	require.Error(t, err, "schema violations fail even when unknown keys are only warned about")
+	assert.True(t, errorx.IsOfType(err, ErrValuesInvalid))
+	assert.ErrorContains(t, err, "/image: additional properties 'tga' not allowed")
+	assert.ErrorContains(t, err, "/replicas: got string, want integer")
+	assert.Equal(t, "v0.1", chrt.Values["image"].(map[string]any)["tag"], "the chart defaults are not modified")
+}
+
+func TestValidateValues_UnknownKeys(t *testing.T) {
+	chrt := testChart("teleport-kube-agent", map[string]any{"proxyAddr": "", "roles": "kube"}, "")
+	sub := testChart("metrics", map[string]any{"enabled": false}, "")
+	chrt.Metadata.Dependencies = []*chart.Dependency{{Name: "metrics", Alias: "monitoring"}}
+	chrt.SetDependencies(sub)
+
+	_, err := ValidateValues(chrt, map[string]any{
+		"proxyAddr":  "teleport.example.com:443",
+		"global":     map[string]any{"region": "eu"},
+		"monitoring": map[string]any{"enabled": true},
+	}, true)
+	require.NoError(t, err, "subchart aliases and global are allowed")
+
+	typos := map[string]any{
+		"proxyAdr":   "teleport.example.com:443",
+		"monitoring": map[string]any{"enabeld": true},
+	}
+	_, err = ValidateValues(chrt, typos, true)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "/monitoring/enabeld: unknown key; chart metrics does not define it")
+	assert.ErrorContains(t, err, "/proxyAdr: unknown key; chart teleport-kube-agent does not define it")
+
+	unknown, err := ValidateValues(chrt, typos, false)
+	require.NoError(t, err, "unknown keys only fail operator-supplied values")
+	require.Len(t, unknown, 2)
+	assert.Equal(t, "/monitoring/enabeld", unknown[0].Path)
+	assert.Equal(t, "/proxyAdr", unknown[1].Path)
+}
+
+func TestValidateValues_NoDefaults(t *testing.T) {
+	unknown, err := ValidateValues(testChart("bare", nil, ""), map[string]any{"anything": true}, true)
+	require.NoError(t, err, "a chart without defaults or schema accepts any key")
+	assert.Empty(t, unknown)
+}
+
+func TestJSONPointer(t *testing.T) {
+	assert.Equal(t, "", jsonPointer(nil))
+	assert.Equal(t, "/metadata/annotations/metallb.io~1address-pool/a~0b",
+		jsonPointer([]string{"metadata", "annotations", "metallb.io/address-pool", "a~b"}))
+}
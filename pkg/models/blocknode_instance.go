@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"path"
+	"strings"
+
+	"github.com/hashgraph/solo-weaver/pkg/deps"
+)
+
+// DefaultBlockNodeServicePort is the block node gRPC service port (the chart's
+// `service.port` default). The default instance keeps it; every additional
+// instance on the host is given its own port above it.
+const DefaultBlockNodeServicePort = 40840
+
+// DefaultBlockNodeLoadBalancerPool is the MetalLB address pool the block node
+// service is announced from unless --load-balancer-pool names another one.
+const DefaultBlockNodeLoadBalancerPool = "public-address-pool"
+
+// BlockNodeInstance returns the name that sets the block node deployed as
+// release apart from the other block nodes on the host. It is empty for the
+// default release (and an unset one), so a single-instance host keeps its
+// policy set names, daemon config and storage sample files unchanged; any
+// other release is its own name.
+func BlockNodeInstance(release string) string {
+	if release == "" || release == deps.BLOCK_NODE_RELEASE {
+		return ""
+	}
+	return release
+}
+
+// BlockNodeStoragePaths returns the storage volumes and samples files of a
+// block node instance (see BlockNodeInstance). The default instance uses
+// DaemonBlockNodeStorageVolumesPath and DaemonBlockNodeStorageSamplesPath;
+// other instances get their own pair beside them.
+func (pp WeaverPaths) BlockNodeStoragePaths(instance string) (volumes, samples string) {
+	if instance == "" {
+		return pp.DaemonBlockNodeStorageVolumesPath, pp.DaemonBlockNodeStorageSamplesPath
+	}
+	return path.Join(pp.DaemonBlockNodeDir, "storage-volumes-"+instance+".json"),
+		path.Join(pp.DaemonBlockNodeDir, "storage-samples-"+instance+".jsonl")
+}
+
+// BlockNodeProbePaths returns the probe target and history files of a block
+// node instance (see BlockNodeInstance). The default instance uses
+// DaemonBlockNodeProbeTargetPath and DaemonBlockNodeProbeHistoryPath; other
+// instances get their own pair beside them.
+func (pp WeaverPaths) BlockNodeProbePaths(instance string) (target, history string) {
+	if instance == "" {
+		return pp.DaemonBlockNodeProbeTargetPath, pp.DaemonBlockNodeProbeHistoryPath
+	}
+	return path.Join(pp.DaemonBlockNodeDir, "probe-target-"+instance+".json"),
+		path.Join(pp.DaemonBlockNodeDir, "probe-history-"+instance+".jsonl")
+}
+
+// BlockNodePolicyName returns the network policy (and nft set) name a block
+// node instance uses for the policy the default instance calls name: the
+// instance is spliced in after the "bn-" prefix ("bn-publisher" becomes
+// "bn-<instance>-publisher"), so the derived v6, ports and counter names of
+// two instances never overlap either. The default instance keeps name.
+func BlockNodePolicyName(instance, name string) string {
+	if instance == "" {
+		return name
+	}
+	return "bn-" + instance + "-" + strings.TrimPrefix(name, "bn-")
+}
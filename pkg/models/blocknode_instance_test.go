@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"path"
+	"testing"
+
+	"github.com/hashgraph/solo-weaver/pkg/deps"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockNodeInstance(t *testing.T) {
+	assert.Equal(t, "", BlockNodeInstance(""))
+	assert.Equal(t, "", BlockNodeInstance(deps.BLOCK_NODE_RELEASE))
+	assert.Equal(t, "testnet", BlockNodeInstance("testnet"))
+}
+
+func TestBlockNodePolicyName(t *testing.T) {
+	assert.Equal(t, "bn-publisher", BlockNodePolicyName("", "bn-publisher"))
+	assert.Equal(t, "bn-testnet-publisher", BlockNodePolicyName("testnet", "bn-publisher"))
+	assert.Equal(t, "bn-testnet-partner-out", BlockNodePolicyName("testnet", "bn-partner-out"))
+}
+
+func TestWeaverPaths_BlockNodeStoragePaths(t *testing.T) {
+	pp := Paths()
+
+	volumes, samples := pp.BlockNodeStoragePaths("")
+	assert.Equal(t, pp.DaemonBlockNodeStorageVolumesPath, volumes)
+	assert.Equal(t, pp.DaemonBlockNodeStorageSamplesPath, samples)
+
+	volumes, samples = pp.BlockNodeStoragePaths("testnet")
+	assert.NotEqual(t, pp.DaemonBlockNodeStorageVolumesPath, volumes)
+	assert.NotEqual(t, pp.DaemonBlockNodeStorageSamplesPath, samples)
+	assert.Contains(t, volumes, "testnet")
+	assert.Contains(t, samples, "testnet")
+}
+
+func TestWeaverPaths_BlockNodeProbePaths(t *testing.T) {
+	pp := Paths()
+
+	target, history := pp.BlockNodeProbePaths("")
+	assert.Equal(t, pp.DaemonBlockNodeProbeTargetPath, target)
+	assert.Equal(t, pp.DaemonBlockNodeProbeHistoryPath, history)
+
+	target, history = pp.BlockNodeProbePaths("testnet")
+	assert.Equal(t, path.Join(pp.DaemonBlockNodeDir, "probe-target-testnet.json"), target)
+	assert.Equal(t, path.Join(pp.DaemonBlockNodeDir, "probe-history-testnet.jsonl"), history)
+}
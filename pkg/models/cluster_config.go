@@ -0,0 +1,352 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"bytes"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/pkg/sanity"
+)
+
+const (
+	DefaultClusterName          = "k8s.main.gcp"
+	DefaultClusterDNSDomain     = "cluster.local"
+	DefaultClusterServiceSubnet = "10.0.0.0/14"
+	DefaultNodeCIDRMaskSize     = 24
+
+	// AdvertisementL2 and AdvertisementBGP are how MetalLB announces the
+	// addresses of a pool: ARP/NDP on the local segment, or BGP to the peers.
+	AdvertisementL2  = "l2"
+	AdvertisementBGP = "bgp"
+
+	// MachineIPAddress stands for the host's own IP in a pool's addresses; it
+	// is rendered as <machine-ip>/32 when MetalLB is deployed.
+	MachineIPAddress = "machine-ip"
+
+	// maxServiceSubnetBits is how many host bits kube-apiserver allows the
+	// service subnet (a /12 for IPv4).
+	maxServiceSubnetBits = 20
+	// maxNodeCIDRBits is how many bits kube-controller-manager allows between
+	// the pod subnet prefix and the node CIDR mask size.
+	maxNodeCIDRBits = 16
+	// maxNodeCIDRMaskSize leaves each node at least 16 pod addresses.
+	maxNodeCIDRMaskSize = 28
+)
+
+// objectNamePattern is a Kubernetes object name (RFC 1123 label): MetalLB
+// pool and peer names become IPAddressPool and BGPPeer names.
+var objectNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ClusterConfig represents the `cluster` configuration block: the network
+// layout `kube cluster install` initialises the cluster with and the MetalLB
+// address pools it deploys. The layout is recorded in ClusterState once the
+// cluster is up, so later runs render the same values; zero fields fall back
+// to the recorded layout and then to DefaultClusterConfig.
+type ClusterConfig struct {
+	Name             string             `yaml:"name" json:"name"`                         // kubeadm clusterName
+	DNSDomain        string             `yaml:"dnsDomain" json:"dnsDomain"`               // cluster DNS domain
+	ServiceSubnet    string             `yaml:"serviceSubnet" json:"serviceSubnet"`       // ClusterIP range
+	PodSubnet        string             `yaml:"podSubnet" json:"podSubnet"`               // pod range; also the host firewall's default pod CIDR
+	NodeCIDRMaskSize int                `yaml:"nodeCidrMaskSize" json:"nodeCidrMaskSize"` // prefix length of each node's slice of the pod subnet
+	LoadBalancer     LoadBalancerConfig `yaml:"loadBalancer" json:"loadBalancer"`
+}
+
+// LoadBalancerConfig is the MetalLB configuration: the address pools
+// LoadBalancer services are assigned from and the BGP peers the pools with a
+// bgp advertisement are announced to.
+type LoadBalancerConfig struct {
+	Pools    []AddressPool `yaml:"pools" json:"pools"`
+	BGPPeers []BGPPeer     `yaml:"bgpPeers" json:"bgpPeers"`
+}
+
+// AddressPool is a MetalLB IPAddressPool. Addresses are CIDRs, ranges
+// ("192.168.10.10-192.168.10.20") or MachineIPAddress; Advertisement is
+// AdvertisementL2 (the default) or AdvertisementBGP.
+type AddressPool struct {
+	Name          string   `yaml:"name" json:"name"`
+	Addresses     []string `yaml:"addresses" json:"addresses"`
+	Advertisement string   `yaml:"advertisement" json:"advertisement"`
+}
+
+// BGPPeer is a MetalLB BGPPeer the bgp pools are announced to.
+type BGPPeer struct {
+	Name        string `yaml:"name" json:"name"`
+	PeerAddress string `yaml:"peerAddress" json:"peerAddress"`
+	PeerASN     uint32 `yaml:"peerAsn" json:"peerAsn"`
+	MyASN       uint32 `yaml:"myAsn" json:"myAsn"`
+}
+
+// DefaultClusterConfig returns the layout clusters were initialised with
+// before it was configurable: a private L2 pool plus the host IP as the public
+// pool.
+func DefaultClusterConfig() ClusterConfig {
+	return ClusterConfig{
+		Name:             DefaultClusterName,
+		DNSDomain:        DefaultClusterDNSDomain,
+		ServiceSubnet:    DefaultClusterServiceSubnet,
+		PodSubnet:        DefaultClusterPodCIDR,
+		NodeCIDRMaskSize: DefaultNodeCIDRMaskSize,
+		LoadBalancer: LoadBalancerConfig{
+			Pools: []AddressPool{
+				{Name: "private-address-pool", Addresses: []string{"192.168.99.0/24"}, Advertisement: AdvertisementL2},
+				{Name: "public-address-pool", Addresses: []string{MachineIPAddress}, Advertisement: AdvertisementL2},
+			},
+		},
+	}
+}
+
+// Merge returns c with its zero fields taken from fallback. The load balancer
+// is taken as a whole: pools without the peers they are announced to, or the
+// other way round, are not a usable configuration.
+func (c ClusterConfig) Merge(fallback ClusterConfig) ClusterConfig {
+	r := c
+	if r.Name == "" {
+		r.Name = fallback.Name
+	}
+	if r.DNSDomain == "" {
+		r.DNSDomain = fallback.DNSDomain
+	}
+	if r.ServiceSubnet == "" {
+		r.ServiceSubnet = fallback.ServiceSubnet
+	}
+	if r.PodSubnet == "" {
+		r.PodSubnet = fallback.PodSubnet
+	}
+	if r.NodeCIDRMaskSize == 0 {
+		r.NodeCIDRMaskSize = fallback.NodeCIDRMaskSize
+	}
+	if len(r.LoadBalancer.Pools) == 0 {
+		r.LoadBalancer = fallback.LoadBalancer
+	}
+	return r
+}
+
+// Validate checks each field and the fields against each other: the service
+// and pod subnets and the pools must not overlap, the node CIDR mask must fit
+// the pod subnet and every bgp pool needs a peer. Zero fields are skipped, so
+// a partial `cluster` block validates; validate the merged layout before
+// rendering it.
+func (c ClusterConfig) Validate() error {
+	if c.Name != "" {
+		if err := sanity.ValidateDNSName(c.Name); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid cluster name: %s", c.Name)
+		}
+	}
+	if c.DNSDomain != "" {
+		if err := sanity.ValidateDNSName(c.DNSDomain); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid cluster dnsDomain: %s", c.DNSDomain)
+		}
+	}
+
+	var service, pod *net.IPNet
+	if c.ServiceSubnet != "" {
+		if err := sanity.ValidateIPv4CIDR(c.ServiceSubnet); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid cluster serviceSubnet: %s", c.ServiceSubnet)
+		}
+		_, service, _ = net.ParseCIDR(c.ServiceSubnet)
+		if ones, bits := service.Mask.Size(); bits-ones > maxServiceSubnetBits {
+			return errorx.IllegalArgument.New("cluster serviceSubnet %s is larger than a /%d", c.ServiceSubnet, bits-maxServiceSubnetBits).
+				WithProperty(ErrPropertyResolution, "use a service subnet of at most 1M addresses, e.g. 10.0.0.0/14")
+		}
+	}
+	if c.PodSubnet != "" {
+		if err := sanity.ValidateIPv4CIDR(c.PodSubnet); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid cluster podSubnet: %s", c.PodSubnet)
+		}
+		_, pod, _ = net.ParseCIDR(c.PodSubnet)
+	}
+	if service != nil && pod != nil && cidrsOverlap(service, pod) {
+		return errorx.IllegalArgument.New("cluster serviceSubnet %s overlaps podSubnet %s", c.ServiceSubnet, c.PodSubnet).
+			WithProperty(ErrPropertyResolution, "pick disjoint service and pod subnets")
+	}
+
+	if c.NodeCIDRMaskSize != 0 {
+		if c.NodeCIDRMaskSize < 1 || c.NodeCIDRMaskSize > maxNodeCIDRMaskSize {
+			return errorx.IllegalArgument.New("invalid cluster nodeCidrMaskSize %d: must be between 1 and %d", c.NodeCIDRMaskSize, maxNodeCIDRMaskSize)
+		}
+		if pod != nil {
+			ones, _ := pod.Mask.Size()
+			if c.NodeCIDRMaskSize <= ones || c.NodeCIDRMaskSize-ones > maxNodeCIDRBits {
+				return errorx.IllegalArgument.New("cluster nodeCidrMaskSize /%d does not fit podSubnet %s", c.NodeCIDRMaskSize, c.PodSubnet).
+					WithProperty(ErrPropertyResolution,
+						"use a mask size longer than the pod subnet's prefix by at most 16 bits, e.g. /24 for a /14")
+			}
+		}
+	}
+
+	return c.LoadBalancer.validate(service, pod)
+}
+
+// CheckUnchanged rejects a layout that sets a field kubeadm fixes at init —
+// the name, DNS domain, subnets and node CIDR mask size — to anything other
+// than the value recorded for the running cluster. Zero fields and the load
+// balancer, which can be reconfigured, are not checked.
+func (c ClusterConfig) CheckUnchanged(recorded ClusterConfig) error {
+	for _, f := range []struct {
+		name            string
+		value, recorded string
+	}{
+		{"name", c.Name, recorded.Name},
+		{"dnsDomain", c.DNSDomain, recorded.DNSDomain},
+		{"serviceSubnet", c.ServiceSubnet, recorded.ServiceSubnet},
+		{"podSubnet", c.PodSubnet, recorded.PodSubnet},
+	} {
+		if f.value != "" && f.recorded != "" && f.value != f.recorded {
+			return errorx.IllegalState.New("cannot change the cluster %s from %s to %s after the cluster was initialised", f.name, f.recorded, f.value).
+				WithProperty(ErrPropertyResolution, []string{
+					"drop the setting to keep the recorded value",
+					"or reinstall the cluster: sudo solo-provisioner kube cluster uninstall",
+				})
+		}
+	}
+	if c.NodeCIDRMaskSize != 0 && recorded.NodeCIDRMaskSize != 0 && c.NodeCIDRMaskSize != recorded.NodeCIDRMaskSize {
+		return errorx.IllegalState.New("cannot change the cluster nodeCidrMaskSize from %d to %d after the cluster was initialised",
+			recorded.NodeCIDRMaskSize, c.NodeCIDRMaskSize).
+			WithProperty(ErrPropertyResolution, "drop the setting to keep the recorded value")
+	}
+	return nil
+}
+
+// ValidateHostPodCIDR checks that the host firewall's pod CIDR, when set, is
+// the cluster's pod subnet: the firewall opens the in-cluster host-service
+// ports to exactly that range, so any other value blocks the pods.
+func (c ClusterConfig) ValidateHostPodCIDR(hostPodCIDR string) error {
+	if hostPodCIDR == "" || c.PodSubnet == "" {
+		return nil
+	}
+	_, host, err := net.ParseCIDR(hostPodCIDR)
+	if err != nil {
+		return errorx.IllegalArgument.Wrap(err, "invalid host podCidr: %s", hostPodCIDR)
+	}
+	_, pod, err := net.ParseCIDR(c.PodSubnet)
+	if err != nil {
+		return errorx.IllegalArgument.Wrap(err, "invalid cluster podSubnet: %s", c.PodSubnet)
+	}
+	if host.String() != pod.String() {
+		return errorx.IllegalArgument.New("host podCidr %s is not the cluster podSubnet %s", hostPodCIDR, c.PodSubnet).
+			WithProperty(ErrPropertyResolution,
+				"set host.podCidr (--pod-cidr) to the cluster pod subnet, or leave it empty to default to it")
+	}
+	return nil
+}
+
+// validate checks the pools and peers, and that no pool overlaps another pool
+// or the service and pod subnets.
+func (lb LoadBalancerConfig) validate(service, pod *net.IPNet) error {
+	type span struct {
+		owner      string
+		start, end net.IP
+	}
+	var spans []span
+	if service != nil {
+		spans = append(spans, span{"serviceSubnet", service.IP.To16(), lastIP(service)})
+	}
+	if pod != nil {
+		spans = append(spans, span{"podSubnet", pod.IP.To16(), lastIP(pod)})
+	}
+
+	names := map[string]bool{}
+	needsPeer := ""
+	for _, p := range lb.Pools {
+		if !objectNamePattern.MatchString(p.Name) {
+			return errorx.IllegalArgument.New("invalid load balancer pool name %q", p.Name).
+				WithProperty(ErrPropertyResolution, "use lowercase letters, digits and hyphens, e.g. public-address-pool")
+		}
+		if names[p.Name] {
+			return errorx.IllegalArgument.New("duplicate load balancer pool %q", p.Name)
+		}
+		names[p.Name] = true
+
+		switch p.Advertisement {
+		case "", AdvertisementL2:
+		case AdvertisementBGP:
+			needsPeer = p.Name
+		default:
+			return errorx.IllegalArgument.New("invalid advertisement %q for load balancer pool %s", p.Advertisement, p.Name).
+				WithProperty(ErrPropertyResolution, "use l2 or bgp")
+		}
+
+		if len(p.Addresses) == 0 {
+			return errorx.IllegalArgument.New("load balancer pool %s has no addresses", p.Name)
+		}
+		for _, a := range p.Addresses {
+			if a == MachineIPAddress {
+				continue
+			}
+			start, end, err := parseAddressRange(a)
+			if err != nil {
+				return errorx.IllegalArgument.Wrap(err, "invalid address %q in load balancer pool %s", a, p.Name)
+			}
+			for _, s := range spans {
+				if bytes.Compare(start, s.end) <= 0 && bytes.Compare(s.start, end) <= 0 {
+					return errorx.IllegalArgument.New("address %s of load balancer pool %s overlaps %s", a, p.Name, s.owner).
+						WithProperty(ErrPropertyResolution, "give each pool addresses no other pool or cluster subnet uses")
+				}
+			}
+			spans = append(spans, span{"pool " + p.Name, start, end})
+		}
+	}
+
+	if needsPeer != "" && len(lb.BGPPeers) == 0 {
+		return errorx.IllegalArgument.New("load balancer pool %s is advertised over bgp but no bgp peer is configured", needsPeer).
+			WithProperty(ErrPropertyResolution, "add the routers to cluster.loadBalancer.bgpPeers")
+	}
+	peers := map[string]bool{}
+	for _, peer := range lb.BGPPeers {
+		if !objectNamePattern.MatchString(peer.Name) || peers[peer.Name] {
+			return errorx.IllegalArgument.New("invalid or duplicate bgp peer name %q", peer.Name)
+		}
+		peers[peer.Name] = true
+		if net.ParseIP(peer.PeerAddress) == nil {
+			return errorx.IllegalArgument.New("invalid address %q of bgp peer %s", peer.PeerAddress, peer.Name)
+		}
+		if peer.PeerASN == 0 || peer.MyASN == 0 {
+			return errorx.IllegalArgument.New("bgp peer %s needs both peerAsn and myAsn", peer.Name)
+		}
+	}
+	return nil
+}
+
+// parseAddressRange parses a pool address, a CIDR or a "first-last" range,
+// into its first and last IP in 16-byte form.
+func parseAddressRange(a string) (net.IP, net.IP, error) {
+	if first, last, ok := strings.Cut(a, "-"); ok {
+		start, end := net.ParseIP(strings.TrimSpace(first)), net.ParseIP(strings.TrimSpace(last))
+		if start == nil || end == nil || (start.To4() == nil) != (end.To4() == nil) {
+			return nil, nil, errorx.IllegalFormat.New("%q is not a range of two addresses of one family", a)
+		}
+		if bytes.Compare(start.To16(), end.To16()) > 0 {
+			return nil, nil, errorx.IllegalFormat.New("range %q ends before it starts", a)
+		}
+		return start.To16(), end.To16(), nil
+	}
+	if err := sanity.ValidateCIDR(a); err != nil {
+		return nil, nil, err
+	}
+	_, n, _ := net.ParseCIDR(a)
+	return n.IP.To16(), lastIP(n), nil
+}
+
+// lastIP returns the last address of n in 16-byte form.
+func lastIP(n *net.IPNet) net.IP {
+	ip := n.IP.To16()
+	mask := n.Mask
+	if len(mask) == net.IPv4len {
+		mask = append(net.CIDRMask(96, 128)[:12], mask...)
+	}
+	last := make(net.IP, net.IPv6len)
+	for i := range ip {
+		last[i] = ip[i] | ^mask[i]
+	}
+	return last
+}
+
+// cidrsOverlap reports whether a and b share an address.
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
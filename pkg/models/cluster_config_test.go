@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterConfig_Validate(t *testing.T) {
+	bgpPool := AddressPool{Name: "public", Addresses: []string{"203.0.113.16/28"}, Advertisement: AdvertisementBGP}
+	peer := BGPPeer{Name: "tor1", PeerAddress: "10.10.0.1", PeerASN: 64512, MyASN: 64513}
+
+	tests := []struct {
+		name    string
+		mutate  func(*ClusterConfig)
+		wantErr string
+	}{
+		{name: "defaults are valid"},
+		{name: "empty is valid", mutate: func(c *ClusterConfig) { *c = ClusterConfig{} }},
+		{
+			name: "site-specific layout",
+			mutate: func(c *ClusterConfig) {
+				c.ServiceSubnet, c.PodSubnet, c.NodeCIDRMaskSize = "172.20.0.0/16", "172.24.0.0/14", 26
+				c.LoadBalancer = LoadBalancerConfig{
+					Pools:    []AddressPool{bgpPool, {Name: "private", Addresses: []string{"192.168.50.10-192.168.50.20"}}},
+					BGPPeers: []BGPPeer{peer},
+				}
+			},
+		},
+		{name: "invalid name", mutate: func(c *ClusterConfig) { c.Name = "main;gcp" }, wantErr: "invalid cluster name"},
+		{name: "ipv6 service subnet", mutate: func(c *ClusterConfig) { c.ServiceSubnet = "fd00::/108" }, wantErr: "invalid cluster serviceSubnet"},
+		{name: "service subnet too large", mutate: func(c *ClusterConfig) { c.ServiceSubnet = "10.0.0.0/8" }, wantErr: "larger than a /12"},
+		{name: "subnets overlap", mutate: func(c *ClusterConfig) { c.PodSubnet = "10.2.0.0/16" }, wantErr: "overlaps podSubnet"},
+		{name: "mask shorter than pod prefix", mutate: func(c *ClusterConfig) { c.NodeCIDRMaskSize = 14 }, wantErr: "does not fit podSubnet"},
+		{name: "mask too far from pod prefix", mutate: func(c *ClusterConfig) { c.NodeCIDRMaskSize = 28; c.PodSubnet = "10.32.0.0/11" }, wantErr: "does not fit podSubnet"},
+		{name: "mask too long", mutate: func(c *ClusterConfig) { c.NodeCIDRMaskSize = 30 }, wantErr: "must be between"},
+		{
+			name:    "pool overlaps pod subnet",
+			mutate:  func(c *ClusterConfig) { c.LoadBalancer.Pools[0].Addresses = []string{"10.5.0.0/24"} },
+			wantErr: "overlaps podSubnet",
+		},
+		{
+			name:    "pools overlap",
+			mutate:  func(c *ClusterConfig) { c.LoadBalancer.Pools[1].Addresses = []string{"192.168.99.10-192.168.99.20"} },
+			wantErr: "overlaps pool private-address-pool",
+		},
+		{name: "duplicate pool", mutate: func(c *ClusterConfig) { c.LoadBalancer.Pools[1].Name = "private-address-pool" }, wantErr: "duplicate"},
+		{name: "pool name", mutate: func(c *ClusterConfig) { c.LoadBalancer.Pools[0].Name = "Private_Pool" }, wantErr: "invalid load balancer pool name"},
+		{name: "reversed range", mutate: func(c *ClusterConfig) { c.LoadBalancer.Pools[0].Addresses = []string{"192.168.1.20-192.168.1.10"} }, wantErr: "ends before it starts"},
+		{name: "advertisement", mutate: func(c *ClusterConfig) { c.LoadBalancer.Pools[0].Advertisement = "ospf" }, wantErr: "invalid advertisement"},
+		{name: "bgp pool without peer", mutate: func(c *ClusterConfig) { c.LoadBalancer.Pools = []AddressPool{bgpPool} }, wantErr: "no bgp peer"},
+		{
+			name: "peer without asn",
+			mutate: func(c *ClusterConfig) {
+				p := peer
+				p.MyASN = 0
+				c.LoadBalancer = LoadBalancerConfig{Pools: []AddressPool{bgpPool}, BGPPeers: []BGPPeer{p}}
+			},
+			wantErr: "needs both peerAsn and myAsn",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := DefaultClusterConfig()
+			if tt.mutate != nil {
+				tt.mutate(&c)
+			}
+			err := c.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestClusterConfig_Merge(t *testing.T) {
+	recorded := DefaultClusterConfig()
+	recorded.PodSubnet = "172.24.0.0/14"
+
+	c := ClusterConfig{Name: "site-a"}.Merge(recorded).Merge(DefaultClusterConfig())
+	assert.Equal(t, "site-a", c.Name)
+	assert.Equal(t, "172.24.0.0/14", c.PodSubnet, "the recorded value wins over the default")
+	assert.Equal(t, DefaultClusterServiceSubnet, c.ServiceSubnet)
+	assert.Equal(t, recorded.LoadBalancer, c.LoadBalancer)
+
+	pools := []AddressPool{{Name: "only", Addresses: []string{"192.168.7.0/24"}}}
+	c = ClusterConfig{LoadBalancer: LoadBalancerConfig{Pools: pools}}.Merge(DefaultClusterConfig())
+	assert.Equal(t, pools, c.LoadBalancer.Pools, "configured pools replace the defaults")
+}
+
+func TestClusterConfig_CheckUnchanged(t *testing.T) {
+	recorded := DefaultClusterConfig()
+	require.NoError(t, ClusterConfig{}.CheckUnchanged(recorded))
+	require.NoError(t, ClusterConfig{PodSubnet: DefaultClusterPodCIDR, LoadBalancer: LoadBalancerConfig{
+		Pools: []AddressPool{{Name: "new", Addresses: []string{"192.168.7.0/24"}}},
+	}}.CheckUnchanged(recorded), "pools may change")
+	require.ErrorContains(t, ClusterConfig{ServiceSubnet: "172.20.0.0/16"}.CheckUnchanged(recorded), "cannot change the cluster serviceSubnet")
+	require.ErrorContains(t, ClusterConfig{NodeCIDRMaskSize: 26}.CheckUnchanged(recorded), "cannot change the cluster nodeCidrMaskSize")
+}
+
+func TestClusterConfig_ValidateHostPodCIDR(t *testing.T) {
+	c := DefaultClusterConfig()
+	require.NoError(t, c.ValidateHostPodCIDR(""))
+	require.NoError(t, c.ValidateHostPodCIDR("10.4.0.0/14"))
+	require.ErrorContains(t, c.ValidateHostPodCIDR("10.244.0.0/16"), "is not the cluster podSubnet")
+}
@@ -55,11 +55,46 @@ type SoloOperatorConfig struct {
 	Enabled bool `yaml:"enabled" json:"enabled"`
 }
 
-// DefaultClusterPodCIDR is the cluster-wide pod subnet. It is the single source
-// of truth shared by the kubeadm init config (networking.podSubnet, rendered via
-// templates.KubeadmInitData.PodSubnet) and the host firewall's default
-// --pod-cidr (the in-cluster host-service ports rule). Keeping both off one
-// constant guarantees the firewall opens exactly the range kubeadm assigns pods.
+// ApplyConflictsForce and ApplyConflictsFail are the KubeConfig.ApplyConflicts
+// policies: take fields another field manager owns back, or stop.
+const (
+	ApplyConflictsForce = "force"
+	ApplyConflictsFail  = "fail"
+)
+
+// KubeConfig represents the `kube` configuration block: how weaver applies its
+// templated manifests (MetalLB pools, block node storage, the Alloy
+// ConfigMap). ApplyConflicts decides what a server-side apply does with fields
+// someone changed outside weaver, e.g. with kubectl edit: "force" (the
+// default) overwrites them, "fail" stops the run naming the fields, so the
+// change can be moved into weaver's configuration first. `kube cluster drift`
+// reports such fields without applying anything.
+type KubeConfig struct {
+	ApplyConflicts string `yaml:"applyConflicts" json:"applyConflicts"` // force (default) or fail
+}
+
+// Validate rejects an unknown conflict policy.
+func (c *KubeConfig) Validate() error {
+	switch c.ApplyConflicts {
+	case "", ApplyConflictsForce, ApplyConflictsFail:
+		return nil
+	}
+	return errorx.IllegalArgument.New("invalid kube applyConflicts: %s", c.ApplyConflicts).
+		WithProperty(ErrPropertyResolution, "set kube.applyConflicts to force or fail")
+}
+
+// ForceConflicts reports whether applies take over fields other field
+// managers own.
+func (c KubeConfig) ForceConflicts() bool {
+	return c.ApplyConflicts != ApplyConflictsFail
+}
+
+// DefaultClusterPodCIDR is the default cluster-wide pod subnet
+// (ClusterConfig.PodSubnet). The kubeadm init config (networking.podSubnet,
+// rendered via templates.KubeadmInitData.PodSubnet) and the host firewall's
+// default --pod-cidr (the in-cluster host-service ports rule) both take the
+// effective pod subnet, so the firewall opens exactly the range kubeadm
+// assigns pods.
 const DefaultClusterPodCIDR = "10.4.0.0/14"
 
 // HostConfig represents the `host` configuration block: node-level host-firewall
@@ -126,6 +161,15 @@ type Config struct {
 	Proxy        ProxyConfig        `yaml:"proxy" json:"proxy"`
 	SoloOperator SoloOperatorConfig `yaml:"soloOperator" json:"soloOperator"`
 	Host         HostConfig         `yaml:"host" json:"host"`
+	Cluster      ClusterConfig      `yaml:"cluster" json:"cluster"`
+	Kube         KubeConfig         `yaml:"kube" json:"kube"`
+}
+
+// Redacted returns a copy of the configuration with its secrets masked.
+func (c Config) Redacted() Config {
+	r := c
+	r.Teleport = c.Teleport.Redacted()
+	return r
 }
 
 // BlockNodeStorage represents the `storage` section under `blockNode`.
@@ -326,6 +370,15 @@ func (c Config) Validate() error {
 	if err := c.Host.Validate(); err != nil {
 		return err
 	}
+	if err := c.Cluster.Validate(); err != nil {
+		return err
+	}
+	if err := c.Cluster.ValidateHostPodCIDR(c.Host.PodCIDR); err != nil {
+		return err
+	}
+	if err := c.Kube.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -366,6 +419,7 @@ type ESOSecretDataEntry struct {
 // Note: Passwords are managed via Vault and External Secrets Operator, not in config files.
 type AlloyConfig struct {
 	MonitorBlockNode       bool                `yaml:"monitorBlockNode" json:"monitorBlockNode"`
+	MonitorTrafficShaper   bool                `yaml:"monitorTrafficShaper" json:"monitorTrafficShaper"`
 	ClusterName            string              `yaml:"clusterName" json:"clusterName"`
 	ClusterSecretStoreName string              `yaml:"clusterSecretStoreName" json:"clusterSecretStoreName"` // Name of the ClusterSecretStore for ESO
 	PrometheusRemotes      []AlloyRemoteConfig `yaml:"prometheusRemotes" json:"prometheusRemotes"`
@@ -515,6 +569,15 @@ type TeleportConfig struct {
 	NodeAgentProxyAddr string `yaml:"nodeAgentProxyAddr" json:"nodeAgentProxyAddr"` // Teleport proxy address (required when NodeAgentToken is set)
 }
 
+// Redacted returns a copy with the NodeAgentToken masked.
+func (c TeleportConfig) Redacted() TeleportConfig {
+	r := c
+	if r.NodeAgentToken != "" {
+		r.NodeAgentToken = "***"
+	}
+	return r
+}
+
 // Validate validates Teleport configuration fields that are set.
 // This performs basic validation without context-specific requirements.
 // Use ValidateClusterAgent() or ValidateNodeAgent() for use-case specific validation.
@@ -103,6 +103,29 @@ type BlockNodeInputs struct {
 	// CLI layer; see internal/workflows/steps.WriteBlockNodeDaemonConfigStep.
 	StatuszBaseURL      string
 	StatuszPollInterval string
+	// BackupTarget is the directory `block node backup` writes a new backup
+	// under, and BackupIncremental bases it on the newest backup already there
+	// (archive tier only). RestoreFrom is the backup directory `block node
+	// restore` reads. All three are empty/false for every other action.
+	BackupTarget      string
+	BackupIncremental bool
+	RestoreFrom       string
+	// StorageGCArchiveTo is the directory `block node storage gc` archives
+	// retired volumes under before deleting them; empty deletes outright.
+	StorageGCArchiveTo string
+	// RollbackRevision is the Helm revision `block node rollback` returns the
+	// release to; zero picks the previous successfully deployed revision.
+	RollbackRevision int
+	// ServicePort is the port the block node service is published on and
+	// LoadBalancerPool the MetalLB pool it is announced from, from
+	// `--service-port` / `--load-balancer-pool`. Zero/empty take the value
+	// persisted for the release, then the default; an additional instance on
+	// the same host is moved to the next port no other instance uses.
+	ServicePort      int
+	LoadBalancerPool string
+	// SkipValuesValidation hands the values to Helm without checking them
+	// against the chart's schema or default keys (--skip-values-validation).
+	SkipValuesValidation bool
 }
 
 // ShapeOverride is one class's operator-supplied HTB bandwidth override, parsed
@@ -119,6 +142,7 @@ type ShapeOverride struct {
 type ClusterInputs struct {
 	Profile            string
 	SkipHardwareChecks bool
+	Network            ClusterConfig // cluster name, subnets and MetalLB pools; zero fields use the recorded or default layout
 }
 
 type MachineInputs struct {
@@ -242,6 +266,36 @@ func (c *BlockNodeInputs) Validate() error {
 		}
 	}
 
+	// Validate backup/restore locations if provided
+	if c.BackupTarget != "" {
+		if _, err := sanity.SanitizePath(c.BackupTarget); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid backup target: %s", c.BackupTarget)
+		}
+	}
+	if c.RestoreFrom != "" {
+		if _, err := sanity.SanitizePath(c.RestoreFrom); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid backup directory: %s", c.RestoreFrom)
+		}
+	}
+	if c.StorageGCArchiveTo != "" {
+		if _, err := sanity.SanitizePath(c.StorageGCArchiveTo); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid archive directory: %s", c.StorageGCArchiveTo)
+		}
+	}
+
+	if c.RollbackRevision < 0 {
+		return errorx.IllegalArgument.New("invalid rollback revision: %d (must be a positive Helm revision, or 0 for the previous one)", c.RollbackRevision)
+	}
+
+	if c.ServicePort < 0 || c.ServicePort > 65535 {
+		return errorx.IllegalArgument.New("invalid service port: %d (must be between 1 and 65535, or 0 for the default)", c.ServicePort)
+	}
+	if c.LoadBalancerPool != "" {
+		if err := sanity.ValidateIdentifier(c.LoadBalancerPool); err != nil {
+			return errorx.IllegalArgument.Wrap(err, "invalid load balancer pool: %s", c.LoadBalancerPool)
+		}
+	}
+
 	// Validate retention thresholds (must be non-negative integers when set)
 	if c.HistoricRetention != "" {
 		n, err := strconv.ParseInt(c.HistoricRetention, 10, 64)
@@ -301,7 +355,7 @@ func (c *ClusterInputs) Validate() error {
 		}
 	}
 
-	return nil
+	return c.Network.Validate()
 }
 
 func (c *MachineInputs) Validate() error {
@@ -338,8 +392,9 @@ func (c TeleportNodeInputs) Redacted() TeleportNodeInputs {
 }
 
 type TeleportClusterInputs struct {
-	Version    string
-	ValuesFile string
+	Version              string
+	ValuesFile           string
+	SkipValuesValidation bool
 }
 
 func (c *TeleportClusterInputs) Validate() error {
@@ -40,6 +40,21 @@ const (
 
 	// ActionReconfigure re-applies configuration to an already-deployed component without changing its version.
 	ActionReconfigure ActionType = "reconfigure"
+
+	// ActionBackup archives the component's persistent data to a backup target without altering it.
+	ActionBackup ActionType = "backup"
+
+	// ActionRestore replaces the component's persistent data with the contents of a backup.
+	ActionRestore ActionType = "restore"
+
+	// ActionStorageGC archives or deletes persistent data the component's installed version no longer uses.
+	ActionStorageGC ActionType = "storage-gc"
+
+	// ActionStorageForecast samples the component's storage usage to project when it runs out of disk.
+	ActionStorageForecast ActionType = "storage-forecast"
+
+	// ActionRollback returns the component to an earlier deployed revision.
+	ActionRollback ActionType = "rollback"
 )
 
 type TargetType string
@@ -82,13 +97,18 @@ const (
 
 // allowedOperations maps each action to the valid target types it can be performed on.
 var allowedOperations = map[ActionType][]TargetType{
-	ActionSetup:       {TargetMachine, TargetSystem, TargetCluster},
-	ActionReset:       {TargetBlockNode, TargetMachine, TargetSystem, TargetCluster, TargetApplication},
-	ActionInstall:     {TargetApplication, TargetBlockNode, TargetConsensusNode, TargetMirrorNode, TargetRelayNode, TargetOperator, TargetTeleportNode, TargetTeleportCluster},
-	ActionUninstall:   {TargetApplication, TargetBlockNode, TargetConsensusNode, TargetMirrorNode, TargetRelayNode, TargetOperator, TargetTeleportNode, TargetTeleportCluster},
-	ActionUpgrade:     {TargetBlockNode, TargetConsensusNode, TargetMirrorNode, TargetRelayNode, TargetOperator},
-	ActionMigrate:     {TargetSystem, TargetCluster, TargetBlockNode, TargetConsensusNode, TargetMirrorNode, TargetRelayNode, TargetOperator},
-	ActionReconfigure: {TargetBlockNode},
+	ActionSetup:           {TargetMachine, TargetSystem, TargetCluster},
+	ActionReset:           {TargetBlockNode, TargetMachine, TargetSystem, TargetCluster, TargetApplication},
+	ActionInstall:         {TargetApplication, TargetBlockNode, TargetConsensusNode, TargetMirrorNode, TargetRelayNode, TargetOperator, TargetTeleportNode, TargetTeleportCluster},
+	ActionUninstall:       {TargetApplication, TargetBlockNode, TargetConsensusNode, TargetMirrorNode, TargetRelayNode, TargetOperator, TargetTeleportNode, TargetTeleportCluster},
+	ActionUpgrade:         {TargetBlockNode, TargetConsensusNode, TargetMirrorNode, TargetRelayNode, TargetOperator},
+	ActionMigrate:         {TargetSystem, TargetCluster, TargetBlockNode, TargetConsensusNode, TargetMirrorNode, TargetRelayNode, TargetOperator},
+	ActionReconfigure:     {TargetBlockNode},
+	ActionBackup:          {TargetBlockNode},
+	ActionRestore:         {TargetBlockNode},
+	ActionStorageGC:       {TargetBlockNode},
+	ActionStorageForecast: {TargetBlockNode},
+	ActionRollback:        {TargetBlockNode},
 }
 
 // Intent defines the desired action to be performed given certain parameters and configuration.
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubeConfig_Validate(t *testing.T) {
+	for _, policy := range []string{"", ApplyConflictsForce, ApplyConflictsFail} {
+		c := KubeConfig{ApplyConflicts: policy}
+		require.NoError(t, c.Validate(), policy)
+	}
+
+	c := KubeConfig{ApplyConflicts: "overwrite"}
+	require.ErrorContains(t, c.Validate(), "invalid kube applyConflicts")
+	require.Error(t, Config{Kube: c}.Validate(), "Config.Validate must surface the kube block")
+}
+
+func TestKubeConfig_ForceConflicts(t *testing.T) {
+	assert.True(t, KubeConfig{}.ForceConflicts(), "forcing is the default")
+	assert.True(t, KubeConfig{ApplyConflicts: ApplyConflictsForce}.ForceConflicts())
+	assert.False(t, KubeConfig{ApplyConflicts: ApplyConflictsFail}.ForceConflicts())
+}
@@ -37,6 +37,34 @@ type WeaverPaths struct {
 	// Path: /opt/solo/weaver/config/infrastructure-versions.yaml
 	InfraVersionsPath string
 
+	// PluginPresetsDir is the optional operator directory of block node plugin
+	// preset files, loaded on top of the presets embedded in the binary.
+	// Path: /opt/solo/weaver/config/plugin-presets
+	PluginPresetsDir string
+
+	// KeysDir holds the keys shipped beside the binary that Helm chart
+	// signatures are verified against: OpenPGP keyrings for provenance files
+	// and cosign public keys (see the catalog's `provenance:` section).
+	// Path: /opt/solo/weaver/bin/keys
+	KeysDir string
+
+	// BundleDir holds the installed air-gap bundle that downloads, Helm chart
+	// pulls and image mirrors resolve from (see `bundle install`).
+	// Path: /opt/solo/weaver/bundle
+	BundleDir string
+
+	// DownloadCacheDir is the content-addressed cache of verified downloads,
+	// keyed by their catalog checksum, that reinstalls and version flips
+	// reuse (see `software cache`).
+	// Path: /opt/solo/weaver/downloads/cache
+	DownloadCacheDir string
+
+	// ManifestsDir holds a copy of every templated manifest weaver last
+	// applied to the cluster, which `kube cluster drift` diffs the live
+	// objects against (see internal/kube).
+	// Path: /opt/solo/weaver/state/manifests
+	ManifestsDir string
+
 	// DaemonServiceSandboxPath is the canonical unit file location inside the
 	// weaver sandbox: $home/sandbox/usr/lib/systemd/system/solo-provisioner-daemon.service
 	// DaemonServiceSymlinkPath is the system-wide symlink that points to it:
@@ -53,6 +81,19 @@ type WeaverPaths struct {
 
 	DaemonConsensusMigrateEventsPath string // $home/daemon/events/consensus/migrate/consensus-migrate-events.jsonl
 
+	// Block node storage forecasting: the CLI records the storage volumes to
+	// sample, and the daemon and `block node storage forecast` append size
+	// samples of them. See internal/blocknode/forecast.
+	DaemonBlockNodeDir                string // $home/daemon/blocknode
+	DaemonBlockNodeStorageVolumesPath string // $home/daemon/blocknode/storage-volumes.json
+	DaemonBlockNodeStorageSamplesPath string // $home/daemon/blocknode/storage-samples.jsonl
+
+	// Block node reachability probing: the CLI records the LoadBalancer
+	// endpoint to probe, and the daemon and `block node probe` append results.
+	// See internal/blocknode/probe.
+	DaemonBlockNodeProbeTargetPath  string // $home/daemon/blocknode/probe-target.json
+	DaemonBlockNodeProbeHistoryPath string // $home/daemon/blocknode/probe-history.jsonl
+
 	AllDirectories []string
 
 	// Sandbox directories for isolated binaries
@@ -81,6 +122,11 @@ func NewWeaverPaths(home string) *WeaverPaths {
 	pp.DaemonSockPath = path.Join(pp.DaemonDir, "daemon.sock")
 	pp.DaemonConfigPath = path.Join(pp.ConfigDir, "daemon.yaml")
 	pp.InfraVersionsPath = path.Join(pp.ConfigDir, "infrastructure-versions.yaml")
+	pp.PluginPresetsDir = path.Join(pp.ConfigDir, "plugin-presets")
+	pp.KeysDir = path.Join(pp.BinDir, "keys")
+	pp.BundleDir = path.Join(home, "bundle")
+	pp.DownloadCacheDir = path.Join(pp.DownloadsDir, "cache")
+	pp.ManifestsDir = path.Join(pp.StateDir, "manifests")
 	pp.DaemonKubeconfigPath = path.Join(pp.ConfigDir, "daemon.kubeconfig")
 	pp.DaemonCNKubeconfigPath = path.Join(pp.ConfigDir, "daemon-cn.kubeconfig")
 	pp.DaemonBNKubeconfigPath = path.Join(pp.ConfigDir, "daemon-bn.kubeconfig")
@@ -90,6 +136,11 @@ func NewWeaverPaths(home string) *WeaverPaths {
 	pp.DaemonConsensusUpgradeEventsDir = path.Join(pp.DaemonConsensusEventsDir, "upgrade")
 	pp.DaemonConsensusMigrateEventsDir = path.Join(pp.DaemonConsensusEventsDir, "migrate")
 	pp.DaemonConsensusMigrateEventsPath = path.Join(pp.DaemonConsensusMigrateEventsDir, "consensus-migrate-events.jsonl")
+	pp.DaemonBlockNodeDir = path.Join(pp.DaemonDir, "blocknode")
+	pp.DaemonBlockNodeStorageVolumesPath = path.Join(pp.DaemonBlockNodeDir, "storage-volumes.json")
+	pp.DaemonBlockNodeStorageSamplesPath = path.Join(pp.DaemonBlockNodeDir, "storage-samples.jsonl")
+	pp.DaemonBlockNodeProbeTargetPath = path.Join(pp.DaemonBlockNodeDir, "probe-target.json")
+	pp.DaemonBlockNodeProbeHistoryPath = path.Join(pp.DaemonBlockNodeDir, "probe-history.jsonl")
 
 	pp.SandboxDir = path.Join(pp.HomeDir, "sandbox")
 	pp.SandboxBinDir = path.Join(pp.SandboxDir, "bin")
@@ -150,6 +201,7 @@ func NewWeaverPaths(home string) *WeaverPaths {
 		pp.DaemonConsensusEventsDir,
 		pp.DaemonConsensusUpgradeEventsDir,
 		pp.DaemonConsensusMigrateEventsDir,
+		pp.DaemonBlockNodeDir,
 	}
 	pp.AllDirectories = append(pp.AllDirectories, pp.SandboxDirectories...)
 
@@ -0,0 +1,398 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/internal/state"
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// AuditKind classifies a file Audit checked.
+type AuditKind string
+
+const (
+	AuditKindBinary     AuditKind = "binary"
+	AuditKindConfig     AuditKind = "config"
+	AuditKindDirectory  AuditKind = "directory"
+	AuditKindUnexpected AuditKind = "unexpected"
+)
+
+// AuditStatus is the outcome of auditing one file.
+type AuditStatus string
+
+const (
+	AuditPass AuditStatus = "pass"
+	AuditFail AuditStatus = "fail"
+	// AuditSkipped marks a file whose ownership and mode passed but whose
+	// content could not be checked, because the catalog has no checksum for it.
+	AuditSkipped AuditStatus = "skipped"
+)
+
+// AuditFile is the audit result of one file.
+type AuditFile struct {
+	Artifact string      `json:"artifact,omitempty"`
+	Version  string      `json:"version,omitempty"`
+	Kind     AuditKind   `json:"kind"`
+	Path     string      `json:"path"`
+	Status   AuditStatus `json:"status"`
+	// Problems says why the file failed or was skipped.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// AuditReport is the result of Audit. Passed is false when any file failed.
+type AuditReport struct {
+	CheckedAt time.Time   `json:"checkedAt"`
+	Passed    bool        `json:"passed"`
+	Files     []AuditFile `json:"files"`
+}
+
+// Failures returns the files that failed the audit.
+func (r *AuditReport) Failures() []AuditFile {
+	var out []AuditFile
+	for _, f := range r.Files {
+		if f.Status == AuditFail {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// readInstalledSoftware reads the recorded version of every installed host
+// artifact from on-disk state. It is a package var so tests can stub it
+// without a state file.
+var readInstalledSoftware = state.ReadInstalledSoftwareFromDisk
+
+// fileOwner is the uid and gid owning a file.
+type fileOwner struct {
+	uid uint32
+	gid uint32
+}
+
+// auditOwner is the owner the security model requires of installed binaries,
+// configs and the sandbox bin dir: only root installs them. It is a package
+// var so tests can audit files they own.
+var auditOwner = fileOwner{uid: 0, gid: 0}
+
+// sandboxRuntimeBinaries are written into SandboxBinDir by cilium's init
+// containers through their /hostbin mount rather than by an installer, so the
+// extra-file scan does not report them.
+var sandboxRuntimeBinaries = []string{"cilium-mount", "cilium-sysctlfix"}
+
+// installedConfig is where an installer places a catalog config file, by its
+// base name, and the path rewrites it patches into the file there.
+type installedConfig struct {
+	path     func() string
+	rewrites func() []pathRewrite
+}
+
+// installedConfigs maps each catalog config file to its installed location.
+var installedConfigs = map[string]installedConfig{
+	kubeletServiceFileName: {
+		path: func() string {
+			return path.Join(models.Paths().SandboxDir, models.SystemdUnitFilesDir, kubeletServiceFileName)
+		},
+		rewrites: kubeletPathRewrites,
+	},
+	kubeadmConfFileName: {
+		path: func() string {
+			return path.Join(models.Paths().SandboxDir, kubeletServiceDirRelPath, kubeadmConfFileName)
+		},
+		rewrites: kubeletPathRewrites,
+	},
+	teleportServiceFileName: {
+		path: func() string {
+			return path.Join(models.Paths().SandboxDir, models.SystemdUnitFilesDir, teleportServiceFileName)
+		},
+		rewrites: teleportServicePathRewrites,
+	},
+}
+
+// Audit re-hashes the binaries and configs of the host artifacts recorded as
+// installed in on-disk state against the catalog checksums of their recorded
+// versions, and checks their ownership and modes against the security model
+// (docs/dev/security-model.md). With no names it audits every recorded
+// artifact and also reports files in SandboxBinDir that none of them
+// installed.
+//
+// Unlike VerifyExecutables, a failing file does not stop the audit: the
+// report lists every file checked and why each one failed. An error is
+// returned only when the audit itself cannot run, e.g. a named artifact is not
+// installed.
+func Audit(names ...string) (*AuditReport, error) {
+	catalog, err := LoadInfrastructureCatalog()
+	if err != nil {
+		return nil, NewConfigLoadError(err)
+	}
+
+	installed, err := readInstalledSoftware()
+	if err != nil {
+		return nil, err
+	}
+
+	return auditHost(catalog, installed, names)
+}
+
+// auditHost is the testable core of Audit: it takes the catalog and the
+// recorded versions explicitly.
+func auditHost(catalog *InfrastructureCatalog, installed map[string]string, names []string) (*AuditReport, error) {
+	scanExtras := len(names) == 0
+	if scanExtras {
+		for name := range installed {
+			if _, err := catalog.GetHostArtifact(name); err == nil {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	report := &AuditReport{CheckedAt: time.Now().UTC()}
+	known := map[string]bool{}
+	for _, name := range names {
+		artifact, err := catalog.GetHostArtifact(name)
+		if err != nil {
+			return nil, err
+		}
+		version, ok := installed[name]
+		if !ok {
+			return nil, errorx.IllegalArgument.New("software '%s' is not installed on this host", name)
+		}
+
+		files, err := auditArtifact(artifact, version)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			known[f.Path] = true
+		}
+		report.Files = append(report.Files, files...)
+	}
+
+	if scanExtras {
+		report.Files = append(report.Files, auditSandboxBinDir(known)...)
+	}
+
+	report.Passed = len(report.Failures()) == 0
+	return report, nil
+}
+
+// auditArtifact audits the installed files of one artifact at version.
+func auditArtifact(artifact *ArtifactMetadata, version string) ([]AuditFile, error) {
+	if artifact.SelfRelease != nil {
+		return []AuditFile{auditSelfRelease(artifact, version)}, nil
+	}
+
+	versionInfo, ok := artifact.Versions[Version(version)]
+	if !ok {
+		// The installed version was delisted since: there is nothing to compare
+		// against, as in VerifyExecutables.
+		return []AuditFile{{
+			Artifact: artifact.Name,
+			Version:  version,
+			Status:   AuditSkipped,
+			Problems: []string{fmt.Sprintf("version %s is not in the software catalog", version)},
+		}}, nil
+	}
+
+	platform := artifact.getPlatform()
+	data := TemplateData{VERSION: version, OS: platform.os, ARCH: platform.arch}
+
+	var files []AuditFile
+	for _, binary := range versionInfo.GetBinaries() {
+		binaryName, err := executeTemplate(binary.Name, data)
+		if err != nil {
+			return nil, NewTemplateError(err, artifact.Name)
+		}
+
+		f := AuditFile{
+			Artifact: artifact.Name,
+			Version:  version,
+			Kind:     AuditKindBinary,
+			Path:     installedBinaryPath(artifact.Name, binaryName),
+		}
+		var sum *Checksum
+		if c, ok := binary.PlatformChecksum[platform.os][platform.arch]; ok {
+			sum = &c
+		}
+		files = append(files, auditFile(f, sum, nil))
+	}
+
+	for _, cfg := range versionInfo.GetConfigs() {
+		configName, err := executeTemplate(cfg.Name, data)
+		if err != nil {
+			return nil, NewTemplateError(err, artifact.Name)
+		}
+
+		loc, ok := installedConfigs[path.Base(configName)]
+		if !ok {
+			files = append(files, AuditFile{
+				Artifact: artifact.Name,
+				Version:  version,
+				Kind:     AuditKindConfig,
+				Path:     configName,
+				Status:   AuditSkipped,
+				Problems: []string{"install location unknown"},
+			})
+			continue
+		}
+
+		f := AuditFile{
+			Artifact: artifact.Name,
+			Version:  version,
+			Kind:     AuditKindConfig,
+			Path:     loc.path(),
+		}
+		files = append(files, auditFile(f, &Checksum{Algorithm: cfg.Algorithm, Value: cfg.Value}, loc.rewrites()))
+	}
+
+	return files, nil
+}
+
+// auditSelfRelease audits the installed binary of a self-released artifact.
+// Its content is checked only for the version this binary was released
+// alongside, the one version whose digest it knows.
+func auditSelfRelease(artifact *ArtifactMetadata, version string) AuditFile {
+	f := AuditFile{
+		Artifact: artifact.Name,
+		Version:  version,
+		Kind:     AuditKindBinary,
+		Path:     path.Join(models.Paths().BinDir, path.Base(artifact.SelfRelease.BinaryName)),
+	}
+	if digest, ok := pinnedDigestFor(version); ok {
+		return auditFile(f, &Checksum{Algorithm: daemonChecksumAlgorithm, Value: digest}, nil)
+	}
+	return auditFile(f, nil, nil)
+}
+
+// auditFile checks f.Path against sum, after reversing rewrites, and its
+// ownership and mode against what the security model requires of f.Kind. A
+// nil sum leaves the content of a file unchecked.
+func auditFile(f AuditFile, sum *Checksum, rewrites []pathRewrite) AuditFile {
+	info, err := os.Lstat(f.Path)
+	if err != nil {
+		f.Status = AuditFail
+		if os.IsNotExist(err) {
+			f.Problems = []string{"missing"}
+		} else {
+			f.Problems = []string{err.Error()}
+		}
+		return f
+	}
+
+	f.Problems = append(f.Problems, modeProblems(info, f.Kind)...)
+
+	unchecked := sum == nil && f.Kind != AuditKindDirectory
+	if !unchecked && info.Mode().IsRegular() {
+		if problem := contentProblem(f.Path, *sum, rewrites); problem != "" {
+			f.Problems = append(f.Problems, problem)
+		}
+	}
+
+	switch {
+	case len(f.Problems) > 0:
+		f.Status = AuditFail
+	case unchecked:
+		f.Status = AuditSkipped
+		f.Problems = []string{"no catalog checksum for this platform or version"}
+	default:
+		f.Status = AuditPass
+	}
+	return f
+}
+
+// contentProblem hashes the file at p, with rewrites reversed so a patched
+// config hashes as the upstream file it was installed from, and describes how
+// it differs from sum. It returns "" when the file matches.
+func contentProblem(p string, sum Checksum, rewrites []pathRewrite) string {
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return err.Error()
+	}
+	for i := len(rewrites) - 1; i >= 0; i-- {
+		content = []byte(strings.ReplaceAll(string(content), rewrites[i].to, rewrites[i].from))
+	}
+
+	h, ok := newHash(sum.Algorithm)
+	if !ok {
+		return fmt.Sprintf("unsupported checksum algorithm %q", sum.Algorithm)
+	}
+	h.Write(content)
+	if actual := fmt.Sprintf("%x", h.Sum(nil)); actual != sum.Value {
+		return fmt.Sprintf("%s mismatch: expected %s, got %s", sum.Algorithm, sum.Value, actual)
+	}
+	return ""
+}
+
+// modeProblems checks a file's type, ownership and permissions: binaries and
+// directories are root:root 0755, configs are root-owned and writable by root
+// only, and nothing is setuid or setgid.
+func modeProblems(info fs.FileInfo, kind AuditKind) []string {
+	var problems []string
+	mode := info.Mode()
+
+	switch {
+	case kind == AuditKindDirectory && !mode.IsDir():
+		problems = append(problems, "not a directory")
+	case kind != AuditKindDirectory && !mode.IsRegular():
+		problems = append(problems, fmt.Sprintf("not a regular file (%s)", mode.Type()))
+	}
+
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && (st.Uid != auditOwner.uid || st.Gid != auditOwner.gid) {
+		problems = append(problems, fmt.Sprintf("owned by %d:%d, want %d:%d", st.Uid, st.Gid, auditOwner.uid, auditOwner.gid))
+	}
+
+	if mode&(fs.ModeSetuid|fs.ModeSetgid) != 0 {
+		problems = append(problems, "setuid or setgid bit set")
+	}
+	switch kind {
+	case AuditKindConfig:
+		if mode.Perm()&0o022 != 0 {
+			problems = append(problems, fmt.Sprintf("mode %04o is writable by group or others", mode.Perm()))
+		}
+	default:
+		if mode.Perm() != models.DefaultDirOrExecPerm {
+			problems = append(problems, fmt.Sprintf("mode %04o, want %04o", mode.Perm(), models.DefaultDirOrExecPerm))
+		}
+	}
+	return problems
+}
+
+// auditSandboxBinDir checks SandboxBinDir itself and reports every file in it
+// that is not in known and not written there at runtime. A host without a
+// sandbox has nothing to report.
+func auditSandboxBinDir(known map[string]bool) []AuditFile {
+	dir := models.Paths().SandboxBinDir
+	if _, err := os.Lstat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	files := []AuditFile{auditFile(AuditFile{Kind: AuditKindDirectory, Path: dir}, nil, nil)}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return files
+	}
+	for _, e := range entries {
+		p := path.Join(dir, e.Name())
+		if known[p] || slices.Contains(sandboxRuntimeBinaries, e.Name()) {
+			continue
+		}
+		files = append(files, AuditFile{
+			Kind:     AuditKindUnexpected,
+			Path:     p,
+			Status:   AuditFail,
+			Problems: []string{"not installed by any recorded software"},
+		})
+	}
+	return files
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// auditFixture installs a synthetic kubelet into a temporary sandbox owned by
+// the test user and returns its catalog.
+func auditFixture(t *testing.T) *InfrastructureCatalog {
+	t.Helper()
+	t.Cleanup(models.SetPaths(t.TempDir()))
+	prevOwner := auditOwner
+	auditOwner = fileOwner{uid: uint32(os.Getuid()), gid: uint32(os.Getgid())}
+	t.Cleanup(func() { auditOwner = prevOwner })
+
+	binary := []byte("kubelet binary")
+	upstream := []byte("ExecStart=/usr/bin/kubelet\n")
+
+	binDir := models.Paths().SandboxBinDir
+	require.NoError(t, os.MkdirAll(binDir, 0o755))
+	require.NoError(t, os.Chmod(binDir, 0o755))
+	require.NoError(t, os.WriteFile(path.Join(binDir, "kubelet"), binary, 0o755))
+
+	unit := installedConfigs[kubeletServiceFileName].path()
+	require.NoError(t, os.MkdirAll(filepath.Dir(unit), 0o755))
+	patched := strings.ReplaceAll(string(upstream), "/usr/bin/kubelet", path.Join(binDir, "kubelet"))
+	require.NoError(t, os.WriteFile(unit, []byte(patched), 0o644))
+
+	artifact := (&ArtifactMetadata{
+		Name:    "kubelet",
+		Default: "1.0.0",
+		Versions: map[Version]VersionDetails{
+			"1.0.0": {
+				Binaries: []BinaryDetail{{Name: "kubelet", PlatformChecksum: linuxAmd64Checksum("sha256", sha256Hex(binary))}},
+				Configs:  []ConfigDetail{{Name: kubeletServiceFileName, Algorithm: "sha256", Value: sha256Hex(upstream)}},
+			},
+		},
+	}).withPlatform("linux", "amd64")
+	return &InfrastructureCatalog{Host: []ArtifactMetadata{*artifact}}
+}
+
+func TestAuditHost_CleanInstallPasses(t *testing.T) {
+	catalog := auditFixture(t)
+	require.NoError(t, os.WriteFile(path.Join(models.Paths().SandboxBinDir, "cilium-mount"), []byte("x"), 0o755))
+
+	report, err := auditHost(catalog, map[string]string{"kubelet": "1.0.0", "unknown": "1.0.0"}, nil)
+	require.NoError(t, err)
+	assert.True(t, report.Passed, "%+v", report.Failures())
+	require.Len(t, report.Files, 3, "binary, patched config and the sandbox bin dir")
+	assert.Equal(t, AuditKindConfig, report.Files[1].Kind)
+	assert.Equal(t, AuditPass, report.Files[1].Status, "the config hashes as upstream once its patches are reversed")
+	assert.Equal(t, AuditKindDirectory, report.Files[2].Kind)
+}
+
+func TestAuditHost_ReportsTampering(t *testing.T) {
+	catalog := auditFixture(t)
+	binDir := models.Paths().SandboxBinDir
+	require.NoError(t, os.WriteFile(path.Join(binDir, "kubelet"), []byte("tampered"), 0o755))
+	require.NoError(t, os.Chmod(installedConfigs[kubeletServiceFileName].path(), 0o666))
+	require.NoError(t, os.WriteFile(path.Join(binDir, "dropped"), []byte("x"), 0o755))
+
+	report, err := auditHost(catalog, map[string]string{"kubelet": "1.0.0"}, nil)
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+
+	failures := report.Failures()
+	require.Len(t, failures, 3)
+	assert.Contains(t, failures[0].Problems[0], "sha256 mismatch")
+	assert.Contains(t, failures[1].Problems[0], "writable by group or others")
+	assert.Equal(t, AuditKindUnexpected, failures[2].Kind)
+	assert.Equal(t, path.Join(binDir, "dropped"), failures[2].Path)
+}
+
+func TestAuditHost_NamedArtifactSkipsExtraFileScan(t *testing.T) {
+	catalog := auditFixture(t)
+	require.NoError(t, os.WriteFile(path.Join(models.Paths().SandboxBinDir, "dropped"), []byte("x"), 0o755))
+
+	report, err := auditHost(catalog, map[string]string{"kubelet": "1.0.0"}, []string{"kubelet"})
+	require.NoError(t, err)
+	assert.True(t, report.Passed)
+	assert.Len(t, report.Files, 2)
+
+	_, err = auditHost(catalog, map[string]string{}, []string{"kubelet"})
+	require.Error(t, err, "an artifact that is not installed cannot be audited")
+}
+
+func TestAuditHost_WrongModeAndMissingFileFail(t *testing.T) {
+	catalog := auditFixture(t)
+	bin := path.Join(models.Paths().SandboxBinDir, "kubelet")
+	require.NoError(t, os.Chmod(bin, 0o775))
+	require.NoError(t, os.Remove(installedConfigs[kubeletServiceFileName].path()))
+
+	report, err := auditHost(catalog, map[string]string{"kubelet": "1.0.0"}, []string{"kubelet"})
+	require.NoError(t, err)
+	failures := report.Failures()
+	require.Len(t, failures, 2)
+	assert.Equal(t, []string{"mode 0775, want 0755"}, failures[0].Problems)
+	assert.Equal(t, []string{"missing"}, failures[1].Problems)
+}
+
+func TestAuditHost_DelistedVersionIsSkipped(t *testing.T) {
+	catalog := auditFixture(t)
+
+	report, err := auditHost(catalog, map[string]string{"kubelet": "0.9.0"}, []string{"kubelet"})
+	require.NoError(t, err)
+	assert.True(t, report.Passed)
+	require.Len(t, report.Files, 1)
+	assert.Equal(t, AuditSkipped, report.Files[0].Status)
+}
+
+func TestAuditHost_SelfReleaseWithoutPinnedDigestIsSkipped(t *testing.T) {
+	catalog := auditFixture(t)
+	catalog.Host = append(catalog.Host, ArtifactMetadata{
+		Name:        DaemonBinaryName,
+		SelfRelease: &SelfReleaseSpec{BinaryName: DaemonBinaryName},
+	})
+	require.NoError(t, os.MkdirAll(models.Paths().BinDir, 0o755))
+	require.NoError(t, os.WriteFile(path.Join(models.Paths().BinDir, DaemonBinaryName), []byte("daemon"), 0o755))
+
+	report, err := auditHost(catalog, map[string]string{DaemonBinaryName: "9.9.9"}, []string{DaemonBinaryName})
+	require.NoError(t, err)
+	require.Len(t, report.Files, 1)
+	assert.Equal(t, AuditSkipped, report.Files[0].Status)
+	assert.True(t, report.Passed)
+}
@@ -35,6 +35,15 @@ func WithVersion(version string) InstallerOption {
 	}
 }
 
+// WithClusterConfig sets the cluster network layout the kubeadm installer
+// renders kubeadm-init.yaml with. Zero fields fall back to
+// models.DefaultClusterConfig; other installers ignore it.
+func WithClusterConfig(cfg models.ClusterConfig) InstallerOption {
+	return func(bi *baseInstaller) {
+		bi.clusterConfig = cfg
+	}
+}
+
 // baseInstaller provides common functionality for all software installers
 // as well as helper functions for common operations.
 type baseInstaller struct {
@@ -45,6 +54,7 @@ type baseInstaller struct {
 	fileManager          fsx.Manager
 	machineRuntime       MachineRuntime // optional, if it is not passed, it would perform disk check directly
 	softwareState        *state.SoftwareState
+	clusterConfig        models.ClusterConfig // kubeadm only, see WithClusterConfig
 
 	// These function fields allow for installer-specific overrides of the verification logic while still
 	// providing a default implementation in the baseInstaller. For example, kubeadm and kubelet need to verify
@@ -81,7 +91,7 @@ func newBaseInstaller(softwareName string, opts ...InstallerOption) (*baseInstal
 
 	bi := &baseInstaller{
 		name:        softwareName,
-		downloader:  NewDownloader(),
+		downloader:  NewDownloader(WithCache(DefaultDownloadCache())),
 		software:    item,
 		fileManager: fsxManager,
 	}
@@ -756,6 +766,13 @@ func (b *baseInstaller) IsConfigured() (bool, error) {
 	return b.softwareState.Configured, nil
 }
 
+// pathRewrite is one path an installer patches into a catalog config file in
+// place of the system path the upstream file refers to.
+type pathRewrite struct {
+	from string
+	to   string
+}
+
 // replaceAllInFile replaces all occurrences of old with new in the given file
 // similar to the unix command `sed -i 's/old/new/g' file`
 func (b *baseInstaller) replaceAllInFile(sourceFile string, old string, new string) error {
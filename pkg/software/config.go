@@ -6,14 +6,17 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"sort"
+	"strings"
 	"sync"
 	"text/template"
 
 	"github.com/joomcode/errorx"
 	"gopkg.in/yaml.v3"
 
+	"github.com/hashgraph/solo-weaver/pkg/helm"
 	"github.com/hashgraph/solo-weaver/pkg/semver"
 )
 
@@ -87,6 +90,37 @@ func (si *ArtifactMetadata) getPlatform() platformProvider {
 type InfrastructureCatalog struct {
 	Host    []ArtifactMetadata `yaml:"host"`
 	Cluster []ChartMetadata    `yaml:"cluster"`
+	// Provenance holds the signature verification policy of Helm charts, by
+	// chart reference. It covers `cluster:` charts and charts installed from
+	// outside the catalog, such as the block node chart. A chart without an
+	// entry is not signature-verified.
+	Provenance []ChartProvenance `yaml:"provenance,omitempty"`
+}
+
+// ChartProvenance is the signature verification policy of one Helm chart.
+// Classic charts are verified by their Helm provenance (.prov) file against
+// the OpenPGP keyring file Keyring; OCI charts by their cosign signature
+// against the PEM public key file CosignKey. Both are bare file names,
+// resolved in the keys directory shipped beside the binary
+// (WeaverPaths.KeysDir).
+type ChartProvenance struct {
+	Chart     string                `yaml:"chart"`
+	Policy    helm.ProvenancePolicy `yaml:"policy"`
+	Keyring   string                `yaml:"keyring,omitempty"`
+	CosignKey string                `yaml:"cosignKey,omitempty"`
+}
+
+// HelmProvenance returns the verification settings for helm.Manager, with
+// the key file names resolved in keysDir.
+func (p ChartProvenance) HelmProvenance(keysDir string) helm.Provenance {
+	out := helm.Provenance{Policy: p.Policy}
+	if p.Keyring != "" {
+		out.Keyring = filepath.Join(keysDir, p.Keyring)
+	}
+	if p.CosignKey != "" {
+		out.CosignKey = filepath.Join(keysDir, p.CosignKey)
+	}
+	return out
 }
 
 // ChartMetadata describes a Helm chart entry under `cluster:`. A chart has
@@ -396,6 +430,17 @@ func MustGetClusterComponent(name string) *ChartMetadata {
 	return chart
 }
 
+// ProvenanceFor returns the signature verification policy of the chart
+// reference chartRef; a chart the catalog names no policy for is off.
+func (c *InfrastructureCatalog) ProvenanceFor(chartRef string) ChartProvenance {
+	for _, p := range c.Provenance {
+		if p.Chart == chartRef {
+			return p
+		}
+	}
+	return ChartProvenance{Chart: chartRef, Policy: helm.ProvenanceOff}
+}
+
 // HostNames returns the names of all host artifacts in the catalog.
 func (c *InfrastructureCatalog) HostNames() []string {
 	names := make([]string, 0, len(c.Host))
@@ -447,6 +492,46 @@ func (c *InfrastructureCatalog) validate() error {
 			return errorx.IllegalFormat.Wrap(err, "cluster[%s]", chart.Name)
 		}
 	}
+	seen := map[string]bool{}
+	for i := range c.Provenance {
+		p := &c.Provenance[i]
+		if err := p.validate(); err != nil {
+			return errorx.IllegalFormat.Wrap(err, "provenance[%s]", p.Chart)
+		}
+		if seen[p.Chart] {
+			return errorx.IllegalFormat.New("provenance[%s]: duplicate entry", p.Chart)
+		}
+		seen[p.Chart] = true
+	}
+	return nil
+}
+
+// validate enforces ChartProvenance invariants: a known policy, and for a
+// policy other than off the key the chart's distribution mode is verified
+// with, named as a bare file name.
+func (p *ChartProvenance) validate() error {
+	if p.Chart == "" {
+		return errorx.IllegalFormat.New("missing chart reference")
+	}
+	switch p.Policy {
+	case helm.ProvenanceOff:
+		return nil
+	case helm.ProvenanceOptional, helm.ProvenanceRequired:
+	default:
+		return errorx.IllegalFormat.New("unknown policy %q (must be %q, %q or %q)",
+			p.Policy, helm.ProvenanceRequired, helm.ProvenanceOptional, helm.ProvenanceOff)
+	}
+
+	key, field := p.Keyring, "keyring"
+	if strings.HasPrefix(p.Chart, "oci://") {
+		key, field = p.CosignKey, "cosignKey"
+	}
+	if key == "" {
+		return errorx.IllegalFormat.New("policy %q requires %s", p.Policy, field)
+	}
+	if key != filepath.Base(key) {
+		return errorx.IllegalFormat.New("%s %q must be a file name in the keys directory, not a path", field, key)
+	}
 	return nil
 }
 
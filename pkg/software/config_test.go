@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/pkg/helm"
 )
 
 func Test_Config_GetDefaultVersion(t *testing.T) {
@@ -1466,6 +1468,47 @@ func Test_Config_InfrastructureCatalog_validate(t *testing.T) {
 			},
 			expectedErr: "cluster[alloy]",
 		},
+		{
+			name: "provenance entries valid",
+			catalog: InfrastructureCatalog{
+				Provenance: []ChartProvenance{
+					{Chart: "grafana/alloy", Policy: helm.ProvenanceRequired, Keyring: "grafana.gpg"},
+					{Chart: "oci://ghcr.io/hiero-ledger/hiero-block-node/block-node-server", Policy: helm.ProvenanceOptional, CosignKey: "hiero.pub"},
+					{Chart: "metallb/metallb", Policy: helm.ProvenanceOff},
+				},
+			},
+		},
+		{
+			name: "provenance unknown policy",
+			catalog: InfrastructureCatalog{
+				Provenance: []ChartProvenance{{Chart: "grafana/alloy", Policy: "strict", Keyring: "grafana.gpg"}},
+			},
+			expectedErr: `unknown policy "strict"`,
+		},
+		{
+			name: "provenance oci chart without cosign key",
+			catalog: InfrastructureCatalog{
+				Provenance: []ChartProvenance{{Chart: "oci://ghcr.io/hashgraph/charts/solo-operator", Policy: helm.ProvenanceRequired, Keyring: "solo.gpg"}},
+			},
+			expectedErr: "requires cosignKey",
+		},
+		{
+			name: "provenance key is a path",
+			catalog: InfrastructureCatalog{
+				Provenance: []ChartProvenance{{Chart: "grafana/alloy", Policy: helm.ProvenanceOptional, Keyring: "/etc/keys/grafana.gpg"}},
+			},
+			expectedErr: "must be a file name",
+		},
+		{
+			name: "provenance duplicate chart",
+			catalog: InfrastructureCatalog{
+				Provenance: []ChartProvenance{
+					{Chart: "metallb/metallb", Policy: helm.ProvenanceOff},
+					{Chart: "metallb/metallb", Policy: helm.ProvenanceOff},
+				},
+			},
+			expectedErr: "provenance[metallb/metallb]: duplicate entry",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1481,6 +1524,20 @@ func Test_Config_InfrastructureCatalog_validate(t *testing.T) {
 	}
 }
 
+func Test_Config_InfrastructureCatalog_ProvenanceFor(t *testing.T) {
+	catalog := &InfrastructureCatalog{
+		Provenance: []ChartProvenance{{Chart: "grafana/alloy", Policy: helm.ProvenanceRequired, Keyring: "grafana.gpg"}},
+	}
+
+	assert.Equal(t, ChartProvenance{Chart: "grafana/alloy", Policy: helm.ProvenanceRequired, Keyring: "grafana.gpg"},
+		catalog.ProvenanceFor("grafana/alloy"))
+	assert.Equal(t, ChartProvenance{Chart: "metallb/metallb", Policy: helm.ProvenanceOff},
+		catalog.ProvenanceFor("metallb/metallb"), "a chart without an entry is off")
+
+	assert.Equal(t, helm.Provenance{Policy: helm.ProvenanceRequired, Keyring: "/opt/solo/weaver/bin/keys/grafana.gpg"},
+		catalog.ProvenanceFor("grafana/alloy").HelmProvenance("/opt/solo/weaver/bin/keys"))
+}
+
 func Test_Config_LoadInfrastructureCatalog(t *testing.T) {
 	catalog, err := LoadInfrastructureCatalog()
 	require.NoError(t, err, "embedded infrastructure-catalog.yaml must load")
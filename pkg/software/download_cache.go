@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joomcode/errorx"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// checksumPattern is what a catalog checksum looks like; anything else is
+// rejected before it becomes a path in the cache.
+var checksumPattern = regexp.MustCompile(`^[0-9a-f]+$`)
+
+// CacheKey identifies a cached blob by the catalog checksum it verifies
+// against.
+type CacheKey struct {
+	Algorithm string
+	Checksum  string
+}
+
+// CacheEntry is one blob in the download cache.
+type CacheEntry struct {
+	Algorithm string    `json:"algorithm"`
+	Checksum  string    `json:"checksum"`
+	Size      int64     `json:"size"`
+	LastUsed  time.Time `json:"lastUsed"`
+	Path      string    `json:"path"`
+}
+
+// Key returns the key the entry is cached under.
+func (e CacheEntry) Key() CacheKey {
+	return CacheKey{Algorithm: e.Algorithm, Checksum: e.Checksum}
+}
+
+// PruneOptions selects the blobs DownloadCache.Prune removes.
+type PruneOptions struct {
+	// Referenced are the checksums to keep; every other blob is removed.
+	Referenced map[CacheKey]bool
+	// OlderThan, when positive, also removes referenced blobs not used for
+	// that long.
+	OlderThan time.Duration
+	// All removes every blob.
+	All bool
+}
+
+// DownloadCache is a content-addressed store of verified downloads under
+// <dir>/<algorithm>/<checksum>. Because a blob is keyed by the catalog
+// checksum it matched, reinstalls and version flips reuse it whatever URL or
+// file name it was downloaded as.
+type DownloadCache struct {
+	dir string
+}
+
+// NewDownloadCache returns the download cache rooted at dir.
+func NewDownloadCache(dir string) *DownloadCache {
+	return &DownloadCache{dir: dir}
+}
+
+// DefaultDownloadCache returns the download cache at
+// WeaverPaths.DownloadCacheDir.
+func DefaultDownloadCache() *DownloadCache {
+	return NewDownloadCache(models.Paths().DownloadCacheDir)
+}
+
+// Dir returns the directory the cache is rooted at.
+func (c *DownloadCache) Dir() string {
+	return c.dir
+}
+
+// blobPath returns where the blob for algorithm/checksum is stored.
+func (c *DownloadCache) blobPath(algorithm, checksum string) (string, error) {
+	checksum = strings.ToLower(checksum)
+	if !IsSupportedAlgorithm(algorithm) || !checksumPattern.MatchString(checksum) {
+		return "", errorx.IllegalArgument.New("invalid cache key %s:%s", algorithm, checksum)
+	}
+	return filepath.Join(c.dir, algorithm, checksum), nil
+}
+
+// Restore places the cached blob for algorithm/checksum at dest and reports
+// whether there was one. The blob is verified first; a blob that no longer
+// matches its checksum is evicted and reported as missing.
+func (c *DownloadCache) Restore(algorithm, checksum, dest string) (bool, error) {
+	blob, err := c.blobPath(algorithm, checksum)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(blob); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, errorx.ExternalError.Wrap(err, "failed to stat cached blob %s", blob)
+	}
+	if err := VerifyChecksum(blob, checksum, algorithm); err != nil {
+		_ = os.Remove(blob)
+		return false, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), models.DefaultDirOrExecPerm); err != nil {
+		return false, errorx.ExternalError.Wrap(err, "failed to create %s", filepath.Dir(dest))
+	}
+	if err := linkOrCopy(blob, dest); err != nil {
+		return false, errorx.ExternalError.Wrap(err, "failed to restore cached blob %s to %s", blob, dest)
+	}
+
+	// The modification time records the last use, which Prune's OlderThan
+	// goes by.
+	now := time.Now()
+	_ = os.Chtimes(blob, now, now)
+	return true, nil
+}
+
+// Store adds the verified file at path to the cache under algorithm/checksum.
+func (c *DownloadCache) Store(path, algorithm, checksum string) error {
+	blob, err := c.blobPath(algorithm, checksum)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(blob), models.DefaultDirOrExecPerm); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to create %s", filepath.Dir(blob))
+	}
+	if err := linkOrCopy(path, blob); err != nil {
+		return errorx.ExternalError.Wrap(err, "failed to add %s to the download cache", path)
+	}
+	return nil
+}
+
+// List returns every blob in the cache, most recently used first.
+func (c *DownloadCache) List() ([]CacheEntry, error) {
+	algorithms, err := os.ReadDir(c.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, errorx.ExternalError.Wrap(err, "failed to read the download cache %s", c.dir)
+	}
+
+	var entries []CacheEntry
+	for _, algorithm := range algorithms {
+		if !algorithm.IsDir() || !IsSupportedAlgorithm(algorithm.Name()) {
+			continue
+		}
+		dir := filepath.Join(c.dir, algorithm.Name())
+		blobs, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, errorx.ExternalError.Wrap(err, "failed to read the download cache %s", dir)
+		}
+		for _, blob := range blobs {
+			// Skips the temporary files of an interrupted Store or Restore.
+			if !blob.Type().IsRegular() || !checksumPattern.MatchString(blob.Name()) {
+				continue
+			}
+			info, err := blob.Info()
+			if err != nil {
+				continue
+			}
+			entries = append(entries, CacheEntry{
+				Algorithm: algorithm.Name(),
+				Checksum:  blob.Name(),
+				Size:      info.Size(),
+				LastUsed:  info.ModTime(),
+				Path:      filepath.Join(dir, blob.Name()),
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+// Prune removes the blobs opts selects and returns them.
+func (c *DownloadCache) Prune(opts PruneOptions) ([]CacheEntry, error) {
+	entries, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-opts.OlderThan)
+	var removed []CacheEntry
+	for _, e := range entries {
+		stale := opts.OlderThan > 0 && e.LastUsed.Before(cutoff)
+		if !opts.All && opts.Referenced[e.Key()] && !stale {
+			continue
+		}
+		if err := os.Remove(e.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, errorx.ExternalError.Wrap(err, "failed to remove cached blob %s", e.Path)
+		}
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
+
+// linkOrCopy atomically places src at dst, as a hard link where the file
+// system allows and as a copy otherwise.
+func linkOrCopy(src, dst string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() { _ = os.Remove(tmpName) }()
+
+	_ = tmp.Close()
+	_ = os.Remove(tmpName)
+	if err := os.Link(src, tmpName); err != nil {
+		if err := copyContents(src, tmpName); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmpName, dst)
+}
+
+// copyContents copies the file at src to a new file at dst.
+func copyContents(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, models.DefaultFilePerm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ReferencedChecksums returns the checksum of every file any version of any
+// host artifact downloads, on any platform: the blobs the download cache keeps
+// by default.
+func (c *InfrastructureCatalog) ReferencedChecksums() map[CacheKey]bool {
+	keys := map[CacheKey]bool{}
+	add := func(algorithm, checksum string) {
+		keys[CacheKey{Algorithm: algorithm, Checksum: strings.ToLower(checksum)}] = true
+	}
+	addPlatforms := func(checksums PlatformChecksum) {
+		for _, arches := range checksums {
+			for _, sum := range arches {
+				add(sum.Algorithm, sum.Value)
+			}
+		}
+	}
+
+	for _, item := range c.Host {
+		if item.SelfRelease != nil {
+			if digest, ok := pinnedDigestFor(ownVersion()); ok {
+				add(daemonChecksumAlgorithm, digest)
+			}
+			continue
+		}
+		for _, details := range item.Versions {
+			for _, archive := range details.GetArchives() {
+				addPlatforms(archive.PlatformChecksum)
+			}
+			for _, binary := range details.BinariesByURL() {
+				addPlatforms(binary.PlatformChecksum)
+			}
+			for _, config := range details.ConfigsByURL() {
+				add(config.Algorithm, config.Value)
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadCache_StoreAndRestore(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir())
+	content := []byte("cri-o release archive")
+	sum := sha256Hex(content)
+
+	src := filepath.Join(t.TempDir(), "cri-o.tar.gz")
+	require.NoError(t, os.WriteFile(src, content, 0o644))
+	require.NoError(t, cache.Store(src, "sha256", sum))
+
+	dest := filepath.Join(t.TempDir(), "renamed", "crio-v1.tar.gz")
+	restored, err := cache.Restore("sha256", sum, dest)
+	require.NoError(t, err)
+	assert.True(t, restored)
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+
+	restored, err = cache.Restore("sha256", sha256Hex([]byte("other")), dest)
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
+
+func TestDownloadCache_RestoreEvictsCorruptBlob(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewDownloadCache(dir)
+	sum := sha256Hex([]byte("expected"))
+	blob := filepath.Join(dir, "sha256", sum)
+	require.NoError(t, os.MkdirAll(filepath.Dir(blob), 0o755))
+	require.NoError(t, os.WriteFile(blob, []byte("tampered"), 0o644))
+
+	dest := filepath.Join(t.TempDir(), "artifact")
+	restored, err := cache.Restore("sha256", sum, dest)
+	require.Error(t, err)
+	assert.False(t, restored)
+	assert.NoFileExists(t, blob)
+	assert.NoFileExists(t, dest)
+}
+
+func TestDownloadCache_RejectsInvalidKey(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir())
+	src := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(src, []byte("x"), 0o644))
+
+	require.Error(t, cache.Store(src, "sha256", "../../escape"))
+	require.Error(t, cache.Store(src, "crc32", "abcd"))
+}
+
+func TestDownloadCache_ListAndPrune(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir())
+	entries, err := cache.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a cache that was never written is empty")
+
+	store := func(content string, lastUsed time.Time) CacheKey {
+		src := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(src, []byte(content), 0o644))
+		key := CacheKey{Algorithm: "sha256", Checksum: sha256Hex([]byte(content))}
+		require.NoError(t, cache.Store(src, key.Algorithm, key.Checksum))
+		require.NoError(t, os.Chtimes(filepath.Join(cache.Dir(), key.Algorithm, key.Checksum), lastUsed, lastUsed))
+		return key
+	}
+	now := time.Now()
+	recent := store("recent", now)
+	old := store("old", now.Add(-60*24*time.Hour))
+	unreferenced := store("unreferenced", now)
+
+	entries, err = cache.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, old, entries[2].Key(), "least recently used last")
+	assert.Equal(t, int64(len("old")), entries[2].Size)
+
+	referenced := map[CacheKey]bool{recent: true, old: true}
+	removed, err := cache.Prune(PruneOptions{Referenced: referenced})
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, unreferenced, removed[0].Key())
+
+	removed, err = cache.Prune(PruneOptions{Referenced: referenced, OlderThan: 30 * 24 * time.Hour})
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, old, removed[0].Key())
+
+	removed, err = cache.Prune(PruneOptions{Referenced: referenced, All: true})
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	entries, err = cache.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func Test_Downloader_DownloadAndVerify_UsesCache(t *testing.T) {
+	content := []byte("kubeadm binary")
+	sum := sha256Hex(content)
+	var calls int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cache := NewDownloadCache(filepath.Join(dir, "cache"))
+	downloader := newTestDownloader(server, dir, WithCache(cache))
+
+	require.NoError(t, downloader.DownloadAndVerify(server.URL, filepath.Join(dir, "kubeadm"), sum, "sha256"))
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	entries, err := cache.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, sum, entries[0].Checksum)
+
+	// A reinstall under another name reuses the verified blob.
+	require.NoError(t, os.Remove(filepath.Join(dir, "kubeadm")))
+	dest := filepath.Join(dir, "kubeadm-renamed")
+	require.NoError(t, downloader.DownloadAndVerify(server.URL, dest, sum, "sha256"))
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls), "the cached blob is reused without a download")
+	require.NoError(t, VerifyChecksum(dest, sum, "sha256"))
+}
+
+func TestReferencedChecksums(t *testing.T) {
+	catalog, err := LoadInfrastructureCatalog()
+	require.NoError(t, err)
+	keys := catalog.ReferencedChecksums()
+
+	for _, arch := range []string{ArchAMD64, ArchARM64} {
+		downloads, err := catalog.HostDownloads(OSLinux, arch)
+		require.NoError(t, err)
+		for _, d := range downloads {
+			if d.SelfRelease {
+				continue
+			}
+			assert.True(t, keys[CacheKey{Algorithm: d.Algorithm, Checksum: d.Checksum}], "%s: %s", d.Software, d.Name)
+		}
+	}
+}
@@ -7,19 +7,26 @@ import (
 	"compress/gzip"
 	"context"
 	"crypto/tls"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/automa-saga/logx"
 	"github.com/joomcode/errorx"
 
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
 	"github.com/hashgraph/solo-weaver/pkg/models"
 	"github.com/hashgraph/solo-weaver/pkg/sanity"
 )
 
+// partialSuffix names the file an in-progress download is written to.
+const partialSuffix = ".part"
+
 // Default retry settings for DownloadAndVerify.
 const (
 	defaultMaxAttempts = 3               // initial attempt + 2 retries
@@ -36,6 +43,10 @@ type Downloader struct {
 	insecureTLS    bool          // Skip TLS certificate verification (for local dev with self-signed certs)
 	maxAttempts    int           // Number of download+verify attempts before giving up
 	retryDelay     time.Duration // Base backoff delay between attempts (0 disables sleeping)
+
+	// cache, when set, is the content-addressed store of verified downloads
+	// DownloadAndVerify reuses and fills.
+	cache *DownloadCache
 }
 
 // DownloaderOption is a function that configures a Downloader
@@ -91,6 +102,13 @@ func WithRetryDelay(delay time.Duration) DownloaderOption {
 	}
 }
 
+// WithCache makes DownloadAndVerify reuse and fill the given download cache.
+func WithCache(cache *DownloadCache) DownloaderOption {
+	return func(d *Downloader) {
+		d.cache = cache
+	}
+}
+
 // WithInsecureTLS skips TLS certificate verification.
 // WARNING: Only use this for local development with self-signed certificates!
 // This option is ignored in release builds for security.
@@ -161,7 +179,14 @@ func NewDownloader(opts ...DownloaderOption) *Downloader {
 	return downloader
 }
 
-// Download downloads a file from the given URL to the specified destination
+// Download downloads a file from the given URL to the specified destination.
+// While an air-gap bundle is active (see pkg/bundle), a URL the bundle holds is
+// copied out of it instead.
+//
+// The body is written to destination+".part" and renamed into place once
+// complete. A transfer that breaks off leaves the partial file behind, and
+// the next call resumes it with an HTTP Range request; a server that answers
+// the range with the whole file (200) restarts it from zero.
 func (fd *Downloader) Download(url, destination string) error {
 	// Validate URL before attempting download
 	if err := sanity.ValidateURL(url, &sanity.ValidateURLOptions{AllowedDomains: fd.allowedDomains}); err != nil {
@@ -174,54 +199,137 @@ func (fd *Downloader) Download(url, destination string) error {
 		return NewDownloadError(err, url, 0)
 	}
 
-	resp, err := fd.client.Get(url)
+	// An active air-gap bundle serves every URL it holds; anything else still
+	// goes to the network.
+	if b := bundle.Active(); b != nil {
+		if artifact, ok := b.Artifact(url); ok {
+			if err := b.CopyTo(artifact.File, cleanDest); err != nil {
+				return NewDownloadError(err, url, 0)
+			}
+			logx.As().Debug().Str("url", url).Str("bundle", b.Dir()).Msg("Resolved download from the air-gap bundle")
+			return nil
+		}
+	}
+
+	// Resume from a partial file an interrupted earlier attempt left behind.
+	partial := cleanDest + partialSuffix
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return NewDownloadError(err, url, 0)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := fd.client.Do(req)
 	if err != nil {
 		return NewDownloadError(err, url, 0)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		// The server ignored the range (or none was asked for): start over.
+		offset = 0
+		flags |= os.O_TRUNC
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			_ = os.Remove(partial)
+			return NewDownloadError(
+				errorx.ExternalError.New("server resumed at %q instead of byte %d", resp.Header.Get("Content-Range"), offset),
+				url, resp.StatusCode)
+		}
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file does not fit the remote one (it changed, or the
+		// partial file is already complete but unverified); the next attempt
+		// starts from zero.
+		_ = os.Remove(partial)
+		return NewDownloadError(nil, url, resp.StatusCode)
+	default:
 		return NewDownloadError(nil, url, resp.StatusCode)
 	}
 
-	out, err := os.Create(cleanDest)
+	out, err := os.OpenFile(partial, flags, models.DefaultFilePerm)
 	if err != nil {
 		return NewDownloadError(err, url, 0)
 	}
-	defer out.Close()
 
 	written, err := io.Copy(out, resp.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
-		_ = os.Remove(cleanDest)
+		// Keep the partial file: the next attempt resumes from it.
 		return NewDownloadError(err, url, 0)
 	}
 
 	// Reject a zero-byte body: a 200-with-empty-response is a corrupt download,
 	// not a valid file. This is treated as a retryable download error.
-	if written == 0 {
-		_ = os.Remove(cleanDest)
+	if offset+written == 0 {
+		_ = os.Remove(partial)
 		return NewDownloadError(errorx.ExternalError.New("downloaded file is empty (0 bytes written)"), url, 0)
 	}
 
 	// When the response advertises a Content-Length, fail fast on a size mismatch
 	// (a truncated copy or mid-stream reset) rather than persisting a partial file.
 	// ContentLength is -1 when unknown (e.g. chunked transfer), so only check when >= 0.
+	// A short body stays in the partial file for the next attempt to resume.
 	if resp.ContentLength >= 0 && written != resp.ContentLength {
-		_ = os.Remove(cleanDest)
+		if written > resp.ContentLength {
+			_ = os.Remove(partial)
+		}
 		return NewDownloadError(
 			errorx.ExternalError.New("download truncated: wrote %d of %d advertised bytes", written, resp.ContentLength),
 			url, 0)
 	}
 
+	if err := os.Rename(partial, cleanDest); err != nil {
+		return NewDownloadError(err, url, 0)
+	}
+	if offset > 0 {
+		logx.As().Debug().Str("url", url).Int64("offset", offset).Msg("Resumed interrupted download")
+	}
+
 	return nil
 }
 
+// contentRangeStart returns the first byte position of a Content-Range header
+// such as "bytes 100-199/200".
+func contentRangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	first, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
+
 // DownloadAndVerify downloads a file from url to destination and verifies its
 // checksum, retrying transient corruption. Both download errors (network reset,
 // empty/truncated body) and a subsequent checksum mismatch are treated as
 // retryable: on failure the bad file is removed and the download is re-attempted
 // with capped exponential backoff, up to maxAttempts. The last error is returned
 // once attempts are exhausted.
+//
+// An attempt broken off mid-stream keeps its partial file, so the next attempt
+// resumes rather than restarting; a resumed file spliced from two different
+// remote files fails verification and is downloaded again from zero. With a
+// cache (WithCache), a verified blob for the checksum is reused without any
+// download, and every verified download is added to the cache.
 func (fd *Downloader) DownloadAndVerify(url, destination, expectedValue, algorithm string) error {
 	attempts := fd.maxAttempts
 	if attempts < 1 {
@@ -247,19 +355,31 @@ func (fd *Downloader) DownloadAndVerify(url, destination, expectedValue, algorit
 		return NewChecksumError(cleanDest, algorithm, expectedValue, "")
 	}
 
+	if fd.cache != nil {
+		restored, err := fd.cache.Restore(algorithm, expectedValue, cleanDest)
+		if err != nil {
+			logx.As().Warn().Err(err).Str("url", url).Msg("Failed to restore download from cache; downloading it")
+		}
+		if restored {
+			logx.As().Debug().Str("url", url).Str("checksum", expectedValue).Msg("Resolved download from the download cache")
+			return nil
+		}
+	}
+
 	var lastErr error
 	for attempt := 1; attempt <= attempts; attempt++ {
 		lastErr = fd.Download(url, cleanDest)
 		if lastErr == nil {
 			lastErr = VerifyChecksum(cleanDest, expectedValue, algorithm)
 			if lastErr == nil {
+				fd.storeInCache(url, cleanDest, expectedValue, algorithm)
 				return nil
 			}
-		}
 
-		// Remove the corrupt/partial file so a bad download never persists and the
-		// next attempt (or a later run) starts clean.
-		_ = os.Remove(cleanDest)
+			// Remove the corrupt file so a bad download never persists and the
+			// next attempt (or a later run) starts clean.
+			_ = os.Remove(cleanDest)
+		}
 
 		if attempt < attempts {
 			logx.As().Warn().
@@ -275,6 +395,17 @@ func (fd *Downloader) DownloadAndVerify(url, destination, expectedValue, algorit
 	return lastErr
 }
 
+// storeInCache adds a verified download to the cache, if there is one. A
+// failure only costs a later re-download, so it is logged, not returned.
+func (fd *Downloader) storeInCache(url, path, checksum, algorithm string) {
+	if fd.cache == nil {
+		return
+	}
+	if err := fd.cache.Store(path, algorithm, checksum); err != nil {
+		logx.As().Warn().Err(err).Str("url", url).Msg("Failed to add download to the download cache")
+	}
+}
+
 // sleepBackoff sleeps for a capped exponential delay before the next attempt.
 // A zero base retryDelay disables sleeping.
 func (fd *Downloader) sleepBackoff(attempt int) {
@@ -4,6 +4,7 @@ package software
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
 	"crypto/sha256"
 	"fmt"
@@ -11,6 +12,9 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -145,6 +149,7 @@ func Test_Downloader_Download_ContentLengthMismatch(t *testing.T) {
 	require.NoError(t, err, "Failed to create temp file")
 	_ = tmpFile.Close()
 	defer func() { _ = os.Remove(tmpFile.Name()) }()
+	defer func() { _ = os.Remove(tmpFile.Name() + partialSuffix) }()
 
 	downloader := NewDownloader(
 		WithHTTPClient(server.Client()),
@@ -257,6 +262,141 @@ func Test_Downloader_DownloadAndVerify_FailsFastOnUnsupportedAlgorithm(t *testin
 	require.Equal(t, int32(0), atomic.LoadInt32(&calls), "No download should be attempted for an unsupported algorithm")
 }
 
+// droppingServer serves content, but breaks off every response that does not
+// ask for a range after half of it, as a connection reset mid-stream would.
+// It records the Range header of each request.
+func droppingServer(t *testing.T, content []byte) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	ranges := &[]string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		if r.Header.Get("Range") != "" {
+			http.ServeContent(w, r, "artifact", time.Time{}, bytes.NewReader(content))
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(content[:len(content)/2])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}))
+	t.Cleanup(server.Close)
+	return server, ranges
+}
+
+func newTestDownloader(server *httptest.Server, basePath string, opts ...DownloaderOption) *Downloader {
+	return NewDownloader(append([]DownloaderOption{
+		WithHTTPClient(server.Client()),
+		WithBasePath(basePath),
+		WithAllowedDomains([]string{"localhost", "127.0.0.1"}),
+		WithRetryDelay(0),
+	}, opts...)...)
+}
+
+func Test_Downloader_Download_ResumesAfterDroppedConnection(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1000))
+	server, ranges := droppingServer(t, content)
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "artifact.tar.gz")
+	downloader := newTestDownloader(server, dir)
+
+	err := downloader.Download(server.URL, dest)
+	require.Error(t, err)
+	require.True(t, errorx.IsOfType(err, DownloadError))
+	require.NoFileExists(t, dest, "an interrupted download is not put in place")
+	partial, err := os.ReadFile(dest + partialSuffix)
+	require.NoError(t, err)
+	require.Equal(t, content[:len(partial)], partial)
+	require.NotEmpty(t, partial)
+
+	require.NoError(t, downloader.Download(server.URL, dest))
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+	require.NoFileExists(t, dest+partialSuffix)
+	require.Equal(t, []string{"", fmt.Sprintf("bytes=%d-", len(partial))}, *ranges)
+}
+
+func Test_Downloader_DownloadAndVerify_ResumesAcrossAttempts(t *testing.T) {
+	content := []byte(strings.Repeat("abcdefghij", 1000))
+	sum := fmt.Sprintf("%x", sha256.Sum256(content))
+	server, ranges := droppingServer(t, content)
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "artifact.tar.gz")
+
+	require.NoError(t, newTestDownloader(server, dir).DownloadAndVerify(server.URL, dest, sum, "sha256"))
+	require.Len(t, *ranges, 2, "the second attempt resumes the first")
+	require.NotEmpty(t, (*ranges)[1])
+	require.NoError(t, VerifyChecksum(dest, sum, "sha256"))
+}
+
+func Test_Downloader_Download_RestartsWhenRangeIgnored(t *testing.T) {
+	content := []byte("the whole file")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "artifact")
+	require.NoError(t, os.WriteFile(dest+partialSuffix, []byte("the wh"), 0o644))
+
+	require.NoError(t, newTestDownloader(server, dir).Download(server.URL, dest))
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	require.Equal(t, content, got, "a 200 answer to a range request replaces the partial file")
+}
+
+func Test_Downloader_Download_DiscardsUnusablePartial(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "range not satisfiable",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			},
+		},
+		{
+			name: "resumed at another offset",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Range", "bytes 0-9/10")
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write([]byte("0123456789"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(tt.handler)
+			defer server.Close()
+			dir := t.TempDir()
+			dest := filepath.Join(dir, "artifact")
+			require.NoError(t, os.WriteFile(dest+partialSuffix, []byte("01234"), 0o644))
+
+			err := newTestDownloader(server, dir).Download(server.URL, dest)
+			require.Error(t, err)
+			require.True(t, errorx.IsOfType(err, DownloadError))
+			require.NoFileExists(t, dest+partialSuffix, "the next attempt starts from zero")
+			require.NoFileExists(t, dest)
+		})
+	}
+}
+
+func Test_contentRangeStart(t *testing.T) {
+	start, ok := contentRangeStart("bytes 100-199/200")
+	require.True(t, ok)
+	require.Equal(t, int64(100), start)
+
+	for _, header := range []string{"", "bytes */200", "items 1-2/3"} {
+		_, ok := contentRangeStart(header)
+		require.False(t, ok, header)
+	}
+}
+
 func Test_Downloader_Extract(t *testing.T) {
 	// Create a temporary directory for test files
 	tempDir, err := os.MkdirTemp("", "test_extract_*")
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"io"
+	"os"
+	"path"
+
+	"github.com/joomcode/errorx"
+)
+
+// HostDownload is one file a host artifact downloads, resolved for a
+// platform: the URL the installer fetches, the file name it is written to
+// under DownloadsDir, and the catalog's integrity record. A self-released
+// artifact (SelfRelease) whose digest is not pinned into this binary has no
+// Checksum; ChecksumURL then names the published checksum asset it is
+// verified against.
+type HostDownload struct {
+	Software    string
+	Version     string
+	URL         string
+	Name        string
+	Algorithm   string
+	Checksum    string
+	ChecksumURL string
+	SelfRelease bool
+}
+
+// HostDownloads returns every file the default version of each host artifact
+// downloads on osName/arch, in catalog order. A self-released artifact
+// resolves at this binary's own version, as the installer does by default.
+func (c *InfrastructureCatalog) HostDownloads(osName, arch string) ([]HostDownload, error) {
+	var downloads []HostDownload
+	for i := range c.Host {
+		item := &c.Host[i]
+		data := TemplateData{OS: osName, ARCH: arch}
+
+		if spec := item.SelfRelease; spec != nil {
+			data.VERSION = ownVersion()
+			url, err := executeTemplate(spec.URL, data)
+			if err != nil {
+				return nil, NewTemplateError(err, item.Name)
+			}
+			d := HostDownload{
+				Software:    item.Name,
+				Version:     data.VERSION,
+				URL:         url,
+				Name:        path.Base(url),
+				Algorithm:   daemonChecksumAlgorithm,
+				SelfRelease: true,
+			}
+			if digest, ok := pinnedDigestFor(data.VERSION); ok {
+				d.Checksum = digest
+			} else {
+				d.ChecksumURL = url + spec.ChecksumSuffix()
+			}
+			downloads = append(downloads, d)
+			continue
+		}
+
+		version, err := item.GetDefaultVersion()
+		if err != nil {
+			return nil, err
+		}
+		data.VERSION = version
+		details := item.Versions[Version(version)]
+
+		resolve := func(name, url string, checksums PlatformChecksum) error {
+			osInfo, ok := checksums[osName]
+			if !ok {
+				return NewPlatformNotFoundError(item.Name, version, osName, "")
+			}
+			sum, ok := osInfo[arch]
+			if !ok {
+				return NewPlatformNotFoundError(item.Name, version, osName, arch)
+			}
+			resolvedURL, err := executeTemplate(url, data)
+			if err != nil {
+				return NewTemplateError(err, item.Name)
+			}
+			resolvedName, err := executeTemplate(name, data)
+			if err != nil {
+				return NewTemplateError(err, item.Name)
+			}
+			downloads = append(downloads, HostDownload{
+				Software:  item.Name,
+				Version:   version,
+				URL:       resolvedURL,
+				Name:      resolvedName,
+				Algorithm: sum.Algorithm,
+				Checksum:  sum.Value,
+			})
+			return nil
+		}
+		for _, archive := range details.GetArchives() {
+			if err := resolve(archive.Name, archive.URL, archive.PlatformChecksum); err != nil {
+				return nil, err
+			}
+		}
+		for _, binary := range details.BinariesByURL() {
+			if err := resolve(binary.Name, binary.URL, binary.PlatformChecksum); err != nil {
+				return nil, err
+			}
+		}
+		// Config URLs are not templated; the installer fetches them as written.
+		for _, config := range details.ConfigsByURL() {
+			downloads = append(downloads, HostDownload{
+				Software:  item.Name,
+				Version:   version,
+				URL:       config.URL,
+				Name:      config.Name,
+				Algorithm: config.Algorithm,
+				Checksum:  config.Value,
+			})
+		}
+	}
+	return downloads, nil
+}
+
+// FetchChecksumAsset downloads the published `<binary>.sha256` asset at url
+// to destination and returns the digest it holds.
+func (fd *Downloader) FetchChecksumAsset(url, destination string) (string, error) {
+	if err := fd.Download(url, destination); err != nil {
+		return "", err
+	}
+	f, err := os.Open(destination)
+	if err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to read the downloaded checksum asset %s", destination)
+	}
+	defer f.Close()
+	content, err := io.ReadAll(io.LimitReader(f, maxChecksumAssetBytes))
+	if err != nil {
+		return "", errorx.ExternalError.Wrap(err, "failed to read the downloaded checksum asset %s", destination)
+	}
+	return parseChecksumAsset(content, path.Base(url))
+}
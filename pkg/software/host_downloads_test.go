@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashgraph/solo-weaver/pkg/bundle"
+)
+
+func TestHostDownloads(t *testing.T) {
+	catalog, err := LoadInfrastructureCatalog()
+	require.NoError(t, err)
+
+	downloads, err := catalog.HostDownloads(OSLinux, ArchARM64)
+	require.NoError(t, err)
+	require.NotEmpty(t, downloads)
+
+	bySoftware := map[string][]HostDownload{}
+	for _, d := range downloads {
+		assert.NotContains(t, d.URL, "{{", "%s: URL is not templated", d.Software)
+		assert.NotEmpty(t, d.Name, d.Software)
+		if d.SelfRelease {
+			assert.True(t, d.Checksum != "" || d.ChecksumURL != "", "%s: no integrity record", d.Software)
+		} else {
+			assert.NotEmpty(t, d.Checksum, "%s: no catalog checksum for %s", d.Software, d.URL)
+		}
+		bySoftware[d.Software] = append(bySoftware[d.Software], d)
+	}
+
+	require.NotEmpty(t, bySoftware["kubeadm"])
+	assert.Contains(t, bySoftware["kubeadm"][0].URL, "/arm64/")
+
+	require.Len(t, bySoftware["solo-provisioner-daemon"], 1)
+	daemon := bySoftware["solo-provisioner-daemon"][0]
+	assert.True(t, daemon.SelfRelease)
+	assert.Equal(t, ownVersion(), daemon.Version)
+	assert.Contains(t, daemon.URL, "solo-provisioner-daemon-linux-arm64")
+}
+
+func TestHostDownloads_UnknownPlatform(t *testing.T) {
+	catalog, err := LoadInfrastructureCatalog()
+	require.NoError(t, err)
+
+	_, err = catalog.HostDownloads(OSLinux, "riscv64")
+	require.Error(t, err)
+}
+
+func Test_Downloader_Download_FromActiveBundle(t *testing.T) {
+	const url = "https://example.com/crio.tar.gz"
+	content := []byte("bundled crio")
+	sum := sha256.Sum256(content)
+
+	dir := t.TempDir()
+	rel := filepath.Join(bundle.ArtifactsDir, "crio", "crio.tar.gz")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(rel)), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, rel), content, 0o644))
+	require.NoError(t, bundle.WriteManifest(dir, bundle.Manifest{
+		Artifacts: []bundle.Artifact{{
+			File:     bundle.File{Path: rel, SHA256: hex.EncodeToString(sum[:])},
+			Software: "crio",
+			URL:      url,
+		}},
+	}))
+	b, err := bundle.Open(dir)
+	require.NoError(t, err)
+	bundle.Activate(b)
+	t.Cleanup(func() { bundle.Activate(nil) })
+
+	dest := t.TempDir()
+	downloader := NewDownloader(WithBasePath(dest), WithAllowedDomains([]string{"example.com"}))
+
+	// No server answers for example.com here: the file can only come from the bundle.
+	target := filepath.Join(dest, "crio.tar.gz")
+	require.NoError(t, downloader.DownloadAndVerify(url, target, hex.EncodeToString(sum[:]), "sha256"))
+	got, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+
+	// A bundled file that no longer matches its manifest digest is rejected.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, rel), []byte("tampered"), 0o644))
+	err = downloader.Download(url, filepath.Join(dest, "again.tar.gz"))
+	require.Error(t, err)
+	assert.True(t, errorx.IsOfType(err, DownloadError))
+	assert.Contains(t, err.Error(), "checksum")
+}
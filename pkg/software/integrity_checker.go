@@ -24,15 +24,24 @@ func IsSupportedAlgorithm(algorithm string) bool {
 
 // VerifyChecksum dynamically verifies the checksum of a file using the specified algorithm
 func VerifyChecksum(filePath string, expectedValue string, algorithm string) error {
+	hashFunction, ok := newHash(algorithm)
+	if !ok {
+		return NewChecksumError(filePath, algorithm, expectedValue, "")
+	}
+	return checksum(filePath, expectedValue, algorithm, hashFunction)
+}
+
+// newHash returns the hash implementation of a supported algorithm.
+func newHash(algorithm string) (hash.Hash, bool) {
 	switch algorithm {
 	case "md5":
-		return checksum(filePath, expectedValue, algorithm, md5.New())
+		return md5.New(), true
 	case "sha256":
-		return checksum(filePath, expectedValue, algorithm, sha256.New())
+		return sha256.New(), true
 	case "sha512":
-		return checksum(filePath, expectedValue, algorithm, sha512.New())
+		return sha512.New(), true
 	default:
-		return NewChecksumError(filePath, algorithm, expectedValue, "")
+		return nil, false
 	}
 }
 
@@ -144,7 +144,8 @@ func (ki *kubeadmInstaller) RemoveConfiguration() error {
 
 // configureKubeadmInit generates the kubeadm init configuration file
 // It retrieves the machine IP, generates a kubeadm token, and gets the hostname
-// It then renders the kubeadm-init.yaml template with the retrieved values
+// It then renders the kubeadm-init.yaml template with the retrieved values and
+// the cluster network layout from WithClusterConfig
 func (ki *kubeadmInstaller) configureKubeadmInit(kubernetesVersion string) error {
 	machineIp, err := network.GetMachineIP()
 	if err != nil {
@@ -161,13 +162,22 @@ func (ki *kubeadmInstaller) configureKubeadmInit(kubernetesVersion string) error
 		return errorx.IllegalState.Wrap(err, "failed to get hostname")
 	}
 
+	cluster := ki.clusterConfig.Merge(models.DefaultClusterConfig())
+	if err := cluster.Validate(); err != nil {
+		return err
+	}
+
 	tmplData := templates.KubeadmInitData{
 		KubeBootstrapToken: kubeadmToken,
 		SandboxDir:         models.Paths().SandboxDir,
 		MachineIP:          machineIp,
 		Hostname:           hostname,
 		KubernetesVersion:  kubernetesVersion,
-		PodSubnet:          models.DefaultClusterPodCIDR,
+		ClusterName:        cluster.Name,
+		DNSDomain:          cluster.DNSDomain,
+		ServiceSubnet:      cluster.ServiceSubnet,
+		PodSubnet:          cluster.PodSubnet,
+		NodeCIDRMaskSize:   cluster.NodeCIDRMaskSize,
 	}
 
 	rendered, err := templates.Render("files/kubeadm/kubeadm-init.yaml", tmplData)
@@ -231,10 +241,11 @@ func (ki *kubeadmInstaller) patchKubeadmConf() error {
 	confPath := ki.getKubeadmConfPath()
 
 	// Replace the kubelet binary path with sandbox path
-	sandboxKubeletPath := path.Join(models.Paths().SandboxBinDir, "kubelet")
-	err := ki.replaceAllInFile(confPath, "/usr/bin/kubelet", sandboxKubeletPath)
-	if err != nil {
-		return errorx.IllegalState.Wrap(err, "failed to replace kubelet path in 10-kubeadm.conf file")
+	for _, r := range kubeletPathRewrites() {
+		err := ki.replaceAllInFile(confPath, r.from, r.to)
+		if err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to replace kubelet path in 10-kubeadm.conf file")
+		}
 	}
 
 	return nil
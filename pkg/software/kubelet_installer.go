@@ -161,15 +161,22 @@ func (ki *kubeletInstaller) validateCriticalPaths() error {
 	return nil
 }
 
+// kubeletPathRewrites are the rewrites patched into kubelet.service and
+// 10-kubeadm.conf so that systemd starts the sandbox kubelet binary.
+func kubeletPathRewrites() []pathRewrite {
+	return []pathRewrite{{from: "/usr/bin/kubelet", to: path.Join(models.Paths().SandboxBinDir, "kubelet")}}
+}
+
 // patchServiceFile patches kubelet.service with updated paths in place
 func (ki *kubeletInstaller) patchServiceFile() error {
 	kubeletServicePath := ki.getKubeletServicePath()
 
 	// Replace the kubelet binary path with sandbox path
-	sandboxKubeletPath := ki.getSandboxKubeletBinPath()
-	err := ki.replaceAllInFile(kubeletServicePath, "/usr/bin/kubelet", sandboxKubeletPath)
-	if err != nil {
-		return errorx.IllegalState.Wrap(err, "failed to replace kubelet path in kubelet.service file")
+	for _, r := range kubeletPathRewrites() {
+		err := ki.replaceAllInFile(kubeletServicePath, r.from, r.to)
+		if err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to replace kubelet path in kubelet.service file")
+		}
 	}
 
 	return nil
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+
+	"github.com/automa-saga/logx"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/hashgraph/solo-weaver/pkg/models"
+)
+
+// DefaultPrefetchParallelism bounds how many artifacts PrefetchHostArtifacts
+// downloads at a time.
+const DefaultPrefetchParallelism = 4
+
+// Prefetch downloads and verifies downloads into dir, at most parallelism at a
+// time, skipping files already there and verified. Self-released artifacts
+// are left to their installer, which resolves their checksum itself. Once one
+// download fails, no further ones start and its error is returned.
+func (fd *Downloader) Prefetch(ctx context.Context, downloads []HostDownload, dir string, parallelism int) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	if err := os.MkdirAll(dir, models.DefaultDirOrExecPerm); err != nil {
+		return NewFileSystemError(err)
+	}
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(parallelism)
+
+	seen := map[string]bool{}
+	for _, d := range downloads {
+		if d.SelfRelease || seen[d.Name] {
+			continue
+		}
+		seen[d.Name] = true
+
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			dest := filepath.Join(dir, d.Name)
+			if VerifyChecksum(dest, d.Checksum, d.Algorithm) == nil {
+				return nil
+			}
+			if err := fd.DownloadAndVerify(d.URL, dest, d.Checksum, d.Algorithm); err != nil {
+				return err
+			}
+			logx.As().Debug().Str("software", d.Software).Str("file", d.Name).Msg("Prefetched artifact")
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// PrefetchHostArtifacts downloads, in parallel, every file the named host
+// artifacts download on this host into WeaverPaths.DownloadsDir, where their
+// installers find them verified. An artifact mr reports installed at its
+// default version is skipped.
+func PrefetchHostArtifacts(ctx context.Context, mr MachineRuntime, names ...string) error {
+	catalog, err := LoadInfrastructureCatalog()
+	if err != nil {
+		return NewConfigLoadError(err)
+	}
+	all, err := catalog.HostDownloads(runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+
+	var downloads []HostDownload
+	for _, d := range all {
+		if !slices.Contains(names, d.Software) {
+			continue
+		}
+		if mr != nil {
+			if cur, ok := mr.SoftwareState(d.Software); ok && cur.Installed && cur.Version == d.Version {
+				continue
+			}
+		}
+		downloads = append(downloads, d)
+	}
+	if len(downloads) == 0 {
+		return nil
+	}
+
+	downloader := NewDownloader(WithCache(DefaultDownloadCache()))
+	return downloader.Prefetch(ctx, downloads, models.Paths().DownloadsDir, DefaultPrefetchParallelism)
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package software
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloader_Prefetch(t *testing.T) {
+	files := map[string][]byte{
+		"/kubelet": []byte("kubelet"),
+		"/kubectl": []byte("kubectl"),
+		"/crio":    []byte("cri-o"),
+		"/helm":    []byte("helm"),
+	}
+	var inFlight, maxInFlight int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write(files[r.URL.Path])
+	}))
+	defer server.Close()
+
+	var downloads []HostDownload
+	for p, content := range files {
+		downloads = append(downloads, HostDownload{
+			Software:  p[1:],
+			URL:       server.URL + p,
+			Name:      p[1:],
+			Algorithm: "sha256",
+			Checksum:  sha256Hex(content),
+		})
+	}
+	// Duplicates and self-released artifacts are skipped.
+	downloads = append(downloads, downloads[0], HostDownload{Name: "daemon", URL: server.URL + "/daemon", SelfRelease: true})
+
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "downloads")
+	require.NoError(t, newTestDownloader(server, dir).Prefetch(context.Background(), downloads, dest, 2))
+
+	for p, content := range files {
+		got, err := os.ReadFile(filepath.Join(dest, p[1:]))
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+	}
+	assert.NoFileExists(t, filepath.Join(dest, "daemon"))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2), "parallelism is bounded")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight), "downloads run in parallel")
+}
+
+func TestDownloader_Prefetch_ReturnsFailure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("unexpected"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	err := newTestDownloader(server, dir, WithMaxAttempts(1)).Prefetch(context.Background(), []HostDownload{{
+		URL:       server.URL + "/kubeadm",
+		Name:      "kubeadm",
+		Algorithm: "sha256",
+		Checksum:  sha256Hex([]byte("expected")),
+	}}, dir, DefaultPrefetchParallelism)
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "kubeadm"))
+}
@@ -237,23 +237,25 @@ func (ti *teleportNodeAgentInstaller) installServiceFile() error {
 	return nil
 }
 
+// teleportServicePathRewrites are the rewrites patched into teleport.service.
+// The original service file uses /usr/local/bin/teleport, which becomes the
+// sandbox binary, and /etc/teleport.yaml, where we use /etc/teleport/teleport.yaml.
+func teleportServicePathRewrites() []pathRewrite {
+	return []pathRewrite{
+		{from: "/usr/local/bin/teleport", to: path.Join(models.Paths().SandboxBinDir, "teleport")},
+		{from: "/etc/teleport.yaml", to: teleportConfigFile},
+	}
+}
+
 // patchServiceFile patches the teleport.service file with the correct binary and config paths
 func (ti *teleportNodeAgentInstaller) patchServiceFile() error {
 	serviceFilePath := ti.getTeleportServicePath()
-	teleportBinaryPath := ti.getSandboxTeleportBinPath()
 
-	// Replace the default binary path with our sandbox path
-	// The original service file uses /usr/local/bin/teleport
-	err := ti.replaceAllInFile(serviceFilePath, "/usr/local/bin/teleport", teleportBinaryPath)
-	if err != nil {
-		return errorx.IllegalState.Wrap(err, "failed to replace teleport binary path in service file")
-	}
-
-	// Replace the default config path with our config path
-	// The original service file uses /etc/teleport.yaml, we use /etc/teleport/teleport.yaml
-	err = ti.replaceAllInFile(serviceFilePath, "/etc/teleport.yaml", teleportConfigFile)
-	if err != nil {
-		return errorx.IllegalState.Wrap(err, "failed to replace config path in service file")
+	for _, r := range teleportServicePathRewrites() {
+		err := ti.replaceAllInFile(serviceFilePath, r.from, r.to)
+		if err != nil {
+			return errorx.IllegalState.Wrap(err, "failed to replace %s in service file", r.from)
+		}
 	}
 
 	return nil